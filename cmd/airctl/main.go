@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/rs/zerolog"
+
+	"github.com/yourusername/air-go/internal/airctl"
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// airctl runs read-only entity queries against the environment the configuration points at.
+// Queries go through the server's converters and exclude deleted entities; access is whatever
+// the MongoDB URI grants, and writes are refused
+//
+//	go run ./cmd/airctl get customer 7c9e6679-7425-40de-944b-e07fc1f90ae7
+//	go run ./cmd/airctl search customer --filter-file f.json --sort createDate:desc --limit 50
+//	go run ./cmd/airctl health --output table
+//	go run ./cmd/airctl dump customer --out customers.ndjson.gz
+//	go run ./cmd/airctl verify customers.ndjson.gz
+func main() {
+	cmd, err := airctl.ParseArgs(os.Args[1:], nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// verify reads only its file, so it needs neither configuration nor a database
+	if cmd.Name == airctl.CommandVerify {
+		if err := airctl.Run(context.Background(), cmd, nil, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "airctl %s: %v\n", cmd.Name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Filters and orders are limited and converted like the server's
+	resolvers.SetMaxSortEntries(cfg.SortMaxEntries)
+	resolvers.SetFilterLimits(resolvers.FilterLimits{
+		MaxIn:    cfg.FilterMaxIn,
+		MaxNin:   cfg.FilterMaxNin,
+		MaxAnd:   cfg.FilterMaxAnd,
+		MaxOr:    cfg.FilterMaxOr,
+		MaxDepth: cfg.FilterMaxDepth,
+		MaxBytes: cfg.FilterMaxBytes,
+	})
+	resolvers.SetExportMaxRows(cfg.ExportMaxRows)
+	resolvers.SetShadowFieldsEnabled(cfg.ShadowFieldsEnabled)
+	resolvers.SetStrictUUIDs(cfg.UUIDStrict)
+	if err := resolvers.SetAbsentAsFalseFields(cfg.FilterAbsentAsFalse); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid FILTER_ABSENT_AS_FALSE: %v\n", err)
+		os.Exit(1)
+	}
+	schema := generated.NewExecutableSchema(generated.Config{}).Schema()
+	if err := resolvers.SetDisabledFeatures(cfg.DisabledEntities, cfg.DisabledOperations, schema); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid DISABLED_ENTITIES or DISABLED_OPERATIONS: %v\n", err)
+		os.Exit(1)
+	}
+
+	client, err := db.NewClient(cfg.Database, zerolog.Nop())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create MongoDB client: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout)
+	err = client.Connect(connectCtx)
+	connectCancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to MongoDB: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(context.Background())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := airctl.Run(ctx, cmd, airctl.ReadOnly(client), os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "airctl %s: %v\n", cmd.Name, err)
+		os.Exit(1)
+	}
+}
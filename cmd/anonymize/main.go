@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/db/anonymize"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// anonymize replaces customer and employee names and emails with deterministic fakes.
+// It reads the same configuration as the server, refuses to run against a database whose
+// name matches ANONYMIZE_PRODUCTION_PATTERN and can be re-run safely
+//
+//	ANONYMIZE_KEY=... go run ./cmd/anonymize -dry-run
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report how many documents would change without writing")
+	batchSize := flag.Int("batch-size", anonymize.DefaultBatchSize, "document updates per bulk write")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := logger.Setup(logger.Options{
+		Format:   cfg.LogFormat,
+		Level:    cfg.LogLevel,
+		Sampling: cfg.LogSampling,
+		Caller:   cfg.LogCaller,
+		Output:   cfg.LogOutput,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up logging: %v\n", err)
+		os.Exit(1)
+	}
+	logger.SetRedactedFields(cfg.LogRedactedFields)
+
+	client, err := db.NewClient(cfg.Database, zerolog.Nop())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create MongoDB client: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout)
+	err = client.Connect(connectCtx)
+	connectCancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to MongoDB: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(context.Background())
+
+	results, err := anonymize.Run(context.Background(), client.Database(), anonymize.DefaultFields, anonymize.Options{
+		Key:               []byte(cfg.AnonymizeKey),
+		DryRun:            *dryRun,
+		BatchSize:         *batchSize,
+		ProductionPattern: regexp.MustCompile(cfg.AnonymizeProductionPattern), // Validated by config.Load
+	}, log.Logger)
+	for _, result := range results {
+		verb := "Anonymized"
+		if result.DryRun {
+			verb = "Would anonymize"
+		}
+		fmt.Printf("%s %d of %d documents in %s\n", verb, result.Changed, result.Scanned, result.Collection)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to anonymize: %v\n", err)
+		os.Exit(1)
+	}
+}
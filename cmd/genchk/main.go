@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/air-go/internal/genchk"
+)
+
+// genchk fails when the committed gqlgen output in internal/graphql/generated no longer matches
+// schema.graphqls. It runs the gqlgen pinned in go.mod in a temporary copy of the module, without
+// network access, and prints the changed types and fields. Run it in the module root
+//
+//	go run ./cmd/genchk
+func main() {
+	configFile := flag.String("config", "gqlgen.yml", "gqlgen configuration file")
+	flag.Parse()
+
+	result, err := genchk.Check(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "genchk: %v\n", err)
+		os.Exit(2)
+	}
+	if !result.Clean() {
+		fmt.Fprintf(os.Stderr, "The generated GraphQL code is out of date with the schema:\n%s", result.Summary())
+		fmt.Fprintln(os.Stderr, "Regenerate it with: go run github.com/99designs/gqlgen generate")
+		os.Exit(1)
+	}
+	fmt.Println("The generated GraphQL code matches the schema")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/air-go/internal/graphql/persisted"
+)
+
+// persist extracts operations from .graphql files into a persisted operations manifest
+//
+//	go run ./cmd/persist -src ./operations -out ./persisted-operations.json
+func main() {
+	src := flag.String("src", "./operations", "directory scanned recursively for .graphql files")
+	out := flag.String("out", "./persisted-operations.json", "manifest file to write")
+	flag.Parse()
+
+	operations := map[string]string{}
+
+	err := filepath.WalkDir(*src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".graphql") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		queries, err := persisted.ExtractOperations(path, string(content))
+		if err != nil {
+			return err
+		}
+
+		for _, query := range queries {
+			operations[persisted.Hash(query)] = query
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to extract operations: %v\n", err)
+		os.Exit(1)
+	}
+
+	content, err := json.MarshalIndent(operations, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode manifest: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, append(content, '\n'), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write manifest: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Wrote %d operations to %s\n", len(operations), *out)
+}
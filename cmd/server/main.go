@@ -2,24 +2,40 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 
+	"github.com/yourusername/air-go/internal/buildinfo"
 	"github.com/yourusername/air-go/internal/config"
 	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/db/migrations"
+	"github.com/yourusername/air-go/internal/db/purge"
 	"github.com/yourusername/air-go/internal/graphql"
+	"github.com/yourusername/air-go/internal/graphql/persisted"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/grpcapi"
+	"github.com/yourusername/air-go/internal/health"
 	"github.com/yourusername/air-go/internal/logger"
 	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/internal/smoke"
 )
 
 func main() {
 	startTime := time.Now()
 
+	smokeTest := flag.Bool("smoke-test", false, "run the read-only self-test against the configured database, print its report and exit")
+	flag.Parse()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -28,7 +44,18 @@ func main() {
 	}
 
 	// Initialize logger
-	logger.Setup(cfg.LogFormat)
+	appLogger, err := logger.Setup(logger.Options{
+		Format:   cfg.LogFormat,
+		Level:    cfg.LogLevel,
+		Sampling: cfg.LogSampling,
+		Caller:   cfg.LogCaller,
+		Output:   cfg.LogOutput,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up logging: %v\n", err)
+		os.Exit(1)
+	}
+	logger.SetRedactedFields(cfg.LogRedactedFields)
 
 	log.Info().Msg("Starting GraphQL API server")
 
@@ -44,17 +71,136 @@ func main() {
 	log.Info().
 		Str("schema_path", schema.SchemaPath).
 		Int("types", len(schema.Schema.Types)).
+		Str("checksum", schema.Checksum).
+		Str("git_sha", buildinfo.GitSHA).
+		Str("build_time", buildinfo.BuildTime).
 		Dur("load_time", time.Since(startTime)).
 		Msg("GraphQL schema loaded successfully")
 
+	// Verify every search filter/sorter field is mapped by its converter
+	if gaps := resolvers.CheckConverterCoverage(); len(gaps) > 0 {
+		for _, gap := range gaps {
+			log.Error().
+				Str("entity", gap.Entity).
+				Str("input", gap.Input).
+				Strs("unhandled_fields", gap.Unhandled).
+				Strs("unknown_fields", gap.Unknown).
+				Msg("Search input fields are not mapped by their converter and will be ignored")
+		}
+		if cfg.FilterCoverageStrict {
+			log.Fatal().
+				Int("inputs", len(gaps)).
+				Msg("Converter coverage check failed - server cannot start")
+		}
+	}
+
+	// Default sorts may only use fields callers can sort by themselves
+	if problems := resolvers.ValidateDefaultSorts(); len(problems) > 0 {
+		log.Fatal().
+			Strs("problems", problems).
+			Msg("Invalid entity default sort - server cannot start")
+	}
+
+	// Limit the order arrays and filters accepted by search and byKeys queries, operation complexity and export rows
+	resolvers.SetMaxSortEntries(cfg.SortMaxEntries)
+	resolvers.SetMaxQueryComplexity(cfg.GraphQLMaxComplexity)
+	resolvers.SetFilterLimits(resolvers.FilterLimits{
+		MaxIn:    cfg.FilterMaxIn,
+		MaxNin:   cfg.FilterMaxNin,
+		MaxAnd:   cfg.FilterMaxAnd,
+		MaxOr:    cfg.FilterMaxOr,
+		MaxDepth: cfg.FilterMaxDepth,
+		MaxBytes: cfg.FilterMaxBytes,
+	})
+	resolvers.SetDateTimeFilterBounds(resolvers.DateTimeFilterBounds{
+		Enabled:       cfg.FilterDateTimeBounds,
+		MinYear:       cfg.FilterDateTimeMinYear,
+		MaxFutureSkew: cfg.FilterDateTimeMaxFutureSkew,
+	})
+	resolvers.SetExportMaxRows(cfg.ExportMaxRows)
+	resolvers.SetMaxAggregateBatchSize(cfg.AggregateMaxBatchSize)
+	resolvers.SetShadowFieldsEnabled(cfg.ShadowFieldsEnabled)
+	resolvers.SetCapOversizedPageSizes(cfg.PageSizeCapEnabled)
+	resolvers.SetLenientFilters(cfg.FilterLenient)
+	if err := resolvers.SetAbsentAsFalseFields(cfg.FilterAbsentAsFalse); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Invalid FILTER_ABSENT_AS_FALSE - server cannot start")
+	}
+	resolvers.SetStrictUUIDs(cfg.UUIDStrict)
+	resolvers.SetCursorMaxAge(cfg.CursorMaxAge)
+	resolvers.SetCursorSigningSecret(cfg.JWTSecret)
+	if err := resolvers.SetResolverStatsWindow(cfg.ResolverStatsWindow); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Invalid RESOLVER_STATS_WINDOW - server cannot start")
+	}
+	if err := resolvers.SetGetCache(cfg.GetCacheEntities, cfg.GetCacheEntries, cfg.GetCacheTTL, cfg.GetCacheNegativeTTL); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Invalid GET_CACHE_ENTITIES - server cannot start")
+	}
+	resolvers.SetAuditDeletedAccess(cfg.AuditDeletedAccess)
+	if err := resolvers.SetAccessAudit(resolvers.AccessAuditOptions{
+		Entities:      cfg.AccessAuditEntities,
+		QueueSize:     cfg.AccessAuditQueueSize,
+		BatchSize:     cfg.AccessAuditBatchSize,
+		FlushInterval: cfg.AccessAuditFlushInterval,
+	}); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Invalid ACCESS_AUDIT_ENTITIES - server cannot start")
+	}
+	resolvers.SetDocumentSizeGuard(resolvers.DocumentSizeGuard{MaxBytes: cfg.DocumentMaxBytes, Strict: cfg.DocumentSizeStrict})
+
+	// Entities and operations this deployment does not offer
+	if err := resolvers.SetDisabledFeatures(cfg.DisabledEntities, cfg.DisabledOperations, schema.Schema); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Invalid DISABLED_ENTITIES or DISABLED_OPERATIONS - server cannot start")
+	}
+	if len(cfg.DisabledEntities) > 0 || len(cfg.DisabledOperations) > 0 {
+		log.Info().
+			Strs("entities", cfg.DisabledEntities).
+			Strs("operations", cfg.DisabledOperations).
+			Bool("hidden", cfg.HideDisabledFeatures).
+			Msg("Features disabled")
+	}
+
+	// Deprecated fields and arguments, refused past their sunset date when enforced
+	if err := resolvers.SetDeprecations(schema.Schema, cfg.DeprecationEnforceSunset); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Invalid @sunset directive in the GraphQL schema - server cannot start")
+	}
+
+	// Embedded sub-documents searches only read on request
+	if err := resolvers.SetSearchHeavyFields(cfg.SearchHeavyFields, schema.Schema); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Invalid SEARCH_HEAVY_FIELDS - server cannot start")
+	}
+
+	// Queue heavy searches so they cannot take every pool connection from point reads
+	resolvers.SetSearchConcurrency(cfg.SearchMaxConcurrentRows, cfg.SearchQueueTimeout)
+
+	// Read-only deployments answer mutations with READ_ONLY
+	resolvers.SetReadOnly(cfg.ReadOnly)
+	if cfg.ReadOnly {
+		log.Info().Msg("Read-only mode: mutations and database writes are refused")
+	}
+
 	// Initialize MongoDB client
-	dbClient, err := db.NewClient(cfg.Database, log.Logger)
+	dbClient, err := db.NewClient(cfg.Database, appLogger)
 	if err != nil {
 		log.Fatal().
 			Err(err).
 			Msg("Failed to create MongoDB client")
 	}
 
+	// Collections refuse writes in read-only mode, whatever code path attempts them
+	dbClient.SetReadOnly(cfg.ReadOnly)
+
 	// Connect to MongoDB with timeout
 	connectCtx, connectCancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout)
 	err = dbClient.Connect(connectCtx)
@@ -71,19 +217,135 @@ func main() {
 		Uint64("pool_size", cfg.Database.MaxPoolSize).
 		Msg("MongoDB connection established")
 
+	// Optional separate read connection for search and lookup queries
+	if cfg.ReadDatabase != nil {
+		connectReader(dbClient, cfg)
+	}
+
+	// Tag responses whose reads fell back to a secondary
+	dbClient.SetReadFallbackHook(resolvers.RecordReadFallback)
+
+	schemaInfo := &health.SchemaInfo{
+		Path:      schema.SchemaPath,
+		TypeCount: len(schema.Schema.Types),
+		Checksum:  schema.Checksum,
+		LoadedAt:  schema.LoadedAt.UTC().Format(time.RFC3339),
+	}
+
+	// Run the self-test instead of serving
+	if *smokeTest {
+		os.Exit(runSmokeTest(dbClient, schemaInfo, cfg))
+	}
+
+	// Apply pending data migrations before serving queries
+	if cfg.MigrationsEnabled {
+		runMigrations(dbClient, cfg)
+	}
+
+	// Verify entity collections and deletion fields against the live database
+	var entityValidation *health.EntityValidation
+	if cfg.EntityValidationEnabled {
+		entityValidation = validateEntityCollections(dbClient, cfg)
+	}
+
 	// Setup graceful shutdown for MongoDB
 	defer func() {
 		disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer disconnectCancel()
 
+		if reader := dbClient.Reader(); reader != dbClient {
+			if err := reader.Disconnect(disconnectCtx); err != nil {
+				log.Error().Err(err).Msg("Error disconnecting MongoDB read connection")
+			}
+			reader.Close()
+		}
 		if err := dbClient.Disconnect(disconnectCtx); err != nil {
 			log.Error().Err(err).Msg("Error disconnecting from MongoDB")
 		}
 		dbClient.Close()
 	}()
 
+	serverOpts := []server.Option{
+		server.WithLogger(appLogger),
+		server.WithDatabaseClient(dbClient),
+		server.WithSchema(schema.Schema),
+		server.WithServiceInfo(&health.ServiceInfo{
+			Schema:           schemaInfo,
+			Build:            health.CurrentBuildInfo(),
+			EntityValidation: entityValidation,
+			ReadOnly:         cfg.ReadOnly,
+		}),
+	}
+
+	// Load the operation allow-list when persisted operations mode is enabled
+	if cfg.PersistedOperationsEnabled {
+		manifest, err := persisted.LoadManifest(cfg.PersistedOperationsManifest)
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Str("manifest_path", cfg.PersistedOperationsManifest).
+				Msg("Failed to load persisted operations manifest")
+		}
+
+		log.Info().
+			Str("manifest_path", cfg.PersistedOperationsManifest).
+			Int("operations", manifest.Len()).
+			Msg("Persisted operations mode enabled")
+
+		serverOpts = append(serverOpts, server.WithPersistedOperations(manifest))
+	}
+
 	// Create and start HTTP server with database client
-	srv := server.New(cfg, server.WithDatabaseClient(dbClient))
+	srv := server.New(cfg, serverOpts...)
+
+	// Create the indexes, including the TTL index enforcing history retention;
+	// /ready reports NOT_READY until they exist
+	indexCtx, indexCancel := context.WithCancel(context.Background())
+	defer indexCancel()
+	if cfg.IndexManagementEnabled {
+		go ensureIndexes(indexCtx, dbClient, cfg, srv.Readiness())
+	}
+
+	// Purge deleted entities past their retention period until shutdown
+	purgeCtx, purgeCancel := context.WithCancel(context.Background())
+	defer purgeCancel()
+	if cfg.PurgeEnabled {
+		go purge.Schedule(purgeCtx, dbClient.Database(), resolvers.PurgeTargets(), cfg.PurgeInterval, purge.Options{
+			DryRun:     cfg.PurgeDryRun,
+			BatchSize:  cfg.PurgeBatchSize,
+			BatchPause: cfg.PurgeBatchPause,
+			LeaseTTL:   cfg.MigrationsLeaseTTL,
+		}, appLogger.With().Str("job", "purge").Logger())
+	}
+
+	// Keep the entityCounts query's counts warm until shutdown
+	countsCtx, countsCancel := context.WithCancel(context.Background())
+	defer countsCancel()
+	if cfg.EntityCountsInterval > 0 {
+		go resolvers.ScheduleEntityCounts(countsCtx, dbClient, cfg.EntityCountsInterval, appLogger.With().Str("job", "entity_counts").Logger())
+	}
+
+	// Replay failed auxiliary writes once MongoDB is back until shutdown
+	retryCtx, retryCancel := context.WithCancel(context.Background())
+	defer retryCancel()
+	if cfg.WriteRetryEntries > 0 && !cfg.ReadOnly {
+		retries := db.NewWriteRetryQueue(db.WriteRetryOptions{
+			Capacity:  cfg.WriteRetryEntries,
+			BaseDelay: cfg.WriteRetryBaseDelay,
+			MaxDelay:  cfg.WriteRetryMaxDelay,
+		}, db.RealClock{}, appLogger.With().Str("job", "write_retry").Logger())
+		resolvers.SetWriteRetryQueue(retries)
+		go retries.Run(retryCtx, dbClient)
+	}
+
+	// Write the access audit events queued by reads until shutdown, then the remaining ones
+	auditCtx, auditCancel := context.WithCancel(context.Background())
+	defer auditCancel()
+	auditDone := make(chan struct{})
+	go func() {
+		defer close(auditDone)
+		resolvers.RunAccessAuditWriter(auditCtx, dbClient, appLogger.With().Str("job", "access_audit").Logger())
+	}()
 
 	log.Info().
 		Dur("startup_time", time.Since(startTime)).
@@ -94,13 +356,19 @@ func main() {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Start server in goroutine
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 2)
 	go func() {
 		if err := srv.Start(); err != nil {
 			errChan <- err
 		}
 	}()
 
+	// Serve the gRPC read API on its own listener until shutdown
+	if cfg.GRPCEnabled {
+		grpcServer := startGRPCServer(dbClient, cfg, errChan)
+		defer grpcServer.GracefulStop()
+	}
+
 	// Wait for shutdown signal or server error
 	select {
 	case err := <-errChan:
@@ -113,5 +381,192 @@ func main() {
 			Msg("Shutdown signal received")
 	}
 
+	auditCancel()
+	<-auditDone
+
 	log.Info().Msg("Server shutdown complete")
 }
+
+// startGRPCServer binds the gRPC listener and serves the customer read API on it, reporting a
+// failure of the listener on errChan
+func startGRPCServer(dbClient *db.Client, cfg *config.Config, errChan chan<- error) *grpc.Server {
+	grpcServer, err := grpcapi.NewServer(dbClient, grpcapi.Options{
+		AuthToken:       cfg.GRPCAuthToken,
+		TLSCertFile:     cfg.GRPCTLSCertFile,
+		TLSKeyFile:      cfg.GRPCTLSKeyFile,
+		TLSClientCAFile: cfg.GRPCTLSClientCAFile,
+	})
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Invalid gRPC configuration - server cannot start")
+	}
+
+	listener, err := net.Listen("tcp", cfg.GRPCListenAddress)
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Str("address", cfg.GRPCListenAddress).
+			Msg("Failed to bind the gRPC listener")
+	}
+
+	log.Info().
+		Str("address", listener.Addr().String()).
+		Bool("tls", cfg.GRPCTLSCertFile != "").
+		Bool("mtls", cfg.GRPCTLSClientCAFile != "").
+		Msg("Starting gRPC server")
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			errChan <- fmt.Errorf("gRPC server: %w", err)
+		}
+	}()
+	return grpcServer
+}
+
+// runSmokeTest runs the read-only self-test through the resolvers, prints its report as JSON on
+// stdout and returns the process exit code: 0 when every check passed, 1 otherwise
+func runSmokeTest(dbClient *db.Client, schemaInfo *health.SchemaInfo, cfg *config.Config) int {
+	resolver := resolvers.NewResolver(dbClient)
+	resolver.ServiceInfo = &health.ServiceInfo{Schema: schemaInfo, Build: health.CurrentBuildInfo(), ReadOnly: cfg.ReadOnly}
+
+	report := smoke.Run(context.Background(), resolver, smoke.Options{
+		CustomerID:     cfg.SmokeTestCustomerID,
+		SchemaChecksum: cfg.SmokeTestChecksum,
+		Timeout:        cfg.SmokeTestTimeout,
+	})
+
+	disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer disconnectCancel()
+	if reader := dbClient.Reader(); reader != dbClient {
+		_ = reader.Disconnect(disconnectCtx)
+		reader.Close()
+	}
+	_ = dbClient.Disconnect(disconnectCtx)
+	dbClient.Close()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil || !report.Passed {
+		return 1
+	}
+	return 0
+}
+
+// connectReader connects the read connection and routes read paths to it
+// Exits when the read connection cannot be established
+func connectReader(dbClient *db.Client, cfg *config.Config) {
+	reader, err := db.NewClient(cfg.ReadDatabase, log.Logger.With().Str("connection", "read").Logger())
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Failed to create MongoDB read client")
+	}
+
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), cfg.ReadDatabase.ConnectTimeout)
+	err = reader.Connect(connectCtx)
+	connectCancel()
+
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Failed to connect MongoDB read connection")
+	}
+
+	dbClient.SetReader(reader)
+
+	log.Info().
+		Str("read_preference", cfg.ReadDatabase.ReadPreference).
+		Uint64("pool_size", cfg.ReadDatabase.MaxPoolSize).
+		Msg("MongoDB read connection established")
+}
+
+// runMigrations applies pending migrations and exits when one fails
+// Another replica holding the lease is running them, so startup continues
+func runMigrations(dbClient *db.Client, cfg *config.Config) {
+	results, err := migrations.Run(context.Background(), dbClient.Database(), migrations.All(), migrations.Options{
+		DryRun:   cfg.MigrationsDryRun,
+		LeaseTTL: cfg.MigrationsLeaseTTL,
+		Timeout:  cfg.MigrationsTimeout,
+	}, log.Logger)
+
+	if errors.Is(err, migrations.ErrLocked) {
+		log.Warn().Msg("Migrations are running on another instance - skipping")
+		return
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Migrations failed - server cannot start")
+	}
+
+	log.Info().
+		Int("applied", len(results)).
+		Bool("dry_run", cfg.MigrationsDryRun).
+		Msg("Migrations complete")
+}
+
+// indexRetryInterval is the pause between failed index creation attempts
+const indexRetryInterval = 30 * time.Second
+
+// ensureIndexes creates missing indexes, retrying until it succeeds or ctx ends,
+// and reports every attempt to readiness
+func ensureIndexes(ctx context.Context, dbClient *db.Client, cfg *config.Config, readiness *server.Readiness) {
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, cfg.Database.ConnectTimeout)
+		indexes := append(resolvers.HistoryIndexes(cfg.CustomerHistoryRetention), server.IdempotencyIndexes(cfg.IdempotencyKeyTTL)...)
+		indexes = append(indexes, resolvers.InventoryIndexes()...)
+		if len(cfg.AccessAuditEntities) > 0 {
+			indexes = append(indexes, resolvers.AccessAuditIndexes(cfg.AccessAuditRetention)...)
+		}
+		if cfg.PurgeEnabled {
+			indexes = append(indexes, purge.Indexes(resolvers.PurgeTargets())...)
+		}
+		err := dbClient.EnsureIndexes(attemptCtx, indexes)
+		cancel()
+
+		readiness.MarkIndexesEnsured(err)
+		if err == nil {
+			log.Info().Msg("Indexes ensured")
+			return
+		}
+		log.Error().Err(err).Dur("retry_in", indexRetryInterval).Msg("Failed to create indexes - server stays not ready")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(indexRetryInterval):
+		}
+	}
+}
+
+// validateEntityCollections logs every entity config problem and exits in strict mode
+func validateEntityCollections(dbClient *db.Client, cfg *config.Config) *health.EntityValidation {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout)
+	defer cancel()
+
+	problems, err := resolvers.ValidateEntityCollections(ctx, dbClient.Database(), cfg.EntityValidationStrict)
+
+	result := &health.EntityValidation{Status: "ok"}
+	for _, problem := range problems {
+		log.Warn().
+			Str("entity", problem.Entity).
+			Str("collection", problem.Collection).
+			Str("problem", problem.Problem).
+			Msg("Entity config does not match the database")
+
+		result.Status = "problems"
+		result.Problems = append(result.Problems, health.EntityProblem{
+			Entity:     problem.Entity,
+			Collection: problem.Collection,
+			Problem:    problem.Problem,
+		})
+	}
+
+	if err != nil {
+		if cfg.EntityValidationStrict {
+			log.Fatal().Err(err).Msg("Entity config validation failed - server cannot start")
+		}
+		log.Error().Err(err).Msg("Entity config validation could not complete")
+		result.Status = "error"
+	}
+
+	return result
+}
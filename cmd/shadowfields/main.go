@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// shadowfields backfills the lowercase shadow fields used by indexed startsWith filters.
+// It reads the same configuration as the server and can be re-run safely
+//
+//	go run ./cmd/shadowfields -entity customer
+func main() {
+	entity := flag.String("entity", "", "entity to backfill (default: every entity declaring shadow fields)")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	client, err := db.NewClient(cfg.Database, zerolog.Nop())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create MongoDB client: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout)
+	err = client.Connect(connectCtx)
+	connectCancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to MongoDB: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(context.Background())
+
+	results, err := resolvers.BackfillShadowFields(context.Background(), client.Database(), *entity)
+	for _, result := range results {
+		fmt.Printf("Updated %d documents in %s (%s)\n", result.Updated, result.Collection, result.Entity)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to backfill shadow fields: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,205 @@
+// Package airctl implements the airctl command line tool: read-only entity queries against a
+// configured environment, going through the same converters, deletion exclusion and validation
+// as the GraphQL server instead of raw MongoDB queries
+package airctl
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// Subcommands
+const (
+	CommandGet    = "get"
+	CommandSearch = "search"
+	CommandHealth = "health"
+	CommandDump   = "dump"
+	CommandVerify = "verify"
+)
+
+// Output formats
+const (
+	OutputJSON  = "json"
+	OutputTable = "table"
+)
+
+// DefaultSearchLimit is the number of entities search prints unless --limit is given
+const DefaultSearchLimit = 50
+
+// Usage describes the command line
+const Usage = `usage:
+  airctl get <entity> <identifier> [--output json|table]
+  airctl search <entity> [--filter-file f.json] [--sort field:asc|desc,...] [--limit n] [--output json|table]
+  airctl health [--output json|table]
+  airctl dump <entity> --out file.ndjson.gz [--output json|table]
+  airctl verify <file.ndjson.gz> [--output json|table]
+
+The filter file holds the "where" argument of the entity's search query as JSON.
+dump writes every document of the entity's collection, deleted ones included, to a local file;
+verify checks such a file against its header without connecting to the database.
+airctl only reads: it has no subcommands that change data.`
+
+// ErrUsage is returned for command lines that do not name a known subcommand
+var ErrUsage = errors.New(Usage)
+
+// Command is a parsed airctl command line
+type Command struct {
+	Name       string
+	Entity     string
+	Identifier string
+	Filter     json.RawMessage // Search "where" input, nil without --filter-file
+	Order      json.RawMessage // Search "order" input, nil without --sort
+	Limit      int
+	File       string // Dump file: written by dump (--out), read by verify
+	Output     string
+}
+
+// ParseArgs parses the arguments following the program name. Flags may come before or after
+// the positional arguments. readFile reads --filter-file; nil reads from the file system
+func ParseArgs(args []string, readFile func(name string) ([]byte, error)) (*Command, error) {
+	if readFile == nil {
+		readFile = os.ReadFile
+	}
+	if len(args) == 0 {
+		return nil, ErrUsage
+	}
+
+	cmd := &Command{Name: args[0]}
+	fs := flag.NewFlagSet("airctl "+cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&cmd.Output, "output", OutputJSON, "output format: json or table")
+
+	var filterFile, sortSpec string
+	switch cmd.Name {
+	case CommandGet, CommandHealth, CommandVerify:
+	case CommandSearch:
+		fs.StringVar(&filterFile, "filter-file", "", "JSON file holding the search filter")
+		fs.StringVar(&sortSpec, "sort", "", "comma-separated sort fields, each field:asc or field:desc")
+		fs.IntVar(&cmd.Limit, "limit", DefaultSearchLimit, "maximum number of entities")
+	case CommandDump:
+		fs.StringVar(&cmd.File, "out", "", "gzip-compressed NDJSON file to write")
+	default:
+		return nil, fmt.Errorf("unknown subcommand %q: airctl is read-only and supports get, search, health, dump and verify\n\n%w", cmd.Name, ErrUsage)
+	}
+
+	positional, err := parseInterleaved(fs, args[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cmd.Name, err)
+	}
+	if cmd.Output != OutputJSON && cmd.Output != OutputTable {
+		return nil, fmt.Errorf("%s: --output must be %s or %s, got %q", cmd.Name, OutputJSON, OutputTable, cmd.Output)
+	}
+
+	switch cmd.Name {
+	case CommandGet:
+		if len(positional) != 2 {
+			return nil, fmt.Errorf("get: expected <entity> <identifier>, got %d arguments", len(positional))
+		}
+		cmd.Entity, cmd.Identifier = positional[0], positional[1]
+	case CommandSearch:
+		if len(positional) != 1 {
+			return nil, fmt.Errorf("search: expected <entity>, got %d arguments", len(positional))
+		}
+		cmd.Entity = positional[0]
+		if cmd.Limit < 1 {
+			return nil, fmt.Errorf("search: --limit must be positive, got %d", cmd.Limit)
+		}
+		if filterFile != "" {
+			if cmd.Filter, err = readFilter(filterFile, readFile); err != nil {
+				return nil, err
+			}
+		}
+		if sortSpec != "" {
+			if cmd.Order, err = parseSort(sortSpec); err != nil {
+				return nil, err
+			}
+		}
+	case CommandHealth:
+		if len(positional) != 0 {
+			return nil, fmt.Errorf("health: expected no arguments, got %d", len(positional))
+		}
+	case CommandDump:
+		if len(positional) != 1 {
+			return nil, fmt.Errorf("dump: expected <entity>, got %d arguments", len(positional))
+		}
+		cmd.Entity = positional[0]
+		if cmd.File == "" {
+			return nil, errors.New("dump: --out is required")
+		}
+	case CommandVerify:
+		if len(positional) != 1 {
+			return nil, fmt.Errorf("verify: expected <file>, got %d arguments", len(positional))
+		}
+		cmd.File = positional[0]
+	}
+
+	if cmd.Entity != "" && !slices.Contains(resolvers.EntityNames(), cmd.Entity) {
+		return nil, fmt.Errorf("%s: unknown entity %q, expected one of %s", cmd.Name, cmd.Entity, strings.Join(resolvers.EntityNames(), ", "))
+	}
+	return cmd, nil
+}
+
+// parseInterleaved parses flags mixed with positional arguments and returns the positional ones
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// readFilter reads a filter file, which must hold a JSON object
+func readFilter(name string, readFile func(name string) ([]byte, error)) (json.RawMessage, error) {
+	data, err := readFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("search: failed to read filter file: %w", err)
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("search: filter file %s must hold a JSON object: %w", name, err)
+	}
+	return json.RawMessage(data), nil
+}
+
+// parseSort converts field:direction pairs to the search "order" input, one entry per field.
+// Dotted fields address nested sorter inputs; the direction defaults to asc
+func parseSort(spec string) (json.RawMessage, error) {
+	var order []map[string]interface{}
+	for _, entry := range strings.Split(spec, ",") {
+		field, direction, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		if field == "" {
+			return nil, fmt.Errorf("search: --sort entry %q does not name a field", entry)
+		}
+		switch strings.ToLower(direction) {
+		case "", "asc":
+			direction = "ASC"
+		case "desc":
+			direction = "DESC"
+		default:
+			return nil, fmt.Errorf("search: --sort direction of %s must be asc or desc, got %q", field, direction)
+		}
+
+		path := strings.Split(field, ".")
+		var value interface{} = direction
+		for i := len(path) - 1; i > 0; i-- {
+			value = map[string]interface{}{path[i]: value}
+		}
+		order = append(order, map[string]interface{}{path[0]: value})
+	}
+	return json.Marshal(order)
+}
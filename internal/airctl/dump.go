@@ -0,0 +1,177 @@
+package airctl
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// dumpBatchSize is the number of documents a dump reads per cursor batch
+const dumpBatchSize = 500
+
+// checksumPrefix names the algorithm of a dump checksum
+const checksumPrefix = "sha256:"
+
+// ErrDumpMismatch is returned by Verify when the documents of a dump do not match its header
+var ErrDumpMismatch = errors.New("dump does not match its header")
+
+// DumpHeader is the first record of a dump file. Count and Checksum cover the document lines
+// that follow it, exactly as written
+type DumpHeader struct {
+	Entity     string    `json:"entity"`
+	Collection string    `json:"collection"`
+	Count      int64     `json:"count"`
+	Checksum   string    `json:"checksum"`
+	DumpedAt   time.Time `json:"dumpedAt"`
+}
+
+// Dump writes every document of an entity's collection, deleted ones included, to a
+// gzip-compressed NDJSON file: the header record, then one canonical Extended JSON document per
+// line in _id order. Documents are streamed from a server-side cursor to a temporary file next to
+// path while they are counted and hashed, so memory stays bounded by one cursor batch
+func Dump(ctx context.Context, client resolvers.DBClient, entity, path string) (*DumpHeader, error) {
+	collection, err := resolvers.CollectionName(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := os.CreateTemp(filepath.Dir(path), ".airctl-dump-*")
+	if err != nil {
+		return nil, fmt.Errorf("dump: %w", err)
+	}
+	defer os.Remove(body.Name())
+	defer body.Close()
+
+	header := &DumpHeader{Entity: entity, Collection: collection, DumpedAt: time.Now().UTC()}
+	digest := sha256.New()
+	buffered := bufio.NewWriter(io.MultiWriter(body, digest))
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetBatchSize(dumpBatchSize)
+	cursor, err := client.ReadCollection(collection).Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("dump: %w", err)
+	}
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		for cursor.Next(ctx) {
+			line, err := bson.MarshalExtJSON(cursor.Current, true, false)
+			if err != nil {
+				return fmt.Errorf("dump: failed to encode a %s document: %w", entity, err)
+			}
+			buffered.Write(line)
+			if err := buffered.WriteByte('\n'); err != nil {
+				return fmt.Errorf("dump: %w", err)
+			}
+			header.Count++
+		}
+		return cursor.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return nil, fmt.Errorf("dump: %w", err)
+	}
+	header.Checksum = checksumPrefix + hex.EncodeToString(digest.Sum(nil))
+
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("dump: %w", err)
+	}
+	if err := writeDump(path, header, body); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("dump: %w", err)
+	}
+	return header, nil
+}
+
+// writeDump writes the header record and the document lines to a new gzip file
+func writeDump(path string, header *DumpHeader, body io.Reader) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	compressed := gzip.NewWriter(file)
+	if err := json.NewEncoder(compressed).Encode(header); err != nil {
+		return err
+	}
+	if _, err := io.Copy(compressed, body); err != nil {
+		return err
+	}
+	if err := compressed.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Verify re-reads a dump file and checks its document lines against the count and checksum of
+// its header. Corrupt compression fails as well; the file is streamed, so memory stays bounded
+func Verify(path string) (*DumpHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("verify: %w", err)
+	}
+	defer file.Close()
+
+	compressed, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("verify: %s is not a gzip file: %w", path, err)
+	}
+	defer compressed.Close()
+
+	reader := bufio.NewReader(compressed)
+	first, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("verify: %s has no header record: %w", path, err)
+	}
+	header := &DumpHeader{}
+	if err := json.Unmarshal(first, header); err != nil {
+		return nil, fmt.Errorf("verify: invalid header record in %s: %w", path, err)
+	}
+
+	lines := &lineCounter{Hash: sha256.New()}
+	if _, err := io.Copy(lines, reader); err != nil {
+		return nil, fmt.Errorf("verify: %s is corrupt: %w", path, err)
+	}
+	checksum := checksumPrefix + hex.EncodeToString(lines.Sum(nil))
+	if lines.count != header.Count {
+		return header, fmt.Errorf("verify: %w: %d documents, header lists %d", ErrDumpMismatch, lines.count, header.Count)
+	}
+	if checksum != header.Checksum {
+		return header, fmt.Errorf("verify: %w: checksum %s, header lists %s", ErrDumpMismatch, checksum, header.Checksum)
+	}
+	return header, nil
+}
+
+// lineCounter hashes the bytes written to it and counts their lines
+type lineCounter struct {
+	hash.Hash
+	count int64
+}
+
+// Write implements io.Writer
+func (c *lineCounter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b == '\n' {
+			c.count++
+		}
+	}
+	return c.Hash.Write(p)
+}
@@ -0,0 +1,91 @@
+package airctl
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// ErrReadOnly is returned by every write through a ReadOnly client
+var ErrReadOnly = errors.New("airctl is read-only: write operations are refused")
+
+// ReadOnly wraps a database client so its collections refuse inserts, updates and deletes.
+// Every collection is opened through the read connection
+func ReadOnly(client resolvers.DBClient) resolvers.DBClient {
+	return readOnlyClient{DBClient: client}
+}
+
+// readOnlyClient hands out read-only collections
+type readOnlyClient struct {
+	resolvers.DBClient
+}
+
+// Collection returns a read-only collection on the read connection
+func (c readOnlyClient) Collection(name string) db.Collection {
+	return readOnlyCollection{Collection: c.DBClient.ReadCollection(name)}
+}
+
+// ReadCollection returns a read-only collection on the read connection
+func (c readOnlyClient) ReadCollection(name string) db.Collection {
+	return c.Collection(name)
+}
+
+// readOnlyCollection refuses the write operations of a collection
+type readOnlyCollection struct {
+	db.Collection
+}
+
+// InsertOne refuses the write
+func (readOnlyCollection) InsertOne(context.Context, interface{}) (*mongo.InsertOneResult, error) {
+	return nil, ErrReadOnly
+}
+
+// InsertMany refuses the write
+func (readOnlyCollection) InsertMany(context.Context, []interface{}) (*mongo.InsertManyResult, error) {
+	return nil, ErrReadOnly
+}
+
+// UpdateOne refuses the write
+func (readOnlyCollection) UpdateOne(context.Context, interface{}, interface{}) (*mongo.UpdateResult, error) {
+	return nil, ErrReadOnly
+}
+
+// UpdateMany refuses the write
+func (readOnlyCollection) UpdateMany(context.Context, interface{}, interface{}) (*mongo.UpdateResult, error) {
+	return nil, ErrReadOnly
+}
+
+// BulkWrite refuses the write
+func (readOnlyCollection) BulkWrite(context.Context, []mongo.WriteModel, ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	return nil, ErrReadOnly
+}
+
+// DeleteOne refuses the write
+func (readOnlyCollection) DeleteOne(context.Context, interface{}) (*mongo.DeleteResult, error) {
+	return nil, ErrReadOnly
+}
+
+// DeleteMany refuses the write
+func (readOnlyCollection) DeleteMany(context.Context, interface{}) (*mongo.DeleteResult, error) {
+	return nil, ErrReadOnly
+}
+
+// Aggregate refuses pipelines with a $out or $merge stage, which write their results
+func (c readOnlyCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	if stages, ok := pipeline.([]bson.M); ok {
+		for _, stage := range stages {
+			_, out := stage["$out"]
+			_, merge := stage["$merge"]
+			if out || merge {
+				return nil, ErrReadOnly
+			}
+		}
+	}
+	return c.Collection.Aggregate(ctx, pipeline, opts...)
+}
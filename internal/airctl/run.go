@@ -0,0 +1,215 @@
+package airctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// Run executes a parsed command against a database client and prints its result to out.
+// Callers pass a ReadOnly client, or nil for verify, which reads only its file. Entities are read
+// through the same generic resolvers as the server, so deleted entities are excluded and filters
+// are converted and validated alike; dump copies the whole collection instead
+func Run(ctx context.Context, cmd *Command, client resolvers.DBClient, out io.Writer) error {
+	switch cmd.Name {
+	case CommandGet:
+		entity, err := resolvers.GetEntity(ctx, client, cmd.Entity, cmd.Identifier)
+		if err != nil {
+			return err
+		}
+		if entity == nil {
+			return fmt.Errorf("%s %s not found", cmd.Entity, cmd.Identifier)
+		}
+		if cmd.Output == OutputTable {
+			return writeTable(out, []interface{}{entity})
+		}
+		return writeJSON(out, entity)
+
+	case CommandSearch:
+		entities, err := search(ctx, cmd, client)
+		if err != nil {
+			return err
+		}
+		if cmd.Output == OutputTable {
+			return writeTable(out, entities)
+		}
+		return writeJSON(out, entities)
+
+	case CommandHealth:
+		status, err := client.HealthStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if cmd.Output == OutputTable {
+			return writeFields(out, status)
+		}
+		return writeJSON(out, status)
+
+	case CommandDump, CommandVerify:
+		var header *DumpHeader
+		var err error
+		if cmd.Name == CommandDump {
+			header, err = Dump(ctx, client, cmd.Entity, cmd.File)
+		} else {
+			header, err = Verify(cmd.File)
+		}
+		if err != nil {
+			return err
+		}
+		if cmd.Output == OutputTable {
+			return writeFields(out, header)
+		}
+		return writeJSON(out, header)
+	}
+	return ErrUsage
+}
+
+// search reads up to the command's limit of entities matching its filter and order
+func search(ctx context.Context, cmd *Command, client resolvers.DBClient) ([]interface{}, error) {
+	if maxRows := resolvers.CurrentLimits().ExportMaxRows; cmd.Limit > maxRows {
+		return nil, fmt.Errorf("search: --limit exceeds the maximum of %d", maxRows)
+	}
+
+	query, err := resolvers.BuildExportQuery(ctx, cmd.Entity, cmd.Filter, cmd.Order, cmd.Limit)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := client.ReadCollection(query.CollectionName).Aggregate(ctx, query.Pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	entities := []interface{}{}
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		for cursor.Next(ctx) {
+			entity := query.NewEntity()
+			if err := cursor.Decode(entity); err != nil {
+				return fmt.Errorf("search: failed to decode %s: %w", cmd.Entity, err)
+			}
+			entities = append(entities, entity)
+		}
+		return cursor.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// writeJSON prints a value as indented JSON
+func writeJSON(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeTable prints entities as a table with a column per top-level field, identifier first.
+// Nested objects and lists are printed as compact JSON
+func writeTable(out io.Writer, entities []interface{}) error {
+	rows := make([]map[string]interface{}, len(entities))
+	columns := map[string]bool{}
+	for i, entity := range entities {
+		row, err := toFields(entity)
+		if err != nil {
+			return err
+		}
+		for column := range row {
+			columns[column] = true
+		}
+		rows[i] = row
+	}
+
+	header := make([]string, 0, len(columns))
+	for column := range columns {
+		header = append(header, column)
+	}
+	sort.Slice(header, func(i, j int) bool {
+		if (header[i] == "identifier") != (header[j] == "identifier") {
+			return header[i] == "identifier"
+		}
+		return header[i] < header[j]
+	})
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(header))
+		for i, column := range header {
+			cells[i] = formatCell(row[column])
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
+
+// writeFields prints a value as a two-column table of its fields, nested fields as dotted paths
+func writeFields(out io.Writer, value interface{}) error {
+	fields, err := toFields(value)
+	if err != nil {
+		return err
+	}
+
+	flat := map[string]interface{}{}
+	flatten("", fields, flat)
+	paths := make([]string, 0, len(flat))
+	for path := range flat {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, path := range paths {
+		fmt.Fprintf(w, "%s\t%s\n", path, formatCell(flat[path]))
+	}
+	return w.Flush()
+}
+
+// toFields converts a value to its JSON fields
+func toFields(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	return fields, json.Unmarshal(data, &fields)
+}
+
+// flatten collects the leaves of nested JSON objects under their dotted paths
+func flatten(prefix string, fields map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range fields {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(key, nested, flat)
+			continue
+		}
+		flat[key] = value
+	}
+}
+
+// formatCell renders a JSON value for a table cell
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,11 @@
+// Package buildinfo holds build metadata injected at link time
+//
+//	go build -ldflags "-X github.com/yourusername/air-go/internal/buildinfo.GitSHA=$(git rev-parse HEAD) \
+//	  -X github.com/yourusername/air-go/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+package buildinfo
+
+// GitSHA is the commit the binary was built from
+var GitSHA = "unknown"
+
+// BuildTime is the RFC3339 time the binary was built
+var BuildTime = "unknown"
@@ -2,10 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/logger"
 )
 
 // Config holds all configuration for the application
@@ -16,14 +21,313 @@ type Config struct {
 	JWTSecret   string
 	CORSOrigins []string
 	Database    *db.DBConfig // MongoDB configuration
+
+	// Listener addresses (host:port). An empty ListenAddress listens on Port on all interfaces;
+	// an empty AdminListenAddress serves the admin routes on the main listener
+	ListenAddress      string
+	AdminListenAddress string
+
+	// Route paths; the admin routes are /health, /ready, /metrics and the schema reload
+	GraphQLPath      string
+	ExportPath       string
+	HealthPath       string
+	ReadyPath        string
+	MetricsPath      string
+	SchemaReloadPath string
+
+	// Admin route re-reading SchemaPath and serving it without a restart (POST, ADMIN role required)
+	SchemaReloadEnabled bool
+
+	// gRPC customer read API for internal services (api/air/v1) on a listener of its own
+	GRPCEnabled         bool
+	GRPCListenAddress   string // host:port of the gRPC listener
+	GRPCAuthToken       string // Static token clients send as "authorization: Bearer" metadata
+	GRPCTLSCertFile     string // Server certificate; the listener is plaintext without one
+	GRPCTLSKeyFile      string
+	GRPCTLSClientCAFile string // CA bundle of the client certificates required for mTLS
+
+	// Field names whose values are replaced by a hash prefix in logged documents and filters
+	LogRedactedFields []string
+
+	// Minimum log level, per-level sampling (level=N pairs), caller info and output destination
+	LogLevel    string
+	LogSampling string
+	LogCaller   bool
+	LogOutput   string
+
+	// Optional separate connection for read paths; nil reads through Database
+	ReadDatabase *db.DBConfig
+
+	// Tag resolver queries with the GraphQL operation name and request ID for profiler correlation
+	QueryCommentsEnabled bool
+
+	// Report the database operation count and time of every GraphQL response, not only of those
+	// requested with the debug header (non-production deployments)
+	DBStatsAlways bool
+
+	// Persisted operations (allow-list) mode
+	PersistedOperationsEnabled  bool   // Reject operations missing from the manifest
+	PersistedOperationsManifest string // Path to the hash → query JSON manifest
+
+	// GraphQL operation batching (a JSON array of operations per POST)
+	GraphQLBatchMaxSize     int // Operations per batch
+	GraphQLBatchParallelism int // Operations of one batch executed concurrently
+
+	// Maximum complexity of a GraphQL operation; relation fields multiply their selection
+	GraphQLMaxComplexity int
+
+	// How long root resolver calls are kept for the resolverStats query
+	ResolverStatsWindow time.Duration
+
+	// Interval of the background refresh of the entityCounts query's counts (0 disables it)
+	EntityCountsInterval time.Duration
+
+	// Fail startup when search inputs have fields their converters do not map
+	FilterCoverageStrict bool
+
+	// Apply search filters and orders partially, with warnings on the result, instead of
+	// rejecting fields and operators the converters do not support
+	FilterLenient bool
+
+	// Boolean filter fields where a document without the field counts as false (entity.field, comma-separated)
+	FilterAbsentAsFalse []string
+
+	// Require identifiers to be RFC 4122 UUIDs of version 1-5 instead of any UUID-shaped value
+	UUIDStrict bool
+
+	// Maximum number of entries in a search or byKeys order array
+	SortMaxEntries int
+
+	// Cap search first/last above the maximum page size instead of rejecting them (legacy clients)
+	PageSizeCapEnabled bool
+
+	// How long a pagination cursor is accepted after it was issued
+	CursorMaxAge time.Duration
+
+	// Search filter limits
+	FilterMaxIn    int // Values per in list
+	FilterMaxNin   int // Values per nin list
+	FilterMaxAnd   int // Conditions per and list
+	FilterMaxOr    int // Conditions per or list
+	FilterMaxDepth int // Nesting of and/or lists
+	FilterMaxBytes int // Estimated serialized filter size
+
+	// Sanity bounds of DateTime filters on BSON dates (createDate)
+	FilterDateTimeBounds        bool          // Whether the bounds are checked
+	FilterDateTimeMinYear       int           // Earliest year of a filter value
+	FilterDateTimeMaxFutureSkew time.Duration // How far past now a filter value may lie
+
+	// Match startsWith filters against the lowercase shadow fields (run cmd/shadowfields first)
+	ShadowFieldsEnabled bool
+
+	// Check entity collections and deletion fields against the database at startup
+	EntityValidationEnabled bool
+	EntityValidationStrict  bool // Fail startup instead of logging warnings
+
+	// Run pending data migrations at startup
+	MigrationsEnabled  bool
+	MigrationsDryRun   bool          // Count changes without writing
+	MigrationsLeaseTTL time.Duration // Lifetime of the lease that keeps other replicas out
+	MigrationsTimeout  time.Duration // Default time limit per migration
+
+	// Purge deleted entities past their retention period in the background, holding a lease
+	// in the migrations collection of MigrationsLeaseTTL
+	PurgeEnabled    bool
+	PurgeDryRun     bool          // Count the entities that would be purged without deleting
+	PurgeInterval   time.Duration // Time between purge runs
+	PurgeBatchSize  int           // Deletes per BulkWrite
+	PurgeBatchPause time.Duration // Pause between batches, limiting the delete rate
+
+	// Refuse mutations and database writes; migrations and purging cannot be enabled with it,
+	// and indexes are not managed
+	ReadOnly bool
+
+	// Read-only self-test run by the server's -smoke-test flag
+	SmokeTestCustomerID string        // Known customer the customerGet check reads; empty skips it
+	SmokeTestChecksum   string        // Expected schema checksum; empty skips the check
+	SmokeTestTimeout    time.Duration // Budget of the whole self-test
+
+	// Search concurrency limit; a search weighs its page size
+	SearchMaxConcurrentRows int           // Rows concurrently running searches may request
+	SearchQueueTimeout      time.Duration // Longest wait for capacity before RESOURCE_EXHAUSTED
+
+	// Upper bound of the cursor batch size of search and byKeys aggregations
+	AggregateMaxBatchSize int
+
+	// How long customer versions recorded before updates and deletes are kept
+	CustomerHistoryRetention time.Duration
+
+	// Create the service's indexes at startup; /ready reports NOT_READY until they exist.
+	// Always off in read-only mode
+	IndexManagementEnabled bool
+
+	// Read-through cache of Get-by-identifier queries (GetCacheTTL 0 disables it)
+	GetCacheEntities    []string      // Entities whose Get queries are cached
+	GetCacheEntries     int           // Identifiers kept
+	GetCacheTTL         time.Duration // How long found entities are served from the cache
+	GetCacheNegativeTTL time.Duration // How long identifiers that were not found answer null (0 disables)
+
+	// Raw size bound of documents decoded into entity results (0 disables it)
+	DocumentMaxBytes   int
+	DocumentSizeStrict bool // Fail queries reading an oversized document instead of leaving it out
+
+	// Check Get and byKeys lookups that miss for deleted entities and log an audit event for each
+	// one found. Doubles the reads of missing lookups
+	AuditDeletedAccess bool
+
+	// Access audit of Get, byKeys and search reads, written to the access_audit collection
+	AccessAuditEntities      []string      // Entities whose reads are recorded (empty disables the audit)
+	AccessAuditQueueSize     int           // Events waiting for the writer; further events are dropped
+	AccessAuditBatchSize     int           // Events inserted per write
+	AccessAuditFlushInterval time.Duration // Longest time an event waits for its batch to fill
+	AccessAuditRetention     time.Duration // How long recorded reads are kept
+
+	// Replay of failed history, access audit and idempotency writes once MongoDB is back (0 entries disable it)
+	WriteRetryEntries   int           // Failed writes kept; the oldest is dropped beyond it
+	WriteRetryBaseDelay time.Duration // Pause after the first failed replay, doubled per failure
+	WriteRetryMaxDelay  time.Duration // Longest pause between replays
+
+	// How long the response of a mutation sent with an Idempotency-Key answers its replays
+	IdempotencyKeyTTL time.Duration
+
+	// GraphQL websocket connections (0 limits and timeouts disable them)
+	WebsocketKeepAliveInterval time.Duration // Interval of the keep-alive and ping messages
+	WebsocketIdleTimeout       time.Duration // How long a connection may run no operation
+	WebsocketMaxConnections    int           // Concurrent connections of the server
+	WebsocketMaxPerPrincipal   int           // Concurrent connections of one user
+
+	// GraphQL query results kept to answer reads while MongoDB reconnects (0 disables)
+	DegradedCacheEntries int
+
+	// Interval of the background readiness evaluation driving the state reported by /ready
+	ReadinessCheckInterval time.Duration
+
+	// Entities and Query/Mutation fields answered with FEATURE_DISABLED (comma-separated)
+	DisabledEntities     []string
+	DisabledOperations   []string
+	HideDisabledFeatures bool // Also omit the disabled fields from introspection
+
+	// Deprecated fields and arguments: the header naming the client in their usage metrics, and
+	// whether those past their @sunset date answer FIELD_SUNSET
+	DeprecationClientHeader  string
+	DeprecationEnforceSunset bool
+
+	// Large embedded fields searches return as null unless includeHeavyFields is set (entity.field, comma-separated)
+	SearchHeavyFields []string
+
+	// NDJSON export endpoint
+	ExportMaxRows   int           // Row cap per export
+	ExportBatchSize int           // Cursor batch size; the response is flushed after every batch
+	ExportTimeout   time.Duration // Overall time limit per export
+
+	// cmd/anonymize
+	AnonymizeKey               string // HMAC key the fake values are derived from
+	AnonymizeProductionPattern string // Regular expression of database names the command refuses to touch
 }
 
 // Load reads configuration from environment variables
 func Load() (*Config, error) {
 	viper.SetDefault("PORT", 8080)
+	viper.SetDefault("LISTEN_ADDRESS", "")
+	viper.SetDefault("ADMIN_LISTEN_ADDRESS", "")
+	viper.SetDefault("GRPC_ENABLED", false)
+	viper.SetDefault("GRPC_LISTEN_ADDRESS", ":9000")
+	viper.SetDefault("GRPC_AUTH_TOKEN", "")
+	viper.SetDefault("GRPC_TLS_CERT_FILE", "")
+	viper.SetDefault("GRPC_TLS_KEY_FILE", "")
+	viper.SetDefault("GRPC_TLS_CLIENT_CA_FILE", "")
+	viper.SetDefault("GRAPHQL_PATH", "/graphql")
+	viper.SetDefault("EXPORT_PATH", "/export")
+	viper.SetDefault("HEALTH_PATH", "/health")
+	viper.SetDefault("READY_PATH", "/ready")
+	viper.SetDefault("METRICS_PATH", "/metrics")
+	viper.SetDefault("SCHEMA_RELOAD_PATH", "/admin/schema/reload")
+	viper.SetDefault("SCHEMA_RELOAD_ENABLED", false)
 	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("LOG_REDACTED_FIELDS", strings.Join(logger.DefaultRedactedFields, ","))
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_SAMPLING", "")
+	viper.SetDefault("LOG_CALLER", true)
+	viper.SetDefault("LOG_OUTPUT", logger.OutputStdout)
 	viper.SetDefault("SCHEMA_PATH", "./schema.graphqls")
 	viper.SetDefault("CORS_ORIGINS", []string{"*"})
+	viper.SetDefault("PERSISTED_OPERATIONS_ENABLED", false)
+	viper.SetDefault("PERSISTED_OPERATIONS_MANIFEST", "./persisted-operations.json")
+	viper.SetDefault("GRAPHQL_BATCH_MAX_SIZE", 10)
+	viper.SetDefault("GRAPHQL_BATCH_PARALLELISM", 1)
+	viper.SetDefault("GRAPHQL_MAX_COMPLEXITY", 20000)
+	viper.SetDefault("RESOLVER_STATS_WINDOW", "5m")
+	viper.SetDefault("ENTITY_COUNTS_INTERVAL", "30s")
+	viper.SetDefault("FILTER_COVERAGE_STRICT", false)
+	viper.SetDefault("FILTER_LENIENT", false)
+	viper.SetDefault("FILTER_ABSENT_AS_FALSE", "customer.isShared,team.isShared")
+	viper.SetDefault("UUID_STRICT", true)
+	viper.SetDefault("SORT_MAX_ENTRIES", 5)
+	viper.SetDefault("CURSOR_MAX_AGE", "24h")
+	viper.SetDefault("FILTER_MAX_IN", 1000)
+	viper.SetDefault("FILTER_MAX_NIN", 1000)
+	viper.SetDefault("FILTER_MAX_AND", 50)
+	viper.SetDefault("FILTER_MAX_OR", 50)
+	viper.SetDefault("FILTER_MAX_DEPTH", 5)
+	viper.SetDefault("FILTER_MAX_BYTES", 65536)
+	viper.SetDefault("FILTER_DATETIME_BOUNDS", true)
+	viper.SetDefault("FILTER_DATETIME_MIN_YEAR", 1970)
+	viper.SetDefault("FILTER_DATETIME_MAX_FUTURE_SKEW", "24h")
+	viper.SetDefault("SHADOW_FIELDS_ENABLED", false)
+	viper.SetDefault("PAGE_SIZE_CAP_ENABLED", false)
+	viper.SetDefault("ENTITY_VALIDATION_ENABLED", false)
+	viper.SetDefault("ENTITY_VALIDATION_STRICT", false)
+	viper.SetDefault("MIGRATIONS_ENABLED", false)
+	viper.SetDefault("MIGRATIONS_DRY_RUN", false)
+	viper.SetDefault("MIGRATIONS_LEASE_TTL", "2m")
+	viper.SetDefault("MIGRATIONS_TIMEOUT", "30m")
+	viper.SetDefault("PURGE_ENABLED", false)
+	viper.SetDefault("PURGE_DRY_RUN", false)
+	viper.SetDefault("PURGE_INTERVAL", "24h")
+	viper.SetDefault("PURGE_BATCH_SIZE", 500)
+	viper.SetDefault("PURGE_BATCH_PAUSE", "1s")
+	viper.SetDefault("READ_ONLY", false)
+	viper.SetDefault("SMOKE_TEST_CUSTOMER_ID", "")
+	viper.SetDefault("SMOKE_TEST_SCHEMA_CHECKSUM", "")
+	viper.SetDefault("SMOKE_TEST_TIMEOUT", "30s")
+	viper.SetDefault("SEARCH_MAX_CONCURRENT_ROWS", 1000)
+	viper.SetDefault("SEARCH_QUEUE_TIMEOUT", "2s")
+	viper.SetDefault("AGGREGATE_MAX_BATCH_SIZE", 1000)
+	viper.SetDefault("CUSTOMER_HISTORY_RETENTION", "2160h")
+	viper.SetDefault("INDEX_MANAGEMENT_ENABLED", true)
+	viper.SetDefault("GET_CACHE_ENTITIES", "customer,team,inventory,executionPlan")
+	viper.SetDefault("GET_CACHE_ENTRIES", 10000)
+	viper.SetDefault("GET_CACHE_TTL", "0s")
+	viper.SetDefault("GET_CACHE_NEGATIVE_TTL", "2s")
+	viper.SetDefault("DOCUMENT_MAX_BYTES", 4<<20)
+	viper.SetDefault("DOCUMENT_SIZE_STRICT", false)
+	viper.SetDefault("AUDIT_DELETED_ACCESS", false)
+	viper.SetDefault("ACCESS_AUDIT_ENTITIES", "")
+	viper.SetDefault("ACCESS_AUDIT_QUEUE_SIZE", 10000)
+	viper.SetDefault("ACCESS_AUDIT_BATCH_SIZE", 500)
+	viper.SetDefault("ACCESS_AUDIT_FLUSH_INTERVAL", "1s")
+	viper.SetDefault("ACCESS_AUDIT_RETENTION", "8760h")
+	viper.SetDefault("WRITE_RETRY_ENTRIES", 1000)
+	viper.SetDefault("WRITE_RETRY_BASE_DELAY", "1s")
+	viper.SetDefault("WRITE_RETRY_MAX_DELAY", "30s")
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", "24h")
+	viper.SetDefault("WEBSOCKET_KEEPALIVE_INTERVAL", "10s")
+	viper.SetDefault("WEBSOCKET_IDLE_TIMEOUT", "5m")
+	viper.SetDefault("WEBSOCKET_MAX_CONNECTIONS", 1000)
+	viper.SetDefault("WEBSOCKET_MAX_CONNECTIONS_PER_PRINCIPAL", 10)
+	viper.SetDefault("DEGRADED_CACHE_ENTRIES", 1000)
+	viper.SetDefault("READINESS_CHECK_INTERVAL", "5s")
+	viper.SetDefault("DISABLED_ENTITIES", "")
+	viper.SetDefault("DISABLED_OPERATIONS", "")
+	viper.SetDefault("HIDE_DISABLED_FEATURES", false)
+	viper.SetDefault("DEPRECATION_CLIENT_HEADER", "X-Client-Name")
+	viper.SetDefault("DEPRECATION_ENFORCE_SUNSET", false)
+	viper.SetDefault("SEARCH_HEAVY_FIELDS", "customer.payment,customer.openBanking")
+	viper.SetDefault("EXPORT_MAX_ROWS", 100000)
+	viper.SetDefault("EXPORT_BATCH_SIZE", 500)
+	viper.SetDefault("EXPORT_TIMEOUT", "5m")
+	viper.SetDefault("ANONYMIZE_KEY", "")
+	viper.SetDefault("ANONYMIZE_PRODUCTION_PATTERN", "(?i)prod")
 
 	// MongoDB defaults
 	viper.SetDefault("MONGODB_URI", "mongodb://localhost:27017")
@@ -36,6 +340,13 @@ func Load() (*Config, error) {
 	viper.SetDefault("MONGODB_RETRY_ATTEMPTS", 3)
 	viper.SetDefault("MONGODB_RETRY_BASE_DELAY", "1s")
 	viper.SetDefault("MONGODB_RETRY_MAX_DELAY", "10s")
+	viper.SetDefault("MONGODB_READ_URI", "")
+	viper.SetDefault("MONGODB_READ_PREFERENCE", "")
+	viper.SetDefault("MONGODB_READ_POOL_MIN", 5)
+	viper.SetDefault("MONGODB_READ_POOL_MAX", 20)
+	viper.SetDefault("MONGODB_READ_FALLBACK_SECONDARY", false)
+	viper.SetDefault("MONGODB_QUERY_COMMENTS", true)
+	viper.SetDefault("DB_STATS_ALWAYS", false)
 
 	viper.AutomaticEnv()
 
@@ -48,11 +359,110 @@ func Load() (*Config, error) {
 	}
 
 	cfg := &Config{
-		Port:        viper.GetInt("PORT"),
-		LogFormat:   viper.GetString("LOG_FORMAT"),
-		SchemaPath:  viper.GetString("SCHEMA_PATH"),
-		JWTSecret:   viper.GetString("JWT_SECRET"),
-		CORSOrigins: viper.GetStringSlice("CORS_ORIGINS"),
+		Port:                        viper.GetInt("PORT"),
+		ListenAddress:               viper.GetString("LISTEN_ADDRESS"),
+		AdminListenAddress:          viper.GetString("ADMIN_LISTEN_ADDRESS"),
+		GRPCEnabled:                 viper.GetBool("GRPC_ENABLED"),
+		GRPCListenAddress:           viper.GetString("GRPC_LISTEN_ADDRESS"),
+		GRPCAuthToken:               viper.GetString("GRPC_AUTH_TOKEN"),
+		GRPCTLSCertFile:             viper.GetString("GRPC_TLS_CERT_FILE"),
+		GRPCTLSKeyFile:              viper.GetString("GRPC_TLS_KEY_FILE"),
+		GRPCTLSClientCAFile:         viper.GetString("GRPC_TLS_CLIENT_CA_FILE"),
+		GraphQLPath:                 viper.GetString("GRAPHQL_PATH"),
+		ExportPath:                  viper.GetString("EXPORT_PATH"),
+		HealthPath:                  viper.GetString("HEALTH_PATH"),
+		ReadyPath:                   viper.GetString("READY_PATH"),
+		MetricsPath:                 viper.GetString("METRICS_PATH"),
+		SchemaReloadPath:            viper.GetString("SCHEMA_RELOAD_PATH"),
+		SchemaReloadEnabled:         viper.GetBool("SCHEMA_RELOAD_ENABLED"),
+		LogFormat:                   viper.GetString("LOG_FORMAT"),
+		LogRedactedFields:           splitList(viper.GetString("LOG_REDACTED_FIELDS")),
+		LogLevel:                    viper.GetString("LOG_LEVEL"),
+		LogSampling:                 viper.GetString("LOG_SAMPLING"),
+		LogCaller:                   viper.GetBool("LOG_CALLER"),
+		LogOutput:                   viper.GetString("LOG_OUTPUT"),
+		SchemaPath:                  viper.GetString("SCHEMA_PATH"),
+		JWTSecret:                   viper.GetString("JWT_SECRET"),
+		CORSOrigins:                 viper.GetStringSlice("CORS_ORIGINS"),
+		PersistedOperationsEnabled:  viper.GetBool("PERSISTED_OPERATIONS_ENABLED"),
+		PersistedOperationsManifest: viper.GetString("PERSISTED_OPERATIONS_MANIFEST"),
+		GraphQLBatchMaxSize:         viper.GetInt("GRAPHQL_BATCH_MAX_SIZE"),
+		GraphQLBatchParallelism:     viper.GetInt("GRAPHQL_BATCH_PARALLELISM"),
+		GraphQLMaxComplexity:        viper.GetInt("GRAPHQL_MAX_COMPLEXITY"),
+		ResolverStatsWindow:         viper.GetDuration("RESOLVER_STATS_WINDOW"),
+		EntityCountsInterval:        viper.GetDuration("ENTITY_COUNTS_INTERVAL"),
+		FilterCoverageStrict:        viper.GetBool("FILTER_COVERAGE_STRICT"),
+		FilterLenient:               viper.GetBool("FILTER_LENIENT"),
+		FilterAbsentAsFalse:         splitList(viper.GetString("FILTER_ABSENT_AS_FALSE")),
+		UUIDStrict:                  viper.GetBool("UUID_STRICT"),
+		SortMaxEntries:              viper.GetInt("SORT_MAX_ENTRIES"),
+		CursorMaxAge:                viper.GetDuration("CURSOR_MAX_AGE"),
+		FilterMaxIn:                 viper.GetInt("FILTER_MAX_IN"),
+		FilterMaxNin:                viper.GetInt("FILTER_MAX_NIN"),
+		FilterMaxAnd:                viper.GetInt("FILTER_MAX_AND"),
+		FilterMaxOr:                 viper.GetInt("FILTER_MAX_OR"),
+		FilterMaxDepth:              viper.GetInt("FILTER_MAX_DEPTH"),
+		FilterMaxBytes:              viper.GetInt("FILTER_MAX_BYTES"),
+		FilterDateTimeBounds:        viper.GetBool("FILTER_DATETIME_BOUNDS"),
+		FilterDateTimeMinYear:       viper.GetInt("FILTER_DATETIME_MIN_YEAR"),
+		FilterDateTimeMaxFutureSkew: viper.GetDuration("FILTER_DATETIME_MAX_FUTURE_SKEW"),
+		ShadowFieldsEnabled:         viper.GetBool("SHADOW_FIELDS_ENABLED"),
+		PageSizeCapEnabled:          viper.GetBool("PAGE_SIZE_CAP_ENABLED"),
+		EntityValidationEnabled:     viper.GetBool("ENTITY_VALIDATION_ENABLED"),
+		EntityValidationStrict:      viper.GetBool("ENTITY_VALIDATION_STRICT"),
+		MigrationsEnabled:           viper.GetBool("MIGRATIONS_ENABLED"),
+		MigrationsDryRun:            viper.GetBool("MIGRATIONS_DRY_RUN"),
+		MigrationsLeaseTTL:          viper.GetDuration("MIGRATIONS_LEASE_TTL"),
+		MigrationsTimeout:           viper.GetDuration("MIGRATIONS_TIMEOUT"),
+		PurgeEnabled:                viper.GetBool("PURGE_ENABLED"),
+		PurgeDryRun:                 viper.GetBool("PURGE_DRY_RUN"),
+		PurgeInterval:               viper.GetDuration("PURGE_INTERVAL"),
+		PurgeBatchSize:              viper.GetInt("PURGE_BATCH_SIZE"),
+		PurgeBatchPause:             viper.GetDuration("PURGE_BATCH_PAUSE"),
+		ReadOnly:                    viper.GetBool("READ_ONLY"),
+		SmokeTestCustomerID:         viper.GetString("SMOKE_TEST_CUSTOMER_ID"),
+		SmokeTestChecksum:           viper.GetString("SMOKE_TEST_SCHEMA_CHECKSUM"),
+		SmokeTestTimeout:            viper.GetDuration("SMOKE_TEST_TIMEOUT"),
+		SearchMaxConcurrentRows:     viper.GetInt("SEARCH_MAX_CONCURRENT_ROWS"),
+		SearchQueueTimeout:          viper.GetDuration("SEARCH_QUEUE_TIMEOUT"),
+		AggregateMaxBatchSize:       viper.GetInt("AGGREGATE_MAX_BATCH_SIZE"),
+		CustomerHistoryRetention:    viper.GetDuration("CUSTOMER_HISTORY_RETENTION"),
+		IndexManagementEnabled:      viper.GetBool("INDEX_MANAGEMENT_ENABLED") && !viper.GetBool("READ_ONLY"),
+		GetCacheEntities:            splitList(viper.GetString("GET_CACHE_ENTITIES")),
+		GetCacheEntries:             viper.GetInt("GET_CACHE_ENTRIES"),
+		GetCacheTTL:                 viper.GetDuration("GET_CACHE_TTL"),
+		GetCacheNegativeTTL:         viper.GetDuration("GET_CACHE_NEGATIVE_TTL"),
+		DocumentMaxBytes:            viper.GetInt("DOCUMENT_MAX_BYTES"),
+		DocumentSizeStrict:          viper.GetBool("DOCUMENT_SIZE_STRICT"),
+		AuditDeletedAccess:          viper.GetBool("AUDIT_DELETED_ACCESS"),
+		AccessAuditEntities:         splitList(viper.GetString("ACCESS_AUDIT_ENTITIES")),
+		AccessAuditQueueSize:        viper.GetInt("ACCESS_AUDIT_QUEUE_SIZE"),
+		AccessAuditBatchSize:        viper.GetInt("ACCESS_AUDIT_BATCH_SIZE"),
+		AccessAuditFlushInterval:    viper.GetDuration("ACCESS_AUDIT_FLUSH_INTERVAL"),
+		AccessAuditRetention:        viper.GetDuration("ACCESS_AUDIT_RETENTION"),
+		WriteRetryEntries:           viper.GetInt("WRITE_RETRY_ENTRIES"),
+		WriteRetryBaseDelay:         viper.GetDuration("WRITE_RETRY_BASE_DELAY"),
+		WriteRetryMaxDelay:          viper.GetDuration("WRITE_RETRY_MAX_DELAY"),
+		IdempotencyKeyTTL:           viper.GetDuration("IDEMPOTENCY_KEY_TTL"),
+		WebsocketKeepAliveInterval:  viper.GetDuration("WEBSOCKET_KEEPALIVE_INTERVAL"),
+		WebsocketIdleTimeout:        viper.GetDuration("WEBSOCKET_IDLE_TIMEOUT"),
+		WebsocketMaxConnections:     viper.GetInt("WEBSOCKET_MAX_CONNECTIONS"),
+		WebsocketMaxPerPrincipal:    viper.GetInt("WEBSOCKET_MAX_CONNECTIONS_PER_PRINCIPAL"),
+		DegradedCacheEntries:        viper.GetInt("DEGRADED_CACHE_ENTRIES"),
+		ReadinessCheckInterval:      viper.GetDuration("READINESS_CHECK_INTERVAL"),
+		DisabledEntities:            splitList(viper.GetString("DISABLED_ENTITIES")),
+		DisabledOperations:          splitList(viper.GetString("DISABLED_OPERATIONS")),
+		HideDisabledFeatures:        viper.GetBool("HIDE_DISABLED_FEATURES"),
+		DeprecationClientHeader:     viper.GetString("DEPRECATION_CLIENT_HEADER"),
+		DeprecationEnforceSunset:    viper.GetBool("DEPRECATION_ENFORCE_SUNSET"),
+		SearchHeavyFields:           splitList(viper.GetString("SEARCH_HEAVY_FIELDS")),
+		ExportMaxRows:               viper.GetInt("EXPORT_MAX_ROWS"),
+		ExportBatchSize:             viper.GetInt("EXPORT_BATCH_SIZE"),
+		ExportTimeout:               viper.GetDuration("EXPORT_TIMEOUT"),
+		AnonymizeKey:                viper.GetString("ANONYMIZE_KEY"),
+		AnonymizeProductionPattern:  viper.GetString("ANONYMIZE_PRODUCTION_PATTERN"),
+		QueryCommentsEnabled:        viper.GetBool("MONGODB_QUERY_COMMENTS"),
+		DBStatsAlways:               viper.GetBool("DB_STATS_ALWAYS"),
 		Database: &db.DBConfig{
 			URI:              viper.GetString("MONGODB_URI"),
 			Database:         viper.GetString("MONGODB_DATABASE"),
@@ -64,6 +474,8 @@ func Load() (*Config, error) {
 			MaxRetryAttempts: viper.GetInt("MONGODB_RETRY_ATTEMPTS"),
 			RetryBaseDelay:   viper.GetDuration("MONGODB_RETRY_BASE_DELAY"),
 			RetryMaxDelay:    viper.GetDuration("MONGODB_RETRY_MAX_DELAY"),
+
+			ReadFallbackSecondary: viper.GetBool("MONGODB_READ_FALLBACK_SECONDARY"),
 		},
 	}
 
@@ -76,19 +488,102 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("database configuration invalid: %w", err)
 	}
 
+	cfg.ReadDatabase = readDatabaseConfig(cfg.Database)
+	if cfg.ReadDatabase != nil {
+		if err := cfg.ReadDatabase.Validate(); err != nil {
+			return nil, fmt.Errorf("read database configuration invalid: %w", err)
+		}
+	}
+
 	return cfg, nil
 }
 
+// splitList splits a comma-separated value, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readDatabaseConfig builds the read connection config from MONGODB_READ_* on top of the primary one.
+// Returns nil when neither MONGODB_READ_URI nor MONGODB_READ_PREFERENCE is set
+func readDatabaseConfig(primary *db.DBConfig) *db.DBConfig {
+	uri := viper.GetString("MONGODB_READ_URI")
+	readPreference := viper.GetString("MONGODB_READ_PREFERENCE")
+	if uri == "" && readPreference == "" {
+		return nil
+	}
+	if uri == "" {
+		uri = primary.URI
+	}
+
+	read := *primary
+	read.URI = uri
+	read.ReadPreference = readPreference
+	read.MinPoolSize = uint64(viper.GetInt("MONGODB_READ_POOL_MIN"))
+	read.MaxPoolSize = uint64(viper.GetInt("MONGODB_READ_POOL_MAX"))
+	return &read
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if c.Port < 1024 || c.Port > 65535 {
 		return fmt.Errorf("PORT must be between 1024 and 65535, got %d", c.Port)
 	}
 
+	for name, address := range map[string]string{"LISTEN_ADDRESS": c.ListenAddress, "ADMIN_LISTEN_ADDRESS": c.AdminListenAddress} {
+		if address == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return fmt.Errorf("%s must be host:port, got '%s'", name, address)
+		}
+	}
+
+	if err := c.validateGRPC(); err != nil {
+		return err
+	}
+
+	paths := make(map[string]string)
+	for _, route := range []struct{ name, path string }{
+		{"GRAPHQL_PATH", c.GraphQLPath},
+		{"EXPORT_PATH", c.ExportPath},
+		{"HEALTH_PATH", c.HealthPath},
+		{"READY_PATH", c.ReadyPath},
+		{"METRICS_PATH", c.MetricsPath},
+		{"SCHEMA_RELOAD_PATH", c.SchemaReloadPath},
+	} {
+		if !strings.HasPrefix(route.path, "/") || route.path == "/" || strings.HasSuffix(route.path, "/") {
+			return fmt.Errorf("%s must start with '/' and must not end with '/', got '%s'", route.name, route.path)
+		}
+		if other, ok := paths[route.path]; ok {
+			return fmt.Errorf("%s must differ from %s, got '%s'", route.name, other, route.path)
+		}
+		paths[route.path] = route.name
+	}
+
 	if c.LogFormat != "json" && c.LogFormat != "console" {
 		return fmt.Errorf("LOG_FORMAT must be 'json' or 'console', got '%s'", c.LogFormat)
 	}
 
+	switch c.LogLevel {
+	case "trace", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("LOG_LEVEL must be 'trace', 'debug', 'info', 'warn' or 'error', got '%s'", c.LogLevel)
+	}
+
+	if _, err := logger.ParseSampling(c.LogSampling); err != nil {
+		return fmt.Errorf("LOG_SAMPLING: %w", err)
+	}
+
+	if c.LogOutput == "" {
+		return fmt.Errorf("LOG_OUTPUT must be 'stdout', 'stderr', 'split' or a file path")
+	}
+
 	if c.SchemaPath == "" {
 		return fmt.Errorf("SCHEMA_PATH is required")
 	}
@@ -101,5 +596,202 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JWT_SECRET should be at least 32 characters long for security, got %d characters", len(c.JWTSecret))
 	}
 
+	if c.PersistedOperationsEnabled && c.PersistedOperationsManifest == "" {
+		return fmt.Errorf("PERSISTED_OPERATIONS_MANIFEST is required when PERSISTED_OPERATIONS_ENABLED is set")
+	}
+
+	if c.GraphQLBatchMaxSize < 1 {
+		return fmt.Errorf("GRAPHQL_BATCH_MAX_SIZE must be positive, got %d", c.GraphQLBatchMaxSize)
+	}
+
+	if c.GraphQLBatchParallelism < 1 {
+		return fmt.Errorf("GRAPHQL_BATCH_PARALLELISM must be positive, got %d", c.GraphQLBatchParallelism)
+	}
+
+	if c.GraphQLMaxComplexity < 1 {
+		return fmt.Errorf("GRAPHQL_MAX_COMPLEXITY must be positive, got %d", c.GraphQLMaxComplexity)
+	}
+
+	if c.SearchMaxConcurrentRows < 1 {
+		return fmt.Errorf("SEARCH_MAX_CONCURRENT_ROWS must be positive, got %d", c.SearchMaxConcurrentRows)
+	}
+
+	if c.SearchQueueTimeout <= 0 {
+		return fmt.Errorf("SEARCH_QUEUE_TIMEOUT must be positive, got %v", c.SearchQueueTimeout)
+	}
+
+	if c.CustomerHistoryRetention < time.Second {
+		return fmt.Errorf("CUSTOMER_HISTORY_RETENTION must be at least 1s, got %v", c.CustomerHistoryRetention)
+	}
+
+	if len(c.AccessAuditEntities) > 0 {
+		if c.AccessAuditQueueSize < 1 || c.AccessAuditBatchSize < 1 {
+			return fmt.Errorf("ACCESS_AUDIT_QUEUE_SIZE and ACCESS_AUDIT_BATCH_SIZE must be positive, got %d and %d", c.AccessAuditQueueSize, c.AccessAuditBatchSize)
+		}
+		if c.AccessAuditFlushInterval <= 0 {
+			return fmt.Errorf("ACCESS_AUDIT_FLUSH_INTERVAL must be positive, got %s", c.AccessAuditFlushInterval)
+		}
+		if c.AccessAuditRetention < time.Second {
+			return fmt.Errorf("ACCESS_AUDIT_RETENTION must be at least 1s, got %s", c.AccessAuditRetention)
+		}
+	}
+
+	if c.WriteRetryEntries < 0 {
+		return fmt.Errorf("WRITE_RETRY_ENTRIES must not be negative, got %d", c.WriteRetryEntries)
+	}
+	if c.WriteRetryEntries > 0 && (c.WriteRetryBaseDelay <= 0 || c.WriteRetryMaxDelay < c.WriteRetryBaseDelay) {
+		return fmt.Errorf("WRITE_RETRY_BASE_DELAY must be positive and at most WRITE_RETRY_MAX_DELAY, got %s and %s", c.WriteRetryBaseDelay, c.WriteRetryMaxDelay)
+	}
+
+	if c.GetCacheTTL < 0 || c.GetCacheNegativeTTL < 0 {
+		return fmt.Errorf("GET_CACHE_TTL and GET_CACHE_NEGATIVE_TTL must not be negative, got %s and %s", c.GetCacheTTL, c.GetCacheNegativeTTL)
+	}
+	if c.GetCacheTTL > 0 && c.GetCacheEntries < 1 {
+		return fmt.Errorf("GET_CACHE_ENTRIES must be positive, got %d", c.GetCacheEntries)
+	}
+
+	if c.EntityCountsInterval != 0 && c.EntityCountsInterval < time.Second {
+		return fmt.Errorf("ENTITY_COUNTS_INTERVAL must be 0 or at least 1s, got %s", c.EntityCountsInterval)
+	}
+
+	if c.IdempotencyKeyTTL < time.Minute {
+		return fmt.Errorf("IDEMPOTENCY_KEY_TTL must be at least 1m, got %s", c.IdempotencyKeyTTL)
+	}
+
+	if c.WebsocketKeepAliveInterval < time.Second {
+		return fmt.Errorf("WEBSOCKET_KEEPALIVE_INTERVAL must be at least 1s, got %s", c.WebsocketKeepAliveInterval)
+	}
+	if c.WebsocketIdleTimeout < 0 {
+		return fmt.Errorf("WEBSOCKET_IDLE_TIMEOUT must not be negative, got %s", c.WebsocketIdleTimeout)
+	}
+	if c.WebsocketMaxConnections < 0 || c.WebsocketMaxPerPrincipal < 0 {
+		return fmt.Errorf("WEBSOCKET_MAX_CONNECTIONS and WEBSOCKET_MAX_CONNECTIONS_PER_PRINCIPAL must not be negative, got %d and %d",
+			c.WebsocketMaxConnections, c.WebsocketMaxPerPrincipal)
+	}
+
+	if c.DegradedCacheEntries < 0 {
+		return fmt.Errorf("DEGRADED_CACHE_ENTRIES must not be negative, got %d", c.DegradedCacheEntries)
+	}
+
+	if c.ReadinessCheckInterval <= 0 {
+		return fmt.Errorf("READINESS_CHECK_INTERVAL must be positive, got %s", c.ReadinessCheckInterval)
+	}
+
+	if c.SortMaxEntries < 1 {
+		return fmt.Errorf("SORT_MAX_ENTRIES must be positive, got %d", c.SortMaxEntries)
+	}
+
+	if c.CursorMaxAge < time.Second {
+		return fmt.Errorf("CURSOR_MAX_AGE must be at least 1s, got %s", c.CursorMaxAge)
+	}
+
+	for name, value := range map[string]int{
+		"FILTER_MAX_IN":    c.FilterMaxIn,
+		"FILTER_MAX_NIN":   c.FilterMaxNin,
+		"FILTER_MAX_AND":   c.FilterMaxAnd,
+		"FILTER_MAX_OR":    c.FilterMaxOr,
+		"FILTER_MAX_DEPTH": c.FilterMaxDepth,
+		"FILTER_MAX_BYTES": c.FilterMaxBytes,
+	} {
+		if value < 1 {
+			return fmt.Errorf("%s must be positive, got %d", name, value)
+		}
+	}
+
+	if c.FilterDateTimeMinYear < 1 || c.FilterDateTimeMinYear > 9999 {
+		return fmt.Errorf("FILTER_DATETIME_MIN_YEAR must be between 1 and 9999, got %d", c.FilterDateTimeMinYear)
+	}
+
+	if c.FilterDateTimeMaxFutureSkew < 0 {
+		return fmt.Errorf("FILTER_DATETIME_MAX_FUTURE_SKEW must not be negative, got %s", c.FilterDateTimeMaxFutureSkew)
+	}
+
+	if c.DocumentMaxBytes < 0 {
+		return fmt.Errorf("DOCUMENT_MAX_BYTES must not be negative, got %d", c.DocumentMaxBytes)
+	}
+
+	if c.MigrationsLeaseTTL <= 0 {
+		return fmt.Errorf("MIGRATIONS_LEASE_TTL must be positive, got %s", c.MigrationsLeaseTTL)
+	}
+
+	if c.MigrationsTimeout <= 0 {
+		return fmt.Errorf("MIGRATIONS_TIMEOUT must be positive, got %s", c.MigrationsTimeout)
+	}
+
+	if c.PurgeInterval <= 0 {
+		return fmt.Errorf("PURGE_INTERVAL must be positive, got %s", c.PurgeInterval)
+	}
+
+	if c.PurgeBatchSize < 1 {
+		return fmt.Errorf("PURGE_BATCH_SIZE must be positive, got %d", c.PurgeBatchSize)
+	}
+
+	if c.PurgeBatchPause < 0 {
+		return fmt.Errorf("PURGE_BATCH_PAUSE must not be negative, got %s", c.PurgeBatchPause)
+	}
+
+	if c.ReadOnly && c.MigrationsEnabled {
+		return fmt.Errorf("MIGRATIONS_ENABLED cannot be combined with READ_ONLY")
+	}
+
+	if c.ReadOnly && c.PurgeEnabled {
+		return fmt.Errorf("PURGE_ENABLED cannot be combined with READ_ONLY")
+	}
+
+	// The audit inserts its events, which the read-only client refuses
+	if c.ReadOnly && len(c.AccessAuditEntities) > 0 {
+		return fmt.Errorf("ACCESS_AUDIT_ENTITIES cannot be combined with READ_ONLY")
+	}
+
+	if c.SmokeTestTimeout <= 0 {
+		return fmt.Errorf("SMOKE_TEST_TIMEOUT must be positive, got %s", c.SmokeTestTimeout)
+	}
+
+	if c.AggregateMaxBatchSize < 1 {
+		return fmt.Errorf("AGGREGATE_MAX_BATCH_SIZE must be positive, got %d", c.AggregateMaxBatchSize)
+	}
+
+	if c.ExportMaxRows < 1 {
+		return fmt.Errorf("EXPORT_MAX_ROWS must be positive, got %d", c.ExportMaxRows)
+	}
+
+	if c.ExportBatchSize < 1 {
+		return fmt.Errorf("EXPORT_BATCH_SIZE must be positive, got %d", c.ExportBatchSize)
+	}
+
+	if c.ExportTimeout <= 0 {
+		return fmt.Errorf("EXPORT_TIMEOUT must be positive, got %s", c.ExportTimeout)
+	}
+
+	if c.AnonymizeProductionPattern == "" {
+		return fmt.Errorf("ANONYMIZE_PRODUCTION_PATTERN must not be empty")
+	}
+	if _, err := regexp.Compile(c.AnonymizeProductionPattern); err != nil {
+		return fmt.Errorf("ANONYMIZE_PRODUCTION_PATTERN is not a valid regular expression: %w", err)
+	}
+
+	return nil
+}
+
+// validateGRPC checks the gRPC listener settings when the listener is enabled
+func (c *Config) validateGRPC() error {
+	if !c.GRPCEnabled {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(c.GRPCListenAddress); err != nil {
+		return fmt.Errorf("GRPC_LISTEN_ADDRESS must be host:port, got '%s'", c.GRPCListenAddress)
+	}
+	if c.GRPCAuthToken == "" && c.GRPCTLSClientCAFile == "" {
+		return fmt.Errorf("GRPC_ENABLED requires GRPC_AUTH_TOKEN or GRPC_TLS_CLIENT_CA_FILE")
+	}
+	if c.GRPCAuthToken != "" && len(c.GRPCAuthToken) < 32 {
+		return fmt.Errorf("GRPC_AUTH_TOKEN should be at least 32 characters long, got %d characters", len(c.GRPCAuthToken))
+	}
+	if (c.GRPCTLSCertFile == "") != (c.GRPCTLSKeyFile == "") {
+		return fmt.Errorf("GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE must be set together")
+	}
+	if c.GRPCTLSClientCAFile != "" && c.GRPCTLSCertFile == "" {
+		return fmt.Errorf("GRPC_TLS_CLIENT_CA_FILE requires GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE")
+	}
 	return nil
 }
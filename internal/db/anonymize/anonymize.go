@@ -0,0 +1,255 @@
+// Package anonymize replaces personal data in non-production databases with deterministic fakes.
+// Fakes are derived from an HMAC of the original value, so a value that appears in several
+// documents or collections (such as an employee email referenced by customers) is replaced
+// by the same fake everywhere and joins keep working
+package anonymize
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// DefaultBatchSize is the number of document updates sent per BulkWrite
+const DefaultBatchSize = 500
+
+// ErrProductionDatabase is returned when the database name matches the production pattern
+var ErrProductionDatabase = errors.New("anonymize: refusing to run against a production database")
+
+// Kind selects how a field's fake value is generated
+type Kind int
+
+const (
+	KindName  Kind = iota // Anon-<hex>
+	KindEmail             // <hex>@anonymized.invalid
+)
+
+// Field is a string field to anonymize. Path may be dotted to reach into embedded documents.
+// Shadow names the field holding the lowercase copy of the value, if any; it is rewritten
+// together with the value when the document has it
+type Field struct {
+	Collection string
+	Path       string
+	Shadow     string
+	Kind       Kind
+}
+
+// DefaultFields are the personal fields of the entity collections and of the recorded customer versions
+var DefaultFields = []Field{
+	{Collection: "customers", Path: "firstName", Shadow: "firstNameLower", Kind: KindName},
+	{Collection: "customers", Path: "lastName", Shadow: "lastNameLower", Kind: KindName},
+	{Collection: "customers", Path: "userEmail", Shadow: "userEmailLower", Kind: KindEmail},
+	{Collection: "customers", Path: "employeeEmail", Kind: KindEmail},
+	{Collection: "customers_history", Path: "preImage.firstName", Shadow: "preImage.firstNameLower", Kind: KindName},
+	{Collection: "customers_history", Path: "preImage.lastName", Shadow: "preImage.lastNameLower", Kind: KindName},
+	{Collection: "customers_history", Path: "preImage.userEmail", Shadow: "preImage.userEmailLower", Kind: KindEmail},
+	{Collection: "customers_history", Path: "preImage.employeeEmail", Kind: KindEmail},
+	{Collection: "employees", Path: "userEmail", Shadow: "userEmailLower", Kind: KindEmail},
+}
+
+// Options control an anonymization run
+type Options struct {
+	Key               []byte         // HMAC key; the same key always produces the same fakes
+	DryRun            bool           // Count the documents that would change without writing
+	BatchSize         int            // Updates per BulkWrite; zero uses DefaultBatchSize
+	ProductionPattern *regexp.Regexp // Database names the run refuses to touch
+}
+
+// Result reports the documents of one collection
+type Result struct {
+	Collection string
+	Scanned    int64
+	Changed    int64 // In dry-run mode, the documents that would change
+	DryRun     bool
+}
+
+// Anonymizer derives fake values from originals
+type Anonymizer struct {
+	key []byte
+}
+
+// NewAnonymizer creates an anonymizer using the given HMAC key
+func NewAnonymizer(key []byte) *Anonymizer {
+	return &Anonymizer{key: key}
+}
+
+// fakePattern matches values this package generated, so that re-runs leave them unchanged
+var fakePattern = regexp.MustCompile(`^(Anon-[0-9a-f]{12}|[0-9a-f]{16}@anonymized\.invalid)$`)
+
+// IsFake reports whether a value was generated by an anonymizer
+func IsFake(value string) bool {
+	return fakePattern.MatchString(value)
+}
+
+// Fake returns the fake for a value. Emails are compared case-insensitively and ignoring
+// surrounding whitespace, matching how they are looked up
+func (a *Anonymizer) Fake(kind Kind, value string) string {
+	switch kind {
+	case KindEmail:
+		return a.digest("email", strings.ToLower(strings.TrimSpace(value)), 16) + "@anonymized.invalid"
+	default:
+		return "Anon-" + a.digest("name", value, 12)
+	}
+}
+
+// digest returns the first n hex characters of the HMAC of a domain-separated value
+func (a *Anonymizer) digest(domain, value string, n int) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(domain))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:n]
+}
+
+// Run anonymizes the fields of every collection in order of first appearance.
+// Returns ErrProductionDatabase without touching anything when the database name matches the production pattern
+func Run(ctx context.Context, database db.Database, fields []Field, opts Options, logger zerolog.Logger) ([]Result, error) {
+	if database == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if opts.ProductionPattern == nil {
+		return nil, fmt.Errorf("production pattern is required")
+	}
+	if opts.ProductionPattern.MatchString(database.Name()) {
+		return nil, fmt.Errorf("%w: %q matches %q", ErrProductionDatabase, database.Name(), opts.ProductionPattern)
+	}
+	if len(opts.Key) == 0 {
+		return nil, fmt.Errorf("anonymization key is required")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	anonymizer := NewAnonymizer(opts.Key)
+	var order []string
+	byCollection := make(map[string][]Field)
+	for _, field := range fields {
+		if _, ok := byCollection[field.Collection]; !ok {
+			order = append(order, field.Collection)
+		}
+		byCollection[field.Collection] = append(byCollection[field.Collection], field)
+	}
+
+	results := make([]Result, 0, len(order))
+	for _, name := range order {
+		result, err := anonymizeCollection(ctx, database.Collection(name), byCollection[name], anonymizer, opts, logger)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return results, nil
+}
+
+// anonymizeCollection rewrites the fields of one collection in BulkWrite batches
+func anonymizeCollection(ctx context.Context, collection db.Collection, fields []Field, anonymizer *Anonymizer, opts Options, logger zerolog.Logger) (Result, error) {
+	result := Result{Collection: collection.Name(), DryRun: opts.DryRun}
+	logger = logger.With().Str("collection", collection.Name()).Logger()
+
+	projection := bson.M{}
+	anyField := make(bson.A, 0, len(fields))
+	for _, field := range fields {
+		projection[field.Path] = 1
+		if field.Shadow != "" {
+			projection[field.Shadow] = 1
+		}
+		anyField = append(anyField, bson.M{field.Path: bson.M{"$type": "string"}})
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"$or": anyField}, options.Find().SetProjection(projection))
+	if err != nil {
+		return result, err
+	}
+
+	batch := make([]mongo.WriteModel, 0, opts.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !opts.DryRun {
+			if _, err := collection.BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(false)); err != nil {
+				return err
+			}
+		}
+		logger.Info().
+			Int64("scanned", result.Scanned).
+			Int64("changed", result.Changed).
+			Bool("dry_run", opts.DryRun).
+			Msg("Anonymization progress")
+		batch = batch[:0]
+		return nil
+	}
+
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		for cursor.Next(ctx) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+			result.Scanned++
+
+			filter, set := documentUpdate(doc, fields, anonymizer)
+			if len(set) == 0 {
+				continue
+			}
+			result.Changed++
+			// Matching the original values keeps a concurrent write from being overwritten
+			batch = append(batch, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(bson.M{"$set": set}))
+			if len(batch) >= opts.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return cursor.Err()
+	})
+	if err != nil {
+		return result, err
+	}
+	return result, flush()
+}
+
+// documentUpdate returns the filter and $set document replacing the fields of one document,
+// or an empty $set when every field is absent or already anonymized
+func documentUpdate(doc bson.M, fields []Field, anonymizer *Anonymizer) (bson.M, bson.M) {
+	filter := bson.M{"_id": doc["_id"]}
+	set := bson.M{}
+	for _, field := range fields {
+		value, ok := lookup(doc, field.Path).(string)
+		if !ok || IsFake(value) {
+			continue
+		}
+		fake := anonymizer.Fake(field.Kind, value)
+		filter[field.Path] = value
+		set[field.Path] = fake
+		if field.Shadow != "" && lookup(doc, field.Shadow) != nil {
+			set[field.Shadow] = strings.ToLower(fake)
+		}
+	}
+	return filter, set
+}
+
+// lookup returns the value at a dotted path, or nil when any part is missing
+func lookup(doc bson.M, path string) interface{} {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		embedded, ok := current.(bson.M)
+		if !ok {
+			return nil
+		}
+		current = embedded[part]
+	}
+	return current
+}
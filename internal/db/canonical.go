@@ -0,0 +1,22 @@
+package db
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db/canonical"
+)
+
+// Canonical returns a copy of a filter, pipeline or document in which every map is replaced by a
+// bson.D sorted by key, at any depth. Ordered documents (bson.D, bson.Raw) keep their order and
+// arrays keep their element order, so semantically equal values built in different orders
+// serialize identically. Queries sent to the driver are left as they are; this form is for
+// hashing, logging and echoing them
+func Canonical(value interface{}) interface{} {
+	return canonical.Of(value)
+}
+
+// MarshalCanonical renders a document as relaxed Extended JSON with the keys of every nested
+// map sorted, so equal documents give equal bytes whatever order their maps were built in
+func MarshalCanonical(doc bson.M) ([]byte, error) {
+	return bson.MarshalExtJSON(Canonical(doc), false, false)
+}
@@ -0,0 +1,79 @@
+// Package canonical puts BSON values in a key-sorted form. It depends on the driver's bson
+// package only, so the logger can share it with the db package
+package canonical
+
+import (
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Of returns a copy of a filter, pipeline or document in which every map is replaced by a bson.D
+// sorted by key, at any depth. Ordered documents (bson.D, bson.Raw) keep their order and arrays
+// keep their element order, so semantically equal values built in different orders serialize
+// identically
+func Of(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case bson.M:
+		return canonicalMap(v)
+	case map[string]interface{}:
+		return canonicalMap(v)
+	case bson.D:
+		doc := make(bson.D, len(v))
+		for i, elem := range v {
+			doc[i] = bson.E{Key: elem.Key, Value: Of(elem.Value)}
+		}
+		return doc
+	case bson.E:
+		return bson.D{{Key: v.Key, Value: Of(v.Value)}}
+	case bson.A:
+		return canonicalArray(reflect.ValueOf([]interface{}(v)))
+	case bson.Raw:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			doc := make(map[string]interface{}, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				doc[iter.Key().String()] = iter.Value().Interface()
+			}
+			return canonicalMap(doc)
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return canonicalArray(rv)
+		}
+	}
+	return value
+}
+
+// canonicalMap returns the entries of a map as a document sorted by key
+func canonicalMap(doc map[string]interface{}) bson.D {
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sorted := make(bson.D, len(keys))
+	for i, key := range keys {
+		sorted[i] = bson.E{Key: key, Value: Of(doc[key])}
+	}
+	return sorted
+}
+
+// canonicalArray returns the canonical form of every element of an array, keeping their order
+func canonicalArray(array reflect.Value) bson.A {
+	elements := make(bson.A, array.Len())
+	for i := range elements {
+		elements[i] = Of(array.Index(i).Interface())
+	}
+	return elements
+}
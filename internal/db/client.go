@@ -7,10 +7,24 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
+// DBClient is the database access the resolvers, the export handler and the tools depend on.
+// It is the single definition their tests mock (see tests/testutil.MockDBClient)
+type DBClient interface {
+	HealthStatus(ctx context.Context) (*HealthStatus, error)
+	Collection(name string) Collection
+	ReadCollection(name string) Collection // Query-only access; may use a separate read connection
+	IsConnected() bool
+}
+
+// Ensure *Client implements DBClient
+var _ DBClient = (*Client)(nil)
+
 // Client encapsulates MongoDB client connection with lifecycle management
 type Client struct {
 	// MongoDB driver client
@@ -20,15 +34,41 @@ type Client struct {
 	config   *DBConfig
 	database *mongo.Database
 
-	// State tracking
-	connected atomic.Bool
-	lastPing  time.Time
-	mu        sync.RWMutex
+	// State tracking; c.mu guards the transitions and the connect fields, but is not held while
+	// connecting or disconnecting
+	state           atomic.Int32 // ConnectionState
+	lastPing        time.Time
+	mu              sync.RWMutex
+	connectCancel   context.CancelFunc // Cancels the in-flight Connect
+	connectDone     chan struct{}      // Closed when the in-flight Connect returns
+	connectCanceled bool               // A Disconnect canceled the in-flight Connect
 
 	// Health cache
 	healthCache *healthCache
 	healthMu    sync.RWMutex
 
+	// Topology event monitor
+	topology *TopologyMonitor
+
+	// Resource usage sampler, started on the first successful Connect
+	resources      *ResourceMonitor
+	resourcesStart sync.Once
+
+	// Optional separate client for read paths (nil reads through this client)
+	reader *Client
+
+	// Optional function told about reads retried on a secondary
+	readFallbackHook ReadFallbackHook
+
+	// Optional driver command monitor, registered on Connect
+	commandMonitor *event.CommandMonitor
+
+	// Refuse writes through Collection (read-only deployments)
+	readOnly bool
+
+	// Time source for health caching and retry waits
+	clock Clock
+
 	// Context for lifecycle
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -50,14 +90,18 @@ func NewClient(config *DBConfig, logger zerolog.Logger) (*Client, error) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	clock := Clock(RealClock{})
 	client := &Client{
 		config: config,
 		logger: logger,
 		ctx:    ctx,
 		cancel: cancel,
 		healthCache: &healthCache{
-			expiresAt: time.Now(),
+			expiresAt: clock.Now(),
 		},
+		topology:  NewTopologyMonitor(logger),
+		resources: NewResourceMonitor(logger, DefaultResourceThresholds),
+		clock:     clock,
 	}
 
 	return client, nil
@@ -65,7 +109,17 @@ func NewClient(config *DBConfig, logger zerolog.Logger) (*Client, error) {
 
 // IsConnected returns the current connection state (thread-safe, cached)
 func (c *Client) IsConnected() bool {
-	return c.connected.Load()
+	return c.State() == StateConnected
+}
+
+// State returns the lifecycle state of the connection
+func (c *Client) State() ConnectionState {
+	return ConnectionState(c.state.Load())
+}
+
+// ConnectionsInUse returns the connections currently checked out of this client's pool
+func (c *Client) ConnectionsInUse() int64 {
+	return c.resources.ConnectionsInUse()
 }
 
 // Database returns the database instance for admin operations (T073)
@@ -77,30 +131,123 @@ func (c *Client) Database() Database {
 	return newDatabase(c.database, c.config.OperationTimeout, c.logger)
 }
 
-// Connect establishes connection to MongoDB with automatic retry logic
+// Connect establishes connection to MongoDB with automatic retry logic. Only one Connect runs
+// at a time: a call while another is in progress returns ErrConnectInProgress, and one while a
+// Disconnect is in progress ErrDisconnectInProgress, without waiting. A Disconnect during the
+// retries cancels them, and Connect returns ErrConnectCanceled
 func (c *Client) Connect(ctx context.Context) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Check if already connected
-	if c.connected.Load() {
+	switch c.State() {
+	case StateConnected:
+		c.mu.Unlock()
 		return ErrAlreadyConnected
+	case StateConnecting:
+		c.mu.Unlock()
+		return ErrConnectInProgress
+	case StateDisconnecting:
+		c.mu.Unlock()
+		return ErrDisconnectInProgress
 	}
 
-	// Create client options with connection pool settings
+	clientOptions, err := c.clientOptions()
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	connectCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	defer close(done)
+	c.connectCancel = cancel
+	c.connectDone = done
+	c.connectCanceled = false
+	c.state.Store(int32(StateConnecting))
+	clock := c.clock
+	c.mu.Unlock()
+
+	startTime := clock.Now()
+	client, err := c.dial(connectCtx, clientOptions, clock, startTime)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connectCancel = nil
+	c.connectDone = nil
+	if c.connectCanceled {
+		if client != nil {
+			client.Disconnect(context.Background())
+		}
+		c.state.Store(int32(StateDisconnected))
+		c.logger.Info().
+			Str("event_type", "mongodb_connection_canceled").
+			Str("host", c.config.URI).
+			Str("database", c.config.Database).
+			Msg("MongoDB connect canceled by disconnect")
+		return ErrConnectCanceled
+	}
+	if err != nil {
+		c.state.Store(int32(StateDisconnected))
+		return err
+	}
+
+	// Connection successful
+	c.mongoClient = client
+	c.database = client.Database(c.config.Database)
+	c.state.Store(int32(StateConnected))
+	c.lastPing = clock.Now()
+
+	latency := clock.Now().Sub(startTime)
+
+	c.logger.Info().
+		Str("event_type", "mongodb_connection_established").
+		Str("host", c.config.URI).
+		Str("database", c.config.Database).
+		Int64("latency_ms", latency.Milliseconds()).
+		Uint64("pool_size", c.config.MaxPoolSize).
+		Msg("MongoDB connected")
+
+	c.resourcesStart.Do(func() {
+		go c.resources.Run(c.ctx, clock, ResourceSampleInterval)
+	})
+
+	return nil
+}
+
+// clientOptions returns the driver options of the connection: pool settings, monitors and
+// read preference. Called with c.mu held
+func (c *Client) clientOptions() (*options.ClientOptions, error) {
 	clientOptions := options.Client().
 		ApplyURI(c.config.URI).
 		SetMinPoolSize(c.config.MinPoolSize).
 		SetMaxPoolSize(c.config.MaxPoolSize).
 		SetMaxConnIdleTime(c.config.MaxConnIdleTime).
-		SetServerSelectionTimeout(c.config.ConnectTimeout)
+		SetServerSelectionTimeout(c.config.ConnectTimeout).
+		SetServerMonitor(c.topology.ServerMonitor()).
+		SetPoolMonitor(c.resources.PoolMonitor()).
+		SetRegistry(Registry)
+	if c.commandMonitor != nil {
+		clientOptions.SetMonitor(c.commandMonitor)
+	}
+	if c.config.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(c.config.ReadPreference)
+		if err != nil {
+			return nil, ErrInvalidConfiguration
+		}
+		readPreference, err := readpref.New(mode)
+		if err != nil {
+			return nil, ErrInvalidConfiguration
+		}
+		clientOptions.SetReadPreference(readPreference)
+	}
+	return clientOptions, nil
+}
 
+// dial connects and pings a driver client, retrying with exponential backoff until the
+// attempts are used up or ctx is done. Runs without c.mu, so Disconnect can cancel it
+func (c *Client) dial(ctx context.Context, clientOptions *options.ClientOptions, clock Clock, startTime time.Time) (*mongo.Client, error) {
 	retryState := &RetryState{
 		Attempt: 0,
 	}
 
-	startTime := time.Now()
-
 	// Retry loop with exponential backoff
 	for attempt := 1; attempt <= c.config.MaxRetryAttempts; attempt++ {
 		retryState.Attempt = attempt
@@ -116,7 +263,7 @@ func (c *Client) Connect(ctx context.Context) error {
 		client, err := mongo.Connect(ctx, clientOptions)
 		if err != nil {
 			retryState.LastError = err
-			retryState.TotalDuration = time.Since(startTime)
+			retryState.TotalDuration = clock.Now().Sub(startTime)
 
 			// If this is the last attempt or context is cancelled, fail
 			if !ShouldRetry(attempt, c.config.MaxRetryAttempts) || ctx.Err() != nil {
@@ -126,19 +273,18 @@ func (c *Client) Connect(ctx context.Context) error {
 					Str("database", c.config.Database).
 					Err(err).
 					Msg("Failed to connect to MongoDB")
-				return ErrConnectionTimeout
+				return nil, ErrConnectionTimeout
 			}
 
 			// Calculate delay and wait before retry
 			delay := CalculateDelay(attempt, c.config.RetryBaseDelay, c.config.RetryMaxDelay)
 			LogRetryAttempt(c.logger, retryState, delay)
 
-			select {
-			case <-time.After(delay):
-				continue
-			case <-ctx.Done():
-				return ctx.Err()
+			retryState.NextRetryAt = clock.Now().Add(delay)
+			if err := WaitForRetry(ctx, clock, delay); err != nil {
+				return nil, err
 			}
+			continue
 		}
 
 		// Ping to verify connection
@@ -149,7 +295,7 @@ func (c *Client) Connect(ctx context.Context) error {
 		if err != nil {
 			client.Disconnect(context.Background())
 			retryState.LastError = err
-			retryState.TotalDuration = time.Since(startTime)
+			retryState.TotalDuration = clock.Now().Sub(startTime)
 
 			if !ShouldRetry(attempt, c.config.MaxRetryAttempts) || ctx.Err() != nil {
 				c.logger.Error().
@@ -157,58 +303,59 @@ func (c *Client) Connect(ctx context.Context) error {
 					Str("host", c.config.URI).
 					Err(err).
 					Msg("Failed to ping MongoDB")
-				return ErrConnectionTimeout
+				return nil, ErrConnectionTimeout
 			}
 
 			delay := CalculateDelay(attempt, c.config.RetryBaseDelay, c.config.RetryMaxDelay)
 			LogRetryAttempt(c.logger, retryState, delay)
 
-			select {
-			case <-time.After(delay):
-				continue
-			case <-ctx.Done():
-				return ctx.Err()
+			retryState.NextRetryAt = clock.Now().Add(delay)
+			if err := WaitForRetry(ctx, clock, delay); err != nil {
+				return nil, err
 			}
+			continue
 		}
 
-		// Connection successful
-		c.mongoClient = client
-		c.database = client.Database(c.config.Database)
-		c.connected.Store(true)
-		c.lastPing = time.Now()
-
-		latency := time.Since(startTime)
-
-		c.logger.Info().
-			Str("event_type", "mongodb_connection_established").
-			Str("host", c.config.URI).
-			Str("database", c.config.Database).
-			Int64("latency_ms", latency.Milliseconds()).
-			Uint64("pool_size", c.config.MaxPoolSize).
-			Msg("MongoDB connected")
-
-		return nil
+		return client, nil
 	}
 
-	return ErrConnectionTimeout
+	return nil, ErrConnectionTimeout
 }
 
-// Disconnect gracefully closes MongoDB connection and cleanup resources
+// Disconnect gracefully closes MongoDB connection and cleanup resources. During a Connect it
+// cancels the connect attempts and waits until Connect has returned, or ctx is done. A
+// Disconnect while another is in progress returns at once
 func (c *Client) Disconnect(ctx context.Context) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if !c.connected.Load() {
-		return nil // Already disconnected, no error
+	switch c.State() {
+	case StateConnecting:
+		c.connectCanceled = true
+		cancel, done := c.connectCancel, c.connectDone
+		c.mu.Unlock()
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case StateConnected:
+	default:
+		c.mu.Unlock()
+		return nil // Already disconnected or disconnecting, no error
 	}
 
 	if c.mongoClient == nil {
+		c.mu.Unlock()
 		return nil
 	}
 
-	startTime := time.Now()
+	mongoClient := c.mongoClient
+	c.state.Store(int32(StateDisconnecting))
+	startTime := c.clock.Now()
+	c.mu.Unlock()
 
-	err := c.mongoClient.Disconnect(ctx)
+	err := mongoClient.Disconnect(ctx)
 	if err != nil {
 		c.logger.Error().
 			Str("event_type", "mongodb_disconnect_error").
@@ -217,11 +364,13 @@ func (c *Client) Disconnect(ctx context.Context) error {
 		// Don't return error, mark as disconnected anyway
 	}
 
-	c.connected.Store(false)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.Store(int32(StateDisconnected))
 	c.mongoClient = nil
 	c.database = nil
 
-	duration := time.Since(startTime)
+	duration := c.clock.Now().Sub(startTime)
 
 	c.logger.Info().
 		Str("event_type", "mongodb_disconnected").
@@ -235,7 +384,7 @@ func (c *Client) Disconnect(ctx context.Context) error {
 
 // Ping verifies MongoDB connectivity with lightweight operation
 func (c *Client) Ping(ctx context.Context) error {
-	if !c.connected.Load() {
+	if !c.IsConnected() {
 		return ErrNotConnected
 	}
 
@@ -249,7 +398,7 @@ func (c *Client) Ping(ctx context.Context) error {
 	}
 
 	c.mu.Lock()
-	c.lastPing = time.Now()
+	c.lastPing = c.clock.Now()
 	c.mu.Unlock()
 
 	return nil
@@ -259,7 +408,7 @@ func (c *Client) Ping(ctx context.Context) error {
 func (c *Client) HealthStatus(ctx context.Context) (*HealthStatus, error) {
 	// Check cache (5-second TTL)
 	c.healthMu.RLock()
-	if c.healthCache.status != nil && time.Now().Before(c.healthCache.expiresAt) {
+	if c.healthCache.status != nil && c.clock.Now().Before(c.healthCache.expiresAt) {
 		cached := c.healthCache.status
 		c.healthMu.RUnlock()
 		return cached, nil
@@ -268,17 +417,18 @@ func (c *Client) HealthStatus(ctx context.Context) (*HealthStatus, error) {
 
 	// Perform health check
 	status := &HealthStatus{
-		Timestamp: time.Now(),
+		Timestamp: c.clock.Now(),
+		State:     c.State().String(),
 	}
 
-	if !c.connected.Load() {
+	if !c.IsConnected() {
 		status.Status = "disconnected"
 		status.Message = "MongoDB not connected"
 		status.LatencyMs = 0
 	} else {
-		startTime := time.Now()
+		startTime := c.clock.Now()
 		err := c.Ping(ctx)
-		latency := time.Since(startTime)
+		latency := c.clock.Now().Sub(startTime)
 
 		if err != nil {
 			status.Status = "error"
@@ -290,12 +440,25 @@ func (c *Client) HealthStatus(ctx context.Context) (*HealthStatus, error) {
 			status.Message = "MongoDB connected"
 			status.LatencyMs = latency.Milliseconds()
 		}
+
+		topology := c.topology.Summary()
+		status.Topology = &topology
+		resources := c.resources.Summary()
+		status.Resources = &resources
+	}
+
+	// The read connection is reported separately; its own cache keeps it cheap
+	if reader := c.Reader(); reader != c {
+		readerStatus, err := reader.HealthStatus(ctx)
+		if err == nil {
+			status.Reader = readerStatus
+		}
 	}
 
 	// Update cache
 	c.healthMu.Lock()
 	c.healthCache.status = status
-	c.healthCache.expiresAt = time.Now().Add(5 * time.Second)
+	c.healthCache.expiresAt = c.clock.Now().Add(5 * time.Second)
 	c.healthMu.Unlock()
 
 	return status, nil
@@ -304,6 +467,7 @@ func (c *Client) HealthStatus(ctx context.Context) (*HealthStatus, error) {
 // Collection returns a collection accessor for database operations (T059)
 // Returns a Collection interface with timeout enforcement and structured logging
 // Returns nil if database is not initialized (call Connect() first)
+// On a read-only client the collection refuses writes with a ReadOnlyError
 func (c *Client) Collection(name string) Collection {
 	if c.database == nil {
 		c.logger.Error().
@@ -314,7 +478,55 @@ func (c *Client) Collection(name string) Collection {
 	}
 
 	mongoCollection := c.database.Collection(name)
-	return newCollection(mongoCollection, c.config.OperationTimeout, c.logger)
+	collection := newCollection(mongoCollection, c.config.OperationTimeout, c.logger)
+	if c.ReadOnly() {
+		return &readOnlyCollection{Collection: collection}
+	}
+	return collection
+}
+
+// SetClock replaces the time source used for health caching and retry waits (tests use a fake clock)
+func (c *Client) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// SetCommandMonitor registers a driver command monitor; it takes effect on the next Connect
+func (c *Client) SetCommandMonitor(monitor *event.CommandMonitor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commandMonitor = monitor
+}
+
+// SetReader routes ReadCollection to a separate client, e.g. one with a secondary read
+// preference and a larger pool. The reader is connected and disconnected by the caller
+func (c *Client) SetReader(reader *Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reader = reader
+}
+
+// Reader returns the client used for read paths: the separate reader if set, otherwise this client
+func (c *Client) Reader() *Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.reader != nil {
+		return c.reader
+	}
+	return c
+}
+
+// ReadCollection returns a collection accessor on the read client.
+// Use it for queries; writes must go through Collection so they reach the primary.
+// With ReadFallbackSecondary, its reads are retried once on a secondary when no primary is available
+func (c *Client) ReadCollection(name string) Collection {
+	reader := c.Reader()
+	collection := reader.Collection(name)
+	if collection == nil || !c.config.ReadFallbackSecondary {
+		return collection
+	}
+	return &readFallbackCollection{Collection: collection, client: reader, parent: c}
 }
 
 // Close gracefully shuts down the client and cancels the context
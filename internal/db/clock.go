@@ -0,0 +1,26 @@
+package db
+
+import "time"
+
+// Clock abstracts the current time and timers so health caching and retry waits
+// can be tested by advancing a fake clock instead of sleeping
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// After returns a channel that receives the current time once d has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock is the Clock backed by the time package
+type RealClock struct{}
+
+// Now returns time.Now()
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// After returns time.After(d)
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
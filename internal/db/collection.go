@@ -2,11 +2,17 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/logger"
 )
 
 // Collection interface defines operations on a MongoDB collection (T057)
@@ -30,6 +36,9 @@ type Collection interface {
 	// UpdateMany updates multiple documents matching the filter
 	UpdateMany(ctx context.Context, filter interface{}, update interface{}) (*mongo.UpdateResult, error)
 
+	// BulkWrite executes a batch of write operations in one command
+	BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error)
+
 	// DeleteOne deletes a single document matching the filter
 	DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error)
 
@@ -42,10 +51,23 @@ type Collection interface {
 	// Aggregate executes an aggregation pipeline
 	Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error)
 
+	// ExplainAggregate returns the explain output of an aggregation pipeline at the given verbosity
+	ExplainAggregate(ctx context.Context, pipeline interface{}, verbosity string) (bson.M, error)
+
+	// AggregateSnapshot executes an aggregation pipeline with snapshot read concern and returns
+	// the result documents together with the cluster time they were read at
+	AggregateSnapshot(ctx context.Context, pipeline interface{}, atClusterTime *primitive.Timestamp) ([]bson.Raw, primitive.Timestamp, error)
+
+	// IndexKeys returns the key document of every index on the collection
+	IndexKeys(ctx context.Context) ([]bson.D, error)
+
 	// Name returns the collection name
 	Name() string
 }
 
+// Ensure *collectionWrapper implements Collection
+var _ Collection = (*collectionWrapper)(nil)
+
 // collectionWrapper wraps mongo.Collection with timeout and logging (T058)
 type collectionWrapper struct {
 	collection       *mongo.Collection
@@ -64,6 +86,11 @@ func newCollection(coll *mongo.Collection, operationTimeout time.Duration, logge
 	}
 }
 
+// log returns the wrapper's logger with the request-scoped fields of ctx, such as the request ID
+func (c *collectionWrapper) log(ctx context.Context) *zerolog.Logger {
+	return logger.Enrich(ctx, c.logger)
+}
+
 // Name returns the collection name (T069)
 func (c *collectionWrapper) Name() string {
 	return c.name
@@ -90,10 +117,11 @@ func (c *collectionWrapper) InsertOne(ctx context.Context, document interface{})
 	result, err := c.collection.InsertOne(ctx, document)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging (FR-017)
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "insert_one").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
@@ -102,11 +130,11 @@ func (c *collectionWrapper) InsertOne(ctx context.Context, document interface{})
 		return nil, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "insert_one").
 		Str("collection", c.name).
 		Dur("duration_ms", duration).
-		Interface("inserted_id", result.InsertedID).
+		Interface("inserted_id", logger.Redacted(result.InsertedID)).
 		Msg("Document inserted")
 
 	return result, nil
@@ -122,10 +150,11 @@ func (c *collectionWrapper) InsertMany(ctx context.Context, documents []interfac
 	result, err := c.collection.InsertMany(ctx, documents)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging (FR-017)
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "insert_many").
 			Str("collection", c.name).
 			Int("document_count", len(documents)).
@@ -135,7 +164,7 @@ func (c *collectionWrapper) InsertMany(ctx context.Context, documents []interfac
 		return nil, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "insert_many").
 		Str("collection", c.name).
 		Int("inserted_count", len(result.InsertedIDs)).
@@ -147,34 +176,42 @@ func (c *collectionWrapper) InsertMany(ctx context.Context, documents []interfac
 
 // FindOne finds a single document (T062)
 func (c *collectionWrapper) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	var opts []*options.FindOneOptions
+	if comment, ok := QueryCommentFrom(ctx); ok {
+		opts = append(opts, options.FindOne().SetComment(comment.Format(c.name)))
+	}
+
 	ctx, cancel := c.withTimeout(ctx)
 	defer cancel()
 
 	startTime := time.Now()
 
-	result := c.collection.FindOne(ctx, filter)
+	result := c.collection.FindOne(ctx, filter, opts...)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Check for errors (ErrNotFound is common and not logged as error)
 	err := result.Err()
 	if err != nil && err != mongo.ErrNoDocuments {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "find_one").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
 			Err(err).
 			Msg("Find operation failed")
 	} else if err == mongo.ErrNoDocuments {
-		c.logger.Debug().
+		c.log(ctx).Debug().
 			Str("operation", "find_one").
 			Str("collection", c.name).
+			Interface("filter", logger.Redacted(filter)).
 			Dur("duration_ms", duration).
 			Msg("Document not found")
 	} else {
-		c.logger.Debug().
+		c.log(ctx).Debug().
 			Str("operation", "find_one").
 			Str("collection", c.name).
+			Interface("filter", logger.Redacted(filter)).
 			Dur("duration_ms", duration).
 			Msg("Document found")
 	}
@@ -184,6 +221,11 @@ func (c *collectionWrapper) FindOne(ctx context.Context, filter interface{}) *mo
 
 // Find finds multiple documents (T063)
 func (c *collectionWrapper) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	// The context's query comment comes first so an explicit caller comment wins
+	if comment, ok := QueryCommentFrom(ctx); ok {
+		opts = append([]*options.FindOptions{options.Find().SetComment(comment.Format(c.name))}, opts...)
+	}
+
 	ctx, cancel := c.withTimeout(ctx)
 	defer cancel()
 
@@ -192,10 +234,11 @@ func (c *collectionWrapper) Find(ctx context.Context, filter interface{}, opts .
 	cursor, err := c.collection.Find(ctx, filter, opts...)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging (FR-017)
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "find").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
@@ -204,9 +247,10 @@ func (c *collectionWrapper) Find(ctx context.Context, filter interface{}, opts .
 		return nil, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "find").
 		Str("collection", c.name).
+		Interface("filter", logger.Redacted(filter)).
 		Dur("duration_ms", duration).
 		Msg("Find operation completed")
 
@@ -223,10 +267,11 @@ func (c *collectionWrapper) UpdateOne(ctx context.Context, filter interface{}, u
 	result, err := c.collection.UpdateOne(ctx, filter, update)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging (FR-017)
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "update_one").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
@@ -235,9 +280,10 @@ func (c *collectionWrapper) UpdateOne(ctx context.Context, filter interface{}, u
 		return nil, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "update_one").
 		Str("collection", c.name).
+		Interface("filter", logger.Redacted(filter)).
 		Int64("matched_count", result.MatchedCount).
 		Int64("modified_count", result.ModifiedCount).
 		Dur("duration_ms", duration).
@@ -256,10 +302,11 @@ func (c *collectionWrapper) UpdateMany(ctx context.Context, filter interface{},
 	result, err := c.collection.UpdateMany(ctx, filter, update)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging (FR-017)
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "update_many").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
@@ -268,9 +315,10 @@ func (c *collectionWrapper) UpdateMany(ctx context.Context, filter interface{},
 		return nil, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "update_many").
 		Str("collection", c.name).
+		Interface("filter", logger.Redacted(filter)).
 		Int64("matched_count", result.MatchedCount).
 		Int64("modified_count", result.ModifiedCount).
 		Dur("duration_ms", duration).
@@ -279,6 +327,41 @@ func (c *collectionWrapper) UpdateMany(ctx context.Context, filter interface{},
 	return result, nil
 }
 
+// BulkWrite executes a batch of write operations in one command
+func (c *collectionWrapper) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	startTime := time.Now()
+
+	result, err := c.collection.BulkWrite(ctx, models, opts...)
+
+	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
+
+	// Structured logging (FR-017)
+	if err != nil {
+		c.log(ctx).Error().
+			Str("operation", "bulk_write").
+			Str("collection", c.name).
+			Int("operations", len(models)).
+			Dur("duration_ms", duration).
+			Err(err).
+			Msg("Bulk write operation failed")
+		return nil, err
+	}
+
+	c.log(ctx).Debug().
+		Str("operation", "bulk_write").
+		Str("collection", c.name).
+		Int64("matched_count", result.MatchedCount).
+		Int64("modified_count", result.ModifiedCount).
+		Dur("duration_ms", duration).
+		Msg("Bulk write completed")
+
+	return result, nil
+}
+
 // DeleteOne deletes a single document (T066)
 func (c *collectionWrapper) DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
 	ctx, cancel := c.withTimeout(ctx)
@@ -289,10 +372,11 @@ func (c *collectionWrapper) DeleteOne(ctx context.Context, filter interface{}) (
 	result, err := c.collection.DeleteOne(ctx, filter)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging (FR-017)
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "delete_one").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
@@ -301,9 +385,10 @@ func (c *collectionWrapper) DeleteOne(ctx context.Context, filter interface{}) (
 		return nil, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "delete_one").
 		Str("collection", c.name).
+		Interface("filter", logger.Redacted(filter)).
 		Int64("deleted_count", result.DeletedCount).
 		Dur("duration_ms", duration).
 		Msg("Document deleted")
@@ -321,10 +406,11 @@ func (c *collectionWrapper) DeleteMany(ctx context.Context, filter interface{})
 	result, err := c.collection.DeleteMany(ctx, filter)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging (FR-017)
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "delete_many").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
@@ -333,9 +419,10 @@ func (c *collectionWrapper) DeleteMany(ctx context.Context, filter interface{})
 		return nil, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "delete_many").
 		Str("collection", c.name).
+		Interface("filter", logger.Redacted(filter)).
 		Int64("deleted_count", result.DeletedCount).
 		Dur("duration_ms", duration).
 		Msg("Documents deleted")
@@ -353,10 +440,11 @@ func (c *collectionWrapper) CountDocuments(ctx context.Context, filter interface
 	count, err := c.collection.CountDocuments(ctx, filter)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging (FR-017)
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "count_documents").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
@@ -365,9 +453,10 @@ func (c *collectionWrapper) CountDocuments(ctx context.Context, filter interface
 		return 0, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "count_documents").
 		Str("collection", c.name).
+		Interface("filter", logger.Redacted(filter)).
 		Int64("count", count).
 		Dur("duration_ms", duration).
 		Msg("Documents counted")
@@ -377,6 +466,11 @@ func (c *collectionWrapper) CountDocuments(ctx context.Context, filter interface
 
 // Aggregate executes an aggregation pipeline
 func (c *collectionWrapper) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	// The context's query comment comes first so an explicit caller comment wins
+	if comment, ok := QueryCommentFrom(ctx); ok {
+		opts = append([]*options.AggregateOptions{options.Aggregate().SetComment(comment.Format(c.name))}, opts...)
+	}
+
 	ctx, cancel := c.withTimeout(ctx)
 	defer cancel()
 
@@ -385,10 +479,11 @@ func (c *collectionWrapper) Aggregate(ctx context.Context, pipeline interface{},
 	cursor, err := c.collection.Aggregate(ctx, pipeline, opts...)
 
 	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
 
 	// Structured logging
 	if err != nil {
-		c.logger.Error().
+		c.log(ctx).Error().
 			Str("operation", "aggregate").
 			Str("collection", c.name).
 			Dur("duration_ms", duration).
@@ -397,7 +492,7 @@ func (c *collectionWrapper) Aggregate(ctx context.Context, pipeline interface{},
 		return nil, err
 	}
 
-	c.logger.Debug().
+	c.log(ctx).Debug().
 		Str("operation", "aggregate").
 		Str("collection", c.name).
 		Dur("duration_ms", duration).
@@ -405,3 +500,212 @@ func (c *collectionWrapper) Aggregate(ctx context.Context, pipeline interface{},
 
 	return cursor, nil
 }
+
+// ExplainAggregate runs the explain command for an aggregation pipeline
+// Verbosity is one of "queryPlanner", "executionStats" or "allPlansExecution"
+func (c *collectionWrapper) ExplainAggregate(ctx context.Context, pipeline interface{}, verbosity string) (bson.M, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	startTime := time.Now()
+
+	command := bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "aggregate", Value: c.name},
+			{Key: "pipeline", Value: pipeline},
+			{Key: "cursor", Value: bson.D{}},
+		}},
+		{Key: "verbosity", Value: verbosity},
+	}
+
+	var result bson.M
+	err := c.collection.Database().RunCommand(ctx, command).Decode(&result)
+
+	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
+
+	// Structured logging
+	if err != nil {
+		c.log(ctx).Error().
+			Str("operation", "explain_aggregate").
+			Str("collection", c.name).
+			Dur("duration_ms", duration).
+			Err(err).
+			Msg("Explain operation failed")
+		return nil, err
+	}
+
+	c.log(ctx).Debug().
+		Str("operation", "explain_aggregate").
+		Str("collection", c.name).
+		Str("verbosity", verbosity).
+		Dur("duration_ms", duration).
+		Msg("Explain operation completed")
+
+	return result, nil
+}
+
+// snapshotCursorReply is the reply of an aggregate or getMore command
+type snapshotCursorReply struct {
+	Cursor struct {
+		ID            int64               `bson:"id"`
+		FirstBatch    []bson.Raw          `bson:"firstBatch"`
+		NextBatch     []bson.Raw          `bson:"nextBatch"`
+		AtClusterTime primitive.Timestamp `bson:"atClusterTime"`
+	} `bson:"cursor"`
+}
+
+// AggregateSnapshot runs an aggregation pipeline with readConcern "snapshot".
+// A nil atClusterTime reads the latest majority-committed snapshot; passing a time returned
+// earlier reads the collection as it was then, so consecutive calls see the same data.
+// Returns ErrSnapshotUnsupported on standalone servers and ErrSnapshotExpired once the
+// time has left the server's snapshot history window
+func (c *collectionWrapper) AggregateSnapshot(ctx context.Context, pipeline interface{}, atClusterTime *primitive.Timestamp) ([]bson.Raw, primitive.Timestamp, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	startTime := time.Now()
+
+	docs, readAt, err := c.aggregateSnapshot(ctx, pipeline, atClusterTime)
+
+	duration := time.Since(startTime)
+	recordOperation(ctx, duration)
+
+	// Structured logging
+	if err != nil {
+		c.log(ctx).Error().
+			Str("operation", "aggregate_snapshot").
+			Str("collection", c.name).
+			Dur("duration_ms", duration).
+			Err(err).
+			Msg("Snapshot aggregate operation failed")
+		return nil, primitive.Timestamp{}, err
+	}
+
+	c.log(ctx).Debug().
+		Str("operation", "aggregate_snapshot").
+		Str("collection", c.name).
+		Uint32("cluster_time", readAt.T).
+		Dur("duration_ms", duration).
+		Msg("Snapshot aggregate operation completed")
+
+	return docs, readAt, nil
+}
+
+// aggregateSnapshot issues the aggregate command and drains its cursor.
+// The driver only exposes snapshot times through sessions, so the command is built by hand.
+// The server only accepts a getMore in the session that opened the cursor, so the aggregate and
+// its getMores share one explicit session; RunCommand would give each its own implicit one
+func (c *collectionWrapper) aggregateSnapshot(ctx context.Context, pipeline interface{}, atClusterTime *primitive.Timestamp) ([]bson.Raw, primitive.Timestamp, error) {
+	database := c.collection.Database()
+
+	// Standalone servers reject snapshot reads; report that plainly
+	replicated, err := isReplicated(ctx, database)
+	if err != nil {
+		return nil, primitive.Timestamp{}, err
+	}
+	if !replicated {
+		return nil, primitive.Timestamp{}, ErrSnapshotUnsupported
+	}
+
+	readConcern := bson.D{{Key: "level", Value: "snapshot"}}
+	if atClusterTime != nil {
+		readConcern = append(readConcern, bson.E{Key: "atClusterTime", Value: *atClusterTime})
+	}
+
+	command := bson.D{
+		{Key: "aggregate", Value: c.name},
+		{Key: "pipeline", Value: pipeline},
+		{Key: "cursor", Value: bson.D{}},
+		{Key: "readConcern", Value: readConcern},
+	}
+	if comment, ok := QueryCommentFrom(ctx); ok {
+		command = append(command, bson.E{Key: "comment", Value: comment.Format(c.name)})
+	}
+
+	// Without causal consistency the driver adds no afterClusterTime, which getMore rejects
+	session, err := database.Client().StartSession(options.Session().SetCausalConsistency(false))
+	if err != nil {
+		return nil, primitive.Timestamp{}, err
+	}
+	defer session.EndSession(ctx)
+	ctx = mongo.NewSessionContext(ctx, session)
+
+	var reply snapshotCursorReply
+	if err := database.RunCommand(ctx, command).Decode(&reply); err != nil {
+		return nil, primitive.Timestamp{}, snapshotError(err)
+	}
+
+	docs := reply.Cursor.FirstBatch
+	readAt := reply.Cursor.AtClusterTime
+	if atClusterTime != nil {
+		readAt = *atClusterTime
+	}
+
+	for cursorID := reply.Cursor.ID; cursorID != 0; {
+		var more snapshotCursorReply
+		getMore := bson.D{
+			{Key: "getMore", Value: cursorID},
+			{Key: "collection", Value: c.name},
+		}
+		if err := database.RunCommand(ctx, getMore).Decode(&more); err != nil {
+			return nil, primitive.Timestamp{}, snapshotError(err)
+		}
+		docs = append(docs, more.Cursor.NextBatch...)
+		cursorID = more.Cursor.ID
+	}
+
+	return docs, readAt, nil
+}
+
+// snapshotError maps the server's SnapshotTooOld and SnapshotUnavailable errors to ErrSnapshotExpired
+func snapshotError(err error) error {
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && (commandErr.Code == 239 || commandErr.Code == 246) {
+		return fmt.Errorf("%w: %v", ErrSnapshotExpired, err)
+	}
+	return err
+}
+
+// IndexKeys lists the collection's indexes and returns their key documents
+func (c *collectionWrapper) IndexKeys(ctx context.Context) ([]bson.D, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	startTime := time.Now()
+
+	cursor, err := c.collection.Indexes().List(ctx)
+	if err != nil {
+		c.log(ctx).Error().
+			Str("operation", "list_indexes").
+			Str("collection", c.name).
+			Dur("duration_ms", time.Since(startTime)).
+			Err(err).
+			Msg("List indexes operation failed")
+		return nil, err
+	}
+
+	var indexes []struct {
+		Key bson.D `bson:"key"`
+	}
+	err = WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		return cursor.All(ctx, &indexes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]bson.D, 0, len(indexes))
+	for _, index := range indexes {
+		keys = append(keys, index.Key)
+	}
+
+	c.log(ctx).Debug().
+		Str("operation", "list_indexes").
+		Str("collection", c.name).
+		Int("indexes", len(keys)).
+		Dur("duration_ms", time.Since(startTime)).
+		Msg("List indexes operation completed")
+
+	return keys, nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	// maxQueryCommentPart caps each value of a query comment
+	maxQueryCommentPart = 64
+
+	// maxQueryCommentLength caps the whole query comment
+	maxQueryCommentLength = 256
+)
+
+// queryCommentKey is the context key of the query comment
+type queryCommentKey struct{}
+
+// QueryComment identifies the GraphQL operation behind the queries of a context.
+// Find and Aggregate send it as the command comment, so profiler and currentOp entries
+// can be correlated with the operation that issued them
+type QueryComment struct {
+	Operation string // GraphQL operation name; empty for anonymous operations
+	RequestID string // X-Request-ID of the HTTP request (suffixed per batched operation)
+}
+
+// WithQueryComment returns a context whose Find and Aggregate calls carry the comment
+func WithQueryComment(ctx context.Context, comment QueryComment) context.Context {
+	return context.WithValue(ctx, queryCommentKey{}, comment)
+}
+
+// QueryCommentFrom returns the query comment of a context, if any
+func QueryCommentFrom(ctx context.Context) (QueryComment, bool) {
+	comment, ok := ctx.Value(queryCommentKey{}).(QueryComment)
+	return comment, ok
+}
+
+// Format renders the comment for a query on the given collection, which stands for the entity.
+// Values are sanitized so client-chosen operation names cannot inject arbitrary text
+func (q QueryComment) Format(collection string) string {
+	operation := q.Operation
+	if operation == "" {
+		operation = "anonymous"
+	}
+
+	comment := "graphql op=" + sanitizeCommentPart(operation) +
+		" entity=" + sanitizeCommentPart(collection)
+	if q.RequestID != "" {
+		comment += " request=" + sanitizeCommentPart(q.RequestID)
+	}
+
+	if len(comment) > maxQueryCommentLength {
+		comment = comment[:maxQueryCommentLength]
+	}
+	return comment
+}
+
+// sanitizeCommentPart keeps letters, digits and _.:- and replaces anything else with _
+func sanitizeCommentPart(value string) string {
+	if len(value) > maxQueryCommentPart {
+		value = value[:maxQueryCommentPart]
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '_' || r == '.' || r == ':' || r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, value)
+}
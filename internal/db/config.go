@@ -8,13 +8,18 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // DBConfig holds MongoDB database configuration
 type DBConfig struct {
 	// Connection
-	URI      string // MongoDB connection string
-	Database string // Target database name
+	URI            string // MongoDB connection string
+	Database       string // Target database name
+	ReadPreference string // Optional read preference mode (e.g. secondaryPreferred); empty keeps the URI/driver default
+
+	// Retry ReadCollection reads that fail for lack of a primary once with readPreference secondaryPreferred
+	ReadFallbackSecondary bool
 
 	// Timeouts (from FR-007)
 	ConnectTimeout   time.Duration // Initial connection establishment (30s per spec)
@@ -45,6 +50,12 @@ func (c *DBConfig) Validate() error {
 		return fmt.Errorf("invalid database name: %w", err)
 	}
 
+	if c.ReadPreference != "" {
+		if _, err := readpref.ModeFromString(c.ReadPreference); err != nil {
+			return fmt.Errorf("invalid read preference: %w", err)
+		}
+	}
+
 	if err := validateTimeouts(c); err != nil {
 		return err
 	}
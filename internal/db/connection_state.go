@@ -0,0 +1,27 @@
+package db
+
+// ConnectionState is the lifecycle state of a Client's connection. Connect moves a client from
+// Disconnected through Connecting to Connected, and back to Disconnected when it fails or a
+// Disconnect cancels it; Disconnect moves it from Connected through Disconnecting to Disconnected
+type ConnectionState int32
+
+const (
+	StateDisconnected ConnectionState = iota
+	StateConnecting
+	StateConnected
+	StateDisconnecting
+)
+
+// String returns the state as reported by HealthStatus
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateDisconnecting:
+		return "disconnecting"
+	default:
+		return "disconnected"
+	}
+}
@@ -0,0 +1,22 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithCursor runs fn on a cursor and closes the cursor afterwards, whether fn returns an error
+// or panics. The cursor is counted as open until then (see OpenCursors). An error from Close is
+// joined to fn's error, so a cursor that could not be released is never silently dropped.
+// Use it for every cursor: a cursor left open pins its connection until the server times it out
+func WithCursor(ctx context.Context, cursor *mongo.Cursor, fn func(cursor *mongo.Cursor) error) (err error) {
+	tracked := TrackCursor(cursor)
+	defer func() {
+		if closeErr := tracked.Close(ctx); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+	return fn(cursor)
+}
@@ -16,7 +16,20 @@ var (
 	ErrDuplicateKey     = errors.New("db: duplicate key error")
 	ErrInvalidDocument  = errors.New("db: invalid document")
 
+	// Snapshot Errors
+	ErrSnapshotUnsupported = errors.New("db: snapshot reads require a replica set or sharded cluster")
+	ErrSnapshotExpired     = errors.New("db: snapshot is no longer available")
+
+	// Transaction Errors
+	ErrTransactionsUnsupported = errors.New("db: transactions require a replica set or sharded cluster")
+
+	// Read-only Errors
+	ErrReadOnly = errors.New("db: client is read-only")
+
 	// State Errors
-	ErrAlreadyConnected    = errors.New("db: already connected")
-	ErrDatabaseUnavailable = errors.New("db: database unavailable")
+	ErrAlreadyConnected     = errors.New("db: already connected")
+	ErrConnectInProgress    = errors.New("db: connect already in progress")
+	ErrDisconnectInProgress = errors.New("db: disconnect in progress")
+	ErrConnectCanceled      = errors.New("db: connect canceled by disconnect")
+	ErrDatabaseUnavailable  = errors.New("db: database unavailable")
 )
@@ -5,10 +5,15 @@ import "time"
 // HealthStatus represents database health check result
 type HealthStatus struct {
 	Status    string    `json:"status"`          // "connected", "disconnected", "error"
+	State     string    `json:"state"`           // Connection lifecycle state, see ConnectionState
 	Message   string    `json:"message"`         // Human-readable message
 	LatencyMs int64     `json:"latency_ms"`      // Ping latency in milliseconds
 	Timestamp time.Time `json:"timestamp"`       // Check timestamp
 	Error     string    `json:"error,omitempty"` // Error details if unhealthy
+
+	Topology  *TopologySummary `json:"topology,omitempty"`  // Deployment topology, nil when disconnected
+	Resources *ResourceSummary `json:"resources,omitempty"` // Recent resource samples, nil when disconnected
+	Reader    *HealthStatus    `json:"reader,omitempty"`    // Separate read connection, nil when reads share this one
 }
 
 // healthCache stores the last health check result with TTL
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexSpec describes an index the service relies on
+type IndexSpec struct {
+	Collection  string
+	Name        string
+	Keys        bson.D
+	ExpireAfter time.Duration      // TTL of the documents; zero for a regular index
+	Sparse      bool               // Leaves out documents without the indexed fields
+	Collation   *options.Collation // Collation of the index; queries must use the same one to use it
+}
+
+// EnsureIndexes creates the indexes that do not exist yet. A TTL index whose expiry differs
+// from its spec is updated in place, so retention changes apply without rebuilding the index.
+// Indexes are matched by name; other indexes on the collections are left alone
+func (c *Client) EnsureIndexes(ctx context.Context, specs []IndexSpec) error {
+	c.mu.RLock()
+	database := c.database
+	c.mu.RUnlock()
+	if database == nil {
+		return ErrNotConnected
+	}
+
+	for _, spec := range specs {
+		if err := c.ensureIndex(ctx, database, spec); err != nil {
+			return fmt.Errorf("index %s on %s: %w", spec.Name, spec.Collection, err)
+		}
+	}
+	return nil
+}
+
+// ensureIndex creates or updates a single index
+func (c *Client) ensureIndex(ctx context.Context, database *mongo.Database, spec IndexSpec) error {
+	indexes := database.Collection(spec.Collection).Indexes()
+
+	existing, err := indexes.ListSpecifications(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range existing {
+		if index.Name != spec.Name {
+			continue
+		}
+
+		expireAfter := int32(spec.ExpireAfter / time.Second)
+		if spec.ExpireAfter == 0 || (index.ExpireAfterSeconds != nil && *index.ExpireAfterSeconds == expireAfter) {
+			return nil
+		}
+
+		command := bson.D{
+			{Key: "collMod", Value: spec.Collection},
+			{Key: "index", Value: bson.D{
+				{Key: "name", Value: spec.Name},
+				{Key: "expireAfterSeconds", Value: expireAfter},
+			}},
+		}
+		if err := database.RunCommand(ctx, command).Err(); err != nil {
+			return err
+		}
+
+		c.logger.Info().
+			Str("collection", spec.Collection).
+			Str("index", spec.Name).
+			Dur("expire_after", spec.ExpireAfter).
+			Msg("Index expiry updated")
+		return nil
+	}
+
+	opts := options.Index().SetName(spec.Name)
+	if spec.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int32(spec.ExpireAfter / time.Second))
+	}
+	if spec.Sparse {
+		opts.SetSparse(true)
+	}
+	if spec.Collation != nil {
+		opts.SetCollation(spec.Collation)
+	}
+	if _, err := indexes.CreateOne(ctx, mongo.IndexModel{Keys: spec.Keys, Options: opts}); err != nil {
+		return err
+	}
+
+	c.logger.Info().
+		Str("collection", spec.Collection).
+		Str("index", spec.Name).
+		Msg("Index created")
+	return nil
+}
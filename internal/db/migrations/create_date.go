@@ -0,0 +1,98 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// createDateCollections are the entity collections whose createDate is normalized
+var createDateCollections = []string{
+	"customers",
+	"employees",
+	"teams",
+	"inventories",
+	"executionPlans",
+	"referencePortfolios",
+}
+
+// normalizeCreateDate converts createDate strings to BSON dates so date filters and sorting
+// compare real dates. Values that are not RFC3339 timestamps are left unchanged
+var normalizeCreateDate = Migration{
+	Version: 1,
+	Name:    "normalize-create-date",
+	Run: func(ctx context.Context, database db.Database, progress *Progress) error {
+		for _, name := range createDateCollections {
+			if err := normalizeCreateDateIn(ctx, database.Collection(name), progress); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		return nil
+	},
+}
+
+// normalizeCreateDateIn converts the string createDate values of one collection
+func normalizeCreateDateIn(ctx context.Context, collection db.Collection, progress *Progress) error {
+	cursor, err := collection.Find(ctx,
+		bson.M{"createDate": bson.M{"$type": "string"}},
+		options.Find().SetProjection(bson.M{"createDate": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	return db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		for cursor.Next(ctx) {
+			var doc struct {
+				ID         interface{} `bson:"_id"`
+				CreateDate string      `bson:"createDate"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+			progress.Scan()
+
+			createDate, ok := ParseCreateDate(doc.CreateDate)
+			if !ok {
+				progress.logger.Warn().
+					Str("collection", collection.Name()).
+					Interface("id", doc.ID).
+					Str("create_date", doc.CreateDate).
+					Msg("Skipping createDate that is not an RFC3339 timestamp")
+				continue
+			}
+
+			if progress.DryRun {
+				progress.Update()
+				continue
+			}
+
+			// Matching the original value keeps a concurrent write from being overwritten
+			result, err := collection.UpdateOne(ctx,
+				bson.M{"_id": doc.ID, "createDate": doc.CreateDate},
+				bson.M{"$set": bson.M{"createDate": createDate}},
+			)
+			if err != nil {
+				return err
+			}
+			if result.ModifiedCount > 0 {
+				progress.Update()
+			}
+		}
+		return cursor.Err()
+	})
+}
+
+// ParseCreateDate parses an RFC3339 createDate string, with or without fractional seconds, as UTC
+func ParseCreateDate(value string) (time.Time, bool) {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed.UTC(), true
+}
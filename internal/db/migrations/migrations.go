@@ -0,0 +1,319 @@
+// Package migrations runs versioned one-time data migrations at startup.
+// Applied versions are recorded in the _migrations collection, and a lease document
+// in the same collection makes sure only one replica runs them at a time. Other jobs
+// that must run on a single replica take a Lease of their own in that collection
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+const (
+	// CollectionName stores one record per applied migration plus the lease document
+	CollectionName = "_migrations"
+
+	// leaseID is the _id of the lease document
+	leaseID = "lease"
+
+	// progressInterval is the number of scanned documents between progress log lines
+	progressInterval = 1000
+)
+
+// ErrLocked is returned when another replica holds an unexpired lease
+var ErrLocked = errors.New("migrations: lease held by another instance")
+
+// DefaultOwner returns the lease owner of this process: its hostname and process ID
+func DefaultOwner() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// Migration is a versioned data migration. Run must be idempotent: it may be interrupted
+// and run again, and in dry-run mode it must count the documents it would update without writing
+type Migration struct {
+	Version int
+	Name    string
+	Timeout time.Duration // Zero uses Options.Timeout
+	Run     func(ctx context.Context, database db.Database, progress *Progress) error
+}
+
+// Options control a migration run
+type Options struct {
+	DryRun   bool          // Count changes without writing or recording migrations
+	LeaseTTL time.Duration // Lease lifetime; renewed while migrations run
+	Timeout  time.Duration // Default time limit per migration
+	Owner    string        // Lease owner; defaults to hostname and process ID
+}
+
+// Result reports the outcome of one migration
+type Result struct {
+	Version  int
+	Name     string
+	Scanned  int64
+	Updated  int64
+	Duration time.Duration
+	DryRun   bool
+}
+
+// All returns every migration in version order
+func All() []Migration {
+	return []Migration{
+		normalizeCreateDate,
+	}
+}
+
+// record is the document stored for an applied migration
+type record struct {
+	Version    int       `bson:"_id"`
+	Name       string    `bson:"name"`
+	AppliedAt  time.Time `bson:"appliedAt"`
+	Scanned    int64     `bson:"scanned"`
+	Updated    int64     `bson:"updated"`
+	DurationMs int64     `bson:"durationMs"`
+}
+
+// Progress counts the documents a migration scanned and updated and logs them periodically
+type Progress struct {
+	DryRun  bool
+	Scanned int64
+	Updated int64
+	logger  zerolog.Logger
+}
+
+// Scan counts one scanned document
+func (p *Progress) Scan() {
+	p.Scanned++
+	if p.Scanned%progressInterval == 0 {
+		p.logger.Info().
+			Int64("scanned", p.Scanned).
+			Int64("updated", p.Updated).
+			Msg("Migration progress")
+	}
+}
+
+// Update counts one updated (or, in dry-run mode, updatable) document
+func (p *Progress) Update() {
+	p.Updated++
+}
+
+// Run applies every migration not yet recorded, in version order, while holding the lease.
+// Returns ErrLocked when another instance is running migrations
+func Run(ctx context.Context, database db.Database, migrations []Migration, opts Options, logger zerolog.Logger) ([]Result, error) {
+	if database == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if opts.LeaseTTL <= 0 {
+		return nil, fmt.Errorf("lease TTL must be positive")
+	}
+	if opts.Owner == "" {
+		opts.Owner = DefaultOwner()
+	}
+
+	pending, err := pendingMigrations(ctx, database.Collection(CollectionName), migrations)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		logger.Info().Msg("No pending migrations")
+		return nil, nil
+	}
+
+	lease := NewLease(database.Collection(CollectionName), leaseID, opts.Owner, opts.LeaseTTL)
+	if err := lease.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer lease.Release(context.WithoutCancel(ctx))
+
+	// Renew the lease until the run ends so long migrations keep it
+	renewCtx, stopRenewal := context.WithCancel(ctx)
+	defer stopRenewal()
+	go lease.KeepAlive(renewCtx, logger)
+
+	// Another instance may have finished the migrations while we waited for the lease
+	pending, err = pendingMigrations(ctx, database.Collection(CollectionName), migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(pending))
+	for _, migration := range pending {
+		result, err := runMigration(ctx, database, migration, opts, logger)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// runMigration runs one migration under its timeout and records it unless in dry-run mode
+func runMigration(ctx context.Context, database db.Database, migration Migration, opts Options, logger zerolog.Logger) (Result, error) {
+	timeout := migration.Timeout
+	if timeout <= 0 {
+		timeout = opts.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	migrationLogger := logger.With().
+		Int("version", migration.Version).
+		Str("migration", migration.Name).
+		Bool("dry_run", opts.DryRun).
+		Logger()
+	migrationLogger.Info().Msg("Migration started")
+
+	progress := &Progress{DryRun: opts.DryRun, logger: migrationLogger}
+	startTime := time.Now()
+	err := migration.Run(ctx, database, progress)
+	duration := time.Since(startTime)
+
+	result := Result{
+		Version:  migration.Version,
+		Name:     migration.Name,
+		Scanned:  progress.Scanned,
+		Updated:  progress.Updated,
+		Duration: duration,
+		DryRun:   opts.DryRun,
+	}
+
+	if err != nil {
+		migrationLogger.Error().
+			Err(err).
+			Int64("scanned", result.Scanned).
+			Int64("updated", result.Updated).
+			Dur("duration_ms", duration).
+			Msg("Migration failed")
+		return result, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+	}
+
+	if !opts.DryRun {
+		_, err = database.Collection(CollectionName).InsertOne(ctx, record{
+			Version:    migration.Version,
+			Name:       migration.Name,
+			AppliedAt:  time.Now().UTC(),
+			Scanned:    result.Scanned,
+			Updated:    result.Updated,
+			DurationMs: duration.Milliseconds(),
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to record migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	migrationLogger.Info().
+		Int64("scanned", result.Scanned).
+		Int64("updated", result.Updated).
+		Dur("duration_ms", duration).
+		Msg("Migration completed")
+	return result, nil
+}
+
+// pendingMigrations returns the migrations without a record, sorted by version
+func pendingMigrations(ctx context.Context, collection db.Collection, migrations []Migration) ([]Migration, error) {
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$type": "number"}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	var records []record
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		return cursor.All(ctx, &records)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, r := range records {
+		applied[r.Version] = true
+	}
+
+	pending := []Migration{}
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Version < pending[j].Version })
+	return pending, nil
+}
+
+// Lease is a lock document that keeps other instances from running the job it is named after
+type Lease struct {
+	collection db.Collection
+	id         string
+	owner      string
+	ttl        time.Duration
+}
+
+// NewLease returns the lease with the given _id in collection, held by owner for ttl at a time
+func NewLease(collection db.Collection, id, owner string, ttl time.Duration) *Lease {
+	return &Lease{collection: collection, id: id, owner: owner, ttl: ttl}
+}
+
+// Acquire takes the lease when it is missing, expired or already ours.
+// Returns ErrLocked when another owner holds it
+func (l *Lease) Acquire(ctx context.Context) error {
+	now := time.Now().UTC()
+	result, err := l.collection.UpdateOne(ctx,
+		bson.M{"_id": l.id, "$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$lte": now}},
+			bson.M{"owner": l.owner},
+		}},
+		bson.M{"$set": bson.M{"owner": l.owner, "expiresAt": now.Add(l.ttl)}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to acquire %s lease: %w", l.id, err)
+	}
+	if result.MatchedCount == 1 {
+		return nil
+	}
+
+	_, err = l.collection.InsertOne(ctx, bson.M{"_id": l.id, "owner": l.owner, "expiresAt": now.Add(l.ttl)})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLocked
+	}
+	if err != nil {
+		return fmt.Errorf("failed to acquire %s lease: %w", l.id, err)
+	}
+	return nil
+}
+
+// KeepAlive extends the lease every third of its TTL until the context ends
+func (l *Lease) KeepAlive(ctx context.Context, logger zerolog.Logger) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := l.collection.UpdateOne(ctx,
+				bson.M{"_id": l.id, "owner": l.owner},
+				bson.M{"$set": bson.M{"expiresAt": time.Now().UTC().Add(l.ttl)}},
+			)
+			if err != nil && ctx.Err() == nil {
+				logger.Warn().Err(err).Str("lease", l.id).Msg("Failed to renew lease")
+			}
+		}
+	}
+}
+
+// Release deletes the lease if it is still ours
+func (l *Lease) Release(ctx context.Context) {
+	_, _ = l.collection.DeleteOne(ctx, bson.M{"_id": l.id, "owner": l.owner})
+}
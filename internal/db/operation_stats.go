@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// OperationStats counts the database operations run for one request and their total duration.
+// The collections add to the stats carried by the context of each operation, so resolvers
+// running concurrently for the same request share them
+type OperationStats struct {
+	count atomic.Int64
+	nanos atomic.Int64
+}
+
+// operationStatsKey is the context key of the operation stats
+type operationStatsKey struct{}
+
+// WithOperationStats returns a context whose database operations are counted in the returned stats
+func WithOperationStats(ctx context.Context) (context.Context, *OperationStats) {
+	stats := &OperationStats{}
+	return context.WithValue(ctx, operationStatsKey{}, stats), stats
+}
+
+// Count returns the number of operations recorded so far
+func (s *OperationStats) Count() int64 {
+	return s.count.Load()
+}
+
+// Duration returns the total duration of the operations recorded so far
+func (s *OperationStats) Duration() time.Duration {
+	return time.Duration(s.nanos.Load())
+}
+
+// recordOperation adds an operation to the stats of ctx, if it carries any
+func recordOperation(ctx context.Context, duration time.Duration) {
+	if stats, ok := ctx.Value(operationStatsKey{}).(*OperationStats); ok {
+		stats.count.Add(1)
+		stats.nanos.Add(int64(duration))
+	}
+}
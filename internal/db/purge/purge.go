@@ -0,0 +1,297 @@
+// Package purge hard-deletes soft-deleted entities once their retention period has passed.
+// Every purged entity leaves an audit record in the _purges collection, and a lease in the
+// migrations collection makes sure only one replica purges at a time
+package purge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/db/migrations"
+)
+
+const (
+	// AuditCollection stores one record per purged entity
+	AuditCollection = "_purges"
+
+	// DeleteDateField holds the BSON date an entity was soft-deleted at
+	DeleteDateField = "deleteDate"
+
+	// DefaultBatchSize is the number of documents deleted per BulkWrite
+	DefaultBatchSize = 500
+
+	// leaseID is the _id of the purge lease in the migrations collection
+	leaseID = "purge"
+)
+
+// Target is an entity collection whose soft-deleted documents are purged after Retention
+type Target struct {
+	Entity        string
+	Collection    string
+	DeletionField string // Field marking deleted documents (e.g. "status.deletion")
+	DeletionValue string // Value of DeletionField in deleted documents
+	Retention     time.Duration
+}
+
+// Options control a purge run
+type Options struct {
+	DryRun     bool          // Count the documents that would be purged without writing
+	BatchSize  int           // Deletes per BulkWrite; zero uses DefaultBatchSize
+	BatchPause time.Duration // Pause between batches, limiting the delete rate
+	LeaseTTL   time.Duration // Lease lifetime; renewed while the run lasts
+	Owner      string        // Lease owner; defaults to hostname and process ID
+	Clock      db.Clock      // Nil uses the real clock
+}
+
+// Result reports the purged documents of one target
+type Result struct {
+	Entity     string
+	Collection string
+	Purged     int64 // In dry-run mode, the documents that would be purged
+	DryRun     bool
+}
+
+// auditRecord is the document stored for a purged entity; it keeps no personal data
+type auditRecord struct {
+	Entity     string      `bson:"entity"`
+	Collection string      `bson:"collection"`
+	DocumentID interface{} `bson:"documentId"`
+	Identifier string      `bson:"identifier"`
+	DeleteDate time.Time   `bson:"deleteDate"`
+	PurgedAt   time.Time   `bson:"purgedAt"`
+	PurgedBy   string      `bson:"purgedBy"`
+}
+
+// Stats counts the documents purged by this process, per entity
+type Stats struct {
+	Entity       string
+	Purged       int64
+	DryRunPurged int64 // Documents dry runs would have purged
+}
+
+var (
+	statsMu    sync.Mutex
+	stats      = map[string]*Stats{}
+	statsOrder []string // Entities of stats in the order they were first counted
+)
+
+// CurrentStats returns the purge counters of every entity purged by this process, in first-purged order
+func CurrentStats() []Stats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	result := make([]Stats, 0, len(stats))
+	for _, entity := range statsOrder {
+		result = append(result, *stats[entity])
+	}
+	return result
+}
+
+// count adds purged documents of an entity to the counters
+func count(entity string, purged int64, dryRun bool) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	entry, ok := stats[entity]
+	if !ok {
+		entry = &Stats{Entity: entity}
+		stats[entity] = entry
+		statsOrder = append(statsOrder, entity)
+	}
+	if dryRun {
+		entry.DryRunPurged += purged
+	} else {
+		entry.Purged += purged
+	}
+}
+
+// Indexes returns the deleteDate indexes the purge queries of the targets use
+func Indexes(targets []Target) []db.IndexSpec {
+	specs := make([]db.IndexSpec, 0, len(targets))
+	for _, target := range targets {
+		specs = append(specs, db.IndexSpec{
+			Collection: target.Collection,
+			Name:       DeleteDateField,
+			Keys:       bson.D{{Key: DeleteDateField, Value: 1}},
+		})
+	}
+	return specs
+}
+
+// Run purges every target once while holding the purge lease.
+// Returns migrations.ErrLocked when another instance is purging
+func Run(ctx context.Context, database db.Database, targets []Target, opts Options, logger zerolog.Logger) ([]Result, error) {
+	if database == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if opts.LeaseTTL <= 0 {
+		return nil, fmt.Errorf("lease TTL must be positive")
+	}
+	if opts.Owner == "" {
+		opts.Owner = migrations.DefaultOwner()
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.Clock == nil {
+		opts.Clock = db.RealClock{}
+	}
+
+	lease := migrations.NewLease(database.Collection(migrations.CollectionName), leaseID, opts.Owner, opts.LeaseTTL)
+	if err := lease.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer lease.Release(context.WithoutCancel(ctx))
+
+	// Renew the lease until the run ends so long purges keep it
+	renewCtx, stopRenewal := context.WithCancel(ctx)
+	defer stopRenewal()
+	go lease.KeepAlive(renewCtx, logger)
+
+	results := make([]Result, 0, len(targets))
+	for _, target := range targets {
+		result, err := purgeTarget(ctx, database, target, opts, logger)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", target.Collection, err)
+		}
+	}
+	return results, nil
+}
+
+// Schedule runs the purge right away and then every interval until ctx ends. Runs locked
+// out by another instance are skipped; failed runs are logged and retried at the next interval
+func Schedule(ctx context.Context, database db.Database, targets []Target, interval time.Duration, opts Options, logger zerolog.Logger) {
+	clock := opts.Clock
+	if clock == nil {
+		clock = db.RealClock{}
+	}
+
+	for {
+		results, err := Run(ctx, database, targets, opts, logger)
+		switch {
+		case errors.Is(err, migrations.ErrLocked):
+			logger.Info().Msg("Purge is running on another instance - skipping")
+		case err != nil && ctx.Err() == nil:
+			logger.Error().Err(err).Dur("retry_in", interval).Msg("Purge failed")
+		case err == nil:
+			var purged int64
+			for _, result := range results {
+				purged += result.Purged
+			}
+			logger.Info().Int64("purged", purged).Bool("dry_run", opts.DryRun).Msg("Purge complete")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-clock.After(interval):
+		}
+	}
+}
+
+// purgeTarget audits and deletes the documents of one target deleted before its retention cutoff
+func purgeTarget(ctx context.Context, database db.Database, target Target, opts Options, logger zerolog.Logger) (Result, error) {
+	result := Result{Entity: target.Entity, Collection: target.Collection, DryRun: opts.DryRun}
+	logger = logger.With().Str("entity", target.Entity).Bool("dry_run", opts.DryRun).Logger()
+	collection := database.Collection(target.Collection)
+	audit := database.Collection(AuditCollection)
+
+	// Only documents still deleted and deleted before the cutoff are purged; the delete filter
+	// repeats the condition, so an entity restored while the run lasts is kept
+	cutoff := opts.Clock.Now().UTC().Add(-target.Retention)
+	expired := bson.M{
+		target.DeletionField: target.DeletionValue,
+		DeleteDateField:      bson.M{"$lt": cutoff},
+	}
+
+	cursor, err := collection.Find(ctx, expired, options.Find().
+		SetProjection(bson.M{"identifier": 1, DeleteDateField: 1}).
+		SetBatchSize(int32(opts.BatchSize)))
+	if err != nil {
+		return result, err
+	}
+
+	var records []interface{}
+	var deletes []mongo.WriteModel
+	sent := false
+	flush := func() error {
+		if len(records) == 0 {
+			return nil
+		}
+		if sent && opts.BatchPause > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-opts.Clock.After(opts.BatchPause):
+			}
+		}
+		sent = true
+
+		purged := int64(len(records))
+		if !opts.DryRun {
+			// The audit record is written first: a failed delete leaves an entity that is
+			// purged again by the next run, never a purge without a record
+			if _, err := audit.InsertMany(ctx, records); err != nil {
+				return err
+			}
+			bulk, err := collection.BulkWrite(ctx, deletes, options.BulkWrite().SetOrdered(false))
+			if err != nil {
+				return err
+			}
+			purged = bulk.DeletedCount
+		}
+		result.Purged += purged
+		count(target.Entity, purged, opts.DryRun)
+		logger.Info().Int64("purged", result.Purged).Msg("Purge progress")
+
+		records, deletes = records[:0], deletes[:0]
+		return nil
+	}
+
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		for cursor.Next(ctx) {
+			var doc struct {
+				ID         interface{} `bson:"_id"`
+				Identifier string      `bson:"identifier"`
+				DeleteDate time.Time   `bson:"deleteDate"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+
+			records = append(records, auditRecord{
+				Entity:     target.Entity,
+				Collection: target.Collection,
+				DocumentID: doc.ID,
+				Identifier: doc.Identifier,
+				DeleteDate: doc.DeleteDate,
+				PurgedAt:   opts.Clock.Now().UTC(),
+				PurgedBy:   opts.Owner,
+			})
+			filter := bson.M{"_id": doc.ID}
+			for field, value := range expired {
+				filter[field] = value
+			}
+			deletes = append(deletes, mongo.NewDeleteOneModel().SetFilter(filter))
+
+			if len(records) >= opts.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return cursor.Err()
+	})
+	if err != nil {
+		return result, err
+	}
+	return result, flush()
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// readFallbackPrimaryWait bounds the first attempt of a read while the topology has no known
+// primary, so the retry on a secondary still fits in the caller's deadline
+const readFallbackPrimaryWait = 2 * time.Second
+
+// noPrimaryErrorCodes are the server error codes of a node that is not, or no longer, primary
+var noPrimaryErrorCodes = []int{
+	189,   // PrimarySteppedDown
+	10107, // NotWritablePrimary
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// IsNoPrimaryError reports whether an operation failed for lack of a primary: the server
+// answered with a not-primary error, or no server matching the read preference could be selected
+func IsNoPrimaryError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		for _, code := range noPrimaryErrorCodes {
+			if serverErr.HasErrorCode(code) {
+				return true
+			}
+		}
+	}
+	var selectionErr topology.ServerSelectionError
+	return errors.As(err, &selectionErr)
+}
+
+// ReadFallbackHook is called after a read failed for lack of a primary and is retried on a
+// secondary, with the context of the read and the collection name
+type ReadFallbackHook func(ctx context.Context, collection string)
+
+// SetReadFallbackHook registers the function told about reads retried on a secondary,
+// e.g. to tag the response so clients can account for stale data
+func (c *Client) SetReadFallbackHook(hook ReadFallbackHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readFallbackHook = hook
+}
+
+// readFallbackCollection retries reads once on a secondary when the first attempt fails for lack
+// of a primary. Writes, snapshot reads and index listings go through the embedded collection
+// and never fall back
+type readFallbackCollection struct {
+	Collection
+	client *Client // Client the collection reads through; its topology tells whether a primary is known
+	parent *Client // Client whose fallback hook is told
+}
+
+// readFallback runs a read and, when it failed for lack of a primary, once more with
+// readPreference secondaryPreferred
+func readFallback[T any](c *readFallbackCollection, ctx context.Context, operation string, read func(ctx context.Context, collection Collection) (T, error)) (T, error) {
+	attemptCtx := ctx
+	if c.client.topology.Summary().Primary == "" {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, readFallbackPrimaryWait)
+		defer cancel()
+	}
+
+	result, err := read(attemptCtx, c.Collection)
+	if !IsNoPrimaryError(err) || ctx.Err() != nil {
+		return result, err
+	}
+
+	c.client.logger.Warn().
+		Str("event_type", "mongodb_read_fallback").
+		Str("operation", operation).
+		Str("collection", c.Name()).
+		Err(err).
+		Msg("No primary available; retrying read on a secondary")
+	c.parent.mu.RLock()
+	hook := c.parent.readFallbackHook
+	c.parent.mu.RUnlock()
+	if hook != nil {
+		hook(ctx, c.Name())
+	}
+
+	return read(ctx, c.client.secondaryCollection(c.Name()))
+}
+
+// FindOne finds a single document, retrying on a secondary without a primary
+func (c *readFallbackCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	result, _ := readFallback(c, ctx, "find_one", func(ctx context.Context, collection Collection) (*mongo.SingleResult, error) {
+		result := collection.FindOne(ctx, filter)
+		return result, result.Err()
+	})
+	return result
+}
+
+// Find finds multiple documents, retrying on a secondary without a primary
+func (c *readFallbackCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return readFallback(c, ctx, "find", func(ctx context.Context, collection Collection) (*mongo.Cursor, error) {
+		return collection.Find(ctx, filter, opts...)
+	})
+}
+
+// CountDocuments counts documents, retrying on a secondary without a primary
+func (c *readFallbackCollection) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	return readFallback(c, ctx, "count_documents", func(ctx context.Context, collection Collection) (int64, error) {
+		return collection.CountDocuments(ctx, filter)
+	})
+}
+
+// Aggregate executes an aggregation pipeline, retrying on a secondary without a primary
+func (c *readFallbackCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return readFallback(c, ctx, "aggregate", func(ctx context.Context, collection Collection) (*mongo.Cursor, error) {
+		return collection.Aggregate(ctx, pipeline, opts...)
+	})
+}
+
+// secondaryCollection returns a collection accessor reading with readPreference secondaryPreferred
+func (c *Client) secondaryCollection(name string) Collection {
+	collection := c.database.Collection(name, options.Collection().SetReadPreference(readpref.SecondaryPreferred()))
+	return newCollection(collection, c.config.OperationTimeout, c.logger)
+}
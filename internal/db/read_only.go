@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReadOnlyError is returned by the writes of a collection of a read-only client. It matches
+// ErrReadOnly with errors.Is
+type ReadOnlyError struct {
+	Operation  string // Refused operation, e.g. insert_one
+	Collection string
+}
+
+// Error implements error
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("db: %s on %s refused: client is read-only", e.Operation, e.Collection)
+}
+
+// Is reports whether target is ErrReadOnly
+func (e *ReadOnlyError) Is(target error) bool {
+	return target == ErrReadOnly
+}
+
+// SetReadOnly makes the collections handed out by Collection refuse inserts, updates, deletes
+// and bulk writes, or lets them write again. Collections obtained before keep their mode
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readOnly = readOnly
+}
+
+// ReadOnly reports whether the client refuses writes
+func (c *Client) ReadOnly() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.readOnly
+}
+
+// readOnlyCollection refuses the write operations of a collection before they reach the database
+type readOnlyCollection struct {
+	Collection
+}
+
+// refuse returns the error of a refused write
+func (c *readOnlyCollection) refuse(operation string) error {
+	return &ReadOnlyError{Operation: operation, Collection: c.Name()}
+}
+
+// InsertOne refuses the write
+func (c *readOnlyCollection) InsertOne(context.Context, interface{}) (*mongo.InsertOneResult, error) {
+	return nil, c.refuse("insert_one")
+}
+
+// InsertMany refuses the write
+func (c *readOnlyCollection) InsertMany(context.Context, []interface{}) (*mongo.InsertManyResult, error) {
+	return nil, c.refuse("insert_many")
+}
+
+// UpdateOne refuses the write
+func (c *readOnlyCollection) UpdateOne(context.Context, interface{}, interface{}) (*mongo.UpdateResult, error) {
+	return nil, c.refuse("update_one")
+}
+
+// UpdateMany refuses the write
+func (c *readOnlyCollection) UpdateMany(context.Context, interface{}, interface{}) (*mongo.UpdateResult, error) {
+	return nil, c.refuse("update_many")
+}
+
+// BulkWrite refuses the write
+func (c *readOnlyCollection) BulkWrite(context.Context, []mongo.WriteModel, ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	return nil, c.refuse("bulk_write")
+}
+
+// DeleteOne refuses the write
+func (c *readOnlyCollection) DeleteOne(context.Context, interface{}) (*mongo.DeleteResult, error) {
+	return nil, c.refuse("delete_one")
+}
+
+// DeleteMany refuses the write
+func (c *readOnlyCollection) DeleteMany(context.Context, interface{}) (*mongo.DeleteResult, error) {
+	return nil, c.refuse("delete_many")
+}
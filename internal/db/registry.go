@@ -0,0 +1,88 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Registry is the BSON registry documents are decoded with. It extends the default registry
+// to decode BSON dates into string fields as RFC3339 UTC timestamps: the models keep timestamps
+// such as createDate as strings, which the normalize-create-date migration stores as dates
+var Registry = newRegistry()
+
+// newRegistry builds the default registry with the date-aware string decoder
+func newRegistry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+	stringType := reflect.TypeOf("")
+	fallback, err := registry.LookupDecoder(stringType)
+	if err != nil {
+		panic(err)
+	}
+	registry.RegisterTypeDecoder(stringType, dateStringDecoder{fallback: fallback})
+	return registry
+}
+
+// Enum is a string enum type of the GraphQL models, which reports whether it holds one of its members
+type Enum interface {
+	IsValid() bool
+}
+
+// RegisterEnumDecoder makes Registry decode values of an enum type that are not one of its
+// members, including nulls and values of other BSON types, as unknown instead of failing the
+// whole document. enumType must have kind string and implement Enum. Must be called before
+// documents are decoded
+func RegisterEnumDecoder(enumType reflect.Type, unknown string) {
+	if enumType.Kind() != reflect.String || !enumType.Implements(reflect.TypeOf((*Enum)(nil)).Elem()) {
+		panic(fmt.Sprintf("db: %s is not a string enum", enumType))
+	}
+	Registry.RegisterTypeDecoder(enumType, enumDecoder{unknown: unknown})
+}
+
+// enumDecoder decodes an enum, replacing values that are not one of its members by unknown
+type enumDecoder struct {
+	unknown string
+}
+
+// DecodeValue implements bsoncodec.ValueDecoder
+func (d enumDecoder) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() {
+		return bsoncodec.ValueDecoderError{Name: "enumDecoder", Kinds: []reflect.Kind{reflect.String}, Received: val}
+	}
+	if vr.Type() != bsontype.String {
+		val.SetString(d.unknown)
+		return vr.Skip()
+	}
+	value, err := vr.ReadString()
+	if err != nil {
+		return err
+	}
+	val.SetString(value)
+	if !val.Interface().(Enum).IsValid() {
+		val.SetString(d.unknown)
+	}
+	return nil
+}
+
+// dateStringDecoder decodes BSON dates into strings and leaves other values to the default decoder
+type dateStringDecoder struct {
+	fallback bsoncodec.ValueDecoder
+}
+
+// DecodeValue implements bsoncodec.ValueDecoder
+func (d dateStringDecoder) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if vr.Type() != bsontype.DateTime || !val.CanSet() {
+		return d.fallback.DecodeValue(dc, vr, val)
+	}
+	millis, err := vr.ReadDateTime()
+	if err != nil {
+		return err
+	}
+	val.SetString(time.UnixMilli(millis).UTC().Format(time.RFC3339Nano))
+	return nil
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ResourceSampleInterval is how often a connected client samples its resource usage
+const ResourceSampleInterval = time.Minute
+
+// resourceSampleWindow is the number of samples kept, one hour at the default interval
+const resourceSampleWindow = 60
+
+// ResourceSample is the resource usage of the process at one point in time
+type ResourceSample struct {
+	At               time.Time `json:"at"`
+	Goroutines       int       `json:"goroutines"`
+	ConnectionsInUse int64     `json:"connections_in_use"` // Connections checked out of this client's pool
+	OpenCursors      int64     `json:"open_cursors"`       // Tracked cursors not closed yet, process-wide
+}
+
+// ResourceSummary is the series of recent resource samples, oldest first
+type ResourceSummary struct {
+	Samples []ResourceSample `json:"samples"`
+}
+
+// ResourceThresholds is the growth over the sample window above which a resource is reported as leaking
+type ResourceThresholds struct {
+	Goroutines       int
+	ConnectionsInUse int64
+	OpenCursors      int64
+}
+
+// DefaultResourceThresholds tolerates load-driven variation; a leak keeps growing past them
+var DefaultResourceThresholds = ResourceThresholds{
+	Goroutines:       500,
+	ConnectionsInUse: 10,
+	OpenCursors:      20,
+}
+
+// openCursors counts the cursors returned by TrackCursor that were not closed yet
+var openCursors atomic.Int64
+
+// OpenCursors returns the number of tracked cursors that were not closed yet
+func OpenCursors() int64 {
+	return openCursors.Load()
+}
+
+// TrackedCursor is a cursor counted as open until it is closed or drained with All
+type TrackedCursor struct {
+	*mongo.Cursor
+	released atomic.Bool
+}
+
+// TrackCursor counts a cursor as open until the returned cursor's Close or All.
+// A cursor that is never closed stays counted, so leaks show up in the resource samples
+func TrackCursor(cursor *mongo.Cursor) *TrackedCursor {
+	openCursors.Add(1)
+	return &TrackedCursor{Cursor: cursor}
+}
+
+// Close closes the cursor and stops counting it
+func (c *TrackedCursor) Close(ctx context.Context) error {
+	c.release()
+	return c.Cursor.Close(ctx)
+}
+
+// All decodes every remaining document into results; the driver closes the cursor afterwards
+func (c *TrackedCursor) All(ctx context.Context, results interface{}) error {
+	defer c.release()
+	return c.Cursor.All(ctx, results)
+}
+
+// release stops counting the cursor, once
+func (c *TrackedCursor) release() {
+	if c.released.CompareAndSwap(false, true) {
+		openCursors.Add(-1)
+	}
+}
+
+// ResourceMonitor samples goroutines, pooled connections in use and open cursors, and logs
+// a warning when one of them grew past its threshold over the sample window, so slow leaks
+// surface in logs and the health output long before the pool or memory runs out
+type ResourceMonitor struct {
+	logger     zerolog.Logger
+	thresholds ResourceThresholds
+
+	connectionsInUse atomic.Int64
+
+	mu      sync.RWMutex
+	samples []ResourceSample
+}
+
+// NewResourceMonitor creates a monitor that logs to the given logger
+func NewResourceMonitor(logger zerolog.Logger, thresholds ResourceThresholds) *ResourceMonitor {
+	return &ResourceMonitor{logger: logger, thresholds: thresholds}
+}
+
+// PoolMonitor returns the driver callbacks to register via ClientOptions.SetPoolMonitor
+func (m *ResourceMonitor) PoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.GetSucceeded:
+				m.connectionsInUse.Add(1)
+			case event.ConnectionReturned:
+				m.connectionsInUse.Add(-1)
+			}
+		},
+	}
+}
+
+// Sample records the current resource usage and warns about resources that grew past their
+// threshold since the oldest sample in the window
+func (m *ResourceMonitor) Sample(now time.Time) ResourceSample {
+	sample := ResourceSample{
+		At:               now,
+		Goroutines:       runtime.NumGoroutine(),
+		ConnectionsInUse: m.connectionsInUse.Load(),
+		OpenCursors:      OpenCursors(),
+	}
+
+	m.mu.Lock()
+	m.samples = append(m.samples, sample)
+	if len(m.samples) > resourceSampleWindow {
+		m.samples = m.samples[len(m.samples)-resourceSampleWindow:]
+	}
+	oldest := m.samples[0]
+	m.mu.Unlock()
+
+	m.warnGrowth("goroutines", int64(oldest.Goroutines), int64(sample.Goroutines), int64(m.thresholds.Goroutines), oldest.At, now)
+	m.warnGrowth("connections_in_use", oldest.ConnectionsInUse, sample.ConnectionsInUse, m.thresholds.ConnectionsInUse, oldest.At, now)
+	m.warnGrowth("open_cursors", oldest.OpenCursors, sample.OpenCursors, m.thresholds.OpenCursors, oldest.At, now)
+	return sample
+}
+
+// warnGrowth logs a resource that grew by more than its threshold
+func (m *ResourceMonitor) warnGrowth(resource string, from, to, threshold int64, since, now time.Time) {
+	if to-from <= threshold {
+		return
+	}
+	m.logger.Warn().
+		Str("event_type", "resource_growth").
+		Str("resource", resource).
+		Int64("from", from).
+		Int64("to", to).
+		Int64("threshold", threshold).
+		Int64("window_ms", now.Sub(since).Milliseconds()).
+		Msg("Resource usage keeps growing; possible leak")
+}
+
+// ConnectionsInUse returns the connections currently checked out of the pool
+func (m *ResourceMonitor) ConnectionsInUse() int64 {
+	return m.connectionsInUse.Load()
+}
+
+// Summary returns the recorded samples, oldest first
+func (m *ResourceMonitor) Summary() ResourceSummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return ResourceSummary{Samples: append([]ResourceSample(nil), m.samples...)}
+}
+
+// Run samples every interval until ctx is done
+func (m *ResourceMonitor) Run(ctx context.Context, clock Clock, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-clock.After(interval):
+			m.Sample(now)
+		}
+	}
+}
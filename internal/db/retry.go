@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"math/rand"
 	"time"
 
@@ -51,6 +52,17 @@ func CalculateDelay(attempt int, baseDelay, maxDelay time.Duration) time.Duratio
 	return jittered
 }
 
+// WaitForRetry blocks until delay has elapsed on the clock or the context ends,
+// returning the context error in the latter case
+func WaitForRetry(ctx context.Context, clock Clock, delay time.Duration) error {
+	select {
+	case <-clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // ShouldRetry determines if another retry attempt should be made
 // Exported for testing (T092)
 func ShouldRetry(attempt int, maxAttempts int) bool {
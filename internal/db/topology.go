@@ -0,0 +1,134 @@
+package db
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/description"
+)
+
+// TopologySummary describes the deployment as last reported by the driver
+type TopologySummary struct {
+	Kind              string `json:"kind"`              // Single, ReplicaSetWithPrimary, Sharded, ...
+	KnownServers      int    `json:"known_servers"`     // Servers the driver has discovered, including unreachable ones
+	Primary           string `json:"primary,omitempty"` // Primary address without credentials, empty when there is none
+	TopologyChanges   int64  `json:"topology_changes"`
+	PrimaryChanges    int64  `json:"primary_changes"`
+	HeartbeatFailures int64  `json:"heartbeat_failures"`
+}
+
+// TopologyMonitor logs and counts driver topology events so membership changes of
+// replica sets (including SRV-polled ones) show up in logs and the health output
+type TopologyMonitor struct {
+	logger zerolog.Logger
+
+	mu           sync.RWMutex
+	kind         string
+	knownServers int
+	primary      string
+
+	topologyChanges   atomic.Int64
+	primaryChanges    atomic.Int64
+	heartbeatFailures atomic.Int64
+}
+
+// NewTopologyMonitor creates a monitor that logs to the given logger
+func NewTopologyMonitor(logger zerolog.Logger) *TopologyMonitor {
+	return &TopologyMonitor{logger: logger}
+}
+
+// ServerMonitor returns the driver callbacks to register via ClientOptions.SetServerMonitor
+func (m *TopologyMonitor) ServerMonitor() *event.ServerMonitor {
+	return &event.ServerMonitor{
+		TopologyDescriptionChanged: m.topologyDescriptionChanged,
+		ServerHeartbeatFailed:      m.serverHeartbeatFailed,
+	}
+}
+
+// Summary returns the current topology and event counts
+func (m *TopologyMonitor) Summary() TopologySummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return TopologySummary{
+		Kind:              m.kind,
+		KnownServers:      m.knownServers,
+		Primary:           m.primary,
+		TopologyChanges:   m.topologyChanges.Load(),
+		PrimaryChanges:    m.primaryChanges.Load(),
+		HeartbeatFailures: m.heartbeatFailures.Load(),
+	}
+}
+
+// topologyDescriptionChanged records the new topology and logs primary changes.
+// Runs while the driver holds the topology lock, so it must not run operations on the client
+func (m *TopologyMonitor) topologyDescriptionChanged(e *event.TopologyDescriptionChangedEvent) {
+	kind := e.NewDescription.Kind.String()
+	primary := primaryAddress(e.NewDescription)
+
+	m.mu.Lock()
+	previousPrimary := m.primary
+	m.kind = kind
+	m.knownServers = len(e.NewDescription.Servers)
+	m.primary = primary
+	m.mu.Unlock()
+
+	m.topologyChanges.Add(1)
+	m.logger.Info().
+		Str("event_type", "mongodb_topology_changed").
+		Str("previous_kind", e.PreviousDescription.Kind.String()).
+		Str("kind", kind).
+		Int("previous_servers", len(e.PreviousDescription.Servers)).
+		Int("servers", len(e.NewDescription.Servers)).
+		Str("primary", primary).
+		Msg("MongoDB topology changed")
+
+	if primary == previousPrimary {
+		return
+	}
+	m.primaryChanges.Add(1)
+
+	// Discovering the first primary is expected; losing or replacing one is not
+	logEvent := m.logger.Warn()
+	if previousPrimary == "" {
+		logEvent = m.logger.Info()
+	}
+	logEvent.
+		Str("event_type", "mongodb_primary_changed").
+		Str("previous_primary", previousPrimary).
+		Str("primary", primary).
+		Msg("MongoDB primary changed")
+}
+
+// serverHeartbeatFailed counts and logs a failed server heartbeat
+func (m *TopologyMonitor) serverHeartbeatFailed(e *event.ServerHeartbeatFailedEvent) {
+	m.heartbeatFailures.Add(1)
+	m.logger.Warn().
+		Str("event_type", "mongodb_heartbeat_failed").
+		Str("connection_id", stripCredentials(e.ConnectionID)).
+		Int64("duration_ms", e.Duration.Milliseconds()).
+		Bool("awaited", e.Awaited).
+		Err(e.Failure).
+		Msg("MongoDB server heartbeat failed")
+}
+
+// primaryAddress returns the address of the primary (or standalone server) without credentials
+func primaryAddress(topology description.Topology) string {
+	for _, server := range topology.Servers {
+		if server.Kind == description.RSPrimary || server.Kind == description.Standalone {
+			return stripCredentials(server.Addr.String())
+		}
+	}
+	return ""
+}
+
+// stripCredentials removes a user:password@ prefix from a host address
+func stripCredentials(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return address
+}
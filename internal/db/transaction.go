@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// isReplicated reports whether the deployment is a replica set or sharded cluster,
+// which snapshot reads and transactions require
+func isReplicated(ctx context.Context, database *mongo.Database) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
+
+// WithTransaction runs fn in a multi-document transaction. Collection operations called with the
+// context passed to fn take part in it, and the transaction is retried on transient errors, so fn
+// must be safe to run more than once. Returns ErrTransactionsUnsupported on standalone servers
+func (c *Client) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	c.mu.RLock()
+	mongoClient, database := c.mongoClient, c.database
+	c.mu.RUnlock()
+	if mongoClient == nil || database == nil {
+		return ErrNotConnected
+	}
+
+	replicated, err := isReplicated(ctx, database)
+	if err != nil {
+		return err
+	}
+	if !replicated {
+		return ErrTransactionsUnsupported
+	}
+
+	session, err := mongoClient.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return err
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Auxiliary writes (customer history without a transaction, the access audit, idempotency
+// responses) are best-effort: the request they belong to succeeds when they fail. A
+// WriteRetryQueue keeps those that failed, for example during a short MongoDB outage, and replays
+// them in order once the database reports connected again. The queue is held in memory and
+// bounded: when it is full the oldest write is dropped, and writes still queued at shutdown are lost
+
+// writeRetryHealthInterval is the pause between health checks while the database is not connected
+const writeRetryHealthInterval = time.Second
+
+// WriteRetryOptions configures a WriteRetryQueue
+type WriteRetryOptions struct {
+	Capacity  int           // Writes kept; the oldest is dropped when a write arrives at a full queue
+	BaseDelay time.Duration // Pause after the first failed replay; doubled after every further failure
+	MaxDelay  time.Duration // Longest pause between replays
+}
+
+// WriteRetryStats counts the writes of a WriteRetryQueue since it was created
+type WriteRetryStats struct {
+	Pending  int   // Writes waiting for replay
+	Queued   int64 // Failed writes added
+	Dropped  int64 // Writes dropped from a full queue
+	Replayed int64 // Writes replayed successfully
+}
+
+// retryWrite is a queued write; seq tells it apart from writes queued later at the same position
+type retryWrite struct {
+	seq   uint64
+	kind  string
+	write func(ctx context.Context) error
+}
+
+// WriteRetryQueue replays failed auxiliary writes in the order they were added. Add is safe for
+// concurrent use; Run replays them until its context ends
+type WriteRetryQueue struct {
+	opts   WriteRetryOptions
+	clock  Clock
+	logger zerolog.Logger
+
+	mu      sync.Mutex
+	writes  []retryWrite
+	nextSeq uint64
+	stats   WriteRetryStats
+	wake    chan struct{}
+}
+
+// NewWriteRetryQueue creates a queue replaying failed writes with the given bounds and backoff
+func NewWriteRetryQueue(opts WriteRetryOptions, clock Clock, logger zerolog.Logger) *WriteRetryQueue {
+	return &WriteRetryQueue{
+		opts:   opts,
+		clock:  clock,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Add queues a failed write for replay. kind names the write in logs (e.g. customer_history).
+// write must be safe to run again after a failure that may have applied it. When the queue is
+// full the oldest write is dropped with a warning. A nil queue keeps nothing and returns false
+func (q *WriteRetryQueue) Add(kind string, write func(ctx context.Context) error) bool {
+	if q == nil {
+		return false
+	}
+
+	q.mu.Lock()
+	q.nextSeq++
+	q.writes = append(q.writes, retryWrite{seq: q.nextSeq, kind: kind, write: write})
+	q.stats.Queued++
+	dropped := ""
+	if len(q.writes) > q.opts.Capacity {
+		dropped = q.writes[0].kind
+		q.writes = q.writes[1:]
+		q.stats.Dropped++
+	}
+	q.mu.Unlock()
+
+	if dropped != "" {
+		q.logger.Warn().
+			Str("kind", dropped).
+			Int("capacity", q.opts.Capacity).
+			Msg("Write retry queue full; dropped the oldest write")
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// Stats returns the queue's counters
+func (q *WriteRetryQueue) Stats() WriteRetryStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats
+	stats.Pending = len(q.writes)
+	return stats
+}
+
+// head returns the oldest queued write, and false when the queue is empty
+func (q *WriteRetryQueue) head() (retryWrite, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.writes) == 0 {
+		return retryWrite{}, false
+	}
+	return q.writes[0], true
+}
+
+// replayed removes a replayed write unless it was dropped meanwhile
+func (q *WriteRetryQueue) replayed(write retryWrite) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.writes) > 0 && q.writes[0].seq == write.seq {
+		q.writes = q.writes[1:]
+	}
+	q.stats.Replayed++
+}
+
+// Run replays the queued writes oldest first until ctx ends. While the client does not report
+// connected, nothing is replayed; a failed replay is retried after a pause growing from
+// BaseDelay to MaxDelay, keeping later writes waiting so the order is kept
+func (q *WriteRetryQueue) Run(ctx context.Context, client DBClient) {
+	failures := 0
+	for {
+		write, ok := q.head()
+		if !ok {
+			select {
+			case <-q.wake:
+				continue
+			case <-ctx.Done():
+				q.logLost()
+				return
+			}
+		}
+
+		if status, err := client.HealthStatus(ctx); err != nil || status == nil || status.Status != "connected" {
+			if WaitForRetry(ctx, q.clock, writeRetryHealthInterval) != nil {
+				q.logLost()
+				return
+			}
+			continue
+		}
+
+		if err := write.write(ctx); err != nil {
+			failures++
+			q.logger.Debug().Err(err).Str("kind", write.kind).Int("attempt", failures).Msg("Replaying a failed write failed")
+			if WaitForRetry(ctx, q.clock, q.backoff(failures)) != nil {
+				q.logLost()
+				return
+			}
+			continue
+		}
+		failures = 0
+		q.replayed(write)
+	}
+}
+
+// backoff returns the pause after the given number of consecutive failed replays
+func (q *WriteRetryQueue) backoff(failures int) time.Duration {
+	delay := q.opts.BaseDelay
+	for i := 1; i < failures && delay < q.opts.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > q.opts.MaxDelay {
+		return q.opts.MaxDelay
+	}
+	return delay
+}
+
+// logLost warns about writes still queued when the queue stops
+func (q *WriteRetryQueue) logLost() {
+	if pending := q.Stats().Pending; pending > 0 {
+		q.logger.Warn().Int("writes", pending).Msg("Write retry queue stopped; queued writes are lost")
+	}
+}
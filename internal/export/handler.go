@@ -0,0 +1,166 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// maxRequestBytes bounds the size of the filter/sorter request body
+const maxRequestBytes = 1 << 20
+
+// Trailers reporting the outcome once the stream has finished
+const (
+	TrailerRowCount  = "X-Export-Row-Count"
+	TrailerTruncated = "X-Export-Truncated"
+	TrailerError     = "X-Export-Error"
+)
+
+// Options controls export limits
+type Options struct {
+	MaxRows   int           // Row cap per export
+	BatchSize int           // Cursor batch size; the response is flushed after every batch
+	Timeout   time.Duration // Overall time limit per export
+}
+
+// Request is the export request body, using the same JSON shape as the search inputs
+type Request struct {
+	Where json.RawMessage `json:"where"`
+	Order json.RawMessage `json:"order"`
+}
+
+// Handler returns an HTTP handler streaming entities matching a filter as NDJSON
+// The entity is taken from the {entity} URL parameter (e.g. customer, employee)
+func Handler(dbClient resolvers.DBClient, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity := chi.URLParam(r, "entity")
+
+		var req Request
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+				http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), opts.Timeout)
+		defer cancel()
+
+		// Query one extra row to detect truncation at the cap
+		query, err := resolvers.BuildExportQuery(ctx, entity, req.Where, req.Order, opts.MaxRows+1)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+
+		cursor, err := dbClient.ReadCollection(query.CollectionName).Aggregate(ctx, query.Pipeline,
+			options.Aggregate().SetBatchSize(int32(opts.BatchSize)).SetAllowDiskUse(true))
+		if err != nil {
+			log.Error().Err(err).Str("entity", entity).Msg("Export query failed")
+			http.Error(w, "Database query failed", http.StatusInternalServerError)
+			return
+		}
+
+		// Exports outlive the server-wide write timeout
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(opts.Timeout))
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Trailer", TrailerRowCount+", "+TrailerTruncated+", "+TrailerError)
+		w.WriteHeader(http.StatusOK)
+
+		flusher, _ := w.(http.Flusher)
+		buffered := bufio.NewWriter(w)
+		encoder := json.NewEncoder(buffered)
+		flush := func() {
+			_ = buffered.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		startTime := time.Now()
+		rows := 0
+		truncated := false
+
+		// Exported entities are recorded in the access audit once per flushed batch
+		exported := make([]interface{}, 0, opts.BatchSize)
+		recordExported := func() {
+			query.RecordAccess(ctx, exported)
+			exported = exported[:0]
+		}
+
+		streamErr := db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+			for cursor.Next(ctx) {
+				if rows == opts.MaxRows {
+					truncated = true
+					return nil
+				}
+
+				doc := query.NewEntity()
+				if err := cursor.Decode(doc); err != nil {
+					return err
+				}
+				if err := encoder.Encode(doc); err != nil {
+					return err
+				}
+				exported = append(exported, doc)
+
+				rows++
+				if rows%opts.BatchSize == 0 {
+					flush()
+					recordExported()
+				}
+			}
+			return cursor.Err()
+		})
+		flush()
+		recordExported()
+
+		w.Header().Set(TrailerRowCount, strconv.Itoa(rows))
+		w.Header().Set(TrailerTruncated, strconv.FormatBool(truncated))
+
+		event := log.Info()
+		if streamErr != nil {
+			// Headers are already sent, so the failure is only reported in the trailer
+			w.Header().Set(TrailerError, streamErr.Error())
+			event = log.Error().Err(streamErr)
+		}
+		event.
+			Str("entity", entity).
+			Int("rows", rows).
+			Bool("truncated", truncated).
+			Dur("duration_ms", time.Since(startTime)).
+			Msg("Export completed")
+	}
+}
+
+// writeQueryError maps a resolver error to an HTTP status
+func writeQueryError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	var queryErr *resolvers.QueryError
+	if errors.As(err, &queryErr) {
+		switch queryErr.Code {
+		case resolvers.ErrCodeNotFound:
+			status = http.StatusNotFound
+		case resolvers.ErrCodeInvalidInput:
+			status = http.StatusBadRequest
+		case resolvers.ErrCodeTimeout:
+			status = http.StatusGatewayTimeout
+		}
+	}
+
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,324 @@
+package genchk
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxListedNames bounds the declarations named per line of a summary
+const maxListedNames = 8
+
+// generatedHeader starts every file gqlgen generates
+const generatedHeader = "// Code generated by github.com/99designs/gqlgen"
+
+// Result lists the generated files that differ from their committed versions
+type Result struct {
+	Files []FileDrift
+}
+
+// FileDrift describes how one committed file differs from its regenerated version
+type FileDrift struct {
+	Path    string   // Committed file, relative to the working directory when possible
+	Changes []string // Changed types, fields and other declarations, readable as is
+}
+
+// Clean reports whether the committed files match the regenerated ones
+func (r *Result) Clean() bool {
+	return len(r.Files) == 0
+}
+
+// Summary returns the changes per file, one indented line per change
+func (r *Result) Summary() string {
+	var b strings.Builder
+	for _, file := range r.Files {
+		b.WriteString(file.Path + ":\n")
+		for _, change := range file.Changes {
+			b.WriteString("  " + change + "\n")
+		}
+	}
+	return b.String()
+}
+
+// compareDirs compares the Go files of the regenerated directory to the committed directory.
+// Committed gqlgen files that are no longer generated are reported as well
+func compareDirs(committed, regenerated string) ([]FileDrift, error) {
+	generated, err := filepath.Glob(filepath.Join(regenerated, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var drifts []FileDrift
+	for _, path := range generated {
+		name := filepath.Base(path)
+		seen[name] = true
+		committedPath := filepath.Join(committed, name)
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		old, err := os.ReadFile(committedPath)
+		if os.IsNotExist(err) {
+			drifts = append(drifts, FileDrift{Path: displayPath(committedPath), Changes: []string{"file is generated but not committed"}})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(old, src) {
+			continue
+		}
+		changes, err := diffDeclarations(old, src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", displayPath(committedPath), err)
+		}
+		drifts = append(drifts, FileDrift{Path: displayPath(committedPath), Changes: changes})
+	}
+
+	committedFiles, err := filepath.Glob(filepath.Join(committed, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range committedFiles {
+		if seen[filepath.Base(path)] {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(src, []byte(generatedHeader)) {
+			drifts = append(drifts, FileDrift{Path: displayPath(path), Changes: []string{"file is committed but no longer generated"}})
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Path < drifts[j].Path })
+	return drifts, nil
+}
+
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// typeDecl is a type; members holds the fields of structs and the methods of interfaces by
+// name, and is nil for other types
+type typeDecl struct {
+	text    string
+	members map[string]typeDecl
+}
+
+// declarations are the top-level declarations of a file. Functions, methods ("Type.name"),
+// variables and constants are compared as a whole
+type declarations struct {
+	types  map[string]typeDecl
+	others map[string]string
+}
+
+// diffDeclarations returns the changes from the old to the new source of a file: added, removed
+// and changed types and fields, then the other declarations whose source changed
+func diffDeclarations(old, new []byte) ([]string, error) {
+	before, err := parseDeclarations(old)
+	if err != nil {
+		return nil, err
+	}
+	after, err := parseDeclarations(new)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, name := range unionKeys(before.types, after.types) {
+		oldType, inOld := before.types[name]
+		newType, inNew := after.types[name]
+		switch {
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("type %s added", name))
+		case !inNew:
+			changes = append(changes, fmt.Sprintf("type %s removed", name))
+		default:
+			changes = append(changes, diffTypes(name, oldType, newType)...)
+		}
+	}
+
+	var added, removed, changed []string
+	for _, name := range unionKeys(before.others, after.others) {
+		oldText, inOld := before.others[name]
+		newText, inNew := after.others[name]
+		switch {
+		case !inOld:
+			added = append(added, name)
+		case !inNew:
+			removed = append(removed, name)
+		case oldText != newText:
+			changed = append(changed, name)
+		}
+	}
+	changes = append(changes, listNames("declarations added", added)...)
+	changes = append(changes, listNames("declarations removed", removed)...)
+	changes = append(changes, listNames("declarations changed", changed)...)
+
+	if len(changes) == 0 {
+		changes = append(changes, "comments or formatting differ")
+	}
+	return changes, nil
+}
+
+// diffTypes returns the added, removed and changed fields or methods of a type, descending
+// into inline structs, or that the type changed when it has no members on either side
+func diffTypes(name string, before, after typeDecl) []string {
+	if before.members == nil || after.members == nil {
+		if before.text != after.text {
+			return []string{fmt.Sprintf("type %s changed", name)}
+		}
+		return nil
+	}
+
+	var changes []string
+	for _, member := range unionKeys(before.members, after.members) {
+		oldMember, inOld := before.members[member]
+		newMember, inNew := after.members[member]
+		switch {
+		case !inOld && newMember.members == nil:
+			changes = append(changes, fmt.Sprintf("%s.%s added: %s", name, member, newMember.text))
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("%s.%s added", name, member))
+		case !inNew:
+			changes = append(changes, fmt.Sprintf("%s.%s removed", name, member))
+		case oldMember.members != nil && newMember.members != nil:
+			changes = append(changes, diffTypes(name+"."+member, oldMember, newMember)...)
+		case oldMember.text != newMember.text:
+			changes = append(changes, fmt.Sprintf("%s.%s changed from %s to %s", name, member, oneLine(oldMember.text), oneLine(newMember.text)))
+		}
+	}
+	return changes
+}
+
+// oneLine shortens a multi-line type to its first line
+func oneLine(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[:i] + " ... }"
+	}
+	return text
+}
+
+// listNames returns a line naming the first maxListedNames names, or none for no names
+func listNames(label string, names []string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	listed := names
+	more := ""
+	if len(listed) > maxListedNames {
+		listed = listed[:maxListedNames]
+		more = fmt.Sprintf(" and %d more", len(names)-maxListedNames)
+	}
+	return []string{fmt.Sprintf("%s (%d): %s%s", label, len(names), strings.Join(listed, ", "), more)}
+}
+
+func parseDeclarations(src []byte) (*declarations, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	source := func(node interface{}) string {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, node); err != nil {
+			return fmt.Sprintf("<%v>", err)
+		}
+		return buf.String()
+	}
+
+	decls := &declarations{types: map[string]typeDecl{}, others: map[string]string{}}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			name := decl.Name.Name
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				name = receiverName(decl.Recv.List[0].Type) + "." + name
+			}
+			decls.others[name] = source(decl)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					decls.types[spec.Name.Name] = typeDecl{text: source(spec), members: members(spec.Type, source)}
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						decls.others[name.Name] = source(spec)
+					}
+				}
+			}
+		}
+	}
+	return decls, nil
+}
+
+// members returns the fields of a struct or the methods of an interface with their types
+func members(expr ast.Expr, source func(interface{}) string) map[string]typeDecl {
+	var fields *ast.FieldList
+	switch expr := expr.(type) {
+	case *ast.StructType:
+		fields = expr.Fields
+	case *ast.InterfaceType:
+		fields = expr.Methods
+	default:
+		return nil
+	}
+
+	result := map[string]typeDecl{}
+	for _, field := range fields.List {
+		member := typeDecl{text: source(field.Type), members: members(field.Type, source)}
+		if field.Tag != nil {
+			member.text += " " + field.Tag.Value
+		}
+		if len(field.Names) == 0 {
+			result[member.text] = member
+		}
+		for _, name := range field.Names {
+			result[name.Name] = member
+		}
+	}
+	return result
+}
+
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	}
+	return "?"
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package genchk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const committedSource = `package generated
+
+type Customer struct {
+	FirstName *string ` + "`json:\"firstName,omitempty\"`" + `
+	Key       *string ` + "`json:\"key,omitempty\"`" + `
+}
+
+type QueryResolver interface {
+	CustomerGet(identifier string) (*Customer, error)
+}
+
+type ComplexityRoot struct {
+	Customer struct {
+		FirstName func(childComplexity int) int
+	}
+}
+
+type UserStatus string
+
+func (e UserStatus) IsValid() bool { return e == "ACTIVE" }
+
+func field_Customer_key() {}
+`
+
+func TestDiffDeclarations(t *testing.T) {
+	regenerated := `package generated
+
+type Customer struct {
+	FirstName string ` + "`json:\"firstName\"`" + `
+	Nickname  *string ` + "`json:\"nickname,omitempty\"`" + `
+}
+
+type QueryResolver interface {
+	CustomerGet(identifier string) (*Customer, error)
+	CustomerSearch(first *int) ([]*Customer, error)
+}
+
+type ComplexityRoot struct {
+	Customer struct {
+		FirstName func(childComplexity int) int
+		Nickname  func(childComplexity int) int
+	}
+}
+
+type UserStatus int
+
+func (e UserStatus) IsValid() bool { return e == 1 }
+
+func field_Customer_nickname() {}
+`
+	changes, err := diffDeclarations([]byte(committedSource), []byte(regenerated))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"ComplexityRoot.Customer.Nickname added: func(childComplexity int) int",
+		"Customer.FirstName changed from *string `json:\"firstName,omitempty\"` to string `json:\"firstName\"`",
+		"Customer.Key removed",
+		"Customer.Nickname added: *string `json:\"nickname,omitempty\"`",
+		"QueryResolver.CustomerSearch added: func(first *int) ([]*Customer, error)",
+		"type UserStatus changed",
+		"declarations added (1): field_Customer_nickname",
+		"declarations removed (1): field_Customer_key",
+		"declarations changed (1): UserStatus.IsValid",
+	}, changes)
+}
+
+func TestDiffDeclarations_CommentsOnly(t *testing.T) {
+	changes, err := diffDeclarations([]byte(committedSource), []byte("// Edited by hand\n"+committedSource))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"comments or formatting differ"}, changes)
+}
+
+func TestListNames(t *testing.T) {
+	assert.Nil(t, listNames("declarations changed", nil))
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	assert.Equal(t, []string{"declarations changed (10): a, b, c, d, e, f, g, h and 2 more"}, listNames("declarations changed", names))
+}
@@ -0,0 +1,142 @@
+// Package genchk checks the committed gqlgen output against the schema. It runs gqlgen generate
+// in a temporary copy of the module and compares the exec and model packages of gqlgen.yml with
+// the committed ones, so a schema change without regeneration fails before it reaches a runtime
+// panic
+package genchk
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/99designs/gqlgen/codegen/config"
+)
+
+// checkOptions are appended to the copied configuration: go mod tidy needs the network and the
+// regenerated resolver stubs are discarded, so neither is worth the time
+const checkOptions = "\nskip_mod_tidy: true\nskip_validation: true\n"
+
+// Check runs gqlgen generate, with the version pinned in go.mod, in a temporary copy of the
+// module and returns how the committed files differ from the regenerated ones. gqlgen runs
+// verbosely so its output explains a failure. It must run in the module root, as gqlgen does,
+// and loads packages without network access
+func Check(configFile string) (*Result, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Exec.Layout != "" && cfg.Exec.Layout != config.ExecLayoutSingleFile {
+		return nil, fmt.Errorf("genchk supports the %s exec layout only, not %s", config.ExecLayoutSingleFile, cfg.Exec.Layout)
+	}
+
+	copyDir, err := os.MkdirTemp("", "genchk-")
+	if err != nil {
+		return nil, fmt.Errorf("create temporary module copy: %w", err)
+	}
+	defer os.RemoveAll(copyDir)
+	if err := copyModule(root, copyDir); err != nil {
+		return nil, fmt.Errorf("copy module: %w", err)
+	}
+
+	copiedConfig, err := filepath.Abs(filepath.Join(copyDir, configFile))
+	if err != nil {
+		return nil, err
+	}
+	if err := appendFile(copiedConfig, checkOptions); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("go", "run", "github.com/99designs/gqlgen", "generate", "--verbose", "--config", copiedConfig)
+	cmd.Dir = copyDir
+	cmd.Env = append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("gqlgen generate: %w\n%s", err, output)
+	}
+
+	// The exec and model packages may share a directory; compare each directory once
+	dirs := map[string]bool{filepath.Dir(cfg.Exec.Filename): true}
+	if cfg.Model.IsDefined() {
+		dirs[filepath.Dir(cfg.Model.Filename)] = true
+	}
+	result := &Result{}
+	for dir := range dirs {
+		committed, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		drifts, err := compareDirs(committed, filepath.Join(copyDir, dir))
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, drifts...)
+	}
+	return result, nil
+}
+
+// copyModule copies the files of the module root to dir, skipping hidden files and directories
+// such as .git
+func copyModule(root, dir string) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dir, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func appendFile(path, text string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,86 @@
+package persisted
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rs/zerolog/log"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// Extension is a gqlgen handler extension that only admits operations listed in the manifest
+// Clients may send the full query text, or only its hash in extensions.persistedQuery.sha256Hash
+type Extension struct {
+	Manifest *Manifest
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = Extension{}
+
+// ExtensionName returns the name of the extension
+func (e Extension) ExtensionName() string {
+	return "PersistedOperations"
+}
+
+// Validate ensures the extension has a manifest to check against
+func (e Extension) Validate(schema graphql.ExecutableSchema) error {
+	if e.Manifest == nil {
+		return errors.New("PersistedOperations.Manifest can not be nil")
+	}
+	return nil
+}
+
+// MutateOperationParameters rejects operations whose hash is not in the manifest
+// and fills in the query text for hash-only requests
+func (e Extension) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	requestedHash := persistedQueryHash(rawParams.Extensions)
+
+	if rawParams.Query == "" {
+		query, ok := e.Manifest.Lookup(requestedHash)
+		if !ok {
+			return operationNotAllowed(requestedHash, rawParams.OperationName)
+		}
+		rawParams.Query = query
+		return nil
+	}
+
+	hash := Hash(rawParams.Query)
+	if requestedHash != "" && requestedHash != hash {
+		err := gqlerror.Errorf("provided persisted query hash does not match query")
+		err.Extensions = map[string]interface{}{"code": resolvers.ErrCodeInvalidInput}
+		return err
+	}
+
+	if _, ok := e.Manifest.Lookup(hash); !ok {
+		return operationNotAllowed(hash, rawParams.OperationName)
+	}
+
+	return nil
+}
+
+// persistedQueryHash extracts extensions.persistedQuery.sha256Hash from the request, if present
+func persistedQueryHash(extensions map[string]interface{}) string {
+	persistedQuery, ok := extensions["persistedQuery"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	hash, _ := persistedQuery["sha256Hash"].(string)
+	return hash
+}
+
+// operationNotAllowed builds the error returned for operations missing from the manifest
+func operationNotAllowed(hash, operationName string) *gqlerror.Error {
+	log.Warn().
+		Str("query_hash", hash).
+		Str("operation_name", operationName).
+		Msg("Rejected operation not in persisted operations manifest")
+
+	err := gqlerror.Errorf("operation is not in the persisted operations manifest")
+	err.Extensions = map[string]interface{}{"code": resolvers.ErrCodeOperationNotAllowed}
+	return err
+}
@@ -0,0 +1,60 @@
+package persisted
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// ExtractOperations parses a .graphql document and returns one normalized query text per operation
+// Each operation text carries only the fragments it (transitively) uses, so it validates on its own
+func ExtractOperations(name, content string) ([]string, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Name: name, Input: content})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	queries := make([]string, 0, len(doc.Operations))
+	for _, operation := range doc.Operations {
+		operationDoc := &ast.QueryDocument{
+			Operations: ast.OperationList{operation},
+		}
+
+		used := map[string]bool{}
+		collectFragments(operation.SelectionSet, doc.Fragments, used)
+		for _, fragment := range doc.Fragments {
+			if used[fragment.Name] {
+				operationDoc.Fragments = append(operationDoc.Fragments, fragment)
+			}
+		}
+
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf, formatter.WithoutDescription()).FormatQueryDocument(operationDoc)
+		queries = append(queries, buf.String())
+	}
+
+	return queries, nil
+}
+
+// collectFragments marks every fragment reachable from the selection set as used
+func collectFragments(selectionSet ast.SelectionSet, fragments ast.FragmentDefinitionList, used map[string]bool) {
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			collectFragments(s.SelectionSet, fragments, used)
+		case *ast.InlineFragment:
+			collectFragments(s.SelectionSet, fragments, used)
+		case *ast.FragmentSpread:
+			if used[s.Name] {
+				continue
+			}
+			used[s.Name] = true
+			if fragment := fragments.ForName(s.Name); fragment != nil {
+				collectFragments(fragment.SelectionSet, fragments, used)
+			}
+		}
+	}
+}
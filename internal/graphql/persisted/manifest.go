@@ -0,0 +1,66 @@
+package persisted
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Manifest maps SHA-256 query hashes to the vetted operation text allowed in production
+type Manifest struct {
+	operations map[string]string
+}
+
+// NewManifest creates a manifest from hash → query pairs
+func NewManifest(operations map[string]string) *Manifest {
+	if operations == nil {
+		operations = map[string]string{}
+	}
+	return &Manifest{operations: operations}
+}
+
+// LoadManifest reads a JSON manifest ({"<sha256>": "<query>"}) from disk
+// Returns error if the file cannot be read or an entry does not match its hash
+func LoadManifest(path string) (*Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read persisted operations manifest: %w", err)
+	}
+
+	var operations map[string]string
+	if err := json.Unmarshal(content, &operations); err != nil {
+		return nil, fmt.Errorf("failed to parse persisted operations manifest: %w", err)
+	}
+
+	for hash, query := range operations {
+		if Hash(query) != hash {
+			return nil, fmt.Errorf("persisted operations manifest entry %s does not match its query hash", hash)
+		}
+	}
+
+	return NewManifest(operations), nil
+}
+
+// Lookup returns the operation text stored under the given hash
+func (m *Manifest) Lookup(hash string) (string, bool) {
+	query, ok := m.operations[hash]
+	return query, ok
+}
+
+// Len returns the number of operations in the manifest
+func (m *Manifest) Len() int {
+	return len(m.operations)
+}
+
+// Operations returns the hash → query pairs of the manifest
+func (m *Manifest) Operations() map[string]string {
+	return m.operations
+}
+
+// Hash returns the hex-encoded SHA-256 hash identifying a query text
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package resolvers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A boolean missing from a document often means false to the business: a customer without
+// isShared is not shared. Filters on the fields listed here treat a missing field as false, so
+// eq: false and neq: true match documents without it and neq: false does not. Other boolean
+// fields keep MongoDB semantics, where a missing field matches neq conditions only
+
+// booleanFilterFields are the boolean fields of each entity's search filter
+var booleanFilterFields = map[string][]string{
+	"customer": {"isShared"},
+	"team":     {"isShared"},
+}
+
+// DefaultAbsentAsFalseFields are the boolean filter fields treating a missing field as false
+var DefaultAbsentAsFalseFields = []string{"customer.isShared", "team.isShared"}
+
+// absentAsFalseFields holds the entity.field entries treating a missing field as false
+var absentAsFalseFields = toSet(DefaultAbsentAsFalseFields)
+
+// SetAbsentAsFalseFields sets the boolean filter fields treating a missing field as false, as
+// entity.field entries (e.g. customer.isShared). Returns an error for an entry that is not a
+// boolean filter field. Must be called before serving queries
+func SetAbsentAsFalseFields(entries []string) error {
+	for _, entry := range entries {
+		entity, field, ok := strings.Cut(entry, ".")
+		if !ok || entity == "" || field == "" {
+			return fmt.Errorf("%q is not an entity.field entry", entry)
+		}
+		fields, ok := booleanFilterFields[entity]
+		if !ok {
+			return fmt.Errorf("%q: %s filters have no boolean fields", entry, entity)
+		}
+		if !slices.Contains(fields, field) {
+			return fmt.Errorf("%q: boolean filter fields of %s: %s", entry, entity, strings.Join(fields, ", "))
+		}
+	}
+	absentAsFalseFields = toSet(entries)
+	return nil
+}
+
+// treatsAbsentAsFalse reports whether filters on an entity's boolean field treat a missing field as false
+func treatsAbsentAsFalse(entity, field string) bool {
+	return absentAsFalseFields[entity+"."+field]
+}
+
+func toSet(entries []string) map[string]bool {
+	set := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		set[entry] = true
+	}
+	return set
+}
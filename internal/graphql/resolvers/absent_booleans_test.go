@@ -0,0 +1,82 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// useAbsentAsFalseFields sets the fields for the rest of a test
+func useAbsentAsFalseFields(t *testing.T, entries []string) {
+	t.Helper()
+	require.NoError(t, SetAbsentAsFalseFields(entries))
+	t.Cleanup(func() { require.NoError(t, SetAbsentAsFalseFields(DefaultAbsentAsFalseFields)) })
+}
+
+// TestConvertBooleanFilter_AbsentDocuments tests which of a shared, a not shared and a customer or
+// team without isShared each isShared condition matches, with and without absent-as-false
+func TestConvertBooleanFilter_AbsentDocuments(t *testing.T) {
+	documents := map[string]bson.M{
+		"present-true":  {"isShared": true},
+		"present-false": {"isShared": false},
+		"absent":        {},
+	}
+	yes, no := true, false
+	tests := []struct {
+		name          string
+		filter        *generated.BooleanFilterInput
+		absentAsFalse []string
+		absentIgnored []string
+	}{
+		{"eq true", &generated.BooleanFilterInput{Eq: &yes}, []string{"present-true"}, []string{"present-true"}},
+		{"eq false", &generated.BooleanFilterInput{Eq: &no}, []string{"absent", "present-false"}, []string{"present-false"}},
+		{"neq true", &generated.BooleanFilterInput{Neq: &yes}, []string{"absent", "present-false"}, []string{"absent", "present-false"}},
+		{"neq false", &generated.BooleanFilterInput{Neq: &no}, []string{"present-true"}, []string{"absent", "present-true"}},
+	}
+
+	matching := func(filter bson.M) []string {
+		var names []string
+		for name, document := range documents {
+			if parityMatches(document, filter) {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useAbsentAsFalseFields(t, DefaultAbsentAsFalseFields)
+			assert.ElementsMatch(t, tt.absentAsFalse, matching(convertCustomerFilter(&generated.CustomerQueryFilterInput{IsShared: tt.filter})))
+			assert.ElementsMatch(t, tt.absentAsFalse, matching(convertTeamFilter(&generated.TeamQueryFilterInput{IsShared: tt.filter})))
+
+			useAbsentAsFalseFields(t, nil)
+			assert.ElementsMatch(t, tt.absentIgnored, matching(convertCustomerFilter(&generated.CustomerQueryFilterInput{IsShared: tt.filter})))
+			assert.ElementsMatch(t, tt.absentIgnored, matching(convertTeamFilter(&generated.TeamQueryFilterInput{IsShared: tt.filter})))
+		})
+	}
+}
+
+// TestConvertBooleanFilter_PerField tests the setting applies to the listed entity only
+func TestConvertBooleanFilter_PerField(t *testing.T) {
+	useAbsentAsFalseFields(t, []string{"team.isShared"})
+	no := false
+	filter := &generated.BooleanFilterInput{Eq: &no}
+
+	assert.Equal(t, bson.M{"isShared": false}, convertCustomerFilter(&generated.CustomerQueryFilterInput{IsShared: filter}))
+	assert.Equal(t,
+		bson.M{"$or": []bson.M{{"isShared": false}, {"isShared": bson.M{"$exists": false}}}},
+		convertTeamFilter(&generated.TeamQueryFilterInput{IsShared: filter}))
+}
+
+func TestSetAbsentAsFalseFields(t *testing.T) {
+	useAbsentAsFalseFields(t, DefaultAbsentAsFalseFields)
+
+	assert.ErrorContains(t, SetAbsentAsFalseFields([]string{"isShared"}), "not an entity.field entry")
+	assert.ErrorContains(t, SetAbsentAsFalseFields([]string{"employee.isShared"}), "employee filters have no boolean fields")
+	assert.ErrorContains(t, SetAbsentAsFalseFields([]string{"customer.firstName"}), "boolean filter fields of customer: isShared")
+	assert.True(t, treatsAbsentAsFalse("customer", "isShared"), "a rejected setting keeps the previous one")
+}
@@ -0,0 +1,369 @@
+package resolvers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// Data-protection officers need a record of which principal read which entity. With the access
+// audit enabled for an entity, its Get, byKeys and search queries and its exports queue an event
+// naming the caller and the entities returned. A writer goroutine inserts the events in batches into the
+// access_audit collection, whose TTL index removes them after the retention period. Reads never
+// wait for the audit: when the queue is full an event is dropped and counted, so the audit cannot
+// slow down or fail serving
+
+// accessAuditCollection holds the recorded reads
+const accessAuditCollection = "access_audit"
+
+// accessAuditShutdownTimeout bounds the final write of the events queued at shutdown
+const accessAuditShutdownTimeout = 5 * time.Second
+
+// AccessAuditOptions configures the access audit
+type AccessAuditOptions struct {
+	Entities      []string      // Entities (entityConfigs names) whose reads are recorded; none disables the audit
+	QueueSize     int           // Events held for the writer; further events are dropped
+	BatchSize     int           // Events inserted per write
+	FlushInterval time.Duration // Longest time a queued event waits for its batch to fill
+}
+
+// AccessAuditStats counts the events of the access audit since it was configured
+type AccessAuditStats struct {
+	Written int64 // Events inserted
+	Dropped int64 // Events dropped because the queue was full
+	Failed  int64 // Events lost to failed inserts that could not be queued for replay
+}
+
+// accessAuditSink queues the events of the audited entities for the writer
+type accessAuditSink struct {
+	entities      map[string]bool
+	events        chan bson.M
+	batchSize     int
+	flushInterval time.Duration
+	written       atomic.Int64
+	dropped       atomic.Int64
+	failed        atomic.Int64
+}
+
+// accessAudit is the configured access audit; nil while it is disabled
+var accessAudit atomic.Pointer[accessAuditSink]
+
+// accessAuditConfig describes the audit collection to searchEntities. Entries are never deleted,
+// so the deletion exclusion matches every entry. Entry identifiers are UUIDv7, so identifier
+// descending is newest first
+var accessAuditConfig = EntityConfig{
+	CollectionName: accessAuditCollection,
+	DeletionField:  "status.deletion",
+	DeletionValue:  "DELETED",
+	DefaultSort:    []SortField{{Field: "identifier", Direction: generated.SortEnumTypeDesc}},
+	FilterConverter: func(filter interface{}) bson.M {
+		if f, ok := filter.(*generated.AccessAuditFilterInput); ok {
+			return convertAccessAuditFilter(f)
+		}
+		return bson.M{}
+	},
+}
+
+// SetAccessAudit configures the access audit of the given entities, replacing the current
+// configuration and its counters. No entities disable it. Run RunAccessAuditWriter afterwards to
+// write the queued events
+func SetAccessAudit(opts AccessAuditOptions) error {
+	for _, entity := range opts.Entities {
+		if _, ok := entityConfigs[entity]; !ok {
+			return fmt.Errorf("unknown entity %q", entity)
+		}
+	}
+	if len(opts.Entities) == 0 {
+		accessAudit.Store(nil)
+		return nil
+	}
+	if opts.QueueSize < 1 || opts.BatchSize < 1 {
+		return fmt.Errorf("access audit queue and batch sizes must be positive, got %d and %d", opts.QueueSize, opts.BatchSize)
+	}
+	if opts.FlushInterval <= 0 {
+		return fmt.Errorf("access audit flush interval must be positive, got %s", opts.FlushInterval)
+	}
+
+	accessAudit.Store(&accessAuditSink{
+		entities:      toSet(opts.Entities),
+		events:        make(chan bson.M, opts.QueueSize),
+		batchSize:     opts.BatchSize,
+		flushInterval: opts.FlushInterval,
+	})
+	return nil
+}
+
+// CurrentAccessAuditStats returns the counters of the access audit, and false while it is disabled
+func CurrentAccessAuditStats() (AccessAuditStats, bool) {
+	sink := accessAudit.Load()
+	if sink == nil {
+		return AccessAuditStats{}, false
+	}
+	return AccessAuditStats{
+		Written: sink.written.Load(),
+		Dropped: sink.dropped.Load(),
+		Failed:  sink.failed.Load(),
+	}, true
+}
+
+// AccessAuditIndexes returns the indexes of the audit collection: lookups by accessed entity and
+// a TTL index removing entries after the retention period
+func AccessAuditIndexes(retention time.Duration) []db.IndexSpec {
+	return []db.IndexSpec{
+		{
+			Collection: accessAuditCollection,
+			Name:       "identifiers_identifier",
+			Keys:       bson.D{{Key: "identifiers", Value: 1}, {Key: "identifier", Value: -1}},
+		},
+		{
+			Collection:  accessAuditCollection,
+			Name:        "accessedAt_ttl",
+			Keys:        bson.D{{Key: "accessedAt", Value: 1}},
+			ExpireAfter: retention,
+		},
+	}
+}
+
+// recordAccess queues an audit event for a read of config's entity that returned result, a
+// pointer to an entity or to a slice of entities. filter is the search filter input, nil for
+// other queries. Reads returning no entity are not recorded. The event is dropped when the queue
+// is full
+func recordAccess(ctx context.Context, config EntityConfig, queryType generated.AccessQueryType, filter, result interface{}) {
+	sink := accessAudit.Load()
+	if sink == nil {
+		return
+	}
+	entity := entityName(config)
+	if !sink.entities[entity] {
+		return
+	}
+	identifiers := resultIdentifiers(result)
+	if len(identifiers) == 0 {
+		return
+	}
+
+	entry, err := uuid.NewV7()
+	if err != nil {
+		sink.failed.Add(1)
+		return
+	}
+	event := bson.M{
+		"_id":         primitive.NewObjectID(), // Set up front, so a replayed insert cannot store the event twice
+		"identifier":  entry.String(),
+		"entity":      entity,
+		"queryType":   string(queryType),
+		"identifiers": identifiers,
+		"accessedAt":  queryClock.Now().UTC().Truncate(time.Millisecond),
+	}
+	if query := resolverName(ctx); query != "" {
+		event["query"] = query
+	}
+	if claims := getUserClaims(ctx); claims != nil && claims.UserID != "" {
+		event["principal"] = claims.UserID
+	}
+	if digest := filterDigest(filter); digest != "" {
+		event["filterDigest"] = digest
+	}
+	if requestID := middleware.RequestIDFromContext(ctx); requestID != "" {
+		event["requestId"] = requestID
+	}
+
+	select {
+	case sink.events <- event:
+	default:
+		sink.dropped.Add(1)
+	}
+}
+
+// resultIdentifiers returns the non-empty identifiers of a pointer to an entity or to a slice of
+// entities, which may be interface values
+func resultIdentifiers(result interface{}) []string {
+	value := reflect.Indirect(reflect.ValueOf(result))
+	identifierOf := func(entity reflect.Value) string {
+		if entity.Kind() == reflect.Interface {
+			entity = entity.Elem()
+		}
+		entity = reflect.Indirect(entity)
+		if entity.Kind() != reflect.Struct {
+			return ""
+		}
+		if identifier := entity.FieldByName("Identifier"); identifier.Kind() == reflect.String {
+			return identifier.String()
+		}
+		return ""
+	}
+
+	var identifiers []string
+	switch value.Kind() {
+	case reflect.Struct:
+		if identifier := identifierOf(value); identifier != "" {
+			identifiers = append(identifiers, identifier)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if identifier := identifierOf(value.Index(i)); identifier != "" {
+				identifiers = append(identifiers, identifier)
+			}
+		}
+	}
+	return identifiers
+}
+
+// filterDigest returns the hex SHA-256 of a search filter input as JSON, or "" without a filter
+func filterDigest(filter interface{}) string {
+	value := reflect.ValueOf(filter)
+	if !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		return ""
+	}
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// RunAccessAuditWriter inserts the queued access events in batches until ctx ends, then writes
+// the events still queued. A batch is written once full or FlushInterval after the previous
+// write. Batches that fail to insert are logged and queued for replay (see SetWriteRetryQueue);
+// without a retry queue their events are lost and counted as failed. Returns at once while the
+// audit is disabled
+func RunAccessAuditWriter(ctx context.Context, client DBClient, logger zerolog.Logger) {
+	sink := accessAudit.Load()
+	if sink == nil {
+		return
+	}
+
+	batch := make([]interface{}, 0, sink.batchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		collection := client.Collection(accessAuditCollection)
+		if _, err := collection.InsertMany(ctx, batch); err != nil {
+			events := append([]interface{}{}, batch...)
+			queued := QueueWriteRetry("access_audit", func(ctx context.Context) error {
+				if err := insertOnce(ctx, collection, events); err != nil {
+					return err
+				}
+				sink.written.Add(int64(len(events)))
+				return nil
+			})
+			if !queued {
+				sink.failed.Add(int64(len(batch)))
+			}
+			logger.Error().Err(err).Int("events", len(batch)).Bool("queued", queued).Msg("Failed to write access audit events")
+		} else {
+			sink.written.Add(int64(len(batch)))
+		}
+		batch = batch[:0]
+	}
+
+	ticker := time.NewTicker(sink.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event := <-sink.events:
+			batch = append(batch, event)
+			if len(batch) >= sink.batchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), accessAuditShutdownTimeout)
+			defer cancel()
+			for {
+				select {
+				case event := <-sink.events:
+					batch = append(batch, event)
+					if len(batch) >= sink.batchSize {
+						flush(shutdownCtx)
+					}
+				default:
+					flush(shutdownCtx)
+					return
+				}
+			}
+		}
+	}
+}
+
+// accessAuditFilterFields lists the fields of the audit filter
+func accessAuditFilterFields() []fieldFilter[generated.AccessAuditFilterInput] {
+	type input = generated.AccessAuditFilterInput
+	fields := []fieldFilter[input]{
+		filterField("entity", func(f *input) bson.M { return convertStringFilter("entity", f.Entity) }),
+		filterField("principal", func(f *input) bson.M { return convertStringFilter("principal", f.Principal) }),
+		filterField("identifiers", func(f *input) bson.M { return convertComparableFilterGUID("identifiers", f.Identifiers) }),
+		filterField("requestId", func(f *input) bson.M { return convertStringFilter("requestId", f.RequestID) }),
+		filterField("accessedAt", func(f *input) bson.M { return convertComparableFilterDateTime("accessedAt", f.AccessedAt) }),
+	}
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertAccessAuditFilter,
+	)...)
+}
+
+// convertAccessAuditFilter converts an AccessAuditFilterInput to a MongoDB filter
+func convertAccessAuditFilter(filter *generated.AccessAuditFilterInput) bson.M {
+	return convertFields(filter, accessAuditFilterFields())
+}
+
+// resolveAccessAuditSearch implements the accessAuditSearch query resolver. Admin only
+func resolveAccessAuditSearch(ctx context.Context, dbClient DBClient, where *generated.AccessAuditFilterInput, first *int64, after *string) (*generated.QueryOutputOfAccessAuditEntry, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	firstInt, _, err := pageSizeArgs(first, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*generated.AccessAuditEntry
+	count, totalCount, hasNextPage, hasPreviousPage, startCursor, endCursor, err := searchEntities(
+		ctx,
+		dbClient,
+		accessAuditConfig,
+		where,
+		nil,
+		SearchPaging{First: firstInt, After: after},
+		SearchOptions{},
+		&entries,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []*generated.AccessAuditEntry{}
+	}
+	return &generated.QueryOutputOfAccessAuditEntry{
+		Count: int64(count),
+		Data:  entries,
+		Paging: &generated.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: hasPreviousPage,
+			StartCursor:     startCursor,
+			EndCursor:       endCursor,
+		},
+		TotalCount:        int64(totalCount),
+		TotalCountAtStart: totalCountAtStart(after, nil, totalCount),
+	}, nil
+}
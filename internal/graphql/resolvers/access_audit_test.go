@@ -0,0 +1,197 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// fakeAuditDB records the documents inserted into the audit collection
+type fakeAuditDB struct {
+	mu       sync.Mutex
+	inserted []bson.M
+	batches  int
+	err      error
+}
+
+func (f *fakeAuditDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f *fakeAuditDB) Collection(name string) db.Collection                       { return &fakeAuditCollection{db: f} }
+func (f *fakeAuditDB) ReadCollection(name string) db.Collection                   { return f.Collection(name) }
+func (f *fakeAuditDB) IsConnected() bool                                          { return true }
+
+func (f *fakeAuditDB) events() []bson.M {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]bson.M{}, f.inserted...)
+}
+
+type fakeAuditCollection struct {
+	db.Collection
+	db *fakeAuditDB
+}
+
+func (c *fakeAuditCollection) InsertMany(ctx context.Context, documents []interface{}) (*mongo.InsertManyResult, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if c.db.err != nil {
+		return nil, c.db.err
+	}
+	c.db.batches++
+	for _, document := range documents {
+		c.db.inserted = append(c.db.inserted, document.(bson.M))
+	}
+	return &mongo.InsertManyResult{}, nil
+}
+
+// enableAccessAudit configures the access audit for the test and disables it afterwards
+func enableAccessAudit(t *testing.T, opts AccessAuditOptions) {
+	t.Helper()
+	require.NoError(t, SetAccessAudit(opts))
+	t.Cleanup(func() { require.NoError(t, SetAccessAudit(AccessAuditOptions{})) })
+}
+
+// writeAccessAudit runs the writer until the queued events are written
+func writeAccessAudit(auditDB *fakeAuditDB) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	RunAccessAuditWriter(ctx, auditDB, zerolog.Nop())
+}
+
+// Test Get, byKeys and search each record the principal, the returned identifiers and the request
+func TestAccessAudit_RecordsEachQueryType(t *testing.T) {
+	enableAccessAudit(t, AccessAuditOptions{Entities: []string{"customer"}, QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	ctx := WithUserClaims(context.Background(), &UserClaims{UserID: "dpo", Roles: []string{"ADMIN"}})
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, "req-1")
+
+	config := entityConfigs["customer"]
+	dbClient := &fakeEntityDB{documents: map[string][]bson.M{config.CollectionName: {
+		{"identifier": parityActiveID, "firstName": "Ada", "status": bson.M{"deletion": "INIT"}},
+	}}}
+
+	var customer generated.Customer
+	require.NoError(t, getEntity(ctx, dbClient, config, parityActiveID, &customer))
+	var byKeys []*generated.Customer
+	require.NoError(t, getEntitiesByKeys(ctx, dbClient, config, []string{parityActiveID}, nil, &byKeys))
+	name := "Ada"
+	where := &generated.CustomerQueryFilterInput{FirstName: &generated.StringFilterInput{Eq: &name}}
+	var found []*generated.Customer
+	_, _, _, _, _, _, err := searchEntities(ctx, dbClient, config, where, nil, SearchPaging{}, SearchOptions{}, &found)
+	require.NoError(t, err)
+
+	auditDB := &fakeAuditDB{}
+	writeAccessAudit(auditDB)
+	events := auditDB.events()
+	require.Len(t, events, 3)
+
+	for i, queryType := range []string{"GET", "BY_KEYS", "SEARCH"} {
+		event := events[i]
+		assert.Equal(t, queryType, event["queryType"])
+		assert.Equal(t, "customer", event["entity"])
+		assert.Equal(t, "dpo", event["principal"])
+		assert.Equal(t, "req-1", event["requestId"])
+		assert.Equal(t, []string{parityActiveID}, event["identifiers"])
+		assert.IsType(t, time.Time{}, event["accessedAt"], "the TTL index needs a BSON date")
+	}
+	assert.NotContains(t, events[0], "filterDigest")
+	assert.Len(t, events[2]["filterDigest"], 64, "searches record the SHA-256 of their filter")
+	assert.Equal(t, filterDigest(where), events[2]["filterDigest"])
+
+	stats, ok := CurrentAccessAuditStats()
+	require.True(t, ok)
+	assert.Equal(t, AccessAuditStats{Written: 3}, stats)
+}
+
+// Test reads of other entities and reads returning nothing are not recorded
+func TestAccessAudit_SkipsUnauditedAndEmptyReads(t *testing.T) {
+	enableAccessAudit(t, AccessAuditOptions{Entities: []string{"customer"}, QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+
+	recordAccess(context.Background(), entityConfigs["team"], generated.AccessQueryTypeGet, nil, &generated.TeamQueryOutput{Identifier: parityActiveID})
+	recordAccess(context.Background(), entityConfigs["customer"], generated.AccessQueryTypeGet, nil, &generated.Customer{})
+	recordAccess(context.Background(), entityConfigs["customer"], generated.AccessQueryTypeSearch, nil, &[]*generated.Customer{})
+
+	auditDB := &fakeAuditDB{}
+	writeAccessAudit(auditDB)
+	assert.Empty(t, auditDB.events())
+}
+
+// Test a full queue drops and counts events without blocking the read
+func TestAccessAudit_SaturatedQueueDrops(t *testing.T) {
+	enableAccessAudit(t, AccessAuditOptions{Entities: []string{"customer"}, QueueSize: 2, BatchSize: 10, FlushInterval: time.Hour})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			recordAccess(context.Background(), entityConfigs["customer"], generated.AccessQueryTypeGet, nil, &generated.Customer{Identifier: parityActiveID})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("recording blocked on a full queue")
+	}
+
+	stats, _ := CurrentAccessAuditStats()
+	assert.Equal(t, int64(3), stats.Dropped)
+
+	auditDB := &fakeAuditDB{}
+	writeAccessAudit(auditDB)
+	assert.Len(t, auditDB.events(), 2)
+}
+
+// Test the writer inserts full batches as they fill and counts failed inserts
+func TestRunAccessAuditWriter_Batches(t *testing.T) {
+	enableAccessAudit(t, AccessAuditOptions{Entities: []string{"customer"}, QueueSize: 10, BatchSize: 2, FlushInterval: time.Hour})
+	for i := 0; i < 5; i++ {
+		recordAccess(context.Background(), entityConfigs["customer"], generated.AccessQueryTypeGet, nil, &generated.Customer{Identifier: parityActiveID})
+	}
+
+	auditDB := &fakeAuditDB{}
+	writeAccessAudit(auditDB)
+	assert.Len(t, auditDB.events(), 5)
+	assert.Equal(t, 3, auditDB.batches)
+
+	recordAccess(context.Background(), entityConfigs["customer"], generated.AccessQueryTypeGet, nil, &generated.Customer{Identifier: parityActiveID})
+	writeAccessAudit(&fakeAuditDB{err: errors.New("write failed")})
+	stats, _ := CurrentAccessAuditStats()
+	assert.Equal(t, AccessAuditStats{Written: 5, Failed: 1}, stats)
+}
+
+func TestSetAccessAudit_Validation(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, SetAccessAudit(AccessAuditOptions{})) })
+
+	err := SetAccessAudit(AccessAuditOptions{Entities: []string{"customers"}, QueueSize: 1, BatchSize: 1, FlushInterval: time.Second})
+	assert.EqualError(t, err, `unknown entity "customers"`)
+	err = SetAccessAudit(AccessAuditOptions{Entities: []string{"customer"}, QueueSize: 0, BatchSize: 1, FlushInterval: time.Second})
+	assert.Error(t, err)
+
+	require.NoError(t, SetAccessAudit(AccessAuditOptions{}))
+	_, enabled := CurrentAccessAuditStats()
+	assert.False(t, enabled)
+}
+
+func TestResolveAccessAuditSearch_RequiresAdmin(t *testing.T) {
+	ctx := WithUserClaims(context.Background(), &UserClaims{UserID: "user"})
+	_, err := resolveAccessAuditSearch(ctx, &fakeAuditDB{}, nil, nil, nil)
+	require.Error(t, err)
+}
+
+func TestConvertAccessAuditFilter(t *testing.T) {
+	identifier := parityActiveID
+	filter := convertAccessAuditFilter(&generated.AccessAuditFilterInput{
+		Identifiers: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &identifier},
+	})
+	assert.Equal(t, bson.M{"identifiers": identifier}, filter)
+}
@@ -0,0 +1,118 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+const (
+	indicatorUpdatedID = "6f1c2d3e-4a5b-4c6d-8e7f-001122334460"
+	indicatorLegacyID  = "6f1c2d3e-4a5b-4c6d-8e7f-001122334461"
+	indicatorNumberID  = "6f1c2d3e-4a5b-4c6d-8e7f-001122334462"
+)
+
+// indicatorDB holds inventories with known, deleted and legacy actionIndicator values
+func indicatorDB() *fakeEntityDB {
+	return &fakeEntityDB{documents: map[string][]bson.M{"inventories": {
+		{"identifier": parityActiveID, "actionIndicator": "NONE"},
+		{"identifier": parityDeletedID, "actionIndicator": "DELETE"},
+		{"identifier": indicatorUpdatedID, "actionIndicator": "UPDATE"},
+		{"identifier": indicatorLegacyID, "actionIndicator": "ARCHIVED"},
+		{"identifier": indicatorNumberID, "actionIndicator": int32(2)},
+	}}}
+}
+
+func indicatorAdmin() context.Context {
+	return WithUserClaims(context.Background(), &UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+}
+
+// searchInventoryIndicators searches inventories with filter and returns their identifiers and indicators
+func searchInventoryIndicators(t *testing.T, filter *generated.InventoryQueryFilterInput) map[string]generated.ActionIndicator {
+	t.Helper()
+	var inventories []*generated.Inventory
+	_, _, _, _, _, _, err := searchEntities(indicatorAdmin(), indicatorDB(), entityConfigs["inventory"], filter, nil, SearchPaging{}, SearchOptions{}, &inventories)
+	require.NoError(t, err)
+	indicators := map[string]generated.ActionIndicator{}
+	for _, inventory := range inventories {
+		indicators[inventory.Identifier] = inventory.ActionIndicator
+	}
+	return indicators
+}
+
+func indicatorFilter(filter generated.EnumFilterOfActionIndicatorInput) *generated.InventoryQueryFilterInput {
+	return &generated.InventoryQueryFilterInput{ActionIndicator: &filter}
+}
+
+func indicatorPtr(indicator generated.ActionIndicator) *generated.ActionIndicator {
+	return &indicator
+}
+
+// Test legacy values read as UNKNOWN in searches and byKeys instead of failing the page
+func TestInventoryActionIndicator_LegacyValuesReadAsUnknown(t *testing.T) {
+	assert.Equal(t, map[string]generated.ActionIndicator{
+		parityActiveID:     generated.ActionIndicatorNone,
+		indicatorUpdatedID: generated.ActionIndicatorUpdate,
+		indicatorLegacyID:  generated.ActionIndicatorUnknown,
+		indicatorNumberID:  generated.ActionIndicatorUnknown,
+	}, searchInventoryIndicators(t, nil))
+
+	var inventories []*generated.Inventory
+	ids := []string{indicatorLegacyID, indicatorNumberID, parityDeletedID}
+	require.NoError(t, getEntitiesByKeys(indicatorAdmin(), indicatorDB(), entityConfigs["inventory"], ids, nil, &inventories))
+	require.Len(t, inventories, 2)
+	for _, inventory := range inventories {
+		assert.Equal(t, generated.ActionIndicatorUnknown, inventory.ActionIndicator)
+	}
+}
+
+func TestInventoryActionIndicator_Filter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter generated.EnumFilterOfActionIndicatorInput
+		want   []string
+	}{
+		{"eq", generated.EnumFilterOfActionIndicatorInput{Eq: indicatorPtr(generated.ActionIndicatorUpdate)}, []string{indicatorUpdatedID}},
+		{"eq unknown", generated.EnumFilterOfActionIndicatorInput{Eq: indicatorPtr(generated.ActionIndicatorUnknown)}, []string{indicatorLegacyID, indicatorNumberID}},
+		{"neq unknown", generated.EnumFilterOfActionIndicatorInput{Neq: indicatorPtr(generated.ActionIndicatorUnknown)}, []string{parityActiveID, indicatorUpdatedID}},
+		{"in with unknown", generated.EnumFilterOfActionIndicatorInput{In: []generated.ActionIndicator{generated.ActionIndicatorNone, generated.ActionIndicatorUnknown}}, []string{parityActiveID, indicatorLegacyID, indicatorNumberID}},
+		{"nin deleted", generated.EnumFilterOfActionIndicatorInput{Nin: []generated.ActionIndicator{generated.ActionIndicatorDelete, generated.ActionIndicatorNone}}, []string{indicatorUpdatedID, indicatorLegacyID, indicatorNumberID}},
+		{"neq deleted", generated.EnumFilterOfActionIndicatorInput{Neq: indicatorPtr(generated.ActionIndicatorDelete)}, []string{parityActiveID, indicatorUpdatedID, indicatorLegacyID, indicatorNumberID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := []string{}
+			for identifier := range searchInventoryIndicators(t, indicatorFilter(tt.filter)) {
+				found = append(found, identifier)
+			}
+			assert.ElementsMatch(t, tt.want, found)
+		})
+	}
+}
+
+func TestConvertInventoryFilter_ActionIndicator(t *testing.T) {
+	filter := convertInventoryFilter(indicatorFilter(generated.EnumFilterOfActionIndicatorInput{Eq: indicatorPtr(generated.ActionIndicatorUnknown)}))
+	assert.Equal(t, bson.M{"actionIndicator": bson.M{"$exists": true, "$nin": knownActionIndicators}}, filter)
+}
+
+// Test conditions selecting the deletion marker are rejected, also inside and/or filters, while
+// other conditions on actionIndicator pass
+func TestValidateEntityFilter_InventoryActionIndicator(t *testing.T) {
+	config := entityConfigs["inventory"]
+
+	err := validateEntityFilter(config, indicatorFilter(generated.EnumFilterOfActionIndicatorInput{Eq: indicatorPtr(generated.ActionIndicatorDelete)}))
+	requireInvalidInput(t, err, "filter cannot select actionIndicator DELETE")
+
+	nested := &generated.InventoryQueryFilterInput{Or: []*generated.InventoryQueryFilterInput{
+		indicatorFilter(generated.EnumFilterOfActionIndicatorInput{In: []generated.ActionIndicator{generated.ActionIndicatorNone, generated.ActionIndicatorDelete}}),
+	}}
+	requireInvalidInput(t, validateEntityFilter(config, nested), "filter cannot select actionIndicator DELETE")
+
+	assert.NoError(t, validateEntityFilter(config, indicatorFilter(generated.EnumFilterOfActionIndicatorInput{Nin: []generated.ActionIndicator{generated.ActionIndicatorDelete}})))
+	assert.NoError(t, validateEntityFilter(config, indicatorFilter(generated.EnumFilterOfActionIndicatorInput{Eq: indicatorPtr(generated.ActionIndicatorNone)})))
+}
@@ -0,0 +1,122 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+const appliedFilterContextKey contextKey = "applied_filter"
+
+// WithAppliedFilter returns a context in which search results echo their final MongoDB filter
+// The filter is only returned to callers with the DEVELOPER role
+func WithAppliedFilter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, appliedFilterContextKey, true)
+}
+
+// isDeveloper reports whether the claims carry the DEVELOPER role
+func isDeveloper(claims *UserClaims) bool {
+	for _, role := range claims.Roles {
+		if role == "DEVELOPER" {
+			return true
+		}
+	}
+	return false
+}
+
+// appliedFilter renders the search filter for the result's appliedFilter field
+// Returns nil unless the debug echo was requested by a developer
+func appliedFilter(ctx context.Context, config EntityConfig, filter interface{}) *string {
+	if requested, _ := ctx.Value(appliedFilterContextKey).(bool); !requested {
+		return nil
+	}
+	claims := getUserClaims(ctx)
+	if claims == nil || !isDeveloper(claims) {
+		return nil
+	}
+
+	rendered := renderRedactedFilter(buildBaseFilter(ctx, config, filter))
+	return &rendered
+}
+
+// renderRedactedFilter renders the canonical form of a filter as JSON with values replaced by their
+// type. Field names and operators are kept so the structure can be debugged without exposing data
+func renderRedactedFilter(filter bson.M) string {
+	var buf bytes.Buffer
+	writeRedacted(&buf, db.Canonical(filter))
+	return buf.String()
+}
+
+// writeRedacted writes one canonical filter value; documents and arrays are walked, scalars are redacted
+func writeRedacted(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case bson.D:
+		buf.WriteByte('{')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, elem.Key)
+			buf.WriteByte(':')
+			writeRedacted(buf, elem.Value)
+		}
+		buf.WriteByte('}')
+	case bson.A:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeRedacted(buf, elem)
+		}
+		buf.WriteByte(']')
+	default:
+		writeJSONString(buf, redactedType(value))
+	}
+}
+
+// writeJSONString writes a JSON string without HTML escaping, so placeholders stay readable
+func writeJSONString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Drop the newline Encode appends
+}
+
+// redactedType returns the placeholder for a scalar filter value
+func redactedType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "<null>"
+	case string:
+		return "<string>"
+	case bool:
+		return "<bool>"
+	case int, int32, int64:
+		return "<int>"
+	case float32, float64, primitive.Decimal128:
+		return "<number>"
+	case time.Time, *time.Time, primitive.DateTime:
+		return "<date>"
+	case primitive.Regex:
+		return "<regex>"
+	case primitive.ObjectID:
+		return "<objectId>"
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return "<null>"
+			}
+			return redactedType(rv.Elem().Interface())
+		}
+		return "<" + rv.Kind().String() + ">"
+	}
+}
@@ -0,0 +1,86 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// Test the applied filter echo for a nested And/Or team filter
+func TestAppliedFilter_NestedFilter(t *testing.T) {
+	alice := "Alice"
+	shared := true
+	filter := &generated.TeamQueryFilterInput{
+		Or: []*generated.TeamQueryFilterInput{
+			{Name: &generated.StringFilterInput{Contains: &alice}},
+			{IsShared: &generated.BooleanFilterInput{Eq: &shared}},
+		},
+	}
+
+	ctx := WithUserClaims(WithAppliedFilter(context.Background()), &UserClaims{
+		UserID: "dev-1",
+		Roles:  []string{"DEVELOPER"},
+	})
+
+	rendered := appliedFilter(ctx, entityConfigs["team"], filter)
+	require.NotNil(t, rendered)
+
+	assert.Equal(t,
+		`{"$and":[{"status.deletion":{"$ne":"<string>"}},{"$or":[{"name":{"$options":"<string>","$regex":"<string>"}},{"isShared":"<bool>"}]}]}`,
+		*rendered)
+	assert.NotContains(t, *rendered, "Alice")
+	assert.True(t, json.Valid([]byte(*rendered)))
+}
+
+// Test the echo is only returned when requested by a developer
+func TestAppliedFilter_RequiresDebugAndDeveloper(t *testing.T) {
+	developer := &UserClaims{UserID: "dev-1", Roles: []string{"DEVELOPER"}}
+	advisor := &UserClaims{UserID: "advisor-1", Roles: []string{"ADVISOR"}}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"Developer without debug header", WithUserClaims(context.Background(), developer)},
+		{"Debug header without developer role", WithUserClaims(WithAppliedFilter(context.Background()), advisor)},
+		{"Debug header without claims", WithAppliedFilter(context.Background())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, appliedFilter(tt.ctx, entityConfigs["team"], nil))
+		})
+	}
+}
+
+// Test the renderer redacts scalar types, sorts map keys and keeps ordered documents in their order
+func TestRenderRedactedFilter(t *testing.T) {
+	filter := bson.M{
+		"zeta":       bson.M{"$gte": time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"alpha":      bson.M{"$in": []string{"secret-a", "secret-b"}},
+		"count":      int32(3),
+		"ratio":      1.5,
+		"deletedAt":  nil,
+		"nested":     bson.D{{Key: "b", Value: "x"}, {Key: "a", Value: true}},
+		"conditions": bson.A{bson.M{"y": "1", "x": int64(2)}},
+	}
+
+	expected := `{"alpha":{"$in":["<string>","<string>"]},` +
+		`"conditions":[{"x":"<int>","y":"<string>"}],` +
+		`"count":"<int>",` +
+		`"deletedAt":"<null>",` +
+		`"nested":{"b":"<string>","a":"<bool>"},` +
+		`"ratio":"<number>",` +
+		`"zeta":{"$gte":"<date>"}}`
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, expected, renderRedactedFilter(filter))
+	}
+}
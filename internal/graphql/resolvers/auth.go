@@ -2,6 +2,10 @@ package resolvers
 
 import (
 	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/logger"
 )
 
 // contextKey is a type for context keys to avoid collisions
@@ -17,6 +21,8 @@ type UserClaims struct {
 	Email       string
 	Roles       []string
 	Permissions []string
+	Teams       []string // Team assignments used for data-level scoping
+	Groups      []string // Customer group memberships used for data-level scoping
 }
 
 // requireAuth ensures the user is authenticated
@@ -24,10 +30,7 @@ type UserClaims struct {
 func requireAuth(ctx context.Context) (*UserClaims, error) {
 	claims, ok := ctx.Value(userClaimsKey).(*UserClaims)
 	if !ok || claims == nil {
-		return nil, &QueryError{
-			Message: "Authentication required",
-			Code:    ErrCodeUnauthorized,
-		}
+		return nil, newUnauthorizedError("Authentication required")
 	}
 	return claims, nil
 }
@@ -40,17 +43,44 @@ func requireAdmin(ctx context.Context) (*UserClaims, error) {
 		return nil, err
 	}
 
-	// Check if user has ADMIN role
+	if isAdmin(claims) {
+		return claims, nil
+	}
+
+	return nil, newForbiddenError("Administrator privileges required")
+}
+
+// IsAdmin reports whether the principal of the context carries the ADMIN role, for admin routes
+// outside the GraphQL endpoint
+func IsAdmin(ctx context.Context) bool {
+	claims := getUserClaims(ctx)
+	return claims != nil && isAdmin(claims)
+}
+
+// isAdmin reports whether the claims carry the ADMIN role
+func isAdmin(claims *UserClaims) bool {
 	for _, role := range claims.Roles {
 		if role == "ADMIN" || role == "ADMINISTRATOR" {
-			return claims, nil
+			return true
 		}
 	}
+	return false
+}
 
-	return nil, &QueryError{
-		Message: "Administrator privileges required",
-		Code:    ErrCodeForbidden,
+// customerAuthorizationFilter restricts customers to the groups of the caller's teams
+// Admins and contexts without claims (internal calls) are not restricted
+func customerAuthorizationFilter(ctx context.Context) bson.M {
+	claims := getUserClaims(ctx)
+	if claims == nil || isAdmin(claims) {
+		return bson.M{}
 	}
+
+	allowed := make([]string, 0, len(claims.Teams)+len(claims.Groups))
+	allowed = append(allowed, claims.Teams...)
+	allowed = append(allowed, claims.Groups...)
+
+	// An empty $in matches nothing, so callers without assignments see no customers
+	return bson.M{"customerGroups": bson.M{"$in": allowed}}
 }
 
 // getUserClaims returns the user claims from context if present, nil otherwise
@@ -60,8 +90,11 @@ func getUserClaims(ctx context.Context) *UserClaims {
 	return claims
 }
 
-// WithUserClaims returns a new context with the given user claims
+// WithUserClaims returns a new context with the given user claims, whose loggers carry the user ID
 // This is exported for use in testing
 func WithUserClaims(ctx context.Context, claims *UserClaims) context.Context {
+	if claims != nil {
+		ctx = logger.WithContextField(ctx, "user_id", claims.UserID)
+	}
 	return context.WithValue(ctx, userClaimsKey, claims)
 }
@@ -0,0 +1,67 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Test customer authorization filter for the supported principals
+func TestCustomerAuthorizationFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   *UserClaims
+		expected bson.M
+	}{
+		{
+			name:     "No claims - unrestricted",
+			claims:   nil,
+			expected: bson.M{},
+		},
+		{
+			name:     "Admin - unrestricted",
+			claims:   &UserClaims{UserID: "admin", Roles: []string{"ADMIN"}, Teams: []string{"TEAM_A"}},
+			expected: bson.M{},
+		},
+		{
+			name:     "Agent - teams and groups",
+			claims:   &UserClaims{UserID: "agent", Teams: []string{"TEAM_A"}, Groups: []string{"AIR_CUSTOMER"}},
+			expected: bson.M{"customerGroups": bson.M{"$in": []string{"TEAM_A", "AIR_CUSTOMER"}}},
+		},
+		{
+			name:     "Agent - no assignments matches nothing",
+			claims:   &UserClaims{UserID: "agent"},
+			expected: bson.M{"customerGroups": bson.M{"$in": []string{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = WithUserClaims(ctx, tt.claims)
+			}
+			assert.Equal(t, tt.expected, customerAuthorizationFilter(ctx))
+		})
+	}
+}
+
+// Test authorization filter is ANDed with the deletion filter
+func TestApplyAuthorizationFilter(t *testing.T) {
+	base := bson.M{"status.deletion": bson.M{"$ne": "DELETED"}}
+	ctx := WithUserClaims(context.Background(), &UserClaims{UserID: "agent", Teams: []string{"TEAM_A"}})
+
+	t.Run("Entity without hook", func(t *testing.T) {
+		assert.Equal(t, base, applyAuthorizationFilter(ctx, entityConfigs["employee"], base))
+	})
+
+	t.Run("Customer hook", func(t *testing.T) {
+		expected := bson.M{"$and": []bson.M{
+			base,
+			{"customerGroups": bson.M{"$in": []string{"TEAM_A"}}},
+		}}
+		assert.Equal(t, expected, applyAuthorizationFilter(ctx, entityConfigs["customer"], base))
+	})
+}
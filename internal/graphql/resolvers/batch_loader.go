@@ -0,0 +1,138 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// relationBatchWait is how long a loader collects keys before it queries.
+// gqlgen resolves the relation fields of a list concurrently, so the fields of one
+// list level arrive within this window and share a single query
+const relationBatchWait = 2 * time.Millisecond
+
+// relationMaxBatch is the maximum number of keys per loader query
+const relationMaxBatch = 1000
+
+// batchLoader collects the keys requested within a short window and loads them with one
+// fetch call. Results are cached for the lifetime of the loader, which is one operation
+type batchLoader[V any] struct {
+	fetch    func(ctx context.Context, keys []string) (map[string]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[string]V
+	pending map[string]*loaderBatch[V] // Keys queued or being fetched
+	batch   *loaderBatch[V]            // Batch still collecting keys
+}
+
+// loaderBatch is one fetch and the keys it loads
+type loaderBatch[V any] struct {
+	keys       []string
+	dispatched bool
+	done       chan struct{}
+	err        error
+}
+
+// newBatchLoader creates a loader around a fetch function
+// fetch returns the value of every key it found; missing keys load as the zero value
+func newBatchLoader[V any](fetch func(ctx context.Context, keys []string) (map[string]V, error)) *batchLoader[V] {
+	return &batchLoader[V]{
+		fetch:    fetch,
+		wait:     relationBatchWait,
+		maxBatch: relationMaxBatch,
+		cache:    map[string]V{},
+		pending:  map[string]*loaderBatch[V]{},
+	}
+}
+
+// Load returns the value of one key, batching it with keys requested concurrently
+func (l *batchLoader[V]) Load(ctx context.Context, key string) (V, error) {
+	values, err := l.LoadMany(ctx, []string{key})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return values[0], nil
+}
+
+// LoadMany returns the values of several keys in key order
+func (l *batchLoader[V]) LoadMany(ctx context.Context, keys []string) ([]V, error) {
+	batches := map[*loaderBatch[V]]bool{}
+
+	l.mu.Lock()
+	for _, key := range keys {
+		if _, ok := l.cache[key]; ok {
+			continue
+		}
+		batches[l.enqueue(ctx, key)] = true
+	}
+	l.mu.Unlock()
+
+	for batch := range batches {
+		<-batch.done
+		if batch.err != nil {
+			return nil, batch.err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	values := make([]V, len(keys))
+	for i, key := range keys {
+		values[i] = l.cache[key]
+	}
+	return values, nil
+}
+
+// enqueue adds a key to the pending batch, starting one if needed; l.mu must be held
+func (l *batchLoader[V]) enqueue(ctx context.Context, key string) *loaderBatch[V] {
+	if batch, ok := l.pending[key]; ok {
+		return batch
+	}
+
+	if l.batch == nil {
+		l.batch = &loaderBatch[V]{done: make(chan struct{})}
+		go func(batch *loaderBatch[V]) {
+			time.Sleep(l.wait)
+			l.mu.Lock()
+			l.dispatch(ctx, batch)
+			l.mu.Unlock()
+		}(l.batch)
+	}
+
+	batch := l.batch
+	batch.keys = append(batch.keys, key)
+	l.pending[key] = batch
+	if len(batch.keys) >= l.maxBatch {
+		l.dispatch(ctx, batch)
+	}
+	return batch
+}
+
+// dispatch starts a batch's fetch once; l.mu must be held. A failed fetch is not cached
+func (l *batchLoader[V]) dispatch(ctx context.Context, batch *loaderBatch[V]) {
+	if batch.dispatched {
+		return
+	}
+	batch.dispatched = true
+	if l.batch == batch {
+		l.batch = nil
+	}
+
+	go func() {
+		results, err := l.fetch(ctx, batch.keys)
+
+		l.mu.Lock()
+		for _, key := range batch.keys {
+			if err == nil {
+				l.cache[key] = results[key]
+			}
+			delete(l.pending, key)
+		}
+		batch.err = err
+		l.mu.Unlock()
+		close(batch.done)
+	}()
+}
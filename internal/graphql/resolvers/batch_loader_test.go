@@ -0,0 +1,97 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingFetch returns a fetch function that records its batches and doubles every key but "missing"
+func recordingFetch() (func(ctx context.Context, keys []string) (map[string]string, error), func() [][]string) {
+	var mu sync.Mutex
+	var batches [][]string
+	fetch := func(_ context.Context, keys []string) (map[string]string, error) {
+		mu.Lock()
+		batches = append(batches, append([]string(nil), keys...))
+		mu.Unlock()
+		values := map[string]string{}
+		for _, key := range keys {
+			if key != "missing" {
+				values[key] = key + key
+			}
+		}
+		return values, nil
+	}
+	return fetch, func() [][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+}
+
+func TestBatchLoader_BatchesConcurrentLoads(t *testing.T) {
+	fetch, batches := recordingFetch()
+	loader := newBatchLoader(fetch)
+	ctx := context.Background()
+
+	keys := []string{"a", "b", "c", "a", "missing"}
+	values := make([]string, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			value, err := loader.Load(ctx, key)
+			assert.NoError(t, err)
+			values[i] = value
+		}(i, key)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"aa", "bb", "cc", "aa", ""}, values)
+	require.Len(t, batches(), 1)
+	batch := batches()[0]
+	sort.Strings(batch)
+	assert.Equal(t, []string{"a", "b", "c", "missing"}, batch)
+
+	// Loaded keys, including missing ones, are cached
+	loaded, err := loader.LoadMany(ctx, []string{"c", "missing", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cc", "", "bb"}, loaded)
+	assert.Len(t, batches(), 1)
+}
+
+func TestBatchLoader_MaxBatch(t *testing.T) {
+	fetch, batches := recordingFetch()
+	loader := newBatchLoader(fetch)
+	loader.maxBatch = 2
+
+	values, err := loader.LoadMany(context.Background(), []string{"a", "b", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aa", "bb", "cc"}, values)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, batches())
+}
+
+func TestBatchLoader_ErrorsAreNotCached(t *testing.T) {
+	calls := 0
+	loader := newBatchLoader(func(_ context.Context, keys []string) (map[string]string, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("unavailable")
+		}
+		return map[string]string{"a": "aa"}, nil
+	})
+
+	_, err := loader.Load(context.Background(), "a")
+	require.EqualError(t, err, "unavailable")
+
+	value, err := loader.Load(context.Background(), "a")
+	require.NoError(t, err)
+	assert.Equal(t, "aa", value)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultMaxAggregateBatchSize bounds the batch size of aggregation cursors unless configured otherwise
+const DefaultMaxAggregateBatchSize = 1000
+
+// maxAggregateBatchSize bounds the batch size of aggregation cursors, so large results are
+// streamed in several batches instead of being held in memory at once
+var maxAggregateBatchSize = DefaultMaxAggregateBatchSize
+
+// SetMaxAggregateBatchSize sets the upper bound of aggregation cursor batch sizes
+// Values below 1 are ignored
+func SetMaxAggregateBatchSize(n int) {
+	if n >= 1 {
+		maxAggregateBatchSize = n
+	}
+}
+
+// aggregateBatchSize returns the cursor batch size of an aggregation expected to return up to
+// documents results: all of them when they fit within the entity's bound, so they arrive with
+// the aggregate reply instead of after the driver's default 101-document first batch and getMores
+func aggregateBatchSize(config EntityConfig, documents int) int32 {
+	bound := maxAggregateBatchSize
+	if config.AggregateBatchSize > 0 && config.AggregateBatchSize < bound {
+		bound = config.AggregateBatchSize
+	}
+	if documents < 1 {
+		documents = 1
+	}
+	if documents > bound {
+		documents = bound
+	}
+	return int32(documents)
+}
+
+// aggregateOptions returns the options of an aggregation expected to return up to documents results
+func aggregateOptions(config EntityConfig, documents int) *options.AggregateOptions {
+	return options.Aggregate().SetBatchSize(aggregateBatchSize(config, documents))
+}
@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// TestAggregateBatchSize tests the batch size covers the expected documents within the bounds
+func TestAggregateBatchSize(t *testing.T) {
+	t.Cleanup(func() { SetMaxAggregateBatchSize(DefaultMaxAggregateBatchSize) })
+
+	assert.Equal(t, int32(201), aggregateBatchSize(EntityConfig{}, 201))
+	assert.Equal(t, int32(1), aggregateBatchSize(EntityConfig{}, 0))
+	assert.Equal(t, int32(DefaultMaxAggregateBatchSize), aggregateBatchSize(EntityConfig{}, 5000))
+
+	// The entity's bound applies below the configured one
+	assert.Equal(t, int32(50), aggregateBatchSize(EntityConfig{AggregateBatchSize: 50}, 201))
+	assert.Equal(t, int32(DefaultMaxAggregateBatchSize), aggregateBatchSize(EntityConfig{AggregateBatchSize: 5000}, 5000))
+
+	SetMaxAggregateBatchSize(101)
+	assert.Equal(t, int32(101), aggregateBatchSize(EntityConfig{}, 201))
+	SetMaxAggregateBatchSize(0) // Ignored
+	assert.Equal(t, int32(101), aggregateBatchSize(EntityConfig{}, 201))
+}
+
+// TestSearchEntities_BatchSizeCoversPage tests searches read the page and its read-ahead
+// document in the first batch
+func TestSearchEntities_BatchSizeCoversPage(t *testing.T) {
+	fake := &fakeSearchDB{identifiers: []string{"a", "b", "c"}}
+	var employees []*generated.Employee
+
+	_, _, _, _, _, _, err := searchEntities(searchFieldContext(t, `{ employeeSearch { count } }`), fake, entityConfigs["employee"], nil, nil, SearchPaging{First: intRef(limits.MaxPageSize)}, SearchOptions{}, &employees)
+	require.NoError(t, err)
+	_, _, _, _, _, _, err = searchEntities(searchFieldContext(t, `{ employeeSearch { count } }`), fake, entityConfigs["employee"], nil, nil, SearchPaging{}, SearchOptions{}, &employees)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int32{int32(limits.MaxPageSize + 1), int32(limits.DefaultPageSize + 1)}, fake.batchSizes)
+}
@@ -0,0 +1,149 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// slowSearchDB answers every search with an empty page after a per-collection delay,
+// tracking how many aggregations run at once
+type slowSearchDB struct {
+	delays   map[string]time.Duration
+	running  atomic.Int32
+	maxInUse atomic.Int32
+}
+
+func (f *slowSearchDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f *slowSearchDB) Collection(name string) db.Collection {
+	return &slowSearchCollection{db: f, delay: f.delays[name]}
+}
+func (f *slowSearchDB) ReadCollection(name string) db.Collection { return f.Collection(name) }
+func (f *slowSearchDB) IsConnected() bool                        { return true }
+
+type slowSearchCollection struct {
+	db.Collection
+	db    *slowSearchDB
+	delay time.Duration
+}
+
+func (c *slowSearchCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	running := c.db.running.Add(1)
+	defer c.db.running.Add(-1)
+	for {
+		peak := c.db.maxInUse.Load()
+		if running <= peak || c.db.maxInUse.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return mongo.NewCursorFromDocuments([]interface{}{bson.M{
+		"metadata": bson.A{bson.M{"totalCount": 0}},
+		"data":     bson.A{},
+	}}, nil, nil)
+}
+
+// dashboardQuery searches three entities in one operation, as the admin dashboard does
+const dashboardQuery = `{
+	customerSearch(first: 10) { totalCount }
+	employeeSearch(first: 10) { totalCount }
+	teamSearch(first: 10) { totalCount }
+}`
+
+// runDashboardQuery executes the dashboard query and returns how long it took
+func runDashboardQuery(t *testing.T, dbClient DBClient) time.Duration {
+	t.Helper()
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: NewResolver(dbClient)}))
+	srv.AddTransport(transport.POST{})
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(WithRelationLoaders(ctx))
+	})
+
+	body, err := json.Marshal(map[string]string{"query": dashboardQuery})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	started := time.Now()
+	srv.ServeHTTP(rec, req)
+	elapsed := time.Since(started)
+
+	var resp struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []interface{}          `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Empty(t, resp.Errors)
+	require.Len(t, resp.Data, 3)
+	return elapsed
+}
+
+// newDashboardDB delays each searched collection by the given latency
+func newDashboardDB(latency time.Duration) *slowSearchDB {
+	return &slowSearchDB{delays: map[string]time.Duration{
+		entityConfigs["customer"].CollectionName: latency,
+		entityConfigs["employee"].CollectionName: latency,
+		entityConfigs["team"].CollectionName:     latency,
+	}}
+}
+
+// useSearchLimiter replaces the search limiter for the duration of a test
+func useSearchLimiter(t *testing.T, limiter *searchLimiter) {
+	t.Helper()
+	searchSlotsMu.Lock()
+	previous := searchSlots
+	searchSlots = limiter
+	searchSlotsMu.Unlock()
+	t.Cleanup(func() {
+		searchSlotsMu.Lock()
+		searchSlots = previous
+		searchSlotsMu.Unlock()
+	})
+}
+
+// Test root searches of one operation run concurrently, so the operation takes about as long
+// as its slowest search rather than the sum of all three
+func TestRootSearches_RunConcurrently(t *testing.T) {
+	const latency = 150 * time.Millisecond
+	useSearchLimiter(t, newSearchLimiter(DefaultSearchMaxConcurrentRows, time.Second))
+	dbClient := newDashboardDB(latency)
+
+	elapsed := runDashboardQuery(t, dbClient)
+	assert.Less(t, elapsed, 2*latency, "searches ran sequentially")
+	assert.Equal(t, int32(3), dbClient.maxInUse.Load())
+}
+
+// Test the search limiter still bounds root searches that run concurrently
+func TestRootSearches_BoundedByLimiter(t *testing.T) {
+	const latency = 50 * time.Millisecond
+	// Each search weighs its page of 10 rows, so only one fits at a time
+	useSearchLimiter(t, newSearchLimiter(10, time.Second))
+	dbClient := newDashboardDB(latency)
+
+	elapsed := runDashboardQuery(t, dbClient)
+	assert.GreaterOrEqual(t, elapsed, 3*latency)
+	assert.Equal(t, int32(1), dbClient.maxInUse.Load())
+}
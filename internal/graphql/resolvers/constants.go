@@ -1,5 +1,63 @@
 package resolvers
 
-// MaxBatchSize is the maximum number of identifiers allowed in a single byKeysGet request
+import (
+	"strconv"
+	"time"
+)
+
+// MaxBatchSize is the default maximum number of identifiers allowed in a single byKeysGet request
 // This limit protects system resources and ensures reasonable query performance
 const MaxBatchSize = 200
+
+// DefaultPageSize is the default page size of search queries when neither first nor last is given
+const DefaultPageSize = MaxBatchSize
+
+// DefaultMaxSortEntries is the default maximum number of entries in an order array
+const DefaultMaxSortEntries = 5
+
+// DefaultMaxFilterListValues is the default maximum number of values in a filter in/nin list
+const DefaultMaxFilterListValues = 1000
+
+// DefaultMaxFilterConditions is the default maximum number of conditions in a filter and/or list
+const DefaultMaxFilterConditions = 50
+
+// DefaultMaxFilterDepth is the default maximum nesting of filter and/or lists
+const DefaultMaxFilterDepth = 5
+
+// DefaultMaxFilterBytes is the default maximum estimated serialized size of a filter
+const DefaultMaxFilterBytes = 64 * 1024
+
+// DefaultMaxRelationSize is the default maximum number of entries of a relation field
+const DefaultMaxRelationSize = 100
+
+// DefaultMaxQueryComplexity is the default maximum complexity of an operation
+// Two relation hops at full size stay below it; a third needs smaller first arguments
+const DefaultMaxQueryComplexity = 20000
+
+// DefaultSearchMaxConcurrentRows is the default number of rows concurrently running searches may
+// request: five full pages, leaving most of a 10-20 connection pool to point reads
+const DefaultSearchMaxConcurrentRows = 5 * MaxBatchSize
+
+// DefaultSearchQueueTimeout is the default longest wait of a search for capacity
+const DefaultSearchQueueTimeout = 2 * time.Second
+
+// SchemaDescriptionValues returns the values substituted for {{Name}} placeholders in
+// schema descriptions, so documented limits and deletion rules follow the code
+func SchemaDescriptionValues() map[string]string {
+	current := CurrentLimits()
+	values := map[string]string{
+		"MaxBatchSize":    strconv.Itoa(current.MaxBatchSize),
+		"DefaultPageSize": strconv.Itoa(current.DefaultPageSize),
+		"MaxPageSize":     strconv.Itoa(current.MaxPageSize),
+		"MaxRelationSize": strconv.Itoa(current.MaxRelationSize),
+		"MaxFacetBuckets": strconv.Itoa(MaxFacetBuckets),
+	}
+
+	for name, config := range entityConfigs {
+		values[name+".DeletionField"] = config.DeletionField
+		values[name+".DeletionValue"] = config.DeletionValue
+		values[name+".DefaultSort"] = describeDefaultSort(config)
+	}
+
+	return values
+}
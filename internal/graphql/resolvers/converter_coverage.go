@@ -0,0 +1,124 @@
+package resolvers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// inputCoverage pairs a generated filter or sorter input type with the fields its converter maps
+type inputCoverage struct {
+	Entity    string
+	InputType reflect.Type
+	Handled   []string // JSON field names; nested object inputs use dotted paths (e.g. "status.deletion")
+}
+
+// converterCoverage lists every search input checked at startup
+// The handled fields come from the field lists the converters iterate, so they cannot drift apart
+var converterCoverage = []inputCoverage{
+	{"customer", reflect.TypeOf(generated.CustomerQueryFilterInput{}), filterFieldPaths(customerFilterFields())},
+	{"customer", reflect.TypeOf(generated.CustomerQuerySorterInput{}), sorterFieldPaths(customerSorterFields())},
+	{"employee", reflect.TypeOf(generated.EmployeeQueryFilterInput{}), filterFieldPaths(employeeFilterFields())},
+	{"employee", reflect.TypeOf(generated.EmployeeQuerySorterInput{}), sorterFieldPaths(employeeSorterFields())},
+	{"team", reflect.TypeOf(generated.TeamQueryFilterInput{}), filterFieldPaths(teamFilterFields())},
+	{"team", reflect.TypeOf(generated.TeamQuerySorterInput{}), sorterFieldPaths(teamSorterFields())},
+	{"inventory", reflect.TypeOf(generated.InventoryQueryFilterInput{}), filterFieldPaths(inventoryFilterFields())},
+	{"inventory", reflect.TypeOf(generated.InventoryQuerySorterInput{}), sorterFieldPaths(inventorySorterFields())},
+	{"executionPlan", reflect.TypeOf(generated.ExecutionPlanQueryFilterInput{}), filterFieldPaths(executionPlanFilterFields())},
+	{"executionPlan", reflect.TypeOf(generated.ExecutionPlanQuerySorterInput{}), sorterFieldPaths(executionPlanSorterFields())},
+	{"referencePortfolio", reflect.TypeOf(generated.ReferencePortfolioQueryFilterInput{}), filterFieldPaths(referencePortfolioFilterFields())},
+	{"referencePortfolio", reflect.TypeOf(generated.ReferencePortfolioQuerySorterInput{}), sorterFieldPaths(referencePortfolioSorterFields())},
+	{"accessAudit", reflect.TypeOf(generated.AccessAuditFilterInput{}), filterFieldPaths(accessAuditFilterFields())},
+}
+
+// CoverageGap reports input fields a converter silently ignores
+type CoverageGap struct {
+	Entity    string
+	Input     string
+	Unhandled []string // Schema fields the converter does not map
+	Unknown   []string // Registered fields missing from the schema type (stale registrations)
+}
+
+func (g CoverageGap) String() string {
+	parts := []string{}
+	if len(g.Unhandled) > 0 {
+		parts = append(parts, "unhandled: "+strings.Join(g.Unhandled, ", "))
+	}
+	if len(g.Unknown) > 0 {
+		parts = append(parts, "unknown: "+strings.Join(g.Unknown, ", "))
+	}
+	return fmt.Sprintf("%s %s (%s)", g.Entity, g.Input, strings.Join(parts, "; "))
+}
+
+// CheckConverterCoverage compares every search input type against the fields its converter maps
+// Returns one gap per input type that has unhandled or unknown fields
+func CheckConverterCoverage() []CoverageGap {
+	return checkCoverage(converterCoverage)
+}
+
+// checkCoverage reports gaps for the given input types
+func checkCoverage(coverages []inputCoverage) []CoverageGap {
+	gaps := []CoverageGap{}
+
+	for _, coverage := range coverages {
+		handled := make(map[string]bool, len(coverage.Handled))
+		for _, field := range coverage.Handled {
+			handled[field] = true
+		}
+
+		schemaFields := inputFieldPaths(coverage.InputType, "")
+		known := make(map[string]bool, len(schemaFields))
+
+		gap := CoverageGap{Entity: coverage.Entity, Input: coverage.InputType.Name()}
+		for _, field := range schemaFields {
+			known[field] = true
+			if !handled[field] {
+				gap.Unhandled = append(gap.Unhandled, field)
+			}
+		}
+		for _, field := range coverage.Handled {
+			if !known[field] {
+				gap.Unknown = append(gap.Unknown, field)
+			}
+		}
+
+		if len(gap.Unhandled) > 0 || len(gap.Unknown) > 0 {
+			sort.Strings(gap.Unhandled)
+			sort.Strings(gap.Unknown)
+			gaps = append(gaps, gap)
+		}
+	}
+
+	return gaps
+}
+
+// inputFieldPaths returns the JSON field names of an input type
+// Nested object inputs (e.g. CustomerStatusObjectFilterInput) are expanded into dotted paths,
+// since each of their fields needs its own mapping; operator inputs and and/or lists are leaves
+func inputFieldPaths(inputType reflect.Type, prefix string) []string {
+	paths := []string{}
+
+	for i := 0; i < inputType.NumField(); i++ {
+		field := inputType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && strings.Contains(fieldType.Name(), "Object") {
+			paths = append(paths, inputFieldPaths(fieldType, prefix+name+".")...)
+			continue
+		}
+
+		paths = append(paths, prefix+name)
+	}
+
+	return paths
+}
@@ -0,0 +1,69 @@
+package resolvers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test-only input types mirroring the shape of generated filter inputs
+type coverageTestStatusObjectFilterInput struct {
+	Creation *string `json:"creation,omitempty"`
+	Deletion *string `json:"deletion,omitempty"`
+}
+
+type coverageTestFilterInput struct {
+	And       []*coverageTestFilterInput           `json:"and,omitempty"`
+	Or        []*coverageTestFilterInput           `json:"or,omitempty"`
+	FirstName *string                              `json:"firstName,omitempty"`
+	NewField  *string                              `json:"newField,omitempty"` // Deliberately not handled
+	Status    *coverageTestStatusObjectFilterInput `json:"status,omitempty"`
+}
+
+// Test unhandled and stale fields are detected, including nested object inputs
+func TestCheckCoverage_DetectsUnhandledFields(t *testing.T) {
+	gaps := checkCoverage([]inputCoverage{{
+		Entity:    "test",
+		InputType: reflect.TypeOf(coverageTestFilterInput{}),
+		Handled:   []string{"and", "or", "firstName", "status.creation", "lastName"},
+	}})
+
+	require.Len(t, gaps, 1)
+	assert.Equal(t, "test", gaps[0].Entity)
+	assert.Equal(t, "coverageTestFilterInput", gaps[0].Input)
+	assert.Equal(t, []string{"newField", "status.deletion"}, gaps[0].Unhandled)
+	assert.Equal(t, []string{"lastName"}, gaps[0].Unknown)
+}
+
+// Test fully handled inputs report no gap
+func TestCheckCoverage_FullyHandled(t *testing.T) {
+	gaps := checkCoverage([]inputCoverage{{
+		Entity:    "test",
+		InputType: reflect.TypeOf(coverageTestFilterInput{}),
+		Handled:   []string{"and", "or", "firstName", "newField", "status.creation", "status.deletion"},
+	}})
+
+	assert.Empty(t, gaps)
+}
+
+// Test registrations only name fields that exist in the generated inputs
+func TestCheckConverterCoverage_NoStaleRegistrations(t *testing.T) {
+	for _, gap := range CheckConverterCoverage() {
+		assert.Empty(t, gap.Unknown, gap.String())
+	}
+}
+
+// Test nested object converters register the dotted paths of the fields they map
+func TestFilterFieldPaths_NestedStatusObject(t *testing.T) {
+	paths := filterFieldPaths(teamFilterFields())
+
+	assert.Subset(t, paths, []string{"status.and", "status.or", "status.creation", "status.deletion"})
+	assert.NotContains(t, paths, "status")
+}
+
+// Test the sorter coverage lists exactly the fields the converter maps
+func TestSorterFieldPaths_MatchConverter(t *testing.T) {
+	assert.Equal(t, []string{"name", "description", "isShared", "employeeId"}, sorterFieldPaths(teamSorterFields()))
+}
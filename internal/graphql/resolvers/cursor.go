@@ -1,46 +1,180 @@
 package resolvers
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/graphql/scalars"
 )
 
 // Cursor represents the internal structure of a pagination cursor
 // T004: Cursor encoding/decoding utilities for pagination
 type Cursor struct {
-	SortFields []interface{} `json:"s"` // Values of sort fields at cursor position
-	Identifier string        `json:"i"` // Entity identifier (UUID) as tiebreaker
+	SortFields []interface{}   `json:"s"`           // Values of sort fields at cursor position
+	Identifier string          `json:"i"`           // Entity identifier (UUID) as tiebreaker
+	Legacy     bool            `json:"l,omitempty"` // Identifier holds the _id hex of a legacy document without identifier
+	Sort       string          `json:"f,omitempty"` // Fingerprint of the sort order the cursor was issued for
+	Snapshot   *cursorSnapshot `json:"t,omitempty"` // Cluster time of a snapshot pagination
+	IssuedAt   int64           `json:"a,omitempty"` // Unix time the cursor was issued; searches reject it after cursorMaxAge
+	StartTotal int             `json:"n,omitempty"` // totalCount of the pagination's first page; 0 in cursors issued before it was carried
+}
+
+// DefaultCursorMaxAge is how long a cursor is accepted after it was issued unless configured otherwise
+const DefaultCursorMaxAge = 24 * time.Hour
+
+// cursorMaxAge is how long a cursor is accepted after it was issued
+var cursorMaxAge = DefaultCursorMaxAge
+
+// SetCursorMaxAge sets how long a cursor is accepted after it was issued
+// Values below 1 second are ignored
+func SetCursorMaxAge(maxAge time.Duration) {
+	if maxAge >= time.Second {
+		cursorMaxAge = maxAge
+	}
+}
+
+// cursorSigningKey is the HMAC key cursors are signed with, so clients can neither forge a
+// fresher issue time nor other values. Until SetCursorSigningSecret is called the key is random,
+// and cursors are only accepted by the process that issued them
+var cursorSigningKey = randomCursorSigningKey()
+
+// randomCursorSigningKey returns a random signing key
+func randomCursorSigningKey() []byte {
+	key := make([]byte, sha256.Size)
+	_, _ = rand.Read(key)
+	return key
+}
+
+// SetCursorSigningSecret derives the cursor signing key from a secret shared by all instances,
+// so a cursor issued by one instance is accepted by the others
+func SetCursorSigningSecret(secret string) {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("air-go cursor signing key"))
+	cursorSigningKey = mac.Sum(nil)
 }
 
-// encodeCursor serializes a Cursor to a base64-encoded JSON string
+// cursorSignature returns the signature of an encoded cursor payload
+func cursorSignature(payload string) string {
+	mac := hmac.New(sha256.New, cursorSigningKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// newCursorExpiredError returns the CURSOR_EXPIRED error of a cursor too old to be replayed
+func newCursorExpiredError() *QueryError {
+	return &QueryError{
+		Message: "cursor expired; restart pagination without a cursor",
+		Code:    ErrCodeCursorExpired,
+	}
+}
+
+// cursorSnapshot is the cluster time a snapshot pagination reads at
+type cursorSnapshot struct {
+	T uint32 `json:"t"`
+	I uint32 `json:"i"`
+}
+
+// newCursorSnapshot wraps a cluster time for a cursor
+func newCursorSnapshot(ts primitive.Timestamp) *cursorSnapshot {
+	return &cursorSnapshot{T: ts.T, I: ts.I}
+}
+
+// timestamp returns the cluster time the snapshot reads at
+func (s *cursorSnapshot) timestamp() *primitive.Timestamp {
+	if s == nil {
+		return nil
+	}
+	return &primitive.Timestamp{T: s.T, I: s.I}
+}
+
+// cursorDate carries a date sort value through the JSON cursor so it decodes back to a date
+type cursorDate struct {
+	Date string `json:"$date"`
+}
+
+// cursorSortValue wraps date sort values; other values are encoded as they are
+func cursorSortValue(value interface{}) interface{} {
+	if date, ok := value.(primitive.DateTime); ok {
+		return cursorDate{Date: scalars.FormatDateTime(date.Time())}
+	}
+	return value
+}
+
+// decodeCursorSortValue turns a wrapped date back into a time.Time, read like a DateTime input
+func decodeCursorSortValue(value interface{}) (interface{}, error) {
+	wrapped, ok := value.(map[string]interface{})
+	if !ok {
+		return value, nil
+	}
+	date, ok := wrapped["$date"].(string)
+	if !ok || len(wrapped) != 1 {
+		return value, nil
+	}
+	parsed, err := scalars.ParseDateTime(date)
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// encodeCursor serializes a Cursor issued now to a base64-encoded JSON string and its signature
 // Used to create opaque cursor strings for pagination (startCursor, endCursor)
 func encodeCursor(cursor Cursor) (string, error) {
+	cursor.IssuedAt = queryClock.Now().Unix()
+
 	// Serialize to JSON
 	jsonBytes, err := json.Marshal(cursor)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal cursor: %w", err)
 	}
 
-	// Encode to base64
+	// Encode to base64 and sign; the standard alphabet has no '.'
 	encoded := base64.StdEncoding.EncodeToString(jsonBytes)
-	return encoded, nil
+	return encoded + "." + cursorSignature(encoded), nil
 }
 
-// decodeCursor deserializes a base64-encoded cursor string back to a Cursor struct
-// Returns error if cursor format is invalid (invalid base64 or malformed JSON)
+// decodeCursor deserializes a cursor string back to a Cursor struct and checks it may be replayed
+// Returns INVALID_INPUT if the cursor format is invalid or its signature does not match, and
+// CURSOR_EXPIRED if it is older than cursorMaxAge or was issued before cursors were signed
 func decodeCursor(cursorStr string) (*Cursor, error) {
-	return DecodeCursor(cursorStr)
+	cursor, err := DecodeCursor(cursorStr)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, signature, signed := strings.Cut(cursorStr, ".")
+	if !signed || cursor.IssuedAt == 0 {
+		return nil, newCursorExpiredError()
+	}
+	if !hmac.Equal([]byte(signature), []byte(cursorSignature(payload))) {
+		return nil, newInvalidInputError("invalid cursor: signature mismatch")
+	}
+	if queryClock.Now().Sub(time.Unix(cursor.IssuedAt, 0)) > cursorMaxAge {
+		return nil, newCursorExpiredError()
+	}
+	return cursor, nil
 }
 
-// DecodeCursor is the exported version for testing
+// DecodeCursor deserializes a cursor without checking its signature or age, e.g. to inspect
+// the cursors a search returned. Searches check both (see decodeCursor)
 func DecodeCursor(cursorStr string) (*Cursor, error) {
 	if cursorStr == "" {
 		return nil, newInvalidInputError("cursor cannot be empty")
 	}
+	payload, _, _ := strings.Cut(cursorStr, ".")
 
 	// Decode from base64
-	jsonBytes, err := base64.StdEncoding.DecodeString(cursorStr)
+	jsonBytes, err := base64.StdEncoding.DecodeString(payload)
 	if err != nil {
 		return nil, newInvalidInputError("invalid cursor format: not valid base64")
 	}
@@ -51,10 +185,88 @@ func DecodeCursor(cursorStr string) (*Cursor, error) {
 		return nil, newInvalidInputError("invalid cursor format: malformed cursor data")
 	}
 
+	for i, value := range cursor.SortFields {
+		decoded, err := decodeCursorSortValue(value)
+		if err != nil {
+			return nil, newInvalidInputError("invalid cursor format: malformed date")
+		}
+		cursor.SortFields[i] = decoded
+	}
+
 	// Validate cursor has identifier
 	if cursor.Identifier == "" {
 		return nil, newInvalidInputError("invalid cursor: missing identifier")
 	}
+	if _, err := cursor.legacyID(); err != nil {
+		return nil, newInvalidInputError("invalid cursor: malformed document id")
+	}
 
 	return &cursor, nil
 }
+
+// totalCountAtStart returns the totalCount of the first page of the pagination a search
+// continues: totalCount itself without a cursor, else the one the cursor carries, or nil for
+// cursors issued before they carried it. The search has already validated the cursors
+func totalCountAtStart(after, before *string, totalCount int) *int64 {
+	for _, encoded := range []*string{after, before} {
+		if encoded == nil || *encoded == "" {
+			continue
+		}
+		cursor, err := DecodeCursor(*encoded)
+		if err != nil || cursor.StartTotal == 0 {
+			return nil
+		}
+		startTotal := int64(cursor.StartTotal)
+		return &startTotal
+	}
+	start := int64(totalCount)
+	return &start
+}
+
+// legacyID returns the _id a legacy cursor was issued at, or NilObjectID for other cursors
+func (c *Cursor) legacyID() (primitive.ObjectID, error) {
+	if !c.Legacy {
+		return primitive.NilObjectID, nil
+	}
+	return primitive.ObjectIDFromHex(c.Identifier)
+}
+
+// sortFingerprint identifies a sort order by its keys and directions, and whether the
+// pagination reads from a snapshot. Keys are those of the $sort stage, so null-safe sorts
+// are told apart by their computed keys. The _id tiebreaker only orders legacy documents among
+// themselves, so it is left out and cursors issued before it was added stay valid
+func sortFingerprint(keys bson.D, snapshot bool) string {
+	parts := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		if key.Key == "_id" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%v", key.Key, key.Value))
+	}
+	if snapshot {
+		parts = append(parts, "snapshot")
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, ",")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// checkCursorSort rejects a cursor issued for a different sort order than the current query's,
+// e.g. after an entity's default sort changed, and snapshot cursors used without snapshot and
+// vice versa
+func checkCursorSort(cursor *Cursor, keys bson.D, snapshot bool) error {
+	if cursor == nil {
+		return nil
+	}
+
+	issued := cursor.Sort
+	if issued != sortFingerprint(keys, snapshot) {
+		if issued == sortFingerprint(keys, !snapshot) {
+			return newInvalidInputError("cursor was issued for a different snapshot mode; pass the same snapshot argument on every page or restart pagination without a cursor")
+		}
+		return newInvalidInputError("cursor was issued for a different sort order; restart pagination without a cursor")
+	}
+	if snapshot && cursor.Snapshot == nil {
+		return newInvalidInputError("invalid cursor: missing snapshot time")
+	}
+	return nil
+}
@@ -2,77 +2,158 @@ package resolvers
 
 import (
 	"context"
-	"regexp"
-	"strings"
 	"time"
 
+	"github.com/yourusername/air-go/internal/db/purge"
 	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/scalars"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// UUID validation regex pattern (RFC4122 format, case-insensitive)
-var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+// customerCreate inserts a customer with the given fields, recording when and by whom it was created.
+// Returns INVALID_INPUT when a customer with the identifier already exists
+func customerCreate(r *mutationResolver, ctx context.Context, input generated.CustomerMutationInput) (*generated.Customer, error) {
+	if reason := checkUUID(input.Identifier); reason != "" {
+		return nil, newInvalidInputError(reason)
+	}
 
-// isValidUUID validates UUID format according to RFC4122
-// Supports uppercase, lowercase, and mixed case UUIDs
-func isValidUUID(uuid string) bool {
-	return uuidRegex.MatchString(strings.ToLower(uuid))
-}
+	collection := r.DBClient.Collection("customers")
+	existing, err := collection.CountDocuments(ctx, bson.M{"identifier": input.Identifier})
+	if err != nil {
+		return nil, mapMongoError(err)
+	}
+	if existing > 0 {
+		return nil, NewInvalidInput("a customer with this identifier already exists", "identifier")
+	}
 
-// customerGet retrieves a customer by identifier from MongoDB
-// Returns nil for non-existent or deleted customers
-// Returns error for invalid input or database failures
-func customerGet(r *queryResolver, ctx context.Context, identifier string) (*generated.Customer, error) {
-	// Start performance logging
-	startTime := time.Now()
-	var err error
-	defer func() {
-		duration := time.Since(startTime)
-		logQueryExecution(ctx, "customerGet", duration, err == nil)
-	}()
-
-	// Validate UUID format (FR-005)
-	if !isValidUUID(identifier) {
-		err = newInvalidInputError("invalid UUID format")
-		return nil, err
+	now := changeTime()
+	document := changeAudit(ctx, now)
+	document["createDate"] = now
+	if userID, ok := document["lastUpdatedByUser"]; ok {
+		document["createdByUser"] = userID
+	}
+	document["identifier"] = input.Identifier
+	document["version"] = int64(1)
+	document["actionIndicator"] = generated.ActionIndicatorNone
+	document["status"] = bson.M{"deletion": generated.DeleteStatusInit}
+	if input.EmployeeID != nil {
+		document["employeeId"] = *input.EmployeeID
+	}
+	if input.FirstName != nil {
+		document["firstName"] = *input.FirstName
+	}
+	if input.LastName != nil {
+		document["lastName"] = *input.LastName
+	}
+	if input.BirthDate != nil {
+		document["birthDate"] = *input.BirthDate
 	}
+	if input.UserEmail != nil {
+		document["userEmail"] = normalizeEmail(*input.UserEmail)
+	}
+	if input.IsShared != nil {
+		document["isShared"] = *input.IsShared
+	}
+	if input.Preference != nil {
+		document["preference"] = bson.M{"language": input.Preference.Language, "theme": input.Preference.Theme}
+	}
+	setShadowFields(document, customerShadowFields)
 
-	// Get customers collection
-	collection := r.DBClient.Collection("customers")
-	if collection == nil {
-		err = &QueryError{
-			Message: "Database not available",
-			Code:    ErrCodeDatabaseError,
-		}
-		return nil, err
+	if _, err := collection.InsertOne(ctx, document); err != nil {
+		return nil, mapMongoError(err)
 	}
+	// Drop the not-found cached by earlier customerGet probes
+	invalidateGetCache("customer", input.Identifier)
 
-	// Build query filter
-	// - Match by identifier
-	// - Exclude deleted customers (status.deletion = DELETED)
-	filter := bson.M{
-		"identifier":      identifier,
-		"status.deletion": bson.M{"$ne": "DELETED"},
+	// Read back from the primary so the response reflects the write
+	var customer generated.Customer
+	if err := collection.FindOne(ctx, bson.M{"identifier": input.Identifier}).Decode(&customer); err != nil {
+		return nil, mapMongoError(err)
 	}
+	return &customer, nil
+}
 
-	// Execute FindOne query
-	result := collection.FindOne(ctx, filter)
-	if result.Err() == mongo.ErrNoDocuments {
-		// Customer not found or deleted - return nil (FR-004, FR-003)
-		return nil, nil
+// customerUpdate sets the given fields of a customer, recording its previous version in the history.
+// Returns NOT_FOUND for unknown, deleted or inaccessible customers
+func customerUpdate(r *mutationResolver, ctx context.Context, input generated.CustomerUpdateMutationInput) (*generated.Customer, error) {
+	if reason := checkUUID(input.Identifier); reason != "" {
+		return nil, newInvalidInputError(reason)
 	}
-	if result.Err() != nil {
-		err = mapMongoError(result.Err())
-		return nil, err
+	if input.ActionCode != nil {
+		return nil, newInvalidInputError("actionCode is not supported by customerUpdate")
 	}
 
-	// Decode customer document
-	var customer generated.Customer
-	if decodeErr := result.Decode(&customer); decodeErr != nil {
-		err = mapMongoError(decodeErr)
+	set := changeAudit(ctx, changeTime())
+	if input.EmployeeID != nil {
+		set["employeeId"] = *input.EmployeeID
+	}
+	if input.FirstName != nil {
+		set["firstName"] = *input.FirstName
+	}
+	if input.LastName != nil {
+		set["lastName"] = *input.LastName
+	}
+	if input.BirthDate != nil {
+		set["birthDate"] = *input.BirthDate
+	}
+	if input.IsShared != nil {
+		set["isShared"] = *input.IsShared
+	}
+	if input.Preference != nil {
+		set["preference"] = bson.M{"language": input.Preference.Language, "theme": input.Preference.Theme}
+	}
+	setShadowFields(set, customerShadowFields)
+
+	found, err := changeCustomerWithHistory(ctx, r.DBClient, input.Identifier, generated.HistoryOperationUpdate, func(ctx context.Context, filter bson.M) error {
+		_, err := r.DBClient.Collection("customers").UpdateOne(ctx, filter, bson.M{"$set": set, "$inc": bson.M{"version": 1}})
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
+	if !found {
+		return nil, newNotFoundError("Customer not found")
+	}
 
+	// Read back from the primary so the response reflects the write
+	var customer generated.Customer
+	if err := r.DBClient.Collection("customers").FindOne(ctx, bson.M{"identifier": input.Identifier}).Decode(&customer); err != nil {
+		return nil, mapMongoError(err)
+	}
 	return &customer, nil
 }
+
+// customerDelete marks a customer as deleted as of now, recording its previous version in the history.
+// Returns false for unknown, already deleted or inaccessible customers
+func customerDelete(r *mutationResolver, ctx context.Context, identifier string) (bool, error) {
+	if reason := checkUUID(identifier); reason != "" {
+		return false, newInvalidInputError(reason)
+	}
+
+	config := entityConfigs["customer"]
+	now := changeTime()
+	set := changeAudit(ctx, now)
+	set[config.DeletionField] = config.DeletionValue
+	set[purge.DeleteDateField] = now
+
+	return changeCustomerWithHistory(ctx, r.DBClient, identifier, generated.HistoryOperationDelete, func(ctx context.Context, filter bson.M) error {
+		_, err := r.DBClient.Collection(config.CollectionName).UpdateOne(ctx, filter, bson.M{"$set": set, "$inc": bson.M{"version": 1}})
+		return err
+	})
+}
+
+// changeTime returns the time of a change, in UTC and at the millisecond precision of BSON dates
+// so a createDate written as a date and the lastUpdateDate string of the same change are equal.
+// Entities created within the same second still sort by creation
+func changeTime() time.Time {
+	return queryClock.Now().UTC().Truncate(time.Millisecond)
+}
+
+// changeAudit returns the $set fields recording when and by whom an entity was last changed at now
+func changeAudit(ctx context.Context, now time.Time) bson.M {
+	set := bson.M{"lastUpdateDate": scalars.FormatDateTime(now)}
+	if claims := getUserClaims(ctx); claims != nil && claims.UserID != "" {
+		set["lastUpdatedByUser"] = claims.UserID
+	}
+	return set
+}
@@ -0,0 +1,205 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/logger"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// customerHistoryCollection holds the customer versions recorded before updates and deletes
+const customerHistoryCollection = "customers_history"
+
+// customerHistoryConfig describes the history collection to searchEntities.
+// Entries have no deletion status, so the deletion exclusion matches every entry. Version
+// identifiers are UUIDv7, so identifier descending is newest first
+var customerHistoryConfig = EntityConfig{
+	CollectionName: customerHistoryCollection,
+	DeletionField:  "status.deletion",
+	DeletionValue:  "DELETED",
+	DefaultSort:    []SortField{{Field: "identifier", Direction: generated.SortEnumTypeDesc}},
+	FilterConverter: func(filter interface{}) bson.M {
+		if identifier, ok := filter.(string); ok {
+			return bson.M{"customerIdentifier": identifier}
+		}
+		return bson.M{}
+	},
+	AuthorizationFilter: customerHistoryAuthorizationFilter,
+}
+
+// customerHistoryAuthorizationFilter shows the versions of customers the caller could see at that version
+func customerHistoryAuthorizationFilter(ctx context.Context) bson.M {
+	filter := bson.M{}
+	for field, condition := range customerAuthorizationFilter(ctx) {
+		filter["preImage."+field] = condition
+	}
+	return filter
+}
+
+// HistoryIndexes returns the indexes of the history collections: lookups by entity and a TTL
+// index removing versions after the retention period
+func HistoryIndexes(retention time.Duration) []db.IndexSpec {
+	return []db.IndexSpec{
+		{
+			Collection: customerHistoryCollection,
+			Name:       "customerIdentifier_identifier",
+			Keys:       bson.D{{Key: "customerIdentifier", Value: 1}, {Key: "identifier", Value: -1}},
+		},
+		{
+			Collection:  customerHistoryCollection,
+			Name:        "recordedAt_ttl",
+			Keys:        bson.D{{Key: "recordedAt", Value: 1}},
+			ExpireAfter: retention,
+		},
+	}
+}
+
+// transactionRunner is implemented by database clients that support multi-document transactions
+type transactionRunner interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// changeCustomerWithHistory applies a change to a customer after recording its pre-image.
+// change receives the filter selecting the customer. On replica sets both writes share a
+// transaction, so neither is kept without the other. Elsewhere the history is written
+// best-effort: a failed history write is logged, queued for replay (see SetWriteRetryQueue) and
+// the change still applies.
+// Returns false when no visible, non-deleted customer has the identifier
+func changeCustomerWithHistory(ctx context.Context, dbClient DBClient, identifier string, operation generated.HistoryOperation, change func(ctx context.Context, filter bson.M) error) (bool, error) {
+	config := entityConfigs["customer"]
+	filter := applyAuthorizationFilter(ctx, config, config.ExcludeDeleted(bson.M{"identifier": identifier}))
+
+	var found bool
+	apply := func(ctx context.Context, transactional bool) error {
+		found = false
+
+		var preImage bson.M
+		err := dbClient.Collection(config.CollectionName).FindOne(ctx, filter).Decode(&preImage)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+
+		entry, err := newHistoryEntry(ctx, identifier, operation, preImage)
+		if err != nil {
+			return err
+		}
+		history := dbClient.Collection(customerHistoryCollection)
+		if _, err := history.InsertOne(ctx, entry); err != nil {
+			if transactional {
+				return err
+			}
+			queued := QueueWriteRetry("customer_history", func(ctx context.Context) error {
+				return insertOnce(ctx, history, []interface{}{entry})
+			})
+			logger.FromContext(ctx).Warn().
+				Err(err).
+				Str("customer", identifier).
+				Str("operation", string(operation)).
+				Bool("queued", queued).
+				Msg("Failed to record customer history; change applied without a history entry")
+		}
+
+		return change(ctx, filter)
+	}
+
+	var err error
+	runner, ok := dbClient.(transactionRunner)
+	if ok {
+		err = runner.WithTransaction(ctx, func(ctx context.Context) error { return apply(ctx, true) })
+	}
+	if !ok || errors.Is(err, db.ErrTransactionsUnsupported) {
+		err = apply(ctx, false)
+	}
+	// Also after failures, which may have applied the change without the history entry
+	invalidateGetCache("customer", identifier)
+	if err != nil {
+		return false, NewDatabaseError(err, "Database operation failed")
+	}
+	return found, nil
+}
+
+// newHistoryEntry builds the history document of a change. changedAt is shown to clients and
+// recordedAt, a BSON date, drives the TTL index. The _id is set up front, so a replayed insert
+// of the entry cannot store it twice
+func newHistoryEntry(ctx context.Context, identifier string, operation generated.HistoryOperation, preImage bson.M) (bson.M, error) {
+	version, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate history entry identifier: %w", err)
+	}
+	delete(preImage, "_id")
+
+	now := queryClock.Now().UTC()
+	entry := bson.M{
+		"_id":                primitive.NewObjectID(),
+		"identifier":         version.String(),
+		"customerIdentifier": identifier,
+		"operation":          string(operation),
+		"changedAt":          now.Format(time.RFC3339Nano),
+		"recordedAt":         now,
+		"preImage":           preImage,
+	}
+	if claims := getUserClaims(ctx); claims != nil && claims.UserID != "" {
+		entry["actor"] = claims.UserID
+	}
+	if requestID := middleware.RequestIDFromContext(ctx); requestID != "" {
+		entry["requestId"] = requestID
+	}
+	return entry, nil
+}
+
+// resolveCustomerHistory implements the customerHistory query resolver
+func resolveCustomerHistory(ctx context.Context, dbClient DBClient, identifier string, first *int64, after *string) (*generated.QueryOutputOfCustomerHistoryEntry, error) {
+	if reason := checkUUID(identifier); reason != "" {
+		return nil, newInvalidInputError(reason)
+	}
+
+	firstInt, _, err := pageSizeArgs(first, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*generated.CustomerHistoryEntry
+	count, totalCount, hasNextPage, hasPreviousPage, startCursor, endCursor, err := searchEntities(
+		ctx,
+		dbClient,
+		customerHistoryConfig,
+		identifier,
+		nil,
+		SearchPaging{First: firstInt, After: after},
+		SearchOptions{},
+		&entries,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []*generated.CustomerHistoryEntry{}
+	}
+	return &generated.QueryOutputOfCustomerHistoryEntry{
+		Count: int64(count),
+		Data:  entries,
+		Paging: &generated.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: hasPreviousPage,
+			StartCursor:     startCursor,
+			EndCursor:       endCursor,
+		},
+		TotalCount:        int64(totalCount),
+		TotalCountAtStart: totalCountAtStart(after, nil, totalCount),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+func TestNewHistoryEntry(t *testing.T) {
+	ctx := WithUserClaims(context.Background(), &UserClaims{UserID: "user-1"})
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, "req-1")
+
+	preImage := bson.M{"_id": "object-id", "identifier": "c1", "firstName": "Ada"}
+	entry, err := newHistoryEntry(ctx, "c1", generated.HistoryOperationUpdate, preImage)
+	require.NoError(t, err)
+
+	assert.Equal(t, "c1", entry["customerIdentifier"])
+	assert.Equal(t, "UPDATE", entry["operation"])
+	assert.Equal(t, "user-1", entry["actor"])
+	assert.Equal(t, "req-1", entry["requestId"])
+	assert.Equal(t, bson.M{"identifier": "c1", "firstName": "Ada"}, entry["preImage"], "the document id is not part of the version")
+	assert.IsType(t, time.Time{}, entry["recordedAt"], "the TTL index needs a BSON date")
+
+	version, err := uuid.Parse(entry["identifier"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), version.Version())
+}
+
+func TestNewHistoryEntry_Anonymous(t *testing.T) {
+	entry, err := newHistoryEntry(context.Background(), "c1", generated.HistoryOperationDelete, bson.M{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE", entry["operation"])
+	assert.NotContains(t, entry, "actor")
+	assert.NotContains(t, entry, "requestId")
+}
+
+func TestNewHistoryEntry_IdentifiersSortNewestLast(t *testing.T) {
+	// identifier descending is the history's newest-first order
+	first, err := newHistoryEntry(context.Background(), "c1", generated.HistoryOperationUpdate, bson.M{})
+	require.NoError(t, err)
+	second, err := newHistoryEntry(context.Background(), "c1", generated.HistoryOperationUpdate, bson.M{})
+	require.NoError(t, err)
+	assert.Less(t, first["identifier"].(string), second["identifier"].(string))
+}
+
+func TestCustomerHistoryAuthorizationFilter(t *testing.T) {
+	admin := WithUserClaims(context.Background(), &UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+	assert.Empty(t, customerHistoryAuthorizationFilter(admin))
+
+	user := WithUserClaims(context.Background(), &UserClaims{UserID: "user", Teams: []string{"team-a"}})
+	assert.Equal(t, bson.M{"preImage.customerGroups": bson.M{"$in": []string{"team-a"}}}, customerHistoryAuthorizationFilter(user))
+}
+
+func TestHistoryIndexes(t *testing.T) {
+	indexes := HistoryIndexes(48 * time.Hour)
+	require.Len(t, indexes, 2)
+
+	ttl := indexes[1]
+	assert.Equal(t, customerHistoryCollection, ttl.Collection)
+	assert.Equal(t, bson.D{{Key: "recordedAt", Value: 1}}, ttl.Keys)
+	assert.Equal(t, 48*time.Hour, ttl.ExpireAfter)
+	assert.Zero(t, indexes[0].ExpireAfter)
+}
+
+func TestResolveCustomerHistory_InvalidIdentifier(t *testing.T) {
+	_, err := resolveCustomerHistory(context.Background(), nil, "not-a-uuid", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid UUID format")
+}
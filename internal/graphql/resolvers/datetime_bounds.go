@@ -0,0 +1,104 @@
+package resolvers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// DateTime filters on BSON dates are checked against sanity bounds: values such as
+// gte "0001-01-01" or lte "9999-12-31" defeat index selectivity, and future values are
+// mostly mistakes. The bounds apply to the parsed values before the filter is converted
+
+// DateTimeFilterBounds bounds the values of DateTime filters on BSON date fields
+type DateTimeFilterBounds struct {
+	Enabled       bool          // Whether the bounds are checked at all
+	MinYear       int           // Earliest year a value may fall in
+	MaxFutureSkew time.Duration // How far past the current time a value may lie
+}
+
+// Default DateTime filter bounds
+const (
+	DefaultDateTimeFilterMinYear       = 1970
+	DefaultDateTimeFilterMaxFutureSkew = 24 * time.Hour
+)
+
+// dateTimeFilterBounds are the bounds currently enforced
+var dateTimeFilterBounds = DateTimeFilterBounds{
+	Enabled:       true,
+	MinYear:       DefaultDateTimeFilterMinYear,
+	MaxFutureSkew: DefaultDateTimeFilterMaxFutureSkew,
+}
+
+// SetDateTimeFilterBounds sets the bounds of DateTime filters on BSON date fields
+func SetDateTimeFilterBounds(bounds DateTimeFilterBounds) {
+	dateTimeFilterBounds = bounds
+}
+
+// dateTimeFilterFields are the filter input fields convertComparableFilterDateTime converts,
+// compared as BSON dates; calendar date fields such as birthDate are not bounded
+var dateTimeFilterFields = map[string]bool{
+	"createDate": true,
+}
+
+// checkDateTimeBounds fails when a DateTime filter value lies before the minimum year or more
+// than the allowed skew after now
+func checkDateTimeBounds(field, operator string, value time.Time, now time.Time) error {
+	bounds := dateTimeFilterBounds
+	if !bounds.Enabled {
+		return nil
+	}
+	if minimum := time.Date(bounds.MinYear, time.January, 1, 0, 0, 0, 0, time.UTC); value.Before(minimum) {
+		return NewInvalidInput(fmt.Sprintf(
+			"%s %s %s is before the minimum year %d of DateTime filters",
+			field, operator, formatDateTimeBound(value), bounds.MinYear,
+		), "where")
+	}
+	if maximum := now.Add(bounds.MaxFutureSkew); value.After(maximum) {
+		return NewInvalidInput(fmt.Sprintf(
+			"%s %s %s is more than %s in the future; DateTime filters may lie at most %s past the current time",
+			field, operator, formatDateTimeBound(value), bounds.MaxFutureSkew, bounds.MaxFutureSkew,
+		), "where")
+	}
+	return nil
+}
+
+// formatDateTimeBound renders a checked value as the DateTime scalar does
+func formatDateTimeBound(value time.Time) string {
+	return value.UTC().Format(time.RFC3339Nano)
+}
+
+// validateDateTimeFilter checks the values the converter compares with, including those of its
+// and/or filters, against the DateTime filter bounds. Null (the empty string) and unparsable
+// values are left to the converter
+func validateDateTimeFilter(field string, filter *generated.ComparableFilterOfNullableOfDateTimeInput, now time.Time) error {
+	if filter == nil || !dateTimeFilterBounds.Enabled {
+		return nil
+	}
+	for _, operand := range []struct {
+		operator string
+		value    *string
+	}{
+		{"eq", filter.Eq}, {"neq", filter.Neq},
+		{"gt", filter.Gt}, {"gte", filter.Gte},
+		{"lt", filter.Lt}, {"lte", filter.Lte},
+	} {
+		if operand.value == nil || *operand.value == "" {
+			continue
+		}
+		parsed, ok := parseDateTimeValue(*operand.value)
+		if !ok {
+			continue
+		}
+		if err := checkDateTimeBounds(field, operand.operator, parsed.(time.Time), now); err != nil {
+			return err
+		}
+	}
+	for _, nested := range append(append([]*generated.ComparableFilterOfNullableOfDateTimeInput{}, filter.And...), filter.Or...) {
+		if err := validateDateTimeFilter(field, nested, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package resolvers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// useDateTimeFilterBounds sets the DateTime filter bounds for the rest of a test
+func useDateTimeFilterBounds(t *testing.T, bounds DateTimeFilterBounds) {
+	t.Helper()
+	previous := dateTimeFilterBounds
+	SetDateTimeFilterBounds(bounds)
+	t.Cleanup(func() { SetDateTimeFilterBounds(previous) })
+}
+
+// TestCheckDateTimeBounds tests values at and inside each bound pass and values outside fail
+// with the violated bound
+func TestCheckDateTimeBounds(t *testing.T) {
+	useDateTimeFilterBounds(t, DateTimeFilterBounds{Enabled: true, MinYear: 1970, MaxFutureSkew: time.Hour})
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, value := range []time.Time{
+		time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), // At the minimum year
+		time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC),
+		now,
+		now.Add(time.Hour), // At the maximum future skew
+	} {
+		assert.NoError(t, checkDateTimeBounds("createDate", "gte", value, now), value)
+	}
+
+	err := checkDateTimeBounds("createDate", "gte", time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), now)
+	requireInvalidInput(t, err, `createDate gte 1969-12-31T23:59:59Z is before the minimum year 1970`)
+	err = checkDateTimeBounds("createDate", "gte", time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), now)
+	requireInvalidInput(t, err, "minimum year 1970")
+
+	err = checkDateTimeBounds("createDate", "lte", now.Add(time.Hour+time.Second), now)
+	requireInvalidInput(t, err, "createDate lte 2024-06-01T13:00:01Z is more than 1h0m0s in the future")
+	err = checkDateTimeBounds("createDate", "lte", time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), now)
+	requireInvalidInput(t, err, "1h0m0s in the future")
+}
+
+// TestValidateDateTimeFilter_ChecksNestedOperators tests the bounds apply to every compared
+// value, including those of and/or filters, and leave null alone
+func TestValidateDateTimeFilter_ChecksNestedOperators(t *testing.T) {
+	useDateTimeFilterBounds(t, DateTimeFilterBounds{Enabled: true, MinYear: 1970, MaxFutureSkew: time.Hour})
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	inside, early, late, null := "2024-01-01", "0001-01-01", "9999-12-31", ""
+
+	require.NoError(t, validateDateTimeFilter("createDate", &generated.ComparableFilterOfNullableOfDateTimeInput{
+		Gte: &inside, Neq: &null,
+		Or: []*generated.ComparableFilterOfNullableOfDateTimeInput{{Lt: &inside}},
+	}, now))
+
+	err := validateDateTimeFilter("createDate", &generated.ComparableFilterOfNullableOfDateTimeInput{
+		Gte: &inside,
+		And: []*generated.ComparableFilterOfNullableOfDateTimeInput{{Eq: &early}},
+	}, now)
+	requireInvalidInput(t, err, "createDate eq 0001-01-01T00:00:00Z is before the minimum year 1970")
+
+	err = validateDateTimeFilter("createDate", &generated.ComparableFilterOfNullableOfDateTimeInput{
+		Or: []*generated.ComparableFilterOfNullableOfDateTimeInput{{Gt: &inside}, {Lte: &late}},
+	}, now)
+	requireInvalidInput(t, err, "createDate lte 9999-12-31T00:00:00Z is more than 1h0m0s in the future")
+}
+
+// TestSearchDateTimeBounds tests searches reject createDate filters outside the bounds before
+// building the pipeline, and accept them with the bounds disabled
+func TestSearchDateTimeBounds(t *testing.T) {
+	config := entityConfigs["customer"]
+	early := "0001-01-01"
+	future := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	old := "1950-06-15"
+
+	_, err := BuildSearchPipeline(config, &generated.CustomerQueryFilterInput{
+		CreateDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &early},
+	}, nil, SearchPaging{})
+	requireInvalidInput(t, err, "createDate gte 0001-01-01T00:00:00Z is before the minimum year 1970")
+	_, err = BuildSearchPipeline(config, &generated.CustomerQueryFilterInput{
+		And: []*generated.CustomerQueryFilterInput{{CreateDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Lte: &future}}},
+	}, nil, SearchPaging{})
+	requireInvalidInput(t, err, "is more than 24h0m0s in the future")
+
+	// birthDate is a calendar date and not bounded
+	_, err = BuildSearchPipeline(entityConfigs["employee"], &generated.EmployeeQueryFilterInput{
+		BirthDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &old},
+	}, nil, SearchPaging{})
+	require.NoError(t, err)
+
+	useDateTimeFilterBounds(t, DateTimeFilterBounds{Enabled: false, MinYear: 1970, MaxFutureSkew: time.Hour})
+	for _, value := range []*string{&early, &future, &old} {
+		filter := &generated.CustomerQueryFilterInput{CreateDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: value}}
+		_, err := BuildSearchPipeline(config, filter, nil, SearchPaging{})
+		assert.NoError(t, err, *value)
+	}
+}
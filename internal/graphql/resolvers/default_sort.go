@@ -0,0 +1,110 @@
+package resolvers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// SortField is one field/direction pair of an entity's default sort
+type SortField struct {
+	Field     string // Sorter input field (JSON name, dotted for nested inputs)
+	Direction generated.SortEnumType
+}
+
+// entitySortStages returns the sort stages for a query: the caller's order when one is given,
+// otherwise the entity's default sort. Either way identifier ascending, then _id, break the
+// remaining ties (see appendTiebreakers)
+func entitySortStages(config EntityConfig, sorter interface{}) ([]bson.M, error) {
+	if config.SorterConverter != nil && hasSortEntries(sorter) {
+		return config.SorterConverter(sorter)
+	}
+
+	builder := &sortBuilder{}
+	for _, field := range config.DefaultSort {
+		if err := builder.add(field.Field, field.Direction); err != nil {
+			return nil, err
+		}
+	}
+	builder.appendTiebreakers()
+	return builder.stages(), nil
+}
+
+// hasSortEntries reports whether the caller supplied a non-empty order array
+func hasSortEntries(sorter interface{}) bool {
+	if sorter == nil {
+		return false
+	}
+	value := reflect.ValueOf(sorter)
+	if value.Kind() == reflect.Slice {
+		return value.Len() > 0
+	}
+	return true
+}
+
+// describeDefaultSort renders an entity's default sort for schema descriptions
+// e.g. "createDate descending, then identifier ascending"
+func describeDefaultSort(config EntityConfig) string {
+	parts := make([]string, 0, len(config.DefaultSort)+1)
+	for _, field := range config.DefaultSort {
+		direction := "ascending"
+		if field.Direction == generated.SortEnumTypeDesc {
+			direction = "descending"
+		}
+		parts = append(parts, field.Field+" "+direction)
+	}
+	parts = append(parts, "identifier ascending")
+	return strings.Join(parts, ", then ")
+}
+
+// ValidateDefaultSorts checks every entity's default sort against the fields its sorter
+// converter maps, so a default can only use a sort callers could request themselves.
+// Returns one message per invalid entry
+func ValidateDefaultSorts() []string {
+	return validateDefaultSorts(entityConfigs)
+}
+
+// validateDefaultSorts checks the default sorts of the given entity configs
+func validateDefaultSorts(configs map[string]EntityConfig) []string {
+	handled := map[string]map[string]bool{}
+	for _, coverage := range converterCoverage {
+		if !strings.HasSuffix(coverage.InputType.Name(), "SorterInput") {
+			continue
+		}
+		fields := map[string]bool{}
+		for _, field := range coverage.Handled {
+			fields[field] = true
+		}
+		handled[coverage.Entity] = fields
+	}
+
+	entities := make([]string, 0, len(configs))
+	for entity := range configs {
+		entities = append(entities, entity)
+	}
+	sort.Strings(entities)
+
+	problems := []string{}
+	for _, entity := range entities {
+		seen := map[string]bool{}
+		for _, field := range configs[entity].DefaultSort {
+			switch {
+			case !handled[entity][field.Field]:
+				problems = append(problems, fmt.Sprintf("%s: default sort field %q is not sortable", entity, field.Field))
+			case seen[field.Field]:
+				problems = append(problems, fmt.Sprintf("%s: default sort field %q is repeated", entity, field.Field))
+			}
+			if _, err := sortEnumToInt(field.Direction); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: default sort field %q has invalid direction %q", entity, field.Field, field.Direction))
+			}
+			seen[field.Field] = true
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,136 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+func TestValidateDefaultSorts_EntityConfigs(t *testing.T) {
+	assert.Empty(t, ValidateDefaultSorts())
+}
+
+func TestValidateDefaultSorts_Problems(t *testing.T) {
+	configs := map[string]EntityConfig{
+		"team": {DefaultSort: []SortField{
+			{Field: "name", Direction: generated.SortEnumTypeAsc},
+			{Field: "createDate", Direction: generated.SortEnumTypeDesc},
+			{Field: "name", Direction: generated.SortEnumTypeDesc},
+			{Field: "description", Direction: "SIDEWAYS"},
+		}},
+	}
+
+	assert.Equal(t, []string{
+		`team: default sort field "createDate" is not sortable`,
+		`team: default sort field "name" is repeated`,
+		`team: default sort field "description" has invalid direction "SIDEWAYS"`,
+	}, validateDefaultSorts(configs))
+}
+
+func TestEntitySortStages_DefaultSort(t *testing.T) {
+	config := entityConfigs["customer"]
+	want := []bson.M{{"$sort": bson.D{{Key: "createDate", Value: -1}, {Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}}}
+
+	// No order, an omitted order list and an empty order list all use the default
+	for _, sorter := range []interface{}{nil, []*generated.CustomerQuerySorterInput(nil), []*generated.CustomerQuerySorterInput{}} {
+		stages, err := entitySortStages(config, sorter)
+		require.NoError(t, err)
+		assert.Equal(t, want, stages)
+	}
+
+	// An entity without a default sorts by identifier
+	stages, err := entitySortStages(entityConfigs["employee"], nil)
+	require.NoError(t, err)
+	assert.Equal(t, []bson.M{{"$sort": bson.D{{Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}}}, stages)
+}
+
+func TestEntitySortStages_CallerOrder(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	stages, err := entitySortStages(entityConfigs["team"], []*generated.TeamQuerySorterInput{{Description: &asc}})
+	require.NoError(t, err)
+	assert.Equal(t, []bson.M{{"$sort": bson.D{{Key: "description", Value: 1}, {Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}}}, stages)
+}
+
+func TestBuildPaginationFilter_DescendingKey(t *testing.T) {
+	keys := bson.D{{Key: "createDate", Value: -1}, {Key: "identifier", Value: 1}}
+	cursor := &Cursor{SortFields: []interface{}{"2026-01-02"}, Identifier: "id-2"}
+
+	assert.Equal(t, bson.M{"$or": []bson.M{
+		{"createDate": bson.M{"$lt": "2026-01-02"}},
+		{"createDate": "2026-01-02", "identifier": bson.M{"$gt": "id-2"}},
+	}}, buildPaginationFilter(cursor, keys, true))
+
+	assert.Equal(t, bson.M{"$or": []bson.M{
+		{"createDate": bson.M{"$gt": "2026-01-02"}},
+		{"createDate": "2026-01-02", "$or": []bson.M{{"identifier": bson.M{"$lt": "id-2"}}, {"identifier": nil}}},
+	}}, buildPaginationFilter(cursor, keys, false))
+}
+
+func TestCheckCursorSort(t *testing.T) {
+	defaultKeys := bson.D{{Key: "createDate", Value: -1}, {Key: "identifier", Value: 1}}
+	identifierKeys := bson.D{{Key: "identifier", Value: 1}}
+
+	encoded, err := generateCursor(bson.M{"identifier": "id-1", "createDate": "2026-01-01"}, defaultKeys, nil, 0)
+	require.NoError(t, err)
+	cursor, err := decodeCursor(encoded)
+	require.NoError(t, err)
+
+	// Same sort order
+	assert.NoError(t, checkCursorSort(cursor, defaultKeys, false))
+
+	// Changed sort order
+	err = checkCursorSort(cursor, bson.D{{Key: "createDate", Value: 1}, {Key: "identifier", Value: 1}}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different sort order")
+
+	// Every cursor decodeCursor accepts carries a fingerprint; one without matches no sort order
+	assert.Error(t, checkCursorSort(&Cursor{Identifier: "id-1"}, identifierKeys, false))
+
+	assert.NoError(t, checkCursorSort(nil, defaultKeys, false))
+}
+
+func TestCheckCursorSort_Snapshot(t *testing.T) {
+	keys := bson.D{{Key: "identifier", Value: 1}}
+	doc := bson.M{"identifier": "id-1"}
+
+	encoded, err := generateCursor(doc, keys, newCursorSnapshot(primitive.Timestamp{T: 1760000000, I: 3}), 0)
+	require.NoError(t, err)
+	snapshotCursor, err := decodeCursor(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, &primitive.Timestamp{T: 1760000000, I: 3}, snapshotCursor.Snapshot.timestamp())
+
+	encoded, err = generateCursor(doc, keys, nil, 0)
+	require.NoError(t, err)
+	plainCursor, err := decodeCursor(encoded)
+	require.NoError(t, err)
+	assert.Nil(t, plainCursor.Snapshot)
+
+	assert.NoError(t, checkCursorSort(snapshotCursor, keys, true))
+	assert.NoError(t, checkCursorSort(plainCursor, keys, false))
+
+	// Snapshot and non-snapshot cursors are not interchangeable
+	err = checkCursorSort(snapshotCursor, keys, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different snapshot mode")
+	err = checkCursorSort(plainCursor, keys, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different snapshot mode")
+
+	// Legacy cursors never carry a snapshot
+	assert.Error(t, checkCursorSort(&Cursor{SortFields: []interface{}{"Smith"}, Identifier: "id-1"}, keys, true))
+
+	// A snapshot fingerprint without a snapshot time is rejected
+	forged := &Cursor{Identifier: "id-1", Sort: sortFingerprint(keys, true)}
+	assert.Error(t, checkCursorSort(forged, keys, true))
+}
+
+func TestDescribeDefaultSort(t *testing.T) {
+	assert.Equal(t, "createDate descending, then identifier ascending", describeDefaultSort(entityConfigs["customer"]))
+	assert.Equal(t, "name ascending, then identifier ascending", describeDefaultSort(entityConfigs["team"]))
+	assert.Equal(t, "identifier ascending", describeDefaultSort(entityConfigs["employee"]))
+}
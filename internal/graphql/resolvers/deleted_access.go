@@ -0,0 +1,122 @@
+package resolvers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// Lookups of identifiers that exist only as deleted entities can signal account enumeration or
+// misuse. With the audit enabled, Get and byKeys lookups that miss check the missed identifiers
+// once more for deleted entities and log an audit event for every one found. Callers still get
+// null, so the audit cannot tell them anything. The check doubles the reads of missing lookups,
+// so it is off by default
+
+// auditDeletedAccess enables the deleted-entity access audit
+var auditDeletedAccess = false
+
+// SetAuditDeletedAccess sets whether Get and byKeys lookups that miss check for deleted entities
+// and log an audit event for each one found
+func SetAuditDeletedAccess(enabled bool) {
+	auditDeletedAccess = enabled
+}
+
+// DeletedAccessStats counts the lookups of deleted entities of one entity
+type DeletedAccessStats struct {
+	Entity   string
+	Attempts int64
+}
+
+// deletedAccessCounts counts the audited lookups per entity since startup
+var deletedAccessCounts = struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}{counts: map[string]int64{}}
+
+// CurrentDeletedAccessStats returns the audited lookups of deleted entities per entity, ordered by entity
+func CurrentDeletedAccessStats() []DeletedAccessStats {
+	deletedAccessCounts.mu.Lock()
+	defer deletedAccessCounts.mu.Unlock()
+	stats := make([]DeletedAccessStats, 0, len(deletedAccessCounts.counts))
+	for entity, attempts := range deletedAccessCounts.counts {
+		stats = append(stats, DeletedAccessStats{Entity: entity, Attempts: attempts})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Entity < stats[j].Entity })
+	return stats
+}
+
+// auditDeletedLookups logs an audit event for every missed identifier that exists as a deleted
+// entity, reading them in a single query. The caller's authorization scope is not applied, so
+// deleted entities outside it are audited too. A failed check is logged and does not fail the lookup
+func auditDeletedLookups(ctx context.Context, collection db.Collection, config EntityConfig, missed []string) {
+	if !auditDeletedAccess || len(missed) == 0 {
+		return
+	}
+
+	filter := bson.M{
+		"identifier":         bson.M{"$in": missed},
+		config.DeletionField: config.DeletionValue,
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 0, "identifier": 1}))
+	var deleted []struct {
+		Identifier string `bson:"identifier"`
+	}
+	if err == nil {
+		err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+			return cursor.All(ctx, &deleted)
+		})
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error().Err(err).
+			Str("collection", config.CollectionName).
+			Int("identifiers", len(missed)).
+			Msg("Failed to check missed lookups for deleted entities")
+		return
+	}
+	if len(deleted) == 0 {
+		return
+	}
+
+	entity := entityName(config)
+	deletedAccessCounts.mu.Lock()
+	deletedAccessCounts.counts[entity] += int64(len(deleted))
+	deletedAccessCounts.mu.Unlock()
+
+	// The context's loggers carry the request_id and the principal's user_id
+	for _, document := range deleted {
+		logger.FromContext(ctx).Warn().
+			Str("audit", "deleted_entity_access").
+			Str("entity", entity).
+			Str("identifier", document.Identifier).
+			Msg("Lookup of a deleted entity")
+	}
+}
+
+// missedIdentifiers returns the identifiers without an entity in result, a pointer to the slice
+// of entities a byKeys lookup decoded
+func missedIdentifiers(identifiers []string, result interface{}) []string {
+	found := map[string]bool{}
+	entities := reflect.ValueOf(result).Elem()
+	for i := 0; i < entities.Len(); i++ {
+		entity := reflect.Indirect(entities.Index(i))
+		if identifier := entity.FieldByName("Identifier"); identifier.Kind() == reflect.String {
+			found[identifier.String()] = true
+		}
+	}
+
+	missed := []string{}
+	for _, identifier := range identifiers {
+		if !found[identifier] {
+			missed = append(missed, identifier)
+		}
+	}
+	return missed
+}
@@ -0,0 +1,198 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// fakeDeletionDB holds customers by identifier with their status.deletion and records the
+// Find filters of the deleted-entity checks
+type fakeDeletionDB struct {
+	deletion map[string]string
+	finds    []bson.M
+}
+
+func (f *fakeDeletionDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f *fakeDeletionDB) Collection(name string) db.Collection                       { return &fakeDeletionCollection{db: f} }
+func (f *fakeDeletionDB) ReadCollection(name string) db.Collection                   { return f.Collection(name) }
+func (f *fakeDeletionDB) IsConnected() bool                                          { return true }
+
+type fakeDeletionCollection struct {
+	db.Collection
+	db *fakeDeletionDB
+}
+
+// matching returns the documents matching an identifier ($in or equality) and status.deletion
+// ($ne or equality) filter
+func (c *fakeDeletionCollection) matching(filter bson.M) []interface{} {
+	identifiers := []string{}
+	switch identifier := filter["identifier"].(type) {
+	case string:
+		identifiers = append(identifiers, identifier)
+	case bson.M:
+		identifiers = append(identifiers, identifier["$in"].([]string)...)
+	}
+
+	documents := []interface{}{}
+	for _, identifier := range identifiers {
+		deletion, ok := c.db.deletion[identifier]
+		if !ok {
+			continue
+		}
+		switch condition := filter["status.deletion"].(type) {
+		case string:
+			ok = deletion == condition
+		case bson.M:
+			ok = deletion != condition["$ne"]
+		}
+		if ok {
+			documents = append(documents, bson.M{"identifier": identifier, "status": bson.M{"deletion": deletion}})
+		}
+	}
+	return documents
+}
+
+func (c *fakeDeletionCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	documents := c.matching(filter.(bson.M))
+	if len(documents) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(documents[0], nil, nil)
+}
+
+func (c *fakeDeletionCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	c.db.finds = append(c.db.finds, filter.(bson.M))
+	return mongo.NewCursorFromDocuments(c.matching(filter.(bson.M)), nil, nil)
+}
+
+func (c *fakeDeletionCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return mongo.NewCursorFromDocuments(c.matching(pipeline.([]bson.M)[0]["$match"].(bson.M)), nil, nil)
+}
+
+const (
+	activeCustomerID  = "0b7e6f3c-5a8e-4a59-9c39-6c2d8d7c1d11"
+	deletedCustomerID = "0b7e6f3c-5a8e-4a59-9c39-6c2d8d7c1d12"
+	absentCustomerID  = "0b7e6f3c-5a8e-4a59-9c39-6c2d8d7c1d13"
+)
+
+// useDeletedAccessAudit enables the audit for the rest of a test and returns its log output
+func useDeletedAccessAudit(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previousLogger := log.Logger
+	log.Logger = zerolog.New(&buf)
+	SetAuditDeletedAccess(true)
+	t.Cleanup(func() {
+		log.Logger = previousLogger
+		SetAuditDeletedAccess(false)
+	})
+	return &buf
+}
+
+// auditEvents returns the deleted-entity access audit events logged to buf
+func auditEvents(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	events := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		if event["audit"] == "deleted_entity_access" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// auditedCustomerContext is a request of user auditor whose loggers carry request ID req-1
+func auditedCustomerContext() context.Context {
+	ctx := logger.WithContextField(context.Background(), "request_id", "req-1")
+	return WithUserClaims(ctx, &UserClaims{UserID: "auditor", Roles: []string{"ADMIN"}})
+}
+
+func deletedAccessAttempts(entity string) int64 {
+	for _, stats := range CurrentDeletedAccessStats() {
+		if stats.Entity == entity {
+			return stats.Attempts
+		}
+	}
+	return 0
+}
+
+// TestGetEntity_AuditsDeletedAccess tests a Get of a deleted customer returns null and logs an
+// audit event with the principal and request ID, while absent and live customers log none
+func TestGetEntity_AuditsDeletedAccess(t *testing.T) {
+	buf := useDeletedAccessAudit(t)
+	database := &fakeDeletionDB{deletion: map[string]string{activeCustomerID: "INIT", deletedCustomerID: "DELETED"}}
+	ctx := auditedCustomerContext()
+	before := deletedAccessAttempts("customer")
+
+	for _, identifier := range []string{activeCustomerID, absentCustomerID, deletedCustomerID} {
+		var customer generated.Customer
+		require.NoError(t, getEntity(ctx, database, entityConfigs["customer"], identifier, &customer))
+		if identifier == activeCustomerID {
+			assert.Equal(t, activeCustomerID, customer.Identifier)
+		} else {
+			assert.Empty(t, customer.Identifier)
+		}
+	}
+
+	events := auditEvents(t, buf)
+	require.Len(t, events, 1)
+	assert.Equal(t, "customer", events[0]["entity"])
+	assert.Equal(t, deletedCustomerID, events[0]["identifier"])
+	assert.Equal(t, "auditor", events[0]["user_id"])
+	assert.Equal(t, "req-1", events[0]["request_id"])
+	assert.Equal(t, before+1, deletedAccessAttempts("customer"))
+	assert.Len(t, database.finds, 2, "only the two misses are checked")
+}
+
+// TestGetEntitiesByKeys_AuditsDeletedAccessInOneQuery tests a byKeys lookup checks all its missed
+// identifiers in a single $in query and audits the deleted ones only
+func TestGetEntitiesByKeys_AuditsDeletedAccessInOneQuery(t *testing.T) {
+	buf := useDeletedAccessAudit(t)
+	database := &fakeDeletionDB{deletion: map[string]string{activeCustomerID: "INIT", deletedCustomerID: "DELETED"}}
+
+	var customers []*generated.Customer
+	identifiers := []string{activeCustomerID, deletedCustomerID, absentCustomerID}
+	require.NoError(t, getEntitiesByKeys(auditedCustomerContext(), database, entityConfigs["customer"], identifiers, nil, &customers))
+	require.Len(t, customers, 1)
+
+	require.Len(t, database.finds, 1)
+	assert.Equal(t, bson.M{
+		"identifier":      bson.M{"$in": []string{deletedCustomerID, absentCustomerID}},
+		"status.deletion": "DELETED",
+	}, database.finds[0])
+
+	events := auditEvents(t, buf)
+	require.Len(t, events, 1)
+	assert.Equal(t, deletedCustomerID, events[0]["identifier"])
+}
+
+// TestDeletedAccessAudit_DisabledByDefault tests misses send no further query without the audit
+func TestDeletedAccessAudit_DisabledByDefault(t *testing.T) {
+	database := &fakeDeletionDB{deletion: map[string]string{deletedCustomerID: "DELETED"}}
+
+	var customer generated.Customer
+	require.NoError(t, getEntity(context.Background(), database, entityConfigs["customer"], deletedCustomerID, &customer))
+	var customers []*generated.Customer
+	require.NoError(t, getEntitiesByKeys(context.Background(), database, entityConfigs["customer"], []string{deletedCustomerID}, nil, &customers))
+	assert.Empty(t, database.finds)
+}
@@ -0,0 +1,268 @@
+package resolvers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// fakeEntityDB holds documents per collection and answers the lookups and searches of the
+// generic queries by evaluating their filters
+type fakeEntityDB struct {
+	documents map[string][]bson.M
+}
+
+func (f *fakeEntityDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f *fakeEntityDB) Collection(name string) db.Collection {
+	return &fakeEntityCollection{documents: f.documents[name]}
+}
+func (f *fakeEntityDB) ReadCollection(name string) db.Collection { return f.Collection(name) }
+func (f *fakeEntityDB) IsConnected() bool                        { return true }
+
+type fakeEntityCollection struct {
+	db.Collection
+	documents []bson.M
+}
+
+func (c *fakeEntityCollection) matching(filter bson.M) []interface{} {
+	matched := []interface{}{}
+	for _, document := range c.documents {
+		if parityMatches(document, filter) {
+			matched = append(matched, document)
+		}
+	}
+	return matched
+}
+
+func (c *fakeEntityCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	matched := c.matching(filter.(bson.M))
+	if len(matched) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(matched[0], nil, nil)
+}
+
+// Aggregate applies the top-level $match stages and answers a search's $facet with the count
+// and all matches; sorting and paging do not affect which documents are excluded
+func (c *fakeEntityCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	stages := pipeline.([]bson.M)
+	filter := bson.M{}
+	conditions := []bson.M{}
+	for _, stage := range stages {
+		if match, ok := stage["$match"].(bson.M); ok {
+			conditions = append(conditions, match)
+		}
+	}
+	filter["$and"] = conditions
+	matched := c.matching(filter)
+
+	if _, ok := stages[len(stages)-1]["$facet"]; ok {
+		return mongo.NewCursorFromDocuments([]interface{}{bson.M{
+			"metadata": []bson.M{{"totalCount": len(matched)}},
+			"data":     matched,
+		}}, nil, nil)
+	}
+	return mongo.NewCursorFromDocuments(matched, nil, db.Registry)
+}
+
+// parityMatches evaluates the filters of the generic queries: $and/$or/$nor, equality, $ne, $in,
+// $nin and $exists on dotted paths. $expr conditions are treated as matching
+func parityMatches(document bson.M, filter bson.M) bool {
+	for key, condition := range filter {
+		switch key {
+		case "$and", "$or":
+			branches := condition.([]bson.M)
+			matchedAny := false
+			for _, branch := range branches {
+				matches := parityMatches(document, branch)
+				if key == "$and" && !matches {
+					return false
+				}
+				matchedAny = matchedAny || matches
+			}
+			if key == "$or" && !matchedAny {
+				return false
+			}
+			continue
+		case "$nor":
+			for _, branch := range condition.([]bson.M) {
+				if parityMatches(document, branch) {
+					return false
+				}
+			}
+			continue
+		case "$expr":
+			continue
+		}
+
+		value := parityLookup(document, key)
+		operators, ok := condition.(bson.M)
+		if !ok {
+			if value != condition {
+				return false
+			}
+			continue
+		}
+		for operator, operand := range operators {
+			switch operator {
+			case "$ne":
+				if value == operand {
+					return false
+				}
+			case "$in":
+				if !parityContains(operand, value) {
+					return false
+				}
+			case "$nin":
+				if parityContains(operand, value) {
+					return false
+				}
+			case "$exists":
+				if (value != nil) != operand.(bool) {
+					return false
+				}
+			default:
+				panic("parityMatches does not evaluate " + operator)
+			}
+		}
+	}
+	return true
+}
+
+// parityLookup returns the value of a dotted path, nil when absent
+func parityLookup(document bson.M, path string) interface{} {
+	var value interface{} = document
+	for _, part := range strings.Split(path, ".") {
+		nested, ok := value.(bson.M)
+		if !ok {
+			return nil
+		}
+		value = nested[part]
+	}
+	return value
+}
+
+func parityContains(list interface{}, value interface{}) bool {
+	values := reflect.ValueOf(list)
+	for i := 0; i < values.Len(); i++ {
+		if values.Index(i).Interface() == value {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	parityActiveID  = "6f1c2d3e-4a5b-4c6d-8e7f-001122334455"
+	parityDeletedID = "6f1c2d3e-4a5b-4c6d-8e7f-001122334456"
+)
+
+// parityEntity describes how an entity's documents are stored independently of entityConfigs,
+// so a query encoding another entity's deletion field or value is caught
+type parityEntity struct {
+	deleted, active bson.M             // Deletion markers of a deleted and of an active document
+	newEntity       func() interface{} // Pointer to the entity type a Get decodes into
+	newList         func() interface{} // Pointer to the slice a byKeys or search decodes into
+}
+
+var parityEntities = map[string]parityEntity{
+	"customer": {
+		deleted: bson.M{"status": bson.M{"deletion": "DELETED"}}, active: bson.M{"status": bson.M{"deletion": "INIT"}},
+		newEntity: func() interface{} { return &generated.Customer{} }, newList: func() interface{} { return &[]*generated.Customer{} },
+	},
+	"employee": {
+		deleted: bson.M{"status": bson.M{"deletion": "DELETED"}}, active: bson.M{"status": bson.M{"deletion": "INIT"}},
+		newEntity: func() interface{} { return &generated.Employee{} }, newList: func() interface{} { return &[]*generated.Employee{} },
+	},
+	"team": {
+		deleted: bson.M{"status": bson.M{"deletion": "DELETED"}}, active: bson.M{"status": bson.M{"deletion": "INIT"}},
+		newEntity: func() interface{} { return &generated.TeamQueryOutput{} }, newList: func() interface{} { return &[]*generated.TeamQueryOutput{} },
+	},
+	"inventory": {
+		deleted: bson.M{"actionIndicator": "DELETE"}, active: bson.M{"actionIndicator": "NONE"},
+		newEntity: func() interface{} { return &generated.Inventory{} }, newList: func() interface{} { return &[]*generated.Inventory{} },
+	},
+	"executionPlan": {
+		deleted: bson.M{"actionIndicator": "DELETE"}, active: bson.M{"actionIndicator": "NONE"},
+		newEntity: func() interface{} { return &generated.ExecutionPlan{} }, newList: func() interface{} { return &[]*generated.ExecutionPlan{} },
+	},
+	"referencePortfolio": {
+		deleted: bson.M{"actionIndicator": "DELETE"}, active: bson.M{"actionIndicator": "NONE"},
+		newEntity: func() interface{} { return &generated.ReferencePortfolioOutput{} }, newList: func() interface{} { return &[]*generated.ReferencePortfolioOutput{} },
+	},
+}
+
+// parityIdentifiers returns the identifiers of a decoded entity or slice of entities
+func parityIdentifiers(result interface{}) []string {
+	value := reflect.ValueOf(result).Elem()
+	if value.Kind() != reflect.Slice {
+		if identifier := value.FieldByName("Identifier").String(); identifier != "" {
+			return []string{identifier}
+		}
+		return []string{}
+	}
+	identifiers := []string{}
+	for i := 0; i < value.Len(); i++ {
+		identifiers = append(identifiers, reflect.Indirect(value.Index(i)).FieldByName("Identifier").String())
+	}
+	return identifiers
+}
+
+// TestDeletionExclusionParity tests Get, byKeys and search exclude each entity's deleted
+// documents as they are stored, next to an active one
+func TestDeletionExclusionParity(t *testing.T) {
+	ctx := WithUserClaims(context.Background(), &UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+
+	for name, config := range entityConfigs {
+		t.Run(name, func(t *testing.T) {
+			entity, ok := parityEntities[name]
+			require.True(t, ok, "describe how %s documents are stored in parityEntities", name)
+
+			document := func(identifier string, markers bson.M) bson.M {
+				stored := bson.M{"identifier": identifier}
+				for key, value := range markers {
+					stored[key] = value
+				}
+				return stored
+			}
+			database := &fakeEntityDB{documents: map[string][]bson.M{config.CollectionName: {
+				document(parityActiveID, entity.active),
+				document(parityDeletedID, entity.deleted),
+			}}}
+
+			active := entity.newEntity()
+			require.NoError(t, getEntity(ctx, database, config, parityActiveID, active))
+			assert.Equal(t, []string{parityActiveID}, parityIdentifiers(active), "Get of the active document")
+			deleted := entity.newEntity()
+			require.NoError(t, getEntity(ctx, database, config, parityDeletedID, deleted))
+			assert.Empty(t, parityIdentifiers(deleted), "Get of the deleted document")
+
+			byKeys := entity.newList()
+			require.NoError(t, getEntitiesByKeys(ctx, database, config, []string{parityActiveID, parityDeletedID}, nil, byKeys))
+			assert.Equal(t, []string{parityActiveID}, parityIdentifiers(byKeys), "byKeys")
+
+			searched := entity.newList()
+			_, totalCount, _, _, _, _, err := searchEntities(ctx, database, config, nil, nil, SearchPaging{}, SearchOptions{}, searched)
+			require.NoError(t, err)
+			assert.Equal(t, []string{parityActiveID}, parityIdentifiers(searched), "search")
+			assert.Equal(t, 1, totalCount, "search total count")
+		})
+	}
+}
+
+// TestExcludeDeleted tests the predicate adds the entity's deletion condition to a filter
+func TestExcludeDeleted(t *testing.T) {
+	assert.Equal(t, bson.M{"identifier": "a", "actionIndicator": bson.M{"$ne": "DELETE"}},
+		entityConfigs["inventory"].ExcludeDeleted(bson.M{"identifier": "a"}))
+	assert.Equal(t, bson.M{"status.deletion": bson.M{"$ne": "DELETED"}}, entityConfigs["team"].ExcludeDeleted(bson.M{}))
+}
@@ -0,0 +1,322 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Fields and arguments marked @deprecated may name the date they stop being served with
+// @sunset(date:). Operations using them are counted per client, so owners see who still has to
+// migrate, and once the date passed they are refused with FIELD_SUNSET when enforcement is on
+
+// sunsetDateLayout is the layout of @sunset dates
+const sunsetDateLayout = "2006-01-02"
+
+// Bounds of the client names counted; further names are counted as otherDeprecationClient
+const (
+	maxDeprecationClients      = 100
+	maxDeprecationClientLength = 64
+	unknownDeprecationClient   = "unknown"
+	otherDeprecationClient     = "other"
+)
+
+// Deprecation is a deprecated field or argument of the schema
+type Deprecation struct {
+	Coordinate string    // Type.field, or Type.field(argument:) for an argument
+	Reason     string    // Reason given by @deprecated
+	Sunset     time.Time // Date from @sunset; zero when none is set
+}
+
+// SunsetPassed reports whether the deprecation's sunset date has been reached at now
+func (d Deprecation) SunsetPassed(now time.Time) bool {
+	return !d.Sunset.IsZero() && !now.UTC().Before(d.Sunset)
+}
+
+// DeprecationSet holds the deprecated coordinates of one schema
+type DeprecationSet struct {
+	schema        *ast.Schema
+	byCoordinate  map[string]Deprecation
+	enforceSunset bool
+}
+
+// deprecations is the deprecation set of the schema served at startup, used by operations whose
+// context carries none (see WithDeprecations)
+var deprecations atomic.Pointer[DeprecationSet]
+
+// deprecationsKey is the context key of the deprecation set of a request's schema
+type deprecationsKey struct{}
+
+// WithDeprecations returns a context whose operations read the deprecation set, so a request
+// keeps the deprecations of the schema it started with while the schema is reloaded
+func WithDeprecations(ctx context.Context, set *DeprecationSet) context.Context {
+	return context.WithValue(ctx, deprecationsKey{}, set)
+}
+
+// currentDeprecations returns the deprecation set of the context, else the one set at startup,
+// empty before SetDeprecations
+func currentDeprecations(ctx context.Context) *DeprecationSet {
+	if set, ok := ctx.Value(deprecationsKey{}).(*DeprecationSet); ok && set != nil {
+		return set
+	}
+	if set := deprecations.Load(); set != nil {
+		return set
+	}
+	return &DeprecationSet{}
+}
+
+// SetDeprecations reads the deprecations of the schema with LoadDeprecations and makes them the
+// default of operations. A failure keeps the previous deprecations
+func SetDeprecations(schema *ast.Schema, enforceSunset bool) error {
+	set, err := LoadDeprecations(schema, enforceSunset)
+	if err != nil {
+		return err
+	}
+	deprecations.Store(set)
+	return nil
+}
+
+// LoadDeprecations reads the @deprecated and @sunset directives of the schema's fields and
+// arguments. With enforceSunset, operations using one whose sunset date has passed are refused.
+// Introspection reports the sunset date at the end of the deprecation reason. A malformed date,
+// or @sunset without @deprecated, fails
+func LoadDeprecations(schema *ast.Schema, enforceSunset bool) (*DeprecationSet, error) {
+	byCoordinate := map[string]Deprecation{}
+	for _, definition := range schema.Types {
+		if definition.BuiltIn || (definition.Kind != ast.Object && definition.Kind != ast.Interface) {
+			continue
+		}
+		for _, field := range definition.Fields {
+			coordinate := definition.Name + "." + field.Name
+			if err := addDeprecation(byCoordinate, coordinate, field.Directives); err != nil {
+				return nil, err
+			}
+			for _, argument := range field.Arguments {
+				if err := addDeprecation(byCoordinate, coordinate+"("+argument.Name+":)", argument.Directives); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return &DeprecationSet{schema: schema, byCoordinate: byCoordinate, enforceSunset: enforceSunset}, nil
+}
+
+// addDeprecation adds the deprecation of a schema coordinate, if its directives declare one
+func addDeprecation(byCoordinate map[string]Deprecation, coordinate string, directives ast.DirectiveList) error {
+	deprecated := directives.ForName("deprecated")
+	sunset := directives.ForName("sunset")
+	if deprecated == nil {
+		if sunset != nil {
+			return fmt.Errorf("%s has @sunset without @deprecated", coordinate)
+		}
+		return nil
+	}
+
+	deprecation := Deprecation{Coordinate: coordinate, Reason: "No longer supported"}
+	reason := deprecated.Arguments.ForName("reason")
+	if reason != nil {
+		deprecation.Reason = strings.TrimSuffix(reason.Value.Raw, sunsetNote(sunset))
+	}
+	if sunset != nil {
+		date := sunset.Arguments.ForName("date")
+		if date == nil {
+			return fmt.Errorf("%s has @sunset without a date", coordinate)
+		}
+		parsed, err := time.Parse(sunsetDateLayout, date.Value.Raw)
+		if err != nil {
+			return fmt.Errorf("%s has an invalid @sunset date %q, expected YYYY-MM-DD", coordinate, date.Value.Raw)
+		}
+		deprecation.Sunset = parsed
+
+		// Introspection reads the reason from the directive, so the date is added there
+		if reason == nil {
+			reason = &ast.Argument{Name: "reason", Value: &ast.Value{Kind: ast.StringValue}}
+			deprecated.Arguments = append(deprecated.Arguments, reason)
+		}
+		reason.Value.Raw = deprecation.Reason + sunsetNote(sunset)
+	}
+	byCoordinate[coordinate] = deprecation
+	return nil
+}
+
+// sunsetNote returns the text introspection appends to a deprecation reason, "" without @sunset
+func sunsetNote(sunset *ast.Directive) string {
+	if sunset == nil {
+		return ""
+	}
+	date := sunset.Arguments.ForName("date")
+	if date == nil {
+		return ""
+	}
+	return " (sunset " + date.Value.Raw + ")"
+}
+
+// OperationDeprecations returns the deprecated fields and arguments an operation selects, each
+// once, in the order they are first selected. Fragments are followed and fields skipped with
+// @skip or @include are left out, as in execution
+func OperationDeprecations(ctx context.Context, oc *graphql.OperationContext) []Deprecation {
+	set := currentDeprecations(ctx)
+	if len(set.byCoordinate) == 0 || oc == nil || oc.Operation == nil {
+		return nil
+	}
+	root := rootDefinition(set.schema, oc.Operation.Operation)
+	if root == nil {
+		return nil
+	}
+
+	collector := &deprecationCollector{set: set, oc: oc, seen: map[string]bool{}}
+	collector.walk(oc.Operation.SelectionSet, []string{root.Name})
+	return collector.found
+}
+
+// rootDefinition returns the schema type an operation starts from
+func rootDefinition(schema *ast.Schema, operation ast.Operation) *ast.Definition {
+	switch operation {
+	case ast.Mutation:
+		return schema.Mutation
+	case ast.Subscription:
+		return schema.Subscription
+	default:
+		return schema.Query
+	}
+}
+
+// deprecationCollector gathers the deprecations of one operation while its selections are walked
+type deprecationCollector struct {
+	set   *DeprecationSet
+	oc    *graphql.OperationContext
+	seen  map[string]bool
+	found []Deprecation
+}
+
+// walk visits the fields of a selection set on a value of one of the satisfies types
+func (c *deprecationCollector) walk(selections ast.SelectionSet, satisfies []string) {
+	for _, field := range graphql.CollectFields(c.oc, selections, satisfies) {
+		if field.Definition == nil || field.ObjectDefinition == nil {
+			continue
+		}
+		coordinate := field.ObjectDefinition.Name + "." + field.Name
+		c.add(coordinate)
+		for _, argument := range field.Arguments {
+			c.add(coordinate + "(" + argument.Name + ":)")
+		}
+		if len(field.Selections) > 0 {
+			c.walk(field.Selections, c.possibleTypeNames(field.Definition.Type.Name()))
+		}
+	}
+}
+
+func (c *deprecationCollector) add(coordinate string) {
+	deprecation, ok := c.set.byCoordinate[coordinate]
+	if !ok || c.seen[coordinate] {
+		return
+	}
+	c.seen[coordinate] = true
+	c.found = append(c.found, deprecation)
+}
+
+// possibleTypeNames returns a type's name and, for interfaces and unions, the names of the
+// object types a value of it can have
+func (c *deprecationCollector) possibleTypeNames(typeName string) []string {
+	names := []string{typeName}
+	definition := c.set.schema.Types[typeName]
+	if definition == nil || definition.Kind == ast.Object {
+		return names
+	}
+	for _, possible := range c.set.schema.GetPossibleTypes(definition) {
+		names = append(names, possible.Name)
+	}
+	return names
+}
+
+// DeprecatedFieldUsage is how often operations of a client used a deprecated field or argument
+type DeprecatedFieldUsage struct {
+	Coordinate string
+	Client     string
+	Count      int64
+}
+
+// deprecationUsageKey identifies one counter of the deprecation usage
+type deprecationUsageKey struct {
+	coordinate, client string
+}
+
+// deprecationUsage counts the operations using deprecated fields since startup
+var deprecationUsage = struct {
+	mu      sync.Mutex
+	counts  map[deprecationUsageKey]int64
+	clients map[string]bool
+}{counts: map[deprecationUsageKey]int64{}, clients: map[string]bool{}}
+
+// RecordDeprecatedUse counts an operation of a client using a deprecated field or argument.
+// Clients are named by a request header; an empty name counts as "unknown", and names beyond the
+// first maxDeprecationClients as "other", so the metric labels stay bounded
+func RecordDeprecatedUse(coordinate, client string) {
+	client = strings.TrimSpace(client)
+	if client == "" {
+		client = unknownDeprecationClient
+	}
+	if len(client) > maxDeprecationClientLength {
+		client = client[:maxDeprecationClientLength]
+	}
+
+	deprecationUsage.mu.Lock()
+	defer deprecationUsage.mu.Unlock()
+	if !deprecationUsage.clients[client] {
+		if len(deprecationUsage.clients) >= maxDeprecationClients {
+			client = otherDeprecationClient
+		} else {
+			deprecationUsage.clients[client] = true
+		}
+	}
+	deprecationUsage.counts[deprecationUsageKey{coordinate: coordinate, client: client}]++
+}
+
+// CurrentDeprecatedFieldUsage returns the deprecated field usage counts, ordered by coordinate and client
+func CurrentDeprecatedFieldUsage() []DeprecatedFieldUsage {
+	deprecationUsage.mu.Lock()
+	defer deprecationUsage.mu.Unlock()
+	usage := make([]DeprecatedFieldUsage, 0, len(deprecationUsage.counts))
+	for key, count := range deprecationUsage.counts {
+		usage = append(usage, DeprecatedFieldUsage{Coordinate: key.coordinate, Client: key.client, Count: count})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Coordinate != usage[j].Coordinate {
+			return usage[i].Coordinate < usage[j].Coordinate
+		}
+		return usage[i].Client < usage[j].Client
+	})
+	return usage
+}
+
+// SunsetError returns the GraphQL error of an operation using a field or argument past its
+// sunset date, carrying the FIELD_SUNSET code in its extensions
+func SunsetError(deprecation Deprecation) *gqlerror.Error {
+	return toGraphQLError(&QueryError{
+		Message: fmt.Sprintf("%s was removed on %s: %s", deprecation.Coordinate, deprecation.Sunset.Format(sunsetDateLayout), deprecation.Reason),
+		Code:    ErrCodeFieldSunset,
+	})
+}
+
+// SunsetRefusal returns the first of an operation's deprecations whose sunset date has passed,
+// and false when there is none or sunsets are not enforced
+func SunsetRefusal(ctx context.Context, used []Deprecation) (Deprecation, bool) {
+	if !currentDeprecations(ctx).enforceSunset {
+		return Deprecation{}, false
+	}
+	now := queryClock.Now()
+	for _, deprecation := range used {
+		if deprecation.SunsetPassed(now) {
+			return deprecation, true
+		}
+	}
+	return Deprecation{}, false
+}
@@ -0,0 +1,180 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+const deprecationTestSchema = `
+directive @sunset(date: String!) on FIELD_DEFINITION | ARGUMENT_DEFINITION
+
+type Query {
+  node: Node
+  search(order: String @deprecated(reason: "Use sort") @sunset(date: "2026-06-30"), sort: String): [Item!]!
+}
+
+interface Node { id: ID! }
+
+type Item implements Node {
+  id: ID!
+  legacyName: String @deprecated(reason: "Use name") @sunset(date: "2026-03-31")
+  oldCode: String @deprecated
+  name: String
+}
+`
+
+// useDeprecations reads the deprecations of the test schema for the rest of a test, with empty usage counts
+func useDeprecations(t *testing.T, enforceSunset bool) *ast.Schema {
+	t.Helper()
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: deprecationTestSchema})
+	require.NoError(t, SetDeprecations(schema, enforceSunset))
+	resetDeprecationUsage()
+	t.Cleanup(func() {
+		_ = SetDeprecations(&ast.Schema{}, false)
+		resetDeprecationUsage()
+	})
+	return schema
+}
+
+func resetDeprecationUsage() {
+	deprecationUsage.mu.Lock()
+	defer deprecationUsage.mu.Unlock()
+	deprecationUsage.counts = map[deprecationUsageKey]int64{}
+	deprecationUsage.clients = map[string]bool{}
+}
+
+// operationContext parses a query against the schema into the context gqlgen executes it with
+func operationContext(t *testing.T, schema *ast.Schema, query string, variables map[string]interface{}) *graphql.OperationContext {
+	t.Helper()
+	doc, errs := gqlparser.LoadQuery(schema, query)
+	require.Empty(t, errs)
+	return &graphql.OperationContext{Doc: doc, Operation: doc.Operations[0], Variables: variables}
+}
+
+// coordinates returns the coordinates of deprecations
+func coordinates(used []Deprecation) []string {
+	names := make([]string, 0, len(used))
+	for _, deprecation := range used {
+		names = append(names, deprecation.Coordinate)
+	}
+	return names
+}
+
+func TestSetDeprecations_AddsSunsetToReason(t *testing.T) {
+	schema := useDeprecations(t, false)
+
+	item := schema.Types["Item"]
+	reason := item.Fields.ForName("legacyName").Directives.ForName("deprecated").Arguments.ForName("reason")
+	assert.Equal(t, "Use name (sunset 2026-03-31)", reason.Value.Raw)
+	assert.Nil(t, item.Fields.ForName("oldCode").Directives.ForName("deprecated").Arguments.ForName("reason"))
+
+	// Reading the same schema again keeps a single note
+	require.NoError(t, SetDeprecations(schema, false))
+	assert.Equal(t, "Use name (sunset 2026-03-31)", reason.Value.Raw)
+	assert.Equal(t, Deprecation{
+		Coordinate: "Item.legacyName",
+		Reason:     "Use name",
+		Sunset:     time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	}, deprecations.Load().byCoordinate["Item.legacyName"])
+}
+
+func TestSetDeprecations_RejectsInvalidSunset(t *testing.T) {
+	invalid := gqlparser.MustLoadSchema(&ast.Source{Input: `
+directive @sunset(date: String!) on FIELD_DEFINITION
+type Query { a: String @deprecated @sunset(date: "31.03.2026") }`})
+	assert.EqualError(t, SetDeprecations(invalid, false), `Query.a has an invalid @sunset date "31.03.2026", expected YYYY-MM-DD`)
+
+	undeprecated := gqlparser.MustLoadSchema(&ast.Source{Input: `
+directive @sunset(date: String!) on FIELD_DEFINITION
+type Query { a: String @sunset(date: "2026-03-31") }`})
+	assert.EqualError(t, SetDeprecations(undeprecated, false), "Query.a has @sunset without @deprecated")
+}
+
+// Test the deprecated fields and arguments of an operation are found through fragments on
+// interfaces, once each, leaving out skipped fields
+func TestOperationDeprecations(t *testing.T) {
+	schema := useDeprecations(t, false)
+
+	oc := operationContext(t, schema, `query($skip: Boolean!) {
+  search(order: "name") { ...names oldCode @skip(if: $skip) }
+  node { id ... on Item { legacyName } }
+}
+fragment names on Item { a: legacyName b: legacyName name }`, map[string]interface{}{"skip": true})
+
+	assert.Equal(t, []string{"Query.search(order:)", "Item.legacyName"}, coordinates(OperationDeprecations(context.Background(), oc)))
+
+	oc = operationContext(t, schema, `{ search(sort: "name") { name } }`, nil)
+	assert.Empty(t, OperationDeprecations(context.Background(), oc))
+}
+
+// Test usage is counted per coordinate and client, bounding the client names
+func TestRecordDeprecatedUse(t *testing.T) {
+	useDeprecations(t, false)
+
+	RecordDeprecatedUse("Item.legacyName", "mobile-app")
+	RecordDeprecatedUse("Item.legacyName", "mobile-app")
+	RecordDeprecatedUse("Item.legacyName", " ")
+	RecordDeprecatedUse("Query.search(order:)", "mobile-app")
+
+	assert.Equal(t, []DeprecatedFieldUsage{
+		{Coordinate: "Item.legacyName", Client: "mobile-app", Count: 2},
+		{Coordinate: "Item.legacyName", Client: "unknown", Count: 1},
+		{Coordinate: "Query.search(order:)", Client: "mobile-app", Count: 1},
+	}, CurrentDeprecatedFieldUsage())
+
+	for i := 0; i < maxDeprecationClients; i++ {
+		RecordDeprecatedUse("Item.oldCode", string(rune('a'+i%26))+string(rune('a'+i/26)))
+	}
+	usage := CurrentDeprecatedFieldUsage()
+	assert.Contains(t, usage, DeprecatedFieldUsage{Coordinate: "Item.oldCode", Client: "other", Count: 2})
+}
+
+// Test deprecations past their sunset date are refused only while enforcement is on
+func TestSunsetRefusal(t *testing.T) {
+	clock := &stepClock{now: time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC)}
+	SetClock(clock)
+	t.Cleanup(func() { SetClock(db.RealClock{}) })
+
+	schema := useDeprecations(t, true)
+	used := OperationDeprecations(context.Background(), operationContext(t, schema, `{ search(order: "name") { legacyName oldCode } }`, nil))
+	require.Len(t, used, 3)
+
+	_, refused := SunsetRefusal(context.Background(), used)
+	assert.False(t, refused, "no sunset has passed yet")
+
+	clock.now = clock.now.Add(12 * time.Hour)
+	deprecation, refused := SunsetRefusal(context.Background(), used)
+	require.True(t, refused)
+	assert.Equal(t, "Item.legacyName", deprecation.Coordinate)
+	err := SunsetError(deprecation)
+	assert.Equal(t, "Item.legacyName was removed on 2026-03-31: Use name", err.Message)
+	assert.Equal(t, ErrCodeFieldSunset, err.Extensions["code"])
+
+	require.NoError(t, SetDeprecations(schema, false))
+	_, refused = SunsetRefusal(context.Background(), used)
+	assert.False(t, refused, "sunsets are not enforced")
+}
+
+// Test an operation reads the deprecation set of its context instead of the default one
+func TestWithDeprecations(t *testing.T) {
+	schema := useDeprecations(t, false)
+	oc := operationContext(t, schema, `{ search(order: "name") { name } }`, nil)
+
+	reloaded, err := LoadDeprecations(gqlparser.MustLoadSchema(&ast.Source{Input: `
+type Query { search(order: String): [Item] }
+type Item { name: String }`}), false)
+	require.NoError(t, err)
+	ctx := WithDeprecations(context.Background(), reloaded)
+
+	assert.Empty(t, OperationDeprecations(ctx, oc))
+	assert.Equal(t, []string{"Query.search(order:)"}, coordinates(OperationDeprecations(context.Background(), oc)))
+}
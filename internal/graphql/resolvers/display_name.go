@@ -0,0 +1,33 @@
+package resolvers
+
+import "strings"
+
+// displayNameFields are the document fields the display name is computed from
+var displayNameFields = []string{"firstName", "lastName", "userEmail"}
+
+// displayName implements the Customer.displayName and Employee.displayName field resolvers: the
+// first and last name joined by a space, the local part of the e-mail address when neither is
+// set, and the first group of the identifier when the address is not set either. Empty and
+// whitespace-only values count as not set. The name is computed from the document, so filters
+// and orders use firstName and lastName instead
+func displayName(firstName, lastName, email *string, identifier string) string {
+	var parts []string
+	for _, part := range []*string{firstName, lastName} {
+		if part != nil && strings.TrimSpace(*part) != "" {
+			parts = append(parts, strings.TrimSpace(*part))
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, " ")
+	}
+
+	if email != nil {
+		local, _, _ := strings.Cut(strings.TrimSpace(*email), "@")
+		if local != "" {
+			return local
+		}
+	}
+
+	prefix, _, _ := strings.Cut(identifier, "-")
+	return prefix
+}
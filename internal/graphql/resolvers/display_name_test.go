@@ -0,0 +1,75 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// Test every fallback of the display name, telling empty strings from nulls
+func TestDisplayName(t *testing.T) {
+	str := func(s string) *string { return &s }
+	identifier := "3f2b8c1d-9a4e-4b7f-8c2d-1e5f6a7b8c9d"
+
+	tests := []struct {
+		name      string
+		firstName *string
+		lastName  *string
+		email     *string
+		expected  string
+	}{
+		{"both names", str("Ada"), str("Lovelace"), str("ada@example.com"), "Ada Lovelace"},
+		{"names trimmed", str("  Ada "), str("\tLovelace"), nil, "Ada Lovelace"},
+		{"first name only", str("Ada"), nil, str("ada@example.com"), "Ada"},
+		{"last name only", nil, str("Lovelace"), nil, "Lovelace"},
+		{"empty first name", str(""), str("Lovelace"), nil, "Lovelace"},
+		{"blank last name", str("Ada"), str("  "), nil, "Ada"},
+		{"null names use e-mail", nil, nil, str("ada.lovelace@example.com"), "ada.lovelace"},
+		{"empty names use e-mail", str(""), str(" "), str(" ada.lovelace@example.com "), "ada.lovelace"},
+		{"e-mail without domain", nil, nil, str("ada"), "ada"},
+		{"null e-mail uses identifier", nil, nil, nil, "3f2b8c1d"},
+		{"empty e-mail uses identifier", str(""), nil, str(""), "3f2b8c1d"},
+		{"e-mail without local part uses identifier", nil, nil, str("@example.com"), "3f2b8c1d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, displayName(tt.firstName, tt.lastName, tt.email, identifier))
+		})
+	}
+}
+
+// Test the field resolvers of both entities read the document's fields
+func TestDisplayNameResolvers(t *testing.T) {
+	first, email := "Grace", "grace@example.com"
+	r := &Resolver{}
+
+	name, err := r.Customer().DisplayName(context.Background(), &generated.Customer{FirstName: &first, Identifier: "c0ffee00-0000-0000-0000-000000000000"})
+	require.NoError(t, err)
+	assert.Equal(t, "Grace", name)
+
+	name, err = r.Employee().DisplayName(context.Background(), &generated.Employee{UserEmail: &email, Identifier: "e0000000-0000-0000-0000-000000000000"})
+	require.NoError(t, err)
+	assert.Equal(t, "grace", name)
+}
+
+// Test selecting displayName keeps the document fields it is computed from
+func TestSearchDataProjection_DisplayName(t *testing.T) {
+	ctx := searchFieldContext(t, `{ customerSearch { data { displayName } } }`)
+	sortKeys := bson.D{{Key: "identifier", Value: 1}}
+
+	expected := bson.M{"$project": bson.M{
+		"identifier":  1,
+		"displayName": 1,
+		"firstName":   1,
+		"lastName":    1,
+		"userEmail":   1,
+	}}
+	assert.Equal(t, expected, searchDataProjection(ctx, entityConfigs["customer"], sortKeys, nil))
+	assert.Equal(t, expected, searchDataProjection(ctx, entityConfigs["employee"], sortKeys, nil))
+}
@@ -0,0 +1,130 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// A corrupted document, such as one with a runaway array written by another system, can take
+// many times its stored size once decoded into the generated structs, and a page of them much
+// more. Entity results therefore check the raw size of each document before decoding it:
+// oversized documents are left out of the result with a warning, or fail the query in strict
+// mode. The check is len(bson.Raw) on bytes read anyway
+
+// DefaultDocumentMaxBytes is the largest raw document decoded into an entity result by default
+const DefaultDocumentMaxBytes = 4 << 20
+
+// DocumentSizeGuard bounds the raw size of the documents decoded into entity results
+type DocumentSizeGuard struct {
+	MaxBytes int  // Largest raw document decoded; 0 disables the guard
+	Strict   bool // Fail the query on an oversized document instead of leaving it out
+}
+
+// documentSizeGuard is the guard applied to Get, byKeys, search and relation results
+var documentSizeGuard = DocumentSizeGuard{MaxBytes: DefaultDocumentMaxBytes}
+
+// SetDocumentSizeGuard sets the raw size bound of decoded documents
+// Must be called before serving queries
+func SetDocumentSizeGuard(guard DocumentSizeGuard) {
+	documentSizeGuard = guard
+}
+
+// OversizedDocumentStats counts the documents of one entity above the size bound
+type OversizedDocumentStats struct {
+	Entity    string
+	Documents int64
+}
+
+// oversizedDocuments counts the oversized documents per entity since startup
+var oversizedDocuments = struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}{counts: map[string]int64{}}
+
+// CurrentOversizedDocumentStats returns the oversized documents read per entity, ordered by entity
+func CurrentOversizedDocumentStats() []OversizedDocumentStats {
+	oversizedDocuments.mu.Lock()
+	defer oversizedDocuments.mu.Unlock()
+	stats := make([]OversizedDocumentStats, 0, len(oversizedDocuments.counts))
+	for entity, documents := range oversizedDocuments.counts {
+		stats = append(stats, OversizedDocumentStats{Entity: entity, Documents: documents})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Entity < stats[j].Entity })
+	return stats
+}
+
+// checkDocumentSize reports whether a raw document of an entity may be decoded. An oversized
+// document is counted and logged with its identifier and size; in strict mode it returns a
+// DATABASE_ERROR instead of false
+func checkDocumentSize(ctx context.Context, config EntityConfig, raw bson.Raw) (bool, error) {
+	guard := documentSizeGuard
+	if guard.MaxBytes <= 0 || len(raw) <= guard.MaxBytes {
+		return true, nil
+	}
+
+	entity := entityName(config)
+	oversizedDocuments.mu.Lock()
+	oversizedDocuments.counts[entity]++
+	oversizedDocuments.mu.Unlock()
+
+	identifier, _ := raw.Lookup("identifier").StringValueOK()
+	logger.FromContext(ctx).Warn().
+		Str("entity", entity).
+		Str("identifier", identifier).
+		Int("size_bytes", len(raw)).
+		Int("max_bytes", guard.MaxBytes).
+		Bool("strict", guard.Strict).
+		Msg("Oversized document not decoded")
+
+	if guard.Strict {
+		return false, &QueryError{
+			Message: fmt.Sprintf("A %s document of %d bytes exceeds the %d bytes decoded", entity, len(raw), guard.MaxBytes),
+			Code:    ErrCodeDatabaseError,
+			Entity:  config.CollectionName,
+		}
+	}
+	return false, nil
+}
+
+// decodeAllGuarded decodes and closes a cursor of entity documents into result, a pointer to a
+// slice of entity pointers, like cursor.All but checking each document's size first
+func decodeAllGuarded(ctx context.Context, config EntityConfig, cursor *mongo.Cursor, result interface{}) error {
+	sliceValue := reflect.ValueOf(result).Elem()
+	elemType := sliceValue.Type().Elem()
+
+	var oversized error
+	err := db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		for cursor.Next(ctx) {
+			decode, err := checkDocumentSize(ctx, config, cursor.Current)
+			if err != nil {
+				oversized = err
+				return nil
+			}
+			if !decode {
+				continue
+			}
+			elem := reflect.New(elemType.Elem())
+			if err := cursor.Decode(elem.Interface()); err != nil {
+				return err
+			}
+			sliceValue.Set(reflect.Append(sliceValue, elem))
+		}
+		return cursor.Err()
+	})
+	if oversized != nil {
+		return oversized
+	}
+	if err != nil {
+		return NewDatabaseError(err, msgDecodeFailed).WithEntity(config.CollectionName)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+const (
+	sizedCustomerID     = "6f1c2d3e-4a5b-4c6d-8e7f-000000000001"
+	oversizedCustomerID = "6f1c2d3e-4a5b-4c6d-8e7f-000000000002"
+	laterCustomerID     = "6f1c2d3e-4a5b-4c6d-8e7f-000000000003"
+)
+
+// useDocumentSizeGuard sets the guard for the rest of a test
+func useDocumentSizeGuard(t *testing.T, guard DocumentSizeGuard) {
+	t.Helper()
+	SetDocumentSizeGuard(guard)
+	t.Cleanup(func() { SetDocumentSizeGuard(DocumentSizeGuard{MaxBytes: DefaultDocumentMaxBytes}) })
+}
+
+// sizedCustomerDB holds two customers and, between them, one padded past 1KB
+func sizedCustomerDB() *fakeEntityDB {
+	customer := func(identifier string) bson.M {
+		return bson.M{"identifier": identifier, "status": bson.M{"deletion": "INIT"}}
+	}
+	oversized := customer(oversizedCustomerID)
+	oversized["padding"] = primitive.Binary{Data: make([]byte, 4096)}
+	return &fakeEntityDB{documents: map[string][]bson.M{
+		"customers": {customer(sizedCustomerID), oversized, customer(laterCustomerID)},
+	}}
+}
+
+func oversizedDocumentCount(entity string) int64 {
+	for _, stats := range CurrentOversizedDocumentStats() {
+		if stats.Entity == entity {
+			return stats.Documents
+		}
+	}
+	return 0
+}
+
+func customerIdentifiers(customers []*generated.Customer) []string {
+	identifiers := make([]string, 0, len(customers))
+	for _, customer := range customers {
+		identifiers = append(identifiers, customer.Identifier)
+	}
+	return identifiers
+}
+
+// TestDocumentSizeGuard_LeavesOutOversizedDocuments tests Get, byKeys and search results leave
+// out a document above the bound, count it and log its identifier and size
+func TestDocumentSizeGuard_LeavesOutOversizedDocuments(t *testing.T) {
+	useDocumentSizeGuard(t, DocumentSizeGuard{MaxBytes: 1024})
+	var buf bytes.Buffer
+	previousLogger := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = previousLogger })
+	database := sizedCustomerDB()
+	config := entityConfigs["customer"]
+	before := oversizedDocumentCount("customer")
+
+	var customer generated.Customer
+	require.NoError(t, getEntity(context.Background(), database, config, oversizedCustomerID, &customer))
+	assert.Empty(t, customer.Identifier, "an oversized document answers null")
+
+	var customers []*generated.Customer
+	identifiers := []string{sizedCustomerID, oversizedCustomerID, laterCustomerID}
+	require.NoError(t, getEntitiesByKeys(context.Background(), database, config, identifiers, nil, &customers))
+	assert.ElementsMatch(t, []string{sizedCustomerID, laterCustomerID}, customerIdentifiers(customers))
+
+	first := 10
+	var page []*generated.Customer
+	count, totalCount, _, _, _, _, err := searchEntities(context.Background(), database, config, nil, nil, SearchPaging{First: &first}, SearchOptions{IncludeHeavyFields: true}, &page)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 3, totalCount, "the total still counts the oversized document")
+	assert.ElementsMatch(t, []string{sizedCustomerID, laterCustomerID}, customerIdentifiers(page))
+
+	assert.Equal(t, before+3, oversizedDocumentCount("customer"))
+	warnings := 0
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		if event["message"] != "Oversized document not decoded" {
+			continue
+		}
+		warnings++
+		assert.Equal(t, oversizedCustomerID, event["identifier"])
+		assert.Greater(t, event["size_bytes"], float64(4096))
+	}
+	assert.Equal(t, 3, warnings)
+}
+
+// TestDocumentSizeGuard_StrictFailsQuery tests strict mode fails the query reading an oversized
+// document, while results within the bound are unaffected
+func TestDocumentSizeGuard_StrictFailsQuery(t *testing.T) {
+	useDocumentSizeGuard(t, DocumentSizeGuard{MaxBytes: 1024, Strict: true})
+	database := sizedCustomerDB()
+	config := entityConfigs["customer"]
+
+	var customer generated.Customer
+	err := getEntity(context.Background(), database, config, oversizedCustomerID, &customer)
+	var queryErr *QueryError
+	require.ErrorAs(t, err, &queryErr)
+	assert.Equal(t, ErrCodeDatabaseError, queryErr.Code)
+	assert.Contains(t, queryErr.Message, "exceeds the 1024 bytes decoded")
+
+	var customers []*generated.Customer
+	err = getEntitiesByKeys(context.Background(), database, config, []string{sizedCustomerID, oversizedCustomerID}, nil, &customers)
+	require.ErrorAs(t, err, &queryErr)
+	assert.Equal(t, ErrCodeDatabaseError, queryErr.Code)
+
+	first := 10
+	var page []*generated.Customer
+	_, _, _, _, _, _, err = searchEntities(context.Background(), database, config, nil, nil, SearchPaging{First: &first}, SearchOptions{IncludeHeavyFields: true}, &page)
+	require.ErrorAs(t, err, &queryErr)
+
+	customers = nil
+	require.NoError(t, getEntitiesByKeys(context.Background(), database, config, []string{sizedCustomerID, laterCustomerID}, nil, &customers))
+	assert.Len(t, customers, 2)
+}
@@ -0,0 +1,99 @@
+package resolvers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// emailFilterFields are the filter input fields holding e-mail addresses
+var emailFilterFields = map[string]bool{
+	"userEmail":     true,
+	"employeeEmail": true,
+}
+
+// normalizeEmail trims an e-mail address and lowercases it; mutations store addresses this way,
+// so eq and in filters match regardless of how the client typed the address
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// convertEmailFilter converts the StringFilterInput of an e-mail field after normalizing its values
+func convertEmailFilter(field, shadowField string, filter *generated.StringFilterInput) bson.M {
+	return convertShadowedStringFilter(field, shadowField, normalizeEmailFilter(filter))
+}
+
+// normalizeEmailFilter returns a copy of an e-mail field filter with every value trimmed and the
+// eq and in values lowercased. The pattern operators keep their case, as they match case-insensitively
+func normalizeEmailFilter(filter *generated.StringFilterInput) *generated.StringFilterInput {
+	if filter == nil {
+		return nil
+	}
+
+	normalized := &generated.StringFilterInput{
+		Eq:          mapEmailValue(filter.Eq, normalizeEmail),
+		Neq:         mapEmailValue(filter.Neq, strings.TrimSpace),
+		Contains:    mapEmailValue(filter.Contains, strings.TrimSpace),
+		Ncontains:   mapEmailValue(filter.Ncontains, strings.TrimSpace),
+		StartsWith:  mapEmailValue(filter.StartsWith, strings.TrimSpace),
+		NstartsWith: mapEmailValue(filter.NstartsWith, strings.TrimSpace),
+		EndsWith:    mapEmailValue(filter.EndsWith, strings.TrimSpace),
+		NendsWith:   mapEmailValue(filter.NendsWith, strings.TrimSpace),
+	}
+	// Lists keep nil and empty apart: an empty in list matches nothing, a nil one is no condition
+	if filter.In != nil {
+		normalized.In = make([]*string, len(filter.In))
+		for i, value := range filter.In {
+			normalized.In[i] = mapEmailValue(value, normalizeEmail)
+		}
+	}
+	if filter.Nin != nil {
+		normalized.Nin = make([]*string, len(filter.Nin))
+		for i, value := range filter.Nin {
+			normalized.Nin[i] = mapEmailValue(value, strings.TrimSpace)
+		}
+	}
+	for _, f := range filter.And {
+		normalized.And = append(normalized.And, normalizeEmailFilter(f))
+	}
+	for _, f := range filter.Or {
+		normalized.Or = append(normalized.Or, normalizeEmailFilter(f))
+	}
+	return normalized
+}
+
+// mapEmailValue applies normalize to an optional filter value
+func mapEmailValue(value *string, normalize func(string) string) *string {
+	if value == nil {
+		return nil
+	}
+	normalized := normalize(*value)
+	return &normalized
+}
+
+// validateEmailFilter fails when an eq value of an e-mail field, including those of its and/or
+// filters, is not a complete address: partial values never match by equality
+func validateEmailFilter(field string, filter *generated.StringFilterInput) error {
+	if filter == nil {
+		return nil
+	}
+	if filter.Eq != nil {
+		email := strings.TrimSpace(*filter.Eq)
+		if !strings.Contains(email, "@") || strings.IndexFunc(email, unicode.IsSpace) >= 0 {
+			return NewInvalidInput(fmt.Sprintf(
+				"%s eq must be a complete e-mail address, got %q; use contains to match part of an address",
+				field, *filter.Eq,
+			), "where")
+		}
+	}
+	for _, nested := range append(append([]*generated.StringFilterInput{}, filter.And...), filter.Or...) {
+		if err := validateEmailFilter(field, nested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// Test every operator of an e-mail filter with messy input
+func TestConvertEmailFilter_Operators(t *testing.T) {
+	messy := "  Ada.Lovelace@Example.COM \t"
+	trimmed := "Ada.Lovelace@Example.COM"
+	lower := "ada.lovelace@example.com"
+	partial := " Lovelace@Example "
+
+	tests := []struct {
+		name     string
+		filter   *generated.StringFilterInput
+		expected bson.M
+	}{
+		{"eq", &generated.StringFilterInput{Eq: &messy}, bson.M{"userEmail": lower}},
+		{"neq", &generated.StringFilterInput{Neq: &messy}, bson.M{"userEmail": bson.M{"$ne": trimmed}}},
+		{"in", &generated.StringFilterInput{In: []*string{&messy, nil}}, bson.M{"userEmail": bson.M{"$in": []*string{&lower, nil}}}},
+		{"nin", &generated.StringFilterInput{Nin: []*string{&messy}}, bson.M{"userEmail": bson.M{"$nin": []*string{&trimmed}}}},
+		{"contains", &generated.StringFilterInput{Contains: &partial}, bson.M{"userEmail": bson.M{"$regex": "Lovelace@Example", "$options": "i"}}},
+		{"startsWith", &generated.StringFilterInput{StartsWith: &partial}, bson.M{"userEmail": bson.M{"$regex": "^Lovelace@Example", "$options": "i"}}},
+		{"endsWith", &generated.StringFilterInput{EndsWith: &partial}, bson.M{"userEmail": bson.M{"$regex": "Lovelace@Example$", "$options": "i"}}},
+		{"nested", &generated.StringFilterInput{Or: []*generated.StringFilterInput{{Eq: &messy}, {Contains: &partial}}}, bson.M{"$or": []bson.M{
+			{"userEmail": lower},
+			{"userEmail": bson.M{"$regex": "Lovelace@Example", "$options": "i"}},
+		}}},
+		{"null check", &generated.StringFilterInput{}, bson.M{"userEmail": nil}},
+		{"empty in", &generated.StringFilterInput{In: []*string{}}, bson.M{"userEmail": bson.M{"$in": []*string{}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, convertEmailFilter("userEmail", "", tt.filter))
+		})
+	}
+	assert.Empty(t, convertEmailFilter("userEmail", "", nil))
+}
+
+// Test the input filter is left untouched, as a search converts it more than once
+func TestConvertEmailFilter_KeepsInput(t *testing.T) {
+	messy := " Ada@Example.com "
+	filter := &generated.StringFilterInput{Eq: &messy, In: []*string{&messy}}
+	convertEmailFilter("userEmail", "", filter)
+	assert.Equal(t, " Ada@Example.com ", *filter.Eq)
+	assert.Equal(t, " Ada@Example.com ", *filter.In[0])
+}
+
+// Test the customer and employee converters normalize their e-mail fields
+func TestConvertEntityFilters_NormalizeEmails(t *testing.T) {
+	messy := " Ada@Example.com "
+	assert.Equal(t, bson.M{"$and": []bson.M{{"userEmail": "ada@example.com"}, {"employeeEmail": "ada@example.com"}}},
+		convertCustomerFilter(&generated.CustomerQueryFilterInput{
+			UserEmail:     &generated.StringFilterInput{Eq: &messy},
+			EmployeeEmail: &generated.StringFilterInput{Eq: &messy},
+		}))
+	assert.Equal(t, bson.M{"userEmail": "ada@example.com"},
+		convertEmployeeFilter(&generated.EmployeeQueryFilterInput{UserEmail: &generated.StringFilterInput{Eq: &messy}}))
+}
+
+// Test eq values of e-mail fields must be complete addresses, wherever they are nested
+func TestValidateFilter_EmailValues(t *testing.T) {
+	valid := " Ada@Example.com "
+	noAt := "ada.example.com"
+	spaced := "ada @example.com"
+	email := func(filter *generated.StringFilterInput) *generated.CustomerQueryFilterInput {
+		return &generated.CustomerQueryFilterInput{EmployeeEmail: filter}
+	}
+
+	assert.NoError(t, validateFilter(email(&generated.StringFilterInput{Eq: &valid})))
+	assert.NoError(t, validateFilter(email(&generated.StringFilterInput{Contains: &noAt, Neq: &spaced, In: []*string{&noAt}})))
+	assert.NoError(t, validateFilter(&generated.CustomerQueryFilterInput{FirstName: &generated.StringFilterInput{Eq: &spaced}}))
+
+	err := validateFilter(email(&generated.StringFilterInput{Eq: &noAt}))
+	requireInvalidInput(t, err, `employeeEmail eq must be a complete e-mail address, got "ada.example.com"; use contains to match part of an address`)
+
+	err = validateFilter(&generated.EmployeeQueryFilterInput{UserEmail: &generated.StringFilterInput{Eq: &spaced}})
+	requireInvalidInput(t, err, "userEmail eq must be a complete e-mail address")
+
+	err = validateFilter(&generated.CustomerQueryFilterInput{And: []*generated.CustomerQueryFilterInput{
+		{UserEmail: &generated.StringFilterInput{Or: []*generated.StringFilterInput{{Eq: &valid}, {Eq: &noAt}}}},
+	}})
+	requireInvalidInput(t, err, "userEmail eq must be a complete e-mail address")
+}
+
+// Test created customers store their e-mail address normalized
+func TestNormalizeEmail(t *testing.T) {
+	assert.Equal(t, "ada@example.com", normalizeEmail("\tAda@Example.COM  "))
+	assert.Equal(t, "", normalizeEmail("   "))
+}
@@ -0,0 +1,149 @@
+package resolvers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/scalars"
+)
+
+// The entityCounts query answers from counts kept warm in the background, so dashboards loading
+// it do not count the collections on every request. A count keeps the time it was taken, so
+// clients can show how current it is; a count that fails to refresh keeps its previous value
+
+// entityCount is the number of non-deleted entities of one kind at refreshedAt
+type entityCount struct {
+	count       int64
+	refreshedAt time.Time
+}
+
+// entityCountCache holds the latest count of every entity
+type entityCountCache struct {
+	mu      sync.RWMutex
+	counts  map[string]entityCount
+	refresh sync.Mutex // Serializes refreshes, so concurrent cold queries count only once
+}
+
+// entityCounts are the counts the entityCounts query answers from
+var entityCounts = &entityCountCache{counts: map[string]entityCount{}}
+
+// ResetEntityCounts discards the cached counts, so the next entityCounts query counts anew
+func ResetEntityCounts() {
+	entityCounts.mu.Lock()
+	defer entityCounts.mu.Unlock()
+	entityCounts.counts = map[string]entityCount{}
+}
+
+// RefreshEntityCounts counts the non-deleted entities of every enabled entity and caches the
+// counts. Entities whose count fails keep their previous count; the first error is returned
+func RefreshEntityCounts(ctx context.Context, client DBClient) error {
+	entityCounts.refresh.Lock()
+	defer entityCounts.refresh.Unlock()
+	return entityCounts.refreshLocked(ctx, client)
+}
+
+// refreshLocked counts every enabled entity; the caller holds the refresh lock
+func (c *entityCountCache) refreshLocked(ctx context.Context, client DBClient) error {
+	var firstErr error
+	for _, entity := range entityNames() {
+		if EntityDisabled(entity) {
+			continue
+		}
+		config := entityConfigs[entity]
+		filter := config.ExcludeDeleted(bson.M{})
+		count, err := client.ReadCollection(config.CollectionName).CountDocuments(ctx, filter)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = withEntity(NewDatabaseError(err, "Failed to count entities"), config.CollectionName)
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		c.counts[entity] = entityCount{count: count, refreshedAt: queryClock.Now()}
+		c.mu.Unlock()
+	}
+	return firstErr
+}
+
+// ScheduleEntityCounts refreshes the entity counts every interval until ctx ends. Failed
+// refreshes are logged and retried at the next interval
+func ScheduleEntityCounts(ctx context.Context, client DBClient, interval time.Duration, logger zerolog.Logger) {
+	for {
+		if err := RefreshEntityCounts(ctx, client); err != nil && ctx.Err() == nil {
+			logger.Error().Err(err).Dur("retry_in", interval).Msg("Failed to refresh entity counts")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-queryClock.After(interval):
+		}
+	}
+}
+
+// snapshot returns the cached counts ordered by entity
+func (c *entityCountCache) snapshot() []*generated.EntityCount {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	counts := make([]*generated.EntityCount, 0, len(c.counts))
+	for entity, cached := range c.counts {
+		if EntityDisabled(entity) {
+			continue
+		}
+		counts = append(counts, &generated.EntityCount{
+			Entity:      entity,
+			Count:       cached.count,
+			RefreshedAt: scalars.FormatDateTime(cached.refreshedAt),
+		})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Entity < counts[j].Entity })
+	return counts
+}
+
+// empty reports whether no entity has been counted yet
+func (c *entityCountCache) empty() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.counts) == 0
+}
+
+// resolveEntityCounts implements the entityCounts query resolver. The counts are served from the
+// cache; before the first refresh completed they are taken once, and admins can force a refresh.
+// Counts are totals regardless of the caller's authorization scope
+func resolveEntityCounts(ctx context.Context, dbClient interface{}, refresh *bool) ([]*generated.EntityCount, error) {
+	if _, err := requireAuth(ctx); err != nil {
+		return nil, err
+	}
+	force := refresh != nil && *refresh
+	if force {
+		if _, err := requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if !force && !entityCounts.empty() {
+		return entityCounts.snapshot(), nil
+	}
+
+	client, ok := dbClient.(DBClient)
+	if !ok {
+		return nil, newDatabaseUnavailableError()
+	}
+	entityCounts.refresh.Lock()
+	defer entityCounts.refresh.Unlock()
+	// A concurrent cold query may have counted while this one waited
+	if force || entityCounts.empty() {
+		if err := entityCounts.refreshLocked(ctx, client); err != nil {
+			return nil, err
+		}
+	}
+	return entityCounts.snapshot(), nil
+}
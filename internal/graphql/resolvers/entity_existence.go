@@ -0,0 +1,113 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// Get queries answer null, and byKeys queries omit, both identifiers that never existed and
+// deleted entities, so callers cannot tell them apart from the entity queries. The entityExistence
+// query tells them apart for support staff, reading only the deletion markers
+
+// existenceRole is the role allowed to check entity existence besides admins
+const existenceRole = "SUPPORT"
+
+// canCheckExistence reports whether the claims carry the SUPPORT or an admin role
+func canCheckExistence(claims *UserClaims) bool {
+	if isAdmin(claims) {
+		return true
+	}
+	for _, role := range claims.Roles {
+		if role == existenceRole {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveEntityExistence implements the entityExistence query resolver. The identifiers are read
+// in one query that includes deleted entities, projected to their identifier and deletion marker.
+// The caller's authorization scope applies, so entities outside it are UNKNOWN
+func resolveEntityExistence(ctx context.Context, dbClient interface{}, entity string, identifiers []string) (result []*generated.EntityExistence, err error) {
+	claims, err := requireAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !canCheckExistence(claims) {
+		return nil, newForbiddenError("SUPPORT or administrator role required")
+	}
+
+	config, err := lookupEntityConfig(entity)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = withQuery(ctx, err, config) }()
+
+	if err := validateBatchSizeGeneric(identifiers); err != nil {
+		return nil, err
+	}
+	for _, id := range identifiers {
+		if reason := checkUUID(id); reason != "" {
+			return nil, newInvalidInputError(reason)
+		}
+	}
+
+	states := map[string]generated.ExistenceState{}
+	if len(identifiers) > 0 {
+		client, ok := dbClient.(DBClient)
+		if !ok {
+			return nil, newDatabaseUnavailableError()
+		}
+		states, err = existenceStates(ctx, client.ReadCollection(config.CollectionName), config, deduplicateIdentifiersGeneric(identifiers))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result = make([]*generated.EntityExistence, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		state, ok := states[identifier]
+		if !ok {
+			state = generated.ExistenceStateUnknown
+		}
+		result = append(result, &generated.EntityExistence{Identifier: identifier, State: state})
+	}
+	return result, nil
+}
+
+// existenceStates reads the deletion markers of the identifiers' entities in the caller's scope
+// and returns the state of every one found
+func existenceStates(ctx context.Context, collection db.Collection, config EntityConfig, identifiers []string) (map[string]generated.ExistenceState, error) {
+	filter := applyAuthorizationFilter(ctx, config, bson.M{"identifier": bson.M{"$in": identifiers}})
+	projection := bson.M{"_id": 0, "identifier": 1, config.DeletionField: 1}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, mapMongoError(err)
+	}
+	var documents []bson.Raw
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		return cursor.All(ctx, &documents)
+	})
+	if err != nil {
+		return nil, mapMongoError(err)
+	}
+
+	deletionPath := strings.Split(config.DeletionField, ".")
+	states := make(map[string]generated.ExistenceState, len(documents))
+	for _, document := range documents {
+		identifier, _ := document.Lookup("identifier").StringValueOK()
+		state := generated.ExistenceStateActive
+		if deletion, _ := document.Lookup(deletionPath...).StringValueOK(); deletion == config.DeletionValue {
+			state = generated.ExistenceStateDeleted
+		}
+		states[identifier] = state
+	}
+	return states, nil
+}
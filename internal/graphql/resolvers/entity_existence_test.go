@@ -0,0 +1,77 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+func (c *fakeEntityCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return mongo.NewCursorFromDocuments(c.matching(filter.(bson.M)), nil, nil)
+}
+
+const existenceUnknownID = "6f1c2d3e-4a5b-4c6d-8e7f-001122334457"
+
+// Test active, deleted and unknown identifiers of entities with different deletion markers, in
+// the order asked for
+func TestResolveEntityExistence_States(t *testing.T) {
+	ctx := WithUserClaims(context.Background(), &UserClaims{UserID: "support", Roles: []string{"ADMIN"}})
+
+	for _, entity := range []string{"customer", "inventory"} {
+		t.Run(entity, func(t *testing.T) {
+			config := entityConfigs[entity]
+			stored := parityEntities[entity]
+			active := bson.M{"identifier": parityActiveID}
+			deleted := bson.M{"identifier": parityDeletedID}
+			for key, value := range stored.active {
+				active[key] = value
+			}
+			for key, value := range stored.deleted {
+				deleted[key] = value
+			}
+			dbClient := &fakeEntityDB{documents: map[string][]bson.M{config.CollectionName: {active, deleted}}}
+
+			result, err := resolveEntityExistence(ctx, dbClient, entity, []string{existenceUnknownID, parityDeletedID, parityActiveID})
+			require.NoError(t, err)
+			assert.Equal(t, []*generated.EntityExistence{
+				{Identifier: existenceUnknownID, State: generated.ExistenceStateUnknown},
+				{Identifier: parityDeletedID, State: generated.ExistenceStateDeleted},
+				{Identifier: parityActiveID, State: generated.ExistenceStateActive},
+			}, result)
+		})
+	}
+}
+
+func TestResolveEntityExistence_RequiresRole(t *testing.T) {
+	user := WithUserClaims(context.Background(), &UserClaims{UserID: "user", Roles: []string{"DEVELOPER"}})
+	_, err := resolveEntityExistence(user, &fakeEntityDB{}, "customer", []string{parityActiveID})
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeForbidden, queryErr.Code)
+
+	support := WithUserClaims(context.Background(), &UserClaims{UserID: "support", Roles: []string{"SUPPORT"}})
+	result, err := resolveEntityExistence(support, &fakeEntityDB{}, "inventory", []string{parityActiveID})
+	require.NoError(t, err)
+	assert.Equal(t, generated.ExistenceStateUnknown, result[0].State)
+}
+
+func TestResolveEntityExistence_InvalidArguments(t *testing.T) {
+	ctx := WithUserClaims(context.Background(), &UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+
+	_, err := resolveEntityExistence(ctx, &fakeEntityDB{}, "customers", []string{parityActiveID})
+	assert.Error(t, err)
+	_, err = resolveEntityExistence(ctx, &fakeEntityDB{}, "customer", []string{"not-a-uuid"})
+	assert.Error(t, err)
+
+	result, err := resolveEntityExistence(ctx, nil, "customer", []string{})
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
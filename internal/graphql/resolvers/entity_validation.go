@@ -0,0 +1,122 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// entityValidationSampleSize bounds the documents sampled per collection when looking for the deletion field
+const entityValidationSampleSize = 100
+
+// EntityConfigProblem reports an entity whose configuration does not match the live database
+type EntityConfigProblem struct {
+	Entity     string
+	Collection string
+	Problem    string
+}
+
+func (p EntityConfigProblem) String() string {
+	return fmt.Sprintf("%s (%s): %s", p.Entity, p.Collection, p.Problem)
+}
+
+// ValidateEntityCollections checks every entity config against the live database:
+// the collection must exist and its deletion field must appear in a sampled document or an index.
+// Empty collections only have their existence checked.
+// Returns an error when the check cannot run, or in strict mode when problems were found
+func ValidateEntityCollections(ctx context.Context, database db.Database, strict bool) ([]EntityConfigProblem, error) {
+	if database == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	names, err := database.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+
+	entities := enabledEntityNames()
+
+	problems := []EntityConfigProblem{}
+	for _, entity := range entities {
+		config := entityConfigs[entity]
+		problem := EntityConfigProblem{Entity: entity, Collection: config.CollectionName}
+
+		if !existing[config.CollectionName] {
+			problem.Problem = "collection does not exist"
+			problems = append(problems, problem)
+			continue
+		}
+
+		found, err := deletionFieldPresent(ctx, database.Collection(config.CollectionName), config.DeletionField)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect collection %s: %w", config.CollectionName, err)
+		}
+		if !found {
+			problem.Problem = fmt.Sprintf("deletion field %q not found in sampled documents or indexes", config.DeletionField)
+			problems = append(problems, problem)
+		}
+	}
+
+	if strict && len(problems) > 0 {
+		descriptions := make([]string, 0, len(problems))
+		for _, problem := range problems {
+			descriptions = append(descriptions, problem.String())
+		}
+		return problems, fmt.Errorf("entity config validation failed: %s", strings.Join(descriptions, "; "))
+	}
+
+	return problems, nil
+}
+
+// deletionFieldPresent reports whether the field is indexed or set in a sample of documents
+// Empty collections report true, since there is nothing to contradict the config
+func deletionFieldPresent(ctx context.Context, collection db.Collection, field string) (bool, error) {
+	indexKeys, err := collection.IndexKeys(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, keys := range indexKeys {
+		for _, key := range keys {
+			if key.Key == field {
+				return true, nil
+			}
+		}
+	}
+
+	cursor, err := collection.Aggregate(ctx, []bson.M{
+		{"$sample": bson.M{"size": entityValidationSampleSize}},
+		{"$group": bson.M{
+			"_id":       nil,
+			"sampled":   bson.M{"$sum": 1},
+			"withField": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$ne": bson.A{bson.M{"$type": "$" + field}, "missing"}}, 1, 0}}},
+		}},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var results []struct {
+		Sampled   int `bson:"sampled"`
+		WithField int `bson:"withField"`
+	}
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		return cursor.All(ctx, &results)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if len(results) == 0 || results[0].Sampled == 0 {
+		return true, nil
+	}
+	return results[0].WithField > 0, nil
+}
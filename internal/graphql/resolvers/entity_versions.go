@@ -0,0 +1,101 @@
+package resolvers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// EntityVersions collects the versions of the entities an operation's Get resolvers read, so the
+// transport can tag the response without hashing it. A response is covered by the versions only
+// when every root field read exactly one versioned entity and no field resolver read more data
+type EntityVersions struct {
+	mu         sync.Mutex
+	versions   []string
+	rootFields int
+	untracked  bool // A field resolver read data the versions do not cover
+}
+
+// entityVersionsKey is the context key of the EntityVersions of an operation
+type entityVersionsKey struct{}
+
+// WithEntityVersions returns a context whose Get resolvers record the versions they read
+func WithEntityVersions(ctx context.Context) (context.Context, *EntityVersions) {
+	versions := &EntityVersions{}
+	return context.WithValue(ctx, entityVersionsKey{}, versions), versions
+}
+
+// TrackEntityVersions is a gqlgen field middleware counting the root fields of operations that
+// collect entity versions, and marking them untracked when a nested field resolver runs
+func TrackEntityVersions(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	versions, ok := ctx.Value(entityVersionsKey{}).(*EntityVersions)
+	fc := graphql.GetFieldContext(ctx)
+	if !ok || fc == nil {
+		return next(ctx)
+	}
+
+	versions.mu.Lock()
+	if fc.Object == "Query" {
+		versions.rootFields++
+	} else if fc.IsResolver {
+		versions.untracked = true
+	}
+	versions.mu.Unlock()
+	return next(ctx)
+}
+
+// recordEntityVersion records the version of an entity read by a Get resolver; document is nil
+// when the entity was not found. Entities without a version counter leave the operation untagged
+func recordEntityVersion(ctx context.Context, config EntityConfig, identifier string, document bson.Raw) {
+	versions, ok := ctx.Value(entityVersionsKey{}).(*EntityVersions)
+	if !ok || !config.Versioned {
+		return
+	}
+
+	version := entityName(config) + "/" + identifier
+	if document == nil {
+		version += "@-"
+	} else {
+		version += "@" + rawFieldString(document, "version") + "@" + rawFieldString(document, "lastUpdateDate")
+	}
+
+	versions.mu.Lock()
+	defer versions.mu.Unlock()
+	versions.versions = append(versions.versions, version)
+}
+
+// ETag returns the weak entity tag of the operation's response to a request, identified by
+// its query, variables and caller. ok is false when the versions do not cover the response
+func (v *EntityVersions) ETag(request string) (etag string, ok bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.untracked || v.rootFields == 0 || len(v.versions) != v.rootFields {
+		return "", false
+	}
+
+	// Root fields resolve concurrently, so their versions are recorded in any order
+	versions := append([]string(nil), v.versions...)
+	sort.Strings(versions)
+
+	hash := sha256.New()
+	hash.Write([]byte(request))
+	for _, version := range versions {
+		hash.Write([]byte{0})
+		hash.Write([]byte(version))
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, true
+}
+
+// rawFieldString returns the value of a top-level document field as a string, or "" when missing
+func rawFieldString(document bson.Raw, key string) string {
+	value, err := document.LookupErr(key)
+	if err != nil {
+		return ""
+	}
+	return value.String()
+}
@@ -1,10 +1,15 @@
 package resolvers
 
 import (
+	"context"
 	"errors"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rs/zerolog"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
 )
 
 // Error codes for GraphQL responses
@@ -14,15 +19,139 @@ const (
 	ErrCodeUnauthorized        = "UNAUTHORIZED"
 	ErrCodeForbidden           = "FORBIDDEN"
 	ErrCodeDatabaseError       = "DATABASE_ERROR"
+	ErrCodeTimeout             = "TIMEOUT"
 	ErrCodeExternalService     = "EXTERNAL_SERVICE_ERROR"
 	ErrCodeInternalServerError = "INTERNAL_SERVER_ERROR"
+	ErrCodeOperationNotAllowed = "OPERATION_NOT_ALLOWED"
+	ErrCodeResourceExhausted   = "RESOURCE_EXHAUSTED"
+	ErrCodeServiceUnavailable  = "SERVICE_UNAVAILABLE"
+	ErrCodeFeatureDisabled     = "FEATURE_DISABLED"
+	ErrCodeCursorExpired       = "CURSOR_EXPIRED"
+	ErrCodeConflict            = "CONFLICT"
+	ErrCodeReadOnly            = "READ_ONLY"
+	ErrCodeFieldSunset         = "FIELD_SUNSET"
+)
+
+// Standard messages of database failures
+const (
+	msgDatabaseUnavailable = "Database not available"
+	msgQueryFailed         = "Database query failed"
+	msgDecodeFailed        = "Failed to decode entities"
 )
 
 // QueryError represents a custom GraphQL error with an error code
+// Field and Entity optionally name the argument and the entity's collection the error is about.
+// EntityType and Query name the entity and the resolver of errors returned by the generic
+// queries, so support can tell which lookup failed
 type QueryError struct {
-	Message string
-	Code    string
-	Cause   error
+	Message    string
+	Code       string
+	Cause      error
+	Field      string
+	Entity     string
+	EntityType string
+	Query      string
+}
+
+// NewDatabaseError returns a DATABASE_ERROR wrapping the cause of a failed database operation
+// Causes that are timeouts return a TIMEOUT error instead
+func NewDatabaseError(cause error, message string) *QueryError {
+	if isTimeout(cause) {
+		return NewTimeout(cause, message)
+	}
+	return &QueryError{Message: message, Code: ErrCodeDatabaseError, Cause: cause}
+}
+
+// NewTimeout returns a TIMEOUT error wrapping the cause of an operation that exceeded its deadline
+func NewTimeout(cause error, message string) *QueryError {
+	return &QueryError{Message: message, Code: ErrCodeTimeout, Cause: cause}
+}
+
+// NewInvalidInput returns an INVALID_INPUT error; field names the offending argument (empty when none)
+func NewInvalidInput(message, field string) *QueryError {
+	return &QueryError{Message: message, Code: ErrCodeInvalidInput, Field: field}
+}
+
+// newDatabaseUnavailableError returns the error of resolvers running without a database
+func newDatabaseUnavailableError() *QueryError {
+	return &QueryError{Message: msgDatabaseUnavailable, Code: ErrCodeDatabaseError}
+}
+
+// WithEntity sets the entity the error is about and returns the error
+func (e *QueryError) WithEntity(entity string) *QueryError {
+	e.Entity = entity
+	return e
+}
+
+// WithCause sets the error's cause and returns the error
+func (e *QueryError) WithCause(cause error) *QueryError {
+	e.Cause = cause
+	return e
+}
+
+// withEntity sets the entity of the QueryError in an error's chain unless it names one already
+func withEntity(err error, entity string) error {
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) && queryErr.Entity == "" {
+		queryErr.Entity = entity
+	}
+	return err
+}
+
+// withQuery sets the entity type and the resolver of the QueryError in an error's chain unless it
+// names them already; the resolver is the gqlgen field being resolved in ctx, if any
+func withQuery(ctx context.Context, err error, config EntityConfig) error {
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		return err
+	}
+	if queryErr.EntityType == "" {
+		queryErr.EntityType = entityName(config)
+	}
+	if queryErr.Query == "" {
+		queryErr.Query = resolverName(ctx)
+	}
+	return err
+}
+
+// resolverName returns the name of the gqlgen field being resolved in ctx, or "" outside gqlgen
+func resolverName(ctx context.Context) string {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Field.Field == nil {
+		return ""
+	}
+	return fc.Field.Name
+}
+
+// sensitiveErrorCodes are the codes whose GraphQL extensions leave out the entity type and
+// resolver, so a caller refused access learns nothing about what it asked for. Their logs keep them
+var sensitiveErrorCodes = map[string]bool{
+	ErrCodeUnauthorized:        true,
+	ErrCodeForbidden:           true,
+	ErrCodeInternalServerError: true,
+}
+
+// queryErrorFields adds the code, entity type and resolver of the QueryError in an error's
+// chain to a log event
+func queryErrorFields(err error) func(*zerolog.Event) {
+	return func(event *zerolog.Event) {
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) {
+			return
+		}
+		event.Str("error_code", queryErr.Code)
+		if queryErr.EntityType != "" {
+			event.Str("entity_type", queryErr.EntityType)
+		}
+		if queryErr.Query != "" {
+			event.Str("resolver", queryErr.Query)
+		}
+	}
+}
+
+// isTimeout reports whether an error is a deadline being exceeded, by the context or the driver
+func isTimeout(err error) bool {
+	return err != nil && (errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err))
 }
 
 // Error implements the error interface
@@ -37,9 +166,25 @@ func (e *QueryError) Unwrap() error {
 
 // Extensions returns the error extensions for GraphQL response
 func (e *QueryError) Extensions() map[string]interface{} {
-	return map[string]interface{}{
+	extensions := map[string]interface{}{
 		"code": e.Code,
 	}
+	if e.Field != "" {
+		extensions["field"] = e.Field
+	}
+	if e.Entity != "" {
+		extensions["entity"] = e.Entity
+	}
+	if sensitiveErrorCodes[e.Code] {
+		return extensions
+	}
+	if e.EntityType != "" {
+		extensions["entityType"] = e.EntityType
+	}
+	if e.Query != "" {
+		extensions["query"] = e.Query
+	}
+	return extensions
 }
 
 // mapMongoError maps MongoDB errors to GraphQL errors with appropriate error codes
@@ -57,29 +202,36 @@ func mapMongoError(err error) error {
 		}
 	}
 
+	// Handle writes refused by a read-only database client
+	if errors.Is(err, db.ErrReadOnly) {
+		return &QueryError{Message: "The server is read-only", Code: ErrCodeReadOnly, Cause: err}
+	}
+
+	// Handle timeouts before connection errors, as the driver reports some as both
+	if isTimeout(err) {
+		return NewTimeout(err, "Database operation timed out")
+	}
+
 	// Handle MongoDB connection errors
-	if mongo.IsTimeout(err) || mongo.IsNetworkError(err) {
-		return &QueryError{
-			Message: "Database connection failed",
-			Code:    ErrCodeDatabaseError,
-			Cause:   err,
-		}
+	if mongo.IsNetworkError(err) {
+		return NewDatabaseError(err, "Database connection failed")
 	}
 
 	// Default database error
+	return NewDatabaseError(err, "Database operation failed")
+}
+
+// newNotFoundError creates a new not found error
+func newNotFoundError(message string) error {
 	return &QueryError{
-		Message: "Database operation failed",
-		Code:    ErrCodeDatabaseError,
-		Cause:   err,
+		Message: message,
+		Code:    ErrCodeNotFound,
 	}
 }
 
 // newInvalidInputError creates a new invalid input error
 func newInvalidInputError(message string) error {
-	return &QueryError{
-		Message: message,
-		Code:    ErrCodeInvalidInput,
-	}
+	return NewInvalidInput(message, "")
 }
 
 // newUnauthorizedError creates a new unauthorized error
@@ -116,10 +268,8 @@ func toGraphQLError(err error) *gqlerror.Error {
 	var queryErr *QueryError
 	if errors.As(err, &queryErr) {
 		return &gqlerror.Error{
-			Message: queryErr.Message,
-			Extensions: map[string]interface{}{
-				"code": queryErr.Code,
-			},
+			Message:    queryErr.Message,
+			Extensions: queryErr.Extensions(),
 		}
 	}
 
@@ -131,3 +281,23 @@ func toGraphQLError(err error) *gqlerror.Error {
 		},
 	}
 }
+
+// ErrorPresenter presents resolver errors like gqlgen's default presenter and adds the
+// extensions of a QueryError anywhere in the error chain
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var queryErr *QueryError
+	if gqlErr == nil || !errors.As(err, &queryErr) {
+		return gqlErr
+	}
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+	for key, value := range queryErr.Extensions() {
+		if _, set := gqlErr.Extensions[key]; !set {
+			gqlErr.Extensions[key] = value
+		}
+	}
+	return gqlErr
+}
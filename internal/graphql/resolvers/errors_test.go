@@ -0,0 +1,171 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// TestQueryError_WrapsCause tests errors.Is and errors.As reach the cause through a QueryError,
+// also when the QueryError is wrapped again
+func TestQueryError_WrapsCause(t *testing.T) {
+	err := fmt.Errorf("loading page: %w", NewDatabaseError(fmt.Errorf("aggregate: %w", context.DeadlineExceeded), msgQueryFailed))
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeTimeout, queryErr.Code)
+	assert.Equal(t, msgQueryFailed, queryErr.Error())
+}
+
+// TestNewDatabaseError_Codes tests only timeouts leave the DATABASE_ERROR code
+func TestNewDatabaseError_Codes(t *testing.T) {
+	assert.Equal(t, ErrCodeDatabaseError, NewDatabaseError(errors.New("boom"), msgQueryFailed).Code)
+	assert.Equal(t, ErrCodeTimeout, NewDatabaseError(context.DeadlineExceeded, msgQueryFailed).Code)
+	assert.Equal(t, ErrCodeDatabaseError, NewDatabaseError(context.Canceled, msgQueryFailed).Code)
+
+	mapped := mapMongoError(fmt.Errorf("find: %w", context.DeadlineExceeded))
+	assert.True(t, errors.Is(mapped, context.DeadlineExceeded))
+	assert.Equal(t, ErrCodeTimeout, mapped.(*QueryError).Code)
+	assert.Equal(t, ErrCodeNotFound, mapMongoError(mongo.ErrNoDocuments).(*QueryError).Code)
+}
+
+// TestQueryError_Extensions tests field and entity appear in the extensions only when set
+func TestQueryError_Extensions(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"code": ErrCodeInvalidInput}, NewInvalidInput("bad", "").Extensions())
+	assert.Equal(t, map[string]interface{}{"code": ErrCodeInvalidInput, "field": "first"}, NewInvalidInput("bad", "first").Extensions())
+
+	err := withEntity(NewDatabaseError(errors.New("boom"), msgDecodeFailed), "customers")
+	assert.Equal(t, map[string]interface{}{"code": ErrCodeDatabaseError, "entity": "customers"}, err.(*QueryError).Extensions())
+	assert.Equal(t, "customers", withEntity(err, "employees").(*QueryError).Entity)
+
+	err = &QueryError{Message: "boom", Code: ErrCodeDatabaseError, EntityType: "team", Query: "teamGet"}
+	assert.Equal(t, map[string]interface{}{"code": ErrCodeDatabaseError, "entityType": "team", "query": "teamGet"}, err.(*QueryError).Extensions())
+	err = &QueryError{Message: "no", Code: ErrCodeForbidden, EntityType: "team", Query: "teamGet"}
+	assert.Equal(t, map[string]interface{}{"code": ErrCodeForbidden}, err.(*QueryError).Extensions(), "sensitive codes name neither")
+}
+
+// TestErrorPresenter tests the presenter keeps gqlgen's message and adds the extensions of
+// a QueryError found in the chain
+func TestErrorPresenter(t *testing.T) {
+	ctx := graphql.WithFieldContext(context.Background(), &graphql.FieldContext{})
+
+	presented := ErrorPresenter(ctx, fmt.Errorf("search: %w", NewInvalidInput("'first' must be non-negative", "first")))
+	assert.Equal(t, "search: 'first' must be non-negative", presented.Message)
+	assert.Equal(t, map[string]interface{}{"code": ErrCodeInvalidInput, "field": "first"}, presented.Extensions)
+
+	presented = ErrorPresenter(ctx, errors.New("plain"))
+	assert.Equal(t, "plain", presented.Message)
+	assert.Nil(t, presented.Extensions)
+
+	// Extensions set by the resolver win over the QueryError's
+	gqlErr := FeatureDisabledError("customer")
+	gqlErr.Err = NewInvalidInput("bad", "first")
+	assert.Equal(t, ErrCodeFeatureDisabled, ErrorPresenter(ctx, gqlErr).Extensions["code"])
+
+	assert.Nil(t, ErrorPresenter(ctx, nil))
+}
+
+// failingDB fails every FindOne and Aggregate with a network error
+type failingDB struct{}
+
+func (f failingDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f failingDB) Collection(name string) db.Collection                       { return failingCollection{} }
+func (f failingDB) ReadCollection(name string) db.Collection                   { return failingCollection{} }
+func (f failingDB) IsConnected() bool                                          { return true }
+
+type failingCollection struct {
+	db.Collection
+}
+
+var errForcedFailure = errors.New("connection reset by peer")
+
+func (c failingCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	return mongo.NewSingleResultFromDocument(bson.M{}, errForcedFailure, nil)
+}
+
+func (c failingCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return nil, errForcedFailure
+}
+
+// resolverContext is the context gqlgen resolves a Query field in
+func resolverContext(field string) context.Context {
+	return graphql.WithFieldContext(context.Background(), &graphql.FieldContext{
+		Object: "Query",
+		Field:  graphql.CollectedField{Field: &ast.Field{Name: field}},
+	})
+}
+
+// TestQueryError_NamesEntityAndResolver tests database failures of the Get, byKeys and search
+// resolvers of different entities name the entity type and the resolver in the GraphQL error
+// extensions and in the error log
+func TestQueryError_NamesEntityAndResolver(t *testing.T) {
+	var buf bytes.Buffer
+	previousLogger := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = previousLogger })
+
+	query := NewResolver(failingDB{}).Query()
+	first := int64(10)
+	tests := []struct {
+		entity, resolver string
+		call             func(ctx context.Context) error
+	}{
+		{"customer", "customerGet", func(ctx context.Context) error {
+			_, err := query.CustomerGet(ctx, activeCustomerID)
+			return err
+		}},
+		{"employee", "employeeByKeysGet", func(ctx context.Context) error {
+			_, err := query.EmployeeByKeysGet(ctx, []string{activeCustomerID}, nil)
+			return err
+		}},
+		{"team", "teamSearch", func(ctx context.Context) error {
+			_, err := query.TeamSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resolver, func(t *testing.T) {
+			buf.Reset()
+			ctx := resolverContext(tt.resolver)
+			err := tt.call(ctx)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errForcedFailure)
+
+			extensions := ErrorPresenter(ctx, err).Extensions
+			assert.Equal(t, ErrCodeDatabaseError, extensions["code"])
+			assert.Equal(t, tt.entity, extensions["entityType"])
+			assert.Equal(t, tt.resolver, extensions["query"])
+
+			var logged map[string]interface{}
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var event map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(line), &event))
+				if event["level"] == "error" {
+					logged = event
+				}
+			}
+			require.NotNil(t, logged, "the failure is logged")
+			assert.Equal(t, ErrCodeDatabaseError, logged["error_code"])
+			assert.Equal(t, tt.entity, logged["entity_type"])
+			assert.Equal(t, tt.resolver, logged["resolver"])
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+const (
+	explainContextKey contextKey = "explain"
+
+	// explainExtensionKey is the GraphQL response extension holding explain summaries
+	explainExtensionKey = "explain"
+
+	// explainVerbosity runs the winning plan to completion so execution statistics are reported
+	explainVerbosity = "executionStats"
+)
+
+// ExplainSummary is the compact explain output attached to the response extensions
+type ExplainSummary struct {
+	Collection      string `json:"collection"`
+	IndexUsed       string `json:"indexUsed,omitempty"` // Empty when no index was used
+	CollectionScan  bool   `json:"collectionScan"`
+	DocsExamined    int64  `json:"docsExamined"`
+	ExecutionMillis int64  `json:"executionMillis"`
+}
+
+// explainRecorder collects explain summaries for every search executed in a request
+type explainRecorder struct {
+	mu         sync.Mutex
+	registered bool
+	summaries  map[string]ExplainSummary
+}
+
+// WithExplain returns a context in which searches return their execution plan instead of data
+func WithExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainContextKey, &explainRecorder{
+		summaries: make(map[string]ExplainSummary),
+	})
+}
+
+// ExplainSummaries returns the explain summaries recorded in the context, keyed by field path
+func ExplainSummaries(ctx context.Context) map[string]ExplainSummary {
+	recorder, ok := ctx.Value(explainContextKey).(*explainRecorder)
+	if !ok {
+		return nil
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	summaries := make(map[string]ExplainSummary, len(recorder.summaries))
+	for key, summary := range recorder.summaries {
+		summaries[key] = summary
+	}
+	return summaries
+}
+
+// record stores a summary and registers the extension on the GraphQL response once
+func (r *explainRecorder) record(ctx context.Context, key string, summary ExplainSummary) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.summaries[key] = summary
+	if !r.registered && graphql.HasOperationContext(ctx) {
+		graphql.RegisterExtension(ctx, explainExtensionKey, r.summaries)
+		r.registered = true
+	}
+}
+
+// explainSearch explains the search pipeline instead of executing it (admin only)
+func explainSearch(ctx context.Context, collection db.Collection, pipeline []bson.M, recorder *explainRecorder) error {
+	if _, err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	raw, err := collection.ExplainAggregate(ctx, pipeline, explainVerbosity)
+	if err != nil {
+		return NewDatabaseError(err, "Explain failed")
+	}
+
+	summary, winningPlan := summarizeExplain(raw)
+	summary.Collection = collection.Name()
+
+	logger.FromContext(ctx).Info().
+		Str("collection", summary.Collection).
+		Interface("winning_plan", logger.Redacted(winningPlan)).
+		Int64("total_docs_examined", summary.DocsExamined).
+		Str("index_used", summary.IndexUsed).
+		Bool("collection_scan", summary.CollectionScan).
+		Int64("execution_ms", summary.ExecutionMillis).
+		Msg("Search explain")
+
+	key := summary.Collection
+	if fc := graphql.GetFieldContext(ctx); fc != nil {
+		key = fc.Path().String()
+	}
+	recorder.record(ctx, key, summary)
+
+	return nil
+}
+
+// explainRecorderFrom returns the explain recorder when explain mode was requested
+func explainRecorderFrom(ctx context.Context) *explainRecorder {
+	recorder, _ := ctx.Value(explainContextKey).(*explainRecorder)
+	return recorder
+}
+
+// summarizeExplain extracts index usage and execution statistics from explain output
+// Aggregations whose first stages were pushed down report them under stages[0].$cursor
+func summarizeExplain(raw bson.M) (ExplainSummary, bson.M) {
+	source := raw
+	if _, ok := raw["queryPlanner"]; !ok {
+		if stages, ok := raw["stages"].(bson.A); ok && len(stages) > 0 {
+			if first, ok := stages[0].(bson.M); ok {
+				if cursor, ok := first["$cursor"].(bson.M); ok {
+					source = cursor
+				}
+			}
+		}
+	}
+
+	summary := ExplainSummary{}
+
+	queryPlanner, _ := source["queryPlanner"].(bson.M)
+	winningPlan, _ := queryPlanner["winningPlan"].(bson.M)
+	walkPlanStages(winningPlan, &summary)
+
+	if stats, ok := source["executionStats"].(bson.M); ok {
+		summary.DocsExamined = toInt64(stats["totalDocsExamined"])
+		summary.ExecutionMillis = toInt64(stats["executionTimeMillis"])
+	}
+
+	return summary, winningPlan
+}
+
+// walkPlanStages records IXSCAN index names and COLLSCAN stages found in a plan tree
+func walkPlanStages(stage bson.M, summary *ExplainSummary) {
+	if stage == nil {
+		return
+	}
+
+	switch stage["stage"] {
+	case "COLLSCAN":
+		summary.CollectionScan = true
+	case "IXSCAN":
+		if indexName, ok := stage["indexName"].(string); ok && summary.IndexUsed == "" {
+			summary.IndexUsed = indexName
+		}
+	}
+
+	// Slot-based engine plans nest the classic plan under queryPlan
+	for _, key := range []string{"queryPlan", "inputStage"} {
+		if child, ok := stage[key].(bson.M); ok {
+			walkPlanStages(child, summary)
+		}
+	}
+	if children, ok := stage["inputStages"].(bson.A); ok {
+		for _, child := range children {
+			if childStage, ok := child.(bson.M); ok {
+				walkPlanStages(childStage, summary)
+			}
+		}
+	}
+}
+
+// toInt64 converts numeric BSON values to int64
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
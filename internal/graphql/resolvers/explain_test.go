@@ -0,0 +1,90 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Test explain output parsing for the plan shapes MongoDB reports for aggregations
+func TestSummarizeExplain(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      bson.M
+		expected ExplainSummary
+	}{
+		{
+			name: "Pushed-down $cursor stage with collection scan",
+			raw: bson.M{
+				"stages": bson.A{
+					bson.M{"$cursor": bson.M{
+						"queryPlanner": bson.M{
+							"winningPlan": bson.M{"stage": "COLLSCAN"},
+						},
+						"executionStats": bson.M{
+							"totalDocsExamined":   int32(50),
+							"executionTimeMillis": int32(3),
+						},
+					}},
+					bson.M{"$facet": bson.M{}},
+				},
+			},
+			expected: ExplainSummary{CollectionScan: true, DocsExamined: 50, ExecutionMillis: 3},
+		},
+		{
+			name: "Top-level plan with index scan",
+			raw: bson.M{
+				"queryPlanner": bson.M{
+					"winningPlan": bson.M{
+						"stage": "FETCH",
+						"inputStage": bson.M{
+							"stage":     "IXSCAN",
+							"indexName": "identifier_1",
+						},
+					},
+				},
+				"executionStats": bson.M{
+					"totalDocsExamined":   int64(2),
+					"executionTimeMillis": int64(0),
+				},
+			},
+			expected: ExplainSummary{IndexUsed: "identifier_1", DocsExamined: 2},
+		},
+		{
+			name: "Slot-based engine plan",
+			raw: bson.M{
+				"queryPlanner": bson.M{
+					"winningPlan": bson.M{
+						"queryPlan": bson.M{
+							"stage": "OR",
+							"inputStages": bson.A{
+								bson.M{"stage": "IXSCAN", "indexName": "status.deletion_1"},
+								bson.M{"stage": "COLLSCAN"},
+							},
+						},
+					},
+				},
+			},
+			expected: ExplainSummary{IndexUsed: "status.deletion_1", CollectionScan: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, _ := summarizeExplain(tt.raw)
+			assert.Equal(t, tt.expected, summary)
+		})
+	}
+}
+
+// Test explain mode is only active when requested
+func TestExplainRecorderFrom(t *testing.T) {
+	assert.Nil(t, explainRecorderFrom(context.Background()))
+	assert.Nil(t, ExplainSummaries(context.Background()))
+
+	ctx := WithExplain(context.Background())
+	assert.NotNil(t, explainRecorderFrom(ctx))
+	assert.Empty(t, ExplainSummaries(ctx))
+}
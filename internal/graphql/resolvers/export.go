@@ -0,0 +1,170 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ExportQuery is a streaming export of one entity collection, built from search input JSON
+type ExportQuery struct {
+	CollectionName string
+	Pipeline       []bson.M
+	NewEntity      func() interface{} // Returns a pointer to decode one exported document into
+
+	config EntityConfig
+	filter interface{}
+}
+
+// RecordAccess records exported entities, pointers returned by NewEntity, in the access audit.
+// Exports are recorded as searches with the export's filter
+func (q *ExportQuery) RecordAccess(ctx context.Context, entities []interface{}) {
+	recordAccess(ctx, q.config, generated.AccessQueryTypeSearch, q.filter, &entities)
+}
+
+// exportSpec decodes search inputs and allocates result values for one entity
+type exportSpec struct {
+	decodeInputs func(where, order json.RawMessage) (filter interface{}, sorter interface{}, err error)
+	newEntity    func() interface{}
+}
+
+// exportSpecs lists the exportable entities, keyed like entityConfigs
+var exportSpecs = map[string]exportSpec{
+	"customer":           newExportSpec[generated.CustomerQueryFilterInput, generated.CustomerQuerySorterInput, generated.Customer](),
+	"employee":           newExportSpec[generated.EmployeeQueryFilterInput, generated.EmployeeQuerySorterInput, generated.Employee](),
+	"team":               newExportSpec[generated.TeamQueryFilterInput, generated.TeamQuerySorterInput, generated.TeamQueryOutput](),
+	"inventory":          newExportSpec[generated.InventoryQueryFilterInput, generated.InventoryQuerySorterInput, generated.Inventory](),
+	"executionPlan":      newExportSpec[generated.ExecutionPlanQueryFilterInput, generated.ExecutionPlanQuerySorterInput, generated.ExecutionPlan](),
+	"referencePortfolio": newExportSpec[generated.ReferencePortfolioQueryFilterInput, generated.ReferencePortfolioQuerySorterInput, generated.ReferencePortfolioOutput](),
+}
+
+// newExportSpec builds an exportSpec for the entity's generated filter, sorter and output types
+func newExportSpec[F any, S any, E any]() exportSpec {
+	return exportSpec{
+		decodeInputs: func(where, order json.RawMessage) (interface{}, interface{}, error) {
+			var filter, sorter interface{}
+
+			if len(where) > 0 && string(where) != "null" {
+				f := new(F)
+				if err := decodeStrict(where, f); err != nil {
+					return nil, nil, NewInvalidInput(fmt.Sprintf("invalid where: %v", err), "where").WithCause(err)
+				}
+				filter = f
+			}
+
+			if len(order) > 0 && string(order) != "null" {
+				var s []*S
+				if err := decodeStrict(order, &s); err != nil {
+					return nil, nil, NewInvalidInput(fmt.Sprintf("invalid order: %v", err), "order").WithCause(err)
+				}
+				sorter = s
+			}
+
+			return filter, sorter, nil
+		},
+		newEntity: func() interface{} {
+			return new(E)
+		},
+	}
+}
+
+// decodeStrict decodes JSON and rejects fields the input type does not define
+func decodeStrict(data json.RawMessage, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+// BuildExportQuery validates search input JSON for an entity and builds its export pipeline
+// The pipeline excludes deleted entities, applies the caller's authorization scope and is capped at limit rows
+func BuildExportQuery(ctx context.Context, entity string, where, order json.RawMessage, limit int) (*ExportQuery, error) {
+	spec, ok := exportSpecs[entity]
+	if !ok {
+		return nil, newNotFoundError(fmt.Sprintf("entity %q cannot be exported", entity))
+	}
+	config, err := lookupEntityConfig(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, sorter, err := spec.decodeInputs(where, order)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSorter(sorter); err != nil {
+		return nil, err
+	}
+	if err := validateEntityFilter(config, filter); err != nil {
+		return nil, err
+	}
+
+	pipeline := []bson.M{
+		{"$match": buildBaseFilter(ctx, config, filter)},
+	}
+
+	// Same ordering as search, defaulting to the entity's default sort
+	sortStages, err := entitySortStages(config, sorter)
+	if err != nil {
+		return nil, err
+	}
+	pipeline = append(pipeline, sortStages...)
+	pipeline = append(pipeline, bson.M{"$limit": limit})
+
+	return &ExportQuery{
+		CollectionName: config.CollectionName,
+		Pipeline:       pipeline,
+		NewEntity:      spec.newEntity,
+		config:         config,
+		filter:         filter,
+	}, nil
+}
+
+// GetEntity reads one entity by identifier for callers outside GraphQL, excluding deleted
+// entities like the get queries. Returns nil when there is no such entity
+func GetEntity(ctx context.Context, dbClient DBClient, entity, identifier string) (interface{}, error) {
+	spec, ok := exportSpecs[entity]
+	if !ok {
+		return nil, newNotFoundError(fmt.Sprintf("unknown entity %q", entity))
+	}
+	config, err := lookupEntityConfig(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	result := spec.newEntity()
+	if err := getEntity(ctx, dbClient, config, identifier, result); err != nil {
+		return nil, err
+	}
+	if reflect.ValueOf(result).Elem().IsZero() {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// EntityNames lists the entities GetEntity and BuildExportQuery accept, sorted
+func EntityNames() []string {
+	names := make([]string, 0, len(exportSpecs))
+	for name := range exportSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CollectionName returns the collection an entity GetEntity and BuildExportQuery accept is stored in
+func CollectionName(entity string) (string, error) {
+	if _, ok := exportSpecs[entity]; !ok {
+		return "", newNotFoundError(fmt.Sprintf("unknown entity %q", entity))
+	}
+	config, err := lookupEntityConfig(entity)
+	if err != nil {
+		return "", err
+	}
+	return config.CollectionName, nil
+}
@@ -0,0 +1,140 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// Searches can count their matches per value of a few whitelisted fields alongside the page.
+// The counts run as extra branches of the search's $facet, so they see the same filter and
+// authorization scope as totalCount, cost one aggregation in total and nothing when not requested
+
+// MaxFacetBuckets is the largest number of values a facet reports; rarer values are left out
+const MaxFacetBuckets = 50
+
+const searchFacetsContextKey contextKey = "search_facets"
+
+// FacetField is a field searches can count their matches per value of
+type FacetField struct {
+	Path  string // Document field, reported as the facet's field
+	Array bool   // The field holds a list; an entity counts once for each of its values
+}
+
+// stages returns the $facet branch counting the matches per value, largest counts first.
+// One bucket more than reported is read to tell whether values were left out
+func (f FacetField) stages() []bson.M {
+	stages := []bson.M{}
+	if f.Array {
+		stages = append(stages, bson.M{"$unwind": bson.M{"path": "$" + f.Path, "preserveNullAndEmptyArrays": true}})
+	}
+	return append(stages,
+		bson.M{"$group": bson.M{"_id": "$" + f.Path, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		bson.M{"$limit": MaxFacetBuckets + 1},
+	)
+}
+
+// facetBucket is one document of a facet branch
+type facetBucket struct {
+	Value interface{} `bson:"_id"`
+	Count int64       `bson:"count"`
+}
+
+// searchFacets are the facets requested of a search and, once it ran, their counts
+type searchFacets struct {
+	fields  []FacetField
+	results []*generated.SearchFacet
+}
+
+// withSearchFacets returns a context in which the search counts the given facets of an entity,
+// and the facets whose buckets it fills. Without names the context is returned unchanged and the
+// facets are nil. Names not whitelisted in the entity's FacetFields are INVALID_INPUT
+func withSearchFacets[T ~string](ctx context.Context, config EntityConfig, names []T) (context.Context, *searchFacets, error) {
+	if names == nil {
+		return ctx, nil, nil
+	}
+
+	facets := &searchFacets{results: []*generated.SearchFacet{}}
+	seen := make(map[T]bool, len(names))
+	for _, name := range names {
+		field, ok := config.FacetFields[string(name)]
+		if !ok {
+			return nil, nil, NewInvalidInput(fmt.Sprintf("%s cannot be counted as a facet of %s", name, config.CollectionName), "facets")
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		facets.fields = append(facets.fields, field)
+		// A search that reads nothing still reports every requested facet
+		facets.results = append(facets.results, &generated.SearchFacet{Field: field.Path, Buckets: []*generated.FacetBucket{}})
+	}
+	return context.WithValue(ctx, searchFacetsContextKey, facets), facets, nil
+}
+
+// searchFacetsFrom returns the facets requested of searches in the context, or nil
+func searchFacetsFrom(ctx context.Context) *searchFacets {
+	facets, _ := ctx.Value(searchFacetsContextKey).(*searchFacets)
+	return facets
+}
+
+// branches returns the $facet branches counting the facets, keyed by facetKey
+func (f *searchFacets) branches() bson.M {
+	if f == nil {
+		return nil
+	}
+	branches := make(bson.M, len(f.fields))
+	for i, field := range f.fields {
+		branches[facetKey(i)] = field.stages()
+	}
+	return branches
+}
+
+// decode fills the facets' buckets from the search's $facet result
+func (f *searchFacets) decode(result searchFacetResult) {
+	if f == nil {
+		return
+	}
+	for i, facet := range f.results {
+		buckets := result.Facets[facetKey(i)]
+		if len(buckets) > MaxFacetBuckets {
+			buckets = buckets[:MaxFacetBuckets]
+			facet.Truncated = true
+		}
+		facet.Buckets = make([]*generated.FacetBucket, len(buckets))
+		for j, bucket := range buckets {
+			facet.Buckets[j] = &generated.FacetBucket{Value: facetValue(bucket.Value), Count: bucket.Count}
+		}
+	}
+}
+
+// list returns the counted facets in the order they were requested, or nil when none were
+func (f *searchFacets) list() []*generated.SearchFacet {
+	if f == nil {
+		return nil
+	}
+	return f.results
+}
+
+// facetKey names the $facet branch of the i-th requested facet; the search's own branches are
+// metadata and data
+func facetKey(i int) string {
+	return "facet" + strconv.Itoa(i)
+}
+
+// facetValue returns a bucket value as reported, or nil for entities without one
+func facetValue(value interface{}) *string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return &v
+	}
+	s := fmt.Sprint(value)
+	return &s
+}
@@ -0,0 +1,96 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// TestCustomerSearch_Facets verifies requested facets run as branches of the search's $facet and
+// are returned in request order with their buckets capped
+func TestCustomerSearch_Facets(t *testing.T) {
+	groups := make(bson.A, MaxFacetBuckets+1)
+	for i := range groups {
+		groups[i] = bson.M{"_id": string(rune('A' + i%26)), "count": int32(MaxFacetBuckets + 1 - i)}
+	}
+	fake := &fakeSearchDB{identifiers: []string{"a", "b", "c"}, facets: bson.M{
+		"facet0": groups,
+		"facet1": bson.A{bson.M{"_id": "ACTIVE", "count": int32(2)}, bson.M{"_id": nil, "count": int32(1)}},
+	}}
+
+	first := int64(2)
+	facets := []generated.CustomerFacetField{
+		generated.CustomerFacetFieldCustomerGroups,
+		generated.CustomerFacetFieldStatusActivation,
+		generated.CustomerFacetFieldCustomerGroups,
+	}
+	result, err := NewResolver(fake).Query().CustomerSearch(context.Background(), nil, nil, &first, nil, nil, nil, nil, nil, facets)
+	require.NoError(t, err)
+
+	require.Len(t, fake.facetStages, 1)
+	branches := fake.facetStages[0]
+	assert.Len(t, branches, 4, "metadata, data and one branch per distinct facet")
+	assert.Equal(t, FacetField{Path: "customerGroups", Array: true}.stages(), branches["facet0"])
+	assert.Equal(t, []bson.M{
+		{"$group": bson.M{"_id": "$status.activation", "count": bson.M{"$sum": 1}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": MaxFacetBuckets + 1},
+	}, branches["facet1"])
+
+	require.Len(t, result.Facets, 2)
+	assert.Equal(t, "customerGroups", result.Facets[0].Field)
+	assert.True(t, result.Facets[0].Truncated)
+	assert.Len(t, result.Facets[0].Buckets, MaxFacetBuckets)
+	assert.Equal(t, int64(MaxFacetBuckets+1), result.Facets[0].Buckets[0].Count)
+
+	activation := result.Facets[1]
+	assert.Equal(t, "status.activation", activation.Field)
+	assert.False(t, activation.Truncated)
+	require.Len(t, activation.Buckets, 2)
+	assert.Equal(t, "ACTIVE", *activation.Buckets[0].Value)
+	assert.Equal(t, int64(2), activation.Buckets[0].Count)
+	assert.Nil(t, activation.Buckets[1].Value, "customers without a value count in a null bucket")
+	assert.Equal(t, int64(3), result.TotalCount)
+}
+
+// TestCustomerSearch_WithoutFacets verifies searches without the facets argument add no branch
+// and return null facets, while an empty list returns an empty one
+func TestCustomerSearch_WithoutFacets(t *testing.T) {
+	fake := &fakeSearchDB{identifiers: []string{"a"}}
+	query := NewResolver(fake).Query()
+
+	result, err := query.CustomerSearch(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, result.Facets)
+	assert.Len(t, fake.facetStages[0], 2, "only metadata and data")
+
+	result, err = query.CustomerSearch(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, []generated.CustomerFacetField{})
+	require.NoError(t, err)
+	assert.NotNil(t, result.Facets)
+	assert.Empty(t, result.Facets)
+}
+
+// TestCustomerSearch_FacetsOfEmptySearch verifies a search that matches nothing without querying
+// still reports every requested facet
+func TestCustomerSearch_FacetsOfEmptySearch(t *testing.T) {
+	fake := &fakeSearchDB{identifiers: []string{"a"}}
+	where := &generated.CustomerQueryFilterInput{CustomerGroups: &generated.CollectionFilterOfCustomerGroupInput{In: []generated.CustomerGroup{}}}
+
+	result, err := NewResolver(fake).Query().CustomerSearch(context.Background(), where, nil, nil, nil, nil, nil, nil, nil,
+		[]generated.CustomerFacetField{generated.CustomerFacetFieldStatusActivation})
+	require.NoError(t, err)
+	assert.Empty(t, fake.facetStages, "the search must not query")
+	require.Len(t, result.Facets, 1)
+	assert.Equal(t, "status.activation", result.Facets[0].Field)
+	assert.Empty(t, result.Facets[0].Buckets)
+}
+
+func TestWithSearchFacets_RejectsFieldsNotWhitelisted(t *testing.T) {
+	_, _, err := withSearchFacets(context.Background(), entityConfigs["employee"], []generated.CustomerFacetField{generated.CustomerFacetFieldStatusActivation})
+	requireInvalidInput(t, err, "cannot be counted as a facet of employees")
+}
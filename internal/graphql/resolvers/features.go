@@ -0,0 +1,196 @@
+package resolvers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// disabledFeatures holds the entities and root fields switched off by configuration.
+// Set once at startup, before the server handles requests
+var disabledFeatures = struct {
+	entities map[string]bool
+	fields   map[string]bool
+}{}
+
+// The root fields of each entity, as listed by the RootFields of entityConfigs. Fields such as
+// search or create do not carry their entity's name, so the lists are spelled out
+var (
+	customerRootFields = []string{
+		"customerGet", "customerGetConnection", "customerByKeysGet", "customerByKeysGetDetailed",
+		"customerByKeysGetConnection", "customerSearch", "customerHistory", "customerGetCrispIdentity",
+		"customerOpenBankingProcessedDataGet", "customerOpenBankingMappingRulesGet",
+		"customerCreate", "customerUpdate", "customerDelete",
+	}
+	employeeRootFields = []string{
+		"employeeGet", "employeeGetConnection", "employeeByKeysGet", "employeeByKeysGetDetailed",
+		"employeeByKeysGetConnection", "employeeSearch", "employeeAllWithRoleGet", "employeeAllByTeamleadGet",
+		"employeeAllByTeamleadAndTeamGet", "employeeTeamLeadForTeamGet", "employeeTeamMembersForTeamGet",
+		"employeeCreate", "employeeUpdate", "employeeDelete", "employeeLock", "employeeInvite",
+		"employeeReInvite", "employeeChangeGroup",
+	}
+	teamRootFields = []string{
+		"teamGet", "teamGetConnection", "teamByKeysGet", "teamByKeysGetDetailed", "teamByKeysGetConnection",
+		"teamSearch", "teamByLeaderGet", "teamByMemberGet",
+		"teamCreate", "teamUpdate", "teamDelete", "teamAssign",
+	}
+	inventoryRootFields = []string{
+		"inventoryGet", "inventoryGetConnection", "inventoryByNumberGet", "inventoryForCustomerGet",
+		"inventoryGetAttachments", "inventoryDownloadAttachment",
+		"byKeysGet", "byKeysGetDetailed", "byKeysGetConnection", "search",
+		"inventoryCreate", "inventoryUpdate", "inventoryConfirmAttachment", "inventoryUploadAttachment",
+		"inventoryDelete",
+	}
+	executionPlanRootFields = []string{
+		"executionPlanGet", "executionPlanGetConnection", "executionPlanByKeysGet", "executionPlanByKeysGetDetailed",
+		"executionPlanByKeysGetConnection", "executionPlanSearch", "executionPlanForCustomerGet",
+		"planActualAdjustmentForCustomerGet", "executionPlanGetAttachments", "executionPlanDownloadAttachment",
+		"executionPlanCreate", "executionPlanUpdate", "executionPlanDelete", "executionPlanUploadAttachment",
+		"executionPlanConfirmAttachment",
+	}
+	referencePortfolioRootFields = []string{
+		"referencePortfolioGet", "referencePortfolioGetConnection", "referencePortfolioByKeysGet",
+		"referencePortfolioByKeysGetDetailed", "referencePortfolioByKeysGetConnection", "referencePortfolioSearch",
+		"referencePortfolioDownloadAttachment", "referencePortfolioGetAttachments",
+		"referencePortfolioActiveForCustomerGet", "referencePortfoliosForCustomerGet",
+		"referencePortfolioGetWealthForecast", "referencePortfolioGetLiquidityForecast",
+		"referencePortfolioSimulateUpdate", "refPortConstantsAndDefaultsGet", "referencePortfolioDemandConceptGet",
+		"referencePortfolioIncompleteNodesGet",
+		"referencePortfolioCreate", "referencePortfolioUpdate", "referencePortfolioConfirmAttachment",
+		"referencePortfolioUploadAttachment", "referencePortfolioDelete", "referencePortfolioReleaseToExecution",
+		"referencePortfolioResetExecution", "referencePortfolioConfirmExecution", "create", "update",
+	}
+)
+
+// rootFieldEntities maps every root field listed in the RootFields of entityConfigs to its entity
+var rootFieldEntities = func() map[string]string {
+	entities := map[string]string{}
+	for entity, config := range entityConfigs {
+		for _, field := range config.RootFields {
+			entities[field] = entity
+		}
+	}
+	return entities
+}()
+
+// SetDisabledFeatures switches off entities (keyed like entityConfigs, e.g. "referencePortfolio")
+// and individual Query or Mutation fields of the schema. The RootFields of a disabled entity
+// are disabled with it; they are checked against the schema as well, so a renamed field fails
+// here instead of staying enabled
+func SetDisabledFeatures(entities, fields []string, schema *ast.Schema) error {
+	disabledEntities, disabledFields, err := disabledFeatureSets(entities, fields, schema)
+	if err != nil {
+		return err
+	}
+	disabledFeatures.entities = disabledEntities
+	disabledFeatures.fields = disabledFields
+	return nil
+}
+
+// CheckDisabledFeatures reports the error SetDisabledFeatures would return for the schema,
+// without changing the disabled features
+func CheckDisabledFeatures(entities, fields []string, schema *ast.Schema) error {
+	_, _, err := disabledFeatureSets(entities, fields, schema)
+	return err
+}
+
+// disabledFeatureSets validates disabled entities and fields against the schema and returns them as sets
+func disabledFeatureSets(entities, fields []string, schema *ast.Schema) (map[string]bool, map[string]bool, error) {
+	disabledEntities := make(map[string]bool, len(entities))
+	for _, entity := range entities {
+		config, ok := entityConfigs[entity]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown entity %q; known entities: %s", entity, strings.Join(entityNames(), ", "))
+		}
+		for _, field := range config.RootFields {
+			if !isRootField(schema, field) {
+				return nil, nil, fmt.Errorf("root field %q of entity %q is not a Query or Mutation field", field, entity)
+			}
+		}
+		disabledEntities[entity] = true
+	}
+
+	disabledFields := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !isRootField(schema, field) {
+			return nil, nil, fmt.Errorf("%q is not a Query or Mutation field", field)
+		}
+		disabledFields[field] = true
+	}
+	return disabledEntities, disabledFields, nil
+}
+
+// isRootField reports whether the schema's Query or Mutation type defines a field
+func isRootField(schema *ast.Schema, field string) bool {
+	for _, root := range []*ast.Definition{schema.Query, schema.Mutation} {
+		if root != nil && root.Fields.ForName(field) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// EntityDisabled reports whether an entity was switched off
+func EntityDisabled(entity string) bool {
+	return disabledFeatures.entities[entity]
+}
+
+// FieldDisabled reports whether a Query or Mutation field was switched off, directly or through its entity
+func FieldDisabled(field string) bool {
+	if disabledFeatures.fields[field] {
+		return true
+	}
+	entity, ok := rootFieldEntities[field]
+	return ok && disabledFeatures.entities[entity]
+}
+
+// newFeatureDisabledError creates the error of a call to a disabled feature
+func newFeatureDisabledError(feature string) error {
+	return &QueryError{
+		Message: fmt.Sprintf("%s is disabled on this server", feature),
+		Code:    ErrCodeFeatureDisabled,
+	}
+}
+
+// FeatureDisabledError returns the GraphQL error of a call to a disabled feature, carrying
+// the FEATURE_DISABLED code in its extensions
+func FeatureDisabledError(feature string) *gqlerror.Error {
+	return toGraphQLError(newFeatureDisabledError(feature))
+}
+
+// lookupEntityConfig returns an entity's configuration for internal callers, failing with
+// FEATURE_DISABLED when the entity was switched off
+func lookupEntityConfig(entity string) (EntityConfig, error) {
+	config, ok := entityConfigs[entity]
+	if !ok {
+		return EntityConfig{}, newNotFoundError(fmt.Sprintf("unknown entity %q", entity))
+	}
+	if EntityDisabled(entity) {
+		return EntityConfig{}, newFeatureDisabledError(entity)
+	}
+	return config, nil
+}
+
+// enabledEntityNames returns the names of the entities that are not switched off, sorted
+func enabledEntityNames() []string {
+	names := make([]string, 0, len(entityConfigs))
+	for _, name := range entityNames() {
+		if !EntityDisabled(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// entityNames returns the names of all entities, sorted
+func entityNames() []string {
+	names := make([]string, 0, len(entityConfigs))
+	for name := range entityConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,141 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// disableFeatures disables entities and fields for the rest of a test
+func disableFeatures(t *testing.T, entities, fields []string) {
+	t.Helper()
+	schema := generated.NewExecutableSchema(generated.Config{}).Schema()
+	require.NoError(t, SetDisabledFeatures(entities, fields, schema))
+	t.Cleanup(func() { _ = SetDisabledFeatures(nil, nil, schema) })
+}
+
+// TestSetDisabledFeatures_RejectsUnknownNames tests entities and fields are checked against the schema
+func TestSetDisabledFeatures_RejectsUnknownNames(t *testing.T) {
+	schema := generated.NewExecutableSchema(generated.Config{}).Schema()
+
+	err := SetDisabledFeatures([]string{"portfolio"}, nil, schema)
+	assert.ErrorContains(t, err, `unknown entity "portfolio"`)
+
+	err = SetDisabledFeatures(nil, []string{"customerFind"}, schema)
+	assert.EqualError(t, err, `"customerFind" is not a Query or Mutation field`)
+
+	assert.NoError(t, SetDisabledFeatures(nil, []string{"customerSearch", "customerUpdate"}, schema))
+	assert.NoError(t, SetDisabledFeatures(nil, nil, schema))
+}
+
+// TestFieldDisabled tests root fields are disabled directly or through the entity listing them
+func TestFieldDisabled(t *testing.T) {
+	disableFeatures(t, []string{"referencePortfolio"}, []string{"teamSearch"})
+
+	for field, disabled := range map[string]bool{
+		"referencePortfolioSearch":          true,
+		"referencePortfolioGet":             true,
+		"referencePortfoliosForCustomerGet": true,
+		"teamSearch":                        true,
+		"teamGet":                           false,
+		"customerSearch":                    false,
+		"serverLimits":                      false,
+	} {
+		assert.Equal(t, disabled, FieldDisabled(field), field)
+	}
+}
+
+// TestFieldDisabled_EntityRootFields tests every root field of a disabled entity is disabled,
+// including those not named after it
+func TestFieldDisabled_EntityRootFields(t *testing.T) {
+	disableFeatures(t, []string{"inventory", "referencePortfolio"}, nil)
+
+	for _, entity := range []string{"inventory", "referencePortfolio"} {
+		for _, field := range entityConfigs[entity].RootFields {
+			assert.True(t, FieldDisabled(field), field)
+		}
+	}
+	for _, field := range []string{"search", "byKeysGet", "byKeysGetDetailed", "byKeysGetConnection", "create", "update", "refPortConstantsAndDefaultsGet"} {
+		assert.True(t, FieldDisabled(field), field)
+	}
+	for _, field := range []string{"customerSearch", "teamByKeysGet", "executionPlanCreate", "entityCounts"} {
+		assert.False(t, FieldDisabled(field), field)
+	}
+}
+
+// TestRootFields_CoverEntityTypes tests every root field returning or taking an entity's types
+// is listed in the entity's RootFields, so a new field cannot stay enabled with its entity disabled
+func TestRootFields_CoverEntityTypes(t *testing.T) {
+	schema := generated.NewExecutableSchema(generated.Config{}).Schema()
+	typeEntities := map[string]string{}
+	for entity := range entityConfigs {
+		typeEntities[strings.ToUpper(entity[:1])+entity[1:]] = entity
+	}
+	entityOf := func(typ *ast.Type) string {
+		name := strings.TrimPrefix(typ.Name(), "QueryOutputOf")
+		for typeName, entity := range typeEntities {
+			if name == typeName || strings.HasPrefix(name, typeName) && unicode.IsUpper(rune(name[len(typeName)])) {
+				return entity
+			}
+		}
+		return ""
+	}
+
+	for _, root := range []*ast.Definition{schema.Query, schema.Mutation} {
+		for _, field := range root.Fields {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+			types := []*ast.Type{field.Type}
+			for _, arg := range field.Arguments {
+				types = append(types, arg.Type)
+			}
+			for _, typ := range types {
+				if entity := entityOf(typ); entity != "" {
+					assert.Contains(t, entityConfigs[entity].RootFields, field.Name, "%s uses %s", field.Name, typ.Name())
+				}
+			}
+		}
+	}
+}
+
+// TestLookupEntityConfig_Disabled tests internal callers get FEATURE_DISABLED for disabled entities
+func TestLookupEntityConfig_Disabled(t *testing.T) {
+	disableFeatures(t, []string{"referencePortfolio"}, nil)
+
+	_, err := lookupEntityConfig("referencePortfolio")
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeFeatureDisabled, queryErr.Code)
+
+	config, err := lookupEntityConfig("customer")
+	require.NoError(t, err)
+	assert.Equal(t, "customers", config.CollectionName)
+
+	_, err = lookupEntityConfig("portfolio")
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeNotFound, queryErr.Code)
+
+	// Server limits no longer list the entity
+	for _, entity := range resolveServerLimits().Entities {
+		assert.NotEqual(t, "referencePortfolio", entity.Entity)
+	}
+}
+
+// TestBuildExportQuery_DisabledEntity tests disabled entities cannot be exported
+func TestBuildExportQuery_DisabledEntity(t *testing.T) {
+	disableFeatures(t, []string{"customer"}, nil)
+
+	_, err := BuildExportQuery(context.Background(), "customer", nil, nil, 10)
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeFeatureDisabled, queryErr.Code)
+}
@@ -1,9 +1,11 @@
 package resolvers
 
 import (
-	"time"
+	"regexp"
+	"strings"
 
 	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/scalars"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -11,6 +13,13 @@ import (
 
 // convertStringFilter converts a StringFilterInput to MongoDB filter for the specified field
 func convertStringFilter(field string, filter *generated.StringFilterInput) bson.M {
+	return convertShadowedStringFilter(field, "", filter)
+}
+
+// convertShadowedStringFilter converts a StringFilterInput like convertStringFilter
+// When shadowField is set, startsWith matches the lowercase shadow field with a case-sensitive
+// anchored regex on the escaped prefix, which MongoDB can answer from an index on that field
+func convertShadowedStringFilter(field, shadowField string, filter *generated.StringFilterInput) bson.M {
 	if filter == nil {
 		return bson.M{}
 	}
@@ -27,8 +36,8 @@ func convertStringFilter(field string, filter *generated.StringFilterInput) bson
 		filter.NstartsWith == nil &&
 		filter.EndsWith == nil &&
 		filter.NendsWith == nil &&
-		(filter.In == nil || len(filter.In) == 0) &&
-		(filter.Nin == nil || len(filter.Nin) == 0) &&
+		filter.In == nil &&
+		filter.Nin == nil &&
 		(filter.And == nil || len(filter.And) == 0) &&
 		(filter.Or == nil || len(filter.Or) == 0)
 
@@ -45,11 +54,11 @@ func convertStringFilter(field string, filter *generated.StringFilterInput) bson
 		conditions = append(conditions, bson.M{field: bson.M{"$ne": *filter.Neq}})
 	}
 
-	// List operators
-	if filter.In != nil && len(filter.In) > 0 {
+	// List operators: an empty in list matches nothing, an empty nin list is a no-op
+	if filter.In != nil {
 		conditions = append(conditions, bson.M{field: bson.M{"$in": filter.In}})
 	}
-	if filter.Nin != nil && len(filter.Nin) > 0 {
+	if len(filter.Nin) > 0 {
 		conditions = append(conditions, bson.M{field: bson.M{"$nin": filter.Nin}})
 	}
 
@@ -60,7 +69,11 @@ func convertStringFilter(field string, filter *generated.StringFilterInput) bson
 			"$options": "i", // Case-insensitive
 		}})
 	}
-	if filter.StartsWith != nil {
+	if filter.StartsWith != nil && shadowField != "" {
+		conditions = append(conditions, bson.M{shadowField: bson.M{
+			"$regex": "^" + regexp.QuoteMeta(strings.ToLower(*filter.StartsWith)),
+		}})
+	} else if filter.StartsWith != nil {
 		conditions = append(conditions, bson.M{field: bson.M{
 			"$regex":   "^" + *filter.StartsWith,
 			"$options": "i",
@@ -77,7 +90,7 @@ func convertStringFilter(field string, filter *generated.StringFilterInput) bson
 	if filter.And != nil {
 		andConditions := []bson.M{}
 		for _, f := range filter.And {
-			if converted := convertStringFilter(field, f); len(converted) > 0 {
+			if converted := convertShadowedStringFilter(field, shadowField, f); len(converted) > 0 {
 				andConditions = append(andConditions, converted)
 			}
 		}
@@ -88,7 +101,7 @@ func convertStringFilter(field string, filter *generated.StringFilterInput) bson
 	if filter.Or != nil {
 		orConditions := []bson.M{}
 		for _, f := range filter.Or {
-			if converted := convertStringFilter(field, f); len(converted) > 0 {
+			if converted := convertShadowedStringFilter(field, shadowField, f); len(converted) > 0 {
 				orConditions = append(orConditions, converted)
 			}
 		}
@@ -110,7 +123,8 @@ func convertStringFilter(field string, filter *generated.StringFilterInput) bson
 // convertEnumFilter converts enum filter with eq/neq/in/nin to MongoDB filter
 // This is a helper for nested object filters with enum fields
 // Note: There's no generic EnumFilterInput - this works with the field operators pattern
-func convertEnumFilterGeneric(field string, eq, neq *string, in, nin []string) bson.M {
+// A non-nil empty in list matches nothing; an empty nin list is a no-op
+func convertEnumFilterGeneric(field string, eq, neq *string, in, nin []interface{}) bson.M {
 	conditions := []bson.M{}
 
 	if eq != nil {
@@ -119,10 +133,10 @@ func convertEnumFilterGeneric(field string, eq, neq *string, in, nin []string) b
 	if neq != nil {
 		conditions = append(conditions, bson.M{field: bson.M{"$ne": *neq}})
 	}
-	if in != nil && len(in) > 0 {
+	if in != nil {
 		conditions = append(conditions, bson.M{field: bson.M{"$in": in}})
 	}
-	if nin != nil && len(nin) > 0 {
+	if len(nin) > 0 {
 		conditions = append(conditions, bson.M{field: bson.M{"$nin": nin}})
 	}
 
@@ -135,8 +149,52 @@ func convertEnumFilterGeneric(field string, eq, neq *string, in, nin []string) b
 	return bson.M{"$and": conditions}
 }
 
+// userStatusStrings converts UserStatus list values to strings, keeping nil apart from an empty list
+// Null entries match documents without the field, like a null in a string list
+func userStatusStrings(values []*generated.UserStatus) []interface{} {
+	if values == nil {
+		return nil
+	}
+	converted := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if value == nil {
+			converted = append(converted, nil)
+			continue
+		}
+		converted = append(converted, string(*value))
+	}
+	return converted
+}
+
 // convertComparableFilterDateTime converts a ComparableFilterOfNullableOfDateTimeInput to MongoDB filter
+// on a field stored as BSON date (createDate)
 func convertComparableFilterDateTime(field string, filter *generated.ComparableFilterOfNullableOfDateTimeInput) bson.M {
+	return convertComparableFilterTime(field, filter, parseDateTimeValue)
+}
+
+// convertComparableFilterDate converts a ComparableFilterOfNullableOfDateTimeInput to MongoDB filter
+// on a calendar date stored as a YYYY-MM-DD string (birthDate), comparing the UTC date of each value
+func convertComparableFilterDate(field string, filter *generated.ComparableFilterOfNullableOfDateTimeInput) bson.M {
+	return convertComparableFilterTime(field, filter, parseDateValue)
+}
+
+// parseDateTimeValue parses a DateTime filter value into the BSON date it is compared with,
+// as the DateTime scalar reads it
+func parseDateTimeValue(value string) (interface{}, bool) {
+	t, err := scalars.ParseDateTime(value)
+	return t, err == nil
+}
+
+// parseDateValue parses a date (YYYY-MM-DD) or RFC3339 filter value into the stored date string,
+// as the Date scalar reads it
+func parseDateValue(value string) (interface{}, bool) {
+	date, err := scalars.NormalizeDate(value)
+	return date, err == nil
+}
+
+// convertComparableFilterTime converts a ComparableFilterOfNullableOfDateTimeInput to MongoDB filter,
+// comparing with the stored representation parse returns; unparsable values are ignored
+func convertComparableFilterTime(field string, filter *generated.ComparableFilterOfNullableOfDateTimeInput, parse func(string) (interface{}, bool)) bson.M {
 	if filter == nil {
 		return bson.M{}
 	}
@@ -149,9 +207,8 @@ func convertComparableFilterDateTime(field string, filter *generated.ComparableF
 			// Empty string represents null
 			conditions = append(conditions, bson.M{field: nil})
 		} else {
-			// Parse DateTime string
-			if t, err := time.Parse(time.RFC3339, *filter.Eq); err == nil {
-				conditions = append(conditions, bson.M{field: t})
+			if v, ok := parse(*filter.Eq); ok {
+				conditions = append(conditions, bson.M{field: v})
 			}
 		}
 	}
@@ -159,31 +216,31 @@ func convertComparableFilterDateTime(field string, filter *generated.ComparableF
 		if *filter.Neq == "" {
 			conditions = append(conditions, bson.M{field: bson.M{"$ne": nil}})
 		} else {
-			if t, err := time.Parse(time.RFC3339, *filter.Neq); err == nil {
-				conditions = append(conditions, bson.M{field: bson.M{"$ne": t}})
+			if v, ok := parse(*filter.Neq); ok {
+				conditions = append(conditions, bson.M{field: bson.M{"$ne": v}})
 			}
 		}
 	}
 
 	// Comparison operators
 	if filter.Gt != nil {
-		if t, err := time.Parse(time.RFC3339, *filter.Gt); err == nil {
-			conditions = append(conditions, bson.M{field: bson.M{"$gt": t}})
+		if v, ok := parse(*filter.Gt); ok {
+			conditions = append(conditions, bson.M{field: bson.M{"$gt": v}})
 		}
 	}
 	if filter.Gte != nil {
-		if t, err := time.Parse(time.RFC3339, *filter.Gte); err == nil {
-			conditions = append(conditions, bson.M{field: bson.M{"$gte": t}})
+		if v, ok := parse(*filter.Gte); ok {
+			conditions = append(conditions, bson.M{field: bson.M{"$gte": v}})
 		}
 	}
 	if filter.Lt != nil {
-		if t, err := time.Parse(time.RFC3339, *filter.Lt); err == nil {
-			conditions = append(conditions, bson.M{field: bson.M{"$lt": t}})
+		if v, ok := parse(*filter.Lt); ok {
+			conditions = append(conditions, bson.M{field: bson.M{"$lt": v}})
 		}
 	}
 	if filter.Lte != nil {
-		if t, err := time.Parse(time.RFC3339, *filter.Lte); err == nil {
-			conditions = append(conditions, bson.M{field: bson.M{"$lte": t}})
+		if v, ok := parse(*filter.Lte); ok {
+			conditions = append(conditions, bson.M{field: bson.M{"$lte": v}})
 		}
 	}
 
@@ -191,7 +248,7 @@ func convertComparableFilterDateTime(field string, filter *generated.ComparableF
 	if filter.And != nil {
 		andConditions := []bson.M{}
 		for _, f := range filter.And {
-			if converted := convertComparableFilterDateTime(field, f); len(converted) > 0 {
+			if converted := convertComparableFilterTime(field, f, parse); len(converted) > 0 {
 				andConditions = append(andConditions, converted)
 			}
 		}
@@ -202,7 +259,7 @@ func convertComparableFilterDateTime(field string, filter *generated.ComparableF
 	if filter.Or != nil {
 		orConditions := []bson.M{}
 		for _, f := range filter.Or {
-			if converted := convertComparableFilterDateTime(field, f); len(converted) > 0 {
+			if converted := convertComparableFilterTime(field, f, parse); len(converted) > 0 {
 				orConditions = append(orConditions, converted)
 			}
 		}
@@ -220,8 +277,9 @@ func convertComparableFilterDateTime(field string, filter *generated.ComparableF
 	return bson.M{"$and": conditions}
 }
 
-// convertBooleanFilter converts a BooleanFilterInput to MongoDB filter
-func convertBooleanFilter(field string, filter *generated.BooleanFilterInput) bson.M {
+// convertBooleanFilter converts a BooleanFilterInput to MongoDB filter. With absentAsFalse a
+// document without the field counts as false (see SetAbsentAsFalseFields)
+func convertBooleanFilter(field string, absentAsFalse bool, filter *generated.BooleanFilterInput) bson.M {
 	if filter == nil {
 		return bson.M{}
 	}
@@ -229,17 +287,21 @@ func convertBooleanFilter(field string, filter *generated.BooleanFilterInput) bs
 	conditions := []bson.M{}
 
 	if filter.Eq != nil {
-		conditions = append(conditions, bson.M{field: *filter.Eq})
+		conditions = append(conditions, booleanEquals(field, absentAsFalse, *filter.Eq))
 	}
 	if filter.Neq != nil {
-		conditions = append(conditions, bson.M{field: bson.M{"$ne": *filter.Neq}})
+		if absentAsFalse {
+			conditions = append(conditions, booleanEquals(field, true, !*filter.Neq))
+		} else {
+			conditions = append(conditions, bson.M{field: bson.M{"$ne": *filter.Neq}})
+		}
 	}
 
 	// Logical operators (recursive)
 	if filter.And != nil {
 		andConditions := []bson.M{}
 		for _, f := range filter.And {
-			if converted := convertBooleanFilter(field, f); len(converted) > 0 {
+			if converted := convertBooleanFilter(field, absentAsFalse, f); len(converted) > 0 {
 				andConditions = append(andConditions, converted)
 			}
 		}
@@ -250,7 +312,7 @@ func convertBooleanFilter(field string, filter *generated.BooleanFilterInput) bs
 	if filter.Or != nil {
 		orConditions := []bson.M{}
 		for _, f := range filter.Or {
-			if converted := convertBooleanFilter(field, f); len(converted) > 0 {
+			if converted := convertBooleanFilter(field, absentAsFalse, f); len(converted) > 0 {
 				orConditions = append(orConditions, converted)
 			}
 		}
@@ -268,6 +330,15 @@ func convertBooleanFilter(field string, filter *generated.BooleanFilterInput) bs
 	return bson.M{"$and": conditions}
 }
 
+// booleanEquals matches a boolean field equal to value; with absentAsFalse, false also matches
+// documents without the field
+func booleanEquals(field string, absentAsFalse, value bool) bson.M {
+	if absentAsFalse && !value {
+		return bson.M{"$or": []bson.M{{field: false}, {field: bson.M{"$exists": false}}}}
+	}
+	return bson.M{field: value}
+}
+
 // convertCollectionFilterCustomerGroup converts a CollectionFilterOfCustomerGroupInput to MongoDB filter
 func convertCollectionFilterCustomerGroup(field string, filter *generated.CollectionFilterOfCustomerGroupInput) bson.M {
 	if filter == nil {
@@ -277,12 +348,12 @@ func convertCollectionFilterCustomerGroup(field string, filter *generated.Collec
 	conditions := []bson.M{}
 
 	// In/Nin operators for arrays
-	if filter.In != nil && len(filter.In) > 0 {
-		// MongoDB $in operator: field value must be in the list
+	if filter.In != nil {
+		// MongoDB $in operator: field value must be in the list (an empty list matches nothing)
 		conditions = append(conditions, bson.M{field: bson.M{"$in": filter.In}})
 	}
-	if filter.Nin != nil && len(filter.Nin) > 0 {
-		// MongoDB $nin operator: field value must not be in the list
+	if len(filter.Nin) > 0 {
+		// MongoDB $nin operator: field value must not be in the list (an empty list is skipped)
 		conditions = append(conditions, bson.M{field: bson.M{"$nin": filter.Nin}})
 	}
 
@@ -322,163 +393,196 @@ func convertCollectionFilterCustomerGroup(field string, filter *generated.Collec
 // T017: Entity-specific filter converters
 // These convert GraphQL FilterInput types to MongoDB bson.M filters
 
-// convertCustomerFilter converts CustomerQueryFilterInput to MongoDB filter
-func convertCustomerFilter(filter *generated.CustomerQueryFilterInput) bson.M {
-	if filter == nil {
-		return bson.M{}
+// fieldFilter converts the input fields at Paths into a MongoDB condition
+// Entity converters are lists of field filters, so the fields they map are known to the coverage check
+type fieldFilter[T any] struct {
+	Paths   []string // JSON field names; nested object inputs use dotted paths (e.g. "status.deletion")
+	Convert func(filter *T) bson.M
+}
+
+// filterField registers the converter of a single input field
+func filterField[T any](path string, convert func(filter *T) bson.M) fieldFilter[T] {
+	return fieldFilter[T]{Paths: []string{path}, Convert: convert}
+}
+
+// logicalFilterFields registers the recursive and/or fields of a filter input
+func logicalFilterFields[T any](and, or func(filter *T) []*T, convert func(filter *T) bson.M) []fieldFilter[T] {
+	return []fieldFilter[T]{
+		filterField("and", func(filter *T) bson.M { return combineFilters("$and", and(filter), convert) }),
+		filterField("or", func(filter *T) bson.M { return combineFilters("$or", or(filter), convert) }),
 	}
+}
 
+// combineFilters converts nested filters and joins the non-empty ones with a logical operator
+func combineFilters[T any](operator string, filters []*T, convert func(filter *T) bson.M) bson.M {
 	conditions := []bson.M{}
-
-	// Simple field filters
-	if filter.FirstName != nil {
-		if converted := convertStringFilter("firstName", filter.FirstName); len(converted) > 0 {
+	for _, f := range filters {
+		if converted := convert(f); len(converted) > 0 {
 			conditions = append(conditions, converted)
 		}
 	}
-	if filter.LastName != nil {
-		if converted := convertStringFilter("lastName", filter.LastName); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+	if len(conditions) == 0 {
+		return bson.M{}
 	}
-	if filter.UserEmail != nil {
-		if converted := convertStringFilter("userEmail", filter.UserEmail); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+	return bson.M{operator: conditions}
+}
+
+// convertFields ANDs the conditions of the field filters in registration order
+func convertFields[T any](filter *T, fields []fieldFilter[T]) bson.M {
+	if filter == nil {
+		return bson.M{}
 	}
-	if filter.EmployeeEmail != nil {
-		if converted := convertStringFilter("employeeEmail", filter.EmployeeEmail); len(converted) > 0 {
+
+	conditions := []bson.M{}
+	for _, field := range fields {
+		if converted := field.Convert(filter); len(converted) > 0 {
 			conditions = append(conditions, converted)
 		}
 	}
-	if filter.IsShared != nil {
-		if converted := convertBooleanFilter("isShared", filter.IsShared); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+
+	if len(conditions) == 0 {
+		return bson.M{}
 	}
-	if filter.CreateDate != nil {
-		if converted := convertComparableFilterDateTime("createDate", filter.CreateDate); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return bson.M{"$and": conditions}
+}
+
+// filterFieldPaths lists the input fields mapped by the field filters
+func filterFieldPaths[T any](fields []fieldFilter[T]) []string {
+	paths := []string{}
+	for _, field := range fields {
+		paths = append(paths, field.Paths...)
 	}
+	return paths
+}
 
-	// Nested object filters
-	if filter.Status != nil {
-		if filter.Status.Activation != nil {
-			// Convert UserStatus enum to string for generic enum filter
-			var eqStr, neqStr *string
-			if filter.Status.Activation.Eq != nil {
-				s := string(*filter.Status.Activation.Eq)
-				eqStr = &s
-			}
-			if filter.Status.Activation.Neq != nil {
-				s := string(*filter.Status.Activation.Neq)
-				neqStr = &s
+// customerFilterFields maps the CustomerQueryFilterInput fields
+func customerFilterFields() []fieldFilter[generated.CustomerQueryFilterInput] {
+	type input = generated.CustomerQueryFilterInput
+
+	// The openBanking object converts as a whole; it registers the paths of its own fields
+	openBankingPaths := []string{}
+	for _, path := range filterFieldPaths(customerOpenBankingFilterFields()) {
+		openBankingPaths = append(openBankingPaths, "openBanking."+path)
+	}
+
+	fields := []fieldFilter[input]{
+		// Simple field filters
+		filterField("firstName", func(f *input) bson.M {
+			return convertShadowedStringFilter("firstName", shadowField(customerShadowFields, "firstName"), f.FirstName)
+		}),
+		filterField("lastName", func(f *input) bson.M {
+			return convertShadowedStringFilter("lastName", shadowField(customerShadowFields, "lastName"), f.LastName)
+		}),
+		filterField("userEmail", func(f *input) bson.M {
+			return convertEmailFilter("userEmail", shadowField(customerShadowFields, "userEmail"), f.UserEmail)
+		}),
+		filterField("employeeEmail", func(f *input) bson.M { return convertEmailFilter("employeeEmail", "", f.EmployeeEmail) }),
+		filterField("isShared", func(f *input) bson.M {
+			return convertBooleanFilter("isShared", treatsAbsentAsFalse("customer", "isShared"), f.IsShared)
+		}),
+		filterField("createDate", func(f *input) bson.M { return convertComparableFilterDateTime("createDate", f.CreateDate) }),
+
+		// Nested object filters
+		filterField("status.activation", func(f *input) bson.M {
+			if f.Status == nil {
+				return bson.M{}
 			}
-			if converted := convertEnumFilterGeneric("status.activation", eqStr, neqStr, nil, nil); len(converted) > 0 {
-				conditions = append(conditions, converted)
+			return convertEnumFilterUserStatus("status.activation", f.Status.Activation)
+		}),
+		filterField("status.deletion", func(f *input) bson.M {
+			if f.Status == nil {
+				return bson.M{}
 			}
-		}
-		if filter.Status.Deletion != nil {
-			if converted := convertEnumFilterDeleteStatus("status.deletion", filter.Status.Deletion); len(converted) > 0 {
-				conditions = append(conditions, converted)
+			return convertEnumFilterDeleteStatus("status.deletion", f.Status.Deletion)
+		}),
+		filterField("status.creation", func(f *input) bson.M {
+			if f.Status == nil {
+				return bson.M{}
 			}
-		}
-		if filter.Status.Creation != nil {
-			if converted := convertEnumFilterCreateStatus("status.creation", filter.Status.Creation); len(converted) > 0 {
-				conditions = append(conditions, converted)
-			}
-		}
-	}
+			return convertEnumFilterCreateStatus("status.creation", f.Status.Creation)
+		}),
 
-	// Collection filter
-	if filter.CustomerGroups != nil {
-		if converted := convertCollectionFilterCustomerGroup("customerGroups", filter.CustomerGroups); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+		// Collection filter
+		filterField("customerGroups", func(f *input) bson.M {
+			return convertCollectionFilterCustomerGroup("customerGroups", f.CustomerGroups)
+		}),
+
+		// Nested object filter
+		{Paths: openBankingPaths, Convert: func(f *input) bson.M { return convertCustomerOpenBankingFilter(f.OpenBanking) }},
 	}
 
 	// Recursive AND/OR
-	if filter.And != nil {
-		andConditions := []bson.M{}
-		for _, f := range filter.And {
-			if converted := convertCustomerFilter(f); len(converted) > 0 {
-				andConditions = append(andConditions, converted)
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertCustomerFilter,
+	)...)
+}
+
+// convertCustomerFilter converts CustomerQueryFilterInput to MongoDB filter
+func convertCustomerFilter(filter *generated.CustomerQueryFilterInput) bson.M {
+	return convertFields(filter, customerFilterFields())
+}
+
+// customerOpenBankingFilterFields maps the CustomerOpenBankingObjectFilterInput fields
+func customerOpenBankingFilterFields() []fieldFilter[generated.CustomerOpenBankingObjectFilterInput] {
+	type input = generated.CustomerOpenBankingObjectFilterInput
+
+	fields := []fieldFilter[input]{
+		// An empty openBanking object ({}) counts as a connection; only absent and null do not
+		filterField("exists", func(f *input) bson.M {
+			if f.Exists == nil {
+				return bson.M{}
 			}
-		}
-		if len(andConditions) > 0 {
-			conditions = append(conditions, bson.M{"$and": andConditions})
-		}
-	}
-	if filter.Or != nil {
-		orConditions := []bson.M{}
-		for _, f := range filter.Or {
-			if converted := convertCustomerFilter(f); len(converted) > 0 {
-				orConditions = append(orConditions, converted)
+			if *f.Exists {
+				return bson.M{"openBanking": bson.M{"$ne": nil}}
 			}
-		}
-		if len(orConditions) > 0 {
-			conditions = append(conditions, bson.M{"$or": orConditions})
-		}
+			return bson.M{"openBanking": nil}
+		}),
+		filterField("status", func(f *input) bson.M { return convertEnumFilterOpenBankingStatus("openBanking.status", f.Status) }),
 	}
 
-	if len(conditions) == 0 {
-		return bson.M{}
-	}
-	if len(conditions) == 1 {
-		return conditions[0]
-	}
-	return bson.M{"$and": conditions}
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertCustomerOpenBankingFilter,
+	)...)
 }
 
-// T018: convertEmployeeFilter converts EmployeeQueryFilterInput to MongoDB filter
-func convertEmployeeFilter(filter *generated.EmployeeQueryFilterInput) bson.M {
+// convertCustomerOpenBankingFilter converts CustomerOpenBankingObjectFilterInput to MongoDB filter
+func convertCustomerOpenBankingFilter(filter *generated.CustomerOpenBankingObjectFilterInput) bson.M {
+	return convertFields(filter, customerOpenBankingFilterFields())
+}
+
+// convertEnumFilterOpenBankingStatus converts EnumFilterOfNullableOfOpenBankingStatusInput to MongoDB filter
+func convertEnumFilterOpenBankingStatus(field string, filter *generated.EnumFilterOfNullableOfOpenBankingStatusInput) bson.M {
 	if filter == nil {
 		return bson.M{}
 	}
 
-	conditions := []bson.M{}
-
-	// Simple field filters
-	if filter.FirstName != nil {
-		if converted := convertStringFilter("firstName", filter.FirstName); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
-	}
-	if filter.LastName != nil {
-		if converted := convertStringFilter("lastName", filter.LastName); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+	var eqStr, neqStr *string
+	if filter.Eq != nil {
+		s := string(*filter.Eq)
+		eqStr = &s
 	}
-	if filter.UserEmail != nil {
-		if converted := convertStringFilter("userEmail", filter.UserEmail); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+	if filter.Neq != nil {
+		s := string(*filter.Neq)
+		neqStr = &s
 	}
 
-	// TODO: Add employeeGroups and status filters
-
-	// Recursive AND/OR
-	if filter.And != nil {
-		andConditions := []bson.M{}
-		for _, f := range filter.And {
-			if converted := convertEmployeeFilter(f); len(converted) > 0 {
-				andConditions = append(andConditions, converted)
-			}
-		}
-		if len(andConditions) > 0 {
-			conditions = append(conditions, bson.M{"$and": andConditions})
-		}
+	conditions := []bson.M{}
+	if converted := convertEnumFilterGeneric(field, eqStr, neqStr, openBankingStatusStrings(filter.In), openBankingStatusStrings(filter.Nin)); len(converted) > 0 {
+		conditions = append(conditions, converted)
 	}
-	if filter.Or != nil {
-		orConditions := []bson.M{}
-		for _, f := range filter.Or {
-			if converted := convertEmployeeFilter(f); len(converted) > 0 {
-				orConditions = append(orConditions, converted)
-			}
-		}
-		if len(orConditions) > 0 {
-			conditions = append(conditions, bson.M{"$or": orConditions})
+	convert := func(f *generated.EnumFilterOfNullableOfOpenBankingStatusInput) bson.M {
+		return convertEnumFilterOpenBankingStatus(field, f)
+	}
+	for _, logical := range []bson.M{combineFilters("$and", filter.And, convert), combineFilters("$or", filter.Or, convert)} {
+		if len(logical) > 0 {
+			conditions = append(conditions, logical)
 		}
 	}
 
@@ -491,6 +595,71 @@ func convertEmployeeFilter(filter *generated.EmployeeQueryFilterInput) bson.M {
 	return bson.M{"$and": conditions}
 }
 
+// openBankingStatusStrings converts OpenBankingStatus list values to strings, keeping nil apart from an empty list
+func openBankingStatusStrings(values []*generated.OpenBankingStatus) []interface{} {
+	if values == nil {
+		return nil
+	}
+	converted := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if value == nil {
+			converted = append(converted, nil)
+			continue
+		}
+		converted = append(converted, string(*value))
+	}
+	return converted
+}
+
+// convertEnumFilterUserStatus converts EnumFilterOfNullableOfUserStatusInput to MongoDB filter
+func convertEnumFilterUserStatus(field string, filter *generated.EnumFilterOfNullableOfUserStatusInput) bson.M {
+	if filter == nil {
+		return bson.M{}
+	}
+
+	// Convert UserStatus enum to string for generic enum filter
+	var eqStr, neqStr *string
+	if filter.Eq != nil {
+		s := string(*filter.Eq)
+		eqStr = &s
+	}
+	if filter.Neq != nil {
+		s := string(*filter.Neq)
+		neqStr = &s
+	}
+	return convertEnumFilterGeneric(field, eqStr, neqStr, userStatusStrings(filter.In), userStatusStrings(filter.Nin))
+}
+
+// employeeFilterFields maps the EmployeeQueryFilterInput fields
+// TODO: Add employeeGroups and status filters
+func employeeFilterFields() []fieldFilter[generated.EmployeeQueryFilterInput] {
+	type input = generated.EmployeeQueryFilterInput
+
+	fields := []fieldFilter[input]{
+		filterField("firstName", func(f *input) bson.M {
+			return convertShadowedStringFilter("firstName", shadowField(employeeShadowFields, "firstName"), f.FirstName)
+		}),
+		filterField("lastName", func(f *input) bson.M {
+			return convertShadowedStringFilter("lastName", shadowField(employeeShadowFields, "lastName"), f.LastName)
+		}),
+		filterField("userEmail", func(f *input) bson.M {
+			return convertEmailFilter("userEmail", shadowField(employeeShadowFields, "userEmail"), f.UserEmail)
+		}),
+		filterField("birthDate", func(f *input) bson.M { return convertComparableFilterDate("birthDate", f.BirthDate) }),
+	}
+
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertEmployeeFilter,
+	)...)
+}
+
+// T018: convertEmployeeFilter converts EmployeeQueryFilterInput to MongoDB filter
+func convertEmployeeFilter(filter *generated.EmployeeQueryFilterInput) bson.M {
+	return convertFields(filter, employeeFilterFields())
+}
+
 // convertComparableFilterGUID converts a ComparableFilterOfNullableOfGUIDInput to MongoDB filter
 func convertComparableFilterGUID(field string, filter *generated.ComparableFilterOfNullableOfGUIDInput) bson.M {
 	if filter == nil {
@@ -507,11 +676,11 @@ func convertComparableFilterGUID(field string, filter *generated.ComparableFilte
 		conditions = append(conditions, bson.M{field: bson.M{"$ne": *filter.Neq}})
 	}
 
-	// List operators
-	if filter.In != nil && len(filter.In) > 0 {
+	// List operators: an empty in list matches nothing, an empty nin list is a no-op
+	if filter.In != nil {
 		conditions = append(conditions, bson.M{field: bson.M{"$in": filter.In}})
 	}
-	if filter.Nin != nil && len(filter.Nin) > 0 {
+	if len(filter.Nin) > 0 {
 		conditions = append(conditions, bson.M{field: bson.M{"$nin": filter.Nin}})
 	}
 
@@ -562,6 +731,74 @@ func convertComparableFilterGUID(field string, filter *generated.ComparableFilte
 	return bson.M{"$and": conditions}
 }
 
+// convertExprFilterInt32 converts a ComparableFilterOfInt32Input on a computed value, an
+// aggregation expression, to a $expr condition. $expr cannot use an index (see splitExprConditions)
+func convertExprFilterInt32(value interface{}, filter *generated.ComparableFilterOfInt32Input) bson.M {
+	if expr := int32FilterExpr(value, filter); expr != nil {
+		return bson.M{"$expr": expr}
+	}
+	return bson.M{}
+}
+
+// int32FilterExpr returns the aggregation expression of a ComparableFilterOfInt32Input on value,
+// or nil when the filter sets no operator
+func int32FilterExpr(value interface{}, filter *generated.ComparableFilterOfInt32Input) interface{} {
+	if filter == nil {
+		return nil
+	}
+
+	expressions := bson.A{}
+	compare := func(operator string, operand *int) {
+		if operand != nil {
+			expressions = append(expressions, bson.M{operator: bson.A{value, *operand}})
+		}
+	}
+
+	compare("$eq", filter.Eq)
+	compare("$ne", filter.Neq)
+
+	// List operators: an empty in list matches nothing, an empty nin list is a no-op
+	if filter.In != nil {
+		expressions = append(expressions, bson.M{"$in": bson.A{value, filter.In}})
+	}
+	if len(filter.Nin) > 0 {
+		expressions = append(expressions, bson.M{"$not": bson.A{bson.M{"$in": bson.A{value, filter.Nin}}}})
+	}
+
+	// Comparison operators; the value is never null, so a negated comparison is the complementary one
+	compare("$gt", filter.Gt)
+	compare("$lte", filter.Ngt)
+	compare("$gte", filter.Gte)
+	compare("$lt", filter.Ngte)
+	compare("$lt", filter.Lt)
+	compare("$gte", filter.Nlt)
+	compare("$lte", filter.Lte)
+	compare("$gt", filter.Nlte)
+
+	// Logical operators (recursive)
+	logical := func(operator string, filters []*generated.ComparableFilterOfInt32Input) {
+		nested := bson.A{}
+		for _, f := range filters {
+			if expr := int32FilterExpr(value, f); expr != nil {
+				nested = append(nested, expr)
+			}
+		}
+		if len(nested) > 0 {
+			expressions = append(expressions, bson.M{operator: nested})
+		}
+	}
+	logical("$and", filter.And)
+	logical("$or", filter.Or)
+
+	switch len(expressions) {
+	case 0:
+		return nil
+	case 1:
+		return expressions[0]
+	}
+	return bson.M{"$and": expressions}
+}
+
 // convertEnumFilterCreateStatus converts EnumFilterOfNullableOfCreateStatusInput to MongoDB filter
 func convertEnumFilterCreateStatus(field string, filter *generated.EnumFilterOfNullableOfCreateStatusInput) bson.M {
 	if filter == nil {
@@ -576,10 +813,10 @@ func convertEnumFilterCreateStatus(field string, filter *generated.EnumFilterOfN
 	if filter.Neq != nil {
 		conditions = append(conditions, bson.M{field: bson.M{"$ne": *filter.Neq}})
 	}
-	if filter.In != nil && len(filter.In) > 0 {
+	if filter.In != nil {
 		conditions = append(conditions, bson.M{field: bson.M{"$in": filter.In}})
 	}
-	if filter.Nin != nil && len(filter.Nin) > 0 {
+	if len(filter.Nin) > 0 {
 		conditions = append(conditions, bson.M{field: bson.M{"$nin": filter.Nin}})
 	}
 
@@ -606,10 +843,10 @@ func convertEnumFilterDeleteStatus(field string, filter *generated.EnumFilterOfN
 	if filter.Neq != nil {
 		conditions = append(conditions, bson.M{field: bson.M{"$ne": *filter.Neq}})
 	}
-	if filter.In != nil && len(filter.In) > 0 {
+	if filter.In != nil {
 		conditions = append(conditions, bson.M{field: bson.M{"$in": filter.In}})
 	}
-	if filter.Nin != nil && len(filter.Nin) > 0 {
+	if len(filter.Nin) > 0 {
 		conditions = append(conditions, bson.M{field: bson.M{"$nin": filter.Nin}})
 	}
 
@@ -622,160 +859,117 @@ func convertEnumFilterDeleteStatus(field string, filter *generated.EnumFilterOfN
 	return bson.M{"$and": conditions}
 }
 
-// convertTeamStatusObjectFilter converts TeamStatusObjectFilterInput to MongoDB filter
-func convertTeamStatusObjectFilter(filter *generated.TeamStatusObjectFilterInput) bson.M {
-	if filter == nil {
-		return bson.M{}
-	}
-
-	conditions := []bson.M{}
+// teamStatusFilterFields maps the TeamStatusObjectFilterInput fields
+func teamStatusFilterFields() []fieldFilter[generated.TeamStatusObjectFilterInput] {
+	type input = generated.TeamStatusObjectFilterInput
 
-	if filter.Creation != nil {
-		if converted := convertEnumFilterCreateStatus("status.creation", filter.Creation); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
-	}
-	if filter.Deletion != nil {
-		if converted := convertEnumFilterDeleteStatus("status.deletion", filter.Deletion); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+	fields := []fieldFilter[input]{
+		filterField("creation", func(f *input) bson.M { return convertEnumFilterCreateStatus("status.creation", f.Creation) }),
+		filterField("deletion", func(f *input) bson.M { return convertEnumFilterDeleteStatus("status.deletion", f.Deletion) }),
 	}
 
-	// Recursive AND/OR
-	if filter.And != nil {
-		andConditions := []bson.M{}
-		for _, f := range filter.And {
-			if converted := convertTeamStatusObjectFilter(f); len(converted) > 0 {
-				andConditions = append(andConditions, converted)
-			}
-		}
-		if len(andConditions) > 0 {
-			conditions = append(conditions, bson.M{"$and": andConditions})
-		}
-	}
-	if filter.Or != nil {
-		orConditions := []bson.M{}
-		for _, f := range filter.Or {
-			if converted := convertTeamStatusObjectFilter(f); len(converted) > 0 {
-				orConditions = append(orConditions, converted)
-			}
-		}
-		if len(orConditions) > 0 {
-			conditions = append(conditions, bson.M{"$or": orConditions})
-		}
-	}
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertTeamStatusObjectFilter,
+	)...)
+}
 
-	if len(conditions) == 0 {
-		return bson.M{}
-	}
-	if len(conditions) == 1 {
-		return conditions[0]
-	}
-	return bson.M{"$and": conditions}
+// convertTeamStatusObjectFilter converts TeamStatusObjectFilterInput to MongoDB filter
+func convertTeamStatusObjectFilter(filter *generated.TeamStatusObjectFilterInput) bson.M {
+	return convertFields(filter, teamStatusFilterFields())
 }
 
+// teamFilterFields maps the TeamQueryFilterInput fields
+func teamFilterFields() []fieldFilter[generated.TeamQueryFilterInput] {
+	type input = generated.TeamQueryFilterInput
+
+	// The status object converts as a whole; it registers the paths of its own fields
+	statusPaths := []string{}
+	for _, path := range filterFieldPaths(teamStatusFilterFields()) {
+		statusPaths = append(statusPaths, "status."+path)
+	}
+
+	fields := []fieldFilter[input]{
+		// Simple field filters
+		filterField("name", func(f *input) bson.M {
+			return convertShadowedStringFilter("name", shadowField(teamShadowFields, "name"), f.Name)
+		}),
+		filterField("description", func(f *input) bson.M { return convertStringFilter("description", f.Description) }),
+		filterField("isShared", func(f *input) bson.M {
+			return convertBooleanFilter("isShared", treatsAbsentAsFalse("team", "isShared"), f.IsShared)
+		}),
+		filterField("employeeId", func(f *input) bson.M { return convertComparableFilterGUID("employeeId", f.EmployeeID) }),
+		filterField("memberCount", func(f *input) bson.M { return convertExprFilterInt32(teamMemberCountExpr, f.MemberCount) }),
+
+		// Nested object filter
+		{Paths: statusPaths, Convert: func(f *input) bson.M { return convertTeamStatusObjectFilter(f.Status) }},
+	}
+
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertTeamFilter,
+	)...)
+}
+
+// teamMemberCountExpr counts a team's members; a missing or null member list counts 0
+var teamMemberCountExpr = bson.M{"$size": bson.M{"$ifNull": bson.A{"$teamMembers.keys", bson.A{}}}}
+
 // T019: convertTeamFilter converts TeamQueryFilterInput to MongoDB filter
 func convertTeamFilter(filter *generated.TeamQueryFilterInput) bson.M {
-	if filter == nil {
-		return bson.M{}
-	}
+	return convertFields(filter, teamFilterFields())
+}
 
-	conditions := []bson.M{}
+// inventoryFilterFields maps the InventoryQueryFilterInput fields
+func inventoryFilterFields() []fieldFilter[generated.InventoryQueryFilterInput] {
+	type input = generated.InventoryQueryFilterInput
 
-	// Simple field filters
-	if filter.Name != nil {
-		if converted := convertStringFilter("name", filter.Name); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
-	}
-	if filter.Description != nil {
-		if converted := convertStringFilter("description", filter.Description); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
-	}
-	if filter.IsShared != nil {
-		if converted := convertBooleanFilter("isShared", filter.IsShared); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+	fields := []fieldFilter[input]{
+		filterField("customerId", func(f *input) bson.M { return convertComparableFilterGUID("customerId", f.CustomerID) }),
+		filterField("key", func(f *input) bson.M { return convertStringFilter(inventoryNumberField, f.Key) }),
+		filterField("actionIndicator", func(f *input) bson.M { return convertActionIndicatorFilter("actionIndicator", f.ActionIndicator) }),
 	}
 
-	// Nested object filter
-	if filter.Status != nil {
-		if converted := convertTeamStatusObjectFilter(filter.Status); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
-	}
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertInventoryFilter,
+	)...)
+}
 
-	// Recursive AND/OR
-	if filter.And != nil {
-		andConditions := []bson.M{}
-		for _, f := range filter.And {
-			if converted := convertTeamFilter(f); len(converted) > 0 {
-				andConditions = append(andConditions, converted)
-			}
-		}
-		if len(andConditions) > 0 {
-			conditions = append(conditions, bson.M{"$and": andConditions})
-		}
-	}
-	if filter.Or != nil {
-		orConditions := []bson.M{}
-		for _, f := range filter.Or {
-			if converted := convertTeamFilter(f); len(converted) > 0 {
-				orConditions = append(orConditions, converted)
-			}
-		}
-		if len(orConditions) > 0 {
-			conditions = append(conditions, bson.M{"$or": orConditions})
-		}
-	}
+// convertInventoryFilter converts InventoryQueryFilterInput to MongoDB filter
+func convertInventoryFilter(filter *generated.InventoryQueryFilterInput) bson.M {
+	return convertFields(filter, inventoryFilterFields())
+}
 
-	if len(conditions) == 0 {
-		return bson.M{}
-	}
-	if len(conditions) == 1 {
-		return conditions[0]
-	}
-	return bson.M{"$and": conditions}
+// knownActionIndicators are the stored actionIndicator values; any other value reads as UNKNOWN
+var knownActionIndicators = bson.A{
+	string(generated.ActionIndicatorNone),
+	string(generated.ActionIndicatorCreate),
+	string(generated.ActionIndicatorUpdate),
+	string(generated.ActionIndicatorDelete),
 }
 
-// T020: convertExecutionPlanFilter converts ExecutionPlanQueryFilterInput to MongoDB filter
-func convertExecutionPlanFilter(filter *generated.ExecutionPlanQueryFilterInput) bson.M {
+// convertActionIndicatorFilter converts EnumFilterOfActionIndicatorInput to MongoDB filter. UNKNOWN
+// stands for the stored values other than the known ones, which read as UNKNOWN
+func convertActionIndicatorFilter(field string, filter *generated.EnumFilterOfActionIndicatorInput) bson.M {
 	if filter == nil {
 		return bson.M{}
 	}
 
 	conditions := []bson.M{}
-
-	// Simple field filter
-	if filter.CustomerID != nil {
-		if converted := convertComparableFilterGUID("customerId", filter.CustomerID); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+	if filter.Eq != nil {
+		conditions = append(conditions, actionIndicatorsIn(field, []generated.ActionIndicator{*filter.Eq}))
 	}
-
-	// Recursive AND/OR
-	if filter.And != nil {
-		andConditions := []bson.M{}
-		for _, f := range filter.And {
-			if converted := convertExecutionPlanFilter(f); len(converted) > 0 {
-				andConditions = append(andConditions, converted)
-			}
-		}
-		if len(andConditions) > 0 {
-			conditions = append(conditions, bson.M{"$and": andConditions})
-		}
+	if filter.Neq != nil {
+		conditions = append(conditions, actionIndicatorsNotIn(field, []generated.ActionIndicator{*filter.Neq}))
 	}
-	if filter.Or != nil {
-		orConditions := []bson.M{}
-		for _, f := range filter.Or {
-			if converted := convertExecutionPlanFilter(f); len(converted) > 0 {
-				orConditions = append(orConditions, converted)
-			}
-		}
-		if len(orConditions) > 0 {
-			conditions = append(conditions, bson.M{"$or": orConditions})
-		}
+	if filter.In != nil {
+		conditions = append(conditions, actionIndicatorsIn(field, filter.In))
+	}
+	if len(filter.Nin) > 0 {
+		conditions = append(conditions, actionIndicatorsNotIn(field, filter.Nin))
 	}
 
 	if len(conditions) == 0 {
@@ -787,52 +981,85 @@ func convertExecutionPlanFilter(filter *generated.ExecutionPlanQueryFilterInput)
 	return bson.M{"$and": conditions}
 }
 
-// T021: convertReferencePortfolioFilter converts ReferencePortfolioQueryFilterInput to MongoDB filter
-func convertReferencePortfolioFilter(filter *generated.ReferencePortfolioQueryFilterInput) bson.M {
-	if filter == nil {
-		return bson.M{}
-	}
-
-	conditions := []bson.M{}
+// unknownActionIndicator matches stored actionIndicator values that are not known
+func unknownActionIndicator(field string) bson.M {
+	return bson.M{field: bson.M{"$exists": true, "$nin": knownActionIndicators}}
+}
 
-	// Simple field filter
-	if filter.CustomerID != nil {
-		if converted := convertComparableFilterGUID("customerId", filter.CustomerID); len(converted) > 0 {
-			conditions = append(conditions, converted)
-		}
+// actionIndicatorsIn matches actionIndicator values in a list, where UNKNOWN matches the unknown values
+func actionIndicatorsIn(field string, values []generated.ActionIndicator) bson.M {
+	known, unknown := splitActionIndicators(values)
+	switch {
+	case !unknown:
+		return bson.M{field: bson.M{"$in": known}}
+	case len(known) == 0:
+		return unknownActionIndicator(field)
+	default:
+		return bson.M{"$or": []bson.M{{field: bson.M{"$in": known}}, unknownActionIndicator(field)}}
 	}
+}
 
-	// Recursive AND/OR
-	if filter.And != nil {
-		andConditions := []bson.M{}
-		for _, f := range filter.And {
-			if converted := convertReferencePortfolioFilter(f); len(converted) > 0 {
-				andConditions = append(andConditions, converted)
-			}
-		}
-		if len(andConditions) > 0 {
-			conditions = append(conditions, bson.M{"$and": andConditions})
-		}
+// actionIndicatorsNotIn matches actionIndicator values not in a list, where UNKNOWN excludes the unknown values
+func actionIndicatorsNotIn(field string, values []generated.ActionIndicator) bson.M {
+	known, unknown := splitActionIndicators(values)
+	if !unknown {
+		return bson.M{field: bson.M{"$nin": known}}
 	}
-	if filter.Or != nil {
-		orConditions := []bson.M{}
-		for _, f := range filter.Or {
-			if converted := convertReferencePortfolioFilter(f); len(converted) > 0 {
-				orConditions = append(orConditions, converted)
-			}
-		}
-		if len(orConditions) > 0 {
-			conditions = append(conditions, bson.M{"$or": orConditions})
+	return bson.M{"$nor": []bson.M{{field: bson.M{"$in": known}}, unknownActionIndicator(field)}}
+}
+
+// splitActionIndicators returns the known values of a list as strings, and whether it holds UNKNOWN
+func splitActionIndicators(values []generated.ActionIndicator) (bson.A, bool) {
+	known := bson.A{}
+	unknown := false
+	for _, value := range values {
+		if value == generated.ActionIndicatorUnknown {
+			unknown = true
+			continue
 		}
+		known = append(known, string(value))
 	}
+	return known, unknown
+}
 
-	if len(conditions) == 0 {
-		return bson.M{}
+// executionPlanFilterFields maps the ExecutionPlanQueryFilterInput fields
+func executionPlanFilterFields() []fieldFilter[generated.ExecutionPlanQueryFilterInput] {
+	type input = generated.ExecutionPlanQueryFilterInput
+
+	fields := []fieldFilter[input]{
+		filterField("customerId", func(f *input) bson.M { return convertComparableFilterGUID("customerId", f.CustomerID) }),
 	}
-	if len(conditions) == 1 {
-		return conditions[0]
+
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertExecutionPlanFilter,
+	)...)
+}
+
+// T020: convertExecutionPlanFilter converts ExecutionPlanQueryFilterInput to MongoDB filter
+func convertExecutionPlanFilter(filter *generated.ExecutionPlanQueryFilterInput) bson.M {
+	return convertFields(filter, executionPlanFilterFields())
+}
+
+// referencePortfolioFilterFields maps the ReferencePortfolioQueryFilterInput fields
+func referencePortfolioFilterFields() []fieldFilter[generated.ReferencePortfolioQueryFilterInput] {
+	type input = generated.ReferencePortfolioQueryFilterInput
+
+	fields := []fieldFilter[input]{
+		filterField("customerId", func(f *input) bson.M { return convertComparableFilterGUID("customerId", f.CustomerID) }),
 	}
-	return bson.M{"$and": conditions}
+
+	return append(fields, logicalFilterFields(
+		func(f *input) []*input { return f.And },
+		func(f *input) []*input { return f.Or },
+		convertReferencePortfolioFilter,
+	)...)
+}
+
+// T021: convertReferencePortfolioFilter converts ReferencePortfolioQueryFilterInput to MongoDB filter
+func convertReferencePortfolioFilter(filter *generated.ReferencePortfolioQueryFilterInput) bson.M {
+	return convertFields(filter, referencePortfolioFilterFields())
 }
 
 // Test helpers - exported for unit testing
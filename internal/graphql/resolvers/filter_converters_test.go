@@ -0,0 +1,282 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// Test empty in lists match nothing and empty nin lists are skipped for every list filter type
+func TestFilterConverters_EmptyInNin(t *testing.T) {
+	created := generated.CreateStatusCreated
+	deleted := generated.DeleteStatusDeleted
+	active := generated.UserStatusInit
+
+	tests := []struct {
+		name     string
+		emptyIn  bson.M
+		emptyNin bson.M
+		nonEmpty bson.M
+		expected bson.M // Expected conversion of nonEmpty
+	}{
+		{
+			name:     "string",
+			emptyIn:  convertStringFilter("name", &generated.StringFilterInput{In: []*string{}}),
+			emptyNin: convertStringFilter("name", &generated.StringFilterInput{Nin: []*string{}}),
+			nonEmpty: convertStringFilter("name", &generated.StringFilterInput{In: stringPtrs(1)}),
+			expected: bson.M{"name": bson.M{"$in": stringPtrs(1)}},
+		},
+		{
+			name:     "GUID",
+			emptyIn:  convertComparableFilterGUID("name", &generated.ComparableFilterOfNullableOfGUIDInput{In: []*string{}}),
+			emptyNin: convertComparableFilterGUID("name", &generated.ComparableFilterOfNullableOfGUIDInput{Nin: []*string{}}),
+			nonEmpty: convertComparableFilterGUID("name", &generated.ComparableFilterOfNullableOfGUIDInput{Nin: stringPtrs(1)}),
+			expected: bson.M{"name": bson.M{"$nin": stringPtrs(1)}},
+		},
+		{
+			name:     "collection",
+			emptyIn:  convertCollectionFilterCustomerGroup("name", &generated.CollectionFilterOfCustomerGroupInput{In: []generated.CustomerGroup{}}),
+			emptyNin: convertCollectionFilterCustomerGroup("name", &generated.CollectionFilterOfCustomerGroupInput{Nin: []generated.CustomerGroup{}}),
+			nonEmpty: convertCollectionFilterCustomerGroup("name", &generated.CollectionFilterOfCustomerGroupInput{In: []generated.CustomerGroup{"A"}}),
+			expected: bson.M{"name": bson.M{"$in": []generated.CustomerGroup{"A"}}},
+		},
+		{
+			name:     "create status enum",
+			emptyIn:  convertEnumFilterCreateStatus("name", &generated.EnumFilterOfNullableOfCreateStatusInput{In: []*generated.CreateStatus{}}),
+			emptyNin: convertEnumFilterCreateStatus("name", &generated.EnumFilterOfNullableOfCreateStatusInput{Nin: []*generated.CreateStatus{}}),
+			nonEmpty: convertEnumFilterCreateStatus("name", &generated.EnumFilterOfNullableOfCreateStatusInput{In: []*generated.CreateStatus{&created}}),
+			expected: bson.M{"name": bson.M{"$in": []*generated.CreateStatus{&created}}},
+		},
+		{
+			name:     "delete status enum",
+			emptyIn:  convertEnumFilterDeleteStatus("name", &generated.EnumFilterOfNullableOfDeleteStatusInput{In: []*generated.DeleteStatus{}}),
+			emptyNin: convertEnumFilterDeleteStatus("name", &generated.EnumFilterOfNullableOfDeleteStatusInput{Nin: []*generated.DeleteStatus{}}),
+			nonEmpty: convertEnumFilterDeleteStatus("name", &generated.EnumFilterOfNullableOfDeleteStatusInput{Nin: []*generated.DeleteStatus{&deleted}}),
+			expected: bson.M{"name": bson.M{"$nin": []*generated.DeleteStatus{&deleted}}},
+		},
+		{
+			name:     "generic enum",
+			emptyIn:  convertEnumFilterGeneric("name", nil, nil, []interface{}{}, nil),
+			emptyNin: convertEnumFilterGeneric("name", nil, nil, nil, []interface{}{}),
+			nonEmpty: convertEnumFilterGeneric("name", nil, nil, userStatusStrings([]*generated.UserStatus{&active, nil}), nil),
+			expected: bson.M{"name": bson.M{"$in": []interface{}{"INIT", nil}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, filterMatchesNothing(tt.emptyIn), "empty in should match nothing: %v", tt.emptyIn)
+			assert.Equal(t, bson.M{}, tt.emptyNin)
+			assert.Equal(t, tt.expected, tt.nonEmpty)
+			assert.False(t, filterMatchesNothing(tt.nonEmpty))
+		})
+	}
+}
+
+// Test an empty list is not mistaken for an explicit null check on strings
+func TestConvertStringFilter_EmptyListIsNotNullCheck(t *testing.T) {
+	assert.Equal(t, bson.M{"name": nil}, convertStringFilter("name", &generated.StringFilterInput{}))
+	assert.Equal(t, bson.M{"name": bson.M{"$in": []*string{}}}, convertStringFilter("name", &generated.StringFilterInput{In: []*string{}}))
+	assert.Equal(t, bson.M{}, convertStringFilter("name", &generated.StringFilterInput{Nin: []*string{}}))
+}
+
+// Test activation status in/nin lists are converted
+func TestConvertCustomerFilter_ActivationInNin(t *testing.T) {
+	active := generated.UserStatusInit
+	filter := &generated.CustomerQueryFilterInput{Status: &generated.CustomerStatusObjectFilterInput{
+		Activation: &generated.EnumFilterOfNullableOfUserStatusInput{Nin: []*generated.UserStatus{&active}},
+	}}
+	assert.Equal(t, bson.M{"status.activation": bson.M{"$nin": []interface{}{"INIT"}}}, convertCustomerFilter(filter))
+
+	filter.Status.Activation = &generated.EnumFilterOfNullableOfUserStatusInput{In: []*generated.UserStatus{}}
+	assert.True(t, filterMatchesNothing(convertCustomerFilter(filter)))
+}
+
+// Test openBanking exists distinguishes an empty object (present) from absent or null, and
+// combines with the status of the connection
+func TestConvertCustomerFilter_OpenBanking(t *testing.T) {
+	exists, missing := true, false
+	activated := generated.OpenBankingStatusActivated
+
+	filter := &generated.CustomerQueryFilterInput{OpenBanking: &generated.CustomerOpenBankingObjectFilterInput{Exists: &exists}}
+	assert.Equal(t, bson.M{"openBanking": bson.M{"$ne": nil}}, convertCustomerFilter(filter))
+
+	filter.OpenBanking.Exists = &missing
+	assert.Equal(t, bson.M{"openBanking": nil}, convertCustomerFilter(filter))
+
+	filter.OpenBanking = &generated.CustomerOpenBankingObjectFilterInput{
+		Exists: &exists,
+		Status: &generated.EnumFilterOfNullableOfOpenBankingStatusInput{In: []*generated.OpenBankingStatus{&activated}},
+	}
+	assert.Equal(t, bson.M{"$and": []bson.M{
+		{"openBanking": bson.M{"$ne": nil}},
+		{"openBanking.status": bson.M{"$in": []interface{}{"ACTIVATED"}}},
+	}}, convertCustomerFilter(filter))
+
+	filter.OpenBanking = &generated.CustomerOpenBankingObjectFilterInput{}
+	assert.Equal(t, bson.M{}, convertCustomerFilter(filter))
+}
+
+// Test birthDate ranges compare the stored YYYY-MM-DD strings, whether given as dates or date times,
+// while createDate keeps comparing BSON dates (date-only values at midnight UTC)
+func TestConvertEmployeeFilter_BirthDate(t *testing.T) {
+	from, until, empty := "1980-01-01", "1990-01-01T23:30:00-02:00", ""
+	filter := &generated.EmployeeQueryFilterInput{BirthDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &from, Lt: &until}}
+	assert.Equal(t, bson.M{"$and": []bson.M{
+		{"birthDate": bson.M{"$gte": "1980-01-01"}},
+		{"birthDate": bson.M{"$lt": "1990-01-02"}},
+	}}, convertEmployeeFilter(filter))
+
+	filter.BirthDate = &generated.ComparableFilterOfNullableOfDateTimeInput{Eq: &empty}
+	assert.Equal(t, bson.M{"birthDate": nil}, convertEmployeeFilter(filter))
+
+	created := "2024-01-01T00:00:00Z"
+	assert.Equal(t, bson.M{"createDate": bson.M{"$gte": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		convertComparableFilterDateTime("createDate", &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &created}))
+	assert.Equal(t, bson.M{"createDate": bson.M{"$gte": time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		convertComparableFilterDateTime("createDate", &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &from}))
+}
+
+// Test createDate filters compare the instant of offset and date-only values in UTC, and skip garbage
+func TestConvertComparableFilterDateTime_Normalized(t *testing.T) {
+	offset, date, garbage := "2024-01-01T01:30:00+02:00", "2024-01-01", "yesterday"
+	assert.Equal(t, bson.M{"$and": []bson.M{
+		{"createDate": bson.M{"$gte": time.Date(2023, 12, 31, 23, 30, 0, 0, time.UTC)}},
+		{"createDate": bson.M{"$lt": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}},
+	}}, convertComparableFilterDateTime("createDate", &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &offset, Lt: &date}))
+	assert.Equal(t, bson.M{}, convertComparableFilterDateTime("createDate", &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &garbage}))
+}
+
+// Test teams filter on their leader's employeeId alongside the status filter
+func TestConvertTeamFilter_EmployeeID(t *testing.T) {
+	leader := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	deleted := generated.DeleteStatusDeleted
+	filter := &generated.TeamQueryFilterInput{
+		EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &leader},
+		Status:     &generated.TeamStatusObjectFilterInput{Deletion: &generated.EnumFilterOfNullableOfDeleteStatusInput{Neq: &deleted}},
+	}
+	assert.Equal(t, bson.M{"$and": []bson.M{
+		{"employeeId": leader},
+		{"status.deletion": bson.M{"$ne": deleted}},
+	}}, convertTeamFilter(filter))
+}
+
+// Test teams filter on their member count, a missing member list counting 0
+func TestConvertTeamFilter_MemberCount(t *testing.T) {
+	count := bson.M{"$size": bson.M{"$ifNull": bson.A{"$teamMembers.keys", bson.A{}}}}
+
+	assert.Equal(t, bson.M{"$expr": bson.M{"$lt": bson.A{count, 3}}}, convertTeamFilter(&generated.TeamQueryFilterInput{
+		MemberCount: &generated.ComparableFilterOfInt32Input{Lt: intRef(3)},
+	}))
+
+	// Negated comparisons become the complementary ones; in, nin and and/or stay inside one $expr
+	assert.Equal(t, bson.M{"$expr": bson.M{"$and": bson.A{
+		bson.M{"$not": bson.A{bson.M{"$in": bson.A{count, []int{0}}}}},
+		bson.M{"$lte": bson.A{count, 5}},
+		bson.M{"$or": bson.A{
+			bson.M{"$eq": bson.A{count, 1}},
+			bson.M{"$in": bson.A{count, []int{}}},
+		}},
+	}}}, convertTeamFilter(&generated.TeamQueryFilterInput{
+		MemberCount: &generated.ComparableFilterOfInt32Input{
+			Nin: []int{0},
+			Ngt: intRef(5),
+			Or:  []*generated.ComparableFilterOfInt32Input{{Eq: intRef(1)}, {In: []int{}}},
+		},
+	}))
+
+	assert.Equal(t, bson.M{}, convertTeamFilter(&generated.TeamQueryFilterInput{MemberCount: &generated.ComparableFilterOfInt32Input{}}))
+}
+
+// Test which combined filters are recognized as never matching
+func TestFilterMatchesNothing(t *testing.T) {
+	emptyIn := bson.M{"name": bson.M{"$in": []*string{}}}
+	eq := bson.M{"name": "Alice"}
+
+	tests := []struct {
+		name     string
+		filter   bson.M
+		expected bool
+	}{
+		{name: "empty filter", filter: bson.M{}, expected: false},
+		{name: "empty in", filter: emptyIn, expected: true},
+		{name: "non-empty in", filter: bson.M{"name": bson.M{"$in": []string{"a"}}}, expected: false},
+		{name: "empty nin", filter: bson.M{"name": bson.M{"$nin": []string{}}}, expected: false},
+		{name: "and with one empty in", filter: bson.M{"$and": []bson.M{eq, emptyIn}}, expected: true},
+		{name: "or with one empty in", filter: bson.M{"$or": []bson.M{eq, emptyIn}}, expected: false},
+		{name: "or with only empty in", filter: bson.M{"$or": []bson.M{emptyIn, emptyIn}}, expected: true},
+		{name: "nested under and", filter: bson.M{"$and": []bson.M{eq, {"$or": []bson.M{emptyIn}}}}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, filterMatchesNothing(tt.filter))
+		})
+	}
+}
+
+// Test search returns an empty page for in: [] without touching the database
+func TestSearchEntities_EmptyInShortCircuits(t *testing.T) {
+	where := &generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{In: []*string{}}}
+
+	// A nil database client would fail with DATABASE_ERROR if the query ran
+	var teams []*generated.TeamQueryOutput
+	count, totalCount, hasNextPage, hasPreviousPage, startCursor, endCursor, err := searchEntities(context.Background(), nil, entityConfigs["team"], where, nil, SearchPaging{}, SearchOptions{}, &teams)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 0, totalCount)
+	assert.False(t, hasNextPage)
+	assert.False(t, hasPreviousPage)
+	assert.Nil(t, startCursor)
+	assert.Nil(t, endCursor)
+	assert.Empty(t, teams)
+}
+
+// Test startsWith targets the lowercase shadow field only when shadow fields are enabled
+func TestConvertTeamFilter_StartsWithShadowField(t *testing.T) {
+	t.Cleanup(func() { SetShadowFieldsEnabled(false) })
+
+	prefix := "Sales.Team"
+	contains := "North"
+	filter := &generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{
+		Contains: &contains,
+		Or:       []*generated.StringFilterInput{{StartsWith: &prefix}},
+	}}
+
+	SetShadowFieldsEnabled(false)
+	assert.Equal(t, bson.M{"$and": []bson.M{
+		{"name": bson.M{"$regex": "North", "$options": "i"}},
+		{"$or": []bson.M{{"name": bson.M{"$regex": "^Sales.Team", "$options": "i"}}}},
+	}}, convertTeamFilter(filter))
+
+	// The prefix is lowercased and escaped, and the regex is case-sensitive so an index on nameLower applies
+	SetShadowFieldsEnabled(true)
+	assert.Equal(t, bson.M{"$and": []bson.M{
+		{"name": bson.M{"$regex": "North", "$options": "i"}},
+		{"$or": []bson.M{{"nameLower": bson.M{"$regex": `^sales\.team`}}}},
+	}}, convertTeamFilter(filter))
+
+	// Fields without a declared shadow field keep the case-insensitive regex
+	description := &generated.TeamQueryFilterInput{Description: &generated.StringFilterInput{StartsWith: &prefix}}
+	assert.Equal(t, bson.M{"description": bson.M{"$regex": "^Sales.Team", "$options": "i"}}, convertTeamFilter(description))
+}
+
+// Test written string fields carry their lowercase shadow fields, whether or not filters use them
+func TestSetShadowFields(t *testing.T) {
+	set := bson.M{"firstName": "Ärne", "lastName": "McDonald", "isShared": true, "lastUpdateDate": "2026-01-01T00:00:00Z"}
+	setShadowFields(set, customerShadowFields)
+
+	assert.Equal(t, bson.M{
+		"firstName":      "Ärne",
+		"firstNameLower": "ärne",
+		"lastName":       "McDonald",
+		"lastNameLower":  "mcdonald",
+		"isShared":       true,
+		"lastUpdateDate": "2026-01-01T00:00:00Z",
+	}, set)
+}
@@ -0,0 +1,204 @@
+package resolvers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// FilterLimits bounds the size of search filter inputs
+type FilterLimits struct {
+	MaxIn    int // Maximum values in an in list
+	MaxNin   int // Maximum values in a nin list
+	MaxAnd   int // Maximum conditions in an and list
+	MaxOr    int // Maximum conditions in an or list
+	MaxDepth int // Maximum nesting of and/or lists
+	MaxBytes int // Maximum estimated serialized filter size in bytes
+}
+
+// DefaultFilterLimits returns the filter limits used unless configured otherwise
+func DefaultFilterLimits() FilterLimits {
+	return FilterLimits{
+		MaxIn:    DefaultMaxFilterListValues,
+		MaxNin:   DefaultMaxFilterListValues,
+		MaxAnd:   DefaultMaxFilterConditions,
+		MaxOr:    DefaultMaxFilterConditions,
+		MaxDepth: DefaultMaxFilterDepth,
+		MaxBytes: DefaultMaxFilterBytes,
+	}
+}
+
+// SetFilterLimits sets the limits enforced on search filters
+// Values below 1 keep the current limit
+func SetFilterLimits(filter FilterLimits) {
+	current := limits.Filter
+	for _, update := range []struct {
+		target *int
+		value  int
+	}{
+		{&current.MaxIn, filter.MaxIn},
+		{&current.MaxNin, filter.MaxNin},
+		{&current.MaxAnd, filter.MaxAnd},
+		{&current.MaxOr, filter.MaxOr},
+		{&current.MaxDepth, filter.MaxDepth},
+		{&current.MaxBytes, filter.MaxBytes},
+	} {
+		if update.value >= 1 {
+			*update.target = update.value
+		}
+	}
+	limits.Filter = current
+}
+
+// scalarFilterBytes approximates the serialized size of a non-string filter value
+const scalarFilterBytes = 8
+
+// filterBudget tracks the estimated size of a filter while its input is walked
+type filterBudget struct {
+	limits FilterLimits
+	used   int
+	depth  int // and/or lists entered on the current path
+}
+
+// charge adds bytes to the estimate and fails once the size limit is exceeded
+func (b *filterBudget) charge(bytes int) error {
+	b.used += bytes
+	if b.used > b.limits.MaxBytes {
+		return NewInvalidInput(fmt.Sprintf("filter exceeds the maximum size of %d bytes", b.limits.MaxBytes), "where")
+	}
+	return nil
+}
+
+// checkList fails when an in/nin/and/or list is longer than its limit
+func (b *filterBudget) checkList(operator string, length int) error {
+	limit := 0
+	switch operator {
+	case "in":
+		limit = b.limits.MaxIn
+	case "nin":
+		limit = b.limits.MaxNin
+	case "and":
+		limit = b.limits.MaxAnd
+	case "or":
+		limit = b.limits.MaxOr
+	default:
+		return nil
+	}
+
+	if length > limit {
+		return NewInvalidInput(fmt.Sprintf("filter operator %q accepts at most %d elements, got %d", operator, limit, length), "where")
+	}
+	return nil
+}
+
+// enter descends into an and/or list and fails once the nesting limit is exceeded
+func (b *filterBudget) enter() error {
+	b.depth++
+	if b.depth > b.limits.MaxDepth {
+		return NewInvalidInput(fmt.Sprintf("filter nests and/or at most %d levels deep", b.limits.MaxDepth), "where")
+	}
+	return nil
+}
+
+// validateFilter checks a filter input against the configured limits, the values of its GUID,
+// e-mail and DateTime filters and its and/or lists before it is converted
+// The input is walked once; list lengths are checked before their elements are visited
+func validateFilter(filter interface{}) error {
+	budget := &filterBudget{limits: limits.Filter}
+	return budget.walk(reflect.ValueOf(filter))
+}
+
+// walk visits a filter input value, charging its fields and checking every list
+func (b *filterBudget) walk(value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return b.walk(value.Elem())
+	case reflect.Struct:
+		if guidFilter, ok := value.Interface().(generated.ComparableFilterOfNullableOfGUIDInput); ok {
+			if err := validateGUIDFilter(guidFilter); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Field(i)
+			if field.IsZero() {
+				continue
+			}
+
+			name := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+			if field.Kind() == reflect.Slice {
+				if err := b.checkList(name, field.Len()); err != nil {
+					return err
+				}
+			}
+
+			if emailFilter, ok := field.Interface().(*generated.StringFilterInput); ok && emailFilterFields[name] {
+				if err := validateEmailFilter(name, emailFilter); err != nil {
+					return err
+				}
+			}
+			if dateFilter, ok := field.Interface().(*generated.ComparableFilterOfNullableOfDateTimeInput); ok && dateTimeFilterFields[name] {
+				if err := validateDateTimeFilter(name, dateFilter, time.Now()); err != nil {
+					return err
+				}
+			}
+
+			if err := b.charge(len(name)); err != nil {
+				return err
+			}
+			nested := name == "and" || name == "or"
+			if nested {
+				if err := b.enter(); err != nil {
+					return err
+				}
+			}
+			if err := b.walk(field); err != nil {
+				return err
+			}
+			if nested {
+				b.depth--
+				// Checked once the list is walked, so its size and depth limits are reported first
+				if err := checkLogicalList(name, field); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if err := b.walk(value.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.String:
+		return b.charge(value.Len())
+	default:
+		return b.charge(scalarFilterBytes)
+	}
+}
+
+// validateGUIDFilter fails when a GUID filter compares with a value that is not a UUID
+// Its and/or filters are validated as the walk reaches them
+func validateGUIDFilter(filter generated.ComparableFilterOfNullableOfGUIDInput) error {
+	values := []*string{filter.Eq, filter.Neq, filter.Gt, filter.Ngt, filter.Gte, filter.Ngte, filter.Lt, filter.Nlt, filter.Lte, filter.Nlte}
+	values = append(values, filter.In...)
+	values = append(values, filter.Nin...)
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		if reason := checkUUID(*value); reason != "" {
+			return NewInvalidInput(reason, "where")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// useFilterLimits sets small filter limits for the duration of a test
+func useFilterLimits(t *testing.T, limits FilterLimits) {
+	t.Helper()
+	previous := CurrentLimits().Filter
+	SetFilterLimits(limits)
+	t.Cleanup(func() { SetFilterLimits(previous) })
+}
+
+// stringPtrs returns n string pointers
+func stringPtrs(n int) []*string {
+	values := make([]*string, n)
+	for i := range values {
+		value := "value"
+		values[i] = &value
+	}
+	return values
+}
+
+// Test each list operator at and over its limit
+func TestValidateFilter_ListLimits(t *testing.T) {
+	useFilterLimits(t, FilterLimits{MaxIn: 3, MaxNin: 2, MaxAnd: 2, MaxOr: 1, MaxDepth: 10, MaxBytes: 1 << 20})
+
+	name := "Alice"
+	nameFilters := func(n int) []*generated.TeamQueryFilterInput {
+		filters := make([]*generated.TeamQueryFilterInput, n)
+		for i := range filters {
+			filters[i] = &generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{Eq: &name}}
+		}
+		return filters
+	}
+
+	tests := []struct {
+		name     string
+		filter   interface{}
+		contains string // Empty when the filter is within its limits
+	}{
+		{
+			name:   "in at limit",
+			filter: &generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{In: stringPtrs(3)}},
+		},
+		{
+			name:     "in over limit",
+			filter:   &generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{In: stringPtrs(4)}},
+			contains: `filter operator "in" accepts at most 3 elements, got 4`,
+		},
+		{
+			name:   "nin at limit",
+			filter: &generated.CustomerQueryFilterInput{CustomerGroups: &generated.CollectionFilterOfCustomerGroupInput{Nin: []generated.CustomerGroup{"A", "B"}}},
+		},
+		{
+			name:     "nin over limit",
+			filter:   &generated.CustomerQueryFilterInput{CustomerGroups: &generated.CollectionFilterOfCustomerGroupInput{Nin: []generated.CustomerGroup{"A", "B", "C"}}},
+			contains: `filter operator "nin" accepts at most 2 elements, got 3`,
+		},
+		{
+			name:   "and at limit",
+			filter: &generated.TeamQueryFilterInput{And: nameFilters(2)},
+		},
+		{
+			name:     "and over limit",
+			filter:   &generated.TeamQueryFilterInput{And: nameFilters(3)},
+			contains: `filter operator "and" accepts at most 2 elements, got 3`,
+		},
+		{
+			name:   "or at limit",
+			filter: &generated.TeamQueryFilterInput{Or: nameFilters(1)},
+		},
+		{
+			name:     "or over limit",
+			filter:   &generated.TeamQueryFilterInput{Or: nameFilters(2)},
+			contains: `filter operator "or" accepts at most 1 elements, got 2`,
+		},
+		{
+			name: "in over limit nested inside and",
+			filter: &generated.TeamQueryFilterInput{And: []*generated.TeamQueryFilterInput{
+				{Name: &generated.StringFilterInput{In: stringPtrs(4)}},
+			}},
+			contains: `filter operator "in" accepts at most 3 elements`,
+		},
+		{
+			name:   "nil filter",
+			filter: (*generated.TeamQueryFilterInput)(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFilter(tt.filter)
+			if tt.contains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			requireInvalidInput(t, err, tt.contains)
+		})
+	}
+}
+
+// Test and/or nesting at and over the depth limit, counting and and or alike
+func TestValidateFilter_DepthLimit(t *testing.T) {
+	useFilterLimits(t, FilterLimits{MaxIn: 100, MaxNin: 100, MaxAnd: 100, MaxOr: 100, MaxDepth: 2, MaxBytes: 1 << 20})
+
+	name := "Alice"
+	leaf := &generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{Eq: &name}}
+	nest := func(operator string, inner *generated.TeamQueryFilterInput) *generated.TeamQueryFilterInput {
+		if operator == "and" {
+			return &generated.TeamQueryFilterInput{And: []*generated.TeamQueryFilterInput{inner}}
+		}
+		return &generated.TeamQueryFilterInput{Or: []*generated.TeamQueryFilterInput{inner}}
+	}
+
+	assert.NoError(t, validateFilter(nest("and", nest("or", leaf))))
+
+	// Siblings share a level
+	assert.NoError(t, validateFilter(&generated.TeamQueryFilterInput{
+		And: []*generated.TeamQueryFilterInput{nest("or", leaf), nest("and", leaf)},
+	}))
+
+	err := validateFilter(nest("and", nest("or", nest("and", leaf))))
+	requireInvalidInput(t, err, "filter nests and/or at most 2 levels deep")
+}
+
+// Test the estimated size limit counts field names and values
+func TestValidateFilter_SizeLimit(t *testing.T) {
+	useFilterLimits(t, FilterLimits{MaxIn: 100, MaxNin: 100, MaxAnd: 100, MaxOr: 100, MaxBytes: 20})
+
+	// "name" + "eq" + 14 characters = 20 bytes
+	atLimit := strings.Repeat("a", 14)
+	assert.NoError(t, validateFilter(&generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{Eq: &atLimit}}))
+
+	overLimit := strings.Repeat("a", 15)
+	err := validateFilter(&generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{Eq: &overLimit}})
+	requireInvalidInput(t, err, "filter exceeds the maximum size of 20 bytes")
+
+	// Many values within the list limit still exceed the size limit
+	err = validateFilter(&generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{In: stringPtrs(10)}})
+	requireInvalidInput(t, err, "maximum size of 20 bytes")
+}
+
+// Test GUID filter values must be UUIDs, including list entries and nested and/or filters
+func TestValidateFilter_GUIDValues(t *testing.T) {
+	leader := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	invalid := "employee-001"
+	guid := func(filter *generated.ComparableFilterOfNullableOfGUIDInput) *generated.TeamQueryFilterInput {
+		return &generated.TeamQueryFilterInput{EmployeeID: filter}
+	}
+
+	assert.NoError(t, validateFilter(guid(&generated.ComparableFilterOfNullableOfGUIDInput{Eq: &leader, In: []*string{&leader, nil}})))
+
+	err := validateFilter(guid(&generated.ComparableFilterOfNullableOfGUIDInput{Neq: &invalid}))
+	requireInvalidInput(t, err, "invalid UUID format: employee-001")
+
+	err = validateFilter(guid(&generated.ComparableFilterOfNullableOfGUIDInput{Nin: []*string{&leader, &invalid}}))
+	requireInvalidInput(t, err, "invalid UUID format: employee-001")
+
+	err = validateFilter(&generated.TeamQueryFilterInput{Or: []*generated.TeamQueryFilterInput{
+		guid(&generated.ComparableFilterOfNullableOfGUIDInput{And: []*generated.ComparableFilterOfNullableOfGUIDInput{{Eq: &invalid}}}),
+	}})
+	requireInvalidInput(t, err, "invalid UUID format: employee-001")
+}
+
+// Test partial limit updates keep the remaining limits
+func TestSetFilterLimits(t *testing.T) {
+	useFilterLimits(t, DefaultFilterLimits())
+
+	SetFilterLimits(FilterLimits{MaxIn: 10})
+
+	assert.Equal(t, FilterLimits{
+		MaxIn:    10,
+		MaxNin:   DefaultMaxFilterListValues,
+		MaxAnd:   DefaultMaxFilterConditions,
+		MaxOr:    DefaultMaxFilterConditions,
+		MaxDepth: DefaultMaxFilterDepth,
+		MaxBytes: DefaultMaxFilterBytes,
+	}, CurrentLimits().Filter)
+}
+
+// Test search rejects an oversized filter before touching the database
+func TestFilterLimits_SearchRejectsBeforeDatabaseWork(t *testing.T) {
+	useFilterLimits(t, FilterLimits{MaxIn: 2, MaxNin: 2, MaxAnd: 2, MaxOr: 2, MaxBytes: 1 << 20})
+
+	where := &generated.EmployeeQueryFilterInput{FirstName: &generated.StringFilterInput{In: stringPtrs(3)}}
+
+	// A nil database client would fail with DATABASE_ERROR if validation ran later
+	var employees []*generated.Employee
+	_, _, _, _, _, _, err := searchEntities(context.Background(), nil, entityConfigs["employee"], where, nil, SearchPaging{}, SearchOptions{}, &employees)
+	requireInvalidInput(t, err, `filter operator "in"`)
+
+	_, err = BuildExportQuery(context.Background(), "employee", []byte(`{"firstName":{"in":["a","b","c"]}}`), nil, 10)
+	requireInvalidInput(t, err, `filter operator "in"`)
+}
@@ -0,0 +1,183 @@
+package resolvers
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Search filters count how often each entity field is filtered with each operator, so
+// deprecations and index planning can rely on what clients actually send. The counts only take
+// fields the entity's converter maps and operators of the generated filter inputs, so their
+// labels are a closed set
+
+// FilterOperatorUsage is how often searches of an entity filtered a field with an operator.
+// Field is the dotted input path, "" for the top-level and/or; Operator is the input operator,
+// "null" for an empty operator input (matching null) and "eq" for fields taking a plain value
+type FilterOperatorUsage struct {
+	Entity   string
+	Field    string
+	Operator string
+	Count    int64
+}
+
+// filterUsageKey identifies one counter of the filter usage
+type filterUsageKey struct {
+	entity, field, operator string
+}
+
+// filterUsage counts the filtered fields and operators since startup
+var filterUsage = struct {
+	mu     sync.Mutex
+	counts map[filterUsageKey]int64
+}{counts: map[filterUsageKey]int64{}}
+
+// CurrentFilterUsage returns the filter operator counts, ordered by entity, field and operator
+func CurrentFilterUsage() []FilterOperatorUsage {
+	filterUsage.mu.Lock()
+	defer filterUsage.mu.Unlock()
+	usage := make([]FilterOperatorUsage, 0, len(filterUsage.counts))
+	for key, count := range filterUsage.counts {
+		usage = append(usage, FilterOperatorUsage{Entity: key.entity, Field: key.field, Operator: key.operator, Count: count})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		a, b := usage[i], usage[j]
+		if a.Entity != b.Entity {
+			return a.Entity < b.Entity
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Operator < b.Operator
+	})
+	return usage
+}
+
+// recordFilterUsage counts the fields and operators set in an entity filter input. Fields its
+// converter does not map and operators it ignores are not counted, as they are not applied
+func recordFilterUsage(filter interface{}) {
+	value := reflect.ValueOf(filter)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return
+	}
+	coverage := filterUsageCoverage(value.Type().Elem())
+	if coverage == nil {
+		return
+	}
+
+	counter := &usageCounter{entity: coverage.entity, counts: map[filterUsageKey]int64{}}
+	counter.walkFields(value.Elem(), "", coverage.handled)
+	if len(counter.counts) == 0 {
+		return
+	}
+
+	filterUsage.mu.Lock()
+	defer filterUsage.mu.Unlock()
+	for key, count := range counter.counts {
+		filterUsage.counts[key] += count
+	}
+}
+
+// usageCoverage is the entity and handled paths of a filter input type, as in converterCoverage
+type usageCoverage struct {
+	entity  string
+	handled map[string]bool
+}
+
+// usageCoverages caches the usageCoverage of each filter input type counted so far
+var usageCoverages sync.Map
+
+// filterUsageCoverage returns the entity and handled paths of a filter input type, or nil if
+// converterCoverage does not list it
+func filterUsageCoverage(inputType reflect.Type) *usageCoverage {
+	if cached, ok := usageCoverages.Load(inputType); ok {
+		return cached.(*usageCoverage)
+	}
+	for _, coverage := range converterCoverage {
+		if coverage.InputType == inputType {
+			cached, _ := usageCoverages.LoadOrStore(inputType, &usageCoverage{entity: coverage.Entity, handled: handledInputPaths(inputType)})
+			return cached.(*usageCoverage)
+		}
+	}
+	return nil
+}
+
+// usageCounter gathers the counts of one filter while it is walked, so the shared counters are
+// locked once per filter
+type usageCounter struct {
+	entity string
+	counts map[filterUsageKey]int64
+}
+
+func (c *usageCounter) add(field, operator string) {
+	c.counts[filterUsageKey{entity: c.entity, field: field, operator: operator}]++
+}
+
+// walkFields visits the set fields of an entity filter input or one of its nested object inputs,
+// like warningCollector.walkFields; prefix is the dotted path of the object input
+func (c *usageCounter) walkFields(value reflect.Value, prefix string, handled map[string]bool) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		path := prefix + name
+
+		fieldType := field.Type()
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case name == "and" || name == "or":
+			if !handled[path] {
+				continue
+			}
+			c.add(strings.TrimSuffix(prefix, "."), name)
+			for j := 0; j < field.Len(); j++ {
+				if element := field.Index(j); !element.IsNil() {
+					c.walkFields(element.Elem(), prefix, handled)
+				}
+			}
+		case fieldType.Kind() == reflect.Struct && strings.Contains(fieldType.Name(), "Object"):
+			c.walkFields(field.Elem(), path+".", handled)
+		case !handled[path]:
+		case field.Kind() == reflect.Ptr && fieldType.Kind() == reflect.Struct:
+			if !c.walkOperators(field.Elem(), path) {
+				c.add(path, "null")
+			}
+		default:
+			c.add(path, "eq")
+		}
+	}
+}
+
+// walkOperators counts the set operators of a field filter input, including its and/or lists,
+// and reports whether any was set
+func (c *usageCounter) walkOperators(value reflect.Value, path string) bool {
+	ignored := ignoredFilterOperators[value.Type()]
+	set := false
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		set = true
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		if slices.Contains(ignored, name) {
+			continue
+		}
+		c.add(path, name)
+		if name != "and" && name != "or" {
+			continue
+		}
+		for j := 0; j < field.Len(); j++ {
+			if element := field.Index(j); !element.IsNil() {
+				c.walkOperators(element.Elem(), path)
+			}
+		}
+	}
+	return set
+}
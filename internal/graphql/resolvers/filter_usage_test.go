@@ -0,0 +1,121 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// filterUsageCounts returns the filter usage counters of an entity by "field operator"
+func filterUsageCounts(entity string) map[string]int64 {
+	counts := map[string]int64{}
+	for _, usage := range CurrentFilterUsage() {
+		if usage.Entity == entity {
+			counts[usage.Field+" "+usage.Operator] = usage.Count
+		}
+	}
+	return counts
+}
+
+// nestedCustomerFilter nests field operators, and/or lists of fields and operators, object
+// inputs, a plain value and an empty operator input
+func nestedCustomerFilter() *generated.CustomerQueryFilterInput {
+	jane, doe := "Jane", "Doe"
+	blocked := generated.UserStatusBlocked
+	exists := true
+	return &generated.CustomerQueryFilterInput{
+		FirstName: &generated.StringFilterInput{Eq: &jane, Or: []*generated.StringFilterInput{{Contains: &doe}}},
+		Or: []*generated.CustomerQueryFilterInput{
+			{LastName: &generated.StringFilterInput{Eq: &doe}},
+			{Status: &generated.CustomerStatusObjectFilterInput{Activation: &generated.EnumFilterOfNullableOfUserStatusInput{Neq: &blocked}}},
+		},
+		OpenBanking:    &generated.CustomerOpenBankingObjectFilterInput{Exists: &exists},
+		CustomerGroups: &generated.CollectionFilterOfCustomerGroupInput{In: []generated.CustomerGroup{}},
+		UserEmail:      &generated.StringFilterInput{},
+	}
+}
+
+// TestRecordFilterUsage_CountsNestedFilter tests a search counts every field and operator of a
+// nested filter once per use
+func TestRecordFilterUsage_CountsNestedFilter(t *testing.T) {
+	before := filterUsageCounts("customer")
+
+	_, err := BuildSearchPipeline(entityConfigs["customer"], nestedCustomerFilter(), nil, SearchPaging{})
+	require.NoError(t, err)
+
+	after := filterUsageCounts("customer")
+	increments := map[string]int64{}
+	for key, count := range after {
+		if delta := count - before[key]; delta != 0 {
+			increments[key] = delta
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		" or":                   1,
+		"firstName eq":          1,
+		"firstName or":          1,
+		"firstName contains":    1,
+		"lastName eq":           1,
+		"status.activation neq": 1,
+		"openBanking.exists eq": 1,
+		"customerGroups in":     1,
+		"userEmail null":        1,
+	}, increments)
+}
+
+// TestRecordFilterUsage_SkipsUnappliedInputs tests fields the converter does not map and
+// operators it ignores are not counted, so the counter labels stay a closed set
+func TestRecordFilterUsage_SkipsUnappliedInputs(t *testing.T) {
+	useLenientFilters(t, true)
+	before := filterUsageCounts("customer")
+
+	_, err := BuildSearchPipeline(entityConfigs["customer"], partiallySupportedFilter(), nil, SearchPaging{})
+	require.NoError(t, err)
+
+	after := filterUsageCounts("customer")
+	assert.Equal(t, before["firstName eq"]+1, after["firstName eq"])
+	assert.Equal(t, before[" or"]+1, after[" or"])
+	assert.Equal(t, before["lastName or"]+1, after["lastName or"])
+	for key := range after {
+		assert.NotContains(t, []string{"employeeId eq", "payment.status eq", "lastName ncontains"}, key)
+	}
+}
+
+// TestRecordFilterUsage_NoFilter tests searches without a filter count nothing
+func TestRecordFilterUsage_NoFilter(t *testing.T) {
+	before := CurrentFilterUsage()
+	var filter *generated.CustomerQueryFilterInput
+	_, err := BuildSearchPipeline(entityConfigs["customer"], filter, nil, SearchPaging{})
+	require.NoError(t, err)
+	assert.Equal(t, before, CurrentFilterUsage())
+}
+
+// BenchmarkConvertCustomerFilter compares converting a nested filter with the usage counting a
+// search adds to it
+func BenchmarkConvertCustomerFilter(b *testing.B) {
+	config := entityConfigs["customer"]
+	filter := nestedCustomerFilter()
+
+	b.Run("count", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			recordFilterUsage(filter)
+		}
+	})
+	b.Run("convert", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buildEntityFilter(config, filter)
+		}
+	})
+	b.Run("convert and count", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			recordFilterUsage(filter)
+			buildEntityFilter(config, filter)
+		}
+	})
+}
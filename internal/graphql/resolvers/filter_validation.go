@@ -0,0 +1,145 @@
+package resolvers
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// stringFilterType is the string field filter, which converts to a null check when it sets no operator
+var stringFilterType = reflect.TypeOf(generated.StringFilterInput{})
+
+// validateEntityFilter checks a search filter input before its pipeline is built: scalar leaf
+// values (see checkFilterValues), the size limits,
+// and/or lists whose conditions are all empty, fields and operators the converter ignores (unless
+// filters are lenient), and conditions on the entity's deletion field,
+// which conflict with the deletion exclusion every search applies and would silently match nothing.
+// Entities with a DeletionFilter accept conditions on the field that do not select deleted entities
+func validateEntityFilter(config EntityConfig, filter interface{}) error {
+	if err := checkFilterValues(filter); err != nil {
+		return err
+	}
+	if err := validateFilter(filter); err != nil {
+		return err
+	}
+	if err := checkSupportedFilter(filter); err != nil {
+		return err
+	}
+	if config.FilterConverter == nil || filter == nil {
+		return nil
+	}
+
+	if config.DeletionFilter != nil {
+		return config.DeletionFilter(filter)
+	}
+	if filterReferencesField(config.FilterConverter(filter), config.DeletionField) {
+		return NewInvalidInput(fmt.Sprintf(
+			"filter cannot reference %s: searches exclude deleted entities, and accessing them requires the includeDeleted flag, which this server does not offer",
+			config.DeletionField,
+		), "where")
+	}
+	return nil
+}
+
+// checkInventoryDeletionFilter fails when an inventory filter selects actionIndicator DELETE, the
+// deletion marker: with eq, or an in list holding it, here or in a nested and/or filter. Such a
+// condition would match nothing, or less than asked for, as searches exclude deleted inventories
+func checkInventoryDeletionFilter(filter *generated.InventoryQueryFilterInput) error {
+	if filter == nil {
+		return nil
+	}
+	if indicator := filter.ActionIndicator; indicator != nil {
+		if (indicator.Eq != nil && *indicator.Eq == generated.ActionIndicatorDelete) || slices.Contains(indicator.In, generated.ActionIndicatorDelete) {
+			return NewInvalidInput(
+				"filter cannot select actionIndicator DELETE: searches exclude deleted entities, and accessing them requires the includeDeleted flag, which this server does not offer",
+				"where",
+			)
+		}
+	}
+	for _, nested := range append(append([]*generated.InventoryQueryFilterInput{}, filter.And...), filter.Or...) {
+		if err := checkInventoryDeletionFilter(nested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterReferencesField reports whether a converted filter has a condition on a field or one of
+// its subfields, at the top level or inside $and/$or/$nor
+func filterReferencesField(filter bson.M, field string) bool {
+	for key, value := range filter {
+		if key == field || strings.HasPrefix(key, field+".") {
+			return true
+		}
+		if key != "$and" && key != "$or" && key != "$nor" {
+			continue
+		}
+		conditions, _ := value.([]bson.M)
+		for _, condition := range conditions {
+			if filterReferencesField(condition, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkLogicalList fails when a non-empty and/or list holds only empty conditions. Converters drop
+// empty conditions, so such a list would otherwise vanish and the filter match more than asked for
+func checkLogicalList(operator string, list reflect.Value) error {
+	if list.Len() == 0 {
+		return nil
+	}
+	for i := 0; i < list.Len(); i++ {
+		if !isEmptyFilterInput(list.Index(i)) {
+			return nil
+		}
+	}
+	return NewInvalidInput(fmt.Sprintf("filter operator %q needs at least one non-empty condition", operator), "where")
+}
+
+// isEmptyFilterInput reports whether a filter input sets no condition, so it converts to an empty
+// filter: nil, no fields set, or only empty nin lists and and/or lists of empty conditions.
+// An empty in list matches nothing and a string filter without operators matches null, so both are conditions
+func isEmptyFilterInput(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil() || isEmptyFilterInput(value.Elem())
+	case reflect.Struct:
+		if value.Type() == stringFilterType {
+			return false
+		}
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Field(i)
+			if field.IsZero() {
+				continue
+			}
+
+			name := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+			switch {
+			case name == "and" || name == "or":
+				for j := 0; j < field.Len(); j++ {
+					if !isEmptyFilterInput(field.Index(j)) {
+						return false
+					}
+				}
+			case name == "nin" && field.Len() == 0:
+				continue
+			case field.Kind() == reflect.Slice:
+				return false
+			case !isEmptyFilterInput(field):
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
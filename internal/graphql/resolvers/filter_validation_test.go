@@ -0,0 +1,86 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// Test empty and/or conditions are dropped, and lists of only empty conditions rejected
+func TestValidateEntityFilter_EmptyBranches(t *testing.T) {
+	config := entityConfigs["customer"]
+	jane := "Jane"
+	named := &generated.CustomerQueryFilterInput{FirstName: &generated.StringFilterInput{Eq: &jane}}
+
+	// An empty branch beside a real one is dropped instead of reaching MongoDB as {}
+	pipeline, err := BuildSearchPipeline(config, &generated.CustomerQueryFilterInput{
+		Or: []*generated.CustomerQueryFilterInput{named, {}},
+	}, nil, SearchPaging{})
+	require.NoError(t, err)
+	match := pipeline[0]["$match"].(bson.M)["$and"].([]bson.M)
+	assert.Equal(t, bson.M{"$or": []bson.M{{"firstName": "Jane"}}}, match[1])
+
+	// An empty in list matches nothing and an empty string filter matches null, so both count as conditions
+	assert.NoError(t, validateEntityFilter(config, &generated.CustomerQueryFilterInput{
+		And: []*generated.CustomerQueryFilterInput{{Status: &generated.CustomerStatusObjectFilterInput{Activation: &generated.EnumFilterOfNullableOfUserStatusInput{In: []*generated.UserStatus{}}}}},
+	}))
+	pipeline, err = BuildSearchPipeline(config, &generated.CustomerQueryFilterInput{
+		Or: []*generated.CustomerQueryFilterInput{{FirstName: &generated.StringFilterInput{}}},
+	}, nil, SearchPaging{})
+	require.NoError(t, err)
+	match = pipeline[0]["$match"].(bson.M)["$and"].([]bson.M)
+	assert.Equal(t, bson.M{"$or": []bson.M{{"firstName": nil}}}, match[1])
+
+	for name, filter := range map[string]*generated.CustomerQueryFilterInput{
+		"or of empty filters": {Or: []*generated.CustomerQueryFilterInput{{}, nil}},
+		"or of empty field filters": {Or: []*generated.CustomerQueryFilterInput{
+			{CreateDate: &generated.ComparableFilterOfNullableOfDateTimeInput{}},
+			{Status: &generated.CustomerStatusObjectFilterInput{Activation: &generated.EnumFilterOfNullableOfUserStatusInput{Nin: []*generated.UserStatus{}}}},
+		}},
+		"nested and of empty filters": {And: []*generated.CustomerQueryFilterInput{
+			named,
+			{Or: []*generated.CustomerQueryFilterInput{{And: []*generated.CustomerQueryFilterInput{{}}}}},
+		}},
+		"field filter or of empty filters": {CreateDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Or: []*generated.ComparableFilterOfNullableOfDateTimeInput{{}}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			requireInvalidInput(t, validateEntityFilter(config, filter), "needs at least one non-empty condition")
+		})
+	}
+}
+
+// Test conditions on the deletion field are rejected, at any nesting
+func TestValidateEntityFilter_DeletionField(t *testing.T) {
+	deleted := generated.DeleteStatusDeleted
+	deletion := &generated.CustomerStatusObjectFilterInput{Deletion: &generated.EnumFilterOfNullableOfDeleteStatusInput{Eq: &deleted}}
+
+	for name, filter := range map[string]*generated.CustomerQueryFilterInput{
+		"top level": {Status: deletion},
+		"inside or": {Or: []*generated.CustomerQueryFilterInput{{Status: deletion}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := BuildSearchPipeline(entityConfigs["customer"], filter, nil, SearchPaging{})
+			requireInvalidInput(t, err, "filter cannot reference status.deletion")
+			assert.ErrorContains(t, err, "includeDeleted")
+		})
+	}
+
+	// Other status fields stay filterable
+	active := generated.UserStatusActive
+	_, err := BuildSearchPipeline(entityConfigs["customer"], &generated.CustomerQueryFilterInput{
+		Status: &generated.CustomerStatusObjectFilterInput{Activation: &generated.EnumFilterOfNullableOfUserStatusInput{Eq: &active}},
+	}, nil, SearchPaging{})
+	assert.NoError(t, err)
+}
+
+// Test field references match the field and its subfields only
+func TestFilterReferencesField(t *testing.T) {
+	assert.True(t, filterReferencesField(bson.M{"$nor": []bson.M{{"actionIndicator": "DELETE"}}}, "actionIndicator"))
+	assert.True(t, filterReferencesField(bson.M{"status.deletion.at": "x"}, "status"))
+	assert.False(t, filterReferencesField(bson.M{"status.deletionReason": "x"}, "status.deletion"))
+	assert.False(t, filterReferencesField(bson.M{"items": bson.M{"$elemMatch": bson.M{"status.deletion": "x"}}}, "status.deletion"))
+}
@@ -0,0 +1,85 @@
+package resolvers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Filter inputs are typed, so GraphQL variables and export request bodies holding an object where
+// a scalar is expected are rejected while they are decoded. checkFilterValues asserts this again
+// before a filter is converted: a map or loosely typed value reaching the converters could carry
+// query operators such as {"$gt": ""} into the MongoDB filter and match more than asked for
+
+// checkFilterValues fails when a leaf value of a filter input is not a scalar or a list of
+// scalars, naming its path in the input (e.g. and[0].firstName.eq)
+func checkFilterValues(filter interface{}) error {
+	return checkFilterValue("", reflect.ValueOf(filter))
+}
+
+// checkFilterValue checks the value at path, which is empty for the filter itself. Filter input
+// structs and their and/or lists are walked, other values must be scalars or lists of scalars
+func checkFilterValue(path string, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return checkFilterValue(path, value.Elem())
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				name = field.Name
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			if err := checkFilterValue(fieldPath, value.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			element := value.Index(i)
+			for element.Kind() == reflect.Ptr || element.Kind() == reflect.Interface {
+				if element.IsNil() {
+					break
+				}
+				element = element.Elem()
+			}
+			if element.Kind() == reflect.Slice || element.Kind() == reflect.Array {
+				return newFilterValueError(fmt.Sprintf("%s[%d]", path, i), "a nested list")
+			}
+			if err := checkFilterValue(fmt.Sprintf("%s[%d]", path, i), element); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		return newFilterValueError(path, "an object")
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return nil
+	default:
+		return newFilterValueError(path, "a "+value.Kind().String())
+	}
+}
+
+// newFilterValueError reports a filter value that is not a scalar or a list of scalars
+func newFilterValueError(path, got string) error {
+	if path == "" {
+		path = "where"
+	}
+	return NewInvalidInput(fmt.Sprintf("filter value %s must be a scalar or a list of scalars, got %s", path, got), "where")
+}
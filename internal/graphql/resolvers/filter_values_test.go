@@ -0,0 +1,81 @@
+package resolvers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// looseFilterInput stands in for a filter input type accepting loose JSON values
+type looseFilterInput struct {
+	Name  *looseFieldInput    `json:"name,omitempty"`
+	And   []*looseFilterInput `json:"and,omitempty"`
+	Count int32               `json:"count,omitempty"`
+}
+
+type looseFieldInput struct {
+	Eq interface{}   `json:"eq,omitempty"`
+	In []interface{} `json:"in,omitempty"`
+}
+
+// Test typed filters of every entity pass, with scalars, lists of scalars and nested and/or lists
+func TestCheckFilterValues_TypedFiltersPass(t *testing.T) {
+	name := "Jane"
+	shared := true
+	id := "550e8400-e29b-41d4-a716-446655440000"
+	filters := []interface{}{
+		nil,
+		(*generated.CustomerQueryFilterInput)(nil),
+		&generated.CustomerQueryFilterInput{
+			FirstName: &generated.StringFilterInput{Eq: &name, In: []*string{&name, nil}},
+			IsShared:  &generated.BooleanFilterInput{Eq: &shared},
+			Or: []*generated.CustomerQueryFilterInput{
+				{EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &id}},
+			},
+		},
+		&generated.TeamQueryFilterInput{IsShared: &generated.BooleanFilterInput{Neq: &shared}},
+	}
+	for _, filter := range filters {
+		assert.NoError(t, checkFilterValues(filter))
+	}
+}
+
+// Test objects and nested lists are rejected with the path of the value
+func TestCheckFilterValues_RejectsObjects(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter interface{}
+		path   string
+		got    string
+	}{
+		{"operator as eq value", &looseFilterInput{Name: &looseFieldInput{Eq: map[string]interface{}{"$gt": ""}}}, "name.eq", "an object"},
+		{"operator in a list", &looseFilterInput{Name: &looseFieldInput{In: []interface{}{"a", map[string]interface{}{"$ne": nil}}}}, "name.in[1]", "an object"},
+		{"nested list", &looseFilterInput{Name: &looseFieldInput{In: []interface{}{[]interface{}{"a"}}}}, "name.in[0]", "a nested list"},
+		{"inside and", &looseFilterInput{And: []*looseFilterInput{{}, {Name: &looseFieldInput{Eq: map[string]interface{}{"$where": "1"}}}}}, "and[1].name.eq", "an object"},
+		{"whole filter", map[string]interface{}{"firstName": "Jane"}, "where", "an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFilterValues(tt.filter)
+			require.Error(t, err)
+			var queryErr *QueryError
+			require.True(t, errors.As(err, &queryErr))
+			assert.Equal(t, ErrCodeInvalidInput, queryErr.Code)
+			assert.Equal(t, "where", queryErr.Field)
+			assert.Equal(t, "filter value "+tt.path+" must be a scalar or a list of scalars, got "+tt.got, queryErr.Message)
+		})
+	}
+}
+
+// Test scalars held in interface values pass
+func TestCheckFilterValues_LooseScalarsPass(t *testing.T) {
+	filter := &looseFilterInput{
+		Name:  &looseFieldInput{Eq: "$gt", In: []interface{}{"a", 1, 2.5, true, nil}},
+		Count: 3,
+	}
+	assert.NoError(t, checkFilterValues(filter))
+}
@@ -0,0 +1,180 @@
+package resolvers
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// lenientFilters applies the supported part of a search filter or order and reports the fields
+// and operators it ignored in the result's warnings, instead of rejecting the input
+var lenientFilters = false
+
+// SetLenientFilters sets whether search filters and orders with fields or operators this server
+// does not support are applied partially with warnings (true) or rejected with INVALID_INPUT (false)
+func SetLenientFilters(enabled bool) {
+	lenientFilters = enabled
+}
+
+// ignoredFilterOperators lists, per field filter input, the operators its converter does not apply
+var ignoredFilterOperators = map[reflect.Type][]string{
+	reflect.TypeOf(generated.StringFilterInput{}):                         {"ncontains", "nstartsWith", "nendsWith"},
+	reflect.TypeOf(generated.ComparableFilterOfNullableOfDateTimeInput{}): {"in", "nin", "ngt", "ngte", "nlt", "nlte"},
+	reflect.TypeOf(generated.ComparableFilterOfNullableOfGUIDInput{}):     {"ngt", "ngte", "nlt", "nlte"},
+	reflect.TypeOf(generated.EnumFilterOfNullableOfUserStatusInput{}):     {"and", "or"},
+	reflect.TypeOf(generated.EnumFilterOfNullableOfCreateStatusInput{}):   {"and", "or"},
+	reflect.TypeOf(generated.EnumFilterOfNullableOfDeleteStatusInput{}):   {"and", "or"},
+}
+
+// Reasons reported for ignored filter inputs
+const (
+	unsupportedFieldReason    = "this server does not filter on this field"
+	unsupportedOperatorReason = "this server does not apply this operator to this field"
+)
+
+// searchFilterWarnings returns the warnings of a search result: the filter inputs that were
+// ignored in lenient mode. Strict mode rejects such filters, so it returns nil
+func searchFilterWarnings(filter interface{}) []*generated.QueryWarning {
+	if !lenientFilters {
+		return nil
+	}
+	return unsupportedFilterInputs(filter)
+}
+
+// checkSupportedFilter fails in strict mode when a filter sets a field or operator its converter ignores
+func checkSupportedFilter(filter interface{}) error {
+	if lenientFilters {
+		return nil
+	}
+	warnings := unsupportedFilterInputs(filter)
+	if len(warnings) == 0 {
+		return nil
+	}
+	warning := warnings[0]
+	if warning.Operator != nil {
+		return NewInvalidInput(fmt.Sprintf("filter operator %q of %q is not supported by this server", *warning.Operator, warning.Field), "where")
+	}
+	return NewInvalidInput(fmt.Sprintf("filter field %q is not supported by this server", warning.Field), "where")
+}
+
+// unsupportedFilterInputs returns the fields and operators set in a search filter input that its
+// converter ignores, in the order of the input type's fields and without duplicates. Fields are checked against the paths
+// the entity converter registers (see converterCoverage), operators against ignoredFilterOperators
+func unsupportedFilterInputs(filter interface{}) []*generated.QueryWarning {
+	value := reflect.ValueOf(filter)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil
+	}
+	handled := handledInputPaths(value.Type().Elem())
+	if handled == nil {
+		return nil
+	}
+
+	collector := &warningCollector{}
+	collector.walkFields(value.Elem(), "", handled)
+	return collector.warnings
+}
+
+// handledInputPaths returns the input field paths the converter of a filter or sorter input type
+// maps, or nil for a type without a registered converter
+func handledInputPaths(inputType reflect.Type) map[string]bool {
+	for _, coverage := range converterCoverage {
+		if coverage.InputType != inputType {
+			continue
+		}
+		handled := make(map[string]bool, len(coverage.Handled))
+		for _, path := range coverage.Handled {
+			handled[path] = true
+		}
+		return handled
+	}
+	return nil
+}
+
+// warningCollector gathers the ignored filter inputs while a filter is walked
+type warningCollector struct {
+	warnings []*generated.QueryWarning
+	seen     map[string]bool // field and operator of the recorded warnings
+}
+
+// add records an ignored field (operator empty) or operator, once
+func (c *warningCollector) add(field, operator, reason string) {
+	key := field + " " + operator
+	if c.seen[key] {
+		return
+	}
+	if c.seen == nil {
+		c.seen = make(map[string]bool)
+	}
+	c.seen[key] = true
+
+	warning := &generated.QueryWarning{Field: field, Reason: reason}
+	if operator != "" {
+		warning.Operator = &operator
+	}
+	c.warnings = append(c.warnings, warning)
+}
+
+// walkFields visits the set fields of an entity filter input or one of its nested object inputs.
+// prefix is the dotted path of the object input ("" for the entity input, "payment." below it)
+func (c *warningCollector) walkFields(value reflect.Value, prefix string, handled map[string]bool) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		name := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+		path := prefix + name
+
+		fieldType := field.Type()
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case name == "and" || name == "or":
+			if !handled[path] {
+				c.add(strings.TrimSuffix(prefix, "."), name, unsupportedOperatorReason)
+				continue
+			}
+			for j := 0; j < field.Len(); j++ {
+				if element := field.Index(j); !element.IsNil() {
+					c.walkFields(element.Elem(), prefix, handled)
+				}
+			}
+		case fieldType.Kind() == reflect.Struct && strings.Contains(fieldType.Name(), "Object"):
+			// Nested object inputs register the paths of their fields, like inputFieldPaths lists them
+			c.walkFields(field.Elem(), path+".", handled)
+		case !handled[path]:
+			c.add(path, "", unsupportedFieldReason)
+		case field.Kind() == reflect.Ptr && fieldType.Kind() == reflect.Struct:
+			c.walkOperators(field.Elem(), path)
+		}
+	}
+}
+
+// walkOperators visits the set operators of a field filter input, including its and/or lists
+func (c *warningCollector) walkOperators(value reflect.Value, path string) {
+	ignored := ignoredFilterOperators[value.Type()]
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		name := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+		if slices.Contains(ignored, name) {
+			c.add(path, name, unsupportedOperatorReason)
+			continue
+		}
+		if name != "and" && name != "or" {
+			continue
+		}
+		for j := 0; j < field.Len(); j++ {
+			if element := field.Index(j); !element.IsNil() {
+				c.walkOperators(element.Elem(), path)
+			}
+		}
+	}
+}
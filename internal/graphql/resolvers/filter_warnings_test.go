@@ -0,0 +1,100 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// useLenientFilters sets the filter mode for the rest of a test
+func useLenientFilters(t *testing.T, enabled bool) {
+	t.Helper()
+	previous := lenientFilters
+	SetLenientFilters(enabled)
+	t.Cleanup(func() { SetLenientFilters(previous) })
+}
+
+// partiallySupportedFilter sets a supported field, an unsupported field, an unsupported nested
+// object field and an unsupported operator, the last one twice
+func partiallySupportedFilter() *generated.CustomerQueryFilterInput {
+	jane, doe := "Jane", "Doe"
+	employee := "0b7e6f3c-5a8e-4a59-9c39-6c2d8d7c1d11"
+	active := generated.PaymentStatusActive
+	return &generated.CustomerQueryFilterInput{
+		FirstName:  &generated.StringFilterInput{Eq: &jane},
+		EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &employee},
+		Payment:    &generated.CustomerPaymentObjectFilterInput{Status: &generated.EnumFilterOfNullableOfPaymentStatusInput{Eq: &active}},
+		Or: []*generated.CustomerQueryFilterInput{
+			{LastName: &generated.StringFilterInput{Ncontains: &doe}},
+			{LastName: &generated.StringFilterInput{Or: []*generated.StringFilterInput{{Ncontains: &doe}}}},
+		},
+	}
+}
+
+// TestSearchFilterWarnings_Lenient tests lenient mode applies the supported part of a filter and
+// lists every ignored field and operator once
+func TestSearchFilterWarnings_Lenient(t *testing.T) {
+	useLenientFilters(t, true)
+	filter := partiallySupportedFilter()
+
+	pipeline, err := BuildSearchPipeline(entityConfigs["customer"], filter, nil, SearchPaging{})
+	require.NoError(t, err)
+	match := pipeline[0]["$match"].(bson.M)["$and"].([]bson.M)
+	assert.Equal(t, bson.M{"firstName": "Jane"}, match[1])
+
+	ncontains := "ncontains"
+	assert.Equal(t, []*generated.QueryWarning{
+		{Field: "lastName", Operator: &ncontains, Reason: "this server does not apply this operator to this field"},
+		{Field: "employeeId", Reason: "this server does not filter on this field"},
+		{Field: "payment.status", Reason: "this server does not filter on this field"},
+	}, searchFilterWarnings(filter))
+
+	// Unsupported and/or lists of object inputs name the object
+	or := "or"
+	initStatus := generated.UserStatusInit
+	assert.Equal(t, []*generated.QueryWarning{
+		{Field: "status", Operator: &or, Reason: "this server does not apply this operator to this field"},
+	}, searchFilterWarnings(&generated.CustomerQueryFilterInput{Status: &generated.CustomerStatusObjectFilterInput{
+		Or: []*generated.CustomerStatusObjectFilterInput{{Activation: &generated.EnumFilterOfNullableOfUserStatusInput{Eq: &initStatus}}},
+	}}))
+
+	jane := "Jane"
+	assert.Nil(t, searchFilterWarnings(&generated.CustomerQueryFilterInput{FirstName: &generated.StringFilterInput{Eq: &jane}}))
+	assert.Nil(t, searchFilterWarnings((*generated.CustomerQueryFilterInput)(nil)))
+}
+
+// TestSearchFilterWarnings_Strict tests strict mode rejects the same filter, naming the first ignored input
+func TestSearchFilterWarnings_Strict(t *testing.T) {
+	useLenientFilters(t, false)
+
+	_, err := BuildSearchPipeline(entityConfigs["customer"], partiallySupportedFilter(), nil, SearchPaging{})
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeInvalidInput, queryErr.Code)
+	assert.EqualError(t, err, `filter operator "ncontains" of "lastName" is not supported by this server`)
+
+	_, err = BuildSearchPipeline(entityConfigs["employee"], &generated.EmployeeQueryFilterInput{
+		EmployeeGroups: &generated.CollectionFilterOfEmployeeGroupInput{In: []generated.EmployeeGroup{}},
+	}, nil, SearchPaging{})
+	assert.EqualError(t, err, `filter field "employeeGroups" is not supported by this server`)
+
+	assert.Nil(t, searchFilterWarnings(partiallySupportedFilter()))
+}
+
+// TestCustomerSearch_ReturnsFilterWarnings tests the warnings reach the search result in lenient mode
+func TestCustomerSearch_ReturnsFilterWarnings(t *testing.T) {
+	useLenientFilters(t, true)
+	resolver := NewResolver(&fakeSearchDB{identifiers: []string{"a"}})
+
+	first := int64(10)
+	result, err := resolver.Query().CustomerSearch(context.Background(), partiallySupportedFilter(), nil, &first, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 3)
+	assert.Equal(t, "lastName", result.Warnings[0].Field)
+}
@@ -3,7 +3,11 @@ package resolvers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/generated"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -13,11 +17,29 @@ import (
 // T005: EntityConfig struct for parameterized entity queries
 // T007: Added FilterConverter for search functionality
 type EntityConfig struct {
-	CollectionName  string                              // MongoDB collection name
-	DeletionField   string                              // Field indicating deletion status (e.g., "status.deletion" or "actionIndicator")
-	DeletionValue   string                              // Value indicating deleted entity (e.g., "DELETED" or "DELETE")
-	SorterConverter func(interface{}) []bson.M          // Converts GraphQL sorter input to MongoDB aggregation pipeline stages
-	FilterConverter func(interface{}) bson.M            // Converts GraphQL filter input to MongoDB filter (T007)
+	CollectionName      string                              // MongoDB collection name
+	DeletionField       string                              // Field indicating deletion status (e.g., "status.deletion" or "actionIndicator")
+	DeletionValue       string                              // Value indicating deleted entity (e.g., "DELETED" or "DELETE")
+	SorterConverter     func(interface{}) ([]bson.M, error) // Converts GraphQL sorter input to MongoDB aggregation pipeline stages
+	DefaultSort         []SortField                         // Sort used when the caller supplies no order, before the identifier tiebreaker
+	FilterConverter     func(interface{}) bson.M            // Converts GraphQL filter input to MongoDB filter (T007)
+	DeletionFilter      func(interface{}) error             // Checks the filter input's conditions on DeletionField (nil = such conditions are rejected)
+	AuthorizationFilter func(context.Context) bson.M        // Restricts results to what the caller may see (nil = unrestricted)
+	ShadowFields        map[string]string                   // String fields with a lowercase shadow field for indexed startsWith (field -> shadow field)
+	ProjectionFields    map[string][]string                 // Document fields read by field resolvers, kept when the field is selected (field -> document fields)
+	AggregateBatchSize  int                                 // Upper bound of the entity's aggregation cursor batches, below MAX_AGGREGATE_BATCH_SIZE (0 = that bound)
+	HeavyFields         []string                            // Large embedded fields searches return as null unless includeHeavyFields is set (see SetSearchHeavyFields)
+	Versioned           bool                                // Every change increments the documents' version, so Get responses can be tagged with it
+	PurgeAfter          time.Duration                       // Deleted documents are hard-deleted this long after their deleteDate (0 = kept forever)
+	FacetFields         map[string]FacetField               // Fields searches can count facets of, keyed by the facet field enum value
+	RootFields          []string                            // Query and Mutation fields serving the entity, disabled with it (see SetDisabledFeatures)
+}
+
+// ExcludeDeleted adds the condition excluding the entity's deleted documents to filter and returns it.
+// Queries and mutations use it rather than spelling out DeletionField and DeletionValue
+func (c EntityConfig) ExcludeDeleted(filter bson.M) bson.M {
+	filter[c.DeletionField] = bson.M{"$ne": c.DeletionValue}
+	return filter
 }
 
 // T013: Entity configuration map with all 6 entities
@@ -27,12 +49,24 @@ var entityConfigs = map[string]EntityConfig{
 		DeletionField:   "status.deletion",
 		DeletionValue:   "DELETED",
 		SorterConverter: customerSorterConverter,
+		DefaultSort:     []SortField{{Field: "createDate", Direction: generated.SortEnumTypeDesc}},
 		FilterConverter: func(filter interface{}) bson.M {
 			if f, ok := filter.(*generated.CustomerQueryFilterInput); ok {
 				return convertCustomerFilter(f)
 			}
 			return bson.M{}
 		},
+		AuthorizationFilter: customerAuthorizationFilter,
+		ShadowFields:        customerShadowFields,
+		HeavyFields:         []string{"payment", "openBanking"},
+		ProjectionFields:    map[string][]string{"displayName": displayNameFields},
+		Versioned:           true,
+		PurgeAfter:          400 * 24 * time.Hour,
+		FacetFields: map[string]FacetField{
+			string(generated.CustomerFacetFieldStatusActivation): {Path: "status.activation"},
+			string(generated.CustomerFacetFieldCustomerGroups):   {Path: "customerGroups", Array: true},
+		},
+		RootFields: customerRootFields,
 	},
 	"employee": {
 		CollectionName:  "employees",
@@ -45,25 +79,44 @@ var entityConfigs = map[string]EntityConfig{
 			}
 			return bson.M{}
 		},
+		ShadowFields:     employeeShadowFields,
+		ProjectionFields: map[string][]string{"displayName": displayNameFields},
+		RootFields:       employeeRootFields,
 	},
 	"team": {
 		CollectionName:  "teams",
 		DeletionField:   "status.deletion",
 		DeletionValue:   "DELETED",
 		SorterConverter: teamSorterConverter, // T044: Added team sorter converter
+		DefaultSort:     []SortField{{Field: "name", Direction: generated.SortEnumTypeAsc}},
 		FilterConverter: func(filter interface{}) bson.M {
 			if f, ok := filter.(*generated.TeamQueryFilterInput); ok {
 				return convertTeamFilter(f)
 			}
 			return bson.M{}
 		},
+		ShadowFields:     teamShadowFields,
+		ProjectionFields: map[string][]string{"members": {"teamMembers"}},
+		RootFields:       teamRootFields,
 	},
 	"inventory": {
 		CollectionName:  "inventories",
 		DeletionField:   "actionIndicator",
 		DeletionValue:   "DELETE",
 		SorterConverter: inventorySorterConverter,
-		FilterConverter: nil, // No search functionality for inventory in this feature
+		FilterConverter: func(filter interface{}) bson.M {
+			if f, ok := filter.(*generated.InventoryQueryFilterInput); ok {
+				return convertInventoryFilter(f)
+			}
+			return bson.M{}
+		},
+		DeletionFilter: func(filter interface{}) error {
+			if f, ok := filter.(*generated.InventoryQueryFilterInput); ok {
+				return checkInventoryDeletionFilter(f)
+			}
+			return nil
+		},
+		RootFields: inventoryRootFields,
 	},
 	"executionPlan": {
 		CollectionName:  "executionPlans",
@@ -76,6 +129,7 @@ var entityConfigs = map[string]EntityConfig{
 			}
 			return bson.M{}
 		},
+		RootFields: executionPlanRootFields,
 	},
 	"referencePortfolio": {
 		CollectionName:  "referencePortfolios",
@@ -88,18 +142,49 @@ var entityConfigs = map[string]EntityConfig{
 			}
 			return bson.M{}
 		},
+		RootFields: referencePortfolioRootFields,
 	},
 }
 
-// T006: UUID validation helper function (using existing isValidUUID from customer.go)
+// T006: UUID validation helper function (using checkUUID from uuid.go)
+
+// byKeysPaging is the paging info of a byKeys or Get connection, which always holds a single page
+func byKeysPaging() *generated.PageInfo {
+	return &generated.PageInfo{HasNextPage: false, HasPreviousPage: false}
+}
+
+// getConnectionData is the data of a Get connection: the entity, or nothing when it was not found
+func getConnectionData[T any](entity *T) []*T {
+	if entity == nil {
+		return []*T{}
+	}
+	return []*T{entity}
+}
+
+// missingIdentifiers returns the requested identifiers that are not among the found entities,
+// deduplicated and in request order. Deleted entities are never found, so they count as missing
+func missingIdentifiers[T any](requested []string, found []*T, identifier func(*T) string) []string {
+	foundIDs := make(map[string]bool, len(found))
+	for _, entity := range found {
+		foundIDs[identifier(entity)] = true
+	}
+
+	missing := []string{}
+	for _, id := range deduplicateIdentifiersGeneric(requested) {
+		if !foundIDs[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
 
 // T007: Batch size validation helper function
 func validateBatchSizeGeneric(identifiers []string) error {
-	if len(identifiers) > MaxBatchSize {
+	if len(identifiers) > limits.MaxBatchSize {
 		return newInvalidInputError(fmt.Sprintf(
 			"batch size exceeds maximum: requested %d, maximum %d",
 			len(identifiers),
-			MaxBatchSize,
+			limits.MaxBatchSize,
 		))
 	}
 	return nil
@@ -121,92 +206,241 @@ func deduplicateIdentifiersGeneric(identifiers []string) []string {
 }
 
 // T011: Convert SortEnumType to MongoDB sort direction integer
-func sortEnumToInt(sortEnum generated.SortEnumType) int {
-	if sortEnum == generated.SortEnumTypeAsc {
-		return 1
+// Unknown values are rejected instead of silently sorting descending
+func sortEnumToInt(sortEnum generated.SortEnumType) (int, error) {
+	switch sortEnum {
+	case generated.SortEnumTypeAsc:
+		return 1, nil
+	case generated.SortEnumTypeDesc:
+		return -1, nil
+	default:
+		return 0, newInvalidInputError(fmt.Sprintf("invalid sort direction: %q", string(sortEnum)))
+	}
+}
+
+// sortKeyPrefix prefixes temporary fields computed for null-safe sorting
+// Searches keep them in the page so cursors carry the computed values; other reads remove them after the $sort
+const sortKeyPrefix = "_sortKey"
+
+// nullPlaceholder replaces missing values in null-safe sort keys
+// It sorts after all valid string values, so ASC places nulls last and DESC places them first
+const nullPlaceholder = "zzzzzzz-null-placeholder"
+
+// sortBuilder collects sort keys in request order into a single $sort stage
+// Separate $sort stages would only honor the last one, so tie-breaking fields must share a stage
+type sortBuilder struct {
+	computed bson.M // Temporary sort keys added before the $sort
+	keys     bson.D // Sort keys in priority order
+}
+
+// add sorts by the field as stored
+func (b *sortBuilder) add(field string, sortEnum generated.SortEnumType) error {
+	direction, err := sortEnumToInt(sortEnum)
+	if err != nil {
+		return err
 	}
-	return -1
+	b.appendKey(field, direction)
+	return nil
 }
 
-// T012: Append null-safe sorting stages for SQL-standard null handling
+// T012: addNullSafe sorts by the field with SQL-standard null handling
 // ASC: non-nulls first (ascending), nulls last
 // DESC: nulls first, non-nulls last (descending)
-func appendNullSafeSorting(pipeline []bson.M, field string, sortEnum generated.SortEnumType) []bson.M {
-	if sortEnum == generated.SortEnumTypeAsc {
-		// For ascending: non-nulls first, nulls last
-		pipeline = append(pipeline, bson.M{
-			"$addFields": bson.M{
-				"_sortKey": bson.M{
-					"$ifNull": []interface{}{
-						"$" + field,
-						"zzzzzzz-null-placeholder", // Sorts after all valid values
-					},
-				},
-			},
-		})
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"_sortKey": 1}})
-		pipeline = append(pipeline, bson.M{"$project": bson.M{"_sortKey": 0}}) // Remove temp field
-	} else {
-		// For descending: nulls first, non-nulls last
-		pipeline = append(pipeline, bson.M{
-			"$addFields": bson.M{
-				"_sortKey": bson.M{
-					"$ifNull": []interface{}{
-						"$" + field,
-						"zzzzzzz-null-placeholder", // Sorts first when descending
-					},
-				},
-			},
-		})
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"_sortKey": -1}})
-		pipeline = append(pipeline, bson.M{"$project": bson.M{"_sortKey": 0}})
+func (b *sortBuilder) addNullSafe(field string, sortEnum generated.SortEnumType) error {
+	return b.addComputed(field, bson.M{"$ifNull": []interface{}{"$" + field, nullPlaceholder}}, sortEnum)
+}
+
+// addNullSafeBoolean sorts by a boolean field ranked by nullSafeBooleanSortKey
+func (b *sortBuilder) addNullSafeBoolean(field string, sortEnum generated.SortEnumType) error {
+	return b.addComputed(field, nullSafeBooleanSortKey(field), sortEnum)
+}
+
+// addComputed sorts by an expression stored in a temporary field named after the source field
+func (b *sortBuilder) addComputed(field string, expression bson.M, sortEnum generated.SortEnumType) error {
+	direction, err := sortEnumToInt(sortEnum)
+	if err != nil {
+		return err
+	}
+	if b.computed == nil {
+		b.computed = bson.M{}
+	}
+	key := sortKeyPrefix + "_" + strings.ReplaceAll(field, ".", "_")
+	if _, exists := b.computed[key]; !exists {
+		b.computed[key] = expression
+	}
+	b.appendKey(key, direction)
+	return nil
+}
+
+// appendTiebreakers ends the sort with identifier, ascending unless sorted explicitly, then _id in
+// the same direction. Legacy documents without identifier all tie on it; _id orders them so the
+// legacy cursors, which compare on _id, page through them without skips or repeats
+func (b *sortBuilder) appendTiebreakers() {
+	b.appendKey("identifier", 1)
+	for _, key := range b.keys {
+		if key.Key == "identifier" {
+			b.appendKey("_id", key.Value.(int))
+		}
+	}
+}
+
+// appendKey adds a sort key; a repeated field keeps its first (highest priority) position
+func (b *sortBuilder) appendKey(key string, direction int) {
+	for _, existing := range b.keys {
+		if existing.Key == key {
+			return
+		}
+	}
+	b.keys = append(b.keys, bson.E{Key: key, Value: direction})
+}
+
+// stages returns the sort pipeline stages, defaulting to identifier and _id ascending
+func (b *sortBuilder) stages() []bson.M {
+	if len(b.keys) == 0 {
+		b.appendTiebreakers()
+	}
+
+	pipeline := []bson.M{}
+	if len(b.computed) > 0 {
+		pipeline = append(pipeline, bson.M{"$addFields": b.computed})
+	}
+	pipeline = append(pipeline, bson.M{"$sort": b.keys})
+	if len(b.computed) > 0 {
+		removed := bson.M{}
+		for key := range b.computed {
+			removed[key] = 0
+		}
+		pipeline = append(pipeline, bson.M{"$project": removed}) // Remove temp fields
 	}
 
 	return pipeline
 }
 
+// sortStageFields returns the field names of the $sort stages in priority order
+func sortStageFields(stages []bson.M) []string {
+	fields := []string{}
+	for _, key := range sortStageKeys(stages) {
+		fields = append(fields, key.Key)
+	}
+	return fields
+}
+
+// sortStageKeys returns the keys and directions of the $sort stages in priority order
+// Temporary null-safe sort keys are included; pagination compares on their computed values
+func sortStageKeys(stages []bson.M) bson.D {
+	keys := bson.D{}
+	for _, stage := range stages {
+		if sortKeys, ok := stage["$sort"].(bson.D); ok {
+			keys = append(keys, sortKeys...)
+		}
+	}
+	return keys
+}
+
+// nullSafeBooleanSortKey ranks a boolean field as false=1, true=2, missing/null=3
+// Sorting the rank ascending places missing values last, descending places them first,
+// matching the null rules of addNullSafe (a string placeholder would sort before booleans)
+func nullSafeBooleanSortKey(field string) bson.M {
+	return bson.M{
+		"$switch": bson.M{
+			"branches": []bson.M{
+				{"case": bson.M{"$eq": []interface{}{"$" + field, false}}, "then": 1},
+				{"case": bson.M{"$eq": []interface{}{"$" + field, true}}, "then": 2},
+			},
+			"default": 3,
+		},
+	}
+}
+
+// applyAuthorizationFilter ANDs the entity's authorization filter into the given filter
+// Returns the filter unchanged when the entity has no hook or the hook imposes no restriction
+func applyAuthorizationFilter(ctx context.Context, config EntityConfig, filter bson.M) bson.M {
+	if config.AuthorizationFilter == nil {
+		return filter
+	}
+	authFilter := config.AuthorizationFilter(ctx)
+	if len(authFilter) == 0 {
+		return filter
+	}
+	return bson.M{"$and": []bson.M{filter, authFilter}}
+}
+
 // T014: Structured logging helper exists in logging.go - using that implementation
 
 // T009: Generic getEntity function for single entity retrieval
 // Retrieves a single entity by identifier, excluding deleted entities
-// Returns nil if entity not found or deleted
-func getEntity(ctx context.Context, dbClient interface{}, config EntityConfig, identifier string, result interface{}) error {
+// Returns nil if entity not found or deleted. Results of cached entities are read through the Get cache.
+// Misses read from MongoDB are audited when they hit a deleted entity (see SetAuditDeletedAccess)
+// Errors name the entity type and the resolver (see withQuery). Found entities are recorded in the access audit (see SetAccessAudit)
+func getEntity(ctx context.Context, dbClient interface{}, config EntityConfig, identifier string, result interface{}) (err error) {
+	defer func() {
+		if err == nil {
+			recordAccess(ctx, config, generated.AccessQueryTypeGet, nil, result)
+		}
+		err = withQuery(ctx, err, config)
+	}()
+
 	// Validate UUID format
-	if !isValidUUID(identifier) {
-		return newInvalidInputError("invalid UUID format")
+	if reason := checkUUID(identifier); reason != "" {
+		return newInvalidInputError(reason)
 	}
 
 	// Cast to DBClient interface
-	db, ok := dbClient.(DBClient)
+	client, ok := dbClient.(DBClient)
 	if !ok {
-		return &QueryError{
-			Message: "Database not available",
-			Code:    ErrCodeDatabaseError,
+		return newDatabaseUnavailableError()
+	}
+
+	entity := entityName(config)
+	scope := getCacheScope(ctx, config)
+	cached, generation, hit := entityGetCache.lookup(entity, identifier, scope, queryClock.Now())
+	if hit {
+		recordEntityVersion(ctx, config, identifier, cached.document)
+		if cached.document == nil {
+			return nil
+		}
+		if decodeErr := mongo.NewSingleResultFromDocument(cached.document, nil, db.Registry).Decode(result); decodeErr != nil {
+			return mapMongoError(decodeErr)
 		}
+		return nil
 	}
 
 	// Get collection
-	collection := db.Collection(config.CollectionName)
+	collection := client.ReadCollection(config.CollectionName)
 
 	// Build query filter: match identifier and exclude deleted entities
-	filter := bson.M{
-		"identifier":         identifier,
-		config.DeletionField: bson.M{"$ne": config.DeletionValue},
-	}
+	filter := config.ExcludeDeleted(bson.M{"identifier": identifier})
+	// Out-of-scope entities are indistinguishable from missing ones
+	filter = applyAuthorizationFilter(ctx, config, filter)
 
 	// Execute FindOne query
 	findResult := collection.FindOne(ctx, filter)
 	if findResult.Err() == mongo.ErrNoDocuments {
 		// Entity not found or deleted - return nil (result will have zero values)
+		entityGetCache.store(entity, identifier, scope, nil, generation, queryClock.Now())
+		recordEntityVersion(ctx, config, identifier, nil)
+		auditDeletedLookups(ctx, collection, config, []string{identifier})
 		return nil
 	}
 	if findResult.Err() != nil {
 		return mapMongoError(findResult.Err())
 	}
 
+	// An oversized document answers null like a missing one, and is not cached
+	document, rawErr := findResult.Raw()
+	if rawErr == nil {
+		decode, sizeErr := checkDocumentSize(ctx, config, document)
+		if sizeErr != nil || !decode {
+			return sizeErr
+		}
+	}
 	if decodeErr := findResult.Decode(result); decodeErr != nil {
 		return mapMongoError(decodeErr)
 	}
+	if rawErr == nil {
+		entityGetCache.store(entity, identifier, scope, slices.Clone(document), generation, queryClock.Now())
+		recordEntityVersion(ctx, config, identifier, document)
+	}
 
 	return nil
 }
@@ -214,7 +448,16 @@ func getEntity(ctx context.Context, dbClient interface{}, config EntityConfig, i
 // T010: Generic getEntitiesByKeys function for batch entity retrieval
 // Retrieves multiple entities by identifiers with optional ordering
 // Returns empty array if no identifiers provided or no matches found
-func getEntitiesByKeys(ctx context.Context, dbClient interface{}, config EntityConfig, identifiers []string, sorter interface{}, result interface{}) error {
+// Missing identifiers are audited when they belong to deleted entities (see SetAuditDeletedAccess)
+// Errors name the entity type and the resolver (see withQuery). Found entities are recorded in the access audit (see SetAccessAudit)
+func getEntitiesByKeys(ctx context.Context, dbClient interface{}, config EntityConfig, identifiers []string, sorter interface{}, result interface{}) (err error) {
+	defer func() {
+		if err == nil {
+			recordAccess(ctx, config, generated.AccessQueryTypeByKeys, nil, result)
+		}
+		err = withQuery(ctx, err, config)
+	}()
+
 	// Validate batch size
 	if err := validateBatchSizeGeneric(identifiers); err != nil {
 		return err
@@ -228,246 +471,229 @@ func getEntitiesByKeys(ctx context.Context, dbClient interface{}, config EntityC
 
 	// Validate all UUID formats
 	for _, id := range identifiers {
-		if !isValidUUID(id) {
-			return newInvalidInputError(fmt.Sprintf("invalid UUID format: %s", id))
+		if reason := checkUUID(id); reason != "" {
+			return newInvalidInputError(reason)
 		}
 	}
 
+	// Validate the order array
+	if err := validateSorter(sorter); err != nil {
+		return err
+	}
+
 	// Deduplicate identifiers
 	dedupedIDs := deduplicateIdentifiersGeneric(identifiers)
 
 	// Build base aggregation pipeline
 	pipeline := []bson.M{
-		{"$match": bson.M{
-			"identifier":         bson.M{"$in": dedupedIDs},
-			config.DeletionField: bson.M{"$ne": config.DeletionValue},
-		}},
+		{"$match": applyAuthorizationFilter(ctx, config, config.ExcludeDeleted(bson.M{
+			"identifier": bson.M{"$in": dedupedIDs},
+		}))},
 	}
 
-	// Apply entity-specific sorting if sorter converter exists and sorter is provided
-	if config.SorterConverter != nil && sorter != nil {
-		sortStages := config.SorterConverter(sorter)
-		pipeline = append(pipeline, sortStages...)
-	} else {
-		// Default sorting by identifier ascending
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"identifier": 1}})
+	// Apply the caller's order or the entity's default sort
+	sortStages, err := entitySortStages(config, sorter)
+	if err != nil {
+		return err
 	}
+	pipeline = append(pipeline, sortStages...)
 
 	// Cast to DBClient interface
-	db, ok := dbClient.(DBClient)
+	client, ok := dbClient.(DBClient)
 	if !ok {
-		return &QueryError{
-			Message: "Database not available",
-			Code:    ErrCodeDatabaseError,
-		}
+		return newDatabaseUnavailableError()
 	}
 
 	// Get collection
-	collection := db.Collection(config.CollectionName)
+	collection := client.ReadCollection(config.CollectionName)
 
-	// Execute aggregation pipeline
-	cursor, err := collection.Aggregate(ctx, pipeline)
+	// Execute aggregation pipeline; all identifiers fit in the first batch unless above the bound
+	cursor, err := collection.Aggregate(ctx, pipeline, aggregateOptions(config, len(dedupedIDs)))
 	if err != nil {
-		return &QueryError{
-			Message: "Database query failed",
-			Code:    ErrCodeDatabaseError,
-			Cause:   err,
-		}
+		return NewDatabaseError(err, msgQueryFailed).WithEntity(config.CollectionName)
 	}
-	defer cursor.Close(ctx)
 
-	// Decode all results
-	if err := cursor.All(ctx, result); err != nil {
-		return &QueryError{
-			Message: "Failed to decode entities",
-			Code:    ErrCodeDatabaseError,
-			Cause:   err,
-		}
+	// Decode all results, leaving out oversized documents
+	if err = decodeAllGuarded(ctx, config, cursor, result); err != nil {
+		return err
 	}
 
-	return nil
-}
-
-// T057: Customer sorter converter
-func customerSorterConverter(sorter interface{}) []bson.M {
-	s, ok := sorter.([]*generated.CustomerQuerySorterInput)
-	if !ok || len(s) == 0 {
-		return []bson.M{{"$sort": bson.M{"identifier": 1}}}
+	if auditDeletedAccess {
+		auditDeletedLookups(ctx, collection, config, missedIdentifiers(dedupedIDs, result))
 	}
 
-	sortSpec := s[0]
-	pipeline := []bson.M{}
+	return nil
+}
 
-	// Map each GraphQL sorter field to MongoDB sort stage
-	if sortSpec.FirstName != nil {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"firstName": sortEnumToInt(*sortSpec.FirstName)}})
-	}
+// sorterField maps one sorter input field to a sort key
+// Sorter converters are lists of sorter fields, so the fields they map are known to the coverage check
+type sorterField[T any] struct {
+	Path      string                                 // JSON field name, dotted for nested inputs; also the stored field
+	Direction func(entry *T) *generated.SortEnumType // nil when the entry does not sort by the field
+	Add       func(b *sortBuilder, field string, sortEnum generated.SortEnumType) error
+}
 
-	if sortSpec.LastName != nil {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"lastName": sortEnumToInt(*sortSpec.LastName)}})
-	}
+// sortAsStored, sortNullSafe and sortNullSafeBoolean register a sorter field with the matching sortBuilder method
+func sortAsStored[T any](path string, direction func(entry *T) *generated.SortEnumType) sorterField[T] {
+	return sorterField[T]{Path: path, Direction: direction, Add: (*sortBuilder).add}
+}
 
-	if sortSpec.BirthDate != nil {
-		pipeline = appendNullSafeSorting(pipeline, "birthDate", *sortSpec.BirthDate)
-	}
+func sortNullSafe[T any](path string, direction func(entry *T) *generated.SortEnumType) sorterField[T] {
+	return sorterField[T]{Path: path, Direction: direction, Add: (*sortBuilder).addNullSafe}
+}
 
-	if sortSpec.EmployeeEmail != nil {
-		pipeline = appendNullSafeSorting(pipeline, "employeeEmail", *sortSpec.EmployeeEmail)
-	}
+func sortNullSafeBoolean[T any](path string, direction func(entry *T) *generated.SortEnumType) sorterField[T] {
+	return sorterField[T]{Path: path, Direction: direction, Add: (*sortBuilder).addNullSafeBoolean}
+}
 
-	if sortSpec.Payment != nil && sortSpec.Payment.Status != nil {
-		pipeline = appendNullSafeSorting(pipeline, "payment.status", *sortSpec.Payment.Status)
+// convertSorter maps the sorter entries to a single $sort stage
+// Sorter entries are applied in order; later entries break ties of earlier ones, and identifier
+// then _id break the remaining ties like the pagination filter does, so pages are deterministic.
+// Entries setting a field without a sorter field fail in strict mode and are ignored in lenient mode
+func convertSorter[T any](entries []*T, fields []sorterField[T]) ([]bson.M, error) {
+	if len(entries) == 0 {
+		return (&sortBuilder{}).stages(), nil
 	}
-
-	if sortSpec.CreateDate != nil {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"createDate": sortEnumToInt(*sortSpec.CreateDate)}})
+	if err := checkSupportedSort(entries, sorterFieldPaths(fields)); err != nil {
+		return nil, err
 	}
 
-	// Default to identifier if no fields specified
-	if len(pipeline) == 0 {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"identifier": 1}})
+	builder := &sortBuilder{}
+	for _, entry := range entries {
+		for _, field := range fields {
+			direction := field.Direction(entry)
+			if direction == nil {
+				continue
+			}
+			if err := field.Add(builder, field.Path, *direction); err != nil {
+				return nil, err
+			}
+		}
 	}
+	builder.appendTiebreakers()
 
-	return pipeline
+	return builder.stages(), nil
 }
 
-// T058: Employee sorter converter
-func employeeSorterConverter(sorter interface{}) []bson.M {
-	s, ok := sorter.([]*generated.EmployeeQuerySorterInput)
-	if !ok || len(s) == 0 {
-		return []bson.M{{"$sort": bson.M{"identifier": 1}}}
+// sorterFieldPaths lists the sorter input fields mapped by the sorter fields
+func sorterFieldPaths[T any](fields []sorterField[T]) []string {
+	paths := make([]string, 0, len(fields))
+	for _, field := range fields {
+		paths = append(paths, field.Path)
 	}
+	return paths
+}
 
-	sortSpec := s[0]
-	pipeline := []bson.M{}
-
-	if sortSpec.FirstName != nil {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"firstName": sortEnumToInt(*sortSpec.FirstName)}})
-	}
+// customerSorterFields maps the CustomerQuerySorterInput fields
+// Payment data is optional, so all payment fields sort null-safely
+func customerSorterFields() []sorterField[generated.CustomerQuerySorterInput] {
+	type input = generated.CustomerQuerySorterInput
+	type paymentInput = generated.CustomerPaymentObjectSorterInput
 
-	if sortSpec.LastName != nil {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"lastName": sortEnumToInt(*sortSpec.LastName)}})
+	payment := func(direction func(p *paymentInput) *generated.SortEnumType) func(entry *input) *generated.SortEnumType {
+		return func(entry *input) *generated.SortEnumType {
+			if entry.Payment == nil {
+				return nil
+			}
+			return direction(entry.Payment)
+		}
 	}
 
-	if sortSpec.BirthDate != nil {
-		pipeline = appendNullSafeSorting(pipeline, "birthDate", *sortSpec.BirthDate)
+	return []sorterField[input]{
+		sortAsStored("firstName", func(s *input) *generated.SortEnumType { return s.FirstName }),
+		sortAsStored("lastName", func(s *input) *generated.SortEnumType { return s.LastName }),
+		sortNullSafe("birthDate", func(s *input) *generated.SortEnumType { return s.BirthDate }),
+		sortNullSafe("employeeEmail", func(s *input) *generated.SortEnumType { return s.EmployeeEmail }),
+		sortNullSafe("payment.status", payment(func(p *paymentInput) *generated.SortEnumType { return p.Status })),
+		sortNullSafe("payment.paidAt", payment(func(p *paymentInput) *generated.SortEnumType { return p.PaidAt })),
+		sortNullSafe("payment.expiresAt", payment(func(p *paymentInput) *generated.SortEnumType { return p.ExpiresAt })),
+		sortNullSafe("payment.subscriptionTier", payment(func(p *paymentInput) *generated.SortEnumType { return p.SubscriptionTier })),
+		sortNullSafe("payment.billingPeriod", payment(func(p *paymentInput) *generated.SortEnumType { return p.BillingPeriod })),
+		sortNullSafeBoolean("payment.promoteToLifetime", payment(func(p *paymentInput) *generated.SortEnumType { return p.PromoteToLifetime })),
+		sortNullSafeBoolean("payment.isCancelableDuringFirstYear", payment(func(p *paymentInput) *generated.SortEnumType { return p.IsCancelableDuringFirstYear })),
+		sortAsStored("createDate", func(s *input) *generated.SortEnumType { return s.CreateDate }),
 	}
+}
 
-	if sortSpec.UserEmail != nil {
-		pipeline = appendNullSafeSorting(pipeline, "userEmail", *sortSpec.UserEmail)
-	}
+// T057: Customer sorter converter
+func customerSorterConverter(sorter interface{}) ([]bson.M, error) {
+	s, _ := sorter.([]*generated.CustomerQuerySorterInput)
+	return convertSorter(s, customerSorterFields())
+}
 
-	// Default to identifier if no fields specified
-	if len(pipeline) == 0 {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"identifier": 1}})
+// employeeSorterFields maps the EmployeeQuerySorterInput fields
+func employeeSorterFields() []sorterField[generated.EmployeeQuerySorterInput] {
+	type input = generated.EmployeeQuerySorterInput
+	return []sorterField[input]{
+		sortAsStored("firstName", func(s *input) *generated.SortEnumType { return s.FirstName }),
+		sortAsStored("lastName", func(s *input) *generated.SortEnumType { return s.LastName }),
+		sortNullSafe("birthDate", func(s *input) *generated.SortEnumType { return s.BirthDate }),
+		sortNullSafe("userEmail", func(s *input) *generated.SortEnumType { return s.UserEmail }),
 	}
+}
 
-	return pipeline
+// T058: Employee sorter converter
+func employeeSorterConverter(sorter interface{}) ([]bson.M, error) {
+	s, _ := sorter.([]*generated.EmployeeQuerySorterInput)
+	return convertSorter(s, employeeSorterFields())
 }
 
-// T059: Inventory sorter converter
-func inventorySorterConverter(sorter interface{}) []bson.M {
-	s, ok := sorter.([]*generated.InventoryQuerySorterInput)
-	if !ok || len(s) == 0 {
-		return []bson.M{{"$sort": bson.M{"identifier": 1}}}
+// inventorySorterFields maps the InventoryQuerySorterInput fields
+func inventorySorterFields() []sorterField[generated.InventoryQuerySorterInput] {
+	type input = generated.InventoryQuerySorterInput
+	return []sorterField[input]{
+		sortNullSafe("customerId", func(s *input) *generated.SortEnumType { return s.CustomerID }),
 	}
+}
 
-	sortSpec := s[0]
-	pipeline := []bson.M{}
-
-	if sortSpec.CustomerID != nil {
-		pipeline = appendNullSafeSorting(pipeline, "customerId", *sortSpec.CustomerID)
-	}
+// T059: Inventory sorter converter
+func inventorySorterConverter(sorter interface{}) ([]bson.M, error) {
+	s, _ := sorter.([]*generated.InventoryQuerySorterInput)
+	return convertSorter(s, inventorySorterFields())
+}
 
-	// Default to identifier if no fields specified
-	if len(pipeline) == 0 {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"identifier": 1}})
+// teamSorterFields maps the TeamQuerySorterInput fields
+// Boolean isShared sorts use a null-safe rank so teams missing the field follow the null rules
+func teamSorterFields() []sorterField[generated.TeamQuerySorterInput] {
+	type input = generated.TeamQuerySorterInput
+	return []sorterField[input]{
+		sortAsStored("name", func(s *input) *generated.SortEnumType { return s.Name }),
+		sortAsStored("description", func(s *input) *generated.SortEnumType { return s.Description }),
+		sortNullSafeBoolean("isShared", func(s *input) *generated.SortEnumType { return s.IsShared }),
+		sortAsStored("employeeId", func(s *input) *generated.SortEnumType { return s.EmployeeID }),
 	}
-
-	return pipeline
 }
 
 // T041: Team sorter converter
-func teamSorterConverter(sorter interface{}) []bson.M {
-	s, ok := sorter.([]*generated.TeamQuerySorterInput)
-	if !ok || len(s) == 0 {
-		return []bson.M{{"$sort": bson.M{"identifier": 1}}}
-	}
-
-	// Build a single $sort document with all fields
-	sortDoc := bson.M{}
-
-	// Process all sorter inputs in order
-	for _, sortSpec := range s {
-		if sortSpec.Name != nil {
-			sortDoc["name"] = sortEnumToInt(*sortSpec.Name)
-		}
-
-		if sortSpec.Description != nil {
-			sortDoc["description"] = sortEnumToInt(*sortSpec.Description)
-		}
-
-		if sortSpec.IsShared != nil {
-			sortDoc["isShared"] = sortEnumToInt(*sortSpec.IsShared)
-		}
-
-		if sortSpec.EmployeeID != nil {
-			sortDoc["employeeId"] = sortEnumToInt(*sortSpec.EmployeeID)
-		}
-	}
+func teamSorterConverter(sorter interface{}) ([]bson.M, error) {
+	s, _ := sorter.([]*generated.TeamQuerySorterInput)
+	return convertSorter(s, teamSorterFields())
+}
 
-	// Default to identifier if no fields specified
-	if len(sortDoc) == 0 {
-		sortDoc["identifier"] = 1
+// executionPlanSorterFields maps the ExecutionPlanQuerySorterInput fields
+func executionPlanSorterFields() []sorterField[generated.ExecutionPlanQuerySorterInput] {
+	type input = generated.ExecutionPlanQuerySorterInput
+	return []sorterField[input]{
+		sortNullSafe("customerId", func(s *input) *generated.SortEnumType { return s.CustomerID }),
 	}
-
-	return []bson.M{{"$sort": sortDoc}}
 }
 
 // T042: ExecutionPlan sorter converter
-func executionPlanSorterConverter(sorter interface{}) []bson.M {
-	s, ok := sorter.([]*generated.ExecutionPlanQuerySorterInput)
-	if !ok || len(s) == 0 {
-		return []bson.M{{"$sort": bson.M{"identifier": 1}}}
-	}
-
-	pipeline := []bson.M{}
-
-	// Process all sorter inputs in order
-	for _, sortSpec := range s {
-		if sortSpec.CustomerID != nil {
-			pipeline = appendNullSafeSorting(pipeline, "customerId", *sortSpec.CustomerID)
-		}
-	}
+func executionPlanSorterConverter(sorter interface{}) ([]bson.M, error) {
+	s, _ := sorter.([]*generated.ExecutionPlanQuerySorterInput)
+	return convertSorter(s, executionPlanSorterFields())
+}
 
-	// Default to identifier if no fields specified
-	if len(pipeline) == 0 {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"identifier": 1}})
+// referencePortfolioSorterFields maps the ReferencePortfolioQuerySorterInput fields
+func referencePortfolioSorterFields() []sorterField[generated.ReferencePortfolioQuerySorterInput] {
+	type input = generated.ReferencePortfolioQuerySorterInput
+	return []sorterField[input]{
+		sortNullSafe("customerId", func(s *input) *generated.SortEnumType { return s.CustomerID }),
 	}
-
-	return pipeline
 }
 
 // T043: ReferencePortfolio sorter converter
-func referencePortfolioSorterConverter(sorter interface{}) []bson.M {
-	s, ok := sorter.([]*generated.ReferencePortfolioQuerySorterInput)
-	if !ok || len(s) == 0 {
-		return []bson.M{{"$sort": bson.M{"identifier": 1}}}
-	}
-
-	pipeline := []bson.M{}
-
-	// Process all sorter inputs in order
-	for _, sortSpec := range s {
-		if sortSpec.CustomerID != nil {
-			pipeline = appendNullSafeSorting(pipeline, "customerId", *sortSpec.CustomerID)
-		}
-	}
-
-	// Default to identifier if no fields specified
-	if len(pipeline) == 0 {
-		pipeline = append(pipeline, bson.M{"$sort": bson.M{"identifier": 1}})
-	}
-
-	return pipeline
+func referencePortfolioSorterConverter(sorter interface{}) ([]bson.M, error) {
+	s, _ := sorter.([]*generated.ReferencePortfolioQuerySorterInput)
+	return convertSorter(s, referencePortfolioSorterFields())
 }
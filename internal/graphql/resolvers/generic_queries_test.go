@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+func TestMissingIdentifiers(t *testing.T) {
+	identifier := func(customer *generated.Customer) string { return customer.Identifier }
+	found := []*generated.Customer{{Identifier: "b"}, {Identifier: "d"}}
+
+	tests := []struct {
+		name      string
+		requested []string
+		found     []*generated.Customer
+		want      []string
+	}{
+		{name: "mixed with duplicates", requested: []string{"c", "b", "a", "c", "d", "a"}, found: found, want: []string{"c", "a"}},
+		{name: "all found", requested: []string{"d", "b", "b"}, found: found, want: []string{}},
+		{name: "none found", requested: []string{"a"}, found: nil, want: []string{"a"}},
+		{name: "nothing requested", requested: nil, found: nil, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, missingIdentifiers(tt.requested, tt.found, identifier))
+		})
+	}
+}
+
+func TestByKeysPaging(t *testing.T) {
+	paging := byKeysPaging()
+	assert.False(t, paging.HasNextPage)
+	assert.False(t, paging.HasPreviousPage)
+	assert.Nil(t, paging.StartCursor)
+	assert.Nil(t, paging.EndCursor)
+}
+
+func TestGetConnectionData(t *testing.T) {
+	customer := &generated.Customer{Identifier: "id-1"}
+	assert.Equal(t, []*generated.Customer{customer}, getConnectionData(customer))
+
+	data := getConnectionData[generated.Customer](nil)
+	assert.NotNil(t, data, "data is non-null in the schema")
+	assert.Empty(t, data)
+}
@@ -2,93 +2,163 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
 )
 
 // T006: Generic searchEntities function for entity search with filtering, sorting, and pagination
 // T009: Validation helpers for pagination parameters
 
-// validatePaginationParams validates first/last pagination parameters
-// Returns error if both first and last are specified, or if limits exceed MaxBatchSize
-func validatePaginationParams(first, last *int) error {
-	// Cannot specify both forward and backward pagination
-	if first != nil && last != nil {
-		return newInvalidInputError("cannot specify both 'first' and 'last' pagination parameters")
+// Errors of pagination arguments mixing the forward (first, after) and backward (last, before) directions
+const (
+	msgFirstAndLast    = "cannot specify both 'first' and 'last' pagination parameters"
+	msgAfterAndBefore  = "cannot specify both 'after' and 'before' cursors"
+	msgAfterWithLast   = "'after' pages forward and cannot be combined with 'last'; use 'first'"
+	msgBeforeWithFirst = "'before' pages backward and cannot be combined with 'first'; use 'last'"
+)
+
+// validatePaginationParams validates the pagination arguments of a search
+// A search pages forward with first and after, or backward with last and before; arguments of
+// both directions are rejected, as are limits exceeding the maximum page size. Empty cursors count as absent
+func validatePaginationParams(paging SearchPaging) error {
+	first, last := paging.First, paging.Last
+	hasAfter := paging.After != nil && *paging.After != ""
+	hasBefore := paging.Before != nil && *paging.Before != ""
+
+	switch {
+	case first != nil && last != nil:
+		return newInvalidInputError(msgFirstAndLast)
+	case hasAfter && hasBefore:
+		return newInvalidInputError(msgAfterAndBefore)
+	case hasAfter && last != nil:
+		return newInvalidInputError(msgAfterWithLast)
+	case hasBefore && first != nil:
+		return newInvalidInputError(msgBeforeWithFirst)
 	}
 
-	// Validate first parameter
 	if first != nil {
-		if *first < 0 {
-			return newInvalidInputError("'first' must be non-negative")
+		if err := checkPageSize("first", int64(*first)); err != nil {
+			return err
 		}
-		if *first > MaxBatchSize {
-			return newInvalidInputError(fmt.Sprintf("'first' exceeds maximum batch size: requested %d, maximum %d", *first, MaxBatchSize))
+	}
+	if last != nil {
+		if err := checkPageSize("last", int64(*last)); err != nil {
+			return err
 		}
 	}
 
-	// Validate last parameter
-	if last != nil {
-		if *last < 0 {
-			return newInvalidInputError("'last' must be non-negative")
+	return nil
+}
+
+// checkPageSize validates a first or last value against the maximum page size
+func checkPageSize(name string, value int64) error {
+	if value < 0 {
+		return NewInvalidInput(fmt.Sprintf("'%s' must be non-negative", name), name)
+	}
+	if value > int64(limits.MaxPageSize) {
+		return NewInvalidInput(fmt.Sprintf("'%s' exceeds maximum batch size: requested %d, maximum %d", name, value, limits.MaxPageSize), name)
+	}
+	return nil
+}
+
+// pageSizeArgs converts the Long first/last arguments of a search resolver to the ints the
+// search functions take. Values are range-checked as int64 first, so absurd values are rejected
+// instead of wrapping where int is narrower than int64
+func pageSizeArgs(first, last *int64) (firstInt, lastInt *int, err error) {
+	convert := func(name string, value *int64) (*int, error) {
+		if value == nil {
+			return nil, nil
 		}
-		if *last > MaxBatchSize {
-			return newInvalidInputError(fmt.Sprintf("'last' exceeds maximum batch size: requested %d, maximum %d", *last, MaxBatchSize))
+		if err := checkPageSize(name, *value); err != nil {
+			return nil, err
 		}
+		converted := int(*value)
+		return &converted, nil
 	}
 
-	return nil
+	if first != nil && last != nil {
+		return nil, nil, newInvalidInputError(msgFirstAndLast)
+	}
+	if firstInt, err = convert("first", first); err != nil {
+		return nil, nil, err
+	}
+	if lastInt, err = convert("last", last); err != nil {
+		return nil, nil, err
+	}
+	return firstInt, lastInt, nil
 }
 
 // buildPaginationFilter builds a MongoDB filter for cursor-based pagination
 // The filter ensures we only get documents after/before the cursor position
-// Based on sort fields and identifier in the cursor
-func buildPaginationFilter(cursor *Cursor, sortFields []string, isForward bool) bson.M {
+// Based on sort keys and identifier in the cursor; descending keys compare in reverse
+// Pages continue strictly past the cursor's (sort values, identifier), so a pagination never
+// returns an identifier twice even when the cursor's own document was deleted since, or
+// documents sharing its sort values were deleted or created
+func buildPaginationFilter(cursor *Cursor, sortKeys bson.D, isForward bool) bson.M {
 	if cursor == nil {
 		return bson.M{}
 	}
 
-	// Determine comparison operator based on direction
-	gtOp := "$gt"
-	if !isForward {
-		gtOp = "$lt"
+	// comparison returns the operator selecting documents past the cursor for a sort direction
+	comparison := func(direction interface{}) string {
+		descending := direction == -1
+		if isForward != descending {
+			return "$gt"
+		}
+		return "$lt"
 	}
 
-	// Special case: if only sorting by identifier (default), just filter by identifier
+	// Identifier breaks ties in its sort direction, ascending unless sorted explicitly
+	var identifierDirection interface{} = 1
+	for _, key := range sortKeys {
+		if key.Key == "identifier" {
+			identifierDirection = key.Value
+		}
+	}
+	identifierOp := comparison(identifierDirection)
+
+	// Special case: if only sorting by identifier, just filter by identifier
 	if len(cursor.SortFields) == 0 && cursor.Identifier != "" {
-		return bson.M{"identifier": bson.M{gtOp: cursor.Identifier}}
+		return identifierCondition(cursor, identifierOp)
 	}
 
 	// Build $or conditions for pagination
 	// For cursor at position [value1, value2, identifier]:
 	// Forward (after): field1 > value1 OR (field1 = value1 AND field2 > value2) OR (field1 = value1 AND field2 = value2 AND identifier > cursorId)
-	// Backward (before): Similar but with < operators
+	// Backward (before): Similar but with < operators; descending fields swap the operators
 
 	orConditions := []bson.M{}
 
-	// Build cascading OR conditions for sort fields (excluding identifier)
-	nonIdentifierFields := []string{}
-	for _, field := range sortFields {
-		if field != "identifier" {
-			nonIdentifierFields = append(nonIdentifierFields, field)
+	// Build cascading OR conditions for sort fields (excluding the identifier and _id tiebreakers)
+	nonIdentifierKeys := bson.D{}
+	for _, key := range sortKeys {
+		if !isTiebreakerKey(key.Key) {
+			nonIdentifierKeys = append(nonIdentifierKeys, key)
 		}
 	}
 
-	for i := 0; i < len(nonIdentifierFields); i++ {
+	for i := 0; i < len(nonIdentifierKeys); i++ {
 		condition := bson.M{}
 
 		// All previous fields must equal cursor values
 		for j := 0; j < i; j++ {
 			if j < len(cursor.SortFields) {
-				condition[nonIdentifierFields[j]] = cursor.SortFields[j]
+				condition[nonIdentifierKeys[j].Key] = cursor.SortFields[j]
 			}
 		}
 
-		// Current field must be greater/less than cursor value
+		// Current field must be past the cursor value
 		if i < len(cursor.SortFields) {
-			condition[nonIdentifierFields[i]] = bson.M{gtOp: cursor.SortFields[i]}
+			condition[nonIdentifierKeys[i].Key] = bson.M{comparison(nonIdentifierKeys[i].Value): cursor.SortFields[i]}
 		}
 
 		orConditions = append(orConditions, condition)
@@ -96,10 +166,12 @@ func buildPaginationFilter(cursor *Cursor, sortFields []string, isForward bool)
 
 	// Final condition: all sort fields equal, identifier greater/less than cursor identifier
 	finalCondition := bson.M{}
-	for i := 0; i < len(cursor.SortFields) && i < len(nonIdentifierFields); i++ {
-		finalCondition[nonIdentifierFields[i]] = cursor.SortFields[i]
+	for i := 0; i < len(cursor.SortFields) && i < len(nonIdentifierKeys); i++ {
+		finalCondition[nonIdentifierKeys[i].Key] = cursor.SortFields[i]
+	}
+	for key, value := range identifierCondition(cursor, identifierOp) {
+		finalCondition[key] = value
 	}
-	finalCondition["identifier"] = bson.M{gtOp: cursor.Identifier}
 	orConditions = append(orConditions, finalCondition)
 
 	if len(orConditions) == 0 {
@@ -109,52 +181,42 @@ func buildPaginationFilter(cursor *Cursor, sortFields []string, isForward bool)
 	return bson.M{"$or": orConditions}
 }
 
-// searchEntities performs generic entity search with filtering, sorting, and pagination
-// Returns count, data array, totalCount, and pagination info
-func searchEntities(
-	ctx context.Context,
-	dbClient interface{},
-	config EntityConfig,
-	filter interface{}, // Entity-specific filter (converted to bson.M by FilterConverter)
-	sorter interface{}, // Entity-specific sorter (converted to pipeline stages by SorterConverter)
-	first *int, after *string, last *int, before *string, // Pagination parameters
-	result interface{}, // Pointer to slice of entity type (will be populated with decoded results)
-) (count int, totalCount int, hasNextPage bool, hasPreviousPage bool, startCursor *string, endCursor *string, err error) {
-	// Validate pagination parameters
-	if err := validatePaginationParams(first, last); err != nil {
-		return 0, 0, false, false, nil, nil, err
-	}
-
-	// Determine effective limit
-	effectiveLimit := MaxBatchSize
-	if first != nil && *first > 0 {
-		effectiveLimit = *first
-	} else if last != nil && *last > 0 {
-		effectiveLimit = *last
-	}
-
-	// Decode cursors if provided
-	var afterCursor *Cursor
-	var beforeCursor *Cursor
+// isTiebreakerKey reports whether a sort key is the identifier or _id tiebreaker, which cursors
+// carry as their identifier rather than as a sort value
+func isTiebreakerKey(key string) bool {
+	return key == "identifier" || key == "_id"
+}
 
-	if after != nil && *after != "" {
-		afterCursor, err = decodeCursor(*after)
-		if err != nil {
-			return 0, 0, false, false, nil, nil, err
-		}
-	}
+// identifierCondition selects the documents past the cursor's tiebreaker. Legacy documents without
+// identifier sort before every identifier and in _id order among themselves, so comparisons
+// towards smaller identifiers select them too, and a legacy cursor compares on _id
+func identifierCondition(cursor *Cursor, op string) bson.M {
+	legacyID, _ := cursor.legacyID() // Checked when the cursor was decoded
+	switch {
+	case cursor.Legacy && op == "$gt":
+		return bson.M{"$or": []bson.M{
+			{"identifier": nil, "_id": bson.M{"$gt": legacyID}},
+			{"identifier": bson.M{"$type": "string"}},
+		}}
+	case cursor.Legacy:
+		return bson.M{"identifier": nil, "_id": bson.M{"$lt": legacyID}}
+	case op == "$lt":
+		return bson.M{"$or": []bson.M{
+			{"identifier": bson.M{"$lt": cursor.Identifier}},
+			{"identifier": nil},
+		}}
+	}
+	return bson.M{"identifier": bson.M{op: cursor.Identifier}}
+}
 
-	if before != nil && *before != "" {
-		beforeCursor, err = decodeCursor(*before)
-		if err != nil {
-			return 0, 0, false, false, nil, nil, err
-		}
-	}
+// buildBaseFilter combines the deletion exclusion, the entity filter and the caller's authorization scope
+func buildBaseFilter(ctx context.Context, config EntityConfig, filter interface{}) bson.M {
+	return applyAuthorizationFilter(ctx, config, buildEntityFilter(config, filter))
+}
 
-	// Build base filter (deletion exclusion + entity filter)
-	baseFilter := bson.M{
-		config.DeletionField: bson.M{"$ne": config.DeletionValue},
-	}
+// buildEntityFilter combines the deletion exclusion and the entity filter
+func buildEntityFilter(config EntityConfig, filter interface{}) bson.M {
+	baseFilter := config.ExcludeDeleted(bson.M{})
 
 	// Apply entity-specific filter if FilterConverter exists and filter is provided
 	if config.FilterConverter != nil && filter != nil {
@@ -163,88 +225,169 @@ func searchEntities(
 			// Combine deletion filter with entity filter using $and
 			baseFilter = bson.M{
 				"$and": []bson.M{
-					{config.DeletionField: bson.M{"$ne": config.DeletionValue}},
+					config.ExcludeDeleted(bson.M{}),
 					entityFilter,
 				},
 			}
 		}
 	}
 
-	// Build aggregation pipeline
-	pipeline := []bson.M{
-		{"$match": baseFilter},
-	}
+	return baseFilter
+}
 
-	// Apply sorting
-	var sortStages []bson.M
-	if config.SorterConverter != nil && sorter != nil {
-		sortStages = config.SorterConverter(sorter)
-	} else {
-		// Default sorting by identifier ascending
-		sortStages = []bson.M{{"$sort": bson.M{"identifier": 1}}}
-	}
-
-	// For pagination filter, we need to know the sort field names
-	// Extract from sort stages
-	var sortFieldNames []string
-	if len(sortStages) > 0 {
-		for _, stage := range sortStages {
-			if sortSpec, ok := stage["$sort"].(bson.M); ok {
-				for fieldName := range sortSpec {
-					if fieldName != "_sortKey" { // Skip temporary sort keys
-						sortFieldNames = append(sortFieldNames, fieldName)
-					}
+// filterMatchesNothing reports whether a MongoDB filter can never match a document,
+// which is the case when it requires a field to be in an empty $in list
+func filterMatchesNothing(filter bson.M) bool {
+	for key, value := range filter {
+		switch key {
+		case "$and":
+			conditions, _ := value.([]bson.M)
+			for _, condition := range conditions {
+				if filterMatchesNothing(condition) {
+					return true
+				}
+			}
+		case "$or":
+			conditions, _ := value.([]bson.M)
+			if len(conditions) == 0 {
+				continue
+			}
+			matchesNothing := true
+			for _, condition := range conditions {
+				if !filterMatchesNothing(condition) {
+					matchesNothing = false
+					break
+				}
+			}
+			if matchesNothing {
+				return true
+			}
+		default:
+			operators, ok := value.(bson.M)
+			if !ok {
+				continue
+			}
+			if in, ok := operators["$in"]; ok {
+				list := reflect.ValueOf(in)
+				if list.Kind() == reflect.Slice && list.Len() == 0 {
+					return true
 				}
 			}
 		}
 	}
+	return false
+}
+
+// searchEntities performs generic entity search with filtering, sorting, and pagination
+// With paging.Snapshot set, every page reads at the cluster time recorded in the first page's cursors.
+// Without options.IncludeHeavyFields, the entity's heavy fields are not read and return null
+// Facets requested in the context (see withSearchFacets) are counted in the same aggregation
+// Returns count, data array, totalCount, and pagination info; errors name the entity type and
+// the resolver (see withQuery). Returned entities are recorded in the access audit (see SetAccessAudit)
+func searchEntities(
+	ctx context.Context,
+	dbClient interface{},
+	config EntityConfig,
+	filter interface{}, // Entity-specific filter (converted to bson.M by FilterConverter)
+	sorter interface{}, // Entity-specific sorter (converted to pipeline stages by SorterConverter)
+	paging SearchPaging, // Page size, cursors and snapshot mode
+	options SearchOptions,
+	result interface{}, // Pointer to slice of entity type (will be populated with decoded results)
+) (count int, totalCount int, hasNextPage bool, hasPreviousPage bool, startCursor *string, endCursor *string, err error) {
+	defer func() {
+		if err == nil {
+			recordAccess(ctx, config, generated.AccessQueryTypeSearch, filter, result)
+		}
+		err = withQuery(ctx, err, config)
+	}()
+
+	// Cap oversized first/last in compatibility mode, then validate the search
+	paging.First, paging.Last = capPageSize(ctx, "first", paging.First), capPageSize(ctx, "last", paging.Last)
+	plan, err := planSearch(config, filter, sorter, paging)
+	if err != nil {
+		return 0, 0, false, false, nil, nil, err
+	}
+	effectiveLimit := plan.limit
+	afterCursor, beforeCursor := plan.after, plan.before
+
+	// Restrict to entities the caller is authorized to see
+	plan.match = applyAuthorizationFilter(ctx, config, plan.match)
+	facets := searchFacetsFrom(ctx)
+	plan.facets = facets.branches()
+
+	// A filter that can never match (e.g. in: []) returns an empty page without querying.
+	// Explain mode still runs so the plan can be inspected
+	if filterMatchesNothing(plan.match) && explainRecorderFrom(ctx) == nil {
+		return 0, 0, false, false, nil, nil, nil
+	}
 
-	// Use $facet to get both count and paginated data in a single query
-	facetPipeline := bson.M{
-		"$facet": bson.M{
-			"metadata": []bson.M{
-				{"$count": "totalCount"},
-			},
-			"data": buildDataPipeline(sortStages, afterCursor, beforeCursor, sortFieldNames, first, last, effectiveLimit),
-		},
+	// Later snapshot pages read at the cluster time their cursor carries
+	var snapshotAt *cursorSnapshot
+	if paging.Snapshot {
+		if afterCursor != nil {
+			snapshotAt = afterCursor.Snapshot
+		} else if beforeCursor != nil {
+			snapshotAt = beforeCursor.Snapshot
+		}
 	}
 
-	pipeline = append(pipeline, facetPipeline)
+	// Use $facet to get both count and paginated data in a single query.
+	// The data branch keeps only the selected fields so large documents fit its 16MB result;
+	// selected heavy fields are left out unless asked for
+	var omitted []string
+	if !options.IncludeHeavyFields {
+		omitted = selectedHeavyFields(ctx, config)
+	}
+	projection := searchDataProjection(ctx, config, plan.sortKeys, omitted)
 
 	// Execute aggregation
 	db, ok := dbClient.(DBClient)
 	if !ok {
-		return 0, 0, false, false, nil, nil, &QueryError{
-			Message: "Database not available",
-			Code:    ErrCodeDatabaseError,
-		}
+		return 0, 0, false, false, nil, nil, newDatabaseUnavailableError()
 	}
 
-	collection := db.Collection(config.CollectionName)
-	cursor, err := collection.Aggregate(ctx, pipeline)
+	// Heavy searches queue here so they cannot starve point reads of pool connections
+	release, err := currentSearchLimiter().acquire(ctx, effectiveLimit)
 	if err != nil {
-		return 0, 0, false, false, nil, nil, &QueryError{
-			Message: "Database query failed",
-			Code:    ErrCodeDatabaseError,
-			Cause:   err,
-		}
+		return 0, 0, false, false, nil, nil, err
 	}
-	defer cursor.Close(ctx)
+	defer release()
 
-	// Parse facet results
-	var facetResults []struct {
-		Metadata []struct {
-			TotalCount int `bson:"totalCount"`
-		} `bson:"metadata"`
-		Data []bson.Raw `bson:"data"` // Use bson.Raw for flexible decoding
+	collection := db.ReadCollection(config.CollectionName)
+
+	// Explain mode reports the execution plan in the response extensions instead of data
+	if recorder := explainRecorderFrom(ctx); recorder != nil {
+		return 0, 0, false, false, nil, nil, explainSearch(ctx, collection, plan.pipeline(effectiveLimit, projection), recorder)
 	}
 
-	if err := cursor.All(ctx, &facetResults); err != nil {
-		return 0, 0, false, false, nil, nil, &QueryError{
-			Message: "Failed to decode search results",
-			Code:    ErrCodeDatabaseError,
-			Cause:   err,
+	// A page too large for one result document is retried with half the limit
+	requestedLimit := effectiveLimit
+	var facetResults []searchFacetResult
+	for {
+		var readAt *cursorSnapshot
+		if paging.Snapshot {
+			facetResults, readAt, err = aggregateSearchSnapshot(ctx, collection, plan.pipeline(effectiveLimit, projection), snapshotAt)
+		} else {
+			// The batch covers the page and the document read ahead for hasNextPage
+			facetResults, err = aggregateSearch(ctx, collection, plan.pipeline(effectiveLimit, projection), aggregateOptions(config, effectiveLimit+1))
+		}
+		if err == nil {
+			if paging.Snapshot {
+				snapshotAt = readAt
+			}
+			break
 		}
+		reduced, ok := truncatedPageSize(effectiveLimit)
+		if !isDocumentTooLarge(err) || !ok {
+			return 0, 0, false, false, nil, nil, withEntity(err, config.CollectionName)
+		}
+		effectiveLimit = reduced
+	}
+	if effectiveLimit < requestedLimit {
+		recordSearchTruncation(ctx, config, requestedLimit, effectiveLimit)
+	}
+	if len(omitted) > 0 {
+		recordOmittedHeavyFields(ctx, omitted)
 	}
 
 	// Handle empty results
@@ -253,23 +396,35 @@ func searchEntities(
 	}
 
 	facetResult := facetResults[0]
+	facets.decode(facetResult)
 
 	// Get totalCount
 	if len(facetResult.Metadata) > 0 {
 		totalCount = facetResult.Metadata[0].TotalCount
 	}
 
+	// totalCount is counted again for every page; the cursors carry the first page's along
+	startTotal := totalCount
+	if afterCursor != nil {
+		startTotal = afterCursor.StartTotal
+	} else if beforeCursor != nil {
+		startTotal = beforeCursor.StartTotal
+	}
+
 	// Decode data into result slice
 	dataCount := len(facetResult.Data)
 
-	// Handle empty data
+	// Determine if we have extra items for pagination detection
+	isForward := plan.isForward()
+
+	// An empty page past a cursor still has the documents on the cursor's side
 	if dataCount == 0 {
-		return 0, totalCount, false, false, nil, nil, nil
+		if isForward {
+			return 0, totalCount, false, afterCursor != nil, nil, nil, nil
+		}
+		return 0, totalCount, beforeCursor != nil, false, nil, nil, nil
 	}
 
-	// Determine if we have extra items for pagination detection
-	isForward := first != nil || (first == nil && last == nil)
-
 	if isForward {
 		// Forward pagination: check if we got limit+1 items
 		if dataCount > effectiveLimit {
@@ -280,46 +435,57 @@ func searchEntities(
 		}
 		hasPreviousPage = afterCursor != nil
 	} else {
-		// Backward pagination: check if we got limit+1 items
+		// Backward pagination: the data was queried in reverse, nearest the end first.
+		// limit+1 items means more precede the page; drop the extra and restore sort order
 		if dataCount > effectiveLimit {
 			hasPreviousPage = true
-			// Trim first item (we queried in reverse)
-			facetResult.Data = facetResult.Data[1:]
+			facetResult.Data = facetResult.Data[:effectiveLimit]
 			dataCount = effectiveLimit
 		}
+		for i, j := 0, dataCount-1; i < j; i, j = i+1, j-1 {
+			facetResult.Data[i], facetResult.Data[j] = facetResult.Data[j], facetResult.Data[i]
+		}
 		hasNextPage = beforeCursor != nil
 	}
 
+	// Leave out oversized documents; the cursors are taken from the documents returned
+	guarded := facetResult.Data[:0]
+	for _, raw := range facetResult.Data {
+		decode, sizeErr := checkDocumentSize(ctx, config, raw)
+		if sizeErr != nil {
+			return 0, 0, false, false, nil, nil, sizeErr
+		}
+		if decode {
+			guarded = append(guarded, raw)
+		}
+	}
+	facetResult.Data = guarded
+	dataCount = len(guarded)
+	if dataCount == 0 {
+		return 0, totalCount, hasNextPage, hasPreviousPage, nil, nil, nil
+	}
+
 	// Decode trimmed data into result
 	// We need to decode each bson.Raw into a bson.M for cursor generation
 	// AND populate the result slice
 	tempArray := make([]bson.M, len(facetResult.Data))
 	for i, raw := range facetResult.Data {
 		if err := bson.Unmarshal(raw, &tempArray[i]); err != nil {
-			return 0, 0, false, false, nil, nil, &QueryError{
-				Message: "Failed to decode entity data",
-				Code:    ErrCodeDatabaseError,
-				Cause:   err,
-			}
+			return 0, 0, false, false, nil, nil, NewDatabaseError(err, msgDecodeFailed).WithEntity(config.CollectionName)
 		}
 	}
+	logMissingIdentifiers(ctx, config, tempArray)
 
 	// Now decode the bson.Raw array into the result slice using reflection
 	// The result parameter is a pointer to a slice (e.g., *[]*Customer)
 	resultValue := reflect.ValueOf(result)
 	if resultValue.Kind() != reflect.Ptr {
-		return 0, 0, false, false, nil, nil, &QueryError{
-			Message: "Result must be a pointer to a slice",
-			Code:    ErrCodeInvalidInput,
-		}
+		return 0, 0, false, false, nil, nil, NewInvalidInput("Result must be a pointer to a slice", "")
 	}
 
 	sliceValue := resultValue.Elem()
 	if sliceValue.Kind() != reflect.Slice {
-		return 0, 0, false, false, nil, nil, &QueryError{
-			Message: "Result must be a pointer to a slice",
-			Code:    ErrCodeInvalidInput,
-		}
+		return 0, 0, false, false, nil, nil, NewInvalidInput("Result must be a pointer to a slice", "")
 	}
 
 	// Decode each raw item into the slice
@@ -329,12 +495,8 @@ func searchEntities(
 		newElem := reflect.New(elemType.Elem()) // elemType is *Customer, elemType.Elem() is Customer
 
 		// Unmarshal into the new element
-		if err := bson.Unmarshal(raw, newElem.Interface()); err != nil {
-			return 0, 0, false, false, nil, nil, &QueryError{
-				Message: "Failed to decode entity into result type",
-				Code:    ErrCodeDatabaseError,
-				Cause:   err,
-			}
+		if err := decodeEntity(raw, newElem.Interface()); err != nil {
+			return 0, 0, false, false, nil, nil, NewDatabaseError(err, msgDecodeFailed).WithEntity(config.CollectionName)
 		}
 
 		// Append to the slice
@@ -347,14 +509,14 @@ func searchEntities(
 	if count > 0 {
 		// Start cursor: from first item
 		firstItem := tempArray[0]
-		startCursorValue, err := generateCursor(firstItem, sortFieldNames)
+		startCursorValue, err := generateCursor(firstItem, plan.sortKeys, snapshotAt, startTotal)
 		if err == nil {
 			startCursor = &startCursorValue
 		}
 
 		// End cursor: from last item
 		lastItem := tempArray[count-1]
-		endCursorValue, err := generateCursor(lastItem, sortFieldNames)
+		endCursorValue, err := generateCursor(lastItem, plan.sortKeys, snapshotAt, startTotal)
 		if err == nil {
 			endCursor = &endCursorValue
 		}
@@ -363,49 +525,125 @@ func searchEntities(
 	return count, totalCount, hasNextPage, hasPreviousPage, startCursor, endCursor, nil
 }
 
-// generateCursor creates a cursor string from an entity document and sort fields
-func generateCursor(doc bson.M, sortFieldNames []string) (string, error) {
+// searchFacetResult is the single document produced by the search $facet stage
+type searchFacetResult struct {
+	Metadata []struct {
+		TotalCount int `bson:"totalCount"`
+	} `bson:"metadata"`
+	Data   []bson.Raw               `bson:"data"`    // Use bson.Raw for flexible decoding
+	Facets map[string][]facetBucket `bson:",inline"` // Requested facet counts, keyed by facetKey
+}
+
+// Stored actionIndicator values the ActionIndicator enum does not define, e.g. from legacy
+// documents, read as UNKNOWN instead of failing the page holding them
+func init() {
+	db.RegisterEnumDecoder(reflect.TypeOf(generated.ActionIndicatorNone), string(generated.ActionIndicatorUnknown))
+}
+
+// decodeEntity decodes a search result into an entity; stored dates decode into its string timestamps
+func decodeEntity(raw bson.Raw, entity interface{}) error {
+	return bson.UnmarshalWithRegistry(db.Registry, raw, entity)
+}
+
+// aggregateSearch runs the search pipeline and decodes the facet results
+func aggregateSearch(ctx context.Context, collection db.Collection, pipeline []bson.M, opts *options.AggregateOptions) ([]searchFacetResult, error) {
+	cursor, err := collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, NewDatabaseError(err, msgQueryFailed)
+	}
+
+	var facetResults []searchFacetResult
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		return cursor.All(ctx, &facetResults)
+	})
+	if err != nil {
+		return nil, NewDatabaseError(err, msgDecodeFailed)
+	}
+	return facetResults, nil
+}
+
+// aggregateSearchSnapshot runs the search pipeline at a snapshot and decodes the facet results.
+// A nil snapshotAt starts a new snapshot; the cluster time read at is returned for the cursors
+func aggregateSearchSnapshot(ctx context.Context, collection db.Collection, pipeline []bson.M, snapshotAt *cursorSnapshot) ([]searchFacetResult, *cursorSnapshot, error) {
+	docs, readAt, err := collection.AggregateSnapshot(ctx, pipeline, snapshotAt.timestamp())
+	switch {
+	case errors.Is(err, db.ErrSnapshotUnsupported):
+		return nil, nil, newInvalidInputError("snapshot pagination requires a replica set or sharded cluster; this MongoDB deployment is a standalone server")
+	case errors.Is(err, db.ErrSnapshotExpired):
+		return nil, nil, newInvalidInputError("snapshot has expired; restart pagination without a cursor")
+	case err != nil:
+		return nil, nil, NewDatabaseError(err, msgQueryFailed)
+	}
+
+	facetResults := make([]searchFacetResult, len(docs))
+	for i, doc := range docs {
+		if err := bson.Unmarshal(doc, &facetResults[i]); err != nil {
+			return nil, nil, NewDatabaseError(err, msgDecodeFailed)
+		}
+	}
+	return facetResults, newCursorSnapshot(readAt), nil
+}
+
+// generateCursor creates a cursor string from an entity document and sort keys
+// Snapshot paginations pass the cluster time their pages read at
+// and every pagination the totalCount of its first page
+func generateCursor(doc bson.M, sortKeys bson.D, snapshotAt *cursorSnapshot, startTotal int) (string, error) {
 	cursor := Cursor{
-		SortFields: make([]interface{}, 0, len(sortFieldNames)),
+		SortFields: make([]interface{}, 0, len(sortKeys)),
+		Sort:       sortFingerprint(sortKeys, snapshotAt != nil),
+		Snapshot:   snapshotAt,
+		StartTotal: startTotal,
 	}
 
 	// Extract sort field values
-	for _, fieldName := range sortFieldNames {
-		if fieldName == "identifier" {
-			continue // Skip identifier in sort fields, we'll add it separately
+	for _, key := range sortKeys {
+		if isTiebreakerKey(key.Key) {
+			continue // Skip identifier and _id in sort fields, we'll add the tiebreaker separately
 		}
-		value := doc[fieldName]
-		cursor.SortFields = append(cursor.SortFields, value)
+		value := doc[key.Key]
+		cursor.SortFields = append(cursor.SortFields, cursorSortValue(value))
 	}
 
-	// Always add identifier as tiebreaker
-	identifier, ok := doc["identifier"].(string)
-	if !ok {
-		return "", fmt.Errorf("document missing identifier field")
+	// Always add identifier as tiebreaker; legacy documents without one fall back to their _id
+	if identifier, ok := doc["identifier"].(string); ok {
+		cursor.Identifier = identifier
+	} else if id, ok := doc["_id"].(primitive.ObjectID); ok {
+		cursor.Identifier, cursor.Legacy = id.Hex(), true
+	} else {
+		return "", fmt.Errorf("document missing identifier and _id fields")
 	}
-	cursor.Identifier = identifier
 
 	// Encode cursor
 	return encodeCursor(cursor)
 }
 
-// buildDataPipeline constructs the data branch of the $facet pipeline
-func buildDataPipeline(sortStages []bson.M, afterCursor, beforeCursor *Cursor, sortFieldNames []string, first, last *int, effectiveLimit int) []bson.M {
+// buildDataPipeline constructs the data branch of the $facet pipeline, reading forward past
+// afterCursor or backward before beforeCursor. A non-nil projection stage is applied after the limit
+func buildDataPipeline(sortStages []bson.M, afterCursor, beforeCursor *Cursor, sortKeys bson.D, isForward bool, effectiveLimit int, projection bson.M) []bson.M {
 	dataPipeline := []bson.M{}
 
-	// Apply sorting stages
-	dataPipeline = append(dataPipeline, sortStages...)
+	// Apply sorting stages. Computed null-safe sort keys are not removed: the pagination filter
+	// compares on them and the cursors carry their values. Result decoding ignores them.
+	// Backward pages sort in reverse so the limit keeps the documents nearest the end
+	for _, stage := range sortStages {
+		if _, removesSortKeys := stage["$project"]; removesSortKeys {
+			continue
+		}
+		if keys, ok := stage["$sort"].(bson.D); ok && !isForward {
+			stage = bson.M{"$sort": reverseSortKeys(keys)}
+		}
+		dataPipeline = append(dataPipeline, stage)
+	}
 
 	// Apply cursor-based pagination filter
-	isForward := first != nil || (first == nil && last == nil)
 
 	if isForward && afterCursor != nil {
-		paginationFilter := buildPaginationFilter(afterCursor, sortFieldNames, true)
+		paginationFilter := buildPaginationFilter(afterCursor, sortKeys, true)
 		if len(paginationFilter) > 0 {
 			dataPipeline = append(dataPipeline, bson.M{"$match": paginationFilter})
 		}
 	} else if !isForward && beforeCursor != nil {
-		paginationFilter := buildPaginationFilter(beforeCursor, sortFieldNames, false)
+		paginationFilter := buildPaginationFilter(beforeCursor, sortKeys, false)
 		if len(paginationFilter) > 0 {
 			dataPipeline = append(dataPipeline, bson.M{"$match": paginationFilter})
 		}
@@ -414,5 +652,22 @@ func buildDataPipeline(sortStages []bson.M, afterCursor, beforeCursor *Cursor, s
 	// Apply limit (+1 to detect hasNextPage/hasPreviousPage)
 	dataPipeline = append(dataPipeline, bson.M{"$limit": effectiveLimit + 1})
 
+	if projection != nil {
+		dataPipeline = append(dataPipeline, projection)
+	}
+
 	return dataPipeline
 }
+
+// reverseSortKeys returns sort keys with every direction flipped
+func reverseSortKeys(keys bson.D) bson.D {
+	reversed := make(bson.D, len(keys))
+	for i, key := range keys {
+		direction := 1
+		if key.Value == 1 {
+			direction = -1
+		}
+		reversed[i] = bson.E{Key: key.Key, Value: direction}
+	}
+	return reversed
+}
@@ -0,0 +1,433 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// fakeSearchDB serves searches over identifier-sorted documents. Its collection evaluates
+// the data branch of the search $facet stage: a $sort on identifier and _id, an identifier
+// $match from a cursor, and the $limit. Legacy documents without identifier sort first; keys
+// the $sort leaves tied keep their seeded order, like MongoDB returns ties in no fixed order.
+// A $limit above maxLimit (when set) fails like a result document above 16MB
+type fakeSearchDB struct {
+	identifiers []string
+	legacyIDs   []primitive.ObjectID // _id of documents without identifier
+	maxLimit    int
+	pipelines   [][]bson.M // Data branches in execution order
+	batchSizes  []int32    // Cursor batch sizes in execution order
+	facetStages []bson.M   // $facet stages in execution order
+	facets      bson.M     // Facet count branches answered next to the page, keyed by branch
+}
+
+func (f *fakeSearchDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f *fakeSearchDB) Collection(name string) db.Collection                       { return &fakeSearchCollection{db: f} }
+func (f *fakeSearchDB) ReadCollection(name string) db.Collection                   { return f.Collection(name) }
+func (f *fakeSearchDB) IsConnected() bool                                          { return true }
+
+type fakeSearchCollection struct {
+	db.Collection
+	db *fakeSearchDB
+}
+
+func (c *fakeSearchCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	stages := pipeline.([]bson.M)
+	facet := stages[len(stages)-1]["$facet"].(bson.M)
+
+	c.db.facetStages = append(c.db.facetStages, facet)
+	c.db.pipelines = append(c.db.pipelines, facet["data"].([]bson.M))
+	if merged := options.MergeAggregateOptions(opts...); merged.BatchSize != nil {
+		c.db.batchSizes = append(c.db.batchSizes, *merged.BatchSize)
+	}
+
+	docs := make([]bson.M, 0, len(c.db.legacyIDs)+len(c.db.identifiers))
+	for _, id := range c.db.legacyIDs {
+		docs = append(docs, bson.M{"_id": id})
+	}
+	for _, identifier := range c.db.identifiers {
+		docs = append(docs, bson.M{"_id": primitive.NewObjectID(), "identifier": identifier})
+	}
+
+	for _, stage := range facet["data"].([]bson.M) {
+		switch {
+		case stage["$sort"] != nil:
+			keys := stage["$sort"].(bson.D)
+			sort.SliceStable(docs, func(i, j int) bool { return fakeSortsBefore(docs[i], docs[j], keys) })
+		case stage["$match"] != nil:
+			kept := docs[:0]
+			for _, doc := range docs {
+				if fakeMatches(doc, stage["$match"].(bson.M)) {
+					kept = append(kept, doc)
+				}
+			}
+			docs = kept
+		case stage["$limit"] != nil:
+			if c.db.maxLimit > 0 && stage["$limit"].(int) > c.db.maxLimit {
+				return nil, mongo.CommandError{Code: errCodeBSONObjectTooLarge, Message: "BSONObj size is invalid"}
+			}
+			if limit := stage["$limit"].(int); len(docs) > limit {
+				docs = docs[:limit]
+			}
+		}
+	}
+
+	data := make(bson.A, len(docs))
+	for i, doc := range docs {
+		data[i] = doc
+	}
+	result := bson.M{
+		"metadata": bson.A{bson.M{"totalCount": len(c.db.identifiers)}},
+		"data":     data,
+	}
+	for key, buckets := range c.db.facets {
+		result[key] = buckets
+	}
+	return mongo.NewCursorFromDocuments([]interface{}{result}, nil, nil)
+}
+
+// fakeSortsBefore orders documents like MongoDB sorts by the keys: missing values before
+// strings, strings and ObjectIDs in their natural order
+func fakeSortsBefore(a, b bson.M, keys bson.D) bool {
+	for _, key := range keys {
+		compared := fakeCompare(a[key.Key], b[key.Key])
+		if compared != 0 {
+			return (compared < 0) == (key.Value != -1)
+		}
+	}
+	return false
+}
+
+// fakeCompare compares two sort values of the same field; nil is a missing value
+func fakeCompare(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if id, ok := a.(primitive.ObjectID); ok {
+		return strings.Compare(id.Hex(), b.(primitive.ObjectID).Hex())
+	}
+	return strings.Compare(a.(string), b.(string))
+}
+
+// fakeMatches evaluates the pagination filters on identifier and _id
+func fakeMatches(doc bson.M, filter bson.M) bool {
+	for key, condition := range filter {
+		if key == "$or" {
+			if !slices.ContainsFunc(condition.([]bson.M), func(branch bson.M) bool { return fakeMatches(doc, branch) }) {
+				return false
+			}
+			continue
+		}
+
+		value, present := doc[key]
+		if condition == nil {
+			if present {
+				return false
+			}
+			continue
+		}
+		for op, operand := range condition.(bson.M) {
+			var ok bool
+			switch v := value.(type) {
+			case string:
+				compared, isString := operand.(string)
+				ok = (op == "$type" && operand == "string") ||
+					(isString && ((op == "$gt" && v > compared) || (op == "$lt" && v < compared)))
+			case primitive.ObjectID:
+				compared := operand.(primitive.ObjectID).Hex()
+				ok = (op == "$gt" && v.Hex() > compared) || (op == "$lt" && v.Hex() < compared)
+			}
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// searchPage is the outcome of a fake search
+type searchPage struct {
+	identifiers                  []string
+	count, totalCount            int
+	hasNextPage, hasPrevPage     bool
+	startCursorSet, endCursorSet bool
+}
+
+func runFakeSearch(t *testing.T, identifiers []string, first *int, after *string, last *int, before *string) searchPage {
+	t.Helper()
+	var employees []*generated.Employee
+	count, totalCount, hasNextPage, hasPreviousPage, startCursor, endCursor, err := searchEntities(context.Background(),
+		&fakeSearchDB{identifiers: identifiers}, entityConfigs["employee"], nil, nil, SearchPaging{First: first, After: after, Last: last, Before: before}, SearchOptions{}, &employees)
+	require.NoError(t, err)
+
+	page := searchPage{count: count, totalCount: totalCount, hasNextPage: hasNextPage, hasPrevPage: hasPreviousPage,
+		startCursorSet: startCursor != nil, endCursorSet: endCursor != nil}
+	for _, employee := range employees {
+		page.identifiers = append(page.identifiers, employee.Identifier)
+	}
+	return page
+}
+
+// cursorAt returns the cursor of a document in the identifier-only employee sort
+func cursorAt(t *testing.T, identifier string) *string {
+	t.Helper()
+	cursor, err := generateCursor(bson.M{"identifier": identifier}, bson.D{{Key: "identifier", Value: 1}}, nil, 0)
+	require.NoError(t, err)
+	return &cursor
+}
+
+func intRef(v int) *int { return &v }
+
+// TestSearchEntities_LastMoreThanMatches tests last N over fewer than N matches returns every
+// match in sort order with no further pages
+func TestSearchEntities_LastMoreThanMatches(t *testing.T) {
+	page := runFakeSearch(t, []string{"c", "a", "b"}, nil, nil, intRef(10), nil)
+	assert.Equal(t, searchPage{
+		identifiers:    []string{"a", "b", "c"},
+		count:          3,
+		totalCount:     3,
+		startCursorSet: true, endCursorSet: true,
+	}, page)
+}
+
+// TestSearchEntities_LastReturnsTheEnd tests last N returns the final N matches in sort order
+func TestSearchEntities_LastReturnsTheEnd(t *testing.T) {
+	identifiers := make([]string, 25)
+	for i := range identifiers {
+		identifiers[i] = fmt.Sprintf("e%02d", i)
+	}
+
+	page := runFakeSearch(t, identifiers, nil, nil, intRef(3), nil)
+	assert.Equal(t, []string{"e22", "e23", "e24"}, page.identifiers)
+	assert.True(t, page.hasPrevPage)
+	assert.False(t, page.hasNextPage)
+
+	page = runFakeSearch(t, identifiers, nil, nil, intRef(3), cursorAt(t, "e10"))
+	assert.Equal(t, []string{"e07", "e08", "e09"}, page.identifiers)
+	assert.True(t, page.hasPrevPage)
+	assert.True(t, page.hasNextPage)
+
+	page = runFakeSearch(t, identifiers, nil, nil, intRef(10), cursorAt(t, "e03"))
+	assert.Equal(t, []string{"e00", "e01", "e02"}, page.identifiers)
+	assert.False(t, page.hasPrevPage)
+	assert.True(t, page.hasNextPage)
+}
+
+// TestSearchEntities_EmptyPagePastCursor tests a cursor that excludes every document yields an
+// empty page that still points back across the cursor
+func TestSearchEntities_EmptyPagePastCursor(t *testing.T) {
+	identifiers := []string{"b", "c"}
+
+	page := runFakeSearch(t, identifiers, nil, nil, intRef(10), cursorAt(t, "a"))
+	assert.Equal(t, searchPage{totalCount: 2, hasNextPage: true}, page)
+
+	page = runFakeSearch(t, identifiers, intRef(10), cursorAt(t, "z"), nil, nil)
+	assert.Equal(t, searchPage{totalCount: 2, hasPrevPage: true}, page)
+}
+
+// TestSearchEntities_ChangesBetweenPages tests a pagination returns no identifier twice when
+// documents, including the cursor's own, are deleted or created between pages, and that its
+// cursors carry the first page's totalCount while totalCount follows the changes
+func TestSearchEntities_ChangesBetweenPages(t *testing.T) {
+	fake := &fakeSearchDB{identifiers: []string{"a", "b", "c", "d", "e", "f", "g"}}
+	search := func(after *string) ([]string, int, *string) {
+		t.Helper()
+		var employees []*generated.Employee
+		_, totalCount, _, _, _, endCursor, err := searchEntities(context.Background(), fake, entityConfigs["employee"], nil, nil, SearchPaging{First: intRef(3), After: after}, SearchOptions{}, &employees)
+		require.NoError(t, err)
+		identifiers := []string{}
+		for _, employee := range employees {
+			identifiers = append(identifiers, employee.Identifier)
+		}
+		return identifiers, totalCount, endCursor
+	}
+
+	page, totalCount, end := search(nil)
+	assert.Equal(t, []string{"a", "b", "c"}, page)
+	assert.Equal(t, 7, totalCount)
+	assert.Equal(t, int64(7), *totalCountAtStart(nil, nil, totalCount))
+
+	// The cursor's document and the next one are deleted, one document is created behind the
+	// cursor and one ahead of it
+	fake.identifiers = []string{"a", "b", "bb", "e", "f", "g", "h"}
+	page, totalCount, end = search(end)
+	assert.Equal(t, []string{"e", "f", "g"}, page)
+	assert.Equal(t, 7, totalCount)
+
+	// The cursor's document is deleted again
+	fake.identifiers = []string{"a", "b", "bb", "e", "f", "h"}
+	page, totalCount, _ = search(end)
+	assert.Equal(t, []string{"h"}, page)
+	assert.Equal(t, 6, totalCount, "totalCount is counted again for every page")
+	assert.Equal(t, int64(7), *totalCountAtStart(end, nil, totalCount), "the cursors carry the first page's totalCount")
+
+	// Cursors issued before they carried the total report none
+	assert.Nil(t, totalCountAtStart(cursorAt(t, "b"), nil, totalCount))
+	assert.Nil(t, totalCountAtStart(nil, cursorAt(t, "b"), totalCount))
+}
+
+// TestPageSizeArgs tests Long first/last arguments are range-checked before they become ints,
+// so values beyond the range of int are rejected rather than wrapped
+func TestPageSizeArgs(t *testing.T) {
+	ten, negative, absurd := int64(10), int64(-1), int64(math.MaxInt64)
+
+	first, last, err := pageSizeArgs(&ten, nil)
+	require.NoError(t, err)
+	assert.Equal(t, intRef(10), first)
+	assert.Nil(t, last)
+
+	first, last, err = pageSizeArgs(nil, &ten)
+	require.NoError(t, err)
+	assert.Nil(t, first)
+	assert.Equal(t, intRef(10), last)
+
+	_, _, err = pageSizeArgs(&absurd, nil)
+	requireInvalidInput(t, err, fmt.Sprintf("'first' exceeds maximum batch size: requested %d, maximum %d", absurd, limits.MaxPageSize))
+
+	_, _, err = pageSizeArgs(nil, &negative)
+	requireInvalidInput(t, err, "'last' must be non-negative")
+
+	_, _, err = pageSizeArgs(&negative, &ten)
+	requireInvalidInput(t, err, msgFirstAndLast)
+}
+
+// TestSearchEntities_MissingIdentifier tests a legacy document without identifier is returned,
+// and that paging forward and backward over it visits every document once
+func TestSearchEntities_MissingIdentifier(t *testing.T) {
+	legacyID := primitive.NewObjectID()
+	fake := &fakeSearchDB{identifiers: []string{"b", "a", "c"}, legacyIDs: []primitive.ObjectID{legacyID}}
+
+	search := func(first *int, after *string, last *int, before *string) ([]string, *string, *string) {
+		t.Helper()
+		var employees []*generated.Employee
+		_, _, _, _, startCursor, endCursor, err := searchEntities(context.Background(), fake, entityConfigs["employee"], nil, nil, SearchPaging{First: first, After: after, Last: last, Before: before}, SearchOptions{}, &employees)
+		require.NoError(t, err)
+		identifiers := []string{}
+		for _, employee := range employees {
+			identifiers = append(identifiers, employee.Identifier)
+		}
+		return identifiers, startCursor, endCursor
+	}
+
+	page, _, end := search(intRef(1), nil, nil, nil)
+	assert.Equal(t, []string{""}, page, "the legacy document sorts first with an empty identifier")
+	decoded, err := decodeCursor(*end)
+	require.NoError(t, err)
+	assert.True(t, decoded.Legacy)
+	assert.Equal(t, legacyID.Hex(), decoded.Identifier)
+
+	page, start, _ := search(intRef(10), end, nil, nil)
+	assert.Equal(t, []string{"a", "b", "c"}, page)
+
+	page, _, _ = search(nil, nil, intRef(10), start)
+	assert.Equal(t, []string{""}, page, "paging back from the first identifier reaches the legacy document")
+
+	_, start, _ = search(nil, nil, intRef(1), nil)
+	page, _, _ = search(nil, nil, intRef(10), start)
+	assert.Equal(t, []string{"", "a", "b"}, page)
+}
+
+// TestSearchEntities_LegacyDocumentsAcrossPages tests paging forward and backward over several
+// legacy documents split across page boundaries visits every document once, in _id order
+func TestSearchEntities_LegacyDocumentsAcrossPages(t *testing.T) {
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	// Seeded out of _id order, so only the $sort puts them in order
+	fake := &fakeSearchDB{identifiers: []string{"b", "a", "c"}, legacyIDs: []primitive.ObjectID{ids[2], ids[0], ids[1]}}
+	want := []string{ids[0].Hex(), ids[1].Hex(), ids[2].Hex(), "a", "b", "c"}
+
+	// cursorPosition returns the identifier, or the _id of a legacy document, a cursor points at
+	cursorPosition := func(cursor *string) string {
+		t.Helper()
+		decoded, err := decodeCursor(*cursor)
+		require.NoError(t, err)
+		return decoded.Identifier
+	}
+	search := func(first *int, after *string, last *int, before *string) (int, *string, *string) {
+		t.Helper()
+		var employees []*generated.Employee
+		count, _, _, _, startCursor, endCursor, err := searchEntities(context.Background(), fake, entityConfigs["employee"], nil, nil, SearchPaging{First: first, After: after, Last: last, Before: before}, SearchOptions{}, &employees)
+		require.NoError(t, err)
+		return count, startCursor, endCursor
+	}
+
+	t.Run("forward", func(t *testing.T) {
+		visited := []string{}
+		var after *string
+		for {
+			count, start, end := search(intRef(2), after, nil, nil)
+			if count == 0 {
+				break
+			}
+			visited = append(visited, cursorPosition(start), cursorPosition(end))
+			after = end
+		}
+		assert.Equal(t, want, visited)
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		visited := []string{}
+		var before *string
+		for {
+			count, start, end := search(nil, nil, intRef(2), before)
+			if count == 0 {
+				break
+			}
+			visited = append([]string{cursorPosition(start), cursorPosition(end)}, visited...)
+			before = start
+		}
+		assert.Equal(t, want, visited)
+	})
+}
+
+// TestGenerateCursor_LegacyFallback tests cursors of documents without identifier carry their
+// _id, and that a legacy cursor with a malformed _id is rejected
+func TestGenerateCursor_LegacyFallback(t *testing.T) {
+	keys := bson.D{{Key: "identifier", Value: 1}}
+
+	_, err := generateCursor(bson.M{"firstName": "Jane"}, keys, nil, 0)
+	assert.ErrorContains(t, err, "document missing identifier and _id fields")
+
+	encoded, err := encodeCursor(Cursor{Identifier: "not-an-object-id", Legacy: true, Sort: sortFingerprint(keys, false)})
+	require.NoError(t, err)
+	_, err = decodeCursor(encoded)
+	requireInvalidInput(t, err, "invalid cursor: malformed document id")
+}
+
+// TestSkipMissingIdentifiers tests lenient mode drops legacy documents from a page with a
+// warning, while strict mode returns them
+func TestSkipMissingIdentifiers(t *testing.T) {
+	page := func() []*generated.Employee {
+		return []*generated.Employee{{Identifier: "a"}, {}, {Identifier: "b"}}
+	}
+
+	kept, skipped := skipMissingIdentifiers(page())
+	assert.Len(t, kept, 3)
+	assert.Zero(t, skipped)
+	assert.Empty(t, searchWarnings(nil, nil, skipped))
+
+	SetLenientFilters(true)
+	t.Cleanup(func() { SetLenientFilters(false) })
+	kept, skipped = skipMissingIdentifiers(page())
+	assert.Equal(t, []*generated.Employee{{Identifier: "a"}, {Identifier: "b"}}, kept)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, []*generated.QueryWarning{{Field: "identifier", Reason: "1 document(s) without an identifier were skipped"}}, searchWarnings(nil, nil, skipped))
+}
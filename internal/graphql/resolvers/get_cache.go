@@ -0,0 +1,203 @@
+package resolvers
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// getCache is a read-through cache of Get-by-identifier queries. It keeps found documents for
+// the positive TTL and, for the shorter negative TTL, that an identifier was not found, so
+// repeated probes for a missing identifier do not reach MongoDB. Entries are kept per
+// authorization scope, so a caller only receives documents its own scope found. Mutations of
+// this instance invalidate an identifier's entries right away; changes made elsewhere (other
+// instances, direct writes) become visible once the entry expires
+type getCache struct {
+	mu          sync.Mutex
+	entities    []string
+	ttl         time.Duration // 0 disables the cache
+	negativeTTL time.Duration // 0 disables negative caching
+	maxEntries  int
+
+	entries    map[string]*list.Element // By entity and identifier
+	order      *list.List               // Least recently used at the back
+	generation uint64                   // Incremented by every invalidation
+	counters   map[string]*GetCacheStats
+}
+
+// getCacheEntry holds the cached lookups of one identifier of an entity
+type getCacheEntry struct {
+	key    string
+	scopes map[string]getCacheValue // By authorization scope
+}
+
+// getCacheValue is a cached lookup; a nil document records that the identifier was not found
+type getCacheValue struct {
+	document  bson.Raw
+	expiresAt time.Time
+}
+
+// GetCacheStats counts the Get lookups of an entity since the cache was configured
+type GetCacheStats struct {
+	Entity       string
+	Hits         int64 // Answered with a cached document
+	NegativeHits int64 // Answered with a cached not-found
+	Misses       int64 // Read from MongoDB
+}
+
+// entityGetCache is the cache of the Get queries; disabled until SetGetCache enables it
+var entityGetCache = &getCache{}
+
+// SetGetCache configures the Get cache of the given entities (entityConfigs names), keeping up
+// to entries identifiers, found documents for ttl and not-found results for negativeTTL.
+// A ttl of 0 disables the cache. Configuring the cache discards its entries and counters
+func SetGetCache(entities []string, entries int, ttl, negativeTTL time.Duration) error {
+	for _, entity := range entities {
+		if _, ok := entityConfigs[entity]; !ok {
+			return fmt.Errorf("unknown entity %q", entity)
+		}
+	}
+	if ttl > 0 && entries < 1 {
+		return fmt.Errorf("get cache entries must be positive, got %d", entries)
+	}
+	if ttl < 0 || negativeTTL < 0 {
+		return fmt.Errorf("get cache TTLs must not be negative, got %s and %s", ttl, negativeTTL)
+	}
+
+	if ttl == 0 {
+		entities = nil
+	}
+
+	c := entityGetCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entities = slices.Clone(entities)
+	c.ttl = ttl
+	c.negativeTTL = negativeTTL
+	c.maxEntries = entries
+	c.entries = make(map[string]*list.Element)
+	c.order = list.New()
+	c.counters = make(map[string]*GetCacheStats, len(entities))
+	for _, entity := range entities {
+		c.counters[entity] = &GetCacheStats{Entity: entity}
+	}
+	return nil
+}
+
+// CurrentGetCacheStats returns the lookup counters of the cached entities, in configuration order
+func CurrentGetCacheStats() []GetCacheStats {
+	c := entityGetCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := make([]GetCacheStats, 0, len(c.entities))
+	for _, entity := range c.entities {
+		stats = append(stats, *c.counters[entity])
+	}
+	return stats
+}
+
+// getCacheKey identifies an identifier of an entity
+func getCacheKey(entity, identifier string) string {
+	return entity + "\x00" + identifier
+}
+
+// getCacheScope identifies the authorization scope of the caller of a Get query by the canonical
+// form of its authorization filter, so equal filters give equal scopes
+func getCacheScope(ctx context.Context, config EntityConfig) string {
+	if config.AuthorizationFilter == nil {
+		return ""
+	}
+	filter := config.AuthorizationFilter(ctx)
+	scope, err := db.MarshalCanonical(filter)
+	if err != nil {
+		return fmt.Sprint(filter)
+	}
+	return string(scope)
+}
+
+// lookup returns the cached lookup of an identifier within a scope. ok is false when the entity is
+// not cached or nothing unexpired is cached; generation is then passed to store
+func (c *getCache) lookup(entity, identifier, scope string, now time.Time) (value getCacheValue, generation uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counters, cached := c.counters[entity]
+	if !cached {
+		return getCacheValue{}, 0, false
+	}
+
+	if element, found := c.entries[getCacheKey(entity, identifier)]; found {
+		value, ok = element.Value.(*getCacheEntry).scopes[scope]
+		if ok && now.Before(value.expiresAt) {
+			c.order.MoveToFront(element)
+			if value.document == nil {
+				counters.NegativeHits++
+			} else {
+				counters.Hits++
+			}
+			return value, 0, true
+		}
+	}
+	counters.Misses++
+	return getCacheValue{}, c.generation, false
+}
+
+// store caches the result of a Get read from MongoDB; a nil document records a not-found. Results
+// read while an invalidation happened are dropped, as they may predate the invalidating write
+func (c *getCache) store(entity, identifier, scope string, document bson.Raw, generation uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, cached := c.counters[entity]; !cached || generation != c.generation {
+		return
+	}
+	ttl := c.ttl
+	if document == nil {
+		ttl = c.negativeTTL
+	}
+	if ttl == 0 {
+		return
+	}
+
+	key := getCacheKey(entity, identifier)
+	element, found := c.entries[key]
+	if !found {
+		element = c.order.PushFront(&getCacheEntry{key: key, scopes: make(map[string]getCacheValue)})
+		c.entries[key] = element
+		if c.order.Len() > c.maxEntries {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*getCacheEntry).key)
+		}
+	}
+	element.Value.(*getCacheEntry).scopes[scope] = getCacheValue{document: document, expiresAt: now.Add(ttl)}
+}
+
+// invalidateGetCache removes the cached lookups of an identifier in every scope, found or not.
+// Mutations call it after their write
+func invalidateGetCache(entity, identifier string) {
+	c := entityGetCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	key := getCacheKey(entity, identifier)
+	if element, found := c.entries[key]; found {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// entityName returns the entityConfigs name of an entity configuration
+func entityName(config EntityConfig) string {
+	for name, candidate := range entityConfigs {
+		if candidate.CollectionName == config.CollectionName {
+			return name
+		}
+	}
+	return config.CollectionName
+}
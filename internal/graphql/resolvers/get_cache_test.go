@@ -0,0 +1,197 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+const (
+	getCacheCustomerID = "550e8400-e29b-41d4-a716-446655440000"
+	getCacheMissingID  = "550e8400-e29b-41d4-a716-446655440001"
+)
+
+// fakeGetDB serves FindOne by identifier and counts the reads reaching it
+type fakeGetDB struct {
+	documents map[string]bson.M
+	reads     int
+}
+
+func (f *fakeGetDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f *fakeGetDB) Collection(name string) db.Collection                       { return &fakeGetCollection{db: f} }
+func (f *fakeGetDB) ReadCollection(name string) db.Collection                   { return f.Collection(name) }
+func (f *fakeGetDB) IsConnected() bool                                          { return true }
+
+type fakeGetCollection struct {
+	db.Collection
+	db *fakeGetDB
+}
+
+func (c *fakeGetCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	c.db.reads++
+	match := filter.(bson.M)
+	if and, ok := match["$and"]; ok {
+		match = and.([]bson.M)[0]
+	}
+	document, ok := c.db.documents[match["identifier"].(string)]
+	if !ok {
+		return mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(document, nil, nil)
+}
+
+// stepClock is a Clock whose time only moves when advanced
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time                         { return c.now }
+func (c *stepClock) After(d time.Duration) <-chan time.Time { return make(chan time.Time) }
+
+// setupGetCache enables the customer Get cache with a fake clock for the duration of the test
+func setupGetCache(t *testing.T, ttl, negativeTTL time.Duration) *stepClock {
+	t.Helper()
+	require.NoError(t, SetGetCache([]string{"customer"}, 10, ttl, negativeTTL))
+	clock := &stepClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	SetClock(clock)
+	t.Cleanup(func() {
+		SetClock(db.RealClock{})
+		_ = SetGetCache(nil, 0, 0, 0)
+	})
+	return clock
+}
+
+func getCacheAdmin() context.Context {
+	return WithUserClaims(context.Background(), &UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+}
+
+func getCustomer(t *testing.T, ctx context.Context, database *fakeGetDB, identifier string) generated.Customer {
+	t.Helper()
+	var customer generated.Customer
+	require.NoError(t, getEntity(ctx, database, entityConfigs["customer"], identifier, &customer))
+	return customer
+}
+
+func TestGetCache_FoundDocumentServedFromCache(t *testing.T) {
+	clock := setupGetCache(t, time.Minute, time.Second)
+	database := &fakeGetDB{documents: map[string]bson.M{
+		getCacheCustomerID: {"identifier": getCacheCustomerID, "firstName": "Ada"},
+	}}
+	ctx := getCacheAdmin()
+
+	assert.Equal(t, "Ada", *getCustomer(t, ctx, database, getCacheCustomerID).FirstName)
+	database.documents[getCacheCustomerID]["firstName"] = "Grace"
+	assert.Equal(t, "Ada", *getCustomer(t, ctx, database, getCacheCustomerID).FirstName)
+	assert.Equal(t, 1, database.reads)
+
+	clock.now = clock.now.Add(time.Minute)
+	assert.Equal(t, "Grace", *getCustomer(t, ctx, database, getCacheCustomerID).FirstName)
+	assert.Equal(t, 2, database.reads)
+}
+
+// TestGetCache_CreatedAfterNegativeEntry verifies an entity written elsewhere after its
+// identifier was cached as not found becomes visible once the negative TTL elapsed, even
+// though the positive TTL is much longer
+func TestGetCache_CreatedAfterNegativeEntry(t *testing.T) {
+	clock := setupGetCache(t, time.Hour, 2*time.Second)
+	database := &fakeGetDB{documents: map[string]bson.M{}}
+	ctx := getCacheAdmin()
+
+	assert.Empty(t, getCustomer(t, ctx, database, getCacheMissingID).Identifier)
+	database.documents[getCacheMissingID] = bson.M{"identifier": getCacheMissingID}
+
+	clock.now = clock.now.Add(time.Second)
+	assert.Empty(t, getCustomer(t, ctx, database, getCacheMissingID).Identifier, "within the negative TTL")
+	assert.Equal(t, 1, database.reads)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.Equal(t, getCacheMissingID, getCustomer(t, ctx, database, getCacheMissingID).Identifier)
+	assert.Equal(t, 2, database.reads)
+}
+
+func TestGetCache_InvalidationRemovesBothKinds(t *testing.T) {
+	setupGetCache(t, time.Hour, time.Hour)
+	database := &fakeGetDB{documents: map[string]bson.M{
+		getCacheCustomerID: {"identifier": getCacheCustomerID, "firstName": "Ada"},
+	}}
+	ctx := getCacheAdmin()
+
+	getCustomer(t, ctx, database, getCacheCustomerID)
+	getCustomer(t, ctx, database, getCacheMissingID)
+
+	database.documents[getCacheCustomerID]["firstName"] = "Grace"
+	database.documents[getCacheMissingID] = bson.M{"identifier": getCacheMissingID}
+	invalidateGetCache("customer", getCacheCustomerID)
+	invalidateGetCache("customer", getCacheMissingID)
+
+	assert.Equal(t, "Grace", *getCustomer(t, ctx, database, getCacheCustomerID).FirstName)
+	assert.Equal(t, getCacheMissingID, getCustomer(t, ctx, database, getCacheMissingID).Identifier)
+	assert.Equal(t, 4, database.reads)
+}
+
+func TestGetCache_ReadDuringInvalidationNotStored(t *testing.T) {
+	clock := setupGetCache(t, time.Hour, time.Hour)
+	_, generation, ok := entityGetCache.lookup("customer", getCacheCustomerID, "", clock.now)
+	require.False(t, ok)
+
+	invalidateGetCache("customer", getCacheCustomerID)
+	entityGetCache.store("customer", getCacheCustomerID, "", nil, generation, clock.now)
+
+	_, _, ok = entityGetCache.lookup("customer", getCacheCustomerID, "", clock.now)
+	assert.False(t, ok, "the read may predate the invalidating write")
+}
+
+func TestGetCache_ScopedByAuthorization(t *testing.T) {
+	setupGetCache(t, time.Hour, time.Hour)
+	database := &fakeGetDB{documents: map[string]bson.M{
+		getCacheCustomerID: {"identifier": getCacheCustomerID},
+	}}
+	teamA := WithUserClaims(context.Background(), &UserClaims{UserID: "a", Teams: []string{"team-a"}})
+	teamB := WithUserClaims(context.Background(), &UserClaims{UserID: "b", Teams: []string{"team-b"}})
+
+	getCustomer(t, teamA, database, getCacheCustomerID)
+	getCustomer(t, teamA, database, getCacheCustomerID)
+	getCustomer(t, teamB, database, getCacheCustomerID)
+	assert.Equal(t, 2, database.reads)
+}
+
+func TestGetCache_Stats(t *testing.T) {
+	setupGetCache(t, time.Hour, time.Hour)
+	database := &fakeGetDB{documents: map[string]bson.M{
+		getCacheCustomerID: {"identifier": getCacheCustomerID},
+	}}
+	ctx := getCacheAdmin()
+
+	getCustomer(t, ctx, database, getCacheCustomerID)
+	getCustomer(t, ctx, database, getCacheCustomerID)
+	getCustomer(t, ctx, database, getCacheMissingID)
+	getCustomer(t, ctx, database, getCacheMissingID)
+	getCustomer(t, ctx, database, getCacheMissingID)
+
+	assert.Equal(t, []GetCacheStats{{Entity: "customer", Hits: 1, NegativeHits: 2, Misses: 2}}, CurrentGetCacheStats())
+}
+
+func TestGetCache_Disabled(t *testing.T) {
+	setupGetCache(t, 0, time.Hour)
+	database := &fakeGetDB{documents: map[string]bson.M{}}
+	ctx := getCacheAdmin()
+
+	getCustomer(t, ctx, database, getCacheMissingID)
+	getCustomer(t, ctx, database, getCacheMissingID)
+	assert.Equal(t, 2, database.reads)
+	assert.Empty(t, CurrentGetCacheStats())
+}
+
+func TestSetGetCache_UnknownEntity(t *testing.T) {
+	err := SetGetCache([]string{"customer", "spaceship"}, 10, time.Minute, time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spaceship")
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/health"
 )
 
 // mapHealthStatus maps internal database health status to GraphQL DatabaseHealth type (T089)
@@ -47,8 +48,8 @@ func (r *Resolver) resolveHealth(ctx context.Context) (*generated.Health, error)
 				errorMsg,
 			)
 
-			// Set overall status to degraded if database is not connected
-			if dbHealth.Status != "connected" {
+			// Set overall status to degraded if either connection is down
+			if dbHealth.Status != "connected" || (dbHealth.Reader != nil && dbHealth.Reader.Status != "connected") {
 				health.Status = "degraded"
 			}
 		} else {
@@ -70,3 +71,29 @@ func (r *Resolver) resolveHealth(ctx context.Context) (*generated.Health, error)
 
 	return health, nil
 }
+
+// resolveServiceInfo implements the serviceInfo query resolver
+// Without configured service info only the build metadata linked into the binary is returned
+func (r *Resolver) resolveServiceInfo() *generated.ServiceInfo {
+	info := r.ServiceInfo
+	if info == nil {
+		info = &health.ServiceInfo{Build: health.CurrentBuildInfo()}
+	}
+
+	result := &generated.ServiceInfo{
+		Build: &generated.BuildInfo{
+			GitSha:    info.Build.GitSHA,
+			BuildTime: info.Build.BuildTime,
+		},
+	}
+	if info.Schema != nil {
+		result.Schema = &generated.SchemaInfo{
+			Path:      info.Schema.Path,
+			TypeCount: info.Schema.TypeCount,
+			Checksum:  info.Schema.Checksum,
+			LoadedAt:  info.Schema.LoadedAt,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,122 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// heavyFieldsExtensionKey is the GraphQL response extension listing heavy fields a search returned as null
+const heavyFieldsExtensionKey = "heavyFieldsOmitted"
+
+// OmittedHeavyFields reports the selected heavy fields a search did not read
+type OmittedHeavyFields struct {
+	Fields  []string `json:"fields"`
+	Message string   `json:"message"`
+}
+
+// heavyFieldsMu serializes registering and filling the heavy fields extension, which
+// concurrently resolved fields of one operation share
+var heavyFieldsMu sync.Mutex
+
+// SetSearchHeavyFields sets the fields searches return as null unless includeHeavyFields is
+// passed, as entity.field entries (e.g. customer.payment). Entities not listed have no heavy
+// fields. Returns an error for an unknown entity or a field the entity's search results lack
+func SetSearchHeavyFields(entries []string, schema *ast.Schema) error {
+	heavyFields, err := searchHeavyFields(entries, schema)
+	if err != nil {
+		return err
+	}
+	for entity, config := range entityConfigs {
+		config.HeavyFields = heavyFields[entity]
+		entityConfigs[entity] = config
+	}
+	return nil
+}
+
+// CheckSearchHeavyFields reports the error SetSearchHeavyFields would return for the schema,
+// without changing the heavy fields
+func CheckSearchHeavyFields(entries []string, schema *ast.Schema) error {
+	_, err := searchHeavyFields(entries, schema)
+	return err
+}
+
+// searchHeavyFields validates entity.field entries against the schema and groups the fields by entity
+func searchHeavyFields(entries []string, schema *ast.Schema) (map[string][]string, error) {
+	heavyFields := make(map[string][]string)
+	for _, entry := range entries {
+		entity, field, ok := strings.Cut(entry, ".")
+		if !ok || entity == "" || field == "" {
+			return nil, fmt.Errorf("%q is not an entity.field entry", entry)
+		}
+		if _, ok := entityConfigs[entity]; !ok {
+			return nil, fmt.Errorf("unknown entity %q; known entities: %s", entity, strings.Join(entityNames(), ", "))
+		}
+		if !searchResultHasField(schema, entity, field) {
+			return nil, fmt.Errorf("%sSearch results have no field %q", entity, field)
+		}
+		heavyFields[entity] = append(heavyFields[entity], field)
+	}
+	return heavyFields, nil
+}
+
+// searchResultHasField reports whether the entries of an entity's search results define a field
+func searchResultHasField(schema *ast.Schema, entity, field string) bool {
+	if schema.Query == nil {
+		return false
+	}
+	search := schema.Query.Fields.ForName(entity + "Search")
+	if search == nil {
+		return false
+	}
+	output := schema.Types[search.Type.Name()]
+	if output == nil {
+		return false
+	}
+	data := output.Fields.ForName(searchDataField)
+	if data == nil {
+		return false
+	}
+	entry := schema.Types[data.Type.Name()]
+	return entry != nil && entry.Fields.ForName(field) != nil
+}
+
+// selectedHeavyFields returns the entity's heavy fields selected under the search's data field
+func selectedHeavyFields(ctx context.Context, config EntityConfig) []string {
+	if len(config.HeavyFields) == 0 {
+		return nil
+	}
+	var selected []string
+	for _, field := range selectedDataFields(ctx) {
+		if slices.Contains(config.HeavyFields, field.Name) && !slices.Contains(selected, field.Name) {
+			selected = append(selected, field.Name)
+		}
+	}
+	return selected
+}
+
+// recordOmittedHeavyFields reports the heavy fields a search returned as null in the response
+// extensions, keyed by field path
+func recordOmittedHeavyFields(ctx context.Context, fields []string) {
+	if !graphql.HasOperationContext(ctx) || graphql.GetFieldContext(ctx) == nil {
+		return
+	}
+	key := graphql.GetFieldContext(ctx).Path().String()
+
+	heavyFieldsMu.Lock()
+	defer heavyFieldsMu.Unlock()
+	omitted, ok := graphql.GetExtension(ctx, heavyFieldsExtensionKey).(map[string]OmittedHeavyFields)
+	if !ok {
+		omitted = make(map[string]OmittedHeavyFields)
+		graphql.RegisterExtension(ctx, heavyFieldsExtensionKey, omitted)
+	}
+	omitted[key] = OmittedHeavyFields{
+		Fields:  fields,
+		Message: "heavy fields are returned as null; pass includeHeavyFields: true to read them",
+	}
+}
@@ -0,0 +1,85 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// TestSearchEntities_OmitsHeavyFields tests a search selecting heavy fields reads them only with
+// includeHeavyFields, and reports the omitted ones in the extensions otherwise
+func TestSearchEntities_OmitsHeavyFields(t *testing.T) {
+	query := `{ customerSearch { data { firstName payment { iban } openBanking { __typename } } } }`
+
+	t.Run("without includeHeavyFields", func(t *testing.T) {
+		fake := &fakeSearchDB{identifiers: []string{"a", "b"}}
+		ctx := searchFieldContext(t, query)
+
+		var customers []*generated.Customer
+		_, _, _, _, _, _, err := searchEntities(ctx, fake, entityConfigs["customer"], nil, nil, SearchPaging{First: intRef(10)}, SearchOptions{}, &customers)
+		require.NoError(t, err)
+
+		require.Len(t, fake.pipelines, 1)
+		assert.Equal(t, bson.M{"$project": bson.M{"identifier": 1, "firstName": 1, "createDate": 1}}, fake.pipelines[0][len(fake.pipelines[0])-1])
+		assert.Equal(t, map[string]OmittedHeavyFields{
+			"customerSearch": {
+				Fields:  []string{"payment", "openBanking"},
+				Message: "heavy fields are returned as null; pass includeHeavyFields: true to read them",
+			},
+		}, graphql.GetExtension(ctx, heavyFieldsExtensionKey))
+	})
+
+	t.Run("with includeHeavyFields", func(t *testing.T) {
+		fake := &fakeSearchDB{identifiers: []string{"a", "b"}}
+		ctx := searchFieldContext(t, query)
+
+		var customers []*generated.Customer
+		_, _, _, _, _, _, err := searchEntities(ctx, fake, entityConfigs["customer"], nil, nil, SearchPaging{First: intRef(10)}, SearchOptions{IncludeHeavyFields: true}, &customers)
+		require.NoError(t, err)
+
+		require.Len(t, fake.pipelines, 1)
+		assert.Equal(t, bson.M{"$project": bson.M{"identifier": 1, "firstName": 1, "createDate": 1, "payment": 1, "openBanking": 1}}, fake.pipelines[0][len(fake.pipelines[0])-1])
+		assert.Nil(t, graphql.GetExtension(ctx, heavyFieldsExtensionKey))
+	})
+
+	t.Run("no heavy field selected", func(t *testing.T) {
+		fake := &fakeSearchDB{identifiers: []string{"a"}}
+		ctx := searchFieldContext(t, `{ customerSearch { data { firstName } } }`)
+
+		var customers []*generated.Customer
+		_, _, _, _, _, _, err := searchEntities(ctx, fake, entityConfigs["customer"], nil, nil, SearchPaging{First: intRef(10)}, SearchOptions{}, &customers)
+		require.NoError(t, err)
+		assert.Nil(t, graphql.GetExtension(ctx, heavyFieldsExtensionKey))
+	})
+}
+
+// TestSetSearchHeavyFields tests entries are assigned to their entities, and unknown entities
+// or fields are rejected
+func TestSetSearchHeavyFields(t *testing.T) {
+	before := map[string][]string{}
+	for entity, config := range entityConfigs {
+		before[entity] = config.HeavyFields
+	}
+	t.Cleanup(func() {
+		for entity, fields := range before {
+			config := entityConfigs[entity]
+			config.HeavyFields = fields
+			entityConfigs[entity] = config
+		}
+	})
+	s := generated.NewExecutableSchema(generated.Config{}).Schema()
+
+	require.NoError(t, SetSearchHeavyFields([]string{"customer.payment", "team.members"}, s))
+	assert.Equal(t, []string{"payment"}, entityConfigs["customer"].HeavyFields)
+	assert.Equal(t, []string{"members"}, entityConfigs["team"].HeavyFields)
+	assert.Nil(t, entityConfigs["employee"].HeavyFields)
+
+	assert.ErrorContains(t, SetSearchHeavyFields([]string{"payment"}, s), "not an entity.field entry")
+	assert.ErrorContains(t, SetSearchHeavyFields([]string{"invoice.payment"}, s), `unknown entity "invoice"`)
+	assert.ErrorContains(t, SetSearchHeavyFields([]string{"customer.salary"}, s), `customerSearch results have no field "salary"`)
+}
@@ -3,20 +3,21 @@ package resolvers
 import (
 	"context"
 	"fmt"
-	"time"
 
-	"github.com/rs/zerolog/log"
+	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/logger"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // T018: Batch size validation
 func validateBatchSize(identifiers []string) error {
-	if len(identifiers) > MaxBatchSize {
+	if len(identifiers) > limits.MaxBatchSize {
 		return newInvalidInputError(fmt.Sprintf(
 			"batch size exceeds maximum: requested %d, maximum %d",
 			len(identifiers),
-			MaxBatchSize,
+			limits.MaxBatchSize,
 		))
 	}
 	return nil
@@ -25,8 +26,8 @@ func validateBatchSize(identifiers []string) error {
 // T019: UUID format validation for all identifiers
 func validateUUIDs(identifiers []string) error {
 	for _, id := range identifiers {
-		if !isValidUUID(id) {
-			return newInvalidInputError(fmt.Sprintf("invalid UUID format: %s", id))
+		if reason := checkUUID(id); reason != "" {
+			return newInvalidInputError(reason)
 		}
 	}
 	return nil
@@ -49,10 +50,9 @@ func deduplicateIdentifiers(identifiers []string) []string {
 
 // T021: Build MongoDB filter with $in operator and deletion status check
 func buildInventoryFilter(identifiers []string) bson.M {
-	return bson.M{
+	return entityConfigs["inventory"].ExcludeDeleted(bson.M{
 		"identifier": bson.M{"$in": identifiers},
-		"actionIndicator": bson.M{"$ne": "DELETE"},
-	}
+	})
 }
 
 // T022: Build aggregation pipeline with ordering
@@ -121,31 +121,22 @@ func appendCustomerIDSorting(pipeline []bson.M, sortDirection generated.SortEnum
 
 // T023: Fetch inventories from database
 func (r *queryResolver) fetchInventories(ctx context.Context, pipeline []bson.M) ([]*generated.Inventory, error) {
-	collection := r.DBClient.Collection("inventories")
+	collection := r.DBClient.ReadCollection("inventories")
 	if collection == nil {
-		return nil, &QueryError{
-			Message: "Database not available",
-			Code:    ErrCodeDatabaseError,
-		}
+		return nil, newDatabaseUnavailableError()
 	}
 
 	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, &QueryError{
-			Message: "Database query failed",
-			Code:    ErrCodeDatabaseError,
-			Cause:   err,
-		}
+		return nil, NewDatabaseError(err, msgQueryFailed)
 	}
-	defer cursor.Close(ctx)
 
 	var inventories []*generated.Inventory
-	if err := cursor.All(ctx, &inventories); err != nil {
-		return nil, &QueryError{
-			Message: "Failed to decode inventories",
-			Code:    ErrCodeDatabaseError,
-			Cause:   err,
-		}
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		return cursor.All(ctx, &inventories)
+	})
+	if err != nil {
+		return nil, NewDatabaseError(err, msgDecodeFailed)
 	}
 
 	return inventories, nil
@@ -162,7 +153,7 @@ func (r *queryResolver) ByKeysGetLegacy(
 	order []*generated.InventoryQuerySorterInput,
 ) ([]*generated.Inventory, error) {
 	// T026: Log query parameters per OBS-001
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	identifierCount := len(identifiers)
 
 	var orderStr string
@@ -172,7 +163,7 @@ func (r *queryResolver) ByKeysGetLegacy(
 		orderStr = "identifier ASC (default)"
 	}
 
-	log.Info().
+	logger.FromContext(ctx).Info().
 		Int("identifierCount", identifierCount).
 		Str("order", orderStr).
 		Str("query", "byKeysGet").
@@ -184,11 +175,11 @@ func (r *queryResolver) ByKeysGetLegacy(
 	// T027: Log query duration per OBS-002
 	// T029: Log errors per OBS-004
 	defer func() {
-		duration := time.Since(startTime).Milliseconds()
+		duration := queryDuration(startTime).Milliseconds()
 
 		if err != nil {
 			// Log error case
-			log.Error().
+			logger.FromContext(ctx).Error().
 				Err(err).
 				Int("identifierCount", identifierCount).
 				Int64("duration", duration).
@@ -196,7 +187,7 @@ func (r *queryResolver) ByKeysGetLegacy(
 				Msg("byKeysGet query failed")
 		} else {
 			// T028: Log result count per OBS-003
-			log.Info().
+			logger.FromContext(ctx).Info().
 				Int("identifierCount", identifierCount).
 				Int("resultCount", resultCount).
 				Int64("duration", duration).
@@ -0,0 +1,92 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// An inventory's external reference number is stored in its key field. Numbers are looked up
+// exactly but case-insensitively, through an index sharing the lookup's collation. Numbers are
+// meant to be unique, but older data holds duplicates, so the index is not: a lookup matching
+// several inventories fails with a CONFLICT error instead
+
+// inventoryNumberField is the document field holding an inventory's number
+const inventoryNumberField = "key"
+
+// inventoryNumberCollation compares numbers ignoring case (strength 2 ignores case but not
+// diacritics). The lookup and its index must use the same collation for the index to apply
+var inventoryNumberCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// InventoryIndexes returns the indexes of the inventory collection: the sparse, case-insensitive
+// index of the number lookup
+func InventoryIndexes() []db.IndexSpec {
+	return []db.IndexSpec{
+		{
+			Collection: entityConfigs["inventory"].CollectionName,
+			Name:       "key_ci",
+			Keys:       bson.D{{Key: inventoryNumberField, Value: 1}},
+			Sparse:     true,
+			Collation:  inventoryNumberCollation,
+		},
+	}
+}
+
+// getInventoryByNumber returns the non-deleted inventory with the given number, or nil when none
+// has it. Several matching inventories are a CONFLICT error listing their identifiers
+func getInventoryByNumber(ctx context.Context, dbClient interface{}, number string) (*generated.Inventory, error) {
+	if strings.TrimSpace(number) == "" {
+		return nil, NewInvalidInput("number must not be empty", "number")
+	}
+
+	client, ok := dbClient.(DBClient)
+	if !ok {
+		return nil, newDatabaseUnavailableError()
+	}
+
+	config := entityConfigs["inventory"]
+	filter := config.ExcludeDeleted(bson.M{inventoryNumberField: number})
+	// Out-of-scope inventories are indistinguishable from missing ones
+	filter = applyAuthorizationFilter(ctx, config, filter)
+
+	opts := options.Find().
+		SetCollation(inventoryNumberCollation).
+		SetSort(bson.D{{Key: "identifier", Value: 1}})
+	cursor, err := client.ReadCollection(config.CollectionName).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mapMongoError(err)
+	}
+
+	var inventories []*generated.Inventory
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		return cursor.All(ctx, &inventories)
+	})
+	if err != nil {
+		return nil, mapMongoError(err)
+	}
+
+	switch len(inventories) {
+	case 0:
+		return nil, nil
+	case 1:
+		return inventories[0], nil
+	}
+
+	identifiers := make([]string, len(inventories))
+	for i, inventory := range inventories {
+		identifiers[i] = inventory.Identifier
+	}
+	return nil, &QueryError{
+		Message: fmt.Sprintf("inventory number %q matches %d inventories: %s", number, len(inventories), strings.Join(identifiers, ", ")),
+		Code:    ErrCodeConflict,
+		Field:   "number",
+		Entity:  config.CollectionName,
+	}
+}
@@ -0,0 +1,102 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// fakeNumberDB serves Find over inventory documents, matching the number case-insensitively when
+// the lookup sets a collation and skipping deleted inventories
+type fakeNumberDB struct {
+	documents []bson.M
+	collation *options.Collation
+}
+
+func (f *fakeNumberDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f *fakeNumberDB) Collection(name string) db.Collection                       { return &fakeNumberCollection{db: f} }
+func (f *fakeNumberDB) ReadCollection(name string) db.Collection                   { return f.Collection(name) }
+func (f *fakeNumberDB) IsConnected() bool                                          { return true }
+
+type fakeNumberCollection struct {
+	db.Collection
+	db *fakeNumberDB
+}
+
+func (c *fakeNumberCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	number := filter.(bson.M)[inventoryNumberField].(string)
+	c.db.collation = options.MergeFindOptions(opts...).Collation
+
+	var matches []interface{}
+	for _, document := range c.db.documents {
+		key, _ := document[inventoryNumberField].(string)
+		if document["actionIndicator"] == "DELETE" {
+			continue
+		}
+		if key == number || (c.db.collation != nil && strings.EqualFold(key, number)) {
+			matches = append(matches, document)
+		}
+	}
+	return mongo.NewCursorFromDocuments(matches, nil, nil)
+}
+
+func TestGetInventoryByNumber(t *testing.T) {
+	database := &fakeNumberDB{documents: []bson.M{
+		{"identifier": "inv-1", "key": "INV-001", "actionIndicator": "NONE"},
+		{"identifier": "inv-2", "key": "INV-002", "actionIndicator": "DELETE"},
+		{"identifier": "inv-3", "key": "INV-003", "actionIndicator": "NONE"},
+		{"identifier": "inv-4", "key": "inv-003", "actionIndicator": "NONE"},
+		{"identifier": "inv-5", "actionIndicator": "NONE"},
+	}}
+	ctx := context.Background()
+
+	t.Run("found ignoring case", func(t *testing.T) {
+		inventory, err := getInventoryByNumber(ctx, database, "inv-001")
+		require.NoError(t, err)
+		require.NotNil(t, inventory)
+		assert.Equal(t, "inv-1", inventory.Identifier)
+		assert.Equal(t, inventoryNumberCollation, database.collation)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		inventory, err := getInventoryByNumber(ctx, database, "INV-404")
+		require.NoError(t, err)
+		assert.Nil(t, inventory)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		inventory, err := getInventoryByNumber(ctx, database, "INV-002")
+		require.NoError(t, err)
+		assert.Nil(t, inventory)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		inventory, err := getInventoryByNumber(ctx, database, "INV-003")
+		assert.Nil(t, inventory)
+		var queryErr *QueryError
+		require.ErrorAs(t, err, &queryErr)
+		assert.Equal(t, ErrCodeConflict, queryErr.Code)
+		assert.Equal(t, "number", queryErr.Field)
+		assert.Contains(t, queryErr.Message, "inv-3, inv-4")
+	})
+
+	t.Run("empty number", func(t *testing.T) {
+		_, err := getInventoryByNumber(ctx, database, " ")
+		requireInvalidInput(t, err, "number")
+	})
+}
+
+func TestConvertInventoryFilter_Key(t *testing.T) {
+	eq := "INV-001"
+	filter := convertInventoryFilter(&generated.InventoryQueryFilterInput{Key: &generated.StringFilterInput{Eq: &eq}})
+	assert.Equal(t, bson.M{"key": "INV-001"}, filter)
+}
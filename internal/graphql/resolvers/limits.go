@@ -0,0 +1,92 @@
+package resolvers
+
+import (
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// DefaultExportMaxRows is the default row cap of an NDJSON export
+const DefaultExportMaxRows = 100000
+
+// Limits are the request limits enforced by the resolvers and the export endpoint.
+// The serverLimits query reports the same values, so clients never hard-code them
+type Limits struct {
+	MaxBatchSize    int          // Maximum identifiers per byKeys request
+	DefaultPageSize int          // Page size of a search without first or last
+	MaxPageSize     int          // Maximum first or last of a search
+	MaxSortEntries  int          // Maximum entries in an order array
+	Filter          FilterLimits // Search filter size limits
+	ExportMaxRows   int          // Row cap per export
+	MaxRelationSize int          // Maximum entries of a relation field
+	MaxComplexity   int          // Maximum complexity of a GraphQL operation
+}
+
+// DefaultLimits returns the limits used unless configured otherwise
+func DefaultLimits() Limits {
+	return Limits{
+		MaxBatchSize:    MaxBatchSize,
+		DefaultPageSize: DefaultPageSize,
+		MaxPageSize:     MaxBatchSize,
+		MaxSortEntries:  DefaultMaxSortEntries,
+		Filter:          DefaultFilterLimits(),
+		ExportMaxRows:   DefaultExportMaxRows,
+		MaxRelationSize: DefaultMaxRelationSize,
+		MaxComplexity:   DefaultMaxQueryComplexity,
+	}
+}
+
+// limits are the limits currently enforced
+var limits = DefaultLimits()
+
+// CurrentLimits returns the limits currently enforced
+func CurrentLimits() Limits {
+	return limits
+}
+
+// SetExportMaxRows sets the row cap per export
+// Values below 1 are ignored
+func SetExportMaxRows(n int) {
+	if n >= 1 {
+		limits.ExportMaxRows = n
+	}
+}
+
+// SetMaxQueryComplexity sets the maximum complexity of a GraphQL operation
+// Values below 1 are ignored
+func SetMaxQueryComplexity(n int) {
+	if n >= 1 {
+		limits.MaxComplexity = n
+	}
+}
+
+// resolveServerLimits implements the serverLimits query resolver
+func resolveServerLimits() *generated.ServerLimits {
+	current := CurrentLimits()
+
+	entities := enabledEntityNames()
+
+	result := &generated.ServerLimits{
+		Entities:       make([]*generated.EntityLimits, 0, len(entities)),
+		MaxSortEntries: current.MaxSortEntries,
+		MaxFilterDepth: current.Filter.MaxDepth,
+		Filter: &generated.FilterLimits{
+			MaxIn:    current.Filter.MaxIn,
+			MaxNin:   current.Filter.MaxNin,
+			MaxAnd:   current.Filter.MaxAnd,
+			MaxOr:    current.Filter.MaxOr,
+			MaxBytes: current.Filter.MaxBytes,
+		},
+		ExportMaxRows:      current.ExportMaxRows,
+		MaxRelationSize:    current.MaxRelationSize,
+		MaxQueryComplexity: current.MaxComplexity,
+	}
+	for _, entity := range entities {
+		result.Entities = append(result.Entities, &generated.EntityLimits{
+			Entity:          entity,
+			MaxBatchSize:    current.MaxBatchSize,
+			DefaultPageSize: current.DefaultPageSize,
+			MaxPageSize:     current.MaxPageSize,
+		})
+	}
+
+	return result
+}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"time"
 
-	"github.com/rs/zerolog/log"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/logger"
 )
 
 // Performance thresholds for different query types
@@ -14,6 +15,20 @@ const (
 	SlowQueryThresholdHealth = 100 * time.Millisecond  // Health check queries
 )
 
+// queryClock times queries for the slow query thresholds
+var queryClock db.Clock = db.RealClock{}
+
+// SetClock replaces the time source for query durations (tests use a fake clock)
+// Must be called before serving queries
+func SetClock(clock db.Clock) {
+	queryClock = clock
+}
+
+// queryDuration returns the time elapsed since a query started
+func queryDuration(startTime time.Time) time.Duration {
+	return queryClock.Now().Sub(startTime)
+}
+
 // getQueryThreshold returns the appropriate performance threshold for a query
 func getQueryThreshold(queryName string) time.Duration {
 	// Health and metadata queries
@@ -49,9 +64,10 @@ func isSearchQuery(queryName string) bool {
 	return searchQueries[queryName]
 }
 
-// logQueryExecution logs query performance metrics
+// logQueryExecution logs query performance metrics with the request ID and user ID of ctx
 func logQueryExecution(ctx context.Context, queryName string, duration time.Duration, success bool) {
 	threshold := getQueryThreshold(queryName)
+	log := logger.FromContext(ctx)
 	logEvent := log.Info()
 
 	// Flag as slow query if exceeds threshold
@@ -59,17 +75,6 @@ func logQueryExecution(ctx context.Context, queryName string, duration time.Dura
 		logEvent = log.Warn().Bool("slow_query", true)
 	}
 
-	// Extract request ID from context if available
-	requestID := getRequestID(ctx)
-	if requestID != "" {
-		logEvent = logEvent.Str("request_id", requestID)
-	}
-
-	// Extract user ID from context if available
-	if claims := getUserClaims(ctx); claims != nil {
-		logEvent = logEvent.Str("user_id", claims.UserID)
-	}
-
 	logEvent.
 		Str("query", queryName).
 		Dur("duration_ms", duration).
@@ -78,59 +83,21 @@ func logQueryExecution(ctx context.Context, queryName string, duration time.Dura
 		Msg("GraphQL query executed")
 }
 
-// logQueryError logs query execution errors
+// logQueryError logs query execution errors with the code, entity type and resolver of a QueryError
 func logQueryError(ctx context.Context, queryName string, err error, duration time.Duration) {
-	logEvent := log.Error().Err(err)
-
-	// Extract request ID from context if available
-	requestID := getRequestID(ctx)
-	if requestID != "" {
-		logEvent = logEvent.Str("request_id", requestID)
-	}
-
-	// Extract user ID from context if available
-	if claims := getUserClaims(ctx); claims != nil {
-		logEvent = logEvent.Str("user_id", claims.UserID)
-	}
-
-	// Extract error code if it's a QueryError
-	if err != nil {
-		if qe, ok := err.(*QueryError); ok {
-			logEvent = logEvent.Str("error_code", qe.Code)
-		}
-	}
-
-	logEvent.
+	logger.FromContext(ctx).Error().Err(err).
+		Func(queryErrorFields(err)).
 		Str("query", queryName).
 		Dur("duration_ms", duration).
 		Bool("success", false).
 		Msg("GraphQL query error")
 }
 
-// getRequestID extracts the request ID from context
-func getRequestID(ctx context.Context) string {
-	if reqID, ok := ctx.Value("request_id").(string); ok {
-		return reqID
-	}
-	return ""
-}
-
 // T008: Search-specific logging functions
 
 // logSearchStart logs the start of a search query with filter/pagination parameters
 func logSearchStart(ctx context.Context, entityType string, hasFilter bool, first, last *int, hasAfter, hasBefore bool) {
-	logEvent := log.Info()
-
-	// Extract request ID from context if available
-	requestID := getRequestID(ctx)
-	if requestID != "" {
-		logEvent = logEvent.Str("request_id", requestID)
-	}
-
-	// Extract user ID from context if available
-	if claims := getUserClaims(ctx); claims != nil {
-		logEvent = logEvent.Str("user_id", claims.UserID)
-	}
+	logEvent := logger.FromContext(ctx).Info()
 
 	logEvent.
 		Str("operation", "search_start").
@@ -156,6 +123,7 @@ func logSearchStart(ctx context.Context, entityType string, hasFilter bool, firs
 // logSearchResult logs the completion of a search query with result counts and performance
 func logSearchResult(ctx context.Context, entityType string, resultCount, totalCount int, duration time.Duration) {
 	threshold := SlowQueryThresholdSearch
+	log := logger.FromContext(ctx)
 	logEvent := log.Info()
 
 	// Flag as slow query if exceeds threshold
@@ -163,17 +131,6 @@ func logSearchResult(ctx context.Context, entityType string, resultCount, totalC
 		logEvent = log.Warn().Bool("slow_query", true)
 	}
 
-	// Extract request ID from context if available
-	requestID := getRequestID(ctx)
-	if requestID != "" {
-		logEvent = logEvent.Str("request_id", requestID)
-	}
-
-	// Extract user ID from context if available
-	if claims := getUserClaims(ctx); claims != nil {
-		logEvent = logEvent.Str("user_id", claims.UserID)
-	}
-
 	logEvent.
 		Str("operation", "search_complete").
 		Str("entity_type", entityType).
@@ -0,0 +1,12 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/yourusername/air-go/tests/testutil/leakcheck"
+)
+
+// TestMain fails the package when its tests leave cursors open
+func TestMain(m *testing.M) {
+	leakcheck.VerifyTestMain(m)
+}
@@ -0,0 +1,66 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// Some legacy documents have an _id but no identifier. Searches return them rather than failing
+// the page: their cursors carry the _id instead (see generateCursor), and their identifier reads
+// as an empty string since the schema does not allow null. In lenient mode they are skipped from
+// the page and reported in the result's warnings instead
+
+// missingIdentifierField is the field reported in the warning of skipped legacy documents
+const missingIdentifierField = "identifier"
+
+// logMissingIdentifiers logs how many documents of a search page have no identifier
+func logMissingIdentifiers(ctx context.Context, config EntityConfig, docs []bson.M) {
+	missing := 0
+	for _, doc := range docs {
+		if _, ok := doc["identifier"].(string); !ok {
+			missing++
+		}
+	}
+	if missing == 0 {
+		return
+	}
+	logger.FromContext(ctx).Warn().
+		Str("collection", config.CollectionName).
+		Int("documents_without_identifier", missing).
+		Int("page_size", len(docs)).
+		Msg("Search page holds legacy documents without identifier; their cursors use _id")
+}
+
+// skipMissingIdentifiers drops the entities without identifier from a search page in lenient
+// mode and returns how many it dropped. Strict mode returns the page as it is
+func skipMissingIdentifiers[T any](entities []*T) ([]*T, int) {
+	if !lenientFilters {
+		return entities, 0
+	}
+	kept := entities[:0]
+	for _, entity := range entities {
+		if reflect.ValueOf(entity).Elem().FieldByName("Identifier").String() != "" {
+			kept = append(kept, entity)
+		}
+	}
+	return kept, len(entities) - len(kept)
+}
+
+// searchWarnings returns the warnings of a search result: the ignored filter inputs and order
+// fields, and the legacy documents skipped from its page
+func searchWarnings(filter, sorter interface{}, skipped int) []*generated.QueryWarning {
+	warnings := append(searchFilterWarnings(filter), searchSortWarnings(sorter)...)
+	if skipped > 0 {
+		warnings = append(warnings, &generated.QueryWarning{
+			Field:  missingIdentifierField,
+			Reason: fmt.Sprintf("%d document(s) without an identifier were skipped", skipped),
+		})
+	}
+	return warnings
+}
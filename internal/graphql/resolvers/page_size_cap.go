@@ -0,0 +1,84 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// pageSizeCapExtensionKey is the GraphQL response extension listing capped first/last arguments
+const pageSizeCapExtensionKey = "pageSizeCap"
+
+// PageSizeCap reports a first or last argument above the maximum page size that was capped
+type PageSizeCap struct {
+	Capped    bool `json:"capped"`
+	Requested int  `json:"requested"`
+	Applied   int  `json:"applied"`
+}
+
+// capOversizedPageSizes caps first/last above the maximum page size instead of rejecting them,
+// for legacy clients that have not been fixed yet
+var capOversizedPageSizes = false
+
+// cappedPageSizes counts the capped first/last arguments since startup
+var cappedPageSizes atomic.Int64
+
+// pageSizeCapMu serializes registering and filling the cap extension, which concurrently
+// resolved fields of one operation share
+var pageSizeCapMu sync.Mutex
+
+// SetCapOversizedPageSizes sets whether first/last above the maximum page size are capped
+// to the maximum (with a warning in the response extensions) or rejected with INVALID_INPUT
+func SetCapOversizedPageSizes(enabled bool) {
+	capOversizedPageSizes = enabled
+}
+
+// CappedPageSizeCount returns how many first/last arguments were capped since startup
+func CappedPageSizeCount() int64 {
+	return cappedPageSizes.Load()
+}
+
+// capPageSize returns a first or last argument capped to the maximum page size when capping
+// is enabled; otherwise, or within the maximum, the argument is returned unchanged
+func capPageSize(ctx context.Context, argument string, value *int) *int {
+	if !capOversizedPageSizes || value == nil || *value <= limits.MaxPageSize {
+		return value
+	}
+	applied := limits.MaxPageSize
+	recordPageSizeCap(ctx, argument, *value, applied)
+	return &applied
+}
+
+// recordPageSizeCap counts and logs a capped argument and reports it in the response
+// extensions, keyed by field path
+func recordPageSizeCap(ctx context.Context, argument string, requested, applied int) {
+	cappedPageSizes.Add(1)
+
+	key := argument
+	if fc := graphql.GetFieldContext(ctx); fc != nil {
+		key = fc.Path().String()
+	}
+	logger.FromContext(ctx).Warn().
+		Str("field", key).
+		Str("argument", argument).
+		Int("requested", requested).
+		Int("applied", applied).
+		Msg("Page size above the maximum capped")
+
+	if !graphql.HasOperationContext(ctx) {
+		return
+	}
+
+	pageSizeCapMu.Lock()
+	defer pageSizeCapMu.Unlock()
+	caps, ok := graphql.GetExtension(ctx, pageSizeCapExtensionKey).(map[string]PageSizeCap)
+	if !ok {
+		caps = make(map[string]PageSizeCap)
+		graphql.RegisterExtension(ctx, pageSizeCapExtensionKey, caps)
+	}
+	caps[key] = PageSizeCap{Capped: true, Requested: requested, Applied: applied}
+}
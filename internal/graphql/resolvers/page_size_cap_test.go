@@ -0,0 +1,76 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// capPageSizes enables capping oversized first/last for the rest of a test
+func capPageSizes(t *testing.T) {
+	t.Helper()
+	SetCapOversizedPageSizes(true)
+	t.Cleanup(func() { SetCapOversizedPageSizes(false) })
+}
+
+// TestSearchEntities_OversizedPageSizeStrict tests first/last above the maximum are rejected by default
+func TestSearchEntities_OversizedPageSizeStrict(t *testing.T) {
+	ctx := searchFieldContext(t, `{ employeeSearch { count } }`)
+	before := CappedPageSizeCount()
+
+	var employees []*generated.Employee
+	_, _, _, _, _, _, err := searchEntities(ctx, &fakeSearchDB{}, entityConfigs["employee"], nil, nil, SearchPaging{First: intRef(limits.MaxPageSize + 1)}, SearchOptions{}, &employees)
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeInvalidInput, queryErr.Code)
+
+	assert.Nil(t, graphql.GetExtension(ctx, pageSizeCapExtensionKey))
+	assert.Equal(t, before, CappedPageSizeCount())
+}
+
+// TestSearchEntities_OversizedPageSizeCapped tests first/last above the maximum are capped in
+// compatibility mode, reported in the extensions and counted
+func TestSearchEntities_OversizedPageSizeCapped(t *testing.T) {
+	capPageSizes(t)
+
+	identifiers := make([]string, limits.MaxPageSize+10)
+	for i := range identifiers {
+		identifiers[i] = fmt.Sprintf("e%04d", i)
+	}
+	ctx := searchFieldContext(t, `{ employeeSearch { count } }`)
+	before := CappedPageSizeCount()
+
+	var employees []*generated.Employee
+	count, _, hasNextPage, _, _, _, err := searchEntities(ctx, &fakeSearchDB{identifiers: identifiers}, entityConfigs["employee"], nil, nil, SearchPaging{First: intRef(1000)}, SearchOptions{}, &employees)
+	require.NoError(t, err)
+	assert.Equal(t, limits.MaxPageSize, count)
+	assert.True(t, hasNextPage)
+
+	assert.Equal(t, map[string]PageSizeCap{
+		"employeeSearch": {Capped: true, Requested: 1000, Applied: limits.MaxPageSize},
+	}, graphql.GetExtension(ctx, pageSizeCapExtensionKey))
+	assert.Equal(t, before+1, CappedPageSizeCount())
+
+	// Within the maximum nothing is capped
+	ctx = searchFieldContext(t, `{ employeeSearch { count } }`)
+	_, _, _, _, _, _, err = searchEntities(ctx, &fakeSearchDB{identifiers: identifiers}, entityConfigs["employee"], nil, nil, SearchPaging{Last: intRef(limits.MaxPageSize)}, SearchOptions{}, &employees)
+	require.NoError(t, err)
+	assert.Nil(t, graphql.GetExtension(ctx, pageSizeCapExtensionKey))
+	assert.Equal(t, before+1, CappedPageSizeCount())
+}
+
+// TestCapPageSize tests negative values and calls outside GraphQL are left to the validation
+func TestCapPageSize(t *testing.T) {
+	capPageSizes(t)
+
+	assert.Nil(t, capPageSize(context.Background(), "first", nil))
+	assert.Equal(t, -1, *capPageSize(context.Background(), "first", intRef(-1)))
+	assert.Equal(t, limits.MaxPageSize, *capPageSize(context.Background(), "last", intRef(limits.MaxPageSize+1)))
+}
@@ -0,0 +1,24 @@
+package resolvers
+
+import (
+	"github.com/yourusername/air-go/internal/db/purge"
+)
+
+// PurgeTargets returns the entities whose deleted documents are purged after their PurgeAfter, by name
+func PurgeTargets() []purge.Target {
+	var targets []purge.Target
+	for _, name := range entityNames() {
+		config := entityConfigs[name]
+		if config.PurgeAfter <= 0 {
+			continue
+		}
+		targets = append(targets, purge.Target{
+			Entity:        name,
+			Collection:    config.CollectionName,
+			DeletionField: config.DeletionField,
+			DeletionValue: config.DeletionValue,
+			Retention:     config.PurgeAfter,
+		})
+	}
+	return targets
+}
@@ -0,0 +1,23 @@
+package resolvers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/db/purge"
+)
+
+func TestPurgeTargets(t *testing.T) {
+	targets := PurgeTargets()
+	require.Len(t, targets, 1)
+	assert.Equal(t, purge.Target{
+		Entity:        "customer",
+		Collection:    "customers",
+		DeletionField: "status.deletion",
+		DeletionValue: "DELETED",
+		Retention:     400 * 24 * time.Hour,
+	}, targets[0])
+}
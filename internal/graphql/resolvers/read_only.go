@@ -0,0 +1,40 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// readOnly makes every mutation answer READ_ONLY, for deployments serving reads only
+var readOnly atomic.Bool
+
+// SetReadOnly switches the read-only mode on or off
+func SetReadOnly(enabled bool) {
+	readOnly.Store(enabled)
+}
+
+// ReadOnly reports whether mutations are refused
+func ReadOnly() bool {
+	return readOnly.Load()
+}
+
+// newReadOnlyError creates the error of a mutation refused in read-only mode
+func newReadOnlyError(field string) error {
+	return &QueryError{
+		Message: fmt.Sprintf("%s is refused: the server is read-only", field),
+		Code:    ErrCodeReadOnly,
+	}
+}
+
+// RejectReadOnlyMutations is a gqlgen field middleware answering every Mutation field with
+// READ_ONLY before its resolver runs while the read-only mode is on; other fields pass through
+func RejectReadOnlyMutations(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" || !ReadOnly() {
+		return next(ctx)
+	}
+	return nil, toGraphQLError(newReadOnlyError(fc.Field.Name))
+}
@@ -0,0 +1,199 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// relationLoadersKey is the context key of the per-operation relation loaders
+type relationLoadersKey struct{}
+
+// relationLoaders batch the relation fields of one operation
+type relationLoaders struct {
+	mu        sync.Mutex
+	employees *batchLoader[*generated.Employee]          // Employee by identifier
+	teams     *batchLoader[[]*generated.TeamQueryOutput] // Teams by member employee identifier
+}
+
+// WithRelationLoaders returns a context whose relation fields share batched loaders
+// The GraphQL handler installs fresh loaders per operation
+func WithRelationLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, relationLoadersKey{}, &relationLoaders{})
+}
+
+// relationLoadersFrom returns the operation's loaders
+// Without WithRelationLoaders every field gets its own loaders, which still works but does not batch
+func relationLoadersFrom(ctx context.Context) *relationLoaders {
+	if loaders, ok := ctx.Value(relationLoadersKey{}).(*relationLoaders); ok {
+		return loaders
+	}
+	return &relationLoaders{}
+}
+
+// employeeLoader returns the loader of employees by identifier
+func (l *relationLoaders) employeeLoader(dbClient interface{}) *batchLoader[*generated.Employee] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.employees == nil {
+		l.employees = newBatchLoader(func(ctx context.Context, identifiers []string) (map[string]*generated.Employee, error) {
+			config, err := lookupEntityConfig("employee")
+			if err != nil {
+				return nil, err
+			}
+			var employees []*generated.Employee
+			if err := findRelated(ctx, dbClient, config, bson.M{"identifier": bson.M{"$in": identifiers}}, &employees); err != nil {
+				return nil, err
+			}
+			byIdentifier := make(map[string]*generated.Employee, len(employees))
+			for _, employee := range employees {
+				byIdentifier[employee.Identifier] = employee
+			}
+			return byIdentifier, nil
+		})
+	}
+	return l.employees
+}
+
+// teamLoader returns the loader of the teams listing an employee in teamMembers
+func (l *relationLoaders) teamLoader(dbClient interface{}) *batchLoader[[]*generated.TeamQueryOutput] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.teams == nil {
+		l.teams = newBatchLoader(func(ctx context.Context, identifiers []string) (map[string][]*generated.TeamQueryOutput, error) {
+			config, err := lookupEntityConfig("team")
+			if err != nil {
+				return nil, err
+			}
+			var teams []*generated.TeamQueryOutput
+			if err := findRelated(ctx, dbClient, config, bson.M{"teamMembers.keys": bson.M{"$in": identifiers}}, &teams); err != nil {
+				return nil, err
+			}
+			requested := make(map[string]bool, len(identifiers))
+			for _, identifier := range identifiers {
+				requested[identifier] = true
+			}
+			byMember := make(map[string][]*generated.TeamQueryOutput, len(identifiers))
+			for _, team := range teams {
+				if team.TeamMembers == nil {
+					continue
+				}
+				for _, member := range deduplicateIdentifiersGeneric(team.TeamMembers.Keys) {
+					if requested[member] {
+						byMember[member] = append(byMember[member], team)
+					}
+				}
+			}
+			return byMember, nil
+		})
+	}
+	return l.teams
+}
+
+// findRelated loads the non-deleted entities matching a relation filter in the entity's default order
+// The caller's authorization scope applies as it does to the root queries
+func findRelated(ctx context.Context, dbClient interface{}, config EntityConfig, filter bson.M, result interface{}) error {
+	config.ExcludeDeleted(filter)
+
+	sortStages, err := entitySortStages(config, nil)
+	if err != nil {
+		return err
+	}
+	pipeline := append([]bson.M{{"$match": applyAuthorizationFilter(ctx, config, filter)}}, sortStages...)
+
+	client, ok := dbClient.(DBClient)
+	if !ok {
+		return newDatabaseUnavailableError()
+	}
+
+	cursor, err := client.ReadCollection(config.CollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return NewDatabaseError(err, msgQueryFailed).WithEntity(config.CollectionName)
+	}
+
+	return decodeAllGuarded(ctx, config, cursor, result)
+}
+
+// relationSize validates a relation field's first argument and returns the number of entries to return
+func relationSize(first *int) (int, error) {
+	if first == nil {
+		return limits.MaxRelationSize, nil
+	}
+	if *first < 0 {
+		return 0, NewInvalidInput("'first' must not be negative", "first")
+	}
+	if *first > limits.MaxRelationSize {
+		return 0, NewInvalidInput(fmt.Sprintf("'first' exceeds maximum relation size: requested %d, maximum %d", *first, limits.MaxRelationSize), "first")
+	}
+	return *first, nil
+}
+
+// resolveTeamMembers implements the TeamQueryOutput.members field resolver
+func resolveTeamMembers(ctx context.Context, dbClient interface{}, team *generated.TeamQueryOutput, first *int) ([]*generated.Employee, error) {
+	size, err := relationSize(first)
+	if err != nil {
+		return nil, err
+	}
+	if team.TeamMembers == nil || len(team.TeamMembers.Keys) == 0 || size == 0 {
+		return []*generated.Employee{}, nil
+	}
+
+	loaded, err := relationLoadersFrom(ctx).employeeLoader(dbClient).LoadMany(ctx, deduplicateIdentifiersGeneric(team.TeamMembers.Keys))
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*generated.Employee, 0, len(loaded))
+	for _, employee := range loaded {
+		if employee != nil && len(members) < size {
+			members = append(members, employee)
+		}
+	}
+	return members, nil
+}
+
+// resolveEmployeeTeams implements the Employee.teams field resolver
+func resolveEmployeeTeams(ctx context.Context, dbClient interface{}, employee *generated.Employee, first *int) ([]*generated.TeamQueryOutput, error) {
+	size, err := relationSize(first)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return []*generated.TeamQueryOutput{}, nil
+	}
+
+	teams, err := relationLoadersFrom(ctx).teamLoader(dbClient).Load(ctx, employee.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(teams) > size {
+		teams = teams[:size]
+	}
+	if teams == nil {
+		teams = []*generated.TeamQueryOutput{}
+	}
+	return teams, nil
+}
+
+// relationComplexity is the complexity of a relation field: its selection counts once per entry
+func relationComplexity(childComplexity int, first *int) int {
+	size := limits.MaxRelationSize
+	if first != nil && *first >= 0 && *first < size {
+		size = *first
+	}
+	return 1 + size*childComplexity
+}
+
+// Complexity returns the complexity functions of the schema's relation fields.
+// Other fields keep gqlgen's default of one plus the complexity of their selection
+func Complexity() generated.ComplexityRoot {
+	var complexity generated.ComplexityRoot
+	complexity.TeamQueryOutput.Members = relationComplexity
+	complexity.Employee.Teams = relationComplexity
+	return complexity
+}
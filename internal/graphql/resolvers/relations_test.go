@@ -0,0 +1,34 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationSize(t *testing.T) {
+	size, err := relationSize(nil)
+	require.NoError(t, err)
+	assert.Equal(t, limits.MaxRelationSize, size)
+
+	five := 5
+	size, err = relationSize(&five)
+	require.NoError(t, err)
+	assert.Equal(t, 5, size)
+
+	over := limits.MaxRelationSize + 1
+	_, err = relationSize(&over)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds maximum relation size")
+
+	negative := -1
+	_, err = relationSize(&negative)
+	assert.Error(t, err)
+}
+
+func TestRelationComplexity(t *testing.T) {
+	two := 2
+	assert.Equal(t, 1+2*3, relationComplexity(3, &two))
+	assert.Equal(t, 1+limits.MaxRelationSize*3, relationComplexity(3, nil))
+}
@@ -1,28 +1,23 @@
 package resolvers
 
 import (
-	"context"
-
 	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/health"
 )
 
 // This file will NOT be regenerated by gqlgen
 // It sets up the base Resolver struct that the generated code uses
 
-// DBClient interface for database operations (enables testing with mocks)
-type DBClient interface {
-	HealthStatus(ctx context.Context) (*db.HealthStatus, error)
-	Collection(name string) db.Collection
-	IsConnected() bool
-}
-
-// Ensure *db.Client implements DBClient interface
-var _ DBClient = (*db.Client)(nil)
+// DBClient is the database access of the resolvers, defined once in the db package
+type DBClient = db.DBClient
 
 // Resolver holds dependencies for GraphQL resolvers (T088)
 type Resolver struct {
 	// Database client for health monitoring and data access
 	DBClient DBClient
+
+	// Schema and build metadata returned by the serviceInfo query (optional)
+	ServiceInfo *health.ServiceInfo
 }
 
 // NewResolver creates a new Resolver instance with the given database client
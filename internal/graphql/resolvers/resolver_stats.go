@@ -0,0 +1,226 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// DefaultResolverStatsWindow is how long resolver calls are kept for the resolverStats query
+const DefaultResolverStatsWindow = 5 * time.Minute
+
+// MaxResolverStatsWindow bounds the window, and with it the memory of the resolver statistics
+const MaxResolverStatsWindow = time.Hour
+
+// resolverStatsSlot is the granularity of the window: calls are kept per minute
+const resolverStatsSlot = time.Minute
+
+// Latency bins grow logarithmically from latencyBinMin, like an HDR histogram, so a percentile
+// read back from its bin is off by at most half a bin (about 2%). Latencies beyond the last bin
+// (about 7.5 minutes) count in the last bin
+const (
+	latencyBinMin    = 10 * time.Microsecond
+	latencyBinGrowth = 1.04
+	latencyBins      = 450
+)
+
+// latencyHistogram counts the calls of a resolver within a slot
+type latencyHistogram struct {
+	bins   [latencyBins]uint32 // Bin 0 holds latencies below latencyBinMin
+	calls  int
+	errors int
+}
+
+// latencyBin returns the bin of a latency
+func latencyBin(d time.Duration) int {
+	if d < latencyBinMin {
+		return 0
+	}
+	bin := int(math.Log(float64(d)/float64(latencyBinMin))/math.Log(latencyBinGrowth)) + 1
+	return min(bin, latencyBins-1)
+}
+
+// binLatency returns the latency a bin stands for: the geometric middle of its bounds
+func binLatency(bin int) time.Duration {
+	if bin == 0 {
+		return latencyBinMin / 2
+	}
+	return time.Duration(float64(latencyBinMin) * math.Pow(latencyBinGrowth, float64(bin)-0.5))
+}
+
+// add merges the calls of another histogram
+func (h *latencyHistogram) add(other *latencyHistogram) {
+	for i, count := range other.bins {
+		h.bins[i] += count
+	}
+	h.calls += other.calls
+	h.errors += other.errors
+}
+
+// percentile returns the latency below which the fraction q of the calls completed
+func (h *latencyHistogram) percentile(q float64) time.Duration {
+	if h.calls == 0 {
+		return 0
+	}
+	rank := uint32(math.Ceil(q * float64(h.calls)))
+	var seen uint32
+	for bin, count := range h.bins {
+		seen += count
+		if seen >= rank && count > 0 {
+			return binLatency(bin)
+		}
+	}
+	return binLatency(latencyBins - 1)
+}
+
+// resolverStatsWindow keeps the calls of the last minutes in a ring of per-minute slots.
+// A slot is reused once its minute left the window, so memory is bounded by the window, the
+// root resolvers and the bins
+type resolverStatsWindow struct {
+	mu    sync.Mutex
+	slots []resolverStatsSlotCalls
+}
+
+// resolverStatsSlotCalls are the calls per resolver within the minute starting at start
+type resolverStatsSlotCalls struct {
+	start     time.Time
+	resolvers map[string]*latencyHistogram
+}
+
+// newResolverStatsWindow creates an empty window of the given length in whole minutes
+func newResolverStatsWindow(window time.Duration) *resolverStatsWindow {
+	minutes := int((window + resolverStatsSlot - 1) / resolverStatsSlot)
+	return &resolverStatsWindow{slots: make([]resolverStatsSlotCalls, max(minutes, 1))}
+}
+
+// record counts a call of a resolver completing at now
+func (w *resolverStatsWindow) record(resolver string, duration time.Duration, failed bool, now time.Time) {
+	start := now.Truncate(resolverStatsSlot)
+	index := int(start.Unix()/int64(resolverStatsSlot/time.Second)) % len(w.slots)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	slot := &w.slots[index]
+	if !slot.start.Equal(start) {
+		*slot = resolverStatsSlotCalls{start: start, resolvers: make(map[string]*latencyHistogram)}
+	}
+	histogram, ok := slot.resolvers[resolver]
+	if !ok {
+		histogram = &latencyHistogram{}
+		slot.resolvers[resolver] = histogram
+	}
+	histogram.bins[latencyBin(duration)]++
+	histogram.calls++
+	if failed {
+		histogram.errors++
+	}
+}
+
+// stats returns the statistics per resolver of the calls in the last minutes up to now, ordered by resolver
+func (w *resolverStatsWindow) stats(minutes int, now time.Time) []*generated.ResolverStats {
+	current := now.Truncate(resolverStatsSlot)
+	oldest := current.Add(-time.Duration(minutes-1) * resolverStatsSlot)
+
+	merged := make(map[string]*latencyHistogram)
+	w.mu.Lock()
+	for _, slot := range w.slots {
+		if slot.start.Before(oldest) || slot.start.After(current) {
+			continue
+		}
+		for resolver, histogram := range slot.resolvers {
+			if merged[resolver] == nil {
+				merged[resolver] = &latencyHistogram{}
+			}
+			merged[resolver].add(histogram)
+		}
+	}
+	w.mu.Unlock()
+
+	stats := make([]*generated.ResolverStats, 0, len(merged))
+	for resolver, histogram := range merged {
+		stats = append(stats, &generated.ResolverStats{
+			Resolver: resolver,
+			Calls:    histogram.calls,
+			Errors:   histogram.errors,
+			P50Ms:    milliseconds(histogram.percentile(0.50)),
+			P95Ms:    milliseconds(histogram.percentile(0.95)),
+			P99Ms:    milliseconds(histogram.percentile(0.99)),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Resolver < stats[j].Resolver })
+	return stats
+}
+
+// minutes returns the length of the window in minutes
+func (w *resolverStatsWindow) minutes() int {
+	return len(w.slots)
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// resolverStatsMu guards replacing resolverStats
+var resolverStatsMu sync.RWMutex
+
+// resolverStats are the calls of the root resolvers in the current window
+var resolverStats = newResolverStatsWindow(DefaultResolverStatsWindow)
+
+// SetResolverStatsWindow sets how long resolver calls are kept for the resolverStats query,
+// rounded up to whole minutes. Setting the window, as loading the configuration does, discards
+// the recorded calls. Returns an error outside 1 minute to MaxResolverStatsWindow
+func SetResolverStatsWindow(window time.Duration) error {
+	if window < resolverStatsSlot || window > MaxResolverStatsWindow {
+		return fmt.Errorf("resolver stats window must be between %s and %s, got %s", resolverStatsSlot, MaxResolverStatsWindow, window)
+	}
+	resolverStatsMu.Lock()
+	defer resolverStatsMu.Unlock()
+	resolverStats = newResolverStatsWindow(window)
+	return nil
+}
+
+// currentResolverStats returns the window calls are recorded in
+func currentResolverStats() *resolverStatsWindow {
+	resolverStatsMu.RLock()
+	defer resolverStatsMu.RUnlock()
+	return resolverStats
+}
+
+// RecordResolverStats is a gqlgen field middleware timing the Query and Mutation fields for the
+// resolverStats query; other fields pass through
+func RecordResolverStats(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || (fc.Object != "Query" && fc.Object != "Mutation") {
+		return next(ctx)
+	}
+
+	startTime := queryClock.Now()
+	result, err := next(ctx)
+	currentResolverStats().record(fc.Field.Name, queryDuration(startTime), err != nil, queryClock.Now())
+	return result, err
+}
+
+// resolveResolverStats implements the resolverStats query resolver (admin only)
+func resolveResolverStats(ctx context.Context, minutes *int) ([]*generated.ResolverStats, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	window := currentResolverStats()
+	span := window.minutes()
+	if minutes != nil {
+		if *minutes < 1 || *minutes > span {
+			return nil, NewInvalidInput(fmt.Sprintf("'minutes' must be between 1 and %d", span), "minutes")
+		}
+		span = *minutes
+	}
+	return window.stats(span, queryClock.Now()), nil
+}
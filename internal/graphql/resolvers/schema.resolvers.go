@@ -7,10 +7,9 @@ package resolvers
 
 import (
 	"context"
-	"time"
 
-	"github.com/rs/zerolog/log"
 	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/logger"
 )
 
 // Ping is the resolver for the ping field.
@@ -205,17 +204,17 @@ func (r *mutationResolver) UserSendInvitationAgain(ctx context.Context, userEmai
 
 // CustomerCreate is the resolver for the customerCreate field.
 func (r *mutationResolver) CustomerCreate(ctx context.Context, customerInput generated.CustomerMutationInput) (*generated.Customer, error) {
-	return nil, nil
+	return customerCreate(r, ctx, customerInput)
 }
 
 // CustomerUpdate is the resolver for the customerUpdate field.
 func (r *mutationResolver) CustomerUpdate(ctx context.Context, customerInput generated.CustomerUpdateMutationInput) (*generated.Customer, error) {
-	return nil, nil
+	return customerUpdate(r, ctx, customerInput)
 }
 
 // CustomerDelete is the resolver for the customerDelete field.
 func (r *mutationResolver) CustomerDelete(ctx context.Context, identifier string) (bool, error) {
-	return false, nil
+	return customerDelete(r, ctx, identifier)
 }
 
 // EmployeeCreate is the resolver for the employeeCreate field.
@@ -363,6 +362,36 @@ func (r *queryResolver) Alive(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// ServiceInfo is the resolver for the serviceInfo field.
+func (r *queryResolver) ServiceInfo(ctx context.Context) (*generated.ServiceInfo, error) {
+	return r.Resolver.resolveServiceInfo(), nil
+}
+
+// ServerLimits is the resolver for the serverLimits field.
+func (r *queryResolver) ServerLimits(ctx context.Context) (*generated.ServerLimits, error) {
+	return resolveServerLimits(), nil
+}
+
+// ResolverStats is the resolver for the resolverStats field.
+func (r *queryResolver) ResolverStats(ctx context.Context, minutes *int) ([]*generated.ResolverStats, error) {
+	return resolveResolverStats(ctx, minutes)
+}
+
+// EntityCounts is the resolver for the entityCounts field.
+func (r *queryResolver) EntityCounts(ctx context.Context, refresh *bool) ([]*generated.EntityCount, error) {
+	return resolveEntityCounts(ctx, r.DBClient, refresh)
+}
+
+// AccessAuditSearch is the resolver for the accessAuditSearch field.
+func (r *queryResolver) AccessAuditSearch(ctx context.Context, where *generated.AccessAuditFilterInput, first *int64, after *string) (*generated.QueryOutputOfAccessAuditEntry, error) {
+	return resolveAccessAuditSearch(ctx, r.DBClient, where, first, after)
+}
+
+// EntityExistence is the resolver for the entityExistence field.
+func (r *queryResolver) EntityExistence(ctx context.Context, entity string, identifiers []string) ([]*generated.EntityExistence, error) {
+	return resolveEntityExistence(ctx, r.DBClient, entity, identifiers)
+}
+
 // Health is the resolver for the health field.
 func (r *queryResolver) Health(ctx context.Context) (*generated.Health, error) {
 	return r.Resolver.resolveHealth(ctx)
@@ -406,10 +435,13 @@ func (r *queryResolver) DocumentMetadataGet(ctx context.Context) ([]*generated.B
 
 // T035: ReferencePortfolioGet resolver using generic getEntity function
 func (r *queryResolver) ReferencePortfolioGet(ctx context.Context, identifier string) (*generated.ReferencePortfolioOutput, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	var err error
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
+		if err != nil {
+			logQueryError(ctx, "referencePortfolioGet", err, duration)
+		}
 		logQueryExecution(ctx, "referencePortfolioGet", duration, err == nil)
 	}()
 
@@ -427,21 +459,37 @@ func (r *queryResolver) ReferencePortfolioGet(ctx context.Context, identifier st
 	return &portfolio, nil
 }
 
+// ReferencePortfolioGetConnection is the resolver for the referencePortfolioGetConnection field.
+func (r *queryResolver) ReferencePortfolioGetConnection(ctx context.Context, identifier string) (*generated.QueryOutputOfReferencePortfolioOutput, error) {
+	entity, err := r.ReferencePortfolioGet(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	data := getConnectionData(entity)
+	return &generated.QueryOutputOfReferencePortfolioOutput{
+		Count:      int64(len(data)),
+		Data:       data,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(data)),
+	}, nil
+}
+
 // T065: ReferencePortfolioByKeysGet resolver (no sorter - default to identifier ordering)
 func (r *queryResolver) ReferencePortfolioByKeysGet(ctx context.Context, identifiers []string, order []*generated.ReferencePortfolioQuerySorterInput) ([]*generated.ReferencePortfolioOutput, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	identifierCount := len(identifiers)
 	var resultCount int
 	var err error
 
 	defer func() {
-		duration := time.Since(startTime).Milliseconds()
+		duration := queryDuration(startTime).Milliseconds()
 		if err != nil {
-			log.Error().Err(err).Int("identifierCount", identifierCount).
+			logger.FromContext(ctx).Error().Err(err).Func(queryErrorFields(err)).Int("identifierCount", identifierCount).
 				Int64("duration", duration).Str("query", "referencePortfolioByKeysGet").
 				Msg("referencePortfolioByKeysGet query failed")
 		} else {
-			log.Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
+			logger.FromContext(ctx).Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
 				Int64("duration", duration).Str("query", "referencePortfolioByKeysGet").
 				Msg("referencePortfolioByKeysGet query completed")
 		}
@@ -459,21 +507,44 @@ func (r *queryResolver) ReferencePortfolioByKeysGet(ctx context.Context, identif
 	return portfolios, nil
 }
 
+// ReferencePortfolioByKeysGetDetailed is the resolver for the referencePortfolioByKeysGetDetailed field.
+func (r *queryResolver) ReferencePortfolioByKeysGetDetailed(ctx context.Context, identifiers []string, order []*generated.ReferencePortfolioQuerySorterInput) (*generated.ReferencePortfolioByKeysResult, error) {
+	portfolios, err := r.ReferencePortfolioByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.ReferencePortfolioByKeysResult{
+		Data:    portfolios,
+		Missing: missingIdentifiers(identifiers, portfolios, func(portfolio *generated.ReferencePortfolioOutput) string { return portfolio.Identifier }),
+	}, nil
+}
+
+// ReferencePortfolioByKeysGetConnection is the resolver for the referencePortfolioByKeysGetConnection field.
+func (r *queryResolver) ReferencePortfolioByKeysGetConnection(ctx context.Context, identifiers []string, order []*generated.ReferencePortfolioQuerySorterInput) (*generated.QueryOutputOfReferencePortfolioOutput, error) {
+	portfolios, err := r.ReferencePortfolioByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.QueryOutputOfReferencePortfolioOutput{
+		Count:      int64(len(portfolios)),
+		Data:       portfolios,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(portfolios)),
+	}, nil
+}
+
 // ReferencePortfolioSearch is the resolver for the referencePortfolioSearch field.
 // T031: ReferencePortfolioSearch resolver using generic searchEntities function
-func (r *queryResolver) ReferencePortfolioSearch(ctx context.Context, where *generated.ReferencePortfolioQueryFilterInput, order []*generated.ReferencePortfolioQuerySorterInput, first *int64, after *string, last *int64, before *string) (*generated.QueryOutputOfReferencePortfolioOutput, error) {
-	startTime := time.Now()
+func (r *queryResolver) ReferencePortfolioSearch(ctx context.Context, where *generated.ReferencePortfolioQueryFilterInput, order []*generated.ReferencePortfolioQuerySorterInput, first *int64, after *string, last *int64, before *string, snapshot *bool, includeHeavyFields *bool) (*generated.QueryOutputOfReferencePortfolioOutput, error) {
+	startTime := queryClock.Now()
 	var err error
 
-	// Convert int64 pointers to int pointers
-	var firstInt, lastInt *int
-	if first != nil {
-		temp := int(*first)
-		firstInt = &temp
-	}
-	if last != nil {
-		temp := int(*last)
-		lastInt = &temp
+	// Convert the Long arguments to int once they are known to be in range
+	firstInt, lastInt, err := pageSizeArgs(first, last)
+	if err != nil {
+		return nil, err
 	}
 
 	// Log search start
@@ -483,7 +554,7 @@ func (r *queryResolver) ReferencePortfolioSearch(ctx context.Context, where *gen
 	logSearchStart(ctx, "referencePortfolio", hasFilter, firstInt, lastInt, hasAfter, hasBefore)
 
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
 		if err != nil {
 			logQueryError(ctx, "referencePortfolioSearch", err, duration)
 		}
@@ -498,7 +569,14 @@ func (r *queryResolver) ReferencePortfolioSearch(ctx context.Context, where *gen
 		config,
 		where,
 		order,
-		firstInt, after, lastInt, before,
+		SearchPaging{
+			First:    firstInt,
+			After:    after,
+			Last:     lastInt,
+			Before:   before,
+			Snapshot: snapshot != nil && *snapshot,
+		},
+		SearchOptions{IncludeHeavyFields: includeHeavyFields != nil && *includeHeavyFields},
 		&portfolios,
 	)
 
@@ -507,8 +585,9 @@ func (r *queryResolver) ReferencePortfolioSearch(ctx context.Context, where *gen
 		return nil, err
 	}
 
-	duration := time.Since(startTime)
+	duration := queryDuration(startTime)
 	logSearchResult(ctx, "referencePortfolio", count, totalCount, duration)
+	portfolios, skipped := skipMissingIdentifiers(portfolios)
 
 	pageInfo := &generated.PageInfo{
 		HasNextPage:     hasNextPage,
@@ -518,10 +597,13 @@ func (r *queryResolver) ReferencePortfolioSearch(ctx context.Context, where *gen
 	}
 
 	result := &generated.QueryOutputOfReferencePortfolioOutput{
-		Count:      int64(count),
-		Data:       portfolios,
-		Paging:     pageInfo,
-		TotalCount: int64(totalCount),
+		Count:             int64(count - skipped),
+		Data:              portfolios,
+		Paging:            pageInfo,
+		TotalCount:        int64(totalCount),
+		TotalCountAtStart: totalCountAtStart(after, before, totalCount),
+		AppliedFilter:     appliedFilter(ctx, config, where),
+		Warnings:          searchWarnings(where, order, skipped),
 	}
 
 	return result, nil
@@ -579,10 +661,13 @@ func (r *queryResolver) ReferencePortfolioIncompleteNodesGet(ctx context.Context
 
 // T033: InventoryGet resolver using generic getEntity function
 func (r *queryResolver) InventoryGet(ctx context.Context, identifier string) (*generated.Inventory, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	var err error
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
+		if err != nil {
+			logQueryError(ctx, "inventoryGet", err, duration)
+		}
 		logQueryExecution(ctx, "inventoryGet", duration, err == nil)
 	}()
 
@@ -600,6 +685,39 @@ func (r *queryResolver) InventoryGet(ctx context.Context, identifier string) (*g
 	return &inventory, nil
 }
 
+// InventoryGetConnection is the resolver for the inventoryGetConnection field.
+func (r *queryResolver) InventoryGetConnection(ctx context.Context, identifier string) (*generated.QueryOutputOfInventory, error) {
+	entity, err := r.InventoryGet(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	data := getConnectionData(entity)
+	return &generated.QueryOutputOfInventory{
+		Count:      int64(len(data)),
+		Data:       data,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(data)),
+	}, nil
+}
+
+// InventoryByNumberGet is the resolver for the inventoryByNumberGet field.
+func (r *queryResolver) InventoryByNumberGet(ctx context.Context, number string) (*generated.Inventory, error) {
+	startTime := queryClock.Now()
+	var err error
+	defer func() {
+		duration := queryDuration(startTime)
+		if err != nil {
+			logQueryError(ctx, "inventoryByNumberGet", err, duration)
+		}
+		logQueryExecution(ctx, "inventoryByNumberGet", duration, err == nil)
+	}()
+
+	var inventory *generated.Inventory
+	inventory, err = getInventoryByNumber(ctx, r.DBClient, number)
+	return inventory, err
+}
+
 // InventoryForCustomerGet is the resolver for the inventoryForCustomerGet field.
 func (r *queryResolver) InventoryForCustomerGet(ctx context.Context, customerID string) (*generated.Inventory, error) {
 	return nil, nil
@@ -617,19 +735,19 @@ func (r *queryResolver) InventoryDownloadAttachment(ctx context.Context, attachm
 
 // T063: InventoryByKeysGet resolver using generic getEntitiesByKeys function
 func (r *queryResolver) ByKeysGet(ctx context.Context, identifiers []string, order []*generated.InventoryQuerySorterInput) ([]*generated.Inventory, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	identifierCount := len(identifiers)
 	var resultCount int
 	var err error
 
 	defer func() {
-		duration := time.Since(startTime).Milliseconds()
+		duration := queryDuration(startTime).Milliseconds()
 		if err != nil {
-			log.Error().Err(err).Int("identifierCount", identifierCount).
+			logger.FromContext(ctx).Error().Err(err).Func(queryErrorFields(err)).Int("identifierCount", identifierCount).
 				Int64("duration", duration).Str("query", "byKeysGet").
 				Msg("byKeysGet query failed")
 		} else {
-			log.Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
+			logger.FromContext(ctx).Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
 				Int64("duration", duration).Str("query", "byKeysGet").
 				Msg("byKeysGet query completed")
 		}
@@ -646,19 +764,118 @@ func (r *queryResolver) ByKeysGet(ctx context.Context, identifiers []string, ord
 	return inventories, nil
 }
 
+// ByKeysGetDetailed is the resolver for the byKeysGetDetailed field.
+func (r *queryResolver) ByKeysGetDetailed(ctx context.Context, identifiers []string, order []*generated.InventoryQuerySorterInput) (*generated.InventoryByKeysResult, error) {
+	inventories, err := r.ByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.InventoryByKeysResult{
+		Data:    inventories,
+		Missing: missingIdentifiers(identifiers, inventories, func(inventory *generated.Inventory) string { return inventory.Identifier }),
+	}, nil
+}
+
+// ByKeysGetConnection is the resolver for the byKeysGetConnection field.
+func (r *queryResolver) ByKeysGetConnection(ctx context.Context, identifiers []string, order []*generated.InventoryQuerySorterInput) (*generated.QueryOutputOfInventory, error) {
+	inventories, err := r.ByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.QueryOutputOfInventory{
+		Count:      int64(len(inventories)),
+		Data:       inventories,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(inventories)),
+	}, nil
+}
+
 // Note: ByKeysGet was previously implemented in inventory.go
 
 // Search is the resolver for the search field.
-func (r *queryResolver) Search(ctx context.Context, where *generated.InventoryQueryFilterInput, order []*generated.InventoryQuerySorterInput, first *int64, after *string, last *int64, before *string) (*generated.QueryOutputOfInventory, error) {
-	return nil, nil
+func (r *queryResolver) Search(ctx context.Context, where *generated.InventoryQueryFilterInput, order []*generated.InventoryQuerySorterInput, first *int64, after *string, last *int64, before *string, snapshot *bool) (*generated.QueryOutputOfInventory, error) {
+	startTime := queryClock.Now()
+	var err error
+
+	// Convert the Long arguments to int once they are known to be in range
+	firstInt, lastInt, err := pageSizeArgs(first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	// Log search start
+	hasFilter := where != nil
+	hasAfter := after != nil && *after != ""
+	hasBefore := before != nil && *before != ""
+	logSearchStart(ctx, "inventory", hasFilter, firstInt, lastInt, hasAfter, hasBefore)
+
+	defer func() {
+		duration := queryDuration(startTime)
+		if err != nil {
+			logQueryError(ctx, "search", err, duration)
+		}
+	}()
+
+	config := entityConfigs["inventory"]
+	var inventories []*generated.Inventory
+
+	count, totalCount, hasNextPage, hasPreviousPage, startCursor, endCursor, searchErr := searchEntities(
+		ctx,
+		r.DBClient,
+		config,
+		where,
+		order,
+		SearchPaging{
+			First:    firstInt,
+			After:    after,
+			Last:     lastInt,
+			Before:   before,
+			Snapshot: snapshot != nil && *snapshot,
+		},
+		SearchOptions{},
+		&inventories,
+	)
+
+	if searchErr != nil {
+		err = searchErr
+		return nil, err
+	}
+
+	duration := queryDuration(startTime)
+	logSearchResult(ctx, "inventory", count, totalCount, duration)
+	inventories, skipped := skipMissingIdentifiers(inventories)
+
+	pageInfo := &generated.PageInfo{
+		HasNextPage:     hasNextPage,
+		HasPreviousPage: hasPreviousPage,
+		StartCursor:     startCursor,
+		EndCursor:       endCursor,
+	}
+
+	result := &generated.QueryOutputOfInventory{
+		Count:             int64(count - skipped),
+		Data:              inventories,
+		Paging:            pageInfo,
+		TotalCount:        int64(totalCount),
+		TotalCountAtStart: totalCountAtStart(after, before, totalCount),
+		AppliedFilter:     appliedFilter(ctx, config, where),
+		Warnings:          searchWarnings(where, order, skipped),
+	}
+
+	return result, nil
 }
 
 // T034: ExecutionPlanGet resolver using generic getEntity function
 func (r *queryResolver) ExecutionPlanGet(ctx context.Context, identifier string) (*generated.ExecutionPlan, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	var err error
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
+		if err != nil {
+			logQueryError(ctx, "executionPlanGet", err, duration)
+		}
 		logQueryExecution(ctx, "executionPlanGet", duration, err == nil)
 	}()
 
@@ -676,21 +893,37 @@ func (r *queryResolver) ExecutionPlanGet(ctx context.Context, identifier string)
 	return &executionPlan, nil
 }
 
+// ExecutionPlanGetConnection is the resolver for the executionPlanGetConnection field.
+func (r *queryResolver) ExecutionPlanGetConnection(ctx context.Context, identifier string) (*generated.QueryOutputOfExecutionPlan, error) {
+	entity, err := r.ExecutionPlanGet(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	data := getConnectionData(entity)
+	return &generated.QueryOutputOfExecutionPlan{
+		Count:      int64(len(data)),
+		Data:       data,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(data)),
+	}, nil
+}
+
 // T064: ExecutionPlanByKeysGet resolver (no sorter - default to identifier ordering)
 func (r *queryResolver) ExecutionPlanByKeysGet(ctx context.Context, identifiers []string, order []*generated.ExecutionPlanQuerySorterInput) ([]*generated.ExecutionPlan, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	identifierCount := len(identifiers)
 	var resultCount int
 	var err error
 
 	defer func() {
-		duration := time.Since(startTime).Milliseconds()
+		duration := queryDuration(startTime).Milliseconds()
 		if err != nil {
-			log.Error().Err(err).Int("identifierCount", identifierCount).
+			logger.FromContext(ctx).Error().Err(err).Func(queryErrorFields(err)).Int("identifierCount", identifierCount).
 				Int64("duration", duration).Str("query", "executionPlanByKeysGet").
 				Msg("executionPlanByKeysGet query failed")
 		} else {
-			log.Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
+			logger.FromContext(ctx).Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
 				Int64("duration", duration).Str("query", "executionPlanByKeysGet").
 				Msg("executionPlanByKeysGet query completed")
 		}
@@ -708,21 +941,44 @@ func (r *queryResolver) ExecutionPlanByKeysGet(ctx context.Context, identifiers
 	return executionPlans, nil
 }
 
+// ExecutionPlanByKeysGetDetailed is the resolver for the executionPlanByKeysGetDetailed field.
+func (r *queryResolver) ExecutionPlanByKeysGetDetailed(ctx context.Context, identifiers []string, order []*generated.ExecutionPlanQuerySorterInput) (*generated.ExecutionPlanByKeysResult, error) {
+	plans, err := r.ExecutionPlanByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.ExecutionPlanByKeysResult{
+		Data:    plans,
+		Missing: missingIdentifiers(identifiers, plans, func(plan *generated.ExecutionPlan) string { return plan.Identifier }),
+	}, nil
+}
+
+// ExecutionPlanByKeysGetConnection is the resolver for the executionPlanByKeysGetConnection field.
+func (r *queryResolver) ExecutionPlanByKeysGetConnection(ctx context.Context, identifiers []string, order []*generated.ExecutionPlanQuerySorterInput) (*generated.QueryOutputOfExecutionPlan, error) {
+	plans, err := r.ExecutionPlanByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.QueryOutputOfExecutionPlan{
+		Count:      int64(len(plans)),
+		Data:       plans,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(plans)),
+	}, nil
+}
+
 // ExecutionPlanSearch is the resolver for the executionPlanSearch field.
 // T030: ExecutionPlanSearch resolver using generic searchEntities function
-func (r *queryResolver) ExecutionPlanSearch(ctx context.Context, where *generated.ExecutionPlanQueryFilterInput, order []*generated.ExecutionPlanQuerySorterInput, first *int64, after *string, last *int64, before *string) (*generated.QueryOutputOfExecutionPlan, error) {
-	startTime := time.Now()
+func (r *queryResolver) ExecutionPlanSearch(ctx context.Context, where *generated.ExecutionPlanQueryFilterInput, order []*generated.ExecutionPlanQuerySorterInput, first *int64, after *string, last *int64, before *string, snapshot *bool, includeHeavyFields *bool) (*generated.QueryOutputOfExecutionPlan, error) {
+	startTime := queryClock.Now()
 	var err error
 
-	// Convert int64 pointers to int pointers
-	var firstInt, lastInt *int
-	if first != nil {
-		temp := int(*first)
-		firstInt = &temp
-	}
-	if last != nil {
-		temp := int(*last)
-		lastInt = &temp
+	// Convert the Long arguments to int once they are known to be in range
+	firstInt, lastInt, err := pageSizeArgs(first, last)
+	if err != nil {
+		return nil, err
 	}
 
 	// Log search start
@@ -732,7 +988,7 @@ func (r *queryResolver) ExecutionPlanSearch(ctx context.Context, where *generate
 	logSearchStart(ctx, "executionPlan", hasFilter, firstInt, lastInt, hasAfter, hasBefore)
 
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
 		if err != nil {
 			logQueryError(ctx, "executionPlanSearch", err, duration)
 		}
@@ -747,7 +1003,14 @@ func (r *queryResolver) ExecutionPlanSearch(ctx context.Context, where *generate
 		config,
 		where,
 		order,
-		firstInt, after, lastInt, before,
+		SearchPaging{
+			First:    firstInt,
+			After:    after,
+			Last:     lastInt,
+			Before:   before,
+			Snapshot: snapshot != nil && *snapshot,
+		},
+		SearchOptions{IncludeHeavyFields: includeHeavyFields != nil && *includeHeavyFields},
 		&executionPlans,
 	)
 
@@ -756,8 +1019,9 @@ func (r *queryResolver) ExecutionPlanSearch(ctx context.Context, where *generate
 		return nil, err
 	}
 
-	duration := time.Since(startTime)
+	duration := queryDuration(startTime)
 	logSearchResult(ctx, "executionPlan", count, totalCount, duration)
+	executionPlans, skipped := skipMissingIdentifiers(executionPlans)
 
 	pageInfo := &generated.PageInfo{
 		HasNextPage:     hasNextPage,
@@ -767,10 +1031,13 @@ func (r *queryResolver) ExecutionPlanSearch(ctx context.Context, where *generate
 	}
 
 	result := &generated.QueryOutputOfExecutionPlan{
-		Count:      int64(count),
-		Data:       executionPlans,
-		Paging:     pageInfo,
-		TotalCount: int64(totalCount),
+		Count:             int64(count - skipped),
+		Data:              executionPlans,
+		Paging:            pageInfo,
+		TotalCount:        int64(totalCount),
+		TotalCountAtStart: totalCountAtStart(after, before, totalCount),
+		AppliedFilter:     appliedFilter(ctx, config, where),
+		Warnings:          searchWarnings(where, order, skipped),
 	}
 
 	return result, nil
@@ -818,10 +1085,13 @@ func (r *queryResolver) OtherUserSigninActivitiesGet(ctx context.Context, identi
 
 // T030: CustomerGet resolver using generic getEntity function
 func (r *queryResolver) CustomerGet(ctx context.Context, identifier string) (*generated.Customer, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	var err error
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
+		if err != nil {
+			logQueryError(ctx, "customerGet", err, duration)
+		}
 		logQueryExecution(ctx, "customerGet", duration, err == nil)
 	}()
 
@@ -840,21 +1110,37 @@ func (r *queryResolver) CustomerGet(ctx context.Context, identifier string) (*ge
 	return &customer, nil
 }
 
+// CustomerGetConnection is the resolver for the customerGetConnection field.
+func (r *queryResolver) CustomerGetConnection(ctx context.Context, identifier string) (*generated.QueryOutputOfCustomer, error) {
+	entity, err := r.CustomerGet(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	data := getConnectionData(entity)
+	return &generated.QueryOutputOfCustomer{
+		Count:      int64(len(data)),
+		Data:       data,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(data)),
+	}, nil
+}
+
 // T060: CustomerByKeysGet resolver using generic getEntitiesByKeys function
 func (r *queryResolver) CustomerByKeysGet(ctx context.Context, identifiers []string, order []*generated.CustomerQuerySorterInput) ([]*generated.Customer, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	identifierCount := len(identifiers)
 	var resultCount int
 	var err error
 
 	defer func() {
-		duration := time.Since(startTime).Milliseconds()
+		duration := queryDuration(startTime).Milliseconds()
 		if err != nil {
-			log.Error().Err(err).Int("identifierCount", identifierCount).
+			logger.FromContext(ctx).Error().Err(err).Func(queryErrorFields(err)).Int("identifierCount", identifierCount).
 				Int64("duration", duration).Str("query", "customerByKeysGet").
 				Msg("customerByKeysGet query failed")
 		} else {
-			log.Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
+			logger.FromContext(ctx).Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
 				Int64("duration", duration).Str("query", "customerByKeysGet").
 				Msg("customerByKeysGet query completed")
 		}
@@ -871,21 +1157,44 @@ func (r *queryResolver) CustomerByKeysGet(ctx context.Context, identifiers []str
 	return customers, nil
 }
 
+// CustomerByKeysGetDetailed is the resolver for the customerByKeysGetDetailed field.
+func (r *queryResolver) CustomerByKeysGetDetailed(ctx context.Context, identifiers []string, order []*generated.CustomerQuerySorterInput) (*generated.CustomerByKeysResult, error) {
+	customers, err := r.CustomerByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.CustomerByKeysResult{
+		Data:    customers,
+		Missing: missingIdentifiers(identifiers, customers, func(customer *generated.Customer) string { return customer.Identifier }),
+	}, nil
+}
+
+// CustomerByKeysGetConnection is the resolver for the customerByKeysGetConnection field.
+func (r *queryResolver) CustomerByKeysGetConnection(ctx context.Context, identifiers []string, order []*generated.CustomerQuerySorterInput) (*generated.QueryOutputOfCustomer, error) {
+	customers, err := r.CustomerByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.QueryOutputOfCustomer{
+		Count:      int64(len(customers)),
+		Data:       customers,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(customers)),
+	}, nil
+}
+
 // CustomerSearch is the resolver for the customerSearch field.
 // T027: Implement CustomerSearch resolver using generic searchEntities function
-func (r *queryResolver) CustomerSearch(ctx context.Context, where *generated.CustomerQueryFilterInput, order []*generated.CustomerQuerySorterInput, first *int64, after *string, last *int64, before *string) (*generated.QueryOutputOfCustomer, error) {
-	startTime := time.Now()
+func (r *queryResolver) CustomerSearch(ctx context.Context, where *generated.CustomerQueryFilterInput, order []*generated.CustomerQuerySorterInput, first *int64, after *string, last *int64, before *string, snapshot *bool, includeHeavyFields *bool, facets []generated.CustomerFacetField) (*generated.QueryOutputOfCustomer, error) {
+	startTime := queryClock.Now()
 	var err error
 
-	// Convert int64 pointers to int pointers for searchEntities
-	var firstInt, lastInt *int
-	if first != nil {
-		temp := int(*first)
-		firstInt = &temp
-	}
-	if last != nil {
-		temp := int(*last)
-		lastInt = &temp
+	// Convert the Long arguments to int once they are known to be in range
+	firstInt, lastInt, err := pageSizeArgs(first, last)
+	if err != nil {
+		return nil, err
 	}
 
 	// Log search start
@@ -895,7 +1204,7 @@ func (r *queryResolver) CustomerSearch(ctx context.Context, where *generated.Cus
 	logSearchStart(ctx, "customer", hasFilter, firstInt, lastInt, hasAfter, hasBefore)
 
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
 		if err != nil {
 			logQueryError(ctx, "customerSearch", err, duration)
 		}
@@ -904,6 +1213,12 @@ func (r *queryResolver) CustomerSearch(ctx context.Context, where *generated.Cus
 	// Get entity configuration
 	config := entityConfigs["customer"]
 
+	// Count the requested facets in the search's aggregation
+	ctx, counted, err := withSearchFacets(ctx, config, facets)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare result slice
 	var customers []*generated.Customer
 
@@ -914,7 +1229,14 @@ func (r *queryResolver) CustomerSearch(ctx context.Context, where *generated.Cus
 		config,
 		where,
 		order,
-		firstInt, after, lastInt, before,
+		SearchPaging{
+			First:    firstInt,
+			After:    after,
+			Last:     lastInt,
+			Before:   before,
+			Snapshot: snapshot != nil && *snapshot,
+		},
+		SearchOptions{IncludeHeavyFields: includeHeavyFields != nil && *includeHeavyFields},
 		&customers,
 	)
 
@@ -924,8 +1246,9 @@ func (r *queryResolver) CustomerSearch(ctx context.Context, where *generated.Cus
 	}
 
 	// Log search result
-	duration := time.Since(startTime)
+	duration := queryDuration(startTime)
 	logSearchResult(ctx, "customer", count, totalCount, duration)
+	customers, skipped := skipMissingIdentifiers(customers)
 
 	// Build PageInfo
 	pageInfo := &generated.PageInfo{
@@ -937,15 +1260,24 @@ func (r *queryResolver) CustomerSearch(ctx context.Context, where *generated.Cus
 
 	// Build and return QueryOutputOfCustomer
 	result := &generated.QueryOutputOfCustomer{
-		Count:      int64(count),
-		Data:       customers,
-		Paging:     pageInfo,
-		TotalCount: int64(totalCount),
+		Count:             int64(count - skipped),
+		Data:              customers,
+		Paging:            pageInfo,
+		TotalCount:        int64(totalCount),
+		TotalCountAtStart: totalCountAtStart(after, before, totalCount),
+		AppliedFilter:     appliedFilter(ctx, config, where),
+		Warnings:          searchWarnings(where, order, skipped),
+		Facets:            counted.list(),
 	}
 
 	return result, nil
 }
 
+// CustomerHistory is the resolver for the customerHistory field.
+func (r *queryResolver) CustomerHistory(ctx context.Context, identifier string, first *int64, after *string) (*generated.QueryOutputOfCustomerHistoryEntry, error) {
+	return resolveCustomerHistory(ctx, r.DBClient, identifier, first, after)
+}
+
 // CustomerGetCrispIdentity is the resolver for the customerGetCrispIdentity field.
 func (r *queryResolver) CustomerGetCrispIdentity(ctx context.Context) (*generated.CrispIdentity, error) {
 	return nil, nil
@@ -953,10 +1285,13 @@ func (r *queryResolver) CustomerGetCrispIdentity(ctx context.Context) (*generate
 
 // T031: EmployeeGet resolver using generic getEntity function
 func (r *queryResolver) EmployeeGet(ctx context.Context, identifier string) (*generated.Employee, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	var err error
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
+		if err != nil {
+			logQueryError(ctx, "employeeGet", err, duration)
+		}
 		logQueryExecution(ctx, "employeeGet", duration, err == nil)
 	}()
 
@@ -974,21 +1309,37 @@ func (r *queryResolver) EmployeeGet(ctx context.Context, identifier string) (*ge
 	return &employee, nil
 }
 
+// EmployeeGetConnection is the resolver for the employeeGetConnection field.
+func (r *queryResolver) EmployeeGetConnection(ctx context.Context, identifier string) (*generated.QueryOutputOfEmployee, error) {
+	entity, err := r.EmployeeGet(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	data := getConnectionData(entity)
+	return &generated.QueryOutputOfEmployee{
+		Count:      int64(len(data)),
+		Data:       data,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(data)),
+	}, nil
+}
+
 // T061: EmployeeByKeysGet resolver using generic getEntitiesByKeys function
 func (r *queryResolver) EmployeeByKeysGet(ctx context.Context, identifiers []string, order []*generated.EmployeeQuerySorterInput) ([]*generated.Employee, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	identifierCount := len(identifiers)
 	var resultCount int
 	var err error
 
 	defer func() {
-		duration := time.Since(startTime).Milliseconds()
+		duration := queryDuration(startTime).Milliseconds()
 		if err != nil {
-			log.Error().Err(err).Int("identifierCount", identifierCount).
+			logger.FromContext(ctx).Error().Err(err).Func(queryErrorFields(err)).Int("identifierCount", identifierCount).
 				Int64("duration", duration).Str("query", "employeeByKeysGet").
 				Msg("employeeByKeysGet query failed")
 		} else {
-			log.Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
+			logger.FromContext(ctx).Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
 				Int64("duration", duration).Str("query", "employeeByKeysGet").
 				Msg("employeeByKeysGet query completed")
 		}
@@ -1005,21 +1356,44 @@ func (r *queryResolver) EmployeeByKeysGet(ctx context.Context, identifiers []str
 	return employees, nil
 }
 
+// EmployeeByKeysGetDetailed is the resolver for the employeeByKeysGetDetailed field.
+func (r *queryResolver) EmployeeByKeysGetDetailed(ctx context.Context, identifiers []string, order []*generated.EmployeeQuerySorterInput) (*generated.EmployeeByKeysResult, error) {
+	employees, err := r.EmployeeByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.EmployeeByKeysResult{
+		Data:    employees,
+		Missing: missingIdentifiers(identifiers, employees, func(employee *generated.Employee) string { return employee.Identifier }),
+	}, nil
+}
+
+// EmployeeByKeysGetConnection is the resolver for the employeeByKeysGetConnection field.
+func (r *queryResolver) EmployeeByKeysGetConnection(ctx context.Context, identifiers []string, order []*generated.EmployeeQuerySorterInput) (*generated.QueryOutputOfEmployee, error) {
+	employees, err := r.EmployeeByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.QueryOutputOfEmployee{
+		Count:      int64(len(employees)),
+		Data:       employees,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(employees)),
+	}, nil
+}
+
 // EmployeeSearch is the resolver for the employeeSearch field.
 // T028: EmployeeSearch resolver using generic searchEntities function
-func (r *queryResolver) EmployeeSearch(ctx context.Context, where *generated.EmployeeQueryFilterInput, order []*generated.EmployeeQuerySorterInput, first *int64, after *string, last *int64, before *string) (*generated.QueryOutputOfEmployee, error) {
-	startTime := time.Now()
+func (r *queryResolver) EmployeeSearch(ctx context.Context, where *generated.EmployeeQueryFilterInput, order []*generated.EmployeeQuerySorterInput, first *int64, after *string, last *int64, before *string, snapshot *bool, includeHeavyFields *bool) (*generated.QueryOutputOfEmployee, error) {
+	startTime := queryClock.Now()
 	var err error
 
-	// Convert int64 pointers to int pointers
-	var firstInt, lastInt *int
-	if first != nil {
-		temp := int(*first)
-		firstInt = &temp
-	}
-	if last != nil {
-		temp := int(*last)
-		lastInt = &temp
+	// Convert the Long arguments to int once they are known to be in range
+	firstInt, lastInt, err := pageSizeArgs(first, last)
+	if err != nil {
+		return nil, err
 	}
 
 	// Log search start
@@ -1029,7 +1403,7 @@ func (r *queryResolver) EmployeeSearch(ctx context.Context, where *generated.Emp
 	logSearchStart(ctx, "employee", hasFilter, firstInt, lastInt, hasAfter, hasBefore)
 
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
 		if err != nil {
 			logQueryError(ctx, "employeeSearch", err, duration)
 		}
@@ -1044,7 +1418,14 @@ func (r *queryResolver) EmployeeSearch(ctx context.Context, where *generated.Emp
 		config,
 		where,
 		order,
-		firstInt, after, lastInt, before,
+		SearchPaging{
+			First:    firstInt,
+			After:    after,
+			Last:     lastInt,
+			Before:   before,
+			Snapshot: snapshot != nil && *snapshot,
+		},
+		SearchOptions{IncludeHeavyFields: includeHeavyFields != nil && *includeHeavyFields},
 		&employees,
 	)
 
@@ -1053,8 +1434,9 @@ func (r *queryResolver) EmployeeSearch(ctx context.Context, where *generated.Emp
 		return nil, err
 	}
 
-	duration := time.Since(startTime)
+	duration := queryDuration(startTime)
 	logSearchResult(ctx, "employee", count, totalCount, duration)
+	employees, skipped := skipMissingIdentifiers(employees)
 
 	pageInfo := &generated.PageInfo{
 		HasNextPage:     hasNextPage,
@@ -1064,10 +1446,13 @@ func (r *queryResolver) EmployeeSearch(ctx context.Context, where *generated.Emp
 	}
 
 	result := &generated.QueryOutputOfEmployee{
-		Count:      int64(count),
-		Data:       employees,
-		Paging:     pageInfo,
-		TotalCount: int64(totalCount),
+		Count:             int64(count - skipped),
+		Data:              employees,
+		Paging:            pageInfo,
+		TotalCount:        int64(totalCount),
+		TotalCountAtStart: totalCountAtStart(after, before, totalCount),
+		AppliedFilter:     appliedFilter(ctx, config, where),
+		Warnings:          searchWarnings(where, order, skipped),
 	}
 
 	return result, nil
@@ -1100,10 +1485,13 @@ func (r *queryResolver) EmployeeTeamMembersForTeamGet(ctx context.Context, teamI
 
 // T032: TeamGet resolver using generic getEntity function
 func (r *queryResolver) TeamGet(ctx context.Context, identifier string) (*generated.TeamQueryOutput, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	var err error
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
+		if err != nil {
+			logQueryError(ctx, "teamGet", err, duration)
+		}
 		logQueryExecution(ctx, "teamGet", duration, err == nil)
 	}()
 
@@ -1121,21 +1509,37 @@ func (r *queryResolver) TeamGet(ctx context.Context, identifier string) (*genera
 	return &team, nil
 }
 
+// TeamGetConnection is the resolver for the teamGetConnection field.
+func (r *queryResolver) TeamGetConnection(ctx context.Context, identifier string) (*generated.QueryOutputOfTeamQueryOutput, error) {
+	entity, err := r.TeamGet(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	data := getConnectionData(entity)
+	return &generated.QueryOutputOfTeamQueryOutput{
+		Count:      int64(len(data)),
+		Data:       data,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(data)),
+	}, nil
+}
+
 // T062: TeamByKeysGet resolver (no sorter - default to identifier ordering)
 func (r *queryResolver) TeamByKeysGet(ctx context.Context, identifiers []string, order []*generated.TeamQuerySorterInput) ([]*generated.TeamQueryOutput, error) {
-	startTime := time.Now()
+	startTime := queryClock.Now()
 	identifierCount := len(identifiers)
 	var resultCount int
 	var err error
 
 	defer func() {
-		duration := time.Since(startTime).Milliseconds()
+		duration := queryDuration(startTime).Milliseconds()
 		if err != nil {
-			log.Error().Err(err).Int("identifierCount", identifierCount).
+			logger.FromContext(ctx).Error().Err(err).Func(queryErrorFields(err)).Int("identifierCount", identifierCount).
 				Int64("duration", duration).Str("query", "teamByKeysGet").
 				Msg("teamByKeysGet query failed")
 		} else {
-			log.Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
+			logger.FromContext(ctx).Info().Int("identifierCount", identifierCount).Int("resultCount", resultCount).
 				Int64("duration", duration).Str("query", "teamByKeysGet").
 				Msg("teamByKeysGet query completed")
 		}
@@ -1153,21 +1557,44 @@ func (r *queryResolver) TeamByKeysGet(ctx context.Context, identifiers []string,
 	return teams, nil
 }
 
+// TeamByKeysGetDetailed is the resolver for the teamByKeysGetDetailed field.
+func (r *queryResolver) TeamByKeysGetDetailed(ctx context.Context, identifiers []string, order []*generated.TeamQuerySorterInput) (*generated.TeamByKeysResult, error) {
+	teams, err := r.TeamByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.TeamByKeysResult{
+		Data:    teams,
+		Missing: missingIdentifiers(identifiers, teams, func(team *generated.TeamQueryOutput) string { return team.Identifier }),
+	}, nil
+}
+
+// TeamByKeysGetConnection is the resolver for the teamByKeysGetConnection field.
+func (r *queryResolver) TeamByKeysGetConnection(ctx context.Context, identifiers []string, order []*generated.TeamQuerySorterInput) (*generated.QueryOutputOfTeamQueryOutput, error) {
+	teams, err := r.TeamByKeysGet(ctx, identifiers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.QueryOutputOfTeamQueryOutput{
+		Count:      int64(len(teams)),
+		Data:       teams,
+		Paging:     byKeysPaging(),
+		TotalCount: int64(len(teams)),
+	}, nil
+}
+
 // TeamSearch is the resolver for the teamSearch field.
 // T029: TeamSearch resolver using generic searchEntities function
-func (r *queryResolver) TeamSearch(ctx context.Context, where *generated.TeamQueryFilterInput, order []*generated.TeamQuerySorterInput, first *int64, after *string, last *int64, before *string) (*generated.QueryOutputOfTeamQueryOutput, error) {
-	startTime := time.Now()
+func (r *queryResolver) TeamSearch(ctx context.Context, where *generated.TeamQueryFilterInput, order []*generated.TeamQuerySorterInput, first *int64, after *string, last *int64, before *string, snapshot *bool, includeHeavyFields *bool) (*generated.QueryOutputOfTeamQueryOutput, error) {
+	startTime := queryClock.Now()
 	var err error
 
-	// Convert int64 pointers to int pointers
-	var firstInt, lastInt *int
-	if first != nil {
-		temp := int(*first)
-		firstInt = &temp
-	}
-	if last != nil {
-		temp := int(*last)
-		lastInt = &temp
+	// Convert the Long arguments to int once they are known to be in range
+	firstInt, lastInt, err := pageSizeArgs(first, last)
+	if err != nil {
+		return nil, err
 	}
 
 	// Log search start
@@ -1177,7 +1604,7 @@ func (r *queryResolver) TeamSearch(ctx context.Context, where *generated.TeamQue
 	logSearchStart(ctx, "team", hasFilter, firstInt, lastInt, hasAfter, hasBefore)
 
 	defer func() {
-		duration := time.Since(startTime)
+		duration := queryDuration(startTime)
 		if err != nil {
 			logQueryError(ctx, "teamSearch", err, duration)
 		}
@@ -1192,7 +1619,14 @@ func (r *queryResolver) TeamSearch(ctx context.Context, where *generated.TeamQue
 		config,
 		where,
 		order,
-		firstInt, after, lastInt, before,
+		SearchPaging{
+			First:    firstInt,
+			After:    after,
+			Last:     lastInt,
+			Before:   before,
+			Snapshot: snapshot != nil && *snapshot,
+		},
+		SearchOptions{IncludeHeavyFields: includeHeavyFields != nil && *includeHeavyFields},
 		&teams,
 	)
 
@@ -1201,8 +1635,9 @@ func (r *queryResolver) TeamSearch(ctx context.Context, where *generated.TeamQue
 		return nil, err
 	}
 
-	duration := time.Since(startTime)
+	duration := queryDuration(startTime)
 	logSearchResult(ctx, "team", count, totalCount, duration)
+	teams, skipped := skipMissingIdentifiers(teams)
 
 	pageInfo := &generated.PageInfo{
 		HasNextPage:     hasNextPage,
@@ -1212,10 +1647,13 @@ func (r *queryResolver) TeamSearch(ctx context.Context, where *generated.TeamQue
 	}
 
 	result := &generated.QueryOutputOfTeamQueryOutput{
-		Count:      int64(count),
-		Data:       teams,
-		Paging:     pageInfo,
-		TotalCount: int64(totalCount),
+		Count:             int64(count - skipped),
+		Data:              teams,
+		Paging:            pageInfo,
+		TotalCount:        int64(totalCount),
+		TotalCountAtStart: totalCountAtStart(after, before, totalCount),
+		AppliedFilter:     appliedFilter(ctx, config, where),
+		Warnings:          searchWarnings(where, order, skipped),
 	}
 
 	return result, nil
@@ -1406,11 +1844,45 @@ func (r *queryResolver) NodeMetadataJSONSchemaGet(ctx context.Context, instanceI
 	return nil, nil
 }
 
+// DisplayName is the resolver for the displayName field.
+func (r *customerResolver) DisplayName(ctx context.Context, obj *generated.Customer) (string, error) {
+	return displayName(obj.FirstName, obj.LastName, obj.UserEmail, obj.Identifier), nil
+}
+
+// Teams is the resolver for the teams field.
+func (r *employeeResolver) Teams(ctx context.Context, obj *generated.Employee, first *int) ([]*generated.TeamQueryOutput, error) {
+	return resolveEmployeeTeams(ctx, r.DBClient, obj, first)
+}
+
+// DisplayName is the resolver for the displayName field.
+func (r *employeeResolver) DisplayName(ctx context.Context, obj *generated.Employee) (string, error) {
+	return displayName(obj.FirstName, obj.LastName, obj.UserEmail, obj.Identifier), nil
+}
+
+// Members is the resolver for the members field.
+func (r *teamQueryOutputResolver) Members(ctx context.Context, obj *generated.TeamQueryOutput, first *int) ([]*generated.Employee, error) {
+	return resolveTeamMembers(ctx, r.DBClient, obj, first)
+}
+
+// Customer returns generated.CustomerResolver implementation.
+func (r *Resolver) Customer() generated.CustomerResolver { return &customerResolver{r} }
+
+// Employee returns generated.EmployeeResolver implementation.
+func (r *Resolver) Employee() generated.EmployeeResolver { return &employeeResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// TeamQueryOutput returns generated.TeamQueryOutputResolver implementation.
+func (r *Resolver) TeamQueryOutput() generated.TeamQueryOutputResolver {
+	return &teamQueryOutputResolver{r}
+}
+
+type customerResolver struct{ *Resolver }
+type employeeResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type teamQueryOutputResolver struct{ *Resolver }
@@ -0,0 +1,123 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+
+	"github.com/yourusername/air-go/internal/health"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// searchLimiter bounds the rows requested by concurrently running searches.
+// A search weighs its effective page size, so a few large unindexed searches cannot hold
+// every pool connection. Point reads (get, byKeys) never pass through it
+type searchLimiter struct {
+	sem      *semaphore.Weighted
+	capacity int64
+	timeout  time.Duration // Longest wait for capacity before a search is rejected
+
+	inUse    atomic.Int64
+	waiting  atomic.Int64
+	rejected atomic.Int64
+}
+
+// newSearchLimiter creates a limiter admitting capacity rows at once
+func newSearchLimiter(capacity int, timeout time.Duration) *searchLimiter {
+	return &searchLimiter{
+		sem:      semaphore.NewWeighted(int64(capacity)),
+		capacity: int64(capacity),
+		timeout:  timeout,
+	}
+}
+
+// acquire waits until a search of the given page size may run and returns its release function.
+// Page sizes above the capacity weigh the whole capacity, so such a search runs alone.
+// A search whose own context ends while waiting gets the context's error; only running out of
+// the queue timeout is a rejection
+func (l *searchLimiter) acquire(ctx context.Context, rows int) (func(), error) {
+	weight := int64(rows)
+	if weight < 1 {
+		weight = 1
+	}
+	if weight > l.capacity {
+		weight = l.capacity
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	l.waiting.Add(1)
+	err := l.sem.Acquire(waitCtx, weight)
+	l.waiting.Add(-1)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		l.rejected.Add(1)
+		logger.FromContext(ctx).Warn().
+			Int64("weight", weight).
+			Int64("in_use", l.inUse.Load()).
+			Int64("max", l.capacity).
+			Msg("Search rejected: concurrency limit reached")
+		return nil, &QueryError{
+			Message: "Too many concurrent searches; retry after a short delay",
+			Code:    ErrCodeResourceExhausted,
+			Cause:   err,
+		}
+	}
+
+	l.inUse.Add(weight)
+	return func() {
+		l.inUse.Add(-weight)
+		l.sem.Release(weight)
+	}, nil
+}
+
+// stats reports the limiter's current usage
+func (l *searchLimiter) stats() health.SearchConcurrency {
+	return health.SearchConcurrency{
+		InUse:    l.inUse.Load(),
+		Max:      l.capacity,
+		Waiting:  l.waiting.Load(),
+		Rejected: l.rejected.Load(),
+	}
+}
+
+var (
+	searchSlotsMu sync.RWMutex
+	searchSlots   = newSearchLimiter(DefaultSearchMaxConcurrentRows, DefaultSearchQueueTimeout)
+)
+
+// currentSearchLimiter returns the limiter searches acquire from
+func currentSearchLimiter() *searchLimiter {
+	searchSlotsMu.RLock()
+	defer searchSlotsMu.RUnlock()
+	return searchSlots
+}
+
+// SetSearchConcurrency sets the rows concurrently running searches may request and how long
+// a search waits for capacity. Values below 1 are ignored. Searches already running keep the
+// limiter they acquired from
+func SetSearchConcurrency(maxRows int, queueTimeout time.Duration) {
+	searchSlotsMu.Lock()
+	defer searchSlotsMu.Unlock()
+
+	capacity, timeout := int(searchSlots.capacity), searchSlots.timeout
+	if maxRows >= 1 {
+		capacity = maxRows
+	}
+	if queueTimeout > 0 {
+		timeout = queueTimeout
+	}
+	searchSlots = newSearchLimiter(capacity, timeout)
+}
+
+// SearchConcurrencyStats reports the rows held by running searches, the maximum, and the
+// searches waiting for or rejected by the limiter
+func SearchConcurrencyStats() health.SearchConcurrency {
+	return currentSearchLimiter().stats()
+}
@@ -0,0 +1,120 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchLimiter_WeighsPageSize(t *testing.T) {
+	limiter := newSearchLimiter(1000, time.Second)
+
+	release, err := limiter.acquire(context.Background(), 200)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), limiter.stats().InUse)
+	assert.Equal(t, int64(1000), limiter.stats().Max)
+
+	release()
+	assert.Equal(t, int64(0), limiter.stats().InUse)
+}
+
+func TestSearchLimiter_ClampsWeight(t *testing.T) {
+	limiter := newSearchLimiter(100, time.Second)
+
+	// A page above the capacity runs alone instead of waiting forever
+	release, err := limiter.acquire(context.Background(), 500)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), limiter.stats().InUse)
+	release()
+
+	// Every search weighs at least one row
+	release, err = limiter.acquire(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), limiter.stats().InUse)
+	release()
+}
+
+func TestSearchLimiter_QueuesUntilCapacityFrees(t *testing.T) {
+	limiter := newSearchLimiter(200, time.Second)
+
+	release, err := limiter.acquire(context.Background(), 200)
+	require.NoError(t, err)
+
+	acquired := make(chan func())
+	go func() {
+		next, err := limiter.acquire(context.Background(), 100)
+		if err == nil {
+			acquired <- next
+		}
+	}()
+
+	require.Eventually(t, func() bool { return limiter.stats().Waiting == 1 }, time.Second, time.Millisecond)
+	release()
+
+	select {
+	case next := <-acquired:
+		assert.Equal(t, int64(100), limiter.stats().InUse)
+		next()
+	case <-time.After(time.Second):
+		t.Fatal("queued search did not run after capacity was released")
+	}
+	assert.Equal(t, int64(0), limiter.stats().Waiting)
+}
+
+func TestSearchLimiter_RejectsAfterQueueTimeout(t *testing.T) {
+	limiter := newSearchLimiter(200, 20*time.Millisecond)
+
+	release, err := limiter.acquire(context.Background(), 200)
+	require.NoError(t, err)
+	defer release()
+
+	_, err = limiter.acquire(context.Background(), 1)
+	require.Error(t, err)
+
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeResourceExhausted, queryErr.Code)
+	assert.Contains(t, queryErr.Message, "retry")
+	assert.Equal(t, int64(1), limiter.stats().Rejected)
+	assert.Equal(t, int64(200), limiter.stats().InUse, "a rejected search holds nothing")
+}
+
+func TestSearchLimiter_CanceledWhileWaiting(t *testing.T) {
+	limiter := newSearchLimiter(200, time.Minute)
+
+	release, err := limiter.acquire(context.Background(), 200)
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var queryErr *QueryError
+	assert.False(t, errors.As(err, &queryErr), "the caller's own deadline is not RESOURCE_EXHAUSTED")
+	assert.Equal(t, int64(0), limiter.stats().Rejected)
+	assert.Equal(t, int64(0), limiter.stats().Waiting)
+}
+
+func TestSetSearchConcurrency(t *testing.T) {
+	previous := currentSearchLimiter()
+	t.Cleanup(func() {
+		searchSlotsMu.Lock()
+		searchSlots = previous
+		searchSlotsMu.Unlock()
+	})
+
+	SetSearchConcurrency(300, 5*time.Second)
+	assert.Equal(t, int64(300), SearchConcurrencyStats().Max)
+	assert.Equal(t, 5*time.Second, currentSearchLimiter().timeout)
+
+	// Invalid values keep the current settings
+	SetSearchConcurrency(0, 0)
+	assert.Equal(t, int64(300), SearchConcurrencyStats().Max)
+	assert.Equal(t, 5*time.Second, currentSearchLimiter().timeout)
+}
@@ -0,0 +1,169 @@
+package resolvers
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SearchPaging is the pagination of a search as the search query received it
+type SearchPaging struct {
+	First    *int
+	After    *string
+	Last     *int
+	Before   *string
+	Snapshot bool // The cursors come from a snapshot search
+}
+
+// SearchOptions selects what a search reads beyond its page
+type SearchOptions struct {
+	IncludeHeavyFields bool // Read the entity's heavy fields when selected
+}
+
+// searchPlan is a validated search: its $match filter, sort and decoded pagination
+type searchPlan struct {
+	match         bson.M
+	exprMatch     bson.M // Top-level $expr conditions, matched after match narrowed the input; nil without any
+	sortStages    []bson.M
+	sortKeys      bson.D // Sort keys in priority order, ending with identifier
+	last          *int   // Backward page size; nil pages forward unless before is set
+	after, before *Cursor
+	limit         int    // Page size: first, last or the default page size
+	facets        bson.M // Facet count branches added to the $facet, keyed by facetKey; nil without any
+}
+
+// isForward reports whether the plan pages forward: with first or after, or without any
+// pagination argument. Searches with last or before page backward
+func (p *searchPlan) isForward() bool {
+	return p.last == nil && p.before == nil
+}
+
+// pipeline returns the aggregation pipeline of the plan reading a page of limit entities.
+// The $facet counts all matches and reads the page; projection, when set, ends the data branch.
+// Requested facet counts run as further branches of the $facet
+func (p *searchPlan) pipeline(limit int, projection bson.M) []bson.M {
+	stages := []bson.M{{"$match": p.match}}
+	if p.exprMatch != nil {
+		stages = append(stages, bson.M{"$match": p.exprMatch})
+	}
+	branches := bson.M{
+		"metadata": []bson.M{
+			{"$count": "totalCount"},
+		},
+		"data": buildDataPipeline(p.sortStages, p.after, p.before, p.sortKeys, p.isForward(), limit, projection),
+	}
+	for key, branch := range p.facets {
+		branches[key] = branch
+	}
+	return append(stages, bson.M{"$facet": branches})
+}
+
+// splitExprConditions moves the top-level $expr conditions out of a filter, returning the rest
+// and the $expr conditions ANDed, or nil without any. $expr conditions cannot use an index, so
+// searches match them in a stage of their own once the other conditions narrowed the input.
+// $expr conditions below $or stay in place
+func splitExprConditions(filter bson.M) (bson.M, bson.M) {
+	if _, ok := filter["$expr"]; ok && len(filter) == 1 {
+		return bson.M{}, filter
+	}
+	conditions, ok := filter["$and"].([]bson.M)
+	if !ok || len(filter) != 1 {
+		return filter, nil
+	}
+
+	kept := []bson.M{}
+	exprs := []bson.M{}
+	for _, condition := range conditions {
+		rest, expr := splitExprConditions(condition)
+		if expr == nil {
+			kept = append(kept, condition)
+			continue
+		}
+		if len(rest) > 0 {
+			kept = append(kept, rest)
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) == 0 {
+		return filter, nil
+	}
+
+	var expr bson.M
+	if len(exprs) == 1 {
+		expr = exprs[0]
+	} else {
+		expr = bson.M{"$and": exprs}
+	}
+	switch len(kept) {
+	case 0:
+		return bson.M{}, expr
+	case 1:
+		return kept[0], expr
+	}
+	return bson.M{"$and": kept}, expr
+}
+
+// planSearch validates a search's filter, sorter and pagination and decodes its cursors.
+// The match filter excludes deleted entities but does not yet carry the caller's authorization scope
+func planSearch(config EntityConfig, filter, sorter interface{}, paging SearchPaging) (*searchPlan, error) {
+	if err := validatePaginationParams(paging); err != nil {
+		return nil, err
+	}
+
+	// Validate the order array
+	if err := validateSorter(sorter); err != nil {
+		return nil, err
+	}
+
+	// Bound the filter before it is converted
+	if err := validateEntityFilter(config, filter); err != nil {
+		return nil, err
+	}
+
+	plan := &searchPlan{last: paging.Last, limit: limits.DefaultPageSize}
+	if paging.First != nil && *paging.First > 0 {
+		plan.limit = *paging.First
+	} else if paging.Last != nil && *paging.Last > 0 {
+		plan.limit = *paging.Last
+	}
+
+	var err error
+	if paging.After != nil && *paging.After != "" {
+		if plan.after, err = decodeCursor(*paging.After); err != nil {
+			return nil, err
+		}
+	}
+	if paging.Before != nil && *paging.Before != "" {
+		if plan.before, err = decodeCursor(*paging.Before); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply the caller's order or the entity's default sort
+	if plan.sortStages, err = entitySortStages(config, sorter); err != nil {
+		return nil, err
+	}
+
+	// Pagination needs the sort keys in priority order; cursors must match them
+	plan.sortKeys = sortStageKeys(plan.sortStages)
+	if err := checkCursorSort(plan.after, plan.sortKeys, paging.Snapshot); err != nil {
+		return nil, err
+	}
+	if err := checkCursorSort(plan.before, plan.sortKeys, paging.Snapshot); err != nil {
+		return nil, err
+	}
+
+	recordFilterUsage(filter)
+	plan.match, plan.exprMatch = splitExprConditions(buildEntityFilter(config, filter))
+	return plan, nil
+}
+
+// BuildSearchPipeline returns the aggregation pipeline a search runs for a filter, sorter and
+// pagination, or the INVALID_INPUT error the search would fail with. It reads no request state:
+// searches additionally restrict $match to the caller's authorization scope and project the data
+// branch to the selected fields
+func BuildSearchPipeline(config EntityConfig, filter, sorter interface{}, paging SearchPaging) ([]bson.M, error) {
+	plan, err := planSearch(config, filter, sorter, paging)
+	if err != nil {
+		return nil, err
+	}
+	return plan.pipeline(plan.limit, nil), nil
+}
@@ -0,0 +1,293 @@
+package resolvers
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// updateGolden rewrites the golden pipelines: go test ./internal/graphql/resolvers -run TestBuildSearchPipeline -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files under testdata")
+
+// searchPipelineCase is one (filter, sorter, paging) input of an entity's search
+type searchPipelineCase struct {
+	name   string
+	filter interface{}
+	sorter interface{}
+	paging SearchPaging
+}
+
+// pipelineCursor encodes a cursor at the given sort values for a sorter's order, as a search
+// issues it for the entity at that position
+func pipelineCursor(t *testing.T, config EntityConfig, sorter interface{}, identifier string, values ...interface{}) *string {
+	t.Helper()
+	stages, err := entitySortStages(config, sorter)
+	require.NoError(t, err)
+	for i, value := range values {
+		values[i] = cursorSortValue(value)
+	}
+	cursor, err := encodeCursor(Cursor{SortFields: values, Identifier: identifier, Sort: sortFingerprint(sortStageKeys(stages), false)})
+	require.NoError(t, err)
+	return &cursor
+}
+
+// searchPipelineCases returns the representative inputs per entity
+func searchPipelineCases(t *testing.T) map[string][]searchPipelineCase {
+	asc, desc := generated.SortEnumTypeAsc, generated.SortEnumTypeDesc
+	created := generated.CreateStatusCreated
+	customerID := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	prefix, doe := "Jo", "Doe"
+	from, until := "1980-01-01", "1990-01-01T00:00:00Z"
+	createdAfter := "2024-01-01T00:00:00Z"
+	shared := true
+
+	customerSorter := []*generated.CustomerQuerySorterInput{{LastName: &asc}, {BirthDate: &desc}}
+	employeeSorter := []*generated.EmployeeQuerySorterInput{{BirthDate: &asc}}
+	teamSorter := []*generated.TeamQuerySorterInput{{IsShared: &desc}, {Name: &asc}}
+	executionPlanSorter := []*generated.ExecutionPlanQuerySorterInput{{CustomerID: &asc}}
+	referencePortfolioSorter := []*generated.ReferencePortfolioQuerySorterInput{{CustomerID: &desc}}
+
+	return map[string][]searchPipelineCase{
+		"customer": {
+			{name: "default"},
+			{
+				name: "filter_sort_first",
+				filter: &generated.CustomerQueryFilterInput{
+					FirstName:  &generated.StringFilterInput{StartsWith: &prefix},
+					CreateDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &createdAfter},
+				},
+				sorter: customerSorter,
+				paging: SearchPaging{First: intRef(10)},
+			},
+			{
+				name:   "sort_after",
+				sorter: customerSorter,
+				paging: SearchPaging{First: intRef(10), After: pipelineCursor(t, entityConfigs["customer"], customerSorter, customerID, doe, "1980-05-01")},
+			},
+			{
+				name:   "default_last_before",
+				paging: SearchPaging{Last: intRef(5), Before: pipelineCursor(t, entityConfigs["customer"], nil, customerID, primitive.NewDateTimeFromTime(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))},
+			},
+		},
+		"employee": {
+			{name: "default"},
+			{
+				name: "filter_sort_first",
+				filter: &generated.EmployeeQueryFilterInput{Or: []*generated.EmployeeQueryFilterInput{
+					{LastName: &generated.StringFilterInput{Eq: &doe}},
+					{BirthDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &from, Lt: &until}},
+				}},
+				sorter: employeeSorter,
+				paging: SearchPaging{First: intRef(20)},
+			},
+			{
+				name:   "sort_last_before",
+				sorter: employeeSorter,
+				paging: SearchPaging{Last: intRef(5), Before: pipelineCursor(t, entityConfigs["employee"], employeeSorter, customerID, "1985-06-15")},
+			},
+			{
+				// Without last, before still pages backward with the default page size
+				name:   "sort_before",
+				sorter: employeeSorter,
+				paging: SearchPaging{Before: pipelineCursor(t, entityConfigs["employee"], employeeSorter, customerID, "1985-06-15")},
+			},
+		},
+		"team": {
+			{name: "default"},
+			{
+				name: "filter_sort_first",
+				filter: &generated.TeamQueryFilterInput{
+					EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &customerID},
+					Status:     &generated.TeamStatusObjectFilterInput{Creation: &generated.EnumFilterOfNullableOfCreateStatusInput{Eq: &created}},
+				},
+				sorter: teamSorter,
+				paging: SearchPaging{First: intRef(10)},
+			},
+			{
+				name:   "sort_after",
+				sorter: teamSorter,
+				paging: SearchPaging{First: intRef(10), After: pipelineCursor(t, entityConfigs["team"], teamSorter, customerID, 1, "Alpha Team")},
+			},
+			{
+				// The top-level member count is matched after the other conditions, the one below or in place
+				name: "filter_member_count",
+				filter: &generated.TeamQueryFilterInput{
+					IsShared:    &generated.BooleanFilterInput{Eq: &shared},
+					MemberCount: &generated.ComparableFilterOfInt32Input{Lt: intRef(3)},
+					Or: []*generated.TeamQueryFilterInput{
+						{MemberCount: &generated.ComparableFilterOfInt32Input{Gt: intRef(4)}},
+						{EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &customerID}},
+					},
+				},
+			},
+		},
+		"executionPlan": {
+			{name: "default"},
+			{
+				name:   "filter_sort_after",
+				filter: &generated.ExecutionPlanQueryFilterInput{CustomerID: &generated.ComparableFilterOfNullableOfGUIDInput{In: []*string{&customerID}}},
+				sorter: executionPlanSorter,
+				paging: SearchPaging{First: intRef(50), After: pipelineCursor(t, entityConfigs["executionPlan"], executionPlanSorter, customerID, customerID)},
+			},
+		},
+		"referencePortfolio": {
+			{name: "default"},
+			{
+				name:   "filter_sort_last",
+				filter: &generated.ReferencePortfolioQueryFilterInput{CustomerID: &generated.ComparableFilterOfNullableOfGUIDInput{Neq: &customerID}},
+				sorter: referencePortfolioSorter,
+				paging: SearchPaging{Last: intRef(3)},
+			},
+		},
+	}
+}
+
+// TestBuildSearchPipeline compares the pipelines of representative searches per entity with
+// the golden files under testdata/search_pipelines
+func TestBuildSearchPipeline(t *testing.T) {
+	for entity, cases := range searchPipelineCases(t) {
+		for _, tc := range cases {
+			t.Run(entity+"/"+tc.name, func(t *testing.T) {
+				pipeline, err := BuildSearchPipeline(entityConfigs[entity], tc.filter, tc.sorter, tc.paging)
+				require.NoError(t, err)
+
+				rendered, err := bson.MarshalExtJSONIndent(bson.D{{Key: "pipeline", Value: canonicalBSON(pipeline)}}, false, false, "", "  ")
+				require.NoError(t, err)
+				rendered = append(rendered, '\n')
+
+				golden := filepath.Join("testdata", "search_pipelines", entity+"_"+tc.name+".json")
+				if *updateGolden {
+					require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+					require.NoError(t, os.WriteFile(golden, rendered, 0o644))
+				}
+				expected, err := os.ReadFile(golden)
+				require.NoError(t, err, "run with -update to create the golden file")
+				assert.Equal(t, string(expected), string(rendered))
+			})
+		}
+	}
+}
+
+// TestBuildSearchPipeline_InvalidInput tests the pipeline is not built for inputs the search rejects
+func TestBuildSearchPipeline_InvalidInput(t *testing.T) {
+	config := entityConfigs["team"]
+	asc := generated.SortEnumTypeAsc
+	nameSorter := []*generated.TeamQuerySorterInput{{Name: &asc}}
+	descriptionSorter := []*generated.TeamQuerySorterInput{{Description: &asc}}
+	invalid := "team-1"
+
+	tests := []struct {
+		name     string
+		build    func() ([]bson.M, error)
+		contains string
+	}{
+		{
+			name: "first and last",
+			build: func() ([]bson.M, error) {
+				return BuildSearchPipeline(config, nil, nil, SearchPaging{First: intRef(1), Last: intRef(1)})
+			},
+			contains: "cannot specify both 'first' and 'last'",
+		},
+		{
+			name: "after and before",
+			build: func() ([]bson.M, error) {
+				cursor := pipelineCursor(t, config, nil, "7c9e6679-7425-40de-944b-e07fc1f90ae7", "Alpha Team")
+				return BuildSearchPipeline(config, nil, nil, SearchPaging{After: cursor, Before: cursor})
+			},
+			contains: "cannot specify both 'after' and 'before' cursors",
+		},
+		{
+			name: "after with last",
+			build: func() ([]bson.M, error) {
+				after := pipelineCursor(t, config, nil, "7c9e6679-7425-40de-944b-e07fc1f90ae7", "Alpha Team")
+				return BuildSearchPipeline(config, nil, nil, SearchPaging{Last: intRef(5), After: after})
+			},
+			contains: "'after' pages forward and cannot be combined with 'last'",
+		},
+		{
+			name: "before with first",
+			build: func() ([]bson.M, error) {
+				before := pipelineCursor(t, config, nil, "7c9e6679-7425-40de-944b-e07fc1f90ae7", "Alpha Team")
+				return BuildSearchPipeline(config, nil, nil, SearchPaging{First: intRef(5), Before: before})
+			},
+			contains: "'before' pages backward and cannot be combined with 'first'",
+		},
+		{
+			name: "cursor of another order",
+			build: func() ([]bson.M, error) {
+				after := pipelineCursor(t, config, nameSorter, "7c9e6679-7425-40de-944b-e07fc1f90ae7", "Alpha Team")
+				return BuildSearchPipeline(config, nil, descriptionSorter, SearchPaging{After: after})
+			},
+			contains: "cursor was issued for a different sort order",
+		},
+		{
+			name: "invalid UUID",
+			build: func() ([]bson.M, error) {
+				filter := &generated.TeamQueryFilterInput{EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &invalid}}
+				return BuildSearchPipeline(config, filter, nil, SearchPaging{})
+			},
+			contains: "invalid UUID format: team-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := tt.build()
+			assert.Nil(t, pipeline)
+			requireInvalidInput(t, err, tt.contains)
+		})
+	}
+}
+
+// canonicalBSON converts documents to bson.D with sorted keys, keeping the order of bson.D
+// (sort keys) and arrays, so the rendered pipeline is stable
+func canonicalBSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.M:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		doc := make(bson.D, 0, len(v))
+		for _, key := range keys {
+			doc = append(doc, bson.E{Key: key, Value: canonicalBSON(v[key])})
+		}
+		return doc
+	case bson.D:
+		doc := make(bson.D, 0, len(v))
+		for _, elem := range v {
+			doc = append(doc, bson.E{Key: elem.Key, Value: canonicalBSON(elem.Value)})
+		}
+		return doc
+	case time.Time, string, nil:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice:
+		array := make(bson.A, rv.Len())
+		for i := range array {
+			array[i] = canonicalBSON(rv.Index(i).Interface())
+		}
+		return array
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		return canonicalBSON(rv.Elem().Interface())
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+package resolvers
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// searchDataField is the field of a search result holding the page of entities
+const searchDataField = "data"
+
+// searchDataProjection returns the $project stage keeping only what a search needs: the fields
+// selected under the result's data field except the omitted ones, the fields their resolvers
+// read, the identifier and the sort keys the cursors carry. Returns nil, keeping whole
+// documents, outside a GraphQL field or when the field has no data selection
+func searchDataProjection(ctx context.Context, config EntityConfig, sortKeys bson.D, omitted []string) bson.M {
+	selected := selectedDataFields(ctx)
+	if len(selected) == 0 {
+		return nil
+	}
+
+	paths := []string{"identifier"}
+	for _, field := range selected {
+		if strings.HasPrefix(field.Name, "__") || slices.Contains(omitted, field.Name) {
+			continue
+		}
+		paths = append(paths, field.Name)
+		paths = append(paths, config.ProjectionFields[field.Name]...)
+	}
+	for _, key := range sortKeys {
+		if key.Key == "_id" {
+			continue // An inclusion projection keeps _id anyway
+		}
+		paths = append(paths, key.Key)
+	}
+
+	projection := bson.M{}
+	for _, path := range withoutNestedPaths(paths) {
+		projection[path] = 1
+	}
+	return bson.M{"$project": projection}
+}
+
+// selectedDataFields returns the fields selected under the data field of the current search
+// field, or nil outside a GraphQL field
+func selectedDataFields(ctx context.Context) []graphql.CollectedField {
+	if !graphql.HasOperationContext(ctx) || graphql.GetFieldContext(ctx) == nil {
+		return nil
+	}
+	oc := graphql.GetOperationContext(ctx)
+
+	for _, field := range graphql.CollectFieldsCtx(ctx, nil) {
+		if field.Name == searchDataField {
+			return graphql.CollectFields(oc, field.Selections, nil)
+		}
+	}
+	return nil
+}
+
+// withoutNestedPaths drops duplicates and the paths inside another path of the list,
+// which MongoDB rejects as a path collision
+func withoutNestedPaths(paths []string) []string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	kept := sorted[:0]
+	for _, path := range sorted {
+		if len(kept) > 0 {
+			last := kept[len(kept)-1]
+			if path == last || strings.HasPrefix(path, last+".") {
+				continue
+			}
+		}
+		kept = append(kept, path)
+	}
+	return kept
+}
@@ -0,0 +1,82 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+const (
+	// searchTruncationExtensionKey is the GraphQL response extension listing truncated searches
+	searchTruncationExtensionKey = "searchTruncation"
+
+	// minTruncatedPageSize is the smallest page a search is retried with after its
+	// $facet result exceeded the BSON document size limit
+	minTruncatedPageSize = 1
+
+	// errCodeBSONObjectTooLarge is the MongoDB error code of a result document above 16MB
+	errCodeBSONObjectTooLarge = 10334
+)
+
+// SearchTruncation reports a search whose page was reduced to fit the $facet result
+// into MongoDB's 16MB document limit
+type SearchTruncation struct {
+	Truncated      bool `json:"truncated"`
+	RequestedLimit int  `json:"requestedLimit"`
+	AppliedLimit   int  `json:"appliedLimit"`
+}
+
+// searchTruncationMu serializes registering and filling the truncation extension, which
+// concurrently resolved fields of one operation share
+var searchTruncationMu sync.Mutex
+
+// isDocumentTooLarge reports whether a search failed because its $facet result exceeded
+// the BSON document size limit
+func isDocumentTooLarge(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(errCodeBSONObjectTooLarge)
+}
+
+// truncatedPageSize returns the next page size to retry with, and false at the floor
+func truncatedPageSize(limit int) (int, bool) {
+	if limit <= minTruncatedPageSize {
+		return limit, false
+	}
+	limit /= 2
+	if limit < minTruncatedPageSize {
+		limit = minTruncatedPageSize
+	}
+	return limit, true
+}
+
+// recordSearchTruncation logs a truncated search and reports it in the response extensions,
+// keyed by field path
+func recordSearchTruncation(ctx context.Context, config EntityConfig, requested, applied int) {
+	logger.FromContext(ctx).Warn().
+		Str("collection", config.CollectionName).
+		Int("requested_limit", requested).
+		Int("applied_limit", applied).
+		Msg("Search result exceeded the document size limit; page truncated")
+
+	if !graphql.HasOperationContext(ctx) {
+		return
+	}
+	key := config.CollectionName
+	if fc := graphql.GetFieldContext(ctx); fc != nil {
+		key = fc.Path().String()
+	}
+
+	searchTruncationMu.Lock()
+	defer searchTruncationMu.Unlock()
+	truncations, ok := graphql.GetExtension(ctx, searchTruncationExtensionKey).(map[string]SearchTruncation)
+	if !ok {
+		truncations = make(map[string]SearchTruncation)
+		graphql.RegisterExtension(ctx, searchTruncationExtensionKey, truncations)
+	}
+	truncations[key] = SearchTruncation{Truncated: true, RequestedLimit: requested, AppliedLimit: applied}
+}
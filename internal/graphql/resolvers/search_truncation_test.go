@@ -0,0 +1,87 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// searchFieldContext returns a context resolving the first field of a query document,
+// as a search resolver would see it
+func searchFieldContext(t *testing.T, query string) context.Context {
+	t.Helper()
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	require.NoError(t, err)
+
+	ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{Doc: doc})
+	ctx = graphql.WithResponseContext(ctx, graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+	field := doc.Operations[0].SelectionSet[0].(*ast.Field)
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{Field: graphql.CollectedField{Field: field, Selections: field.SelectionSet}})
+}
+
+// TestSearchDataProjection tests the data branch keeps the selected fields, the fields their
+// resolvers read, the identifier and the sort keys
+func TestSearchDataProjection(t *testing.T) {
+	ctx := searchFieldContext(t, `
+		query {
+			teamSearch {
+				count
+				data { name ...Members status { deletion } __typename }
+			}
+		}
+		fragment Members on TeamQueryOutput { members { identifier } }
+	`)
+	sortKeys := bson.D{{Key: "name", Value: 1}, {Key: "status.deletion", Value: 1}, {Key: "identifier", Value: 1}}
+
+	assert.Equal(t, bson.M{"$project": bson.M{
+		"identifier":  1,
+		"name":        1,
+		"members":     1,
+		"teamMembers": 1,
+		"status":      1,
+	}}, searchDataProjection(ctx, entityConfigs["team"], sortKeys, nil))
+
+	// Without a data selection or outside GraphQL, whole documents are kept
+	assert.Nil(t, searchDataProjection(searchFieldContext(t, `{ teamSearch { count } }`), entityConfigs["team"], sortKeys, nil))
+	assert.Nil(t, searchDataProjection(context.Background(), entityConfigs["team"], sortKeys, nil))
+}
+
+// TestSearchEntities_TruncatesOversizedPages tests a page whose result exceeds the document size
+// limit is retried with half the limit, reported in the extensions, and still paginates
+func TestSearchEntities_TruncatesOversizedPages(t *testing.T) {
+	identifiers := make([]string, 30)
+	for i := range identifiers {
+		identifiers[i] = fmt.Sprintf("e%02d", i)
+	}
+	fake := &fakeSearchDB{identifiers: identifiers, maxLimit: 6}
+	ctx := searchFieldContext(t, `{ employeeSearch { data { firstName } } }`)
+
+	var employees []*generated.Employee
+	count, _, hasNextPage, _, _, _, err := searchEntities(ctx, fake, entityConfigs["employee"], nil, nil, SearchPaging{First: intRef(20)}, SearchOptions{}, &employees)
+	require.NoError(t, err)
+
+	// 20 -> 10 -> 5; the fake accepts a $limit (page size + 1) of at most 6
+	assert.Equal(t, 5, count)
+	assert.True(t, hasNextPage)
+	require.Len(t, fake.pipelines, 3)
+	assert.Equal(t, bson.M{"$project": bson.M{"identifier": 1, "firstName": 1}}, fake.pipelines[2][len(fake.pipelines[2])-1])
+
+	assert.Equal(t, map[string]SearchTruncation{
+		"employeeSearch": {Truncated: true, RequestedLimit: 20, AppliedLimit: 5},
+	}, graphql.GetExtension(ctx, searchTruncationExtensionKey))
+
+	// Nothing fits: the error is returned once the floor is reached
+	fake = &fakeSearchDB{identifiers: identifiers, maxLimit: 1}
+	_, _, _, _, _, _, err = searchEntities(context.Background(), fake, entityConfigs["employee"], nil, nil, SearchPaging{First: intRef(4)}, SearchOptions{}, &employees)
+	assert.True(t, isDocumentTooLarge(err))
+	assert.Len(t, fake.pipelines, 3, "4 -> 2 -> 1")
+}
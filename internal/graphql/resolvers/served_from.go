@@ -0,0 +1,30 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// servedFromExtensionKey is the GraphQL response extension set when a read was served by a
+// secondary because no primary was available; its data may be slightly stale
+const servedFromExtensionKey = "servedFrom"
+
+// servedFromMu serializes setting the servedFrom extension, which concurrently resolved fields
+// of one operation share
+var servedFromMu sync.Mutex
+
+// RecordReadFallback tags the response of the operation in ctx as served from a secondary.
+// Register it with db.Client.SetReadFallbackHook
+func RecordReadFallback(ctx context.Context, collection string) {
+	if !graphql.HasOperationContext(ctx) {
+		return
+	}
+
+	servedFromMu.Lock()
+	defer servedFromMu.Unlock()
+	if graphql.GetExtension(ctx, servedFromExtensionKey) == nil {
+		graphql.RegisterExtension(ctx, servedFromExtensionKey, "secondary")
+	}
+}
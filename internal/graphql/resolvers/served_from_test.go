@@ -0,0 +1,22 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRecordReadFallback tests a read fallback tags the response as served from a secondary,
+// and is ignored outside an operation
+func TestRecordReadFallback(t *testing.T) {
+	ctx := searchFieldContext(t, `{ customerSearch { data { identifier } } }`)
+	assert.Nil(t, graphql.GetExtension(ctx, servedFromExtensionKey))
+
+	RecordReadFallback(ctx, "customers")
+	RecordReadFallback(ctx, "employees")
+	assert.Equal(t, map[string]interface{}{servedFromExtensionKey: "secondary"}, graphql.GetExtensions(ctx))
+
+	assert.NotPanics(t, func() { RecordReadFallback(context.Background(), "customers") })
+}
@@ -0,0 +1,153 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// Lowercase shadow fields let case-insensitive startsWith filters use an index.
+// Each map pairs a string field with the field holding its lowercase copy
+var (
+	customerShadowFields = map[string]string{
+		"firstName": "firstNameLower",
+		"lastName":  "lastNameLower",
+		"userEmail": "userEmailLower",
+	}
+	employeeShadowFields = map[string]string{
+		"firstName": "firstNameLower",
+		"lastName":  "lastNameLower",
+		"userEmail": "userEmailLower",
+	}
+	teamShadowFields = map[string]string{
+		"name": "nameLower",
+	}
+)
+
+// shadowFieldsEnabled switches startsWith filters to the shadow fields once they are backfilled
+var shadowFieldsEnabled = false
+
+// SetShadowFieldsEnabled sets whether startsWith filters target the lowercase shadow fields
+func SetShadowFieldsEnabled(enabled bool) {
+	shadowFieldsEnabled = enabled
+}
+
+// shadowField returns the shadow field declared for a field, or "" when there is none or they are disabled
+func shadowField(shadowFields map[string]string, field string) string {
+	if !shadowFieldsEnabled {
+		return ""
+	}
+	return shadowFields[field]
+}
+
+// setShadowFields adds the lowercase shadow field of every string field in a $set document,
+// so written documents never need a backfill
+func setShadowFields(set bson.M, shadowFields map[string]string) {
+	for field, shadow := range shadowFields {
+		if value, ok := set[field].(string); ok {
+			set[shadow] = strings.ToLower(value)
+		}
+	}
+}
+
+// ShadowFieldBackfill reports the documents whose shadow fields were written for one entity
+type ShadowFieldBackfill struct {
+	Entity     string
+	Collection string
+	Updated    int64
+}
+
+// BackfillShadowFields writes the lowercase shadow fields of every entity that declares them.
+// An empty entity backfills all of them except disabled ones. Documents whose shadow fields are current are left untouched,
+// so the backfill can be re-run safely
+func BackfillShadowFields(ctx context.Context, database db.Database, entity string) ([]ShadowFieldBackfill, error) {
+	if database == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	entities := []string{entity}
+	if entity == "" {
+		entities = entities[:0]
+		for _, name := range enabledEntityNames() {
+			if len(entityConfigs[name].ShadowFields) > 0 {
+				entities = append(entities, name)
+			}
+		}
+	} else if config, err := lookupEntityConfig(entity); err != nil {
+		return nil, err
+	} else if len(config.ShadowFields) == 0 {
+		return nil, fmt.Errorf("entity %q does not declare shadow fields", entity)
+	}
+
+	results := make([]ShadowFieldBackfill, 0, len(entities))
+	for _, name := range entities {
+		config := entityConfigs[name]
+		updated, err := backfillCollection(ctx, database.Collection(config.CollectionName), config.ShadowFields)
+		if err != nil {
+			return results, fmt.Errorf("failed to backfill %s: %w", config.CollectionName, err)
+		}
+		results = append(results, ShadowFieldBackfill{Entity: name, Collection: config.CollectionName, Updated: updated})
+	}
+	return results, nil
+}
+
+// backfillCollection sets each shadow field to the lowercase source value and unsets it when the source is not a string
+// Lowercasing happens in Go rather than with $toLower, which only handles ASCII
+func backfillCollection(ctx context.Context, collection db.Collection, shadowFields map[string]string) (int64, error) {
+	projection := bson.M{}
+	for field, shadow := range shadowFields {
+		projection[field] = 1
+		projection[shadow] = 1
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return 0, err
+	}
+
+	var updated int64
+	err = db.WithCursor(ctx, cursor, func(cursor *mongo.Cursor) error {
+		for cursor.Next(ctx) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+
+			set := bson.M{}
+			unset := bson.M{}
+			for field, shadow := range shadowFields {
+				value, isString := doc[field].(string)
+				current, hasShadow := doc[shadow]
+				switch {
+				case isString && current != strings.ToLower(value):
+					set[shadow] = strings.ToLower(value)
+				case !isString && hasShadow:
+					unset[shadow] = ""
+				}
+			}
+			if len(set) == 0 && len(unset) == 0 {
+				continue
+			}
+
+			update := bson.M{}
+			if len(set) > 0 {
+				update["$set"] = set
+			}
+			if len(unset) > 0 {
+				update["$unset"] = unset
+			}
+			if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, update); err != nil {
+				return err
+			}
+			updated++
+		}
+		return cursor.Err()
+	})
+	return updated, err
+}
@@ -0,0 +1,286 @@
+package resolvers
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Test sort direction conversion rejects unknown values
+func TestSortEnumToInt(t *testing.T) {
+	asc, err := sortEnumToInt(generated.SortEnumTypeAsc)
+	require.NoError(t, err)
+	assert.Equal(t, 1, asc)
+
+	desc, err := sortEnumToInt(generated.SortEnumTypeDesc)
+	require.NoError(t, err)
+	assert.Equal(t, -1, desc)
+
+	_, err = sortEnumToInt(generated.SortEnumType("asc"))
+	require.Error(t, err)
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeInvalidInput, queryErr.Code)
+}
+
+// Test an invalid direction from JSON never silently becomes DESC
+func TestSorterConverter_InvalidEnumFromJSON(t *testing.T) {
+	// Strict JSON decoding of the generated enum rejects the value outright
+	var sorter []*generated.TeamQuerySorterInput
+	err := json.Unmarshal([]byte(`[{"isShared":"SIDEWAYS"}]`), &sorter)
+	assert.Error(t, err)
+
+	// A value that bypassed enum validation is rejected by the converters
+	invalid := generated.SortEnumType("SIDEWAYS")
+	converters := map[string]func() ([]bson.M, error){
+		"team": func() ([]bson.M, error) {
+			return teamSorterConverter([]*generated.TeamQuerySorterInput{{IsShared: &invalid}})
+		},
+		"customer": func() ([]bson.M, error) {
+			return customerSorterConverter([]*generated.CustomerQuerySorterInput{{FirstName: &invalid}})
+		},
+		"employee null-safe": func() ([]bson.M, error) {
+			return employeeSorterConverter([]*generated.EmployeeQuerySorterInput{{BirthDate: &invalid}})
+		},
+		"executionPlan": func() ([]bson.M, error) {
+			return executionPlanSorterConverter([]*generated.ExecutionPlanQuerySorterInput{{CustomerID: &invalid}})
+		},
+	}
+
+	for name, convert := range converters {
+		t.Run(name, func(t *testing.T) {
+			stages, err := convert()
+			require.Error(t, err)
+			assert.Nil(t, stages)
+			assert.Contains(t, err.Error(), "invalid sort direction")
+		})
+	}
+}
+
+// Test boolean isShared sorts use the null-safe rank instead of sorting the raw field
+func TestTeamSorterConverter_IsSharedNullSafe(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	desc := generated.SortEnumTypeDesc
+
+	tests := []struct {
+		name      string
+		direction *generated.SortEnumType
+		expected  int
+	}{
+		{"ASC places missing last", &asc, 1},
+		{"DESC places missing first", &desc, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := teamSorterConverter([]*generated.TeamQuerySorterInput{{IsShared: tt.direction}})
+			require.NoError(t, err)
+			require.Len(t, stages, 3)
+
+			assert.Equal(t, bson.M{"$addFields": bson.M{"_sortKey_isShared": nullSafeBooleanSortKey("isShared")}}, stages[0])
+			assert.Equal(t, bson.M{"$sort": bson.D{{Key: "_sortKey_isShared", Value: tt.expected}, {Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}}, stages[1])
+			assert.Equal(t, bson.M{"$project": bson.M{"_sortKey_isShared": 0}}, stages[2])
+		})
+	}
+}
+
+// Test a page after an isShared cursor compares on the computed rank the cursor carries
+func TestBuildDataPipeline_ComputedSortKeyPagination(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	stages, err := teamSorterConverter([]*generated.TeamQuerySorterInput{{IsShared: &asc}})
+	require.NoError(t, err)
+	keys := sortStageKeys(stages)
+
+	encoded, err := generateCursor(bson.M{"identifier": "team-2", "isShared": true, "_sortKey_isShared": 2}, keys, nil, 0)
+	require.NoError(t, err)
+	cursor, err := decodeCursor(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{float64(2)}, cursor.SortFields)
+
+	first := 2
+	pipeline := buildDataPipeline(stages, cursor, nil, keys, true, first, nil)
+
+	// The rank stays in the page documents: the pagination filter and the cursors need it
+	assert.Equal(t, []bson.M{
+		stages[0],
+		stages[1],
+		{"$match": bson.M{"$or": []bson.M{
+			{"_sortKey_isShared": bson.M{"$gt": float64(2)}},
+			{"_sortKey_isShared": float64(2), "identifier": bson.M{"$gt": "team-2"}},
+		}}},
+		{"$limit": 3},
+	}, pipeline)
+}
+
+// Test the rank orders false < true < missing
+func TestNullSafeBooleanSortKey(t *testing.T) {
+	key := nullSafeBooleanSortKey("isShared")
+	sw := key["$switch"].(bson.M)
+	branches := sw["branches"].([]bson.M)
+
+	require.Len(t, branches, 2)
+	assert.Equal(t, bson.M{"$eq": []interface{}{"$isShared", false}}, branches[0]["case"])
+	assert.Equal(t, 1, branches[0]["then"])
+	assert.Equal(t, bson.M{"$eq": []interface{}{"$isShared", true}}, branches[1]["case"])
+	assert.Equal(t, 2, branches[1]["then"])
+	assert.Equal(t, 3, sw["default"])
+}
+
+// Test every sorter entry contributes to a single $sort stage in request order
+func TestSorterConverter_MultipleEntriesSingleSort(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	desc := generated.SortEnumTypeDesc
+
+	stages, err := customerSorterConverter([]*generated.CustomerQuerySorterInput{
+		{LastName: &asc},
+		{FirstName: &desc},
+		{BirthDate: &asc},
+	})
+	require.NoError(t, err)
+	require.Len(t, stages, 3)
+
+	assert.Equal(t, bson.M{"$addFields": bson.M{
+		"_sortKey_birthDate": bson.M{"$ifNull": []interface{}{"$birthDate", nullPlaceholder}},
+	}}, stages[0])
+	assert.Equal(t, bson.M{"$sort": bson.D{
+		{Key: "lastName", Value: 1},
+		{Key: "firstName", Value: -1},
+		{Key: "_sortKey_birthDate", Value: 1},
+		{Key: "identifier", Value: 1},
+		{Key: "_id", Value: 1},
+	}}, stages[1])
+	assert.Equal(t, bson.M{"$project": bson.M{"_sortKey_birthDate": 0}}, stages[2])
+
+	// Temporary keys are cursor fields too, compared on their computed values
+	assert.Equal(t, []string{"lastName", "firstName", "_sortKey_birthDate", "identifier", "_id"}, sortStageFields(stages))
+}
+
+// Test the employee converter no longer ignores entries after the first
+func TestEmployeeSorterConverter_TieBreaker(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+
+	stages, err := employeeSorterConverter([]*generated.EmployeeQuerySorterInput{
+		{LastName: &asc},
+		{FirstName: &asc},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []bson.M{{"$sort": bson.D{
+		{Key: "lastName", Value: 1},
+		{Key: "firstName", Value: 1},
+		{Key: "identifier", Value: 1},
+		{Key: "_id", Value: 1},
+	}}}, stages)
+}
+
+// Test a nullable userEmail sort shares the $sort stage with a plain lastName sort, and its temporary
+// key survives until after the sort so cursors and the pagination filter see both fields
+func TestEmployeeSorterConverter_NullableWithPlainField(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	desc := generated.SortEnumTypeDesc
+
+	stages, err := employeeSorterConverter([]*generated.EmployeeQuerySorterInput{
+		{UserEmail: &desc},
+		{LastName: &asc},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []bson.M{
+		{"$addFields": bson.M{"_sortKey_userEmail": bson.M{"$ifNull": []interface{}{"$userEmail", nullPlaceholder}}}},
+		{"$sort": bson.D{
+			{Key: "_sortKey_userEmail", Value: -1},
+			{Key: "lastName", Value: 1},
+			{Key: "identifier", Value: 1},
+			{Key: "_id", Value: 1},
+		}},
+		{"$project": bson.M{"_sortKey_userEmail": 0}},
+	}, stages)
+	assert.Equal(t, []string{"_sortKey_userEmail", "lastName", "identifier", "_id"}, sortStageFields(stages))
+
+	// A page continues after the cursor on both fields, comparing a missing e-mail as the placeholder
+	cursor := &Cursor{SortFields: []interface{}{nullPlaceholder, "Doe"}, Identifier: "employee-1"}
+	assert.Equal(t, bson.M{"$or": []bson.M{
+		{"_sortKey_userEmail": bson.M{"$lt": nullPlaceholder}},
+		{"_sortKey_userEmail": nullPlaceholder, "lastName": bson.M{"$gt": "Doe"}},
+		{"_sortKey_userEmail": nullPlaceholder, "lastName": "Doe", "identifier": bson.M{"$gt": "employee-1"}},
+	}}, buildPaginationFilter(cursor, sortStageKeys(stages), true))
+}
+
+// Test payment sorter fields are mapped null-safely, with boolean flags ranked
+func TestCustomerSorterConverter_PaymentFields(t *testing.T) {
+	desc := generated.SortEnumTypeDesc
+
+	stages, err := customerSorterConverter([]*generated.CustomerQuerySorterInput{
+		{Payment: &generated.CustomerPaymentObjectSorterInput{ExpiresAt: &desc}},
+		{Payment: &generated.CustomerPaymentObjectSorterInput{PromoteToLifetime: &desc}},
+	})
+	require.NoError(t, err)
+	require.Len(t, stages, 3)
+
+	computed := stages[0]["$addFields"].(bson.M)
+	assert.Equal(t, bson.M{"$ifNull": []interface{}{"$payment.expiresAt", nullPlaceholder}}, computed["_sortKey_payment_expiresAt"])
+	assert.Equal(t, nullSafeBooleanSortKey("payment.promoteToLifetime"), computed["_sortKey_payment_promoteToLifetime"])
+	assert.Equal(t, bson.M{"$sort": bson.D{
+		{Key: "_sortKey_payment_expiresAt", Value: -1},
+		{Key: "_sortKey_payment_promoteToLifetime", Value: -1},
+		{Key: "identifier", Value: 1},
+		{Key: "_id", Value: 1},
+	}}, stages[1])
+}
+
+// Test caller sorts break remaining ties on identifier, matching the pagination filter
+func TestSorterConverter_IdentifierTieBreaker(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	stages, err := teamSorterConverter([]*generated.TeamQuerySorterInput{{Name: &asc}})
+	require.NoError(t, err)
+
+	assert.Equal(t, bson.D{{Key: "name", Value: 1}, {Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}, sortStageKeys(stages))
+
+	// The pagination filter continues after the cursor on the same keys
+	cursor := &Cursor{SortFields: []interface{}{"Team A"}, Identifier: "team-1"}
+	assert.Equal(t, bson.M{"$or": []bson.M{
+		{"name": bson.M{"$gt": "Team A"}},
+		{"name": "Team A", "identifier": bson.M{"$gt": "team-1"}},
+	}}, buildPaginationFilter(cursor, sortStageKeys(stages), true))
+}
+
+// Test a repeated field keeps its first position
+func TestSortBuilder_RepeatedFieldKeepsFirst(t *testing.T) {
+	builder := &sortBuilder{}
+	require.NoError(t, builder.add("lastName", generated.SortEnumTypeAsc))
+	require.NoError(t, builder.add("firstName", generated.SortEnumTypeAsc))
+	require.NoError(t, builder.add("lastName", generated.SortEnumTypeDesc))
+
+	assert.Equal(t, []bson.M{{"$sort": bson.D{
+		{Key: "lastName", Value: 1},
+		{Key: "firstName", Value: 1},
+	}}}, builder.stages())
+}
+
+// Test a cursor from one null-safe sort is rejected under another: the fingerprint covers the computed keys
+func TestCheckCursorSort_ComputedSortKeys(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	birthDateStages, err := customerSorterConverter([]*generated.CustomerQuerySorterInput{{BirthDate: &asc}})
+	require.NoError(t, err)
+	emailStages, err := customerSorterConverter([]*generated.CustomerQuerySorterInput{{EmployeeEmail: &asc}})
+	require.NoError(t, err)
+
+	birthDateKeys := sortStageKeys(birthDateStages)
+	assert.Equal(t, bson.D{{Key: "_sortKey_birthDate", Value: 1}, {Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}, birthDateKeys)
+
+	encoded, err := generateCursor(bson.M{"identifier": "cust-1", "_sortKey_birthDate": "1990-01-01"}, birthDateKeys, nil, 0)
+	require.NoError(t, err)
+	cursor, err := decodeCursor(encoded)
+	require.NoError(t, err)
+
+	assert.NoError(t, checkCursorSort(cursor, birthDateKeys, false))
+
+	err = checkCursorSort(cursor, sortStageKeys(emailStages), false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different sort order")
+}
@@ -0,0 +1,141 @@
+package resolvers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// unsupportedSortReason is reported for order fields the entity's sorter converter does not map
+const unsupportedSortReason = "this server does not sort by this field"
+
+// SetMaxSortEntries sets the maximum number of order entries accepted per query
+// Values below 1 are ignored
+func SetMaxSortEntries(n int) {
+	if n >= 1 {
+		limits.MaxSortEntries = n
+	}
+}
+
+// validateSorter checks an order array before any database work
+// Rejects arrays above the entry cap, entries that set no field and fields repeated across entries
+func validateSorter(sorter interface{}) error {
+	entries := reflect.ValueOf(sorter)
+	if entries.Kind() != reflect.Slice || entries.Len() == 0 {
+		return nil
+	}
+
+	if entries.Len() > limits.MaxSortEntries {
+		return NewInvalidInput(fmt.Sprintf("order accepts at most %d entries, got %d", limits.MaxSortEntries, entries.Len()), "order")
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < entries.Len(); i++ {
+		fields := setSorterFields(entries.Index(i), "")
+		if len(fields) == 0 {
+			return NewInvalidInput(fmt.Sprintf("order entry %d does not set a sort field", i), "order")
+		}
+
+		for _, field := range fields {
+			if seen[field] {
+				return NewInvalidInput(fmt.Sprintf("duplicate sort field %q in order", field), "order")
+			}
+			seen[field] = true
+		}
+	}
+
+	return nil
+}
+
+// setSorterFields returns the JSON paths of the fields set in a sorter entry
+// Nested object sorters (e.g. payment) are expanded into dotted paths
+func setSorterFields(entry reflect.Value, prefix string) []string {
+	if entry.Kind() == reflect.Ptr {
+		if entry.IsNil() {
+			return nil
+		}
+		entry = entry.Elem()
+	}
+	if entry.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := []string{}
+	for i := 0; i < entry.NumField(); i++ {
+		value := entry.Field(i)
+		if value.Kind() != reflect.Ptr || value.IsNil() {
+			continue
+		}
+
+		name := strings.Split(entry.Type().Field(i).Tag.Get("json"), ",")[0]
+		if value.Elem().Kind() == reflect.Struct {
+			fields = append(fields, setSorterFields(value, prefix+name+".")...)
+			continue
+		}
+		fields = append(fields, prefix+name)
+	}
+
+	return fields
+}
+
+// unsupportedSortFields returns the fields set in the entries of an order array that are not
+// among the handled paths, in entry order and without duplicates
+func unsupportedSortFields(sorter interface{}, handled []string) []string {
+	entries := reflect.ValueOf(sorter)
+	if entries.Kind() != reflect.Slice {
+		return nil
+	}
+	known := make(map[string]bool, len(handled))
+	for _, path := range handled {
+		known[path] = true
+	}
+
+	unsupported := []string{}
+	for i := 0; i < entries.Len(); i++ {
+		for _, field := range setSorterFields(entries.Index(i), "") {
+			if !known[field] {
+				known[field] = true
+				unsupported = append(unsupported, field)
+			}
+		}
+	}
+	return unsupported
+}
+
+// checkSupportedSort fails in strict mode when an order entry sets a field outside the handled paths,
+// which the converter would otherwise skip without a trace
+func checkSupportedSort(sorter interface{}, handled []string) error {
+	if lenientFilters {
+		return nil
+	}
+	if fields := unsupportedSortFields(sorter, handled); len(fields) > 0 {
+		return NewInvalidInput(fmt.Sprintf("sort field %q is not supported by this server", fields[0]), "order")
+	}
+	return nil
+}
+
+// searchSortWarnings returns the order fields a search ignored in lenient mode. The handled fields
+// come from the converter coverage of the entries' input type; strict mode rejects such orders, so
+// it returns nil
+func searchSortWarnings(sorter interface{}) []*generated.QueryWarning {
+	entries := reflect.ValueOf(sorter)
+	if !lenientFilters || entries.Kind() != reflect.Slice || entries.Type().Elem().Kind() != reflect.Ptr {
+		return nil
+	}
+	handled := handledInputPaths(entries.Type().Elem().Elem())
+	if handled == nil {
+		return nil
+	}
+	paths := make([]string, 0, len(handled))
+	for path := range handled {
+		paths = append(paths, path)
+	}
+
+	var warnings []*generated.QueryWarning
+	for _, field := range unsupportedSortFields(sorter, paths) {
+		warnings = append(warnings, &generated.QueryWarning{Field: field, Reason: unsupportedSortReason})
+	}
+	return warnings
+}
@@ -0,0 +1,189 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// requireInvalidInput asserts an INVALID_INPUT error containing the given text
+func requireInvalidInput(t *testing.T, err error, contains string) {
+	t.Helper()
+	require.Error(t, err)
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ErrCodeInvalidInput, queryErr.Code)
+	assert.Contains(t, queryErr.Message, contains)
+}
+
+// Test order array validation for customer and team sorters
+func TestValidateSorter(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	desc := generated.SortEnumTypeDesc
+
+	tests := []struct {
+		name     string
+		sorter   interface{}
+		contains string // Empty when the sorter is valid
+	}{
+		{
+			name:   "Customer tie-breaking entries",
+			sorter: []*generated.CustomerQuerySorterInput{{LastName: &asc}, {FirstName: &asc}},
+		},
+		{
+			name:   "Nil order",
+			sorter: []*generated.CustomerQuerySorterInput(nil),
+		},
+		{
+			name: "Customer entries over the cap",
+			sorter: []*generated.CustomerQuerySorterInput{
+				{FirstName: &asc}, {LastName: &asc}, {BirthDate: &asc}, {EmployeeEmail: &asc}, {CreateDate: &asc}, {UserEmail: &asc},
+			},
+			contains: "at most 5 entries, got 6",
+		},
+		{
+			name:     "Customer duplicate field with conflicting directions",
+			sorter:   []*generated.CustomerQuerySorterInput{{LastName: &asc}, {LastName: &desc}},
+			contains: `duplicate sort field "lastName"`,
+		},
+		{
+			name: "Customer duplicate nested field",
+			sorter: []*generated.CustomerQuerySorterInput{
+				{Payment: &generated.CustomerPaymentObjectSorterInput{Status: &asc}},
+				{Payment: &generated.CustomerPaymentObjectSorterInput{Status: &desc}},
+			},
+			contains: `duplicate sort field "payment.status"`,
+		},
+		{
+			name:     "Customer empty entry",
+			sorter:   []*generated.CustomerQuerySorterInput{{LastName: &asc}, {}},
+			contains: "order entry 1 does not set a sort field",
+		},
+		{
+			name:     "Customer empty nested object",
+			sorter:   []*generated.CustomerQuerySorterInput{{Payment: &generated.CustomerPaymentObjectSorterInput{}}},
+			contains: "order entry 0 does not set a sort field",
+		},
+		{
+			name:     "Team null entry",
+			sorter:   []*generated.TeamQuerySorterInput{nil},
+			contains: "order entry 0 does not set a sort field",
+		},
+		{
+			name:     "Team duplicate field in one entry and the next",
+			sorter:   []*generated.TeamQuerySorterInput{{Name: &asc, IsShared: &asc}, {IsShared: &desc}},
+			contains: `duplicate sort field "isShared"`,
+		},
+		{
+			name: "Team entries over the cap",
+			sorter: []*generated.TeamQuerySorterInput{
+				{Name: &asc}, {Description: &asc}, {IsShared: &asc}, {EmployeeID: &asc}, {Name: &desc}, {Description: &desc},
+			},
+			contains: "at most 5 entries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSorter(tt.sorter)
+			if tt.contains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			requireInvalidInput(t, err, tt.contains)
+		})
+	}
+}
+
+// Test the entry cap follows the configured value
+func TestSetMaxSortEntries(t *testing.T) {
+	defer SetMaxSortEntries(DefaultMaxSortEntries)
+	asc := generated.SortEnumTypeAsc
+	sorter := []*generated.EmployeeQuerySorterInput{{LastName: &asc}, {FirstName: &asc}}
+
+	SetMaxSortEntries(1)
+	requireInvalidInput(t, validateSorter(sorter), "at most 1 entries, got 2")
+
+	SetMaxSortEntries(0) // Ignored
+	requireInvalidInput(t, validateSorter(sorter), "at most 1 entries")
+
+	SetMaxSortEntries(2)
+	assert.NoError(t, validateSorter(sorter))
+}
+
+// Test search and byKeys reject invalid order arrays before touching the database
+func TestSorterValidation_BeforeDatabaseWork(t *testing.T) {
+	ctx := context.Background()
+	asc := generated.SortEnumTypeAsc
+	desc := generated.SortEnumTypeDesc
+	order := []*generated.EmployeeQuerySorterInput{{LastName: &asc}, {LastName: &desc}}
+
+	// A nil database client would fail with DATABASE_ERROR if validation ran later
+	var employees []*generated.Employee
+	_, _, _, _, _, _, err := searchEntities(ctx, nil, entityConfigs["employee"], nil, order, SearchPaging{}, SearchOptions{}, &employees)
+	requireInvalidInput(t, err, `duplicate sort field "lastName"`)
+
+	err = getEntitiesByKeys(ctx, nil, entityConfigs["employee"], []string{"550e8400-e29b-41d4-a716-446655440000"}, order, &employees)
+	requireInvalidInput(t, err, `duplicate sort field "lastName"`)
+}
+
+// driftedSorterInput is a sorter input with a field its converter does not map, as if the schema
+// gained a sort field before the converter did
+type driftedSorterInput struct {
+	FirstName *generated.SortEnumType `json:"firstName,omitempty"`
+	Nickname  *generated.SortEnumType `json:"nickname,omitempty"`
+}
+
+func driftedSorterFields() []sorterField[driftedSorterInput] {
+	return []sorterField[driftedSorterInput]{
+		sortAsStored("firstName", func(s *driftedSorterInput) *generated.SortEnumType { return s.FirstName }),
+	}
+}
+
+// TestConvertSorter_UnsupportedField tests strict mode rejects an order setting a field the
+// converter does not map, naming it, while lenient mode sorts by the mapped fields only
+func TestConvertSorter_UnsupportedField(t *testing.T) {
+	asc := generated.SortEnumTypeAsc
+	entries := []*driftedSorterInput{{FirstName: &asc}, {Nickname: &asc}}
+
+	useLenientFilters(t, false)
+	_, err := convertSorter(entries, driftedSorterFields())
+	requireInvalidInput(t, err, `sort field "nickname" is not supported by this server`)
+	var queryErr *QueryError
+	require.ErrorAs(t, err, &queryErr)
+	assert.Equal(t, "order", queryErr.Field)
+
+	useLenientFilters(t, true)
+	stages, err := convertSorter(entries, driftedSorterFields())
+	require.NoError(t, err)
+	assert.Equal(t, []bson.M{{"$sort": bson.D{{Key: "firstName", Value: 1}, {Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}}}, stages)
+}
+
+// TestSearchSortWarnings tests lenient searches report each unmapped order field once, looked up
+// through the converter coverage of the order's input type
+func TestSearchSortWarnings(t *testing.T) {
+	previous := converterCoverage
+	converterCoverage = append(append([]inputCoverage{}, previous...),
+		inputCoverage{"customer", reflect.TypeOf(driftedSorterInput{}), sorterFieldPaths(driftedSorterFields())})
+	t.Cleanup(func() { converterCoverage = previous })
+
+	asc, desc := generated.SortEnumTypeAsc, generated.SortEnumTypeDesc
+	entries := []*driftedSorterInput{{Nickname: &desc}, {FirstName: &asc}, {Nickname: &asc}}
+
+	useLenientFilters(t, true)
+	want := []*generated.QueryWarning{{Field: "nickname", Reason: "this server does not sort by this field"}}
+	assert.Equal(t, want, searchSortWarnings(entries))
+	assert.Equal(t, want, searchWarnings(nil, entries, 0))
+	assert.Nil(t, searchSortWarnings([]*generated.CustomerQuerySorterInput{{FirstName: &asc}}))
+	assert.Nil(t, searchSortWarnings(nil))
+
+	useLenientFilters(t, false)
+	assert.Nil(t, searchSortWarnings(entries))
+}
@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// UUID validation regex pattern (RFC4122 format, case-insensitive)
+var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// objectIDRegex matches the 24 hex digits of a MongoDB ObjectId, which callers sometimes pass
+// where an identifier is expected
+var objectIDRegex = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// nilUUID is the all-zero UUID, which never identifies an entity
+const nilUUID = "00000000-0000-0000-0000-000000000000"
+
+// strictUUIDs requires identifiers to be RFC 4122 UUIDs of version 1 to 5. Without it any
+// 8-4-4-4-12 hex value other than the nil UUID is accepted
+var strictUUIDs = true
+
+// SetStrictUUIDs sets whether identifiers must carry an RFC 4122 version (1-5) and variant (true)
+// or only have the UUID shape (false)
+func SetStrictUUIDs(enabled bool) {
+	strictUUIDs = enabled
+}
+
+// checkUUID returns why a value is not an acceptable identifier, or "" when it is. The reason
+// names the value and is meant as the message of an INVALID_INPUT error
+// Supports uppercase, lowercase, and mixed case UUIDs
+func checkUUID(value string) string {
+	uuid := strings.ToLower(value)
+	switch {
+	case objectIDRegex.MatchString(uuid):
+		return fmt.Sprintf("invalid UUID format: %s looks like an ObjectId; this API uses UUIDs", value)
+	case !uuidRegex.MatchString(uuid):
+		return fmt.Sprintf("invalid UUID format: %s", value)
+	case uuid == nilUUID:
+		return fmt.Sprintf("invalid UUID: %s is the nil UUID, which identifies no entity", value)
+	case !strictUUIDs:
+		return ""
+	case uuid[14] < '1' || uuid[14] > '5':
+		return fmt.Sprintf("invalid UUID: %s has version %c; identifiers are RFC 4122 UUIDs of version 1-5", value, uuid[14])
+	case !strings.ContainsRune("89ab", rune(uuid[19])):
+		return fmt.Sprintf("invalid UUID: %s does not have the RFC 4122 variant", value)
+	}
+	return ""
+}
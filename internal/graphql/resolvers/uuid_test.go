@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		strict  string // Expected reason with strict UUIDs; empty when valid
+		lenient string // Expected reason without
+	}{
+		{"version 4", "550e8400-e29b-41d4-a716-446655440000", "", ""},
+		{"version 1 uppercase", "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", "", ""},
+		{"version 5", "886313e1-3b8a-5372-9b90-0c9aee199e5d", "", ""},
+		{"malformed", "employee-001", "invalid UUID format: employee-001", "invalid UUID format: employee-001"},
+		{"ObjectId", "5f1d7a3b9c8e4a2b1c0d9e8f", "looks like an ObjectId", "looks like an ObjectId"},
+		{"nil UUID", nilUUID, "is the nil UUID", "is the nil UUID"},
+		{"version 0", "550e8400-e29b-01d4-a716-446655440000", "has version 0", ""},
+		{"version 6", "1ec9414c-232a-6b00-b3c8-9e6bdeced846", "has version 6", ""},
+		{"NCS variant", "550e8400-e29b-41d4-7716-446655440000", "does not have the RFC 4122 variant", ""},
+		{"future variant", "550e8400-e29b-41d4-e716-446655440000", "does not have the RFC 4122 variant", ""},
+	}
+
+	t.Cleanup(func() { SetStrictUUIDs(true) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for strict, want := range map[bool]string{true: tt.strict, false: tt.lenient} {
+				SetStrictUUIDs(strict)
+				reason := checkUUID(tt.value)
+				if want == "" {
+					assert.Empty(t, reason, "strict=%v", strict)
+				} else {
+					assert.Contains(t, reason, want, "strict=%v", strict)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package resolvers
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// writeRetries replays failed auxiliary writes; nil while they are best-effort only
+var writeRetries atomic.Pointer[db.WriteRetryQueue]
+
+// SetWriteRetryQueue sets the queue replaying failed history, access audit and idempotency
+// writes; nil drops them after logging, as without the queue
+func SetWriteRetryQueue(queue *db.WriteRetryQueue) {
+	writeRetries.Store(queue)
+}
+
+// QueueWriteRetry queues a failed auxiliary write for replay (see db.WriteRetryQueue.Add).
+// Returns false when no queue is set and the write is lost
+func QueueWriteRetry(kind string, write func(ctx context.Context) error) bool {
+	return writeRetries.Load().Add(kind, write)
+}
+
+// CurrentWriteRetryStats returns the counters of the write retry queue, and false without one
+func CurrentWriteRetryStats() (db.WriteRetryStats, bool) {
+	queue := writeRetries.Load()
+	if queue == nil {
+		return db.WriteRetryStats{}, false
+	}
+	return queue.Stats(), true
+}
+
+// insertOnce inserts documents one at a time, skipping those already stored. The documents carry
+// their _id, so a replay after a failure that applied some of the inserts does not duplicate them
+func insertOnce(ctx context.Context, collection db.Collection, documents []interface{}) error {
+	for _, document := range documents {
+		if _, err := collection.InsertOne(ctx, document); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return nil
+}
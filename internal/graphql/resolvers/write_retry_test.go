@@ -0,0 +1,68 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+)
+
+// useWriteRetryQueue sets a retry queue for the test and returns it
+func useWriteRetryQueue(t *testing.T) *db.WriteRetryQueue {
+	t.Helper()
+	queue := db.NewWriteRetryQueue(db.WriteRetryOptions{Capacity: 10, BaseDelay: time.Second, MaxDelay: time.Second}, db.RealClock{}, zerolog.Nop())
+	SetWriteRetryQueue(queue)
+	t.Cleanup(func() { SetWriteRetryQueue(nil) })
+	return queue
+}
+
+// duplicateCollection stores documents by _id and fails inserts of stored ones with a duplicate key error
+type duplicateCollection struct {
+	db.Collection
+	stored []interface{}
+	ids    map[interface{}]bool
+}
+
+func (c *duplicateCollection) InsertOne(ctx context.Context, document interface{}) (*mongo.InsertOneResult, error) {
+	id := document.(bson.M)["_id"]
+	if c.ids[id] {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+	}
+	c.ids[id] = true
+	c.stored = append(c.stored, id)
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func TestInsertOnce_SkipsStoredDocuments(t *testing.T) {
+	collection := &duplicateCollection{ids: map[interface{}]bool{"a": true}}
+	err := insertOnce(context.Background(), collection, []interface{}{bson.M{"_id": "a"}, bson.M{"_id": "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"b"}, collection.stored)
+}
+
+// Test a failed audit batch is queued for replay instead of counted as failed
+func TestRunAccessAuditWriter_QueuesFailedBatch(t *testing.T) {
+	queue := useWriteRetryQueue(t)
+	enableAccessAudit(t, AccessAuditOptions{Entities: []string{"customer"}, QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	recordAccess(context.Background(), entityConfigs["customer"], generated.AccessQueryTypeGet, nil, &generated.Customer{Identifier: parityActiveID})
+
+	writeAccessAudit(&fakeAuditDB{err: errors.New("server selection timeout")})
+	stats, _ := CurrentAccessAuditStats()
+	assert.Equal(t, AccessAuditStats{}, stats)
+	assert.Equal(t, db.WriteRetryStats{Pending: 1, Queued: 1}, queue.Stats())
+}
+
+func TestQueueWriteRetry_WithoutQueue(t *testing.T) {
+	assert.False(t, QueueWriteRetry("test", func(ctx context.Context) error { return nil }))
+	_, ok := CurrentWriteRetryStats()
+	assert.False(t, ok)
+}
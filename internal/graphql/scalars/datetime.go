@@ -0,0 +1,109 @@
+// Package scalars implements the DateTime and Date GraphQL scalars. Both keep the Go type
+// string in the generated models; values are normalized when they are marshaled to clients
+// and when client input is unmarshaled, so stored and compared values share one representation
+package scalars
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// dateLayout is the representation of calendar dates (YYYY-MM-DD)
+const dateLayout = "2006-01-02"
+
+// ParseDateTime parses an RFC3339 timestamp with any offset and optional fractional seconds,
+// or a date (YYYY-MM-DD) as midnight UTC. The result is in UTC
+func ParseDateTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid DateTime %q: expected an RFC3339 timestamp (2024-01-31T12:00:00Z, 2024-01-31T13:00:00+01:00) or a date (2024-01-31)", value)
+}
+
+// FormatDateTime renders a timestamp as RFC3339 in UTC, with fractional seconds only when set
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// NormalizeDateTime parses a DateTime value and renders it as RFC3339 in UTC
+func NormalizeDateTime(value string) (string, error) {
+	t, err := ParseDateTime(value)
+	if err != nil {
+		return "", err
+	}
+	return FormatDateTime(t), nil
+}
+
+// MarshalDateTime writes a DateTime as RFC3339 in UTC. Stored values that are no timestamp
+// are written unchanged rather than failing the whole response
+func MarshalDateTime(value string) graphql.Marshaler {
+	if normalized, err := NormalizeDateTime(value); err == nil {
+		value = normalized
+	}
+	return graphql.MarshalString(value)
+}
+
+// UnmarshalDateTime reads a DateTime input as RFC3339 in UTC. The empty string, which
+// filters use for null, is kept
+func UnmarshalDateTime(v interface{}) (string, error) {
+	value, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("DateTime must be a string, got %T", v)
+	}
+	if value == "" {
+		return "", nil
+	}
+	return NormalizeDateTime(value)
+}
+
+// ParseDate parses a date (YYYY-MM-DD) or an RFC3339 timestamp, whose UTC date is taken.
+// The result is midnight UTC
+func ParseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		year, month, day := t.UTC().Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid Date %q: expected a date (2024-01-31) or an RFC3339 timestamp (2024-01-31T12:00:00Z)", value)
+}
+
+// FormatDate renders the UTC date of a timestamp as YYYY-MM-DD
+func FormatDate(t time.Time) string {
+	return t.UTC().Format(dateLayout)
+}
+
+// NormalizeDate parses a Date value and renders it as YYYY-MM-DD
+func NormalizeDate(value string) (string, error) {
+	t, err := ParseDate(value)
+	if err != nil {
+		return "", err
+	}
+	return FormatDate(t), nil
+}
+
+// MarshalDate writes a Date as YYYY-MM-DD. Stored values that are no date are written unchanged
+func MarshalDate(value string) graphql.Marshaler {
+	if normalized, err := NormalizeDate(value); err == nil {
+		value = normalized
+	}
+	return graphql.MarshalString(value)
+}
+
+// UnmarshalDate reads a Date input as YYYY-MM-DD. The empty string is kept
+func UnmarshalDate(v interface{}) (string, error) {
+	value, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("Date must be a string, got %T", v)
+	}
+	if value == "" {
+		return "", nil
+	}
+	return NormalizeDate(value)
+}
@@ -1,21 +1,34 @@
 package graphql
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
 )
 
+// placeholderPattern matches {{Name}} placeholders used in schema descriptions
+var placeholderPattern = regexp.MustCompile(`\{\{[A-Za-z0-9_.]+\}\}`)
+
 // Schema represents a loaded and validated GraphQL schema
 type Schema struct {
 	Schema     *ast.Schema
 	RawContent string
 	LoadedAt   time.Time
 	SchemaPath string
+	Checksum   string // Hex SHA-256 of RawContent, identifying the schema build
 }
 
 // LoadSchema loads and validates the GraphQL schema from the specified file
@@ -37,10 +50,16 @@ func LoadSchema(schemaPath string) (*Schema, error) {
 		return nil, fmt.Errorf("schema file is empty: %s", schemaPath)
 	}
 
+	// Fill description placeholders with the limits and entity rules enforced in code
+	interpolated, err := interpolateDescriptions(string(content), resolvers.SchemaDescriptionValues())
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse and validate schema
 	source := &ast.Source{
 		Name:  schemaPath,
-		Input: string(content),
+		Input: interpolated,
 	}
 
 	schema, gqlErr := gqlparser.LoadSchema(source)
@@ -55,15 +74,118 @@ func LoadSchema(schemaPath string) (*Schema, error) {
 
 	loadedSchema := &Schema{
 		Schema:     schema,
-		RawContent: string(content),
+		RawContent: interpolated,
 		LoadedAt:   time.Now(),
 		SchemaPath: schemaPath,
+		Checksum:   checksum(interpolated),
 	}
 
 	log.Info().
 		Str("path", schemaPath).
 		Int("types", len(schema.Types)).
+		Str("checksum", loadedSchema.Checksum).
 		Msg("Schema loaded and validated successfully")
 
 	return loadedSchema, nil
 }
+
+// interpolateDescriptions replaces {{Name}} placeholders in the schema source with the given values
+// Returns error if a placeholder has no value, so descriptions never ship with template markers
+func interpolateDescriptions(content string, values map[string]string) (string, error) {
+	pairs := make([]string, 0, len(values)*2)
+	for name, value := range values {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+
+	interpolated := strings.NewReplacer(pairs...).Replace(content)
+
+	if unresolved := placeholderPattern.FindString(interpolated); unresolved != "" {
+		return "", fmt.Errorf("unresolved schema description placeholder: %s", unresolved)
+	}
+
+	return interpolated, nil
+}
+
+// checksum returns the hex SHA-256 of the schema text
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckImplemented returns what schema declares that the executable schema generated into this
+// build cannot serve: types, fields, arguments, enum values, union members and interfaces it
+// does not implement, or implements with another type. Descriptions and directives are not
+// compared, and definitions schema leaves out are allowed
+func CheckImplemented(schema *ast.Schema) []string {
+	compiled := generated.NewExecutableSchema(generated.Config{}).Schema()
+
+	names := make([]string, 0, len(schema.Types))
+	for name, definition := range schema.Types {
+		if !definition.BuiltIn {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		definition := schema.Types[name]
+		implemented := compiled.Types[name]
+		if implemented == nil {
+			problems = append(problems, fmt.Sprintf("type %s is not implemented by this build", name))
+			continue
+		}
+		if implemented.Kind != definition.Kind {
+			problems = append(problems, fmt.Sprintf("type %s is %s, this build implements %s", name, definition.Kind, implemented.Kind))
+			continue
+		}
+		problems = append(problems, checkFieldsImplemented(definition, implemented)...)
+		for _, value := range definition.EnumValues {
+			if implemented.EnumValues.ForName(value.Name) == nil {
+				problems = append(problems, fmt.Sprintf("enum value %s.%s is not implemented by this build", name, value.Name))
+			}
+		}
+		problems = append(problems, missingNames(name, "member", definition.Types, implemented.Types)...)
+		problems = append(problems, missingNames(name, "interface", definition.Interfaces, implemented.Interfaces)...)
+	}
+	return problems
+}
+
+// checkFieldsImplemented compares the fields of a type, and their arguments, with the implemented type
+func checkFieldsImplemented(definition, implemented *ast.Definition) []string {
+	var problems []string
+	for _, field := range definition.Fields {
+		coordinate := definition.Name + "." + field.Name
+		implementedField := implemented.Fields.ForName(field.Name)
+		if implementedField == nil {
+			problems = append(problems, fmt.Sprintf("%s is not implemented by this build", coordinate))
+			continue
+		}
+		if field.Type.String() != implementedField.Type.String() {
+			problems = append(problems, fmt.Sprintf("%s has type %s, this build implements %s", coordinate, field.Type, implementedField.Type))
+		}
+		for _, argument := range field.Arguments {
+			argumentCoordinate := coordinate + "(" + argument.Name + ":)"
+			implementedArgument := implementedField.Arguments.ForName(argument.Name)
+			if implementedArgument == nil {
+				problems = append(problems, fmt.Sprintf("%s is not implemented by this build", argumentCoordinate))
+				continue
+			}
+			if argument.Type.String() != implementedArgument.Type.String() {
+				problems = append(problems, fmt.Sprintf("%s has type %s, this build implements %s", argumentCoordinate, argument.Type, implementedArgument.Type))
+			}
+		}
+	}
+	return problems
+}
+
+// missingNames returns a problem for each of a type's union members or interfaces the implemented type lacks
+func missingNames(typeName, kind string, names, implemented []string) []string {
+	var problems []string
+	for _, name := range names {
+		if !slices.Contains(implemented, name) {
+			problems = append(problems, fmt.Sprintf("%s %s of %s is not implemented by this build", kind, name, typeName))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,102 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// RequestIDMetadata is the metadata key of the request ID, as the X-Request-ID header of HTTP
+const RequestIDMetadata = "x-request-id"
+
+// serverCredentials returns the TLS credentials of the listener, requiring client certificates
+// that chain to the client CA when one is configured
+func serverCredentials(opts Options) (credentials.TransportCredentials, error) {
+	certificate, err := tls.LoadX509KeyPair(opts.TLSCertFile, opts.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load gRPC server certificate: %w", err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+
+	if opts.TLSClientCAFile != "" {
+		bundle, err := os.ReadFile(opts.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read gRPC client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("gRPC client CA %s holds no PEM certificate", opts.TLSClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// authenticate returns an interceptor admitting calls that pass every configured mechanism: the
+// static token, and a verified client certificate when client CAs are configured. The caller
+// (the certificate's common name, or "token") and the request ID are added to the call's loggers.
+// Authenticated services are internal callers, so no user authorization scope applies
+func authenticate(opts Options) grpc.UnaryServerInterceptor {
+	token := []byte(opts.AuthToken)
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		requestID := firstValue(md, RequestIDMetadata)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		ctx = logger.WithContextField(ctx, "request_id", requestID)
+
+		client := "token"
+		if opts.TLSClientCAFile != "" {
+			commonName, ok := clientCommonName(ctx)
+			if !ok {
+				return nil, status.Error(codes.Unauthenticated, "client certificate required")
+			}
+			client = commonName
+		}
+		if len(token) > 0 {
+			presented, found := strings.CutPrefix(firstValue(md, "authorization"), "Bearer ")
+			if !found || subtle.ConstantTimeCompare([]byte(presented), token) != 1 {
+				return nil, status.Error(codes.Unauthenticated, "invalid or missing token")
+			}
+		}
+
+		return handler(logger.WithContextField(ctx, "grpc_client", client), request)
+	}
+}
+
+// clientCommonName returns the common name of the verified client certificate of a call
+func clientCommonName(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName, true
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// statusCodes maps the codes of resolver errors to gRPC status codes
+var statusCodes = map[string]codes.Code{
+	resolvers.ErrCodeNotFound:            codes.NotFound,
+	resolvers.ErrCodeInvalidInput:        codes.InvalidArgument,
+	resolvers.ErrCodeUnauthorized:        codes.Unauthenticated,
+	resolvers.ErrCodeForbidden:           codes.PermissionDenied,
+	resolvers.ErrCodeDatabaseError:       codes.Unavailable,
+	resolvers.ErrCodeTimeout:             codes.DeadlineExceeded,
+	resolvers.ErrCodeExternalService:     codes.Unavailable,
+	resolvers.ErrCodeResourceExhausted:   codes.ResourceExhausted,
+	resolvers.ErrCodeServiceUnavailable:  codes.Unavailable,
+	resolvers.ErrCodeFeatureDisabled:     codes.Unimplemented,
+	resolvers.ErrCodeCursorExpired:       codes.FailedPrecondition,
+	resolvers.ErrCodeOperationNotAllowed: codes.FailedPrecondition,
+}
+
+// toStatus returns the gRPC status error of a resolver error. The message is the GraphQL
+// error message; errors without a known code are Internal
+func toStatus(err error) error {
+	var queryErr *resolvers.QueryError
+	if !errors.As(err, &queryErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	code, ok := statusCodes[queryErr.Code]
+	if !ok {
+		code = codes.Internal
+	}
+	return status.Error(code, queryErr.Message)
+}
+
+// featureDisabled returns the status of a call to a method whose GraphQL field is disabled
+func featureDisabled(field string) error {
+	return status.Errorf(codes.Unimplemented, "%s is disabled on this server", field)
+}
@@ -0,0 +1,224 @@
+// Package grpcapi serves the customer read API of api/air/v1 over gRPC for internal services
+// that do not use GraphQL. Its methods are thin adapters over the customerGet,
+// customerByKeysGet and customerSearch resolvers, so validation, deletion exclusion and limits
+// are those of the GraphQL queries
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	airv1 "github.com/yourusername/air-go/api/air/v1"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// Options configures the authentication of the gRPC server. Every configured mechanism is
+// required; at least one must be
+type Options struct {
+	// Static token clients send as "authorization: Bearer <token>" metadata
+	AuthToken string
+
+	// Server certificate and key; the listener is plaintext without them
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// CA bundle client certificates must chain to (mTLS); needs the server certificate
+	TLSClientCAFile string
+}
+
+// NewServer returns a gRPC server serving the CustomerService over dbClient
+func NewServer(dbClient resolvers.DBClient, opts Options) (*grpc.Server, error) {
+	if opts.AuthToken == "" && opts.TLSClientCAFile == "" {
+		return nil, fmt.Errorf("the gRPC server needs a static token or client certificates")
+	}
+
+	serverOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(
+		authenticate(opts),
+		logCalls,
+	)}
+	if opts.TLSCertFile != "" {
+		creds, err := serverCredentials(opts)
+		if err != nil {
+			return nil, err
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(serverOpts...)
+	airv1.RegisterCustomerServiceServer(server, NewCustomerService(resolvers.NewResolver(dbClient).Query()))
+	return server, nil
+}
+
+// CustomerService implements airv1.CustomerServiceServer over the GraphQL query resolvers
+type CustomerService struct {
+	airv1.UnimplementedCustomerServiceServer
+	query generated.QueryResolver
+}
+
+// NewCustomerService returns the customer service answering through query
+func NewCustomerService(query generated.QueryResolver) *CustomerService {
+	return &CustomerService{query: query}
+}
+
+// The methods answer Unimplemented when the GraphQL field they adapt is disabled
+// (DISABLED_ENTITIES, DISABLED_OPERATIONS), as the GraphQL endpoint answers FEATURE_DISABLED
+
+// GetCustomer answers like customerGet
+func (s *CustomerService) GetCustomer(ctx context.Context, request *airv1.GetCustomerRequest) (*airv1.GetCustomerResponse, error) {
+	if resolvers.FieldDisabled("customerGet") {
+		return nil, featureDisabled("customerGet")
+	}
+	customer, err := s.query.CustomerGet(ctx, request.GetIdentifier())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if customer == nil {
+		return &airv1.GetCustomerResponse{}, nil
+	}
+	return &airv1.GetCustomerResponse{Customer: toCustomer(customer)}, nil
+}
+
+// GetCustomersByKeys answers like customerByKeysGet without an order
+func (s *CustomerService) GetCustomersByKeys(ctx context.Context, request *airv1.GetCustomersByKeysRequest) (*airv1.GetCustomersByKeysResponse, error) {
+	if resolvers.FieldDisabled("customerByKeysGet") {
+		return nil, featureDisabled("customerByKeysGet")
+	}
+	customers, err := s.query.CustomerByKeysGet(ctx, request.GetIdentifiers(), nil)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &airv1.GetCustomersByKeysResponse{Customers: toCustomers(customers)}, nil
+}
+
+// SearchCustomers answers like customerSearch with the filter of SearchFilter
+func (s *CustomerService) SearchCustomers(ctx context.Context, request *airv1.SearchCustomersRequest) (*airv1.SearchCustomersResponse, error) {
+	if resolvers.FieldDisabled("customerSearch") {
+		return nil, featureDisabled("customerSearch")
+	}
+	var first *int64
+	if request.GetFirst() != 0 {
+		value := int64(request.GetFirst())
+		first = &value
+	}
+	var after *string
+	if request.GetAfter() != "" {
+		after = &request.After
+	}
+
+	filter, err := SearchFilter(request)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.query.CustomerSearch(ctx, filter, nil, first, after, nil, nil, nil, nil, nil)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &airv1.SearchCustomersResponse{Customers: toCustomers(page.Data), TotalCount: page.TotalCount}
+	if page.Paging != nil {
+		response.HasNextPage = page.Paging.HasNextPage
+		if page.Paging.EndCursor != nil {
+			response.EndCursor = *page.Paging.EndCursor
+		}
+	}
+	return response, nil
+}
+
+// SearchFilter returns the customerSearch filter of a search request: employeeId in the
+// employee IDs, firstName or lastName containing the name, and status.activation equal to the
+// status, each when set. It returns nil for a request without conditions, and InvalidArgument
+// for a status that is not a UserStatus value of the GraphQL schema
+func SearchFilter(request *airv1.SearchCustomersRequest) (*generated.CustomerQueryFilterInput, error) {
+	var conditions []*generated.CustomerQueryFilterInput
+	if ids := request.GetEmployeeIds(); len(ids) > 0 {
+		in := make([]*string, len(ids))
+		for i := range ids {
+			in[i] = &ids[i]
+		}
+		conditions = append(conditions, &generated.CustomerQueryFilterInput{
+			EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{In: in},
+		})
+	}
+	if name := request.GetNameContains(); name != "" {
+		conditions = append(conditions, &generated.CustomerQueryFilterInput{Or: []*generated.CustomerQueryFilterInput{
+			{FirstName: &generated.StringFilterInput{Contains: &name}},
+			{LastName: &generated.StringFilterInput{Contains: &name}},
+		}})
+	}
+	if activation := request.GetActivationStatus(); activation != "" {
+		userStatus := generated.UserStatus(activation)
+		if !userStatus.IsValid() {
+			values := make([]string, len(generated.AllUserStatus))
+			for i, value := range generated.AllUserStatus {
+				values[i] = string(value)
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "activation_status %q is not one of %s", activation, strings.Join(values, ", "))
+		}
+		conditions = append(conditions, &generated.CustomerQueryFilterInput{
+			Status: &generated.CustomerStatusObjectFilterInput{
+				Activation: &generated.EnumFilterOfNullableOfUserStatusInput{Eq: &userStatus},
+			},
+		})
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	return &generated.CustomerQueryFilterInput{And: conditions}, nil
+}
+
+// toCustomers converts resolver results to API customers
+func toCustomers(customers []*generated.Customer) []*airv1.Customer {
+	converted := make([]*airv1.Customer, 0, len(customers))
+	for _, customer := range customers {
+		converted = append(converted, toCustomer(customer))
+	}
+	return converted
+}
+
+// toCustomer converts a resolver result to an API customer
+func toCustomer(customer *generated.Customer) *airv1.Customer {
+	converted := &airv1.Customer{
+		Identifier:    customer.Identifier,
+		FirstName:     stringValue(customer.FirstName),
+		LastName:      stringValue(customer.LastName),
+		UserEmail:     stringValue(customer.UserEmail),
+		EmployeeId:    stringValue(customer.EmployeeID),
+		EmployeeEmail: stringValue(customer.EmployeeEmail),
+		CreateDate:    stringValue(customer.CreateDate),
+	}
+	if customer.Status != nil && customer.Status.Activation != nil {
+		converted.ActivationStatus = string(*customer.Status.Activation)
+	}
+	return converted
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// logCalls logs every call with its method, gRPC code and duration, like the HTTP request log
+func logCalls(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	startTime := time.Now()
+	response, err := handler(ctx, request)
+
+	event := logger.FromContext(ctx).Info()
+	if err != nil {
+		event = logger.FromContext(ctx).Warn().Err(err)
+	}
+	event.
+		Str("method", strings.TrimPrefix(info.FullMethod, "/")).
+		Str("code", status.Code(err).String()).
+		Dur("duration_ms", time.Since(startTime)).
+		Msg("gRPC call completed")
+	return response, err
+}
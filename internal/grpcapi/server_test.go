@@ -0,0 +1,128 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	airv1 "github.com/yourusername/air-go/api/air/v1"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+const (
+	testToken      = "0123456789abcdef0123456789abcdef"
+	testCustomerID = "4f7c1e9a-2b3d-4c5e-8f6a-7b8c9d0e1f2a"
+)
+
+// startServer serves NewServer over an in-memory listener and returns a client of it
+func startServer(t *testing.T, dbClient *testutil.MockDBClient) airv1.CustomerServiceClient {
+	t.Helper()
+	server, err := NewServer(dbClient, Options{AuthToken: testToken})
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return airv1.NewCustomerServiceClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestNewServer_RequiresAuthentication(t *testing.T) {
+	_, err := NewServer(new(testutil.MockDBClient), Options{})
+	assert.Error(t, err)
+}
+
+// TestAuthenticate_Token tests calls without the static token are rejected before any query
+func TestAuthenticate_Token(t *testing.T) {
+	dbClient := new(testutil.MockDBClient)
+	collection := new(testutil.MockCollection)
+	dbClient.On("Collection", "customers").Return(collection)
+	collection.On("FindOne", mock.Anything, mock.Anything).Return(testutil.FoundResult(bson.M{
+		"identifier": testCustomerID,
+		"firstName":  "Ada",
+		"status":     bson.M{"activation": "ACTIVE", "deletion": "INIT"},
+	}))
+	client := startServer(t, dbClient)
+	request := &airv1.GetCustomerRequest{Identifier: testCustomerID}
+
+	_, err := client.GetCustomer(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.GetCustomer(withToken("wrong"+testToken), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	collection.AssertNotCalled(t, "FindOne", mock.Anything, mock.Anything)
+
+	response, err := client.GetCustomer(withToken(testToken), request)
+	require.NoError(t, err)
+	assert.Equal(t, testCustomerID, response.GetCustomer().GetIdentifier())
+	assert.Equal(t, "Ada", response.GetCustomer().GetFirstName())
+	assert.Equal(t, "ACTIVE", response.GetCustomer().GetActivationStatus())
+}
+
+// TestGetCustomer_Status tests resolver errors and missing customers map to gRPC answers
+func TestGetCustomer_Status(t *testing.T) {
+	dbClient := new(testutil.MockDBClient)
+	collection := new(testutil.MockCollection)
+	dbClient.On("Collection", "customers").Return(collection)
+	collection.On("FindOne", mock.Anything, mock.Anything).Return(testutil.NotFoundResult())
+	client := startServer(t, dbClient)
+
+	_, err := client.GetCustomer(withToken(testToken), &airv1.GetCustomerRequest{Identifier: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	response, err := client.GetCustomer(withToken(testToken), &airv1.GetCustomerRequest{Identifier: testCustomerID})
+	require.NoError(t, err)
+	assert.Nil(t, response.GetCustomer(), "a missing customer answers an empty response, as customerGet answers null")
+}
+
+func TestSearchFilter(t *testing.T) {
+	filter, err := SearchFilter(&airv1.SearchCustomersRequest{First: 10})
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+
+	filter, err = SearchFilter(&airv1.SearchCustomersRequest{
+		EmployeeIds:      []string{"e1", "e2"},
+		NameContains:     "ada",
+		ActivationStatus: "ACTIVE",
+	})
+	require.NoError(t, err)
+	require.Len(t, filter.And, 3)
+	require.Len(t, filter.And[0].EmployeeID.In, 2)
+	assert.Equal(t, "e2", *filter.And[0].EmployeeID.In[1])
+	assert.Equal(t, "ada", *filter.And[1].Or[0].FirstName.Contains)
+	assert.Equal(t, "ada", *filter.And[1].Or[1].LastName.Contains)
+	assert.Equal(t, generated.UserStatus("ACTIVE"), *filter.And[2].Status.Activation.Eq)
+}
+
+// TestSearchCustomers_UnknownActivationStatus tests a status outside the GraphQL UserStatus enum
+// is rejected before the database is queried
+func TestSearchCustomers_UnknownActivationStatus(t *testing.T) {
+	client := startServer(t, new(testutil.MockDBClient))
+
+	_, err := client.SearchCustomers(withToken(testToken), &airv1.SearchCustomersRequest{ActivationStatus: "active"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, `activation_status "active" is not one of INIT, ACTIVE, BLOCKED`, status.Convert(err).Message())
+}
@@ -6,22 +6,108 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/yourusername/air-go/internal/buildinfo"
 	"github.com/yourusername/air-go/internal/db"
 )
 
 // DatabaseHealth represents database connectivity status (T091)
 type DatabaseHealth struct {
 	Status    string `json:"status"`          // connected, disconnected, error
+	State     string `json:"state,omitempty"` // Connection lifecycle: disconnected, connecting, connected, disconnecting
 	Message   string `json:"message"`         // Human-readable status message
 	LatencyMs int64  `json:"latency_ms"`      // Ping latency in milliseconds
 	Error     string `json:"error,omitempty"` // Error details if status is error
+
+	Topology  *db.TopologySummary `json:"topology,omitempty"`  // Known servers, primary and event counts
+	Resources *db.ResourceSummary `json:"resources,omitempty"` // Goroutines, connections in use and open cursors per sample
+	Reader    *DatabaseHealth     `json:"reader,omitempty"`    // Separate read connection, omitted when reads use the primary client
+}
+
+// newDatabaseHealth converts a database health status, including its read connection
+func newDatabaseHealth(status *db.HealthStatus) *DatabaseHealth {
+	health := &DatabaseHealth{
+		Status:    status.Status,
+		State:     status.State,
+		Message:   status.Message,
+		LatencyMs: status.LatencyMs,
+		Error:     status.Error,
+		Topology:  status.Topology,
+		Resources: status.Resources,
+	}
+	if status.Reader != nil {
+		health.Reader = newDatabaseHealth(status.Reader)
+	}
+	return health
+}
+
+// SchemaInfo identifies the GraphQL schema build served by this instance
+type SchemaInfo struct {
+	Path      string `json:"path"`       // Schema file path
+	TypeCount int    `json:"type_count"` // Number of types, including built-ins
+	Checksum  string `json:"checksum"`   // Hex SHA-256 of the loaded schema text
+	LoadedAt  string `json:"loaded_at"`  // RFC3339 load timestamp
+}
+
+// SchemaReload is the result of an admin-triggered schema reload
+type SchemaReload struct {
+	Status           string   `json:"status"`            // reloaded, unchanged, rejected
+	At               string   `json:"at"`                // RFC3339 timestamp of the reload
+	PreviousChecksum string   `json:"previous_checksum"` // Checksum of the schema served before the reload
+	Checksum         string   `json:"checksum"`          // Checksum of the reloaded schema file, empty when it could not be read
+	Errors           []string `json:"errors,omitempty"`  // Why the reloaded schema was rejected; the previous one is still served
+}
+
+// BuildInfo identifies the binary, injected via ldflags (see internal/buildinfo)
+type BuildInfo struct {
+	GitSHA    string `json:"git_sha"`
+	BuildTime string `json:"build_time"`
+}
+
+// CurrentBuildInfo returns the build metadata linked into this binary
+func CurrentBuildInfo() BuildInfo {
+	return BuildInfo{
+		GitSHA:    buildinfo.GitSHA,
+		BuildTime: buildinfo.BuildTime,
+	}
+}
+
+// EntityProblem reports an entity config that does not match the live database
+type EntityProblem struct {
+	Entity     string `json:"entity"`
+	Collection string `json:"collection"`
+	Problem    string `json:"problem"`
+}
+
+// EntityValidation is the result of the startup entity config validation
+type EntityValidation struct {
+	Status   string          `json:"status"` // ok, problems, error (validation could not complete)
+	Problems []EntityProblem `json:"problems,omitempty"`
+}
+
+// ServiceInfo describes the running service build
+type ServiceInfo struct {
+	Schema           *SchemaInfo       `json:"schema,omitempty"` // Nil when no schema was loaded
+	Build            BuildInfo         `json:"build"`
+	EntityValidation *EntityValidation `json:"entity_validation,omitempty"` // Nil when the startup validation is disabled
+	ReadOnly         bool              `json:"read_only"`                   // Mutations and database writes are refused
+	SchemaReload     *SchemaReload     `json:"schema_reload,omitempty"`     // Last schema reload, nil before the first
+}
+
+// SearchConcurrency reports the search concurrency limiter; weights are requested rows
+type SearchConcurrency struct {
+	InUse    int64 `json:"in_use"`   // Rows held by running searches
+	Max      int64 `json:"max"`      // Rows running searches may hold
+	Waiting  int64 `json:"waiting"`  // Searches queued for capacity
+	Rejected int64 `json:"rejected"` // Searches rejected after the queue timeout since startup
 }
 
 // Response represents the health check response structure (T091)
 type Response struct {
-	Status    string          `json:"status"`             // Overall status: ok, degraded
-	Timestamp string          `json:"timestamp"`          // RFC3339 timestamp
-	Database  *DatabaseHealth `json:"database,omitempty"` // Database health (optional)
+	Status    string             `json:"status"`             // Overall status: ok, degraded
+	Timestamp string             `json:"timestamp"`          // RFC3339 timestamp
+	Database  *DatabaseHealth    `json:"database,omitempty"` // Database health (optional)
+	Service   *ServiceInfo       `json:"service,omitempty"`  // Schema and build metadata (optional)
+	Search    *SearchConcurrency `json:"search,omitempty"`   // Search concurrency limiter (optional)
 }
 
 // DBHealthChecker interface for checking database health
@@ -32,13 +118,21 @@ type DBHealthChecker interface {
 }
 
 // Handler returns an HTTP handler for the health check endpoint
-// If dbClient is nil, only basic health status is returned
-func Handler(dbClient DBHealthChecker) http.HandlerFunc {
+// If dbClient is nil, only basic health status is returned; info and search are omitted when nil.
+// info is read per request, as a schema reload replaces the service info
+func Handler(dbClient DBHealthChecker, info func() *ServiceInfo, search func() SearchConcurrency) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		response := Response{
 			Status:    "ok",
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
 		}
+		if info != nil {
+			response.Service = info()
+		}
+		if search != nil {
+			stats := search()
+			response.Search = &stats
+		}
 
 		// Include database health if client is provided (T090)
 		if dbClient != nil {
@@ -47,15 +141,10 @@ func Handler(dbClient DBHealthChecker) http.HandlerFunc {
 
 			dbHealth, err := dbClient.HealthStatus(ctx)
 			if err == nil && dbHealth != nil {
-				response.Database = &DatabaseHealth{
-					Status:    dbHealth.Status,
-					Message:   dbHealth.Message,
-					LatencyMs: dbHealth.LatencyMs,
-					Error:     dbHealth.Error,
-				}
+				response.Database = newDatabaseHealth(dbHealth)
 
-				// If database is not connected, set overall status to degraded
-				if dbHealth.Status != "connected" {
+				// If either connection is down, set overall status to degraded
+				if dbHealth.Status != "connected" || (dbHealth.Reader != nil && dbHealth.Reader.Status != "connected") {
 					response.Status = "degraded"
 				}
 			} else {
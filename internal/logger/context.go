@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// contextField is a request-scoped log field carried by a context
+type contextField struct {
+	key   string
+	value string
+}
+
+// contextFields adds the request-scoped fields of a context to log entries. As a hook it only
+// runs for entries that are written, so loggers taken from a context cost little for disabled levels
+type contextFields []contextField
+
+// Run implements zerolog.Hook
+func (f *contextFields) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	for _, field := range *f {
+		e.Str(field.key, field.value)
+	}
+}
+
+// contextFieldsKey is the context key of the request-scoped log fields
+type contextFieldsKey struct{}
+
+// WithContextField returns a context whose loggers carry a request-scoped field, such as the
+// request ID or the principal. A field already present under key is replaced
+func WithContextField(ctx context.Context, key, value string) context.Context {
+	var updated contextFields
+	if fields, ok := ctx.Value(contextFieldsKey{}).(*contextFields); ok {
+		updated = make(contextFields, 0, len(*fields)+1)
+		for _, field := range *fields {
+			if field.key != key {
+				updated = append(updated, field)
+			}
+		}
+	}
+	updated = append(updated, contextField{key: key, value: value})
+	return context.WithValue(ctx, contextFieldsKey{}, &updated)
+}
+
+// FromContext returns the global logger enriched with the request-scoped fields of ctx
+func FromContext(ctx context.Context) *zerolog.Logger {
+	return Enrich(ctx, log.Logger)
+}
+
+// Enrich returns base with the request-scoped fields of ctx added, or base itself when ctx
+// carries none. Components with a logger of their own use it to keep their fields
+func Enrich(ctx context.Context, base zerolog.Logger) *zerolog.Logger {
+	if fields, ok := ctx.Value(contextFieldsKey{}).(*contextFields); ok {
+		base = base.Hook(fields)
+	}
+	return &base
+}
@@ -1,33 +1,157 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
-// Setup initializes the global logger with the specified format
-func Setup(format string) {
-	var output io.Writer = os.Stdout
+// Output destinations besides a file path
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputSplit  = "split" // Warnings and above to stderr, the rest to stdout
+)
 
-	if format == "console" {
-		// Human-readable console output for development
-		output = zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			TimeFormat: time.RFC3339,
+// Options configure the logger built by Setup
+type Options struct {
+	Format   string // json or console
+	Level    string // Minimum level (trace, debug, info, warn, error); empty is info
+	Sampling string // Per-level sampling such as "debug=100,info=10": every Nth entry of the level is written
+	Caller   bool   // Add the file and line of the log call
+	Output   string // stdout, stderr, split or a file path; empty is stdout
+}
+
+// Setup builds the logger described by opts, installs it as the global logger and returns it,
+// so components taking a logger receive the same instance. A file output stays open for the
+// lifetime of the process
+func Setup(opts Options) (zerolog.Logger, error) {
+	level := zerolog.InfoLevel
+	if opts.Level != "" {
+		parsed, err := zerolog.ParseLevel(opts.Level)
+		if err != nil {
+			return zerolog.Nop(), fmt.Errorf("invalid log level %q", opts.Level)
 		}
+		level = parsed
+	}
+	sampling, err := ParseSampling(opts.Sampling)
+	if err != nil {
+		return zerolog.Nop(), err
+	}
+	output, err := openOutput(opts.Output, opts.Format)
+	if err != nil {
+		return zerolog.Nop(), err
 	}
 
 	// Configure zerolog
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = zerolog.New(output).
-		With().
-		Timestamp().
-		Caller().
-		Logger()
+	builder := zerolog.New(output).Level(level).With().Timestamp()
+	if opts.Caller {
+		builder = builder.Caller()
+	}
+	logger := builder.Logger()
+	if len(sampling) > 0 {
+		logger = logger.Sample(levelSampler(sampling))
+	}
+
+	log.Logger = logger
+	return logger, nil
+}
+
+// ParseSampling parses a comma-separated list of level=N pairs into the sampling rate per level.
+// Fatal and panic entries are never sampled
+func ParseSampling(spec string) (map[zerolog.Level]uint32, error) {
+	rates := map[zerolog.Level]uint32{}
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, every, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid log sampling %q: expected level=N", pair)
+		}
+		level, err := zerolog.ParseLevel(strings.TrimSpace(name))
+		if err != nil || level < zerolog.TraceLevel || level > zerolog.ErrorLevel {
+			return nil, fmt.Errorf("invalid log sampling %q: level must be trace, debug, info, warn or error", pair)
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(every), 10, 32)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid log sampling %q: N must be a positive integer", pair)
+		}
+		rates[level] = uint32(n)
+	}
+	return rates, nil
+}
+
+// levelSampler writes every Nth entry of the sampled levels and every entry of the others
+func levelSampler(rates map[zerolog.Level]uint32) zerolog.Sampler {
+	sampler := zerolog.LevelSampler{}
+	for level, n := range rates {
+		basic := &zerolog.BasicSampler{N: n}
+		switch level {
+		case zerolog.TraceLevel:
+			sampler.TraceSampler = basic
+		case zerolog.DebugLevel:
+			sampler.DebugSampler = basic
+		case zerolog.InfoLevel:
+			sampler.InfoSampler = basic
+		case zerolog.WarnLevel:
+			sampler.WarnSampler = basic
+		case zerolog.ErrorLevel:
+			sampler.ErrorSampler = basic
+		}
+	}
+	return sampler
+}
+
+// openOutput returns the writer of an output destination in the given format
+func openOutput(output, format string) (io.Writer, error) {
+	switch output {
+	case "", OutputStdout:
+		return formatted(os.Stdout, format), nil
+	case OutputStderr:
+		return formatted(os.Stderr, format), nil
+	case OutputSplit:
+		return splitWriter{low: formatted(os.Stdout, format), high: formatted(os.Stderr, format)}, nil
+	}
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log output: %w", err)
+	}
+	return formatted(file, format), nil
+}
+
+// formatted wraps out in a human-readable console writer for the console format
+func formatted(out io.Writer, format string) io.Writer {
+	if format == "console" {
+		return zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339}
+	}
+	return out
+}
+
+// splitWriter writes warnings and above to high and the other entries to low
+type splitWriter struct {
+	low, high io.Writer
+}
+
+// Write implements io.Writer for entries without a level
+func (w splitWriter) Write(p []byte) (int, error) {
+	return w.low.Write(p)
+}
+
+// WriteLevel implements zerolog.LevelWriter
+func (w splitWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level >= zerolog.WarnLevel && level != zerolog.NoLevel {
+		return w.high.Write(p)
+	}
+	return w.low.Write(p)
 }
 
 // WithRequestID returns a logger with the request ID in context
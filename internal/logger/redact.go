@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/db/canonical"
+)
+
+// DefaultRedactedFields are the field names whose values are redacted unless configured otherwise
+var DefaultRedactedFields = []string{"userEmail", "firstName", "lastName", "birthDate"}
+
+// redactedFieldsSuffix marks the lowercase shadow copy of a field (userEmailLower), which
+// holds the same personal data and is redacted with it
+const redactedFieldsSuffix = "lower"
+
+// redactedFields holds the lowercased names of the redacted fields
+var redactedFields atomic.Pointer[map[string]bool]
+
+func init() {
+	SetRedactedFields(DefaultRedactedFields)
+}
+
+// SetRedactedFields sets the field names whose values Redact replaces. Names are matched
+// case-insensitively against the last segment of dotted paths and also cover their shadow copies
+func SetRedactedFields(fields []string) {
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			names[strings.ToLower(field)] = true
+		}
+	}
+	redactedFields.Store(&names)
+}
+
+// isRedactedField reports whether the values of a document key are redacted
+func isRedactedField(key string, names map[string]bool) bool {
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		key = key[i+1:]
+	}
+	key = strings.ToLower(key)
+	return names[key] || names[strings.TrimSuffix(key, redactedFieldsSuffix)]
+}
+
+// Redacted wraps a value so it is redacted only when a log event renders it: disabled levels
+// pay for the wrapper alone. Use it with zerolog's Interface:
+//
+//	logger.Debug().Interface("filter", logger.Redacted(filter))
+func Redacted(value interface{}) json.Marshaler {
+	return redactedValue{value: value}
+}
+
+// redactedValue renders its value redacted
+type redactedValue struct {
+	value interface{}
+}
+
+// MarshalJSON implements json.Marshaler. The redacted value is rendered in its canonical form, maps
+// with sorted keys and ordered documents as objects in their order, so equal values always give
+// equal log lines
+func (r redactedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonValue(canonical.Of(Redact(r.value))))
+}
+
+// orderedObject renders an ordered document as a JSON object instead of a list of key/value pairs
+type orderedObject bson.D
+
+// MarshalJSON implements json.Marshaler
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, elem := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		key, err := json.Marshal(elem.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(elem.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(append(buf, key...), ':'), value...)
+	}
+	return append(buf, '}'), nil
+}
+
+// jsonValue prepares a canonical value for encoding/json, rendering documents as objects
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		doc := make(orderedObject, len(v))
+		for i, elem := range v {
+			doc[i] = bson.E{Key: elem.Key, Value: jsonValue(elem.Value)}
+		}
+		return doc
+	case bson.A:
+		elements := make([]interface{}, len(v))
+		for i, elem := range v {
+			elements[i] = jsonValue(elem)
+		}
+		return elements
+	}
+	return value
+}
+
+// Redact returns a copy of a value in which the values of redacted fields are replaced by a
+// hash prefix (sha256:1a2b3c4d), so equal values can be correlated across log lines without
+// exposing them. Documents (bson.M, bson.D, maps, structs) and arrays are walked at any depth;
+// under a redacted field every scalar is replaced, keeping operators such as $in readable
+func Redact(value interface{}) interface{} {
+	return redact(value, *redactedFields.Load(), false)
+}
+
+// redact walks one value; sensitive is set below a redacted field
+func redact(value interface{}, names map[string]bool, sensitive bool) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case bson.M:
+		return redactMap(v, names, sensitive)
+	case map[string]interface{}:
+		return redactMap(v, names, sensitive)
+	case bson.D:
+		return redactDocument(v, names, sensitive)
+	case bson.E:
+		return redactDocument(bson.D{v}, names, sensitive)
+	case bson.Raw:
+		var doc bson.D
+		if err := bson.Unmarshal(v, &doc); err != nil {
+			return "<unreadable>"
+		}
+		return redactDocument(doc, names, sensitive)
+	case bson.A:
+		return redactArray(reflect.ValueOf([]interface{}(v)), names, sensitive)
+	case string, bool, int, int32, int64, float32, float64, time.Time, primitive.DateTime,
+		primitive.ObjectID, primitive.Decimal128, primitive.Regex, primitive.Timestamp, primitive.Binary:
+		if sensitive {
+			return hashValue(v)
+		}
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return redact(rv.Elem().Interface(), names, sensitive)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break // Byte slices are scalars
+		}
+		return redactArray(rv, names, sensitive)
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			doc := make(bson.M, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				doc[iter.Key().String()] = iter.Value().Interface()
+			}
+			return redactMap(doc, names, sensitive)
+		}
+	case reflect.Struct:
+		// Structs are logged as the document they are stored as, so their bson field names are matched
+		raw, err := bson.Marshal(value)
+		if err != nil {
+			return "<unreadable>"
+		}
+		return redact(bson.Raw(raw), names, sensitive)
+	}
+
+	if sensitive {
+		return hashValue(value)
+	}
+	return value
+}
+
+// redactMap redacts the values of a document
+func redactMap(doc map[string]interface{}, names map[string]bool, sensitive bool) bson.M {
+	redacted := make(bson.M, len(doc))
+	for key, value := range doc {
+		redacted[key] = redact(value, names, sensitive || isRedactedField(key, names))
+	}
+	return redacted
+}
+
+// redactDocument redacts the values of an ordered document, keeping the order
+func redactDocument(doc bson.D, names map[string]bool, sensitive bool) bson.D {
+	redacted := make(bson.D, len(doc))
+	for i, elem := range doc {
+		redacted[i] = bson.E{Key: elem.Key, Value: redact(elem.Value, names, sensitive || isRedactedField(elem.Key, names))}
+	}
+	return redacted
+}
+
+// redactArray redacts the elements of an array. Aggregation expressions name fields as values
+// ({"$eq": ["$userEmail", ...]}), so an array referencing a redacted field has its other elements redacted
+func redactArray(array reflect.Value, names map[string]bool, sensitive bool) bson.A {
+	refersRedacted := false
+	for i := 0; i < array.Len(); i++ {
+		if ref, ok := array.Index(i).Interface().(string); ok && strings.HasPrefix(ref, "$") && isRedactedField(ref[1:], names) {
+			refersRedacted = true
+		}
+	}
+
+	redacted := make(bson.A, array.Len())
+	for i := range redacted {
+		element := array.Index(i).Interface()
+		if ref, ok := element.(string); ok && !sensitive && strings.HasPrefix(ref, "$") {
+			redacted[i] = ref // Field references and variables name data without holding it
+			continue
+		}
+		redacted[i] = redact(element, names, sensitive || refersRedacted)
+	}
+	return redacted
+}
+
+// hashValue returns the hash prefix standing in for a redacted value
+func hashValue(value interface{}) string {
+	sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+	return "sha256:" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yourusername/air-go/internal/logger"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+const (
+	// DefaultBatchMaxSize is the maximum number of operations per batch when none is configured
+	DefaultBatchMaxSize = 10
+
+	// DefaultBatchParallelism executes batched operations one after another
+	DefaultBatchParallelism = 1
+
+	// maxBatchBytes caps the request body of a batch
+	maxBatchBytes = 4 << 20
+)
+
+// Error codes of batch-level errors; per-operation errors keep their own codes
+const (
+	errCodeBatchTooLarge = "BATCH_TOO_LARGE"
+	errCodeInvalidBatch  = "INVALID_BATCH"
+)
+
+// batchRecorder buffers the response of one batched operation, or of a GET request awaiting its ETag
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *batchRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *batchRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// isBatchRequest reports whether r is a JSON POST whose body is an array of operations.
+// The body is left intact for the handler that reads it next
+func isBatchRequest(r *http.Request) bool {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return false
+	}
+
+	reader := bufio.NewReader(r.Body)
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{reader, r.Body}
+
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return false
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = reader.ReadByte()
+		default:
+			return b[0] == '['
+		}
+	}
+}
+
+// serveBatch executes an array of GraphQL operations and writes an array of their responses
+// in request order. Every operation is a separate pass through the handler, so extensions
+// and limits apply per operation, and gets the request ID suffixed with its 1-based position
+func (s *Server) serveBatch(handler http.Handler, w http.ResponseWriter, r *http.Request) {
+	var operations []json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBytes)).Decode(&operations); err != nil {
+		writeBatchError(w, http.StatusBadRequest, errCodeInvalidBatch, fmt.Sprintf("invalid batch body: %v", err))
+		return
+	}
+
+	maxSize, parallelism := s.batchLimits()
+	if len(operations) == 0 {
+		writeBatchError(w, http.StatusBadRequest, errCodeInvalidBatch, "batch contains no operations")
+		return
+	}
+	if len(operations) > maxSize {
+		writeBatchError(w, http.StatusBadRequest, errCodeBatchTooLarge,
+			fmt.Sprintf("batch of %d operations exceeds the maximum of %d", len(operations), maxSize))
+		return
+	}
+
+	requestID := middleware.GetRequestID(r)
+	startTime := time.Now()
+
+	results := make([]json.RawMessage, len(operations))
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, operation := range operations {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, operation json.RawMessage) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			results[i] = executeBatchedOperation(handler, r, operation, fmt.Sprintf("%s-%d", requestID, i+1))
+		}(i, operation)
+	}
+	wg.Wait()
+
+	log.Info().
+		Str("request_id", requestID).
+		Int("operations", len(operations)).
+		Int("parallelism", parallelism).
+		Dur("duration_ms", time.Since(startTime)).
+		Msg("GraphQL batch executed")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		log.Error().Err(err).Str("request_id", requestID).Msg("Failed to write GraphQL batch response")
+	}
+}
+
+// executeBatchedOperation runs one operation of a batch and returns its JSON response
+func executeBatchedOperation(handler http.Handler, r *http.Request, operation json.RawMessage, requestID string) json.RawMessage {
+	ctx := context.WithValue(r.Context(), middleware.RequestIDKey, requestID)
+	ctx = logger.WithContextField(ctx, "request_id", requestID)
+	sub := r.Clone(ctx)
+	sub.Body = io.NopCloser(bytes.NewReader(operation))
+	sub.ContentLength = int64(len(operation))
+
+	recorder := &batchRecorder{header: http.Header{}}
+	handler.ServeHTTP(recorder, sub)
+
+	body := bytes.TrimSpace(recorder.body.Bytes())
+	if json.Valid(body) {
+		return body
+	}
+
+	// Plain-text failures (e.g. from the transport) become a GraphQL error response
+	message := string(body)
+	if message == "" {
+		message = http.StatusText(recorder.status)
+	}
+	return graphQLErrorResponse(errCodeInvalidBatch, message)
+}
+
+// batchLimits returns the configured batch size and parallelism, falling back to the defaults
+func (s *Server) batchLimits() (maxSize, parallelism int) {
+	maxSize, parallelism = DefaultBatchMaxSize, DefaultBatchParallelism
+	if s.config.GraphQLBatchMaxSize >= 1 {
+		maxSize = s.config.GraphQLBatchMaxSize
+	}
+	if s.config.GraphQLBatchParallelism >= 1 {
+		parallelism = s.config.GraphQLBatchParallelism
+	}
+	return maxSize, parallelism
+}
+
+// writeBatchError rejects a whole batch with a single GraphQL error response
+func writeBatchError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(graphQLErrorResponse(code, message))
+}
+
+// graphQLErrorResponse builds a response body carrying one error with an extensions code
+func graphQLErrorResponse(code, message string) json.RawMessage {
+	body, _ := json.Marshal(map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"message":    message,
+			"extensions": map[string]interface{}{"code": code},
+		}},
+	})
+	return body
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// DBStatsHeader requests the number and total time of the database operations of a GraphQL
+// response in its extensions
+const DBStatsHeader = "X-Debug-DB-Stats"
+
+// Response extensions holding the database operation stats
+const (
+	dbOpsExtensionKey    = "dbOps"
+	dbTimeMsExtensionKey = "dbTimeMs"
+)
+
+// dbStatsOperations is a gqlgen operation middleware counting the database operations of each
+// operation and attaching the count and their total time in milliseconds to its responses.
+// With always every response gets them, otherwise only those requested with DBStatsHeader.
+// Incremental (@defer) responses carry the totals so far
+func dbStatsOperations(always bool) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		if !always && graphql.GetOperationContext(ctx).Headers.Get(DBStatsHeader) == "" {
+			return next(ctx)
+		}
+
+		ctx, stats := db.WithOperationStats(ctx)
+		responses := next(ctx)
+		return func(ctx context.Context) *graphql.Response {
+			resp := responses(ctx)
+			if resp == nil {
+				return nil
+			}
+			if resp.Extensions == nil {
+				resp.Extensions = map[string]interface{}{}
+			}
+			resp.Extensions[dbOpsExtensionKey] = stats.Count()
+			resp.Extensions[dbTimeMsExtensionKey] = float64(stats.Duration().Microseconds()) / 1000
+			return resp
+		}
+	}
+}
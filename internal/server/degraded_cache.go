@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// DegradedCache keeps the last successful result of each GraphQL query so that reads keep
+// working while MongoDB reconnects. While the server is READY, query results are stored;
+// while it is DEGRADED, a query is answered from its stored result, or fails with
+// SERVICE_UNAVAILABLE when there is none. Results are keyed by principal, so a caller only
+// ever receives results computed under its own authorization filters. Mutations always run
+// against MongoDB and are neither stored nor answered from the cache
+type DegradedCache struct {
+	readiness *Readiness
+	entries   *lru.LRU[degradedCacheEntry]
+}
+
+// degradedCacheEntry is a stored query result
+type degradedCacheEntry struct {
+	data     json.RawMessage
+	storedAt time.Time
+}
+
+// NewDegradedCache creates a cache holding up to size query results
+func NewDegradedCache(readiness *Readiness, size int) *DegradedCache {
+	return &DegradedCache{
+		readiness: readiness,
+		entries:   lru.New[degradedCacheEntry](size),
+	}
+}
+
+// AroundOperations is a gqlgen operation middleware applying the cache
+func (c *DegradedCache) AroundOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation != ast.Query {
+		return next(ctx)
+	}
+	key, ok := degradedCacheKey(ctx, oc)
+	if !ok {
+		return next(ctx)
+	}
+
+	switch c.readiness.State() {
+	case ReadinessDegraded:
+		entry, found := c.entries.Get(ctx, key)
+		if !found {
+			return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{{
+				Message:    "Database is reconnecting and no cached result is available",
+				Extensions: map[string]interface{}{"code": resolvers.ErrCodeServiceUnavailable},
+			}}})
+		}
+		return graphql.OneShot(&graphql.Response{
+			Data: entry.data,
+			Extensions: map[string]interface{}{
+				"degradedCache": map[string]interface{}{"storedAt": entry.storedAt.UTC().Format(time.RFC3339)},
+			},
+		})
+
+	case ReadinessReady:
+		responses := next(ctx)
+		first := true
+		return func(ctx context.Context) *graphql.Response {
+			resp := responses(ctx)
+			// Incremental (@defer) results are not stored
+			if first && resp != nil && len(resp.Errors) == 0 && resp.Data != nil && resp.HasNext == nil {
+				c.entries.Add(ctx, key, degradedCacheEntry{data: resp.Data, storedAt: c.readiness.clock.Now()})
+			}
+			first = false
+			return resp
+		}
+
+	default:
+		return next(ctx)
+	}
+}
+
+// degradedCacheKey identifies a query by its principal, document, operation and variables
+func degradedCacheKey(ctx context.Context, oc *graphql.OperationContext) (string, bool) {
+	claims, ok := middleware.GetClaims(ctx)
+	if !ok {
+		return "", false
+	}
+
+	raw, err := json.Marshal(struct {
+		Principal     *resolvers.UserClaims  `json:"principal"`
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}{userClaimsFromJWT(claims), oc.RawQuery, oc.OperationName, oc.Variables})
+	if err != nil {
+		return "", false
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), true
+}
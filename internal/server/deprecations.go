@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// DefaultDeprecationClientHeader names the client in the deprecated field usage when no header is configured
+const DefaultDeprecationClientHeader = "X-Client-Name"
+
+// deprecationClientHeader returns the request header naming the client in the deprecated field usage
+func (s *Server) deprecationClientHeader() string {
+	if s.config.DeprecationClientHeader == "" {
+		return DefaultDeprecationClientHeader
+	}
+	return s.config.DeprecationClientHeader
+}
+
+// DeprecatedFieldOperations is a gqlgen operation middleware counting the deprecated fields and
+// arguments an operation selects by the client named in clientHeader, before it runs. Responses
+// of such operations announce the deprecation in their headers, and while sunsets are enforced
+// an operation using one past its sunset date answers FIELD_SUNSET without running
+func DeprecatedFieldOperations(clientHeader string) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		oc := graphql.GetOperationContext(ctx)
+		used := resolvers.OperationDeprecations(ctx, oc)
+		if len(used) == 0 {
+			return next(ctx)
+		}
+
+		client := oc.Headers.Get(clientHeader)
+		for _, deprecation := range used {
+			resolvers.RecordDeprecatedUse(deprecation.Coordinate, client)
+		}
+		if notice, ok := ctx.Value(deprecationNoticeKey{}).(*deprecationNotice); ok {
+			notice.add(used)
+		}
+
+		if deprecation, ok := resolvers.SunsetRefusal(ctx, used); ok {
+			return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{resolvers.SunsetError(deprecation)}})
+		}
+		return next(ctx)
+	}
+}
+
+// deprecationNoticeKey is the context key of the deprecationNotice of a request
+type deprecationNoticeKey struct{}
+
+// deprecationNotice collects whether a request's operations used deprecated fields, and the
+// earliest sunset date among them. Batched operations may add to it concurrently
+type deprecationNotice struct {
+	mu         sync.Mutex
+	deprecated bool
+	sunset     time.Time
+}
+
+func (n *deprecationNotice) add(used []resolvers.Deprecation) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.deprecated = true
+	for _, deprecation := range used {
+		if !deprecation.Sunset.IsZero() && (n.sunset.IsZero() || deprecation.Sunset.Before(n.sunset)) {
+			n.sunset = deprecation.Sunset
+		}
+	}
+}
+
+// setHeaders sets the Deprecation and, with a sunset date, the Sunset (RFC 8594) response headers
+func (n *deprecationNotice) setHeaders(header http.Header) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.deprecated {
+		return
+	}
+	header.Set("Deprecation", "true")
+	if !n.sunset.IsZero() {
+		header.Set("Sunset", n.sunset.UTC().Format(http.TimeFormat))
+	}
+}
+
+// withDeprecationNotice returns a response writer adding the deprecation headers of the
+// request's operations before the response is written, and the request carrying their notice
+func withDeprecationNotice(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request) {
+	notice := &deprecationNotice{}
+	ctx := context.WithValue(r.Context(), deprecationNoticeKey{}, notice)
+	return &deprecationHeaderWriter{ResponseWriter: w, notice: notice}, r.WithContext(ctx)
+}
+
+// deprecationHeaderWriter sets the headers of its deprecationNotice when the response starts
+type deprecationHeaderWriter struct {
+	http.ResponseWriter
+	notice  *deprecationNotice
+	started bool
+}
+
+func (w *deprecationHeaderWriter) start() {
+	if !w.started {
+		w.started = true
+		w.notice.setHeaders(w.ResponseWriter.Header())
+	}
+}
+
+func (w *deprecationHeaderWriter) WriteHeader(status int) {
+	w.start()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *deprecationHeaderWriter) Write(b []byte) (int, error) {
+	w.start()
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer
+func (w *deprecationHeaderWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
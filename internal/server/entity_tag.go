@@ -0,0 +1,69 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// EntityTagHandler serves GraphQL GET requests with a weak ETag when the response only holds
+// entities of Get queries with a version, and answers 304 Not Modified without a body when the
+// request's If-None-Match carries that tag. The tag is computed from the versions the resolvers
+// read, the request's query string and the caller, so the response is never hashed; any change
+// to one of the entities changes its version and with it the tag. Other responses pass unchanged
+func EntityTagHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Debug responses differ from regular ones for the same versions
+		if r.Method != http.MethodGet || r.Header.Get(ExplainHeader) != "" || r.Header.Get(AppliedFilterHeader) != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, versions := resolvers.WithEntityVersions(r.Context())
+		recorder := &batchRecorder{header: http.Header{}}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		header := w.Header()
+		for key, values := range recorder.header {
+			header[key] = values
+		}
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		if status == http.StatusOK {
+			principal := ""
+			if claims, ok := middleware.GetClaims(r.Context()); ok {
+				principal, _ = claims["sub"].(string)
+			}
+			if etag, ok := versions.ETag(principal + "\n" + r.URL.RawQuery); ok {
+				header.Set("ETag", etag)
+				header.Set("Cache-Control", "private, no-cache")
+				header.Add("Vary", "Authorization")
+				if entityTagMatches(r.Header.Get("If-None-Match"), etag) {
+					header.Del("Content-Type")
+					header.Del("Content-Length")
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
+		}
+
+		w.WriteHeader(status)
+		_, _ = w.Write(recorder.body.Bytes())
+	})
+}
+
+// entityTagMatches reports whether an If-None-Match header lists etag, comparing weakly
+func entityTagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
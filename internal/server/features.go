@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/introspection"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// disabledFeatureFields is a gqlgen field middleware answering disabled Query and Mutation
+// fields with FEATURE_DISABLED before their resolvers run. With hide, introspection also
+// omits them from the Query and Mutation types
+func disabledFeatureFields(hide bool) graphql.FieldMiddleware {
+	return func(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+		fc := graphql.GetFieldContext(ctx)
+		if fc == nil {
+			return next(ctx)
+		}
+		if isRootType(fc.Object) && resolvers.FieldDisabled(fc.Field.Name) {
+			return nil, resolvers.FeatureDisabledError(fc.Field.Name)
+		}
+
+		result, err := next(ctx)
+		if hide && err == nil && fc.Object == "__Type" && fc.Field.Name == "fields" {
+			result = withoutDisabledFields(fc, result)
+		}
+		return result, err
+	}
+}
+
+// isRootType reports whether an object type is the Query or Mutation type
+func isRootType(object string) bool {
+	return object == "Query" || object == "Mutation"
+}
+
+// withoutDisabledFields drops the disabled fields from the introspected fields of a root type
+func withoutDisabledFields(fc *graphql.FieldContext, result interface{}) interface{} {
+	fields, ok := result.([]introspection.Field)
+	if !ok || fc.Parent == nil {
+		return result
+	}
+	typ, ok := fc.Parent.Result.(*introspection.Type)
+	if !ok || typ.Name() == nil || !isRootType(*typ.Name()) {
+		return result
+	}
+
+	kept := make([]introspection.Field, 0, len(fields))
+	for _, field := range fields {
+		if !resolvers.FieldDisabled(field.Name) {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
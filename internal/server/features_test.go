@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/introspection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// withField returns a context resolving the named field of an object
+func withField(ctx context.Context, object, field string, result interface{}) context.Context {
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: object,
+		Field:  graphql.CollectedField{Field: &ast.Field{Name: field}},
+		Result: result,
+	})
+}
+
+// TestDisabledFeatureFields_DisabledEntities verifies every root field of disabled inventory and
+// referencePortfolio entities is rejected with FEATURE_DISABLED and hidden from introspection,
+// including the fields not named after their entity
+func TestDisabledFeatureFields_DisabledEntities(t *testing.T) {
+	schema := generated.NewExecutableSchema(generated.Config{}).Schema()
+	require.NoError(t, resolvers.SetDisabledFeatures([]string{"inventory", "referencePortfolio"}, nil, schema))
+	t.Cleanup(func() { _ = resolvers.SetDisabledFeatures(nil, nil, schema) })
+	middleware := disabledFeatureFields(true)
+
+	rejected := map[string]bool{}
+	for _, root := range []*ast.Definition{schema.Query, schema.Mutation} {
+		for _, field := range root.Fields {
+			called := false
+			_, err := middleware(withField(context.Background(), root.Name, field.Name, nil), func(context.Context) (interface{}, error) {
+				called = true
+				return nil, nil
+			})
+			if !called {
+				var gqlErr *gqlerror.Error
+				require.True(t, errors.As(err, &gqlErr), field.Name)
+				assert.Equal(t, resolvers.ErrCodeFeatureDisabled, gqlErr.Extensions["code"], field.Name)
+				rejected[field.Name] = true
+			}
+		}
+
+		typ := introspection.WrapTypeFromDef(schema, root)
+		ctx := withField(withField(context.Background(), "Query", "__type", typ), "__Type", "fields", nil)
+		result, err := middleware(ctx, func(context.Context) (interface{}, error) {
+			return typ.Fields(true), nil
+		})
+		require.NoError(t, err)
+		fields := result.([]introspection.Field)
+		assert.NotEmpty(t, fields)
+		for _, field := range fields {
+			assert.False(t, rejected[field.Name], "%s is listed by introspection", field.Name)
+		}
+	}
+
+	for _, field := range []string{
+		"inventoryGet", "search", "byKeysGet", "byKeysGetDetailed", "byKeysGetConnection", "inventoryCreate",
+		"referencePortfolioSearch", "refPortConstantsAndDefaultsGet", "create", "update", "referencePortfolioDelete",
+	} {
+		assert.True(t, rejected[field], field)
+	}
+	assert.False(t, rejected["customerSearch"])
+	assert.False(t, rejected["executionPlanCreate"])
+}
@@ -0,0 +1,243 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// IdempotencyKeyHeader names a mutation request so that retries of it are answered from the
+// response of the first execution instead of running again
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyCollection holds the idempotency keys with the responses of their mutations
+const IdempotencyCollection = "_idempotency"
+
+// maxIdempotencyKeyLength bounds the keys clients may send
+const maxIdempotencyKeyLength = 255
+
+// idempotencyPendingTimeout is how long a key whose mutation has not completed blocks its
+// replays; after it the execution counts as abandoned and the next request runs the mutation
+const idempotencyPendingTimeout = time.Minute
+
+// IdempotencyStore makes mutations sent with an Idempotency-Key header run once per key. The
+// first request reserves the key together with a hash of its operation and stores the
+// response once the mutation succeeded; replays with the same operation receive the stored
+// response. Reusing a key for a different operation fails with CONFLICT, as does a replay while
+// the first execution is still running. Failed mutations release their key, so a retry runs
+// them again. Keys are scoped by the token's subject and expire after the TTL
+type IdempotencyStore struct {
+	collection db.Collection
+	ttl        time.Duration
+	clock      db.Clock
+}
+
+// idempotencyRecord is a stored idempotency key
+type idempotencyRecord struct {
+	ID            string     `bson:"_id"`
+	Principal     string     `bson:"principal"`
+	Key           string     `bson:"key"`
+	OperationHash string     `bson:"operationHash"`
+	Response      *string    `bson:"response,omitempty"` // Response data as JSON; nil while the mutation runs
+	CreatedAt     time.Time  `bson:"createdAt"`
+	CompletedAt   *time.Time `bson:"completedAt,omitempty"`
+}
+
+// NewIdempotencyStore creates a store keeping idempotency keys in collection for ttl
+func NewIdempotencyStore(collection db.Collection, ttl time.Duration, clock db.Clock) *IdempotencyStore {
+	return &IdempotencyStore{collection: collection, ttl: ttl, clock: clock}
+}
+
+// IdempotencyIndexes returns the TTL index removing idempotency keys after ttl
+func IdempotencyIndexes(ttl time.Duration) []db.IndexSpec {
+	return []db.IndexSpec{
+		{
+			Collection:  IdempotencyCollection,
+			Name:        "createdAt_ttl",
+			Keys:        bson.D{{Key: "createdAt", Value: 1}},
+			ExpireAfter: ttl,
+		},
+	}
+}
+
+// AroundOperations is a gqlgen operation middleware applying idempotency keys to mutations.
+// Queries and mutations without the header pass through
+func (s *IdempotencyStore) AroundOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation != ast.Mutation {
+		return next(ctx)
+	}
+	key := oc.Headers.Get(IdempotencyKeyHeader)
+	if key == "" {
+		return next(ctx)
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return idempotencyError("Idempotency-Key must not be longer than 255 characters", resolvers.ErrCodeInvalidInput)
+	}
+	claims, ok := middleware.GetClaims(ctx)
+	if !ok {
+		return next(ctx)
+	}
+
+	principal, operationHash, err := idempotencyHashes(claims, oc)
+	if err != nil {
+		return next(ctx)
+	}
+	id := idempotencyID(principal, key)
+
+	record, err := s.reserve(ctx, idempotencyRecord{
+		ID:            id,
+		Principal:     principal,
+		Key:           key,
+		OperationHash: operationHash,
+		CreatedAt:     s.clock.Now(),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("event_type", "idempotency_key_failed").Msg("Failed to reserve idempotency key")
+		return idempotencyError("Idempotency-Key could not be checked", resolvers.ErrCodeDatabaseError)
+	}
+	if record != nil {
+		switch {
+		case record.OperationHash != operationHash:
+			return idempotencyError("Idempotency-Key was already used for a different operation", resolvers.ErrCodeConflict)
+		case record.Response == nil:
+			return idempotencyError("A request with this Idempotency-Key is still in progress", resolvers.ErrCodeConflict)
+		default:
+			return graphql.OneShot(&graphql.Response{Data: json.RawMessage(*record.Response)})
+		}
+	}
+
+	responses := next(ctx)
+	first := true
+	return func(ctx context.Context) *graphql.Response {
+		resp := responses(ctx)
+		if first {
+			first = false
+			s.complete(ctx, id, resp)
+		}
+		return resp
+	}
+}
+
+// reserve inserts the record of a new key. When the key exists, the stored record is returned
+// instead, unless it expired or its mutation was abandoned: then it is replaced
+func (s *IdempotencyStore) reserve(ctx context.Context, record idempotencyRecord) (*idempotencyRecord, error) {
+	for {
+		_, err := s.collection.InsertOne(ctx, record)
+		if err == nil {
+			return nil, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+
+		var existing idempotencyRecord
+		if err := s.collection.FindOne(ctx, bson.M{"_id": record.ID}).Decode(&existing); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue // Removed by the TTL monitor meanwhile
+			}
+			return nil, err
+		}
+		if !s.stale(existing) {
+			return &existing, nil
+		}
+		// Only the stale record is removed, so concurrent requests replace it at most once
+		if _, err := s.collection.DeleteOne(ctx, bson.M{"_id": existing.ID, "createdAt": existing.CreatedAt}); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// stale reports whether a record no longer answers replays: it expired but the TTL monitor,
+// which runs about once a minute, has not removed it yet, or its mutation never completed
+func (s *IdempotencyStore) stale(record idempotencyRecord) bool {
+	age := s.clock.Now().Sub(record.CreatedAt)
+	return age >= s.ttl || (record.Response == nil && age >= idempotencyPendingTimeout)
+}
+
+// complete stores the response of a successful mutation, or releases the key of a failed one.
+// Incremental (@defer) responses are not stored. Failed writes are queued for replay (see
+// resolvers.SetWriteRetryQueue); until replayed, replays of the key answer CONFLICT
+func (s *IdempotencyStore) complete(ctx context.Context, id string, resp *graphql.Response) {
+	ctx = context.WithoutCancel(ctx)
+	if resp == nil || len(resp.Errors) > 0 || resp.Data == nil || resp.HasNext != nil {
+		release := func(ctx context.Context) error {
+			_, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+			return err
+		}
+		if err := release(ctx); err != nil {
+			queued := resolvers.QueueWriteRetry("idempotency_release", release)
+			log.Error().Err(err).Str("event_type", "idempotency_key_failed").Bool("queued", queued).Msg("Failed to release idempotency key")
+		}
+		return
+	}
+
+	update := bson.M{"$set": bson.M{
+		"response":    string(resp.Data),
+		"completedAt": s.clock.Now(),
+	}}
+	store := func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+		return err
+	}
+	if err := store(ctx); err != nil {
+		queued := resolvers.QueueWriteRetry("idempotency_response", store)
+		log.Error().Err(err).Str("event_type", "idempotency_key_failed").Bool("queued", queued).Msg("Failed to store idempotent response")
+	}
+}
+
+// idempotencyHashes returns the hash of the principal and the hash identifying the operation
+// by its document, name and variables. The principal is the token's subject only: a refreshed
+// token with other roles or teams must still find the keys of its earlier requests
+func idempotencyHashes(claims jwt.MapClaims, oc *graphql.OperationContext) (string, string, error) {
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return "", "", err
+	}
+	if subject == "" {
+		return "", "", errors.New("the token has no subject to scope idempotency keys by")
+	}
+	operation, err := json.Marshal(struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}{oc.RawQuery, oc.OperationName, oc.Variables})
+	if err != nil {
+		return "", "", err
+	}
+	return sha256Hex([]byte(subject)), sha256Hex(operation), nil
+}
+
+// idempotencyID identifies a key of a principal; keys of different principals never collide
+func idempotencyID(principal, key string) string {
+	return sha256Hex([]byte(principal + "\x00" + key))
+}
+
+// sha256Hex returns the hex encoded SHA-256 hash of b
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// idempotencyError answers an operation with a single error
+func idempotencyError(message, code string) graphql.ResponseHandler {
+	return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{{
+		Message:    message,
+		Extensions: map[string]interface{}{"code": code},
+	}}})
+}
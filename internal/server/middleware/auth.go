@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -24,69 +25,75 @@ const (
 func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Extract the token from the Authorization header
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				log.Warn().Msg("Missing Authorization header")
-				http.Error(w, "Unauthorized: Missing Authorization header", http.StatusUnauthorized)
-				return
-			}
-
-			// Check for "Bearer " prefix
-			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-			if tokenString == authHeader {
-				log.Warn().Msg("Invalid Authorization header format")
-				http.Error(w, "Unauthorized: Invalid Authorization header format", http.StatusUnauthorized)
-				return
-			}
-
-			// Parse and validate the token
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				// Verify signing method
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				return []byte(jwtSecret), nil
-			})
-
+			claims, err := ValidateToken(jwtSecret, r.Header.Get("Authorization"))
 			if err != nil {
-				log.Warn().Err(err).Msg("Token validation failed")
 				http.Error(w, fmt.Sprintf("Unauthorized: %v", err), http.StatusUnauthorized)
 				return
 			}
 
-			if !token.Valid {
-				log.Warn().Msg("Invalid token")
-				http.Error(w, "Unauthorized: Invalid token", http.StatusUnauthorized)
-				return
-			}
+			// Call the next handler with the updated context
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
 
-			// Extract claims
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				log.Warn().Msg("Failed to parse token claims")
-				http.Error(w, "Unauthorized: Invalid token claims", http.StatusUnauthorized)
-				return
-			}
+// ValidateToken validates the bearer token of an Authorization value (header or websocket init
+// payload) and returns its claims, which carry a user ID
+func ValidateToken(jwtSecret, authorization string) (jwt.MapClaims, error) {
+	if authorization == "" {
+		log.Warn().Msg("Missing Authorization header")
+		return nil, errors.New("Missing Authorization header")
+	}
 
-			// Extract user ID from claims (sub claim is standard)
-			userID, ok := claims["sub"].(string)
-			if !ok || userID == "" {
-				log.Warn().Msg("Missing or invalid user ID in token claims")
-				http.Error(w, "Unauthorized: Missing user ID in token", http.StatusUnauthorized)
-				return
-			}
+	// Check for "Bearer " prefix
+	tokenString := strings.TrimPrefix(authorization, "Bearer ")
+	if tokenString == authorization {
+		log.Warn().Msg("Invalid Authorization header format")
+		return nil, errors.New("Invalid Authorization header format")
+	}
+
+	// Parse and validate the token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Verify signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(jwtSecret), nil
+	})
+
+	if err != nil {
+		log.Warn().Err(err).Msg("Token validation failed")
+		return nil, err
+	}
 
-			// Add user ID and full claims to context
-			ctx := context.WithValue(r.Context(), UserIDKey, userID)
-			ctx = context.WithValue(ctx, ClaimsKey, claims)
+	if !token.Valid {
+		log.Warn().Msg("Invalid token")
+		return nil, errors.New("Invalid token")
+	}
 
-			log.Debug().Str("user_id", userID).Msg("User authenticated successfully")
+	// Extract claims
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		log.Warn().Msg("Failed to parse token claims")
+		return nil, errors.New("Invalid token claims")
+	}
 
-			// Call the next handler with the updated context
-			next.ServeHTTP(w, r.WithContext(ctx))
-		})
+	// Extract user ID from claims (sub claim is standard)
+	userID, ok := claims["sub"].(string)
+	if !ok || userID == "" {
+		log.Warn().Msg("Missing or invalid user ID in token claims")
+		return nil, errors.New("Missing user ID in token")
 	}
+
+	log.Debug().Str("user_id", userID).Msg("User authenticated successfully")
+	return claims, nil
+}
+
+// WithClaims adds the user ID and the full claims of a validated token to a context
+func WithClaims(ctx context.Context, claims jwt.MapClaims) context.Context {
+	userID, _ := claims["sub"].(string)
+	ctx = context.WithValue(ctx, UserIDKey, userID)
+	return context.WithValue(ctx, ClaimsKey, claims)
 }
 
 // GetUserID extracts the user ID from the request context
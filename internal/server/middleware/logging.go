@@ -1,12 +1,16 @@
 package middleware
 
 import (
+	"bufio"
 	"context"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+
+	"github.com/yourusername/air-go/internal/logger"
 )
 
 const (
@@ -34,6 +38,15 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Hijack hands the connection over to a websocket upgrade, which is logged as switching protocols
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buf, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err == nil {
+		rw.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, buf, err
+}
+
 // LoggingMiddleware logs HTTP requests and responses with request ID, duration, and other metadata
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -46,8 +59,9 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		// Add request ID to response headers
 		w.Header().Set(RequestIDHeader, requestID)
 
-		// Add request ID to context
+		// Add request ID to context and to the loggers taken from it
 		ctx := context.WithValue(r.Context(), RequestIDKey, requestID)
+		ctx = logger.WithContextField(ctx, "request_id", requestID)
 
 		// Wrap the response writer to capture status code and size
 		wrappedWriter := &responseWriter{
@@ -95,7 +109,12 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 
 // GetRequestID extracts the request ID from the request context
 func GetRequestID(r *http.Request) string {
-	requestID, ok := r.Context().Value(RequestIDKey).(string)
+	return RequestIDFromContext(r.Context())
+}
+
+// RequestIDFromContext extracts the request ID from a context derived from the request
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, ok := ctx.Value(RequestIDKey).(string)
 	if !ok {
 		return ""
 	}
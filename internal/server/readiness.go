@@ -0,0 +1,373 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/db/purge"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/health"
+)
+
+// ReadinessState is the serving state reported by /ready
+type ReadinessState string
+
+const (
+	// ReadinessNotReady means a startup condition is not met yet
+	ReadinessNotReady ReadinessState = "NOT_READY"
+
+	// ReadinessReady means the server can serve all requests
+	ReadinessReady ReadinessState = "READY"
+
+	// ReadinessDegraded means the server was ready but MongoDB is reconnecting
+	ReadinessDegraded ReadinessState = "DEGRADED"
+
+	// ReadinessShuttingDown means the server is draining outstanding requests
+	ReadinessShuttingDown ReadinessState = "SHUTTING_DOWN"
+)
+
+// Value returns the state as a gauge value for metric scrapers
+func (s ReadinessState) Value() int {
+	switch s {
+	case ReadinessReady:
+		return 1
+	case ReadinessDegraded:
+		return 2
+	case ReadinessShuttingDown:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ReadinessReport is the /ready response body
+type ReadinessReport struct {
+	State      ReadinessState `json:"state"`
+	StateValue int            `json:"state_value"`       // Gauge: 0 NOT_READY, 1 READY, 2 DEGRADED, 3 SHUTTING_DOWN
+	Reasons    []string       `json:"reasons,omitempty"` // Why the state is not READY
+	Since      string         `json:"since"`             // When the current state was entered
+	Timestamp  string         `json:"timestamp"`
+}
+
+// Readiness tracks the serving state of the server. The state is NOT_READY until the schema
+// is loaded, the indexes are ensured (when required) and MongoDB has reported "connected"
+// once, READY afterwards, DEGRADED while
+// MongoDB is unreachable after having been connected, and SHUTTING_DOWN once draining starts.
+// The state is evaluated by Run in the background and on every /ready and /metrics request.
+// Every transition is logged
+type Readiness struct {
+	mu       sync.Mutex
+	dbClient health.DBHealthChecker
+	clock    db.Clock
+	logger   zerolog.Logger
+
+	schemaLoaded    bool
+	indexesRequired bool
+	indexesEnsured  bool
+	indexErr        error
+	wasReady        bool
+	shuttingDown    bool
+
+	state   ReadinessState
+	reasons []string
+	since   time.Time
+}
+
+// NewReadiness creates a readiness tracker in the NOT_READY state.
+// A nil dbClient leaves the database out of the evaluation
+func NewReadiness(dbClient health.DBHealthChecker, clock db.Clock, logger zerolog.Logger) *Readiness {
+	return &Readiness{
+		dbClient: dbClient,
+		clock:    clock,
+		logger:   logger,
+		state:    ReadinessNotReady,
+		reasons:  []string{"schema not loaded"},
+		since:    clock.Now(),
+	}
+}
+
+// MarkSchemaLoaded records that the GraphQL schema is loaded
+func (r *Readiness) MarkSchemaLoaded() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemaLoaded = true
+}
+
+// RequireIndexes keeps the server NOT_READY until MarkIndexesEnsured reports success
+func (r *Readiness) RequireIndexes() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.indexesRequired = true
+}
+
+// MarkIndexesEnsured records the outcome of an index creation attempt; a failed attempt
+// is reported as the reason the server is not ready
+func (r *Readiness) MarkIndexesEnsured(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.indexesEnsured = err == nil
+	r.indexErr = err
+}
+
+// State returns the state as of the last evaluation without checking the database
+func (r *Readiness) State() ReadinessState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// BeginShutdown moves the server to SHUTTING_DOWN; the state is final
+func (r *Readiness) BeginShutdown() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shuttingDown = true
+	r.transition(ReadinessShuttingDown, []string{"server is shutting down"})
+}
+
+// Evaluate checks the startup conditions and database health, applies the resulting
+// transition and returns the current state
+func (r *Readiness) Evaluate(ctx context.Context) ReadinessReport {
+	var dbStatus *db.HealthStatus
+	var dbErr error
+	if r.dbClient != nil {
+		dbStatus, dbErr = r.dbClient.HealthStatus(ctx)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.shuttingDown {
+		state, reasons := r.evaluate(dbStatus, dbErr)
+		r.transition(state, reasons)
+	}
+
+	return ReadinessReport{
+		State:      r.state,
+		StateValue: r.state.Value(),
+		Reasons:    append([]string(nil), r.reasons...),
+		Since:      r.since.UTC().Format(time.RFC3339),
+		Timestamp:  r.clock.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// Run evaluates the state every interval until ctx is done, so transitions happen, and are
+// logged, whether or not anything requests /ready or /metrics. Each check may take up to interval
+func (r *Readiness) Run(ctx context.Context, interval time.Duration) {
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		r.Evaluate(checkCtx)
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.clock.After(interval):
+		}
+	}
+}
+
+// evaluate derives the state from the recorded conditions and a database health result
+func (r *Readiness) evaluate(dbStatus *db.HealthStatus, dbErr error) (ReadinessState, []string) {
+	var reasons []string
+	if !r.schemaLoaded {
+		reasons = append(reasons, "schema not loaded")
+	}
+	if r.indexesRequired && !r.indexesEnsured {
+		reason := "indexes not ensured"
+		if r.indexErr != nil {
+			reason += ": " + r.indexErr.Error()
+		}
+		reasons = append(reasons, reason)
+	}
+
+	dbReason := ""
+	if r.dbClient != nil {
+		switch {
+		case dbErr != nil:
+			dbReason = "database health check failed: " + dbErr.Error()
+		case dbStatus == nil || dbStatus.Status != "connected":
+			dbReason = "database not connected"
+			if dbStatus != nil && dbStatus.Message != "" {
+				dbReason += ": " + dbStatus.Message
+			}
+		case dbStatus.Reader != nil && dbStatus.Reader.Status != "connected":
+			dbReason = "read connection not connected"
+		}
+	}
+
+	if len(reasons) > 0 {
+		if dbReason != "" {
+			reasons = append(reasons, dbReason)
+		}
+		return ReadinessNotReady, reasons
+	}
+	if dbReason != "" {
+		// The driver reconnects on its own; only a server that has served before is degraded
+		if r.wasReady {
+			return ReadinessDegraded, []string{dbReason}
+		}
+		return ReadinessNotReady, []string{dbReason}
+	}
+
+	r.wasReady = true
+	return ReadinessReady, nil
+}
+
+// transition records a new state and logs it; callers hold r.mu
+func (r *Readiness) transition(state ReadinessState, reasons []string) {
+	r.reasons = reasons
+	if state == r.state {
+		return
+	}
+
+	now := r.clock.Now()
+	previous, previousSince := r.state, r.since
+	r.state, r.since = state, now
+
+	event := r.logger.Warn()
+	if state == ReadinessReady || state == ReadinessShuttingDown {
+		event = r.logger.Info()
+	}
+	event.
+		Str("from", string(previous)).
+		Str("to", string(state)).
+		Strs("reasons", reasons).
+		Dur("previous_state_duration", now.Sub(previousSince)).
+		Msg("Readiness state changed")
+}
+
+// connectionCounter is implemented by database clients reporting the connections checked out
+// of their pool, e.g. *db.Client
+type connectionCounter interface {
+	ConnectionsInUse() int64
+}
+
+// MetricsHandler returns the /metrics endpoint, exposing the serving state, the search
+// concurrency limiter and the resources watched for leaks as gauges, and the capped page sizes, the rejected searches, the Get cache
+// lookups, the audited lookups of deleted entities and the purged entities as counters in the
+// Prometheus text format
+func (r *Readiness) MetricsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		report := r.Evaluate(req.Context())
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = fmt.Fprintf(w, "# HELP air_readiness_state Serving state: 0 NOT_READY, 1 READY, 2 DEGRADED, 3 SHUTTING_DOWN\n")
+		_, _ = fmt.Fprintf(w, "# TYPE air_readiness_state gauge\n")
+		_, _ = fmt.Fprintf(w, "air_readiness_state %d\n", report.StateValue)
+		_, _ = fmt.Fprintf(w, "# HELP air_page_size_capped_total Search first/last arguments capped to the maximum page size\n")
+		_, _ = fmt.Fprintf(w, "# TYPE air_page_size_capped_total counter\n")
+		_, _ = fmt.Fprintf(w, "air_page_size_capped_total %d\n", resolvers.CappedPageSizeCount())
+		_, _ = fmt.Fprintf(w, "# HELP air_goroutines Goroutines of the process\n")
+		_, _ = fmt.Fprintf(w, "# TYPE air_goroutines gauge\n")
+		_, _ = fmt.Fprintf(w, "air_goroutines %d\n", runtime.NumGoroutine())
+		if counter, ok := r.dbClient.(connectionCounter); ok {
+			_, _ = fmt.Fprintf(w, "# HELP air_mongodb_connections_in_use Connections checked out of the MongoDB pool\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_mongodb_connections_in_use gauge\n")
+			_, _ = fmt.Fprintf(w, "air_mongodb_connections_in_use %d\n", counter.ConnectionsInUse())
+		}
+		_, _ = fmt.Fprintf(w, "# HELP air_mongodb_open_cursors Search and byKeys cursors not closed yet\n")
+		_, _ = fmt.Fprintf(w, "# TYPE air_mongodb_open_cursors gauge\n")
+		_, _ = fmt.Fprintf(w, "air_mongodb_open_cursors %d\n", db.OpenCursors())
+		search := resolvers.SearchConcurrencyStats()
+		_, _ = fmt.Fprintf(w, "# HELP air_search_rows_in_use Rows held by running searches\n")
+		_, _ = fmt.Fprintf(w, "# TYPE air_search_rows_in_use gauge\n")
+		_, _ = fmt.Fprintf(w, "air_search_rows_in_use %d\n", search.InUse)
+		_, _ = fmt.Fprintf(w, "# HELP air_search_rows_max Rows running searches may hold (SEARCH_MAX_CONCURRENT_ROWS)\n")
+		_, _ = fmt.Fprintf(w, "# TYPE air_search_rows_max gauge\n")
+		_, _ = fmt.Fprintf(w, "air_search_rows_max %d\n", search.Max)
+		_, _ = fmt.Fprintf(w, "# HELP air_search_waiting Searches queued for capacity\n")
+		_, _ = fmt.Fprintf(w, "# TYPE air_search_waiting gauge\n")
+		_, _ = fmt.Fprintf(w, "air_search_waiting %d\n", search.Waiting)
+		_, _ = fmt.Fprintf(w, "# HELP air_search_rejected_total Searches rejected after the queue timeout (SEARCH_QUEUE_TIMEOUT)\n")
+		_, _ = fmt.Fprintf(w, "# TYPE air_search_rejected_total counter\n")
+		_, _ = fmt.Fprintf(w, "air_search_rejected_total %d\n", search.Rejected)
+		if stats := resolvers.CurrentGetCacheStats(); len(stats) > 0 {
+			_, _ = fmt.Fprintf(w, "# HELP air_get_cache_lookups_total Get-by-identifier lookups by entity and cache result: hit, negative_hit (cached not-found) or miss\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_get_cache_lookups_total counter\n")
+			for _, entity := range stats {
+				_, _ = fmt.Fprintf(w, "air_get_cache_lookups_total{entity=%q,result=\"hit\"} %d\n", entity.Entity, entity.Hits)
+				_, _ = fmt.Fprintf(w, "air_get_cache_lookups_total{entity=%q,result=\"negative_hit\"} %d\n", entity.Entity, entity.NegativeHits)
+				_, _ = fmt.Fprintf(w, "air_get_cache_lookups_total{entity=%q,result=\"miss\"} %d\n", entity.Entity, entity.Misses)
+			}
+		}
+		if stats := resolvers.CurrentDeletedAccessStats(); len(stats) > 0 {
+			_, _ = fmt.Fprintf(w, "# HELP air_deleted_entity_lookups_total Get and byKeys lookups of deleted entities, by entity (AUDIT_DELETED_ACCESS)\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_deleted_entity_lookups_total counter\n")
+			for _, entity := range stats {
+				_, _ = fmt.Fprintf(w, "air_deleted_entity_lookups_total{entity=%q} %d\n", entity.Entity, entity.Attempts)
+			}
+		}
+		if stats, ok := resolvers.CurrentAccessAuditStats(); ok {
+			_, _ = fmt.Fprintf(w, "# HELP air_access_audit_events_total Access audit events by result: written, dropped (queue full) or failed (insert error)\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_access_audit_events_total counter\n")
+			_, _ = fmt.Fprintf(w, "air_access_audit_events_total{result=\"written\"} %d\n", stats.Written)
+			_, _ = fmt.Fprintf(w, "air_access_audit_events_total{result=\"dropped\"} %d\n", stats.Dropped)
+			_, _ = fmt.Fprintf(w, "air_access_audit_events_total{result=\"failed\"} %d\n", stats.Failed)
+		}
+		if stats, ok := resolvers.CurrentWriteRetryStats(); ok {
+			_, _ = fmt.Fprintf(w, "# HELP air_write_retry_pending Failed auxiliary writes waiting for replay\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_write_retry_pending gauge\n")
+			_, _ = fmt.Fprintf(w, "air_write_retry_pending %d\n", stats.Pending)
+			_, _ = fmt.Fprintf(w, "# HELP air_write_retries_total Failed auxiliary writes by result: queued, dropped (queue full) or replayed\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_write_retries_total counter\n")
+			_, _ = fmt.Fprintf(w, "air_write_retries_total{result=\"queued\"} %d\n", stats.Queued)
+			_, _ = fmt.Fprintf(w, "air_write_retries_total{result=\"dropped\"} %d\n", stats.Dropped)
+			_, _ = fmt.Fprintf(w, "air_write_retries_total{result=\"replayed\"} %d\n", stats.Replayed)
+		}
+		if stats := resolvers.CurrentOversizedDocumentStats(); len(stats) > 0 {
+			_, _ = fmt.Fprintf(w, "# HELP air_oversized_documents_total Documents above DOCUMENT_MAX_BYTES read for a result, by entity\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_oversized_documents_total counter\n")
+			for _, entity := range stats {
+				_, _ = fmt.Fprintf(w, "air_oversized_documents_total{entity=%q} %d\n", entity.Entity, entity.Documents)
+			}
+		}
+		if usage := resolvers.CurrentFilterUsage(); len(usage) > 0 {
+			_, _ = fmt.Fprintf(w, "# HELP air_filter_operator_uses_total Search filters by entity, filtered field and operator\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_filter_operator_uses_total counter\n")
+			for _, counter := range usage {
+				_, _ = fmt.Fprintf(w, "air_filter_operator_uses_total{entity=%q,field=%q,operator=%q} %d\n", counter.Entity, counter.Field, counter.Operator, counter.Count)
+			}
+		}
+		if usage := resolvers.CurrentDeprecatedFieldUsage(); len(usage) > 0 {
+			_, _ = fmt.Fprintf(w, "# HELP air_deprecated_field_uses_total Operations using a deprecated field or argument, by schema coordinate and client (DEPRECATION_CLIENT_HEADER)\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_deprecated_field_uses_total counter\n")
+			for _, counter := range usage {
+				_, _ = fmt.Fprintf(w, "air_deprecated_field_uses_total{field=%q,client=%q} %d\n", counter.Coordinate, counter.Client, counter.Count)
+			}
+		}
+		if stats := purge.CurrentStats(); len(stats) > 0 {
+			_, _ = fmt.Fprintf(w, "# HELP air_purged_entities_total Deleted entities purged after their retention period, by entity; dry_run counts those dry runs would have purged\n")
+			_, _ = fmt.Fprintf(w, "# TYPE air_purged_entities_total counter\n")
+			for _, entity := range stats {
+				_, _ = fmt.Fprintf(w, "air_purged_entities_total{entity=%q,dry_run=\"false\"} %d\n", entity.Entity, entity.Purged)
+				_, _ = fmt.Fprintf(w, "air_purged_entities_total{entity=%q,dry_run=\"true\"} %d\n", entity.Entity, entity.DryRunPurged)
+			}
+		}
+	}
+}
+
+// Handler returns the /ready endpoint. READY and DEGRADED answer 200 so that a brief
+// MongoDB failover does not take every instance out of rotation; NOT_READY and
+// SHUTTING_DOWN answer 503
+func (r *Readiness) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		report := r.Evaluate(req.Context())
+
+		status := http.StatusOK
+		if report.State == ReadinessNotReady || report.State == ReadinessShuttingDown {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/yourusername/air-go/internal/graphql"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/health"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// Statuses of a schema reload
+const (
+	SchemaReloaded  = "reloaded"
+	SchemaUnchanged = "unchanged"
+	SchemaRejected  = "rejected"
+)
+
+// servedSchema is the schema the GraphQL endpoint serves, its deprecations and the service info
+// describing it. All are replaced together by a reload; a request keeps the servedSchema it
+// started with
+type servedSchema struct {
+	schema       *ast.Schema               // Loaded schema with interpolated descriptions (nil uses the generated schema)
+	deprecations *resolvers.DeprecationSet // Deprecations of a reloaded schema (nil uses those set at startup)
+	info         *health.ServiceInfo       // Schema and build metadata reported by /health and the serviceInfo query
+}
+
+// currentServed returns the served schema; its fields are nil when no option set them
+func (s *Server) currentServed() *servedSchema {
+	if served := s.served.Load(); served != nil {
+		return served
+	}
+	return &servedSchema{}
+}
+
+// updateServed replaces the served schema with a copy changed by update
+func (s *Server) updateServed(update func(served *servedSchema)) {
+	served := *s.currentServed()
+	update(&served)
+	s.served.Store(&served)
+}
+
+// currentServiceInfo returns the service info of the served schema, nil when none was set
+func (s *Server) currentServiceInfo() *health.ServiceInfo {
+	return s.currentServed().info
+}
+
+// ReloadSchema re-reads the schema file and, when it loads and validates, this build implements
+// everything it declares and the configured disabled features and heavy fields still apply, serves
+// it and its deprecations to the requests that start afterwards. Requests
+// already running, and open websocket connections, finish on the schema they started with. A
+// schema that fails is reported and the previous one stays in place. Reloads run one at a time
+func (s *Server) ReloadSchema() *health.SchemaReload {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	previous := s.currentServed()
+	result := &health.SchemaReload{At: s.readiness.clock.Now().UTC().Format(time.RFC3339)}
+	if previous.info != nil && previous.info.Schema != nil {
+		result.PreviousChecksum = previous.info.Schema.Checksum
+	}
+
+	var deprecations *resolvers.DeprecationSet
+	loaded, err := graphql.LoadSchema(s.config.SchemaPath)
+	switch {
+	case err != nil:
+		result.Status = SchemaRejected
+		result.Errors = []string{err.Error()}
+	case loaded.Checksum == result.PreviousChecksum:
+		result.Status = SchemaUnchanged
+		result.Checksum = loaded.Checksum
+	default:
+		result.Checksum = loaded.Checksum
+		result.Errors = graphql.CheckImplemented(loaded.Schema)
+		if len(result.Errors) == 0 {
+			deprecations, err = s.checkReloadedSchema(loaded.Schema)
+			if err != nil {
+				result.Errors = []string{err.Error()}
+			}
+		}
+		result.Status = SchemaRejected
+		if len(result.Errors) == 0 {
+			result.Status = SchemaReloaded
+		}
+	}
+
+	info := health.ServiceInfo{Build: health.CurrentBuildInfo()}
+	if previous.info != nil {
+		info = *previous.info
+	}
+	info.SchemaReload = result
+	next := &servedSchema{schema: previous.schema, deprecations: previous.deprecations, info: &info}
+	if result.Status == SchemaReloaded {
+		next.schema = loaded.Schema
+		next.deprecations = deprecations
+		info.Schema = &health.SchemaInfo{
+			Path:      loaded.SchemaPath,
+			TypeCount: len(loaded.Schema.Types),
+			Checksum:  loaded.Checksum,
+			LoadedAt:  loaded.LoadedAt.UTC().Format(time.RFC3339),
+		}
+	}
+	s.served.Store(next)
+
+	event := s.logger.Info()
+	if result.Status == SchemaRejected {
+		event = s.logger.Error()
+	}
+	event.
+		Str("status", result.Status).
+		Str("previous_checksum", result.PreviousChecksum).
+		Str("checksum", result.Checksum).
+		Strs("errors", result.Errors).
+		Msg("GraphQL schema reload")
+	return result
+}
+
+// checkReloadedSchema validates the configured disabled features and search heavy fields against
+// a reloaded schema, as at startup, and reads its deprecations
+func (s *Server) checkReloadedSchema(schema *ast.Schema) (*resolvers.DeprecationSet, error) {
+	if err := resolvers.CheckDisabledFeatures(s.config.DisabledEntities, s.config.DisabledOperations, schema); err != nil {
+		return nil, fmt.Errorf("DISABLED_ENTITIES or DISABLED_OPERATIONS: %w", err)
+	}
+	if err := resolvers.CheckSearchHeavyFields(s.config.SearchHeavyFields, schema); err != nil {
+		return nil, fmt.Errorf("SEARCH_HEAVY_FIELDS: %w", err)
+	}
+	return resolvers.LoadDeprecations(schema, s.config.DeprecationEnforceSunset)
+}
+
+// schemaReloadHandler serves the schema reload route: administrators POST to reload the schema
+// and receive the result, with 422 Unprocessable Entity when the schema was rejected
+func (s *Server) schemaReloadHandler(w http.ResponseWriter, r *http.Request) {
+	if !resolvers.IsAdmin(r.Context()) {
+		http.Error(w, "Administrator privileges required", http.StatusForbidden)
+		return
+	}
+
+	result := s.ReloadSchema()
+	status := http.StatusOK
+	if result.Status == SchemaRejected {
+		status = http.StatusUnprocessableEntity
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// schemaReloadRoute returns the schema reload handler behind JWT authentication
+func (s *Server) schemaReloadRoute() http.Handler {
+	return middleware.AuthMiddleware(s.config.JWTSecret)(principalMiddleware(http.HandlerFunc(s.schemaReloadHandler)))
+}
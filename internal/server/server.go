@@ -2,33 +2,79 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
 	"github.com/rs/cors"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/yourusername/air-go/internal/config"
 	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/export"
 	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/persisted"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
 	"github.com/yourusername/air-go/internal/health"
 	"github.com/yourusername/air-go/internal/server/middleware"
 )
 
+// ExplainHeader requests search execution plans instead of data (admin only)
+const ExplainHeader = "X-Debug-Explain"
+
+// AppliedFilterHeader requests the final search filter in the results (developer only)
+const AppliedFilterHeader = "X-Debug-Applied-Filter"
+
 // Server represents the HTTP server
 type Server struct {
 	config   *config.Config
 	router   *chi.Mux
 	srv      *http.Server
 	dbClient health.DBHealthChecker // Database client for health checks
+
+	// Logger of the server and its readiness checks
+	logger zerolog.Logger
+
+	// Schema served by the GraphQL endpoint and its metadata, replaced by schema reloads
+	served   atomic.Pointer[servedSchema]
+	reloadMu sync.Mutex
+
+	// Persisted operations manifest; when set, only listed operations are executed
+	persistedOperations *persisted.Manifest
+
+	// Serving state reported by /ready
+	readiness *Readiness
+
+	// Query results answering reads while DEGRADED (nil when disabled)
+	degradedCache *DegradedCache
+
+	// Websocket connections of the GraphQL endpoint
+	websockets *WebsocketHub
+
+	// Admin listener serving /health, /ready and /metrics; nil serves them on the main listener
+	adminRouter *chi.Mux
+	adminSrv    *http.Server
+
+	// Bound by Listen: the main listener, then the admin listener
+	listeners []net.Listener
 }
 
 // Option is a function that configures the server
@@ -41,11 +87,41 @@ func WithDatabaseClient(dbClient health.DBHealthChecker) Option {
 	}
 }
 
+// WithLogger sets the logger of the server and its readiness checks; the global logger by default
+func WithLogger(logger zerolog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithSchema sets the schema served by the GraphQL endpoint and its introspection
+func WithSchema(schema *ast.Schema) Option {
+	return func(s *Server) {
+		s.updateServed(func(served *servedSchema) { served.schema = schema })
+	}
+}
+
+// WithServiceInfo sets the schema and build metadata reported by /health and serviceInfo
+func WithServiceInfo(info *health.ServiceInfo) Option {
+	return func(s *Server) {
+		s.updateServed(func(served *servedSchema) { served.info = info })
+	}
+}
+
+// WithPersistedOperations enables allow-list mode: GraphQL operations whose hash is not
+// in the manifest are rejected. The /health endpoint is not affected.
+func WithPersistedOperations(manifest *persisted.Manifest) Option {
+	return func(s *Server) {
+		s.persistedOperations = manifest
+	}
+}
+
 // New creates a new HTTP server with configured routes and middleware
 func New(cfg *config.Config, opts ...Option) *Server {
 	s := &Server{
 		config: cfg,
 		router: chi.NewRouter(),
+		logger: log.Logger,
 	}
 
 	// Apply options
@@ -53,34 +129,80 @@ func New(cfg *config.Config, opts ...Option) *Server {
 		opt(s)
 	}
 
+	s.readiness = NewReadiness(s.dbClient, db.RealClock{}, s.logger)
+	if cfg.IndexManagementEnabled {
+		s.readiness.RequireIndexes()
+	}
+	if cfg.DegradedCacheEntries > 0 {
+		s.degradedCache = NewDegradedCache(s.readiness, cfg.DegradedCacheEntries)
+	}
+
+	// Websockets carry subscriptions; without a Subscription type they would only open an
+	// authenticated endpoint with nothing to serve
+	if servesSubscriptions(s.currentServed().schema) {
+		s.websockets = NewWebsocketHub(cfg)
+	}
+
+	if cfg.AdminListenAddress != "" {
+		s.adminRouter = chi.NewRouter()
+	}
+
 	s.setupMiddleware()
 	s.setupRoutes()
 
-	s.srv = &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      s.router,
+	// The GraphQL handler is built from the loaded schema during route setup
+	s.readiness.MarkSchemaLoaded()
+
+	address := cfg.ListenAddress
+	if address == "" {
+		address = fmt.Sprintf(":%d", cfg.Port)
+	}
+	s.srv = newHTTPServer(address, s.router)
+	if s.adminRouter != nil {
+		s.adminSrv = newHTTPServer(cfg.AdminListenAddress, s.adminRouter)
+	}
+
+	return s
+}
+
+// newHTTPServer creates a listener's HTTP server with the request timeouts
+func newHTTPServer(address string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         address,
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+}
 
-	return s
+// routePath returns the configured path of a route, or its default when none is configured
+func routePath(configured, fallback string) string {
+	if configured == "" {
+		return fallback
+	}
+	return configured
 }
 
 // setupMiddleware configures the middleware chain
 func (s *Server) setupMiddleware() {
-	// Basic middleware (applied to all routes)
-	s.router.Use(chimiddleware.RequestID)
-	s.router.Use(chimiddleware.RealIP)
-	s.router.Use(chimiddleware.Recoverer)
-	s.router.Use(middleware.LoggingMiddleware)
+	// Basic middleware (applied to all routes of both listeners)
+	for _, router := range []*chi.Mux{s.router, s.adminRouter} {
+		if router == nil {
+			continue
+		}
+		router.Use(chimiddleware.RequestID)
+		router.Use(chimiddleware.RealIP)
+		router.Use(chimiddleware.Recoverer)
+		router.Use(middleware.LoggingMiddleware)
+	}
 
 	// CORS middleware
 	corsMiddleware := cors.New(cors.Options{
 		AllowedOrigins:   s.config.CORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-None-Match", "X-Request-ID", ExplainHeader, AppliedFilterHeader, DBStatsHeader, IdempotencyKeyHeader, s.deprecationClientHeader()},
+		ExposedHeaders:   []string{"ETag", "X-Request-ID", "Deprecation", "Sunset"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
@@ -88,16 +210,50 @@ func (s *Server) setupMiddleware() {
 }
 
 // setupRoutes configures all HTTP routes
+// Admin routes go to the admin listener when one is configured
 func (s *Server) setupRoutes() {
+	admin := s.router
+	if s.adminRouter != nil {
+		admin = s.adminRouter
+	}
+
 	// Health check endpoint (no authentication required)
 	// Passes database client if available for health monitoring
-	s.router.Get("/health", health.Handler(s.dbClient))
+	admin.Get(routePath(s.config.HealthPath, "/health"), health.Handler(s.dbClient, s.currentServiceInfo, resolvers.SearchConcurrencyStats))
+
+	// Readiness endpoint (no authentication required)
+	admin.Get(routePath(s.config.ReadyPath, "/ready"), s.readiness.Handler())
+
+	// Readiness state gauge for metric scrapers (no authentication required)
+	admin.Get(routePath(s.config.MetricsPath, "/metrics"), s.metricsHandler)
+
+	// Schema reload (ADMIN role required)
+	if s.config.SchemaReloadEnabled {
+		admin.Post(routePath(s.config.SchemaReloadPath, "/admin/schema/reload"), s.schemaReloadRoute().ServeHTTP)
+	}
 
 	// GraphQL endpoint (authentication required)
 	// This will be implemented in later phases (T025)
-	s.router.Route("/graphql", func(r chi.Router) {
+	s.router.Route(routePath(s.config.GraphQLPath, "/graphql"), func(r chi.Router) {
+		authenticated := func(next http.Handler) http.Handler {
+			return middleware.AuthMiddleware(s.config.JWTSecret)(principalMiddleware(next))
+		}
+
+		// Plain GET queries are tagged with the versions of the entities they read. Websocket
+		// connections authenticate with their connection_init payload
+		get := authenticated(EntityTagHandler(http.HandlerFunc(s.graphQLHandler)))
+		if s.websockets != nil {
+			get = s.websockets.Handler(http.HandlerFunc(s.graphQLHandler), get)
+		}
+		r.Get("/", get.ServeHTTP)
+		r.Post("/", authenticated(http.HandlerFunc(s.graphQLHandler)).ServeHTTP)
+	})
+
+	// NDJSON export endpoint (authentication required)
+	s.router.Route(routePath(s.config.ExportPath, "/export"), func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware(s.config.JWTSecret))
-		r.Post("/", s.graphQLHandler)
+		r.Use(principalMiddleware)
+		r.Post("/{entity}", s.exportHandler)
 	})
 }
 
@@ -111,69 +267,371 @@ func (s *Server) graphQLHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The request finishes on the schema it started with, whatever reloads happen meanwhile
+	served := s.currentServed()
+	if served.deprecations != nil {
+		r = r.WithContext(resolvers.WithDeprecations(r.Context(), served.deprecations))
+	}
+
+	// Debug responses differ from regular ones, so they bypass the degraded cache
+	cache := s.degradedCache
+
+	// Debug explain mode; resolvers reject it for non-admin callers
+	if r.Header.Get(ExplainHeader) != "" {
+		r = r.WithContext(resolvers.WithExplain(r.Context()))
+		cache = nil
+	}
+
+	// Debug filter echo; only developers receive the appliedFilter field
+	if r.Header.Get(AppliedFilterHeader) != "" {
+		r = r.WithContext(resolvers.WithAppliedFilter(r.Context()))
+		cache = nil
+	}
+
+	// Read-only servers refuse mutations, so there are no idempotency keys to record
+	var idempotency *IdempotencyStore
+	if !s.config.ReadOnly {
+		idempotency = NewIdempotencyStore(dbClient.Collection(IdempotencyCollection), s.config.IdempotencyKeyTTL, s.readiness.clock)
+	}
+
 	resolver := &resolvers.Resolver{
-		DBClient: dbClient,
+		DBClient:    dbClient,
+		ServiceInfo: served.info,
+	}
+	srv := newGraphQLHandler(generated.NewExecutableSchema(generated.Config{
+		Schema:     served.schema,
+		Resolvers:  resolver,
+		Complexity: resolvers.Complexity(),
+	}), graphQLHandlerOptions{
+		Manifest:                s.persistedOperations,
+		QueryComments:           s.config.QueryCommentsEnabled,
+		Cache:                   cache,
+		Idempotency:             idempotency,
+		Websockets:              s.websockets,
+		HideDisabled:            s.config.HideDisabledFeatures,
+		DBStatsAlways:           s.config.DBStatsAlways,
+		DeprecationClientHeader: s.deprecationClientHeader(),
+	})
+
+	// Responses of operations using deprecated fields announce it in their headers; websockets
+	// have no response headers once upgraded
+	if !websocket.IsWebSocketUpgrade(r) {
+		w, r = withDeprecationNotice(w, r)
+	}
+
+	// Some clients send an array of operations in one POST
+	if isBatchRequest(r) {
+		s.serveBatch(srv, w, r)
+		return
 	}
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
 	srv.ServeHTTP(w, r)
 }
 
+// metricsHandler serves /metrics: the readiness and resolver metrics, then the websocket metrics
+// when websockets are served
+func (s *Server) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	s.readiness.MetricsHandler()(w, r)
+	if s.websockets != nil {
+		s.websockets.writeMetrics(w)
+	}
+}
+
+// exportHandler streams entity exports as NDJSON
+func (s *Server) exportHandler(w http.ResponseWriter, r *http.Request) {
+	dbClient, ok := s.dbClient.(*db.Client)
+	if !ok {
+		http.Error(w, "Database client not available", http.StatusInternalServerError)
+		return
+	}
+
+	export.Handler(dbClient, export.Options{
+		MaxRows:   resolvers.CurrentLimits().ExportMaxRows,
+		BatchSize: s.config.ExportBatchSize,
+		Timeout:   s.config.ExportTimeout,
+	})(w, r)
+}
+
+// principalMiddleware exposes the authenticated principal to resolvers for data-level authorization
+func principalMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if claims, ok := middleware.GetClaims(r.Context()); ok {
+			r = r.WithContext(resolvers.WithUserClaims(r.Context(), userClaimsFromJWT(claims)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// userClaimsFromJWT maps validated JWT claims onto the resolver principal
+func userClaimsFromJWT(claims jwt.MapClaims) *resolvers.UserClaims {
+	userID, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	return &resolvers.UserClaims{
+		UserID:      userID,
+		Email:       email,
+		Roles:       stringSliceClaim(claims, "roles"),
+		Permissions: stringSliceClaim(claims, "permissions"),
+		Teams:       stringSliceClaim(claims, "teams"),
+		Groups:      stringSliceClaim(claims, "groups"),
+	}
+}
+
+// stringSliceClaim reads a JSON array claim, ignoring non-string entries
+func stringSliceClaim(claims jwt.MapClaims, key string) []string {
+	raw, ok := claims[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// graphQLHandlerOptions configures the optional parts of the GraphQL handler
+type graphQLHandlerOptions struct {
+	Manifest                *persisted.Manifest // Replaces automatic persisted queries when set
+	QueryComments           bool                // Tag database queries with the operation name and request ID
+	Cache                   *DegradedCache      // Answers queries while DEGRADED; nil disables it
+	Idempotency             *IdempotencyStore   // Replays mutations carrying an Idempotency-Key; nil disables it
+	Websockets              *WebsocketHub       // Serves operations over websockets; nil disables them
+	HideDisabled            bool                // Omit disabled features from introspection
+	DBStatsAlways           bool                // Report database operations without DBStatsHeader
+	DeprecationClientHeader string              // Header naming the client in the deprecated field counts
+}
+
+// newGraphQLHandler builds the gqlgen handler with the default transports and extensions,
+// complexity limit, relation loaders and the field middlewares for disabled features, read-only
+// mode, resolver stats and ETags
+func newGraphQLHandler(es graphql.ExecutableSchema, opts graphQLHandlerOptions) *handler.Server {
+	srv := handler.New(es)
+	srv.SetErrorPresenter(resolvers.ErrorPresenter)
+
+	if opts.Websockets != nil {
+		srv.AddTransport(opts.Websockets.Transport())
+		srv.AroundOperations(opts.Websockets.AroundOperations)
+	}
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(resolvers.CurrentLimits().MaxComplexity))
+	// Operations refused for a sunset field run nothing, so they are neither cached nor counted
+	srv.AroundOperations(DeprecatedFieldOperations(opts.DeprecationClientHeader))
+	// Outside the relation loaders, so their batched reads are counted too
+	srv.AroundOperations(dbStatsOperations(opts.DBStatsAlways))
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		if opts.QueryComments {
+			ctx = db.WithQueryComment(ctx, db.QueryComment{
+				Operation: operationName(graphql.GetOperationContext(ctx)),
+				RequestID: middleware.RequestIDFromContext(ctx),
+			})
+		}
+		return next(resolvers.WithRelationLoaders(ctx))
+	})
+	srv.AroundFields(disabledFeatureFields(opts.HideDisabled))
+	srv.AroundFields(resolvers.RejectReadOnlyMutations)
+	srv.AroundFields(resolvers.RecordResolverStats)
+	srv.AroundFields(resolvers.TrackEntityVersions)
+	if opts.Cache != nil {
+		srv.AroundOperations(opts.Cache.AroundOperations)
+	}
+	if opts.Idempotency != nil {
+		srv.AroundOperations(opts.Idempotency.AroundOperations)
+	}
+	if opts.Manifest != nil {
+		srv.Use(persisted.Extension{Manifest: opts.Manifest})
+	} else {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New[string](100),
+		})
+	}
+
+	return srv
+}
+
+// operationName returns the name of the executed operation, which clients may omit from the request
+func operationName(oc *graphql.OperationContext) string {
+	if oc.Operation != nil {
+		return oc.Operation.Name
+	}
+	return oc.OperationName
+}
+
 // ServeHTTP implements http.Handler interface to allow using Server with httptest
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
 }
 
-// Start begins listening for HTTP requests and handles graceful shutdown
+// AdminHandler returns the handler of the admin listener, or nil when the admin routes are
+// served on the main listener
+func (s *Server) AdminHandler() http.Handler {
+	if s.adminRouter == nil {
+		return nil
+	}
+	return s.adminRouter
+}
+
+// Readiness returns the serving state tracker behind /ready
+func (s *Server) Readiness() *Readiness {
+	return s.readiness
+}
+
+// httpServers returns the main server and, when configured, the admin server
+func (s *Server) httpServers() []*http.Server {
+	if s.adminSrv == nil {
+		return []*http.Server{s.srv}
+	}
+	return []*http.Server{s.srv, s.adminSrv}
+}
+
+// Listen binds the main listener and, when configured, the admin listener
+func (s *Server) Listen() error {
+	for _, srv := range s.httpServers() {
+		listener, err := net.Listen("tcp", srv.Addr)
+		if err != nil {
+			for _, bound := range s.listeners {
+				_ = bound.Close()
+			}
+			s.listeners = nil
+			return fmt.Errorf("listen on %s: %w", srv.Addr, err)
+		}
+		s.listeners = append(s.listeners, listener)
+	}
+	return nil
+}
+
+// Addr returns the address the main listener is bound to, or "" before Listen
+func (s *Server) Addr() string {
+	if len(s.listeners) == 0 {
+		return ""
+	}
+	return s.listeners[0].Addr().String()
+}
+
+// AdminAddr returns the address the admin listener is bound to, or "" before Listen or without one
+func (s *Server) AdminAddr() string {
+	if len(s.listeners) < 2 {
+		return ""
+	}
+	return s.listeners[1].Addr().String()
+}
+
+// Start binds the listeners, then serves them and handles graceful shutdown
 func (s *Server) Start() error {
-	// Channel to listen for errors from the server
-	serverErrors := make(chan error, 1)
+	if err := s.Listen(); err != nil {
+		return err
+	}
+	return s.Serve()
+}
+
+// Serve serves the listeners bound by Listen until Stop is called, a listener fails or a
+// shutdown signal arrives. All listeners shut down together
+func (s *Server) Serve() error {
+	// Keep the readiness state current while serving, whether or not anything probes it
+	readinessCtx, stopReadiness := context.WithCancel(context.Background())
+	defer stopReadiness()
+	if s.config.ReadinessCheckInterval > 0 {
+		go s.readiness.Run(readinessCtx, s.config.ReadinessCheckInterval)
+	}
 
-	// Start the server in a goroutine
-	go func() {
-		log.Info().
-			Int("port", s.config.Port).
-			Str("schema_path", s.config.SchemaPath).
-			Msg("Starting HTTP server")
+	// Channel to listen for errors from the servers
+	servers := s.httpServers()
+	serverErrors := make(chan error, len(servers))
 
-		serverErrors <- s.srv.ListenAndServe()
-	}()
+	for i, srv := range servers {
+		listener := s.listeners[i]
+		go func() {
+			s.logger.Info().
+				Str("address", listener.Addr().String()).
+				Bool("admin", srv == s.adminSrv).
+				Str("schema_path", s.config.SchemaPath).
+				Msg("Starting HTTP server")
+
+			serverErrors <- srv.Serve(listener)
+		}()
+	}
 
 	// Channel to listen for interrupt signals
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(shutdown)
 
-	// Block until we receive a signal or an error
-	select {
-	case err := <-serverErrors:
-		if err != nil && err != http.ErrServerClosed {
-			return fmt.Errorf("server error: %w", err)
-		}
+	// Block until every server stopped, one failed or we receive a signal
+	for running := len(servers); running > 0; running-- {
+		select {
+		case err := <-serverErrors:
+			if err != nil && err != http.ErrServerClosed {
+				s.closeServers()
+				return fmt.Errorf("server error: %w", err)
+			}
 
-	case sig := <-shutdown:
-		log.Info().
-			Str("signal", sig.String()).
-			Msg("Received shutdown signal, starting graceful shutdown")
+		case sig := <-shutdown:
+			s.logger.Info().
+				Str("signal", sig.String()).
+				Msg("Received shutdown signal, starting graceful shutdown")
 
-		// Give outstanding requests 30 seconds to complete
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+			s.readiness.BeginShutdown()
 
-		if err := s.srv.Shutdown(ctx); err != nil {
-			log.Error().Err(err).Msg("Error during server shutdown")
-			// Force close the server
-			if closeErr := s.srv.Close(); closeErr != nil {
-				return fmt.Errorf("could not stop server gracefully: %w", closeErr)
+			// Give outstanding requests 30 seconds to complete
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			if err := s.shutdown(ctx); err != nil {
+				s.logger.Error().Err(err).Msg("Error during server shutdown")
+				// Force close the servers
+				if closeErr := s.closeServers(); closeErr != nil {
+					return fmt.Errorf("could not stop server gracefully: %w", closeErr)
+				}
+				return fmt.Errorf("server shutdown error: %w", err)
 			}
-			return fmt.Errorf("server shutdown error: %w", err)
-		}
 
-		log.Info().Msg("Server stopped gracefully")
+			s.logger.Info().Msg("Server stopped gracefully")
+			return nil
+		}
 	}
 
 	return nil
 }
 
-// Stop gracefully stops the server
+// Stop gracefully stops the servers, waiting for the outstanding requests of both listeners
 func (s *Server) Stop(ctx context.Context) error {
-	return s.srv.Shutdown(ctx)
+	s.readiness.BeginShutdown()
+	return s.shutdown(ctx)
+}
+
+// shutdown gracefully shuts the servers down concurrently, so they drain within the same deadline.
+// Websocket connections are closed first, as the servers do not track upgraded connections
+func (s *Server) shutdown(ctx context.Context) error {
+	if s.websockets != nil {
+		s.websockets.Drain()
+	}
+
+	servers := s.httpServers()
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, srv := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = srv.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// closeServers closes the servers without waiting for outstanding requests
+func (s *Server) closeServers() error {
+	var errs []error
+	for _, srv := range s.httpServers() {
+		errs = append(errs, srv.Close())
+	}
+	return errors.Join(errs...)
 }
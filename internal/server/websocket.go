@@ -0,0 +1,388 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gorilla/websocket"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// Close codes of the websocket connections the server ends. The 44xx codes follow the
+// graphql-transport-ws protocol; draining uses the standard going away code
+const (
+	WebsocketCloseShutdown     = websocket.CloseGoingAway // 1001: the server is shutting down
+	WebsocketCloseUnauthorized = 4401                     // connection_init carried no valid token
+	WebsocketCloseIdle         = 4408                     // No operation ran for the idle timeout
+	WebsocketCloseTooMany      = 4429                     // The server or the user holds too many connections
+)
+
+// websocketInitTimeout bounds how long a client may take to send connection_init
+const websocketInitTimeout = 10 * time.Second
+
+// websocketCloseReasons label the connections closed by the server in the metrics, by close code
+var websocketCloseReasons = map[int]string{
+	WebsocketCloseShutdown:     "shutdown",
+	WebsocketCloseUnauthorized: "unauthorized",
+	WebsocketCloseIdle:         "idle",
+	WebsocketCloseTooMany:      "limit",
+}
+
+// WebsocketHub authenticates and tracks the websocket connections of the GraphQL endpoint.
+// Browsers cannot set headers on websocket upgrades, so connections authenticate with the
+// Authorization value of their connection_init payload, falling back to the upgrade request's
+// header. It limits the connections per server and per user, closes connections that ran no
+// operation for the idle timeout, and closes all of them when the server drains
+type WebsocketHub struct {
+	jwtSecret       string
+	keepAlive       time.Duration
+	idleTimeout     time.Duration // 0 keeps idle connections open
+	maxConnections  int           // 0 is unlimited
+	maxPerPrincipal int           // 0 is unlimited
+
+	mu          sync.Mutex
+	connections map[*websocketConnection]struct{}
+	byPrincipal map[string]int
+	draining    bool
+	closed      map[int]int64 // Connections closed by the server, by close code
+
+	operations atomic.Int64
+}
+
+// websocketConnection is the state of one upgraded connection
+type websocketConnection struct {
+	hub           *WebsocketHub
+	authorization string // Authorization header of the upgrade request
+	principal     string
+
+	mu           sync.Mutex
+	cancel       context.CancelFunc // Ends the connection; set once it is admitted
+	operations   int
+	idle         *time.Timer
+	closeCode    int
+	closeMessage string
+}
+
+// websocketConnectionKey is the context key of the websocketConnection serving an operation
+type websocketConnectionKey struct{}
+
+// WebsocketStats is the state of the websocket connections reported by /metrics
+type WebsocketStats struct {
+	Connections int
+	Operations  int64
+	Closed      map[string]int64 // Connections closed by the server, by reason
+}
+
+// servesSubscriptions reports whether the served schema, or this build's when none was set,
+// declares a Subscription type; the websocket transport is only registered for one
+func servesSubscriptions(schema *ast.Schema) bool {
+	if schema == nil {
+		schema = generated.NewExecutableSchema(generated.Config{}).Schema()
+	}
+	return schema.Subscription != nil
+}
+
+// NewWebsocketHub creates the hub of the configured keep-alive interval, idle timeout and limits
+func NewWebsocketHub(cfg *config.Config) *WebsocketHub {
+	return &WebsocketHub{
+		jwtSecret:       cfg.JWTSecret,
+		keepAlive:       cfg.WebsocketKeepAliveInterval,
+		idleTimeout:     cfg.WebsocketIdleTimeout,
+		maxConnections:  cfg.WebsocketMaxConnections,
+		maxPerPrincipal: cfg.WebsocketMaxPerPrincipal,
+		connections:     make(map[*websocketConnection]struct{}),
+		byPrincipal:     make(map[string]int),
+		closed:          make(map[int]int64),
+	}
+}
+
+// Transport returns the gqlgen websocket transport authenticating connections with the hub.
+// graphql-ws clients receive keep-alive messages, graphql-transport-ws clients pings they must
+// answer within twice the interval
+func (h *WebsocketHub) Transport() transport.Websocket {
+	return transport.Websocket{
+		InitFunc:              h.initConnection,
+		InitTimeout:           websocketInitTimeout,
+		KeepAlivePingInterval: h.keepAlive,
+		PingPongInterval:      h.keepAlive,
+	}
+}
+
+// Handler serves websocket upgrades of the GraphQL endpoint with next and other requests
+// with plain, which must authenticate them itself
+func (h *WebsocketHub) Handler(next, plain http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			plain.ServeHTTP(w, r)
+			return
+		}
+
+		conn := &websocketConnection{hub: h, authorization: r.Header.Get("Authorization")}
+		defer h.release(conn)
+		ctx := context.WithValue(r.Context(), websocketConnectionKey{}, conn)
+		next.ServeHTTP(&websocketResponseWriter{ResponseWriter: w, conn: conn}, r.WithContext(ctx))
+	})
+}
+
+// AroundOperations is a gqlgen operation middleware keeping websocket connections with a
+// running operation from idling out. Operations over HTTP pass through
+func (h *WebsocketHub) AroundOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	conn, ok := ctx.Value(websocketConnectionKey{}).(*websocketConnection)
+	if !ok {
+		return next(ctx)
+	}
+	conn.begin()
+	// The transport cancels the operation's context once its last response was sent
+	context.AfterFunc(ctx, conn.end)
+	return next(ctx)
+}
+
+// Drain closes every connection and rejects new ones; the server calls it when it shuts down,
+// as http.Server.Shutdown does not wait for upgraded connections
+func (h *WebsocketHub) Drain() {
+	h.mu.Lock()
+	h.draining = true
+	connections := make([]*websocketConnection, 0, len(h.connections))
+	for conn := range h.connections {
+		connections = append(connections, conn)
+	}
+	h.mu.Unlock()
+
+	for _, conn := range connections {
+		conn.close(WebsocketCloseShutdown, "server shutting down")
+	}
+}
+
+// Stats returns the open connections, their running operations and the closed connections
+func (h *WebsocketHub) Stats() WebsocketStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stats := WebsocketStats{
+		Connections: len(h.connections),
+		Operations:  h.operations.Load(),
+		Closed:      make(map[string]int64, len(websocketCloseReasons)),
+	}
+	for code, reason := range websocketCloseReasons {
+		stats.Closed[reason] = h.closed[code]
+	}
+	return stats
+}
+
+// writeMetrics appends the websocket metrics to a /metrics response
+func (h *WebsocketHub) writeMetrics(w io.Writer) {
+	stats := h.Stats()
+	_, _ = fmt.Fprintf(w, "# HELP air_websocket_connections Open GraphQL websocket connections\n")
+	_, _ = fmt.Fprintf(w, "# TYPE air_websocket_connections gauge\n")
+	_, _ = fmt.Fprintf(w, "air_websocket_connections %d\n", stats.Connections)
+	_, _ = fmt.Fprintf(w, "# HELP air_websocket_operations Operations running on GraphQL websocket connections\n")
+	_, _ = fmt.Fprintf(w, "# TYPE air_websocket_operations gauge\n")
+	_, _ = fmt.Fprintf(w, "air_websocket_operations %d\n", stats.Operations)
+	_, _ = fmt.Fprintf(w, "# HELP air_websocket_closed_total GraphQL websocket connections closed by the server, by reason: unauthorized, limit, idle or shutdown\n")
+	_, _ = fmt.Fprintf(w, "# TYPE air_websocket_closed_total counter\n")
+	for _, reason := range []string{"unauthorized", "limit", "idle", "shutdown"} {
+		_, _ = fmt.Fprintf(w, "air_websocket_closed_total{reason=%q} %d\n", reason, stats.Closed[reason])
+	}
+}
+
+// initConnection authenticates a connection_init message and admits the connection within the limits
+func (h *WebsocketHub) initConnection(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	conn, ok := ctx.Value(websocketConnectionKey{}).(*websocketConnection)
+	if !ok {
+		return ctx, nil, errors.New("websocket connection not registered")
+	}
+
+	authorization := payload.Authorization()
+	if authorization == "" {
+		authorization = conn.authorization
+	}
+	claims, err := middleware.ValidateToken(h.jwtSecret, authorization)
+	if err != nil {
+		message := fmt.Sprintf("Unauthorized: %v", err)
+		conn.setClose(WebsocketCloseUnauthorized, message)
+		return ctx, nil, errors.New(message)
+	}
+
+	ctx, cancel := context.WithCancel(middleware.WithClaims(ctx, claims))
+	ctx = resolvers.WithUserClaims(ctx, userClaimsFromJWT(claims))
+	principal, _ := claims["sub"].(string)
+	if code, err := h.admit(conn, principal, cancel); err != nil {
+		cancel()
+		conn.setClose(code, err.Error())
+		return ctx, nil, err
+	}
+	return ctx, nil, nil
+}
+
+// admit registers a connection of a principal unless the server drains or a limit is reached
+func (h *WebsocketHub) admit(conn *websocketConnection, principal string, cancel context.CancelFunc) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case h.draining:
+		return WebsocketCloseShutdown, errors.New("server shutting down")
+	case h.maxConnections > 0 && len(h.connections) >= h.maxConnections:
+		return WebsocketCloseTooMany, fmt.Errorf("too many websocket connections to this server (at most %d)", h.maxConnections)
+	case h.maxPerPrincipal > 0 && h.byPrincipal[principal] >= h.maxPerPrincipal:
+		return WebsocketCloseTooMany, fmt.Errorf("too many websocket connections of this user (at most %d)", h.maxPerPrincipal)
+	}
+
+	conn.principal = principal
+	h.connections[conn] = struct{}{}
+	h.byPrincipal[principal]++
+	conn.start(cancel)
+	return 0, nil
+}
+
+// release unregisters a connection once its handler returned and counts why the server closed it
+func (h *WebsocketHub) release(conn *websocketConnection) {
+	conn.mu.Lock()
+	if conn.idle != nil {
+		conn.idle.Stop()
+	}
+	code := conn.closeCode
+	conn.mu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.connections[conn]; ok {
+		delete(h.connections, conn)
+		if h.byPrincipal[conn.principal]--; h.byPrincipal[conn.principal] == 0 {
+			delete(h.byPrincipal, conn.principal)
+		}
+	}
+	if code != 0 {
+		h.closed[code]++
+	}
+}
+
+// start arms the idle timer of an admitted connection
+func (c *websocketConnection) start(cancel context.CancelFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancel = cancel
+	if c.hub.idleTimeout > 0 {
+		c.idle = time.AfterFunc(c.hub.idleTimeout, c.closeIdle)
+	}
+}
+
+// begin counts an operation starting on the connection
+func (c *websocketConnection) begin() {
+	c.hub.operations.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.operations++
+	if c.idle != nil {
+		c.idle.Stop()
+	}
+}
+
+// end counts an operation completing on the connection; the last one re-arms the idle timer
+func (c *websocketConnection) end() {
+	c.hub.operations.Add(-1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.operations--
+	if c.operations == 0 && c.idle != nil {
+		c.idle.Reset(c.hub.idleTimeout)
+	}
+}
+
+// closeIdle closes the connection unless an operation started since the idle timer fired
+func (c *websocketConnection) closeIdle() {
+	c.mu.Lock()
+	running := c.operations > 0
+	c.mu.Unlock()
+	if !running {
+		c.close(WebsocketCloseIdle, "idle timeout")
+	}
+}
+
+// setClose records the code and message the connection's close frame carries; the first one wins
+func (c *websocketConnection) setClose(code int, message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closeCode == 0 {
+		c.closeCode = code
+		c.closeMessage = message
+	}
+}
+
+// close ends an admitted connection with a close code
+func (c *websocketConnection) close(code int, message string) {
+	c.setClose(code, message)
+	c.mu.Lock()
+	cancel := c.cancel
+	c.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// closing returns the close code and message recorded for the connection, or 0
+func (c *websocketConnection) closing() (int, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeCode, c.closeMessage
+}
+
+// websocketResponseWriter hands the upgraded connection to the transport wrapped in a closeCodeConn
+type websocketResponseWriter struct {
+	http.ResponseWriter
+	conn *websocketConnection
+}
+
+// Hijack takes over the connection for the websocket upgrade
+func (w *websocketResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	netConn, buf, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &closeCodeConn{Conn: netConn, conn: w.conn}, buf, nil
+}
+
+// closeCodeConn gives the close frame of a connection the server ended the recorded close code.
+// The gqlgen transport closes every connection with the normal closure code, which would not
+// tell clients why the server ended it
+type closeCodeConn struct {
+	net.Conn
+	conn *websocketConnection
+}
+
+func (c *closeCodeConn) Write(b []byte) (int, error) {
+	code, message := c.conn.closing()
+	if code == 0 || !isNormalCloseFrame(b) {
+		return c.Conn.Write(b)
+	}
+	if len(message) > 123 {
+		message = message[:123] // Control frame payloads are limited to 125 bytes
+	}
+	frame := append([]byte{b[0], byte(2 + len(message)), byte(code >> 8), byte(code)}, message...)
+	if _, err := c.Conn.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// isNormalCloseFrame reports whether b is a whole unmasked close frame with the normal closure code
+func isNormalCloseFrame(b []byte) bool {
+	return len(b) >= 4 &&
+		b[0] == 0x88 && // FIN and the close opcode
+		int(b[1]) == len(b)-2 && // Unmasked, the payload fills the write
+		binary.BigEndian.Uint16(b[2:4]) == websocket.CloseNormalClosure
+}
@@ -0,0 +1,148 @@
+// Package smoke runs a read-only self-test against a live deployment. The checks call the
+// GraphQL resolvers directly, so they cover configuration, database and resolvers without
+// the HTTP listener, and never write
+package smoke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// DefaultTimeout bounds the whole run when no timeout is configured
+const DefaultTimeout = 30 * time.Second
+
+// Options configure a smoke test run
+type Options struct {
+	CustomerID     string        // Identifier of a customer known to exist; empty skips the customerGet check
+	SchemaChecksum string        // Expected schema checksum; empty skips the checksum check
+	Timeout        time.Duration // Budget of the whole run; zero uses DefaultTimeout
+}
+
+// Check is the outcome of one check
+type Check struct {
+	Name       string `json:"name"`
+	Passed     bool   `json:"passed"`
+	Skipped    bool   `json:"skipped,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Report is the outcome of a smoke test run
+type Report struct {
+	Passed     bool    `json:"passed"`
+	Checks     []Check `json:"checks"`
+	DurationMs int64   `json:"durationMs"`
+}
+
+// check is a named check; it returns a detail for the report, errSkipped to skip, or a failure
+type check struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+// errSkipped marks a check without the configuration it needs
+var errSkipped = errors.New("skipped")
+
+// Run runs every check in order within the timeout and reports their outcome. Checks left when
+// the budget is spent fail with the context's error. The report passes when no check failed
+func Run(ctx context.Context, resolver *resolvers.Resolver, opts Options) Report {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	started := time.Now()
+	report := Report{Passed: true}
+	for _, c := range checks(resolver, opts) {
+		checkStarted := time.Now()
+		result := Check{Name: c.name}
+
+		detail, err := c.run(ctx)
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		switch {
+		case errors.Is(err, errSkipped):
+			result.Passed, result.Skipped = true, true
+		case err != nil:
+			result.Detail = err.Error()
+			report.Passed = false
+		default:
+			result.Passed, result.Detail = true, detail
+		}
+
+		result.DurationMs = time.Since(checkStarted).Milliseconds()
+		report.Checks = append(report.Checks, result)
+	}
+	report.DurationMs = time.Since(started).Milliseconds()
+	return report
+}
+
+// checks returns the checks of a run in the order they run
+func checks(resolver *resolvers.Resolver, opts Options) []check {
+	query := resolver.Query()
+	return []check{
+		{name: "health", run: func(ctx context.Context) (string, error) {
+			health, err := query.Health(ctx)
+			if err != nil {
+				return "", err
+			}
+			if health.Status != "ok" {
+				detail := health.Status
+				if health.Database != nil {
+					detail = fmt.Sprintf("%s: database %s", health.Status, health.Database.Status)
+				}
+				return "", errors.New(detail)
+			}
+			if health.Database == nil {
+				return "no database configured", nil
+			}
+			return "database " + health.Database.Status, nil
+		}},
+		{name: "schemaChecksum", run: func(ctx context.Context) (string, error) {
+			if opts.SchemaChecksum == "" {
+				return "", errSkipped
+			}
+			info, err := query.ServiceInfo(ctx)
+			if err != nil {
+				return "", err
+			}
+			if info.Schema == nil {
+				return "", errors.New("no schema loaded")
+			}
+			if info.Schema.Checksum != opts.SchemaChecksum {
+				return "", fmt.Errorf("schema checksum %s, expected %s", info.Schema.Checksum, opts.SchemaChecksum)
+			}
+			return info.Schema.Checksum, nil
+		}},
+		{name: "customerGet", run: func(ctx context.Context) (string, error) {
+			if opts.CustomerID == "" {
+				return "", errSkipped
+			}
+			customer, err := query.CustomerGet(ctx, opts.CustomerID)
+			if err != nil {
+				return "", err
+			}
+			if customer == nil {
+				return "", fmt.Errorf("customer %s not found", opts.CustomerID)
+			}
+			return customer.Identifier, nil
+		}},
+		{name: "customerSearch", run: func(ctx context.Context) (string, error) {
+			first := int64(1)
+			result, err := query.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
+			if err != nil {
+				return "", err
+			}
+			if len(result.Data) > 1 {
+				return "", fmt.Errorf("returned %d customers for first: 1", len(result.Data))
+			}
+			return fmt.Sprintf("%d customer(s)", len(result.Data)), nil
+		}},
+	}
+}
@@ -0,0 +1,139 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	scopedCustomerA = "a10e8400-e29b-41d4-a716-446655440000"
+	scopedCustomerB = "b20e8400-e29b-41d4-a716-446655440000"
+)
+
+// seedScopedCustomers seeds one customer per team group, plus a deleted customer in team A
+func seedScopedCustomers(t *testing.T, dbClient *db.Client) {
+	t.Helper()
+	seedCustomerInGroups(t, dbClient, scopedCustomerA, "Alice", "INIT", "TEAM_A")
+	seedCustomerInGroups(t, dbClient, scopedCustomerB, "Bob", "INIT", "TEAM_B")
+	seedCustomerInGroups(t, dbClient, "c30e8400-e29b-41d4-a716-446655440000", "Carol", "DELETED", "TEAM_A")
+}
+
+// Two principals over the same seed data only see their own team's customers
+func TestCustomerAuthorization_Isolation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+	seedScopedCustomers(t, dbClient)
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	agentA := testutil.WithSupportAgentContext(context.Background(), "agent-a", "TEAM_A")
+	agentB := testutil.WithSupportAgentContext(context.Background(), "agent-b", "TEAM_B")
+
+	t.Run("get in scope", func(t *testing.T) {
+		result, err := queryResolver.CustomerGet(agentA, scopedCustomerA)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, scopedCustomerA, result.Identifier)
+	})
+
+	t.Run("get out of scope returns null like not-found", func(t *testing.T) {
+		result, err := queryResolver.CustomerGet(agentB, scopedCustomerA)
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("byKeys drops out-of-scope customers", func(t *testing.T) {
+		results, err := queryResolver.CustomerByKeysGet(agentA, []string{scopedCustomerA, scopedCustomerB}, nil)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, scopedCustomerA, results[0].Identifier)
+	})
+
+	t.Run("search only counts in-scope customers", func(t *testing.T) {
+		result, err := queryResolver.CustomerSearch(agentB, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.TotalCount)
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, scopedCustomerB, result.Data[0].Identifier)
+	})
+
+	t.Run("user filter composes with scope", func(t *testing.T) {
+		firstName := "Bob"
+		where := &generated.CustomerQueryFilterInput{
+			FirstName: &generated.StringFilterInput{Eq: &firstName},
+		}
+		result, err := queryResolver.CustomerSearch(agentA, where, nil, nil, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.TotalCount)
+		assert.Empty(t, result.Data)
+	})
+}
+
+// Admins bypass team scoping but still never see deleted customers
+func TestCustomerAuthorization_AdminBypass(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+	seedScopedCustomers(t, dbClient)
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	admin := testutil.WithAdminContext(context.Background())
+
+	result, err := queryResolver.CustomerSearch(admin, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.TotalCount)
+}
+
+// A principal without team assignments sees no customers
+func TestCustomerAuthorization_NoTeams(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+	seedScopedCustomers(t, dbClient)
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	agent := testutil.WithSupportAgentContext(context.Background(), "agent-none")
+
+	result, err := queryResolver.CustomerGet(agent, scopedCustomerA)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+// Helper: Seed customer belonging to the given customer groups
+func seedCustomerInGroups(t *testing.T, dbClient *db.Client, identifier, firstName, deletionStatus string, groups ...string) {
+	t.Helper()
+	ctx := context.Background()
+
+	collection := dbClient.Collection("customers")
+	doc := bson.M{
+		"identifier":     identifier,
+		"firstName":      firstName,
+		"lastName":       "Scoped",
+		"createDate":     testutil.NextCreateDate(),
+		"customerGroups": groups,
+		"status": bson.M{
+			"deletion": deletionStatus,
+		},
+		"actionIndicator": "NONE",
+	}
+
+	_, err := collection.InsertOne(ctx, doc)
+	require.NoError(t, err)
+}
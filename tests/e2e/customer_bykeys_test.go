@@ -11,6 +11,7 @@ import (
 	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/generated"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -93,6 +94,74 @@ func TestCustomerByKeysGet_OrderByLastNameASC(t *testing.T) {
 	assert.Equal(t, "Zimmerman", *result[2].LastName)
 }
 
+// E2E test for customerByKeysGet ordering by lastName ASC with firstName DESC breaking ties
+func TestCustomerByKeysGet_OrderByLastNameThenFirstName(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	// Two customers share a lastName, so only the second sorter entry orders them
+	id1 := "100e8400-e29b-41d4-a716-446655440013"
+	id2 := "200e8400-e29b-41d4-a716-446655440014"
+	id3 := "300e8400-e29b-41d4-a716-446655440015"
+
+	seedCustomer(t, dbClient, id1, "Alice", "Brown", "INIT")
+	seedCustomer(t, dbClient, id2, "Bob", "Anderson", "INIT")
+	seedCustomer(t, dbClient, id3, "Charlie", "Brown", "INIT")
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+
+	ascSort := generated.SortEnumTypeAsc
+	descSort := generated.SortEnumTypeDesc
+	order := []*generated.CustomerQuerySorterInput{
+		{LastName: &ascSort},
+		{FirstName: &descSort},
+	}
+
+	result, err := queryResolver.CustomerByKeysGet(ctx, []string{id1, id2, id3}, order)
+
+	// Assertions - Anderson first, then the Browns by firstName DESC
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	assert.Equal(t, "Bob", *result[0].FirstName)
+	assert.Equal(t, "Charlie", *result[1].FirstName)
+	assert.Equal(t, "Alice", *result[2].FirstName)
+}
+
+// E2E test for customerByKeysGet ordering by createDate, matching customerSearch
+func TestCustomerByKeysGet_OrderByCreateDateDESC(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	id1 := "100e8400-e29b-41d4-a716-446655440016"
+	id2 := "200e8400-e29b-41d4-a716-446655440017"
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	seedCustomerWithCreateDate(t, dbClient, id1, "Alice", "Old", "ACTIVE", "INIT", base)
+	seedCustomerWithCreateDate(t, dbClient, id2, "Bob", "New", "ACTIVE", "INIT", base.Add(24*time.Hour))
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+
+	descSort := generated.SortEnumTypeDesc
+	order := []*generated.CustomerQuerySorterInput{{CreateDate: &descSort}}
+
+	result, err := queryResolver.CustomerByKeysGet(ctx, []string{id1, id2}, order)
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, id2, result[0].Identifier)
+	assert.Equal(t, id1, result[1].Identifier)
+}
+
 // T040: E2E test for customerByKeysGet ordering by payment.status DESC
 func TestCustomerByKeysGet_OrderByPaymentStatusDESC(t *testing.T) {
 	if testing.Short() {
@@ -313,7 +382,7 @@ func seedCustomerWithPaymentStatus(t *testing.T, dbClient *db.Client, identifier
 		"identifier":  identifier,
 		"firstName":   firstName,
 		"lastName":    lastName,
-		"createDate":  time.Now().Format(time.RFC3339),
+		"createDate":  testutil.NextCreateDate(),
 		"status": bson.M{
 			"deletion": deletionStatus,
 		},
@@ -338,7 +407,7 @@ func seedCustomerWithBirthDate(t *testing.T, dbClient *db.Client, identifier, fi
 		"identifier":  identifier,
 		"firstName":   firstName,
 		"lastName":    lastName,
-		"createDate":  time.Now().Format(time.RFC3339),
+		"createDate":  testutil.NextCreateDate(),
 		"status": bson.M{
 			"deletion": deletionStatus,
 		},
@@ -352,3 +421,141 @@ func seedCustomerWithBirthDate(t *testing.T, dbClient *db.Client, identifier, fi
 	_, err := collection.InsertOne(ctx, doc)
 	require.NoError(t, err)
 }
+
+// E2E test for customerByKeysGetDetailed with found, unknown, deleted and duplicated identifiers
+func TestCustomerByKeysGetDetailed_ReportsMissing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	found1 := "100e8400-e29b-41d4-a716-446655440070"
+	found2 := "200e8400-e29b-41d4-a716-446655440071"
+	deleted := "300e8400-e29b-41d4-a716-446655440072"
+	unknown1 := "400e8400-e29b-41d4-a716-446655440073"
+	unknown2 := "500e8400-e29b-41d4-a716-446655440074"
+
+	seedCustomer(t, dbClient, found1, "Alice", "Anderson", "INIT")
+	seedCustomer(t, dbClient, found2, "Bob", "Brown", "INIT")
+	seedCustomer(t, dbClient, deleted, "Charlie", "Clark", "DELETED")
+
+	resolver := resolvers.NewResolver(dbClient)
+	queryResolver := resolver.Query()
+
+	// Duplicates of found and missing identifiers, missing ones out of identifier order
+	identifiers := []string{unknown2, found1, deleted, found1, unknown1, unknown2, found2}
+	result, err := queryResolver.CustomerByKeysGetDetailed(ctx, identifiers, nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Len(t, result.Data, 2)
+	foundIDs := []string{result.Data[0].Identifier, result.Data[1].Identifier}
+	assert.ElementsMatch(t, []string{found1, found2}, foundIDs)
+
+	// Deduplicated, in request order, deleted counted as missing
+	assert.Equal(t, []string{unknown2, deleted, unknown1}, result.Missing)
+}
+
+// E2E test for customerByKeysGetDetailed when every identifier is found or none is requested
+func TestCustomerByKeysGetDetailed_NoneMissing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	id1 := "100e8400-e29b-41d4-a716-446655440080"
+	seedCustomer(t, dbClient, id1, "Alice", "Anderson", "INIT")
+
+	resolver := resolvers.NewResolver(dbClient)
+	queryResolver := resolver.Query()
+
+	result, err := queryResolver.CustomerByKeysGetDetailed(ctx, []string{id1, id1}, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Data, 1)
+	assert.Empty(t, result.Missing)
+	assert.NotNil(t, result.Missing, "missing is an empty list, not null")
+
+	result, err = queryResolver.CustomerByKeysGetDetailed(ctx, []string{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Data)
+	assert.Empty(t, result.Missing)
+}
+
+// E2E test for customerByKeysGetDetailed rejecting invalid input like customerByKeysGet
+func TestCustomerByKeysGetDetailed_InvalidInput(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	resolver := resolvers.NewResolver(dbClient)
+	queryResolver := resolver.Query()
+
+	result, err := queryResolver.CustomerByKeysGetDetailed(ctx, []string{"not-a-uuid"}, nil)
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "invalid UUID format")
+}
+
+// E2E test for customerByKeysGetConnection returning the same envelope as customerSearch
+func TestCustomerByKeysGetConnection_MatchesSearchEnvelope(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	id1 := "100e8400-e29b-41d4-a716-446655440090"
+	id2 := "200e8400-e29b-41d4-a716-446655440091"
+	deleted := "300e8400-e29b-41d4-a716-446655440092"
+	seedCustomer(t, dbClient, id1, "Alice", "Envelope", "INIT")
+	seedCustomer(t, dbClient, id2, "Bob", "Envelope", "INIT")
+	seedCustomer(t, dbClient, deleted, "Charlie", "Envelope", "DELETED")
+
+	resolver := resolvers.NewResolver(dbClient)
+	queryResolver := resolver.Query()
+
+	lastName := "Envelope"
+	first := int64(10)
+	search, err := queryResolver.CustomerSearch(ctx, &generated.CustomerQueryFilterInput{
+		LastName: &generated.StringFilterInput{Eq: &lastName},
+	}, nil, &first, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	connection, err := queryResolver.CustomerByKeysGetConnection(ctx, []string{id1, deleted, id2, id1}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, connection)
+
+	assert.Equal(t, search.Count, connection.Count)
+	assert.Equal(t, search.TotalCount, connection.TotalCount)
+	assert.Equal(t, int64(len(connection.Data)), connection.Count)
+	assert.Equal(t, connection.Count, connection.TotalCount)
+
+	searchIDs := make([]string, len(search.Data))
+	for i, customer := range search.Data {
+		searchIDs[i] = customer.Identifier
+	}
+	connectionIDs := make([]string, len(connection.Data))
+	for i, customer := range connection.Data {
+		connectionIDs[i] = customer.Identifier
+	}
+	assert.ElementsMatch(t, searchIDs, connectionIDs)
+
+	// A single page: nothing before or after it
+	require.NotNil(t, connection.Paging)
+	assert.Equal(t, search.Paging.HasNextPage, connection.Paging.HasNextPage)
+	assert.Equal(t, search.Paging.HasPreviousPage, connection.Paging.HasPreviousPage)
+	assert.False(t, connection.Paging.HasNextPage)
+	assert.False(t, connection.Paging.HasPreviousPage)
+}
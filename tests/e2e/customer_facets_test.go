@@ -0,0 +1,194 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// facetCustomer is a seeded customer of the facet tests
+type facetCustomer struct {
+	activation string // Empty leaves status.activation unset
+	groups     []string
+	deleted    bool
+}
+
+// TestCustomerSearch_FacetsMatchManualCounts verifies the facet counts equal counting the seeded
+// non-deleted customers matching the filter by hand, regardless of the page size, and that the
+// counts are read by the search's single aggregate
+func TestCustomerSearch_FacetsMatchManualCounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+	ctx := context.Background()
+	commands := testutil.NewCommandRecorder()
+	dbClient := connectFacetTestDatabase(t, commands)
+
+	seeded := []facetCustomer{
+		{activation: "ACTIVE", groups: []string{"AIR_CUSTOMER", "TEAM_NORTH"}},
+		{activation: "ACTIVE", groups: []string{"AIR_CUSTOMER"}},
+		{activation: "ACTIVE", groups: []string{"TEAM_SOUTH"}},
+		{activation: "BLOCKED", groups: []string{"AIR_CUSTOMER", "TEAM_SOUTH"}},
+		{activation: "INIT"},
+		{groups: []string{"TEAM_NORTH"}},
+		{activation: "ACTIVE", groups: []string{"AIR_CUSTOMER"}, deleted: true},
+		{activation: "BLOCKED", groups: []string{"TEAM_NORTH"}, deleted: true},
+	}
+	seedFacetCustomers(t, dbClient, seeded)
+	commands.Reset()
+
+	// Counted by hand: every non-deleted customer, those without a value under null
+	wantActivation := map[string]int64{}
+	wantGroups := map[string]int64{}
+	for _, customer := range seeded {
+		if customer.deleted {
+			continue
+		}
+		wantActivation[customer.activation]++
+		if len(customer.groups) == 0 {
+			wantGroups[""]++
+		}
+		for _, group := range customer.groups {
+			wantGroups[group]++
+		}
+	}
+
+	first := int64(2)
+	result, err := resolvers.NewResolver(dbClient).Query().CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil,
+		[]generated.CustomerFacetField{generated.CustomerFacetFieldStatusActivation, generated.CustomerFacetFieldCustomerGroups})
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 2)
+	assert.Equal(t, int64(6), result.TotalCount)
+
+	require.Len(t, result.Facets, 2)
+	assert.Equal(t, "status.activation", result.Facets[0].Field)
+	assert.Equal(t, wantActivation, facetCounts(result.Facets[0]))
+	assert.Equal(t, "customerGroups", result.Facets[1].Field)
+	assert.Equal(t, wantGroups, facetCounts(result.Facets[1]))
+	for _, facet := range result.Facets {
+		assert.False(t, facet.Truncated)
+		for i := 1; i < len(facet.Buckets); i++ {
+			assert.GreaterOrEqual(t, facet.Buckets[i-1].Count, facet.Buckets[i].Count, "buckets are sorted by count")
+		}
+	}
+
+	aggregates := commands.Commands("aggregate")
+	assert.Len(t, aggregates, 1, "facets are counted by the search's aggregate")
+
+	// Facets count the filtered matches only
+	blocked := generated.UserStatusBlocked
+	where := &generated.CustomerQueryFilterInput{Status: &generated.CustomerStatusObjectFilterInput{
+		Activation: &generated.EnumFilterOfNullableOfUserStatusInput{Eq: &blocked},
+	}}
+	result, err = resolvers.NewResolver(dbClient).Query().CustomerSearch(ctx, where, nil, nil, nil, nil, nil, nil, nil,
+		[]generated.CustomerFacetField{generated.CustomerFacetFieldCustomerGroups})
+	require.NoError(t, err)
+	require.Len(t, result.Facets, 1)
+	assert.Equal(t, map[string]int64{"AIR_CUSTOMER": 1, "TEAM_SOUTH": 1}, facetCounts(result.Facets[0]))
+}
+
+// TestCustomerSearch_WithoutFacetsAddsNoBranches verifies a search without the facets argument
+// sends the same aggregate as before facets existed: a $facet of metadata and data only
+func TestCustomerSearch_WithoutFacetsAddsNoBranches(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+	ctx := context.Background()
+	commands := testutil.NewCommandRecorder()
+	dbClient := connectFacetTestDatabase(t, commands)
+	seedFacetCustomers(t, dbClient, []facetCustomer{{activation: "ACTIVE", groups: []string{"AIR_CUSTOMER"}}})
+	commands.Reset()
+
+	result, err := resolvers.NewResolver(dbClient).Query().CustomerSearch(ctx, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, result.Facets)
+
+	aggregates := commands.Commands("aggregate")
+	require.Len(t, aggregates, 1)
+	stages, err := aggregates[0].Body.Lookup("pipeline").Array().Values()
+	require.NoError(t, err)
+	branches, err := stages[len(stages)-1].Document().Lookup("$facet").Document().Elements()
+	require.NoError(t, err)
+	keys := make([]string, len(branches))
+	for i, branch := range branches {
+		keys[i] = branch.Key()
+	}
+	assert.ElementsMatch(t, []string{"metadata", "data"}, keys)
+}
+
+// connectFacetTestDatabase connects to the test database recording the commands sent and
+// empties the customers collection
+func connectFacetTestDatabase(t *testing.T, commands *testutil.CommandRecorder) *db.Client {
+	t.Helper()
+	ctx := context.Background()
+	dbClient, err := db.NewClient(&db.DBConfig{
+		URI:              "mongodb://localhost:27017",
+		Database:         "test_air_go",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      1,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}, zerolog.Nop())
+	require.NoError(t, err)
+	dbClient.SetCommandMonitor(commands.Monitor())
+	require.NoError(t, dbClient.Connect(ctx))
+	t.Cleanup(func() { teardownTestDatabase(t, dbClient) })
+
+	_, err = dbClient.Collection("customers").DeleteMany(ctx, bson.M{})
+	require.NoError(t, err)
+	return dbClient
+}
+
+// seedFacetCustomers inserts the customers with consecutive identifiers
+func seedFacetCustomers(t *testing.T, dbClient *db.Client, customers []facetCustomer) {
+	t.Helper()
+	documents := make([]interface{}, len(customers))
+	for i, customer := range customers {
+		status := bson.M{"deletion": "INIT"}
+		if customer.deleted {
+			status["deletion"] = "DELETED"
+		}
+		if customer.activation != "" {
+			status["activation"] = customer.activation
+		}
+		document := bson.M{
+			"identifier": fmt.Sprintf("fa000000-0000-4000-8000-%012d", i),
+			"createDate": testutil.NextCreateDate(),
+			"status":     status,
+		}
+		if customer.groups != nil {
+			document["customerGroups"] = customer.groups
+		}
+		documents[i] = document
+	}
+	_, err := dbClient.Collection("customers").InsertMany(context.Background(), documents)
+	require.NoError(t, err)
+}
+
+// facetCounts returns a facet's counts by value, counting entities without a value under ""
+func facetCounts(facet *generated.SearchFacet) map[string]int64 {
+	counts := make(map[string]int64, len(facet.Buckets))
+	for _, bucket := range facet.Buckets {
+		value := ""
+		if bucket.Value != nil {
+			value = *bucket.Value
+		}
+		counts[value] = bucket.Count
+	}
+	return counts
+}
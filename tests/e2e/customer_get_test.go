@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -205,7 +206,7 @@ func seedCustomer(t *testing.T, dbClient *db.Client, identifier, firstName, last
 		"identifier":  identifier,
 		"firstName":   firstName,
 		"lastName":    lastName,
-		"createDate":  time.Now().Format(time.RFC3339),
+		"createDate":  testutil.NextCreateDate(),
 		"status": bson.M{
 			"deletion": deletionStatus,
 		},
@@ -215,3 +216,43 @@ func seedCustomer(t *testing.T, dbClient *db.Client, identifier, firstName, last
 	_, err := collection.InsertOne(ctx, doc)
 	require.NoError(t, err)
 }
+
+// E2E test for customerGetConnection returning the customerSearch envelope with zero or one entries
+func TestCustomerGetConnection_Envelope(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	customerID := "550e8400-e29b-41d4-a716-446655440070"
+	deletedID := "550e8400-e29b-41d4-a716-446655440071"
+	seedCustomer(t, dbClient, customerID, "John", "Connection", "INIT")
+	seedCustomer(t, dbClient, deletedID, "Jane", "Connection", "DELETED")
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+
+	found, err := queryResolver.CustomerGetConnection(ctx, customerID)
+	require.NoError(t, err)
+	require.Len(t, found.Data, 1)
+	assert.Equal(t, customerID, found.Data[0].Identifier)
+	assert.Equal(t, int64(1), found.Count)
+	assert.Equal(t, int64(1), found.TotalCount)
+	assert.False(t, found.Paging.HasNextPage)
+	assert.False(t, found.Paging.HasPreviousPage)
+
+	for _, identifier := range []string{deletedID, "660e8400-e29b-41d4-a716-446655440000"} {
+		missing, err := queryResolver.CustomerGetConnection(ctx, identifier)
+		require.NoError(t, err)
+		assert.NotNil(t, missing.Data)
+		assert.Empty(t, missing.Data)
+		assert.Equal(t, int64(0), missing.Count)
+		assert.Equal(t, int64(0), missing.TotalCount)
+	}
+
+	// Errors of customerGet are passed through
+	_, err = queryResolver.CustomerGetConnection(ctx, "not-a-uuid")
+	require.Error(t, err)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/generated"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -44,7 +45,7 @@ func TestCustomerSearch_BasicFiltering_FirstName(t *testing.T) {
 
 	// Execute customerSearch query
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -93,7 +94,7 @@ func TestCustomerSearch_StatusFiltering_Activation(t *testing.T) {
 
 	// Execute customerSearch query
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -138,7 +139,7 @@ func TestCustomerSearch_EmptyResultSet(t *testing.T) {
 
 	// Execute customerSearch query
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -176,7 +177,7 @@ func TestCustomerSearch_EmptyFilter(t *testing.T) {
 
 	// Execute customerSearch query with no filter (nil)
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -214,7 +215,7 @@ func TestCustomerSearch_InvalidCursor(t *testing.T) {
 	// Execute customerSearch query with invalid cursor
 	first := int64(10)
 	invalidCursor := "not-a-valid-base64-cursor"
-	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, &invalidCursor, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, &invalidCursor, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.Error(t, err)
@@ -242,7 +243,7 @@ func TestCustomerSearch_ConflictingPaginationParams(t *testing.T) {
 	// Execute customerSearch query with both first and last
 	first := int64(10)
 	last := int64(5)
-	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, &last, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, &last, nil, nil, nil, nil)
 
 	// Assertions
 	require.Error(t, err)
@@ -251,6 +252,121 @@ func TestCustomerSearch_ConflictingPaginationParams(t *testing.T) {
 	assert.Contains(t, err.Error(), "last")
 }
 
+// E2E test for cursors combined with the page size of the other direction, and both cursors
+func TestCustomerSearch_MixedDirectionPaginationParams(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	seedCustomerForSearch(t, dbClient, "customer-051", "John", "Doe", "ACTIVE", "INIT")
+	seedCustomerForSearch(t, dbClient, "customer-052", "Jane", "Smith", "ACTIVE", "INIT")
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+
+	// Take a real cursor from the end of a first page
+	pageSize := int64(2)
+	page, err := queryResolver.CustomerSearch(ctx, nil, nil, &pageSize, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 2)
+	cursor := page.Paging.EndCursor
+	first, last := int64(1), int64(1)
+
+	tests := []struct {
+		name     string
+		first    *int64
+		after    *string
+		last     *int64
+		before   *string
+		expected string
+	}{
+		{name: "after with last", after: cursor, last: &last, expected: "'after' pages forward and cannot be combined with 'last'; use 'first'"},
+		{name: "before with first", first: &first, before: cursor, expected: "'before' pages backward and cannot be combined with 'first'; use 'last'"},
+		{name: "after and before", after: cursor, before: cursor, expected: "cannot specify both 'after' and 'before' cursors"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := queryResolver.CustomerSearch(ctx, nil, nil, tt.first, tt.after, tt.last, tt.before, nil, nil, nil)
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+
+	// A before cursor alone pages backward with the default page size instead of being ignored
+	result, err := queryResolver.CustomerSearch(ctx, nil, nil, nil, nil, nil, cursor, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, page.Data[0].Identifier, result.Data[0].Identifier)
+}
+
+// E2E test for a legacy customer without identifier among normal ones: it is returned with an
+// empty identifier, and paging forward and backward visits every customer once
+func TestCustomerSearch_LegacyDocumentWithoutIdentifier(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	seedCustomerForSearch(t, dbClient, "customer-071", "John", "Doe", "ACTIVE", "INIT")
+	seedCustomerForSearch(t, dbClient, "customer-072", "Jane", "Doe", "ACTIVE", "INIT")
+	seedCustomerForSearch(t, dbClient, "customer-073", "Jim", "Roe", "ACTIVE", "INIT")
+	_, err := dbClient.Collection("customers").InsertOne(ctx, bson.M{
+		"firstName": "Legacy",
+		"lastName":  "Doe",
+		"status":    bson.M{"activation": "ACTIVE", "deletion": "INIT"},
+	})
+	require.NoError(t, err)
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	asc := generated.SortEnumTypeAsc
+	order := []*generated.CustomerQuerySorterInput{{LastName: &asc}}
+
+	// The legacy customer ties on lastName and sorts before the identifiers
+	pageSize := int64(2)
+	var forward []string
+	var after *string
+	for {
+		result, err := queryResolver.CustomerSearch(ctx, nil, order, &pageSize, after, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		for _, customer := range result.Data {
+			forward = append(forward, *customer.FirstName)
+		}
+		if !result.Paging.HasNextPage {
+			break
+		}
+		after = result.Paging.EndCursor
+	}
+	assert.Equal(t, []string{"Legacy", "John", "Jane", "Jim"}, forward)
+
+	var backward []string
+	var before *string
+	for {
+		result, err := queryResolver.CustomerSearch(ctx, nil, order, nil, nil, &pageSize, before, nil, nil, nil)
+		require.NoError(t, err)
+		var page []string
+		for _, customer := range result.Data {
+			page = append(page, *customer.FirstName)
+			if *customer.FirstName == "Legacy" {
+				assert.Empty(t, customer.Identifier)
+			}
+		}
+		backward = append(page, backward...)
+		if !result.Paging.HasPreviousPage {
+			break
+		}
+		before = result.Paging.StartCursor
+	}
+	assert.Equal(t, forward, backward)
+}
+
 // T086: E2E test for null value filters (employeeEmail eq null finds entities with null)
 func TestCustomerSearch_NullValueFilter(t *testing.T) {
 	if testing.Short() {
@@ -279,7 +395,7 @@ func TestCustomerSearch_NullValueFilter(t *testing.T) {
 
 	// Execute customerSearch query
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -296,6 +412,51 @@ func TestCustomerSearch_NullValueFilter(t *testing.T) {
 	}
 }
 
+// E2E test for the openBanking existence filter: an empty openBanking object counts as a
+// connection, an absent or null one does not
+func TestCustomerSearch_OpenBankingExists(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	seedCustomerForSearch(t, dbClient, "customer-070", "John", "Absent", "ACTIVE", "INIT")
+	seedCustomerWithOpenBanking(t, dbClient, "customer-071", "Jane", nil)
+	seedCustomerWithOpenBanking(t, dbClient, "customer-072", "Bob", bson.M{})
+	seedCustomerWithOpenBanking(t, dbClient, "customer-073", "Alice", bson.M{"userId": "ob-1", "status": "ACTIVATED"})
+	seedCustomerWithOpenBanking(t, dbClient, "customer-074", "Carol", bson.M{"userId": "ob-2", "status": "DISABLED"})
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	first := int64(10)
+	search := func(openBanking *generated.CustomerOpenBankingObjectFilterInput) []string {
+		t.Helper()
+		filter := &generated.CustomerQueryFilterInput{OpenBanking: openBanking}
+		result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, result.Count, result.TotalCount)
+		identifiers := []string{}
+		for _, customer := range result.Data {
+			identifiers = append(identifiers, customer.Identifier)
+		}
+		return identifiers
+	}
+	exists, missing := true, false
+	activated := generated.OpenBankingStatusActivated
+
+	assert.ElementsMatch(t, []string{"customer-072", "customer-073", "customer-074"},
+		search(&generated.CustomerOpenBankingObjectFilterInput{Exists: &exists}))
+	assert.ElementsMatch(t, []string{"customer-070", "customer-071"},
+		search(&generated.CustomerOpenBankingObjectFilterInput{Exists: &missing}))
+	assert.ElementsMatch(t, []string{"customer-073"},
+		search(&generated.CustomerOpenBankingObjectFilterInput{
+			Exists: &exists,
+			Status: &generated.EnumFilterOfNullableOfOpenBankingStatusInput{Eq: &activated},
+		}))
+}
+
 // T087: E2E test for very large result set without pagination (applies 200 default limit)
 func TestCustomerSearch_DefaultLimitApplied(t *testing.T) {
 	if testing.Short() {
@@ -317,7 +478,7 @@ func TestCustomerSearch_DefaultLimitApplied(t *testing.T) {
 	queryResolver := resolver.Query()
 
 	// Execute customerSearch query without pagination params
-	result, err := queryResolver.CustomerSearch(ctx, nil, nil, nil, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -350,13 +511,13 @@ func TestCustomerSearch_CursorBeyondDataset(t *testing.T) {
 
 	// Get first page to obtain cursor
 	first := int64(10)
-	result1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	result1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.False(t, result1.Paging.HasNextPage) // No more pages
 
 	// Try to fetch next page with cursor (should return empty)
 	if result1.Paging.EndCursor != nil {
-		result2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, result1.Paging.EndCursor, nil, nil)
+		result2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, result1.Paging.EndCursor, nil, nil, nil, nil, nil)
 
 		// Assertions
 		require.NoError(t, err)
@@ -407,7 +568,7 @@ func TestCustomerSearch_ComplexAndOrFilter(t *testing.T) {
 
 	// Execute customerSearch
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -467,7 +628,7 @@ func TestCustomerSearch_DeeplyNestedFilters(t *testing.T) {
 
 	// Execute customerSearch
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -491,7 +652,7 @@ func seedCustomerForSearch(t *testing.T, dbClient *db.Client, identifier, firstN
 		"identifier":      identifier,
 		"firstName":       firstName,
 		"lastName":        lastName,
-		"createDate":      time.Now().Format(time.RFC3339),
+		"createDate":      testutil.NextCreateDate(),
 		"status": bson.M{
 			"activation": activationStatus,
 			"deletion":   deletionStatus,
@@ -513,7 +674,7 @@ func seedCustomerWithEmployeeEmail(t *testing.T, dbClient *db.Client, identifier
 		"identifier":      identifier,
 		"firstName":       firstName,
 		"lastName":        lastName,
-		"createDate":      time.Now().Format(time.RFC3339),
+		"createDate":      testutil.NextCreateDate(),
 		"status": bson.M{
 			"activation": activationStatus,
 			"deletion":   deletionStatus,
@@ -529,6 +690,16 @@ func seedCustomerWithEmployeeEmail(t *testing.T, dbClient *db.Client, identifier
 	require.NoError(t, err)
 }
 
+// Helper: Seed customer with an openBanking value (nil stores null; use seedCustomerForSearch for absent)
+func seedCustomerWithOpenBanking(t *testing.T, dbClient *db.Client, identifier, firstName string, openBanking interface{}) {
+	t.Helper()
+	seedCustomerForSearch(t, dbClient, identifier, firstName, "OpenBanking", "ACTIVE", "INIT")
+
+	_, err := dbClient.Collection("customers").UpdateOne(context.Background(),
+		bson.M{"identifier": identifier}, bson.M{"$set": bson.M{"openBanking": openBanking}})
+	require.NoError(t, err)
+}
+
 // T034: E2E test for customerSearch single-field sorting (createDate DESC)
 func TestCustomerSearch_Sorting_CreateDateDesc(t *testing.T) {
 	if testing.Short() {
@@ -557,7 +728,7 @@ func TestCustomerSearch_Sorting_CreateDateDesc(t *testing.T) {
 
 	// Execute customerSearch query
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, nil, sorter, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, sorter, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -599,7 +770,7 @@ func TestCustomerSearch_Sorting_NullHandling(t *testing.T) {
 
 	// Execute customerSearch query
 	first := int64(10)
-	result, err := queryResolver.CustomerSearch(ctx, nil, sorter, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, sorter, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -638,7 +809,7 @@ func TestCustomerSearch_Pagination_ForwardFirstPage(t *testing.T) {
 
 	// Execute customerSearch with first: 20
 	first := int64(20)
-	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -671,12 +842,12 @@ func TestCustomerSearch_Pagination_ForwardNextPage(t *testing.T) {
 
 	// Get first page
 	first := int64(20)
-	result1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	result1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result1.Paging.EndCursor)
 
 	// Get next page using endCursor from first page
-	result2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, result1.Paging.EndCursor, nil, nil)
+	result2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, result1.Paging.EndCursor, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -708,12 +879,12 @@ func TestCustomerSearch_Pagination_LastPage(t *testing.T) {
 
 	// Get first page (20 items)
 	first := int64(20)
-	result1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	result1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result1.Paging.EndCursor)
 
 	// Get last page (remaining 5 items)
-	result2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, result1.Paging.EndCursor, nil, nil)
+	result2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, result1.Paging.EndCursor, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -742,19 +913,19 @@ func TestCustomerSearch_Pagination_Bidirectional(t *testing.T) {
 
 	// Navigate forward: page 1
 	first := int64(10)
-	page1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	page1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	assert.Equal(t, int64(10), page1.Count)
 
 	// Navigate forward: page 2
-	page2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page1.Paging.EndCursor, nil, nil)
+	page2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page1.Paging.EndCursor, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	assert.Equal(t, int64(10), page2.Count)
 	assert.True(t, page2.Paging.HasPreviousPage)
 
 	// Navigate backward: back to page 1
 	last := int64(10)
-	pageBack, err := queryResolver.CustomerSearch(ctx, nil, nil, nil, nil, &last, page2.Paging.StartCursor)
+	pageBack, err := queryResolver.CustomerSearch(ctx, nil, nil, nil, nil, &last, page2.Paging.StartCursor, nil, nil, nil)
 	require.NoError(t, err)
 	assert.Equal(t, int64(10), pageBack.Count)
 
@@ -772,7 +943,7 @@ func seedCustomerWithCreateDate(t *testing.T, dbClient *db.Client, identifier, f
 		"identifier":      identifier,
 		"firstName":       firstName,
 		"lastName":        lastName,
-		"createDate":      createDate.Format(time.RFC3339),
+		"createDate":      createDate.UTC(),
 		"status": bson.M{
 			"activation": activationStatus,
 			"deletion":   deletionStatus,
@@ -810,7 +981,7 @@ func TestCustomerSearch_CountAndTotalCount_FullPage(t *testing.T) {
 
 	// Execute customerSearch query requesting first 20
 	first := int64(20)
-	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -842,7 +1013,7 @@ func TestCustomerSearch_CountAndTotalCount_NoFilters(t *testing.T) {
 
 	// Execute customerSearch query with no filter, requesting first 50
 	first := int64(50)
-	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -874,17 +1045,17 @@ func TestCustomerSearch_TotalCount_ConsistentAcrossPages(t *testing.T) {
 
 	// Get page 1
 	first := int64(50)
-	page1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	page1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, page1)
 
 	// Get page 2
-	page2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page1.Paging.EndCursor, nil, nil)
+	page2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page1.Paging.EndCursor, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, page2)
 
 	// Get page 3
-	page3, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page2.Paging.EndCursor, nil, nil)
+	page3, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page2.Paging.EndCursor, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, page3)
 
@@ -898,3 +1069,181 @@ func TestCustomerSearch_TotalCount_ConsistentAcrossPages(t *testing.T) {
 	assert.Equal(t, int64(50), page2.Count)
 	assert.Equal(t, int64(50), page3.Count) // Exactly 150 items, so page 3 has 50
 }
+
+// E2E test for deletions between pages: soft-deleting the cursor's customer and others sharing
+// its sort value neither repeats nor skips the remaining customers, and totalCountAtStart stays
+// at the first page's count while totalCount follows the deletions
+func TestCustomerSearch_Pagination_DeletionsBetweenPages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	for i := 1; i <= 9; i++ {
+		seedCustomerForSearch(t, dbClient, fmt.Sprintf("cust-deleted-%03d", i), fmt.Sprintf("First%d", i), "Shared", "ACTIVE", "INIT")
+	}
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	sortAsc := generated.SortEnumTypeAsc
+	order := []*generated.CustomerQuerySorterInput{{LastName: &sortAsc}}
+	first := int64(3)
+
+	page1, err := queryResolver.CustomerSearch(ctx, nil, order, &first, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cust-deleted-001", "cust-deleted-002", "cust-deleted-003"}, customerIdentifiers(page1))
+	assert.Equal(t, int64(9), page1.TotalCount)
+	require.NotNil(t, page1.TotalCountAtStart)
+	assert.Equal(t, int64(9), *page1.TotalCountAtStart)
+
+	// Delete the customer the cursor was taken from and the next one
+	softDeleteCustomers(t, dbClient, "cust-deleted-003", "cust-deleted-004")
+
+	page2, err := queryResolver.CustomerSearch(ctx, nil, order, &first, page1.Paging.EndCursor, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cust-deleted-005", "cust-deleted-006", "cust-deleted-007"}, customerIdentifiers(page2))
+	assert.Equal(t, int64(7), page2.TotalCount)
+	require.NotNil(t, page2.TotalCountAtStart)
+	assert.Equal(t, int64(9), *page2.TotalCountAtStart)
+
+	// Delete an already returned customer
+	softDeleteCustomers(t, dbClient, "cust-deleted-001")
+
+	page3, err := queryResolver.CustomerSearch(ctx, nil, order, &first, page2.Paging.EndCursor, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cust-deleted-008", "cust-deleted-009"}, customerIdentifiers(page3))
+	assert.False(t, page3.Paging.HasNextPage)
+	assert.Equal(t, int64(6), page3.TotalCount)
+	require.NotNil(t, page3.TotalCountAtStart)
+	assert.Equal(t, int64(9), *page3.TotalCountAtStart)
+
+	// Paging back from the last page skips the deleted customers as well
+	last := int64(10)
+	back, err := queryResolver.CustomerSearch(ctx, nil, order, nil, nil, &last, page3.Paging.StartCursor, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cust-deleted-002", "cust-deleted-005", "cust-deleted-006", "cust-deleted-007"}, customerIdentifiers(back))
+	require.NotNil(t, back.TotalCountAtStart)
+	assert.Equal(t, int64(9), *back.TotalCountAtStart)
+}
+
+// E2E test for insertions between pages: customers created behind the cursor are not returned,
+// those created ahead of it are, and no customer is returned twice
+func TestCustomerSearch_Pagination_InsertionsBetweenPages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	for i := 1; i <= 6; i++ {
+		seedCustomerForSearch(t, dbClient, fmt.Sprintf("cust-inserted-%03d", i), fmt.Sprintf("First%d", i), "Shared", "ACTIVE", "INIT")
+	}
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	sortAsc := generated.SortEnumTypeAsc
+	order := []*generated.CustomerQuerySorterInput{{LastName: &sortAsc}}
+	first := int64(3)
+
+	page1, err := queryResolver.CustomerSearch(ctx, nil, order, &first, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	returned := customerIdentifiers(page1)
+
+	// Create customers behind the cursor, with a lower and with the same sort value, and ahead of it
+	seedCustomerForSearch(t, dbClient, "cust-inserted-000", "Early", "Shared", "ACTIVE", "INIT")
+	seedCustomerForSearch(t, dbClient, "cust-inserted-099", "Before", "Aardvark", "ACTIVE", "INIT")
+	seedCustomerForSearch(t, dbClient, "cust-inserted-010", "Late", "Shared", "ACTIVE", "INIT")
+
+	cursor := page1.Paging.EndCursor
+	for page := 2; cursor != nil; page++ {
+		result, err := queryResolver.CustomerSearch(ctx, nil, order, &first, cursor, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), result.TotalCount, "page %d", page)
+		require.NotNil(t, result.TotalCountAtStart)
+		assert.Equal(t, int64(6), *result.TotalCountAtStart, "page %d", page)
+
+		returned = append(returned, customerIdentifiers(result)...)
+		cursor = nil
+		if result.Paging.HasNextPage {
+			cursor = result.Paging.EndCursor
+		}
+	}
+
+	assert.Equal(t, []string{
+		"cust-inserted-001", "cust-inserted-002", "cust-inserted-003",
+		"cust-inserted-004", "cust-inserted-005", "cust-inserted-006", "cust-inserted-010",
+	}, returned)
+}
+
+// Helper: Identifiers of a customer search page in page order
+func customerIdentifiers(result *generated.QueryOutputOfCustomer) []string {
+	identifiers := make([]string, 0, len(result.Data))
+	for _, customer := range result.Data {
+		identifiers = append(identifiers, customer.Identifier)
+	}
+	return identifiers
+}
+
+// Helper: Soft-delete customers by marking their deletion status
+func softDeleteCustomers(t *testing.T, dbClient *db.Client, identifiers ...string) {
+	t.Helper()
+	_, err := dbClient.Collection("customers").UpdateMany(context.Background(),
+		bson.M{"identifier": bson.M{"$in": identifiers}},
+		bson.M{"$set": bson.M{"status.deletion": "DELETED"}})
+	require.NoError(t, err)
+}
+
+// E2E test for the customer default sort: an unsorted search returns newest customers first
+// and its cursors page through the same order
+func TestCustomerSearch_DefaultSort_CreateDateDesc(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	now := time.Now()
+	seedCustomerWithCreateDate(t, dbClient, "00000000-0000-4000-8000-00000000d001", "Alice", "First", "ACTIVE", "INIT", now.Add(-4*24*time.Hour))
+	seedCustomerWithCreateDate(t, dbClient, "00000000-0000-4000-8000-00000000d002", "Bob", "Second", "ACTIVE", "INIT", now.Add(-1*24*time.Hour))
+	seedCustomerWithCreateDate(t, dbClient, "00000000-0000-4000-8000-00000000d003", "Carol", "Third", "ACTIVE", "INIT", now.Add(-3*24*time.Hour))
+	seedCustomerWithCreateDate(t, dbClient, "00000000-0000-4000-8000-00000000d004", "Dave", "Fourth", "ACTIVE", "INIT", now.Add(-2*24*time.Hour))
+
+	resolver := resolvers.NewResolver(dbClient)
+	queryResolver := resolver.Query()
+
+	// First page without an order
+	first := int64(2)
+	page1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, page1.Data, 2)
+	assert.Equal(t, "Bob", *page1.Data[0].FirstName)
+	assert.Equal(t, "Dave", *page1.Data[1].FirstName)
+	require.NotNil(t, page1.Paging.EndCursor)
+
+	// Next page continues in default order
+	page2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page1.Paging.EndCursor, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, page2.Data, 2)
+	assert.Equal(t, "Carol", *page2.Data[0].FirstName)
+	assert.Equal(t, "Alice", *page2.Data[1].FirstName)
+	assert.False(t, page2.Paging.HasNextPage)
+
+	// byKeys without an order uses the same default
+	byKeys, err := queryResolver.CustomerByKeysGet(ctx, []string{"00000000-0000-4000-8000-00000000d001", "00000000-0000-4000-8000-00000000d002", "00000000-0000-4000-8000-00000000d003"}, nil)
+	require.NoError(t, err)
+	require.Len(t, byKeys, 3)
+	assert.Equal(t, "Bob", *byKeys[0].FirstName)
+	assert.Equal(t, "Carol", *byKeys[1].FirstName)
+	assert.Equal(t, "Alice", *byKeys[2].FirstName)
+
+	// A cursor from the default order is rejected under an explicit order
+	sortAsc := generated.SortEnumTypeAsc
+	_, err = queryResolver.CustomerSearch(ctx, nil, []*generated.CustomerQuerySorterInput{{FirstName: &sortAsc}}, &first, page1.Paging.EndCursor, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different sort order")
+}
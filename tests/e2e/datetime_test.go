@@ -0,0 +1,134 @@
+package e2e
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/server"
+)
+
+// dateTimeEntity is an entity of a search selecting the timestamps
+type dateTimeEntity struct {
+	Identifier string  `json:"identifier"`
+	CreateDate *string `json:"createDate"`
+	BirthDate  *string `json:"birthDate"`
+}
+
+// dateTimeSearches is the data of a customerSearch or employeeSearch
+type dateTimeSearches struct {
+	CustomerSearch struct {
+		Data []dateTimeEntity `json:"data"`
+	} `json:"customerSearch"`
+	EmployeeSearch struct {
+		Data []dateTimeEntity `json:"data"`
+	} `json:"employeeSearch"`
+}
+
+// newDateTimeTestServer starts a server on the test database with no customers and employees
+func newDateTimeTestServer(t *testing.T) (*httptest.Server, *db.Client) {
+	t.Helper()
+
+	dbClient := setupTestDatabase(t)
+	t.Cleanup(func() { teardownTestDatabase(t, dbClient) })
+	for _, collection := range []string{"customers", "employees"} {
+		_, err := dbClient.Collection(collection).DeleteMany(context.Background(), bson.M{})
+		require.NoError(t, err)
+	}
+
+	cfg := &config.Config{
+		Port:                    8080,
+		LogFormat:               "json",
+		SchemaPath:              "../../schema.graphqls",
+		JWTSecret:               batchJWTSecret,
+		CORSOrigins:             []string{"*"},
+		GraphQLBatchMaxSize:     10,
+		GraphQLBatchParallelism: 1,
+	}
+
+	ts := httptest.NewServer(server.New(cfg, server.WithDatabaseClient(dbClient)))
+	t.Cleanup(ts.Close)
+	return ts, dbClient
+}
+
+// seedEntityWithDates inserts a customer or employee with the given stored createDate and birthDate values
+func seedEntityWithDates(t *testing.T, dbClient *db.Client, collection, identifier string, createDate, birthDate interface{}) {
+	t.Helper()
+
+	_, err := dbClient.Collection(collection).InsertOne(context.Background(), bson.M{
+		"identifier":      identifier,
+		"firstName":       identifier,
+		"createDate":      createDate,
+		"birthDate":       birthDate,
+		"status":          bson.M{"deletion": "INIT"},
+		"actionIndicator": "NONE",
+	})
+	require.NoError(t, err)
+}
+
+// TestDateTime_OutgoingNormalized tests stored timestamps with offsets, BSON dates and inconsistent
+// birthDates are all returned as RFC3339 UTC and YYYY-MM-DD
+func TestDateTime_OutgoingNormalized(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ts, dbClient := newDateTimeTestServer(t)
+	seedEntityWithDates(t, dbClient, "customers", "cust-date-1", "2024-03-01T10:00:00+02:00", "1985-06-15")
+	seedEntityWithDates(t, dbClient, "customers", "cust-date-2", time.Date(2024, 3, 2, 8, 30, 0, 0, time.UTC), "1985-06-15T23:30:00-02:00")
+
+	var result dateTimeSearches
+	errs := postRelationQuery(t, ts, `{ customerSearch(order: [{ firstName: ASC }]) { data { identifier createDate birthDate } } }`, &result)
+	require.Empty(t, errs)
+	data := result.CustomerSearch.Data
+	require.Len(t, data, 2)
+
+	assert.Equal(t, "2024-03-01T08:00:00Z", *data[0].CreateDate)
+	assert.Equal(t, "1985-06-15", *data[0].BirthDate)
+	assert.Equal(t, "2024-03-02T08:30:00Z", *data[1].CreateDate)
+	assert.Equal(t, "1985-06-16", *data[1].BirthDate)
+}
+
+// TestDateTime_FilterInputs tests offset and date-only filter values compare like their UTC instants,
+// and invalid values are rejected by the scalar
+func TestDateTime_FilterInputs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ts, dbClient := newDateTimeTestServer(t)
+	seedEntityWithDates(t, dbClient, "customers", "cust-date-1", time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC), nil)
+	seedEntityWithDates(t, dbClient, "customers", "cust-date-2", time.Date(2024, 3, 1, 0, 30, 0, 0, time.UTC), nil)
+	seedEntityWithDates(t, dbClient, "employees", "emp-date-1", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "1979-12-31")
+	seedEntityWithDates(t, dbClient, "employees", "emp-date-2", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "1980-01-01")
+
+	// 2024-03-01T01:00:00+01:00 is midnight UTC
+	var result dateTimeSearches
+	errs := postRelationQuery(t, ts, `{ customerSearch(where: { createDate: { gte: "2024-03-01T01:00:00+01:00" } }) { data { identifier } } }`, &result)
+	require.Empty(t, errs)
+	require.Len(t, result.CustomerSearch.Data, 1)
+	assert.Equal(t, "cust-date-2", result.CustomerSearch.Data[0].Identifier)
+
+	// A date-only value is midnight UTC
+	errs = postRelationQuery(t, ts, `{ customerSearch(where: { createDate: { lt: "2024-03-01" } }) { data { identifier } } }`, &result)
+	require.Empty(t, errs)
+	require.Len(t, result.CustomerSearch.Data, 1)
+	assert.Equal(t, "cust-date-1", result.CustomerSearch.Data[0].Identifier)
+
+	// birthDate compares the UTC date of a timestamp with an offset
+	errs = postRelationQuery(t, ts, `{ employeeSearch(where: { birthDate: { gte: "1979-12-31T23:00:00-02:00" } }) { data { identifier } } }`, &result)
+	require.Empty(t, errs)
+	require.Len(t, result.EmployeeSearch.Data, 1)
+	assert.Equal(t, "emp-date-2", result.EmployeeSearch.Data[0].Identifier)
+
+	errs = postRelationQuery(t, ts, `{ customerSearch(where: { createDate: { gte: "yesterday" } }) { data { identifier } } }`, &result)
+	require.NotEmpty(t, errs)
+	assert.Contains(t, errs[0].Message, `invalid DateTime "yesterday"`)
+}
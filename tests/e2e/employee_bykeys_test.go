@@ -49,6 +49,43 @@ func TestEmployeeByKeysGet_OrderByLastNameASC(t *testing.T) {
 	assert.Equal(t, "Zimmerman", *result[2].LastName)
 }
 
+// E2E test for employeeByKeysGet ordering by lastName ASC with firstName ASC breaking ties
+func TestEmployeeByKeysGet_OrderByLastNameThenFirstName(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	// Two employees share a lastName, so only the second sorter entry orders them
+	id1 := "500e8400-e29b-41d4-a716-446655440003"
+	id2 := "600e8400-e29b-41d4-a716-446655440004"
+	id3 := "700e8400-e29b-41d4-a716-446655440005"
+
+	seedEmployee(t, dbClient, id1, "Charlie", "Brown", "INIT")
+	seedEmployee(t, dbClient, id2, "Bob", "Zimmerman", "INIT")
+	seedEmployee(t, dbClient, id3, "Alice", "Brown", "INIT")
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+
+	ascSort := generated.SortEnumTypeAsc
+	order := []*generated.EmployeeQuerySorterInput{
+		{LastName: &ascSort},
+		{FirstName: &ascSort},
+	}
+
+	result, err := queryResolver.EmployeeByKeysGet(ctx, []string{id1, id2, id3}, order)
+
+	// Assertions - Alice Brown, Charlie Brown, Bob Zimmerman
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	assert.Equal(t, "Alice", *result[0].FirstName)
+	assert.Equal(t, "Charlie", *result[1].FirstName)
+	assert.Equal(t, "Bob", *result[2].FirstName)
+}
+
 // T048: E2E test for employeeByKeysGet deduplication
 func TestEmployeeByKeysGet_Deduplication(t *testing.T) {
 	if testing.Short() {
@@ -3,12 +3,12 @@ package e2e
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -74,7 +74,7 @@ func seedEmployee(t *testing.T, dbClient *db.Client, identifier, firstName, last
 		"identifier":  identifier,
 		"firstName":   firstName,
 		"lastName":    lastName,
-		"createDate":  time.Now().Format(time.RFC3339),
+		"createDate":  testutil.NextCreateDate(),
 		"status": bson.M{
 			"deletion": deletionStatus,
 		},
@@ -3,13 +3,13 @@ package e2e
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/generated"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -43,7 +43,7 @@ func TestEmployeeSearch_BasicFiltering_UserEmail(t *testing.T) {
 
 	// Execute employeeSearch query
 	first := int64(10)
-	result, err := queryResolver.EmployeeSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.EmployeeSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -90,7 +90,7 @@ func TestEmployeeSearch_SingleFieldSorting_LastNameASC(t *testing.T) {
 
 	// Execute employeeSearch query
 	first := int64(10)
-	result, err := queryResolver.EmployeeSearch(ctx, nil, sorter, &first, nil, nil, nil)
+	result, err := queryResolver.EmployeeSearch(ctx, nil, sorter, &first, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -128,7 +128,7 @@ func TestEmployeeSearch_BackwardPagination(t *testing.T) {
 
 	// Execute employeeSearch query with last: 10 (backward pagination)
 	last := int64(10)
-	result, err := queryResolver.EmployeeSearch(ctx, nil, nil, nil, nil, &last, nil)
+	result, err := queryResolver.EmployeeSearch(ctx, nil, nil, nil, nil, &last, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -138,6 +138,8 @@ func TestEmployeeSearch_BackwardPagination(t *testing.T) {
 	assert.Equal(t, int64(10), result.Count)
 	assert.Equal(t, int64(25), result.TotalCount)
 	assert.Len(t, result.Data, 10)
+	assert.Equal(t, "employee-020-P", result.Data[0].Identifier)
+	assert.Equal(t, "employee-020-Y", result.Data[9].Identifier)
 
 	// Should have previous page available
 	assert.True(t, result.Paging.HasPreviousPage)
@@ -169,7 +171,7 @@ func TestEmployeeSearch_CountWithPartialPage(t *testing.T) {
 
 	// Execute employeeSearch query requesting first 20 (but only 5 exist)
 	first := int64(20)
-	result, err := queryResolver.EmployeeSearch(ctx, nil, nil, &first, nil, nil, nil)
+	result, err := queryResolver.EmployeeSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -214,7 +216,7 @@ func TestEmployeeSearch_ComplexFilter_AndCombination(t *testing.T) {
 
 	// Execute employeeSearch
 	first := int64(10)
-	result, err := queryResolver.EmployeeSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.EmployeeSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -224,6 +226,78 @@ func TestEmployeeSearch_ComplexFilter_AndCombination(t *testing.T) {
 	assert.Equal(t, "Smith", *result.Data[0].LastName)
 }
 
+// E2E test for a birthDate range: employees without a birthDate are excluded, and pages sorted
+// by birthDate continue from their cursors
+func TestEmployeeSearch_BirthDateRange(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	seedEmployeeWithBirthDate(t, dbClient, "emp-birth-1", "Before", "1979-12-31")
+	seedEmployeeWithBirthDate(t, dbClient, "emp-birth-2", "First", "1980-01-01")
+	seedEmployeeWithBirthDate(t, dbClient, "emp-birth-3", "Middle", "1985-06-15")
+	seedEmployeeWithBirthDate(t, dbClient, "emp-birth-4", "Last", "1989-12-31")
+	seedEmployeeWithBirthDate(t, dbClient, "emp-birth-5", "After", "1990-01-01")
+	seedEmployeeWithBirthDate(t, dbClient, "emp-birth-6", "Unknown", "") // null birthDate
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+
+	// birthDate >= 1980-01-01 AND birthDate < 1990-01-01, given as a date and a date time
+	from, until := "1980-01-01", "1990-01-01T00:00:00Z"
+	filter := &generated.EmployeeQueryFilterInput{
+		BirthDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Gte: &from, Lt: &until},
+	}
+	sortAsc := generated.SortEnumTypeAsc
+	sorter := []*generated.EmployeeQuerySorterInput{{BirthDate: &sortAsc}}
+
+	first := int64(2)
+	result, err := queryResolver.EmployeeSearch(ctx, filter, sorter, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.TotalCount)
+	require.Len(t, result.Data, 2)
+	assert.Equal(t, "1980-01-01", *result.Data[0].BirthDate)
+	assert.Equal(t, "1985-06-15", *result.Data[1].BirthDate)
+	assert.True(t, result.Paging.HasNextPage)
+
+	next, err := queryResolver.EmployeeSearch(ctx, filter, sorter, &first, result.Paging.EndCursor, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, next.Data, 1)
+	assert.Equal(t, "1989-12-31", *next.Data[0].BirthDate)
+	assert.False(t, next.Paging.HasNextPage)
+
+	// eq "" selects the employees without a birthDate
+	empty := ""
+	filter = &generated.EmployeeQueryFilterInput{BirthDate: &generated.ComparableFilterOfNullableOfDateTimeInput{Eq: &empty}}
+	result, err = queryResolver.EmployeeSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, "emp-birth-6", result.Data[0].Identifier)
+}
+
+// Helper: Seed employee with birthDate (stored as YYYY-MM-DD; empty leaves it unset)
+func seedEmployeeWithBirthDate(t *testing.T, dbClient *db.Client, identifier, firstName, birthDate string) {
+	t.Helper()
+
+	doc := bson.M{
+		"identifier": identifier,
+		"firstName":  firstName,
+		"createDate": testutil.NextCreateDate(),
+		"status": bson.M{
+			"deletion": "INIT",
+		},
+	}
+	if birthDate != "" {
+		doc["birthDate"] = birthDate
+	}
+
+	_, err := dbClient.Collection("employees").InsertOne(context.Background(), doc)
+	require.NoError(t, err)
+}
+
 // Helper: Seed employee for search tests
 func seedEmployeeForSearch(t *testing.T, dbClient *db.Client, identifier, firstName, lastName, userEmail, deletionStatus string) {
 	t.Helper()
@@ -235,7 +309,7 @@ func seedEmployeeForSearch(t *testing.T, dbClient *db.Client, identifier, firstN
 		"firstName":       firstName,
 		"lastName":        lastName,
 		"userEmail":       userEmail,
-		"createDate":      time.Now().Format(time.RFC3339),
+		"createDate":      testutil.NextCreateDate(),
 		"status": bson.M{
 			"deletion": deletionStatus,
 		},
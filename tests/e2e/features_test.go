@@ -0,0 +1,127 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/server"
+)
+
+// newFeaturesTestServer starts a server with the referencePortfolio entity disabled. Its database
+// client is never connected; disabled fields and introspection are answered without it
+func newFeaturesTestServer(t *testing.T, hide bool) *httptest.Server {
+	t.Helper()
+
+	schema := generated.NewExecutableSchema(generated.Config{}).Schema()
+	require.NoError(t, resolvers.SetDisabledFeatures([]string{"referencePortfolio"}, nil, schema))
+	t.Cleanup(func() { _ = resolvers.SetDisabledFeatures(nil, nil, schema) })
+
+	dbClient, err := db.NewClient(&db.DBConfig{
+		URI:              "mongodb://localhost:27017",
+		Database:         "features_test_db",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}, zerolog.Nop())
+	require.NoError(t, err)
+	t.Cleanup(dbClient.Close)
+
+	cfg := &config.Config{
+		Port:                 8080,
+		LogFormat:            "json",
+		SchemaPath:           "../../schema.graphqls",
+		JWTSecret:            batchJWTSecret,
+		CORSOrigins:          []string{"*"},
+		HideDisabledFeatures: hide,
+	}
+
+	ts := httptest.NewServer(server.New(cfg, server.WithDatabaseClient(dbClient)))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// queryTypeFields introspects the names of the Query fields
+func queryTypeFields(t *testing.T, ts *httptest.Server) map[string]bool {
+	t.Helper()
+	resp := postBatch(t, ts, map[string]interface{}{"query": `{ __schema { queryType { fields { name } } } }`})
+
+	var body struct {
+		Data struct {
+			Schema struct {
+				QueryType struct {
+					Fields []struct{ Name string } `json:"fields"`
+				} `json:"queryType"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	names := make(map[string]bool)
+	for _, field := range body.Data.Schema.QueryType.Fields {
+		names[field.Name] = true
+	}
+	require.NotEmpty(t, names)
+	return names
+}
+
+// TestDisabledEntity_ReturnsFeatureDisabled verifies calls to a disabled entity's queries fail
+// with FEATURE_DISABLED while introspection keeps listing them by default
+func TestDisabledEntity_ReturnsFeatureDisabled(t *testing.T) {
+	ts := newFeaturesTestServer(t, false)
+
+	resp := postBatch(t, ts, map[string]interface{}{
+		"query": `{ referencePortfolioSearch(first: 1) { count } }`,
+	})
+	var body struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message    string                 `json:"message"`
+			Path       []interface{}          `json:"path"`
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "FEATURE_DISABLED", body.Errors[0].Extensions["code"])
+	assert.Equal(t, []interface{}{"referencePortfolioSearch"}, body.Errors[0].Path)
+
+	fields := queryTypeFields(t, ts)
+	assert.True(t, fields["referencePortfolioSearch"])
+	assert.True(t, fields["customerSearch"])
+}
+
+// TestDisabledEntity_HiddenFromIntrospection verifies the hide flag omits the disabled fields
+// from introspection while calls still fail with FEATURE_DISABLED
+func TestDisabledEntity_HiddenFromIntrospection(t *testing.T) {
+	ts := newFeaturesTestServer(t, true)
+
+	fields := queryTypeFields(t, ts)
+	assert.False(t, fields["referencePortfolioSearch"])
+	assert.False(t, fields["referencePortfoliosForCustomerGet"])
+	assert.True(t, fields["customerSearch"])
+
+	resp := postBatch(t, ts, map[string]interface{}{"query": `{ referencePortfolioGet(identifier: "7c000000-0000-4000-8000-000000000001") { identifier } }`})
+	var body struct {
+		Errors []struct {
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "FEATURE_DISABLED", body.Errors[0].Extensions["code"])
+}
@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/server"
+)
+
+const batchJWTSecret = "test-secret-key-at-least-32-characters-long"
+
+// newBatchTestServer starts a server whose database client is never connected; the batched
+// operations are answered or rejected before any database access
+func newBatchTestServer(t *testing.T, maxSize int) *httptest.Server {
+	t.Helper()
+
+	dbClient, err := db.NewClient(&db.DBConfig{
+		URI:              "mongodb://localhost:27017",
+		Database:         "batch_test_db",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}, zerolog.Nop())
+	require.NoError(t, err)
+	t.Cleanup(dbClient.Close)
+
+	cfg := &config.Config{
+		Port:                    8080,
+		LogFormat:               "json",
+		SchemaPath:              "../../schema.graphqls",
+		JWTSecret:               batchJWTSecret,
+		CORSOrigins:             []string{"*"},
+		GraphQLBatchMaxSize:     maxSize,
+		GraphQLBatchParallelism: 2,
+	}
+
+	ts := httptest.NewServer(server.New(cfg, server.WithDatabaseClient(dbClient)))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// postBatch posts a raw GraphQL body with an admin token
+func postBatch(t *testing.T, ts *httptest.Server, body interface{}) *http.Response {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "batch-admin",
+		"roles": []string{"ADMIN"},
+	}).SignedString([]byte(batchJWTSecret))
+	require.NoError(t, err)
+
+	jsonBody, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/graphql", bytes.NewReader(jsonBody))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// TestGraphQLBatch_MixedOperations posts a valid query, an invalid query and one over the sort limit
+// and expects one response per operation in request order
+func TestGraphQLBatch_MixedOperations(t *testing.T) {
+	ts := newBatchTestServer(t, 5)
+
+	resp := postBatch(t, ts, []map[string]interface{}{
+		{"query": "query { alive }"},
+		{"query": "query { notAField }"},
+		{"query": `query {
+			customerSearch(order: [
+				{ firstName: ASC }, { lastName: ASC }, { birthDate: ASC },
+				{ userEmail: ASC }, { isShared: ASC }, { createDate: ASC }
+			]) { totalCount }
+		}`},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var results []GraphQLResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+	require.Len(t, results, 3)
+
+	// Valid query
+	assert.Empty(t, results[0].Errors)
+	assert.Equal(t, map[string]interface{}{"alive": true}, results[0].Data)
+
+	// Schema validation failure
+	require.NotEmpty(t, results[1].Errors)
+	assert.Nil(t, results[1].Data)
+	assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", results[1].Errors[0].Extensions["code"])
+	assert.Contains(t, results[1].Errors[0].Message, "notAField")
+
+	// Over the order entry limit, rejected by the resolver
+	require.NotEmpty(t, results[2].Errors)
+	assert.Equal(t, "order accepts at most 5 entries, got 6", results[2].Errors[0].Message)
+	assert.Equal(t, []interface{}{"customerSearch"}, results[2].Errors[0].Path)
+}
+
+// TestGraphQLBatch_TooLarge verifies a batch over the maximum size is rejected as a whole
+func TestGraphQLBatch_TooLarge(t *testing.T) {
+	ts := newBatchTestServer(t, 2)
+
+	resp := postBatch(t, ts, []map[string]interface{}{
+		{"query": "query { alive }"},
+		{"query": "query { alive }"},
+		{"query": "query { alive }"},
+	})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var result GraphQLResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "BATCH_TOO_LARGE", result.Errors[0].Extensions["code"])
+	assert.Equal(t, "batch of 3 operations exceeds the maximum of 2", result.Errors[0].Message)
+}
+
+// TestGraphQLBatch_EmptyBatch verifies an empty array is rejected
+func TestGraphQLBatch_EmptyBatch(t *testing.T) {
+	ts := newBatchTestServer(t, 2)
+
+	resp := postBatch(t, ts, []map[string]interface{}{})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var result GraphQLResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "INVALID_BATCH", result.Errors[0].Extensions["code"])
+}
+
+// TestGraphQLBatch_SingleOperationUnchanged verifies a plain object body is still served as one operation
+func TestGraphQLBatch_SingleOperationUnchanged(t *testing.T) {
+	ts := newBatchTestServer(t, 2)
+
+	resp := postBatch(t, ts, map[string]interface{}{"query": "query { alive }"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result GraphQLResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{"alive": true}, result.Data)
+}
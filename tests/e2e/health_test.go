@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
 	"github.com/yourusername/air-go/internal/server"
 )
 
@@ -57,6 +58,40 @@ func TestHealthCheckEndpoint(t *testing.T) {
 	assert.NoError(t, err, "Timestamp should be in RFC3339 format")
 }
 
+// TestHealthCheckSearchConcurrency verifies the health response reports the search concurrency limiter
+func TestHealthCheckSearchConcurrency(t *testing.T) {
+	cfg := &config.Config{
+		Port:        8080,
+		LogFormat:   "json",
+		SchemaPath:  "../../schema.graphqls",
+		JWTSecret:   "test-secret-key-at-least-32-characters-long",
+		CORSOrigins: []string{"*"},
+	}
+
+	ts := httptest.NewServer(server.New(cfg))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var healthResponse struct {
+		Search *struct {
+			InUse    int64 `json:"in_use"`
+			Max      int64 `json:"max"`
+			Waiting  int64 `json:"waiting"`
+			Rejected int64 `json:"rejected"`
+		} `json:"search"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&healthResponse))
+
+	require.NotNil(t, healthResponse.Search)
+	assert.Equal(t, int64(resolvers.DefaultSearchMaxConcurrentRows), healthResponse.Search.Max)
+	assert.Equal(t, int64(0), healthResponse.Search.InUse)
+	assert.Equal(t, int64(0), healthResponse.Search.Waiting)
+}
+
 // TestHealthCheckPerformance verifies that the health check responds in <100ms for 99% of requests
 func TestHealthCheckPerformance(t *testing.T) {
 	// Create test server
@@ -158,3 +193,37 @@ func TestHealthCheckWithoutAuthentication(t *testing.T) {
 	// Assert response contains status "ok"
 	assert.Equal(t, "ok", healthResponse.Status)
 }
+
+// TestReadyEndpoint verifies a server without a database client is ready once its schema is loaded
+func TestReadyEndpoint(t *testing.T) {
+	cfg := &config.Config{
+		Port:        8080,
+		LogFormat:   "json",
+		SchemaPath:  "../../schema.graphqls",
+		JWTSecret:   "test-secret-key-at-least-32-characters-long",
+		CORSOrigins: []string{"*"},
+	}
+
+	srv := server.New(cfg)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/ready")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var report server.ReadinessReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, server.ReadinessReady, report.State)
+	assert.Empty(t, report.Reasons)
+
+	// Draining takes the server out of rotation
+	srv.Readiness().BeginShutdown()
+
+	resp2, err := http.Get(ts.URL + "/ready")
+	require.NoError(t, err)
+	defer resp2.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp2.StatusCode)
+}
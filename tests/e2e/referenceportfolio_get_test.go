@@ -3,12 +3,12 @@ package e2e
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -70,7 +70,7 @@ func seedReferencePortfolio(t *testing.T, dbClient *db.Client, identifier, actio
 
 	doc := bson.M{
 		"identifier":      identifier,
-		"createDate":      time.Now().Format(time.RFC3339),
+		"createDate":      testutil.NextCreateDate(),
 		"actionIndicator": actionIndicator,
 		"isConsistent":    true,
 		"isComplete":      true,
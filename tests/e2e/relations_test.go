@@ -0,0 +1,228 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+const (
+	relationEmployeeA       = "7a000000-0000-4000-8000-000000000001"
+	relationEmployeeB       = "7a000000-0000-4000-8000-000000000002"
+	relationEmployeeDeleted = "7a000000-0000-4000-8000-000000000003"
+	relationTeamAlpha       = "7b000000-0000-4000-8000-000000000001"
+	relationTeamBeta        = "7b000000-0000-4000-8000-000000000002"
+	relationTeamDeleted     = "7b000000-0000-4000-8000-000000000003"
+)
+
+// takeQueryCount returns the number of read commands (aggregate and find) recorded since the last call
+func takeQueryCount(recorder *testutil.CommandRecorder) int {
+	count := recorder.CommandCount("aggregate") + recorder.CommandCount("find")
+	recorder.Reset()
+	return count
+}
+
+// newRelationTestServer starts a server on a monitored database client holding a small
+// team/employee graph: Alpha lists A, B and a deleted employee, Beta lists B, and a deleted team lists A
+func newRelationTestServer(t *testing.T, counter *testutil.CommandRecorder) *httptest.Server {
+	t.Helper()
+	ctx := context.Background()
+
+	dbClient, err := db.NewClient(&db.DBConfig{
+		URI:              "mongodb://localhost:27017",
+		Database:         "test_air_go",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}, zerolog.Nop())
+	require.NoError(t, err)
+	dbClient.SetCommandMonitor(counter.Monitor())
+	require.NoError(t, dbClient.Connect(ctx))
+	t.Cleanup(func() { teardownTestDatabase(t, dbClient) })
+
+	for _, collection := range []string{"employees", "teams"} {
+		_, err := dbClient.Collection(collection).DeleteMany(ctx, bson.M{})
+		require.NoError(t, err)
+	}
+
+	seedEmployee(t, dbClient, relationEmployeeA, "Ada", "Lovelace", "INIT")
+	seedEmployee(t, dbClient, relationEmployeeB, "Bob", "Builder", "INIT")
+	seedEmployee(t, dbClient, relationEmployeeDeleted, "Dan", "Deleted", "DELETED")
+
+	seedRelationTeam(t, dbClient, relationTeamAlpha, "Alpha", "INIT", relationEmployeeA, relationEmployeeB, relationEmployeeDeleted)
+	seedRelationTeam(t, dbClient, relationTeamBeta, "Beta", "INIT", relationEmployeeB)
+	seedRelationTeam(t, dbClient, relationTeamDeleted, "Gamma", "DELETED", relationEmployeeA)
+
+	cfg := &config.Config{
+		Port:                    8080,
+		LogFormat:               "json",
+		SchemaPath:              "../../schema.graphqls",
+		JWTSecret:               batchJWTSecret,
+		CORSOrigins:             []string{"*"},
+		GraphQLBatchMaxSize:     10,
+		GraphQLBatchParallelism: 1,
+	}
+
+	ts := httptest.NewServer(server.New(cfg, server.WithDatabaseClient(dbClient)))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// seedRelationTeam inserts a team listing the given employees in teamMembers
+func seedRelationTeam(t *testing.T, dbClient *db.Client, identifier, name, deletionStatus string, members ...string) {
+	t.Helper()
+
+	_, err := dbClient.Collection("teams").InsertOne(context.Background(), bson.M{
+		"identifier": identifier,
+		"name":       name,
+		"teamMembers": bson.M{
+			"nodeType": "Employee",
+			"keys":     members,
+		},
+		"createDate":      testutil.NextCreateDate(),
+		"status":          bson.M{"deletion": deletionStatus},
+		"actionIndicator": "NONE",
+	})
+	require.NoError(t, err)
+}
+
+// postRelationQuery posts one query and decodes the response
+func postRelationQuery(t *testing.T, ts *httptest.Server, query string, result interface{}) []GraphQLError {
+	t.Helper()
+
+	resp := postBatch(t, ts, map[string]interface{}{"query": query})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []GraphQLError  `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	if len(body.Errors) == 0 {
+		require.NoError(t, json.Unmarshal(body.Data, result))
+	}
+	return body.Errors
+}
+
+// relatedIdentifiers maps each entity to the identifiers of its related entities
+func relatedIdentifiers(entries []struct {
+	Identifier string `json:"identifier"`
+	Related    []struct {
+		Identifier string `json:"identifier"`
+	} `json:"related"`
+}) map[string][]string {
+	related := map[string][]string{}
+	for _, entry := range entries {
+		related[entry.Identifier] = []string{}
+		for _, item := range entry.Related {
+			related[entry.Identifier] = append(related[entry.Identifier], item.Identifier)
+		}
+	}
+	return related
+}
+
+// TestRelations_TeamMembers verifies Team.members resolves every team of a page with one batched query
+func TestRelations_TeamMembers(t *testing.T) {
+	counter := testutil.NewCommandRecorder()
+	ts := newRelationTestServer(t, counter)
+	counter.Reset()
+
+	var result struct {
+		TeamSearch struct {
+			Data []struct {
+				Identifier string `json:"identifier"`
+				Related    []struct {
+					Identifier string `json:"identifier"`
+				} `json:"related"`
+			} `json:"data"`
+		} `json:"teamSearch"`
+	}
+	errs := postRelationQuery(t, ts, `{ teamSearch { data { identifier related: members { identifier } } } }`, &result)
+	require.Empty(t, errs)
+
+	assert.Equal(t, map[string][]string{
+		relationTeamAlpha: {relationEmployeeA, relationEmployeeB},
+		relationTeamBeta:  {relationEmployeeB},
+	}, relatedIdentifiers(result.TeamSearch.Data))
+	assert.Equal(t, 2, takeQueryCount(counter), "one search and one batched members query")
+}
+
+// TestRelations_EmployeeTeams verifies Employee.teams resolves every employee of a page with one batched query
+func TestRelations_EmployeeTeams(t *testing.T) {
+	counter := testutil.NewCommandRecorder()
+	ts := newRelationTestServer(t, counter)
+	counter.Reset()
+
+	var result struct {
+		EmployeeSearch struct {
+			Data []struct {
+				Identifier string `json:"identifier"`
+				Related    []struct {
+					Identifier string `json:"identifier"`
+				} `json:"related"`
+			} `json:"data"`
+		} `json:"employeeSearch"`
+	}
+	errs := postRelationQuery(t, ts, `{ employeeSearch { data { identifier related: teams { identifier } } } }`, &result)
+	require.Empty(t, errs)
+
+	assert.Equal(t, map[string][]string{
+		relationEmployeeA: {relationTeamAlpha},
+		relationEmployeeB: {relationTeamAlpha, relationTeamBeta},
+	}, relatedIdentifiers(result.EmployeeSearch.Data))
+	assert.Equal(t, 2, takeQueryCount(counter), "one search and one batched teams query")
+}
+
+// TestRelations_First verifies the first argument truncates a relation and is capped
+func TestRelations_First(t *testing.T) {
+	counter := testutil.NewCommandRecorder()
+	ts := newRelationTestServer(t, counter)
+
+	var result struct {
+		TeamGet struct {
+			Members []struct {
+				Identifier string `json:"identifier"`
+			} `json:"members"`
+		} `json:"teamGet"`
+	}
+	errs := postRelationQuery(t, ts, `{ teamGet(identifier: "`+relationTeamAlpha+`") { members(first: 1) { identifier } } }`, &result)
+	require.Empty(t, errs)
+	require.Len(t, result.TeamGet.Members, 1)
+	assert.Equal(t, relationEmployeeA, result.TeamGet.Members[0].Identifier)
+
+	errs = postRelationQuery(t, ts, `{ teamGet(identifier: "`+relationTeamAlpha+`") { members(first: 101) { identifier } } }`, &result)
+	require.NotEmpty(t, errs)
+	assert.Contains(t, errs[0].Message, "exceeds maximum relation size")
+}
+
+// TestRelations_CircularSelectionRejected verifies the complexity limit rejects deep circular
+// selections before any query runs
+func TestRelations_CircularSelectionRejected(t *testing.T) {
+	counter := testutil.NewCommandRecorder()
+	ts := newRelationTestServer(t, counter)
+	counter.Reset()
+
+	var result map[string]interface{}
+	errs := postRelationQuery(t, ts, `{ teamSearch { data { members { teams { members { identifier } } } } } }`, &result)
+	require.NotEmpty(t, errs)
+	assert.Contains(t, errs[0].Message, "exceeds the limit")
+	assert.Equal(t, 0, takeQueryCount(counter))
+}
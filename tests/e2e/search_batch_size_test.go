@@ -0,0 +1,70 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestSearch_StandardPageSingleRoundTrip verifies a 200-entry page is read by one aggregate
+// whose batch covers the page and its read-ahead document, with no getMore
+func TestSearch_StandardPageSingleRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+	ctx := context.Background()
+
+	commands := testutil.NewCommandRecorder()
+	dbClient, err := db.NewClient(&db.DBConfig{
+		URI:              "mongodb://localhost:27017",
+		Database:         "test_air_go",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}, zerolog.Nop())
+	require.NoError(t, err)
+	dbClient.SetCommandMonitor(commands.Monitor())
+	require.NoError(t, dbClient.Connect(ctx))
+	t.Cleanup(func() { teardownTestDatabase(t, dbClient) })
+
+	_, err = dbClient.Collection("employees").DeleteMany(ctx, bson.M{})
+	require.NoError(t, err)
+	documents := make([]interface{}, 250)
+	for i := range documents {
+		documents[i] = bson.M{
+			"identifier": fmt.Sprintf("7e000000-0000-4000-8000-%012d", i),
+			"firstName":  fmt.Sprintf("Batch%03d", i),
+			"createDate": testutil.NextCreateDate(),
+			"status":     bson.M{"deletion": "INIT"},
+		}
+	}
+	_, err = dbClient.Collection("employees").InsertMany(ctx, documents)
+	require.NoError(t, err)
+	commands.Reset()
+
+	first := int64(200)
+	result, err := resolvers.NewResolver(dbClient).Query().EmployeeSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 200)
+	assert.True(t, result.Paging.HasNextPage)
+
+	aggregates := commands.Commands("aggregate")
+	require.Len(t, aggregates, 1)
+	assert.Zero(t, commands.CommandCount("getMore"))
+	assert.Equal(t, int32(201), aggregates[0].Body.Lookup("cursor", "batchSize").Int32())
+}
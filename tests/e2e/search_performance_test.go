@@ -52,7 +52,7 @@ func TestSearchPerformance_10KEntities(t *testing.T) {
 		first := int64(200) // Default max batch size
 
 		start := time.Now()
-		result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+		result, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 		duration := time.Since(start)
 
 		require.NoError(t, err)
@@ -74,7 +74,7 @@ func TestSearchPerformance_10KEntities(t *testing.T) {
 		first := int64(200)
 
 		start := time.Now()
-		result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+		result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
 		duration := time.Since(start)
 
 		require.NoError(t, err)
@@ -94,7 +94,7 @@ func TestSearchPerformance_10KEntities(t *testing.T) {
 		first := int64(100)
 
 		start := time.Now()
-		result, err := queryResolver.CustomerSearch(ctx, nil, sorter, &first, nil, nil, nil)
+		result, err := queryResolver.CustomerSearch(ctx, nil, sorter, &first, nil, nil, nil, nil, nil, nil)
 		duration := time.Since(start)
 
 		require.NoError(t, err)
@@ -109,13 +109,13 @@ func TestSearchPerformance_10KEntities(t *testing.T) {
 	t.Run("PaginationSecondPage", func(t *testing.T) {
 		// Get first page
 		first := int64(100)
-		page1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+		page1, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		require.NotNil(t, page1.Paging.EndCursor)
 
 		// Get second page
 		start := time.Now()
-		page2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page1.Paging.EndCursor, nil, nil)
+		page2, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, page1.Paging.EndCursor, nil, nil, nil, nil, nil)
 		duration := time.Since(start)
 
 		require.NoError(t, err)
@@ -138,7 +138,7 @@ func TestSearchPerformance_10KEntities(t *testing.T) {
 		first := int64(200)
 
 		start := time.Now()
-		result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+		result, err := queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
 		duration := time.Since(start)
 
 		require.NoError(t, err)
@@ -175,7 +175,7 @@ func BenchmarkCustomerSearch_NoFilter(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+		_, _ = queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 	}
 }
 
@@ -200,6 +200,33 @@ func BenchmarkCustomerSearch_WithFilter(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil)
+		_, _ = queryResolver.CustomerSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil, nil)
+	}
+}
+
+// BenchmarkCustomerSearch_BatchSize compares a 200-entry page read with the driver's default
+// 101-document first batch against a batch size covering the page
+func BenchmarkCustomerSearch_BatchSize(b *testing.B) {
+	ctx := context.Background()
+	dbClient := setupTestDatabase(&testing.T{})
+
+	// Seed a moderate dataset
+	for i := 0; i < 1000; i++ {
+		identifier := fmt.Sprintf("bench-%04d-0000-0000-0000-000000000000", i+1)
+		seedCustomerForSearch(&testing.T{}, dbClient, identifier, "First", "Last", "ACTIVE", "INIT")
+	}
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	first := int64(200)
+	defer resolvers.SetMaxAggregateBatchSize(resolvers.DefaultMaxAggregateBatchSize)
+
+	for _, bound := range []int{101, resolvers.DefaultMaxAggregateBatchSize} {
+		b.Run(fmt.Sprintf("max_batch_%d", bound), func(b *testing.B) {
+			resolvers.SetMaxAggregateBatchSize(bound)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
+			}
+		})
 	}
 }
@@ -22,10 +22,10 @@ func TestSearchWithGetByKeys_Integration(t *testing.T) {
 	defer teardownTestDatabase(t, dbClient)
 
 	// Seed test customers
-	seedCustomerForSearch(t, dbClient, "00000000-0000-0000-0000-000000000001", "Alice", "Anderson", "ACTIVE", "INIT")
-	seedCustomerForSearch(t, dbClient, "00000000-0000-0000-0000-000000000002", "Amy", "Brown", "ACTIVE", "INIT")
-	seedCustomerForSearch(t, dbClient, "00000000-0000-0000-0000-000000000003", "Carol", "Carter", "BLOCKED", "INIT")
-	seedCustomerForSearch(t, dbClient, "00000000-0000-0000-0000-000000000004", "David", "Davis", "ACTIVE", "INIT")
+	seedCustomerForSearch(t, dbClient, "00000000-0000-4000-8000-000000000001", "Alice", "Anderson", "ACTIVE", "INIT")
+	seedCustomerForSearch(t, dbClient, "00000000-0000-4000-8000-000000000002", "Amy", "Brown", "ACTIVE", "INIT")
+	seedCustomerForSearch(t, dbClient, "00000000-0000-4000-8000-000000000003", "Carol", "Carter", "BLOCKED", "INIT")
+	seedCustomerForSearch(t, dbClient, "00000000-0000-4000-8000-000000000004", "David", "Davis", "ACTIVE", "INIT")
 
 	// Create resolver
 	resolver := resolvers.NewResolver(dbClient)
@@ -40,7 +40,7 @@ func TestSearchWithGetByKeys_Integration(t *testing.T) {
 	}
 
 	first := int64(10)
-	searchResult, err := queryResolver.CustomerSearch(ctx, searchFilter, nil, &first, nil, nil, nil)
+	searchResult, err := queryResolver.CustomerSearch(ctx, searchFilter, nil, &first, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, searchResult)
 	assert.Equal(t, int64(2), searchResult.Count) // Alice and Amy both start with A
@@ -73,16 +73,16 @@ func TestSearchWithGetByKeys_Integration(t *testing.T) {
 
 	// Test 2: Verify both queries exclude deleted entities
 	// Search should exclude deleted
-	allSearchResult, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil)
+	allSearchResult, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	assert.Equal(t, int64(4), allSearchResult.TotalCount) // All 4 non-deleted
 
 	// GetByKeys should also exclude deleted
 	allIdentifiers := []string{
-		"00000000-0000-0000-0000-000000000001",
-		"00000000-0000-0000-0000-000000000002",
-		"00000000-0000-0000-0000-000000000003",
-		"00000000-0000-0000-0000-000000000004",
+		"00000000-0000-4000-8000-000000000001",
+		"00000000-0000-4000-8000-000000000002",
+		"00000000-0000-4000-8000-000000000003",
+		"00000000-0000-4000-8000-000000000004",
 	}
 	allGetByKeysResult, err := queryResolver.CustomerByKeysGet(ctx, allIdentifiers, nil)
 	require.NoError(t, err)
@@ -94,7 +94,7 @@ func TestSearchWithGetByKeys_Integration(t *testing.T) {
 		{LastName: &sortAsc},
 	}
 
-	sortedSearchResult, err := queryResolver.CustomerSearch(ctx, nil, sorter, &first, nil, nil, nil)
+	sortedSearchResult, err := queryResolver.CustomerSearch(ctx, nil, sorter, &first, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	sortedGetByKeysResult, err := queryResolver.CustomerByKeysGet(ctx, allIdentifiers, sorter)
 	require.NoError(t, err)
@@ -128,7 +128,7 @@ func TestSearchWithGetByKeys_SharedConfiguration(t *testing.T) {
 	}
 
 	// Search without pagination params should return max 200
-	searchResult, err := queryResolver.CustomerSearch(ctx, nil, nil, nil, nil, nil, nil)
+	searchResult, err := queryResolver.CustomerSearch(ctx, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	assert.Equal(t, int64(200), searchResult.Count)
 	assert.Equal(t, int64(210), searchResult.TotalCount)
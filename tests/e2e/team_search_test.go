@@ -2,15 +2,16 @@ package e2e
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/generated"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -44,7 +45,7 @@ func TestTeamSearch_BasicFiltering_NameStartsWith(t *testing.T) {
 
 	// Execute teamSearch query
 	first := int64(10)
-	result, err := queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -95,7 +96,7 @@ func TestTeamSearch_MultiFieldSorting(t *testing.T) {
 
 	// Execute teamSearch query
 	first := int64(10)
-	result, err := queryResolver.TeamSearch(ctx, nil, sorter, &first, nil, nil, nil)
+	result, err := queryResolver.TeamSearch(ctx, nil, sorter, &first, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -114,6 +115,173 @@ func TestTeamSearch_MultiFieldSorting(t *testing.T) {
 	assert.Equal(t, "BBB Description", *result.Data[3].Description) // B comes after in DESC
 }
 
+// E2E test for teamSearch isShared sorting with true, false and missing values
+// Missing values follow the null rules: last in ASC, first in DESC
+func TestTeamSearch_IsSharedNullSafeSorting(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	shared := true
+	private := false
+	seedTeamWithIsShared(t, dbClient, "team-020", "Missing Team", nil)
+	seedTeamWithIsShared(t, dbClient, "team-021", "Shared Team", &shared)
+	seedTeamWithIsShared(t, dbClient, "team-022", "Private Team", &private)
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	first := int64(10)
+
+	tests := []struct {
+		name      string
+		direction generated.SortEnumType
+		expected  []string
+	}{
+		{"ASC", generated.SortEnumTypeAsc, []string{"Private Team", "Shared Team", "Missing Team"}},
+		{"DESC", generated.SortEnumTypeDesc, []string{"Missing Team", "Shared Team", "Private Team"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			direction := tt.direction
+			sorter := []*generated.TeamQuerySorterInput{{IsShared: &direction}}
+
+			result, err := queryResolver.TeamSearch(ctx, nil, sorter, &first, nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+			require.Len(t, result.Data, 3)
+
+			names := make([]string, 0, len(result.Data))
+			for _, team := range result.Data {
+				names = append(names, *team.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+// Test isShared sorts page by the null-safe rank: the cursor carries the rank, so the second
+// page continues after the first instead of restarting on identifier order
+func TestTeamSearch_IsSharedNullSafeSorting_Paging(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	// Identifier order differs from rank order, so a cursor on identifier alone would page wrongly
+	shared := true
+	private := false
+	seedTeamWithIsShared(t, dbClient, "team-040", "Missing Team", nil)
+	seedTeamWithIsShared(t, dbClient, "team-041", "Shared Team", &shared)
+	seedTeamWithIsShared(t, dbClient, "team-042", "Private Team", &private)
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	first := int64(2)
+
+	tests := []struct {
+		name      string
+		direction generated.SortEnumType
+		pages     [][]string
+	}{
+		{"ASC", generated.SortEnumTypeAsc, [][]string{{"Private Team", "Shared Team"}, {"Missing Team"}}},
+		{"DESC", generated.SortEnumTypeDesc, [][]string{{"Missing Team", "Shared Team"}, {"Private Team"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			direction := tt.direction
+			sorter := []*generated.TeamQuerySorterInput{{IsShared: &direction}}
+
+			var after *string
+			for i, expected := range tt.pages {
+				result, err := queryResolver.TeamSearch(ctx, nil, sorter, &first, after, nil, nil, nil, nil)
+				require.NoError(t, err)
+
+				names := make([]string, 0, len(result.Data))
+				for _, team := range result.Data {
+					names = append(names, *team.Name)
+				}
+				assert.Equal(t, expected, names, "page %d", i+1)
+				assert.Equal(t, i < len(tt.pages)-1, result.Paging.HasNextPage, "page %d", i+1)
+
+				after = result.Paging.EndCursor
+			}
+		})
+	}
+}
+
+// Test teams with equal sort values page by identifier without skipping or repeating any
+func TestTeamSearch_EqualSortValuesPageByIdentifier(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	// Inserted out of identifier order so natural order cannot pass for the tiebreaker
+	seedTeamForSearch(t, dbClient, "team-052", "Same Team", "INIT")
+	seedTeamForSearch(t, dbClient, "team-050", "Same Team", "INIT")
+	seedTeamForSearch(t, dbClient, "team-051", "Same Team", "INIT")
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	asc := generated.SortEnumTypeAsc
+	sorter := []*generated.TeamQuerySorterInput{{Name: &asc}}
+	first := int64(2)
+
+	page1, err := queryResolver.TeamSearch(ctx, nil, sorter, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	page2, err := queryResolver.TeamSearch(ctx, nil, sorter, &first, page1.Paging.EndCursor, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	identifiers := []string{}
+	for _, team := range append(page1.Data, page2.Data...) {
+		identifiers = append(identifiers, team.Identifier)
+	}
+	assert.Equal(t, []string{"team-050", "team-051", "team-052"}, identifiers)
+	assert.False(t, page2.Paging.HasNextPage)
+}
+
+// Test in: [] matches nothing without querying while nin: [] matches everything
+func TestTeamSearch_EmptyInNinLists(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	seedTeamForSearch(t, dbClient, "team-030", "Alpha Team", "INIT")
+	seedTeamForSearch(t, dbClient, "team-031", "Beta Team", "INIT")
+
+	first := int64(10)
+	emptyIn := &generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{In: []*string{}}}
+	emptyNin := &generated.TeamQueryFilterInput{Name: &generated.StringFilterInput{Nin: []*string{}}}
+
+	result, err := resolvers.NewResolver(dbClient).Query().TeamSearch(ctx, emptyIn, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.Count)
+	assert.Equal(t, int64(0), result.TotalCount)
+	assert.Empty(t, result.Data)
+
+	result, err = resolvers.NewResolver(dbClient).Query().TeamSearch(ctx, emptyNin, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Count)
+	assert.Equal(t, int64(2), result.TotalCount)
+
+	// Without a database the empty in list still succeeds, since no query is sent
+	result, err = resolvers.NewResolver(nil).Query().TeamSearch(ctx, emptyIn, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.TotalCount)
+}
+
 // T062: E2E test for nested OR filters (multiple OR conditions)
 func TestTeamSearch_NestedORFilters(t *testing.T) {
 	if testing.Short() {
@@ -154,7 +322,7 @@ func TestTeamSearch_NestedORFilters(t *testing.T) {
 
 	// Execute teamSearch query
 	first := int64(10)
-	result, err := queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil)
+	result, err := queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -180,6 +348,148 @@ func TestTeamSearch_NestedORFilters(t *testing.T) {
 	assert.True(t, foundGamma)
 }
 
+// E2E test for teams led by an employee: the employeeId filter alone and combined with a status filter
+func TestTeamSearch_EmployeeIDFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	leaderA := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	leaderB := "16fd2706-8baf-433b-82eb-8c7fada847da"
+	seedTeamWithLeader(t, dbClient, "team-lead-1", "Alpha Team", leaderA, "CREATED")
+	seedTeamWithLeader(t, dbClient, "team-lead-2", "Beta Team", leaderA, "")
+	seedTeamWithLeader(t, dbClient, "team-lead-3", "Gamma Team", leaderB, "CREATED")
+	seedTeamForSearch(t, dbClient, "team-lead-4", "Delta Team", "INIT") // No leader
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	first := int64(10)
+
+	filter := &generated.TeamQueryFilterInput{EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &leaderA}}
+	result, err := queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.TotalCount)
+	assert.Equal(t, "Alpha Team", *result.Data[0].Name)
+	assert.Equal(t, "Beta Team", *result.Data[1].Name)
+
+	// Combined with the status filter
+	created := generated.CreateStatusCreated
+	filter.Status = &generated.TeamStatusObjectFilterInput{Creation: &generated.EnumFilterOfNullableOfCreateStatusInput{Eq: &created}}
+	result, err = queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.TotalCount)
+	assert.Equal(t, "team-lead-1", result.Data[0].Identifier)
+
+	// Teams led by either employee
+	filter = &generated.TeamQueryFilterInput{EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{In: []*string{&leaderA, &leaderB}}}
+	result, err = queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.TotalCount)
+
+	// Values that are not UUIDs are rejected
+	invalid := "team-lead-1"
+	filter = &generated.TeamQueryFilterInput{EmployeeID: &generated.ComparableFilterOfNullableOfGUIDInput{Eq: &invalid}}
+	_, err = queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
+	assert.ErrorContains(t, err, "invalid UUID format")
+}
+
+// E2E test for teamSearch memberCount filter: teams without a member list count 0
+func TestTeamSearch_MemberCountFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	seedTeamForSearch(t, dbClient, "team-members-0", "Alpha Team", "INIT") // No member list
+	seedTeamWithMembers(t, dbClient, "team-members-2", "Beta Team", 2)
+	seedTeamWithMembers(t, dbClient, "team-members-5", "Gamma Team", 5)
+
+	queryResolver := resolvers.NewResolver(dbClient).Query()
+	first := int64(10)
+	search := func(filter *generated.TeamQueryFilterInput) []string {
+		t.Helper()
+		result, err := queryResolver.TeamSearch(ctx, filter, nil, &first, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		identifiers := make([]string, 0, len(result.Data))
+		for _, team := range result.Data {
+			identifiers = append(identifiers, team.Identifier)
+		}
+		return identifiers
+	}
+	zero, two, three, five := 0, 2, 3, 5
+
+	// Teams with fewer than 3 members
+	assert.Equal(t, []string{"team-members-0", "team-members-2"},
+		search(&generated.TeamQueryFilterInput{MemberCount: &generated.ComparableFilterOfInt32Input{Lt: &three}}))
+	assert.Equal(t, []string{"team-members-5"},
+		search(&generated.TeamQueryFilterInput{MemberCount: &generated.ComparableFilterOfInt32Input{Gt: &two}}))
+	assert.Equal(t, []string{"team-members-0", "team-members-2"},
+		search(&generated.TeamQueryFilterInput{MemberCount: &generated.ComparableFilterOfInt32Input{Lte: &two}}))
+	assert.Equal(t, []string{"team-members-2", "team-members-5"},
+		search(&generated.TeamQueryFilterInput{MemberCount: &generated.ComparableFilterOfInt32Input{Gt: &zero, Lte: &five}}))
+
+	// Composed with other team filters inside and/or
+	prefix := "Gamma"
+	assert.Equal(t, []string{"team-members-0", "team-members-5"},
+		search(&generated.TeamQueryFilterInput{Or: []*generated.TeamQueryFilterInput{
+			{MemberCount: &generated.ComparableFilterOfInt32Input{Eq: &zero}},
+			{Name: &generated.StringFilterInput{StartsWith: &prefix}},
+		}}))
+	assert.Equal(t, []string{"team-members-5"},
+		search(&generated.TeamQueryFilterInput{And: []*generated.TeamQueryFilterInput{
+			{MemberCount: &generated.ComparableFilterOfInt32Input{Gt: &two}},
+			{Name: &generated.StringFilterInput{StartsWith: &prefix}},
+		}}))
+}
+
+// Helper: Seed team listing count members in teamMembers
+func seedTeamWithMembers(t *testing.T, dbClient *db.Client, identifier, name string, count int) {
+	t.Helper()
+
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s-employee-%d", identifier, i)
+	}
+	doc := bson.M{
+		"identifier":      identifier,
+		"name":            name,
+		"createDate":      testutil.NextCreateDate(),
+		"status":          bson.M{"deletion": "INIT"},
+		"actionIndicator": "NONE",
+		"teamMembers":     bson.M{"keys": keys},
+	}
+
+	_, err := dbClient.Collection("teams").InsertOne(context.Background(), doc)
+	require.NoError(t, err)
+}
+
+// Helper: Seed team with a leader's employeeId and an optional creation status
+func seedTeamWithLeader(t *testing.T, dbClient *db.Client, identifier, name, employeeID, creationStatus string) {
+	t.Helper()
+
+	status := bson.M{"deletion": "INIT"}
+	if creationStatus != "" {
+		status["creation"] = creationStatus
+	}
+	doc := bson.M{
+		"identifier":      identifier,
+		"name":            name,
+		"employeeId":      employeeID,
+		"createDate":      testutil.NextCreateDate(),
+		"status":          status,
+		"actionIndicator": "NONE",
+	}
+
+	_, err := dbClient.Collection("teams").InsertOne(context.Background(), doc)
+	require.NoError(t, err)
+}
+
 // Helper: Seed team for search tests
 func seedTeamForSearch(t *testing.T, dbClient *db.Client, identifier, name, deletionStatus string) {
 	t.Helper()
@@ -189,7 +499,7 @@ func seedTeamForSearch(t *testing.T, dbClient *db.Client, identifier, name, dele
 	doc := bson.M{
 		"identifier":      identifier,
 		"name":            name,
-		"createDate":      time.Now().Format(time.RFC3339),
+		"createDate":      testutil.NextCreateDate(),
 		"status": bson.M{
 			"deletion": deletionStatus,
 		},
@@ -230,7 +540,7 @@ func seedTeamWithDescription(t *testing.T, dbClient *db.Client, identifier, name
 		"identifier":      identifier,
 		"name":            name,
 		"description":     description,
-		"createDate":      time.Now().Format(time.RFC3339),
+		"createDate":      testutil.NextCreateDate(),
 		"status": bson.M{
 			"deletion": deletionStatus,
 		},
@@ -240,3 +550,63 @@ func seedTeamWithDescription(t *testing.T, dbClient *db.Client, identifier, name
 	_, err := collection.InsertOne(ctx, doc)
 	require.NoError(t, err)
 }
+
+// Helper: Seed team with optional isShared flag (nil leaves the field missing)
+func seedTeamWithIsShared(t *testing.T, dbClient *db.Client, identifier, name string, isShared *bool) {
+	t.Helper()
+	ctx := context.Background()
+
+	collection := dbClient.Collection("teams")
+	doc := bson.M{
+		"identifier": identifier,
+		"name":       name,
+		"createDate": testutil.NextCreateDate(),
+		"status": bson.M{
+			"deletion": "INIT",
+		},
+		"actionIndicator": "NONE",
+	}
+	if isShared != nil {
+		doc["isShared"] = *isShared
+	}
+
+	_, err := collection.InsertOne(ctx, doc)
+	require.NoError(t, err)
+}
+
+// E2E test for the team default sort: an unsorted search returns teams by name ascending
+func TestTeamSearch_DefaultSort_NameAsc(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	ctx := context.Background()
+	dbClient := setupTestDatabase(t)
+	defer teardownTestDatabase(t, dbClient)
+
+	// Identifiers ascend in the opposite order of the names
+	seedTeamForSearch(t, dbClient, "00000000-0000-4000-8000-00000000e001", "Zulu", "INIT")
+	seedTeamForSearch(t, dbClient, "00000000-0000-4000-8000-00000000e002", "Mike", "INIT")
+	seedTeamForSearch(t, dbClient, "00000000-0000-4000-8000-00000000e003", "Alpha", "INIT")
+
+	resolver := resolvers.NewResolver(dbClient)
+	queryResolver := resolver.Query()
+
+	first := int64(2)
+	page1, err := queryResolver.TeamSearch(ctx, nil, nil, &first, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, page1.Data, 2)
+	assert.Equal(t, "Alpha", *page1.Data[0].Name)
+	assert.Equal(t, "Mike", *page1.Data[1].Name)
+
+	page2, err := queryResolver.TeamSearch(ctx, nil, nil, &first, page1.Paging.EndCursor, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, page2.Data, 1)
+	assert.Equal(t, "Zulu", *page2.Data[0].Name)
+
+	byKeys, err := queryResolver.TeamByKeysGet(ctx, []string{"00000000-0000-4000-8000-00000000e001", "00000000-0000-4000-8000-00000000e002", "00000000-0000-4000-8000-00000000e003"}, nil)
+	require.NoError(t, err)
+	require.Len(t, byKeys, 3)
+	assert.Equal(t, "Alpha", *byKeys[0].Name)
+	assert.Equal(t, "Zulu", *byKeys[2].Name)
+}
@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/airctl"
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// TestAirctl_SearchCustomer verifies airctl search converts the filter file and sort like the
+// server, excludes deleted customers and prints them as JSON or a table
+func TestAirctl_SearchCustomer(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	client, err := db.NewClient(&db.DBConfig{
+		URI:              uri,
+		Database:         "airctl_test_db",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      1,
+		MaxPoolSize:      5,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}, zerolog.Nop())
+	require.NoError(t, err)
+	require.NoError(t, client.Connect(ctx))
+	defer func() {
+		_ = client.Disconnect(context.Background())
+		client.Close()
+	}()
+
+	_, err = client.Collection("customers").InsertMany(ctx, []interface{}{
+		bson.M{"identifier": "550e8400-e29b-41d4-a716-446655440001", "firstName": "Jane", "lastName": "Doe", "createDate": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "status": bson.M{"deletion": "INIT"}},
+		bson.M{"identifier": "550e8400-e29b-41d4-a716-446655440002", "firstName": "Janet", "lastName": "Roe", "createDate": time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "status": bson.M{"deletion": "INIT"}},
+		bson.M{"identifier": "550e8400-e29b-41d4-a716-446655440003", "firstName": "Janis", "lastName": "Poe", "createDate": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "status": bson.M{"deletion": "DELETED"}},
+		bson.M{"identifier": "550e8400-e29b-41d4-a716-446655440004", "firstName": "John", "lastName": "Smith", "createDate": time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), "status": bson.M{"deletion": "INIT"}},
+	})
+	require.NoError(t, err)
+
+	filterFile := filepath.Join(t.TempDir(), "filter.json")
+	require.NoError(t, os.WriteFile(filterFile, []byte(`{"firstName":{"startsWith":"jan"}}`), 0o600))
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd, err := airctl.ParseArgs(args, nil)
+		require.NoError(t, err)
+		var out bytes.Buffer
+		require.NoError(t, airctl.Run(ctx, cmd, airctl.ReadOnly(client), &out))
+		return out.String()
+	}
+
+	var customers []struct {
+		Identifier string `json:"identifier"`
+		FirstName  string `json:"firstName"`
+		CreateDate string `json:"createDate"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(run("search", "customer", "--filter-file", filterFile, "--sort", "createDate:desc")), &customers))
+	require.Len(t, customers, 2, "the deleted customer is excluded")
+	assert.Equal(t, "Janet", customers[0].FirstName)
+	assert.Equal(t, "Jane", customers[1].FirstName)
+	assert.Equal(t, "2024-02-01T00:00:00Z", customers[0].CreateDate)
+
+	table := run("search", "customer", "--filter-file", filterFile, "--limit", "1", "--output", "table")
+	assert.Regexp(t, `^identifier\s+`, table)
+	assert.Contains(t, table, "550e8400-e29b-41d4-a716-446655440001")
+	assert.NotContains(t, table, "Janet")
+
+	assert.Contains(t, run("get", "customer", "550e8400-e29b-41d4-a716-446655440004"), `"firstName": "John"`)
+	assert.Contains(t, run("health"), `"status": "connected"`)
+
+	cmd, err := airctl.ParseArgs([]string{"get", "customer", "550e8400-e29b-41d4-a716-446655440003"}, nil)
+	require.NoError(t, err)
+	assert.ErrorContains(t, airctl.Run(ctx, cmd, airctl.ReadOnly(client), &bytes.Buffer{}), "not found")
+}
+
+// TestAirctl_DumpVerify verifies airctl dump copies a seeded collection with its deleted
+// documents, that verify accepts the file, and that verify fails once a byte is corrupted
+func TestAirctl_DumpVerify(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, "airctl_dump_test_db")
+	documents := make([]interface{}, 0, 1200)
+	for i := 0; i < 1200; i++ {
+		deletion := "INIT"
+		if i%10 == 0 {
+			deletion = "DELETED"
+		}
+		documents = append(documents, bson.M{
+			"identifier": fmt.Sprintf("550e8400-e29b-41d4-a716-%012d", i),
+			"firstName":  fmt.Sprintf("Customer %d", i),
+			"createDate": time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+			"status":     bson.M{"deletion": deletion},
+		})
+	}
+	_, err = client.Collection("customers").InsertMany(ctx, documents)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "customers.ndjson.gz")
+	run := func(args ...string) (*airctl.DumpHeader, error) {
+		cmd, err := airctl.ParseArgs(args, nil)
+		require.NoError(t, err)
+		var out bytes.Buffer
+		if err := airctl.Run(ctx, cmd, airctl.ReadOnly(client), &out); err != nil {
+			return nil, err
+		}
+		header := &airctl.DumpHeader{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), header))
+		return header, nil
+	}
+
+	dumped, err := run("dump", "customer", "--out", path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1200), dumped.Count, "deleted customers are dumped too")
+	assert.Equal(t, "customers", dumped.Collection)
+
+	verified, err := run("verify", path)
+	require.NoError(t, err)
+	assert.Equal(t, dumped.Checksum, verified.Checksum)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content[len(content)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+	_, err = run("verify", path)
+	assert.Error(t, err)
+}
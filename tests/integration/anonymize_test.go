@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/db/anonymize"
+)
+
+// TestAnonymize_SeededData anonymizes a seeded set and checks determinism and that emails
+// shared between customers and employees stay consistent
+func TestAnonymize_SeededData(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the clients disconnect
+
+	seed := func(client *db.Client) {
+		_, err := client.Collection("employees").InsertMany(ctx, []interface{}{
+			bson.M{"identifier": "e1", "userEmail": "Grace@Example.com", "userEmailLower": "grace@example.com"},
+			bson.M{"identifier": "e2", "userEmail": "alan@example.com"},
+		})
+		require.NoError(t, err)
+		_, err = client.Collection("customers").InsertMany(ctx, []interface{}{
+			bson.M{"identifier": "c1", "firstName": "Ada", "lastName": "Byron", "userEmail": "ada@example.com", "employeeEmail": "grace@example.com"},
+			bson.M{"identifier": "c2", "firstName": "Ada", "lastName": "King", "employeeEmail": "alan@example.com"},
+		})
+		require.NoError(t, err)
+	}
+	opts := anonymize.Options{Key: []byte("staging-key"), ProductionPattern: regexp.MustCompile("(?i)prod")}
+
+	first := connectTestClient(t, uri, "anonymize_a_test_db")
+	second := connectTestClient(t, uri, "anonymize_b_test_db")
+	seed(first)
+	seed(second)
+
+	t.Run("dry run counts without writing", func(t *testing.T) {
+		dryRun := opts
+		dryRun.DryRun = true
+		results, err := anonymize.Run(ctx, first.Database(), anonymize.DefaultFields, dryRun, zerolog.Nop())
+		require.NoError(t, err)
+		assert.Equal(t, []anonymize.Result{
+			{Collection: "customers", Scanned: 2, Changed: 2, DryRun: true},
+			{Collection: "customers_history", DryRun: true},
+			{Collection: "employees", Scanned: 2, Changed: 2, DryRun: true},
+		}, results)
+		assert.Equal(t, "Ada", documentOf(t, first, "customers", "c1")["firstName"])
+	})
+
+	for _, client := range []*db.Client{first, second} {
+		_, err := anonymize.Run(ctx, client.Database(), anonymize.DefaultFields, opts, zerolog.Nop())
+		require.NoError(t, err)
+	}
+
+	c1 := documentOf(t, first, "customers", "c1")
+	c2 := documentOf(t, first, "customers", "c2")
+	e1 := documentOf(t, first, "employees", "e1")
+	e2 := documentOf(t, first, "employees", "e2")
+
+	assert.Regexp(t, `^Anon-`, c1["firstName"])
+	assert.Equal(t, c1["firstName"], c2["firstName"], "equal values get equal fakes")
+	assert.NotEqual(t, c1["lastName"], c2["lastName"])
+
+	// Customers still reference their employees
+	assert.Regexp(t, `@anonymized\.invalid$`, e1["userEmail"])
+	assert.Equal(t, e1["userEmail"], c1["employeeEmail"])
+	assert.Equal(t, e2["userEmail"], c2["employeeEmail"])
+	assert.Equal(t, e1["userEmail"], e1["userEmailLower"])
+	assert.NotContains(t, e2, "userEmailLower", "absent shadow fields are not added")
+
+	// The same key yields the same fakes in another database
+	for _, ref := range []struct{ collection, identifier string }{
+		{"customers", "c1"}, {"customers", "c2"}, {"employees", "e1"}, {"employees", "e2"},
+	} {
+		a := documentOf(t, first, ref.collection, ref.identifier)
+		b := documentOf(t, second, ref.collection, ref.identifier)
+		delete(a, "_id")
+		delete(b, "_id")
+		assert.Equal(t, a, b, "%s %s", ref.collection, ref.identifier)
+	}
+
+	t.Run("re-run changes nothing", func(t *testing.T) {
+		results, err := anonymize.Run(ctx, first.Database(), anonymize.DefaultFields, opts, zerolog.Nop())
+		require.NoError(t, err)
+		for _, result := range results {
+			assert.Zero(t, result.Changed, result.Collection)
+		}
+	})
+
+	t.Run("production database is refused", func(t *testing.T) {
+		production := connectTestClient(t, uri, "air_production")
+		seed(production)
+		_, err := anonymize.Run(ctx, production.Database(), anonymize.DefaultFields, opts, zerolog.Nop())
+		assert.ErrorIs(t, err, anonymize.ErrProductionDatabase)
+		assert.Equal(t, "Ada", documentOf(t, production, "customers", "c1")["firstName"])
+	})
+}
+
+// documentOf returns the raw document with the given identifier
+func documentOf(t *testing.T, client *db.Client, collection, identifier string) bson.M {
+	t.Helper()
+	var doc bson.M
+	require.NoError(t, client.Collection(collection).FindOne(context.Background(), bson.M{"identifier": identifier}).Decode(&doc))
+	return doc
+}
@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of the driver and the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestContextLogging_FindOneCarriesRequestID runs a request through the logging middleware and
+// verifies the collection wrapper's FindOne log line carries the request's ID
+func TestContextLogging_FindOneCarriesRequestID(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	var logs syncBuffer
+	client, err := db.NewClient(&db.DBConfig{
+		URI:              uri,
+		Database:         "context_logging_test_db",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      1,
+		MaxPoolSize:      5,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}, zerolog.New(&logs).Level(zerolog.DebugLevel))
+	require.NoError(t, err)
+	require.NoError(t, client.Connect(ctx))
+	t.Cleanup(func() {
+		_ = client.Disconnect(context.Background())
+		client.Close()
+	})
+
+	handler := middleware.LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = client.Collection("customers").FindOne(r.Context(), bson.M{"identifier": "missing"}).Err()
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-find-one")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var findOne string
+	for _, line := range strings.Split(logs.String(), "\n") {
+		if strings.Contains(line, `"operation":"find_one"`) {
+			findOne = line
+		}
+	}
+	require.NotEmpty(t, findOne, logs.String())
+	assert.Contains(t, findOne, `"request_id":"req-find-one"`)
+}
@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestCreateDate_PaginationWithinOneSecond verifies customers created within the same second get
+// distinct createDates and page through the default createDate order without skipping or
+// repeating a customer at page boundaries
+func TestCreateDate_PaginationWithinOneSecond(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := connectTestClient(t, uri, "create_date_pagination_db")
+
+	// Every clock reading advances 5ms, so all creates fall within one second
+	resolvers.SetClock(testutil.NewTickingClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), 5*time.Millisecond))
+	t.Cleanup(func() { resolvers.SetClock(db.RealClock{}) })
+
+	resolver := resolvers.NewResolver(client)
+	adminCtx := testutil.WithAdminContext(ctx)
+	const total = 50
+	created := make([]string, 0, total)
+	for i := 0; i < total; i++ {
+		identifier := fmt.Sprintf("550e8400-e29b-41d4-a716-4466554401%02d", i)
+		_, err := resolver.Mutation().CustomerCreate(adminCtx, generated.CustomerMutationInput{Identifier: identifier})
+		require.NoError(t, err)
+		created = append(created, identifier)
+	}
+
+	// The default order is createDate descending: the last customer created comes first
+	want := make([]string, 0, total)
+	for i := total - 1; i >= 0; i-- {
+		want = append(want, created[i])
+	}
+
+	paginate := func() []string {
+		var identifiers []string
+		first := int64(7)
+		var after *string
+		for {
+			page, err := resolver.Query().CustomerSearch(adminCtx, nil, nil, &first, after, nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+			for _, customer := range page.Data {
+				identifiers = append(identifiers, customer.Identifier)
+			}
+			if !page.Paging.HasNextPage {
+				return identifiers
+			}
+			after = page.Paging.EndCursor
+		}
+	}
+
+	assert.Equal(t, want, paginate())
+	assert.Equal(t, want, paginate(), "a second pagination returns the same pages")
+
+	all := int64(total)
+	page, err := resolver.Query().CustomerSearch(adminCtx, nil, nil, &all, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Data, total)
+	for i := 1; i < total; i++ {
+		previous, err := time.Parse(time.RFC3339Nano, *page.Data[i-1].CreateDate)
+		require.NoError(t, err)
+		current, err := time.Parse(time.RFC3339Nano, *page.Data[i].CreateDate)
+		require.NoError(t, err)
+		assert.True(t, previous.After(current), "createDate %v is not after %v", previous, current)
+	}
+}
@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestCustomerHistory_UpdatesAndDelete verifies two updates and a delete record three
+// versions, each holding the customer as it was before its change
+func TestCustomerHistory_UpdatesAndDelete(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartReplicaSetTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := connectTestClient(t, uri, "customer_history_test_db")
+
+	identifier := "7d2c4b1a-3e5f-4a6b-8c9d-0e1f2a3b4c5d"
+	_, err = client.Collection("customers").InsertOne(ctx, bson.M{
+		"identifier": identifier,
+		"firstName":  "Ada",
+		"lastName":   "Byron",
+		"status":     bson.M{"deletion": "INIT"},
+	})
+	require.NoError(t, err)
+
+	resolver := resolvers.NewResolver(client)
+	adminCtx := testutil.WithAdminContext(ctx)
+
+	firstName := "Augusta"
+	_, err = resolver.Mutation().CustomerUpdate(adminCtx, generated.CustomerUpdateMutationInput{Identifier: identifier, FirstName: &firstName})
+	require.NoError(t, err)
+
+	lastName := "King"
+	_, err = resolver.Mutation().CustomerUpdate(adminCtx, generated.CustomerUpdateMutationInput{Identifier: identifier, LastName: &lastName})
+	require.NoError(t, err)
+
+	deleted, err := resolver.Mutation().CustomerDelete(adminCtx, identifier)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	// Updates keep the lowercase shadow fields current
+	var current bson.M
+	require.NoError(t, client.Collection("customers").FindOne(ctx, bson.M{"identifier": identifier}).Decode(&current))
+	assert.Equal(t, "augusta", current["firstNameLower"])
+	assert.Equal(t, "king", current["lastNameLower"])
+
+	// Oldest first; version identifiers are UUIDv7
+	cursor, err := client.Collection("customers_history").Find(ctx, bson.M{"customerIdentifier": identifier},
+		options.Find().SetSort(bson.D{{Key: "identifier", Value: 1}}))
+	require.NoError(t, err)
+	var entries []bson.M
+	require.NoError(t, cursor.All(ctx, &entries))
+	require.Len(t, entries, 3)
+
+	type version struct {
+		operation, firstName, lastName, deletion string
+	}
+	want := []version{
+		{"UPDATE", "Ada", "Byron", "INIT"},
+		{"UPDATE", "Augusta", "Byron", "INIT"},
+		{"DELETE", "Augusta", "King", "INIT"},
+	}
+	for i, entry := range entries {
+		preImage, ok := entry["preImage"].(bson.M)
+		require.True(t, ok, "entry %d has no pre-image", i)
+		status, _ := preImage["status"].(bson.M)
+		assert.Equal(t, want[i], version{
+			operation: entry["operation"].(string),
+			firstName: preImage["firstName"].(string),
+			lastName:  preImage["lastName"].(string),
+			deletion:  status["deletion"].(string),
+		}, "entry %d", i)
+		assert.Equal(t, identifier, preImage["identifier"])
+	}
+
+	// The query lists the same versions newest first
+	history, err := resolver.Query().CustomerHistory(adminCtx, identifier, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), history.TotalCount)
+	operations := make([]generated.HistoryOperation, 0, len(history.Data))
+	for _, entry := range history.Data {
+		operations = append(operations, entry.Operation)
+	}
+	assert.Equal(t, []generated.HistoryOperation{
+		generated.HistoryOperationDelete,
+		generated.HistoryOperationUpdate,
+		generated.HistoryOperationUpdate,
+	}, operations)
+}
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+const dbStatsJWTSecret = "test-secret-key-at-least-32-characters-long"
+
+// operationCount returns the number of recorded commands a collection operation sends; handshakes
+// and cursor cleanup are not operations
+func operationCount(recorder *testutil.CommandRecorder) int {
+	count := 0
+	for _, name := range []string{"aggregate", "find", "count", "insert", "update", "delete"} {
+		count += recorder.CommandCount(name)
+	}
+	return count
+}
+
+// dbStatsQuery searches customers and employees, resolving the teams of the employees through
+// one batched relation lookup
+const dbStatsQuery = `query Stats {
+	customerSearch(first: 5) { data { identifier } }
+	employeeSearch(first: 5) { data { identifier teams { name } } }
+}`
+
+// TestDBStats_CountsRequestOperations verifies the dbOps extension matches the database commands
+// a customerSearch plus an employeeSearch with a relation field sends, and that the stats are
+// only reported on request unless DB_STATS_ALWAYS is set
+func TestDBStats_CountsRequestOperations(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	client, commands := connectRecordedTestClient(t, uri, "db_stats_test_db")
+
+	_, err = client.Collection("customers").InsertOne(ctx, bson.M{"identifier": testutil.TestCustomerID1, "status": bson.M{"deletion": "INIT"}})
+	require.NoError(t, err)
+	_, err = client.Collection("employees").InsertMany(ctx, []interface{}{
+		bson.M{"identifier": testutil.TestCustomerID1, "status": bson.M{"deletion": "INIT"}},
+		bson.M{"identifier": testutil.TestCustomerID2, "status": bson.M{"deletion": "INIT"}},
+	})
+	require.NoError(t, err)
+	_, err = client.Collection("teams").InsertOne(ctx, bson.M{
+		"name":        "Team A",
+		"teamMembers": bson.M{"keys": []string{testutil.TestCustomerID1, testutil.TestCustomerID2}},
+		"status":      bson.M{"deletion": "INIT"},
+	})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "stats-admin",
+		"roles": []string{"ADMIN"},
+	}).SignedString([]byte(dbStatsJWTSecret))
+	require.NoError(t, err)
+
+	run := func(t *testing.T, always, header bool) map[string]interface{} {
+		t.Helper()
+		ts := httptest.NewServer(server.New(&config.Config{
+			Port:          8080,
+			LogFormat:     "json",
+			SchemaPath:    "../../schema.graphqls",
+			JWTSecret:     dbStatsJWTSecret,
+			CORSOrigins:   []string{"*"},
+			DBStatsAlways: always,
+		}, server.WithDatabaseClient(client)))
+		defer ts.Close()
+
+		body, err := json.Marshal(map[string]string{"query": dbStatsQuery})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/graphql", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if header {
+			req.Header.Set(server.DBStatsHeader, "1")
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result struct {
+			Errors     []interface{}          `json:"errors"`
+			Extensions map[string]interface{} `json:"extensions"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Empty(t, result.Errors)
+		return result.Extensions
+	}
+
+	t.Run("requested with the header", func(t *testing.T) {
+		commands.Reset()
+		extensions := run(t, false, true)
+		sent := operationCount(commands)
+
+		// At least one operation per search plus the single batched team lookup
+		require.GreaterOrEqual(t, sent, 3)
+		assert.Equal(t, float64(sent), extensions["dbOps"])
+		assert.Greater(t, extensions["dbTimeMs"], float64(0))
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		extensions := run(t, false, false)
+		assert.NotContains(t, extensions, "dbOps")
+		assert.NotContains(t, extensions, "dbTimeMs")
+	})
+
+	t.Run("always", func(t *testing.T) {
+		commands.Reset()
+		extensions := run(t, true, false)
+		assert.Equal(t, float64(operationCount(commands)), extensions["dbOps"])
+	})
+}
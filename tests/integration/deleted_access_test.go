@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/logger"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestDeletedAccessAudit_LogsDeletedButExistingOnly verifies customerGet and customerByKeysGet
+// return nothing for a deleted customer, and that the audit logs its identifier with the request
+// ID, but not the identifier no customer ever had
+func TestDeletedAccessAudit_LogsDeletedButExistingOnly(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	client := connectTestClient(t, uri, "deleted_access_test_db")
+
+	const deletedID = "c3d4e5f6-0000-4000-8000-000000000003"
+	const absentID = "c3d4e5f6-0000-4000-8000-000000000004"
+	_, err = client.Collection("customers").InsertMany(ctx, []interface{}{
+		bson.M{"identifier": testutil.TestCustomerID1, "status": bson.M{"deletion": "INIT"}},
+		bson.M{"identifier": deletedID, "status": bson.M{"deletion": "DELETED"}},
+	})
+	require.NoError(t, err)
+
+	var logs syncBuffer
+	previousLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	resolvers.SetAuditDeletedAccess(true)
+	t.Cleanup(func() {
+		log.Logger = previousLogger
+		resolvers.SetAuditDeletedAccess(false)
+	})
+
+	query := resolvers.NewResolver(client).Query()
+	requestCtx := logger.WithContextField(testutil.WithAdminContext(ctx), "request_id", "req-audit")
+
+	customer, err := query.CustomerGet(requestCtx, deletedID)
+	require.NoError(t, err)
+	assert.Nil(t, customer)
+	customer, err = query.CustomerGet(requestCtx, absentID)
+	require.NoError(t, err)
+	assert.Nil(t, customer)
+
+	customers, err := query.CustomerByKeysGet(requestCtx, []string{testutil.TestCustomerID1, deletedID, absentID}, nil)
+	require.NoError(t, err)
+	require.Len(t, customers, 1)
+
+	var audited []string
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var event map[string]interface{}
+		if json.Unmarshal([]byte(line), &event) != nil || event["audit"] != "deleted_entity_access" {
+			continue
+		}
+		assert.Equal(t, "customer", event["entity"])
+		assert.Equal(t, "req-audit", event["request_id"])
+		audited = append(audited, event["identifier"].(string))
+	}
+	assert.Equal(t, []string{deletedID, deletedID}, audited, "one event per lookup of the deleted customer")
+}
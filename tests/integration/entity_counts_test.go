@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestEntityCounts_NoCommandsBetweenRefreshes verifies entityCounts answers from the warm cache
+// without sending a command to MongoDB, counts non-deleted entities only, and picks up changes
+// with the next background refresh
+func TestEntityCounts_NoCommandsBetweenRefreshes(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	client, commands := connectRecordedTestClient(t, uri, "entity_counts_test_db")
+	// CountDocuments runs as an aggregate
+	counted := func() int {
+		return commands.CommandCount("aggregate") + commands.CommandCount("count")
+	}
+
+	_, err = client.Collection("customers").InsertMany(ctx, []interface{}{
+		bson.M{"identifier": testutil.TestCustomerID1, "status": bson.M{"deletion": "INIT"}},
+		bson.M{"identifier": testutil.TestCustomerID2, "status": bson.M{"deletion": "DELETED"}},
+	})
+	require.NoError(t, err)
+
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	resolvers.SetClock(clock)
+	resolvers.ResetEntityCounts()
+	refreshCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		resolvers.ScheduleEntityCounts(refreshCtx, client, 30*time.Second, zerolog.Nop())
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		resolvers.SetClock(db.RealClock{})
+		resolvers.ResetEntityCounts()
+	})
+	require.True(t, clock.BlockUntilWaiters(1, 30*time.Second), "first refresh did not complete")
+
+	query := resolvers.NewResolver(client).Query()
+	adminCtx := testutil.WithAdminContext(ctx)
+	customerCount := func() int64 {
+		counts, err := query.EntityCounts(adminCtx, nil)
+		require.NoError(t, err)
+		for _, count := range counts {
+			if count.Entity == "customer" {
+				return count.Count
+			}
+		}
+		t.Fatal("no customer count")
+		return 0
+	}
+
+	before := counted()
+	assert.Equal(t, int64(1), customerCount())
+	assert.Equal(t, int64(1), customerCount())
+	assert.Equal(t, before, counted(), "entityCounts must not reach MongoDB between refreshes")
+
+	_, err = client.Collection("customers").InsertOne(ctx, bson.M{"identifier": "c3d4e5f6-0000-4000-8000-000000000003", "status": bson.M{"deletion": "INIT"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), customerCount())
+
+	clock.Advance(30 * time.Second)
+	require.True(t, clock.BlockUntilWaiters(1, 30*time.Second), "second refresh did not complete")
+	assert.Equal(t, int64(2), customerCount())
+	assert.Greater(t, counted(), before)
+}
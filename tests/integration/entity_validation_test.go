@@ -0,0 +1,139 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+const entityValidationDB = "entity_validation_test_db"
+
+// TestValidateEntityCollections_RenamedCollection verifies a renamed collection and a
+// collection missing its deletion field are detected in lenient and strict mode
+func TestValidateEntityCollections_RenamedCollection(t *testing.T) {
+	ctx := context.Background()
+
+	// Start test container
+	raw, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, entityValidationDB)
+
+	// Seed every entity collection with a valid document
+	seeds := map[string]bson.M{
+		"customers":           {"identifier": "c1", "status": bson.M{"deletion": "INIT"}},
+		"employees":           {"identifier": "e1", "status": bson.M{"deletion": "INIT"}},
+		"teams":               {"identifier": "t1", "status": bson.M{"deletion": "INIT"}},
+		"inventories":         {"identifier": "i1", "actionIndicator": "NONE"},
+		"executionPlans":      {"identifier": "x1", "actionIndicator": "NONE"},
+		"referencePortfolios": {"identifier": "r1", "actionIndicator": "NONE"},
+	}
+	for collection, doc := range seeds {
+		_, err := client.Collection(collection).InsertOne(ctx, doc)
+		require.NoError(t, err)
+	}
+
+	problems, err := resolvers.ValidateEntityCollections(ctx, client.Database(), true)
+	require.NoError(t, err)
+	assert.Empty(t, problems, "valid collections should pass strict validation")
+
+	// Rename a collection the way a typo in the config would miss it
+	err = raw.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "renameCollection", Value: entityValidationDB + ".inventories"},
+		{Key: "to", Value: entityValidationDB + ".inventorys"},
+	}).Err()
+	require.NoError(t, err)
+
+	// Drop the deletion field from the only team document
+	_, err = client.Collection("teams").UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"status": ""}})
+	require.NoError(t, err)
+
+	expected := []resolvers.EntityConfigProblem{
+		{Entity: "inventory", Collection: "inventories", Problem: "collection does not exist"},
+		{Entity: "team", Collection: "teams", Problem: `deletion field "status.deletion" not found in sampled documents or indexes`},
+	}
+
+	t.Run("lenient mode reports problems without failing", func(t *testing.T) {
+		problems, err := resolvers.ValidateEntityCollections(ctx, client.Database(), false)
+		require.NoError(t, err)
+		assert.Equal(t, expected, problems)
+	})
+
+	t.Run("strict mode fails with the problems", func(t *testing.T) {
+		problems, err := resolvers.ValidateEntityCollections(ctx, client.Database(), true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "inventory (inventories): collection does not exist")
+		assert.Equal(t, expected, problems)
+	})
+
+	t.Run("an index on the deletion field satisfies the check", func(t *testing.T) {
+		_, err := raw.Database(entityValidationDB).Collection("teams").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "status.deletion", Value: 1}},
+		})
+		require.NoError(t, err)
+
+		problems, err := resolvers.ValidateEntityCollections(ctx, client.Database(), false)
+		require.NoError(t, err)
+		assert.Equal(t, expected[:1], problems)
+	})
+}
+
+// connectTestClient connects a db.Client for the named database to the test container
+func connectTestClient(t *testing.T, uri, database string) *db.Client {
+	t.Helper()
+	return connectMonitoredTestClient(t, uri, database, nil)
+}
+
+// connectRecordedTestClient is connectTestClient recording the commands the client sends
+func connectRecordedTestClient(t *testing.T, uri, database string) (*db.Client, *testutil.CommandRecorder) {
+	t.Helper()
+	recorder := testutil.NewCommandRecorder()
+	return connectMonitoredTestClient(t, uri, database, recorder.Monitor()), recorder
+}
+
+// connectMonitoredTestClient is connectTestClient with a driver command monitor (nil for none)
+func connectMonitoredTestClient(t *testing.T, uri, database string, monitor *event.CommandMonitor) *db.Client {
+	t.Helper()
+
+	config := &db.DBConfig{
+		URI:              uri,
+		Database:         database,
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}
+
+	client, err := db.NewClient(config, zerolog.Nop())
+	require.NoError(t, err)
+	client.SetCommandMonitor(monitor)
+
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer connectCancel()
+	require.NoError(t, client.Connect(connectCtx))
+
+	t.Cleanup(func() {
+		disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer disconnectCancel()
+		_ = client.Disconnect(disconnectCtx)
+		client.Close()
+	})
+
+	return client
+}
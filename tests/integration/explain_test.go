@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestSearchExplain_UnindexedSort verifies explain mode reports a collection scan instead of data
+func TestSearchExplain_UnindexedSort(t *testing.T) {
+	ctx := context.Background()
+
+	// Start test container
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	config := &db.DBConfig{
+		URI:              uri,
+		Database:         "explain_test_db",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}
+
+	client, err := db.NewClient(config, zerolog.Nop())
+	require.NoError(t, err)
+
+	connectCtx, connectCancel := context.WithTimeout(ctx, 30*time.Second)
+	err = client.Connect(connectCtx)
+	connectCancel()
+	require.NoError(t, err)
+
+	defer func() {
+		disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer disconnectCancel()
+		_ = client.Disconnect(disconnectCtx)
+		client.Close()
+	}()
+
+	// Seed customers without any secondary index
+	customers := make([]interface{}, 0, 50)
+	for i := 0; i < 50; i++ {
+		customers = append(customers, bson.M{
+			"identifier":      fmt.Sprintf("550e8400-e29b-41d4-a716-4466554400%02d", i),
+			"firstName":       fmt.Sprintf("Customer %02d", 50-i),
+			"actionIndicator": "NONE",
+			"status":          bson.M{"deletion": "INIT"},
+		})
+	}
+	_, err = client.Collection("customers").InsertMany(ctx, customers)
+	require.NoError(t, err)
+
+	queryResolver := resolvers.NewResolver(client).Query()
+	asc := generated.SortEnumTypeAsc
+	order := []*generated.CustomerQuerySorterInput{{FirstName: &asc}}
+
+	t.Run("admin receives summary instead of data", func(t *testing.T) {
+		explainCtx := resolvers.WithExplain(testutil.WithAdminContext(ctx))
+
+		result, err := queryResolver.CustomerSearch(explainCtx, nil, order, nil, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.Data)
+
+		summaries := resolvers.ExplainSummaries(explainCtx)
+		require.Len(t, summaries, 1)
+		summary := summaries["customers"]
+		assert.Equal(t, "customers", summary.Collection)
+		assert.True(t, summary.CollectionScan, "unindexed search should report a collection scan")
+		assert.Empty(t, summary.IndexUsed)
+		assert.Equal(t, int64(50), summary.DocsExamined)
+	})
+
+	t.Run("non-admin is rejected", func(t *testing.T) {
+		explainCtx := resolvers.WithExplain(testutil.WithUserContext(ctx, "user-id", "user@test.com"))
+
+		_, err := queryResolver.CustomerSearch(explainCtx, nil, order, nil, nil, nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Administrator privileges required")
+	})
+
+	t.Run("normal request returns data", func(t *testing.T) {
+		result, err := queryResolver.CustomerSearch(testutil.WithAdminContext(ctx), nil, order, nil, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(50), result.TotalCount)
+	})
+}
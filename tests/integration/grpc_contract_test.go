@@ -0,0 +1,278 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	airv1 "github.com/yourusername/air-go/api/air/v1"
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/grpcapi"
+	"github.com/yourusername/air-go/internal/server"
+)
+
+const (
+	grpcContractJWTSecret = "test-secret-key-at-least-32-characters-long"
+	grpcContractToken     = "contract-token-at-least-32-characters-long"
+
+	contractEmployeeA = "1a2b3c4d-0000-4000-8000-00000000000a"
+	contractEmployeeB = "1a2b3c4d-0000-4000-8000-00000000000b"
+)
+
+// contractCustomers are the seed customers; the last one is deleted and answered by neither API
+var contractCustomers = []bson.M{
+	contractCustomer("5e0a1b2c-0000-4000-8000-000000000001", "Anna", "Berg", contractEmployeeA, "ACTIVE", "INIT"),
+	contractCustomer("5e0a1b2c-0000-4000-8000-000000000002", "Jonas", "Hannemann", contractEmployeeA, "BLOCKED", "INIT"),
+	contractCustomer("5e0a1b2c-0000-4000-8000-000000000003", "Lena", "Vogt", contractEmployeeB, "ACTIVE", "INIT"),
+	contractCustomer("5e0a1b2c-0000-4000-8000-000000000004", "Hanna", "Krause", contractEmployeeB, "ACTIVE", "INIT"),
+	contractCustomer("5e0a1b2c-0000-4000-8000-000000000005", "Anton", "Meyer", contractEmployeeA, "ACTIVE", "DELETED"),
+}
+
+func contractCustomer(identifier, firstName, lastName, employeeID, activation, deletion string) bson.M {
+	return bson.M{
+		"identifier":    identifier,
+		"firstName":     firstName,
+		"lastName":      lastName,
+		"userEmail":     strings.ToLower(firstName) + "@example.com",
+		"employeeId":    employeeID,
+		"employeeEmail": "advisor@example.com",
+		"createDate":    "2024-03-01T10:00:00Z",
+		"status":        bson.M{"activation": activation, "deletion": deletion},
+	}
+}
+
+// graphqlCustomer is a customer as selected by customerFields
+type graphqlCustomer struct {
+	Identifier    string  `json:"identifier"`
+	FirstName     *string `json:"firstName"`
+	LastName      *string `json:"lastName"`
+	UserEmail     *string `json:"userEmail"`
+	EmployeeID    *string `json:"employeeId"`
+	EmployeeEmail *string `json:"employeeEmail"`
+	CreateDate    *string `json:"createDate"`
+	Status        *struct {
+		Activation *string `json:"activation"`
+	} `json:"status"`
+}
+
+const customerFields = `identifier firstName lastName userEmail employeeId employeeEmail createDate status { activation }`
+
+// apiCustomer converts a GraphQL customer to the gRPC message it must equal
+func (c graphqlCustomer) apiCustomer() *airv1.Customer {
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	customer := &airv1.Customer{
+		Identifier:    c.Identifier,
+		FirstName:     value(c.FirstName),
+		LastName:      value(c.LastName),
+		UserEmail:     value(c.UserEmail),
+		EmployeeId:    value(c.EmployeeID),
+		EmployeeEmail: value(c.EmployeeEmail),
+		CreateDate:    value(c.CreateDate),
+	}
+	if c.Status != nil {
+		customer.ActivationStatus = value(c.Status.Activation)
+	}
+	return customer
+}
+
+func apiCustomers(customers []graphqlCustomer) []*airv1.Customer {
+	converted := make([]*airv1.Customer, 0, len(customers))
+	for _, customer := range customers {
+		converted = append(converted, customer.apiCustomer())
+	}
+	return converted
+}
+
+// assertSameCustomers compares customers field by field, in order
+func assertSameCustomers(t *testing.T, expected, actual []*airv1.Customer) {
+	t.Helper()
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].String(), actual[i].String(), "customer %d", i)
+	}
+}
+
+// TestGRPCContract_SameResultsAsGraphQL runs the seed customers through both APIs and verifies
+// GetCustomer, GetCustomersByKeys and SearchCustomers answer what their GraphQL queries answer
+func TestGRPCContract_SameResultsAsGraphQL(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	client := connectTestClient(t, uri, "grpc_contract_test_db")
+	documents := make([]interface{}, len(contractCustomers))
+	for i, customer := range contractCustomers {
+		documents[i] = customer
+	}
+	_, err = client.Collection("customers").InsertMany(ctx, documents)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(server.New(&config.Config{
+		Port:        8080,
+		LogFormat:   "json",
+		SchemaPath:  "../../schema.graphqls",
+		JWTSecret:   grpcContractJWTSecret,
+		CORSOrigins: []string{"*"},
+	}, server.WithDatabaseClient(client)))
+	t.Cleanup(ts.Close)
+
+	jwtToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "contract-admin",
+		"roles": []string{"ADMIN"},
+	}).SignedString([]byte(grpcContractJWTSecret))
+	require.NoError(t, err)
+
+	graphql := func(t *testing.T, query string, data interface{}) {
+		t.Helper()
+		body, err := json.Marshal(map[string]string{"query": query})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/graphql", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data   json.RawMessage `json:"data"`
+			Errors []interface{}   `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Empty(t, result.Errors)
+		require.NoError(t, json.Unmarshal(result.Data, data))
+	}
+
+	grpcServer, err := grpcapi.NewServer(client, grpcapi.Options{AuthToken: grpcContractToken})
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	customers := airv1.NewCustomerServiceClient(conn)
+	grpcCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+grpcContractToken)
+
+	t.Run("GetCustomer", func(t *testing.T) {
+		for _, seed := range contractCustomers {
+			identifier := seed["identifier"].(string)
+			var data struct {
+				CustomerGet *graphqlCustomer `json:"customerGet"`
+			}
+			graphql(t, fmt.Sprintf(`{ customerGet(identifier: %q) { %s } }`, identifier, customerFields), &data)
+
+			response, err := customers.GetCustomer(grpcCtx, &airv1.GetCustomerRequest{Identifier: identifier})
+			require.NoError(t, err)
+			if data.CustomerGet == nil {
+				assert.Nil(t, response.GetCustomer(), identifier)
+				continue
+			}
+			assertSameCustomers(t, []*airv1.Customer{data.CustomerGet.apiCustomer()}, []*airv1.Customer{response.GetCustomer()})
+		}
+	})
+
+	t.Run("GetCustomersByKeys", func(t *testing.T) {
+		identifiers := make([]string, 0, len(contractCustomers)+1)
+		for _, seed := range contractCustomers {
+			identifiers = append(identifiers, seed["identifier"].(string))
+		}
+		identifiers = append(identifiers, "5e0a1b2c-0000-4000-8000-0000000000ff")
+		quoted, err := json.Marshal(identifiers)
+		require.NoError(t, err)
+
+		var data struct {
+			CustomerByKeysGet []graphqlCustomer `json:"customerByKeysGet"`
+		}
+		graphql(t, fmt.Sprintf(`{ customerByKeysGet(identifiers: %s) { %s } }`, quoted, customerFields), &data)
+		require.Len(t, data.CustomerByKeysGet, 4, "the deleted and the unknown customer are omitted")
+
+		response, err := customers.GetCustomersByKeys(grpcCtx, &airv1.GetCustomersByKeysRequest{Identifiers: identifiers})
+		require.NoError(t, err)
+		assertSameCustomers(t, apiCustomers(data.CustomerByKeysGet), response.GetCustomers())
+	})
+
+	searches := []struct {
+		name    string
+		request *airv1.SearchCustomersRequest
+		where   string
+	}{
+		{"no filter", &airv1.SearchCustomersRequest{}, ""},
+		{"employee IDs",
+			&airv1.SearchCustomersRequest{EmployeeIds: []string{contractEmployeeA}},
+			fmt.Sprintf(`{ and: [{ employeeId: { in: [%q] } }] }`, contractEmployeeA)},
+		{"name contains",
+			&airv1.SearchCustomersRequest{NameContains: "ann"},
+			`{ and: [{ or: [{ firstName: { contains: "ann" } }, { lastName: { contains: "ann" } }] }] }`},
+		{"all conditions",
+			&airv1.SearchCustomersRequest{EmployeeIds: []string{contractEmployeeA, contractEmployeeB}, NameContains: "ann", ActivationStatus: "ACTIVE"},
+			fmt.Sprintf(`{ and: [{ employeeId: { in: [%q, %q] } }, { or: [{ firstName: { contains: "ann" } }, { lastName: { contains: "ann" } }] }, { status: { activation: { eq: ACTIVE } } }] }`, contractEmployeeA, contractEmployeeB)},
+	}
+	for _, search := range searches {
+		t.Run("SearchCustomers "+search.name, func(t *testing.T) {
+			var arguments []string
+			if search.where != "" {
+				arguments = append(arguments, "where: "+search.where)
+			}
+			// Page through two at a time, so cursors are compared too
+			arguments = append(arguments, "first: 2")
+			request := search.request
+			request.First = 2
+
+			for {
+				query := fmt.Sprintf(`{ customerSearch(%s) { totalCount data { %s } paging { hasNextPage endCursor } } }`,
+					strings.Join(arguments, ", "), customerFields)
+				var data struct {
+					CustomerSearch struct {
+						TotalCount int64             `json:"totalCount"`
+						Data       []graphqlCustomer `json:"data"`
+						Paging     struct {
+							HasNextPage bool    `json:"hasNextPage"`
+							EndCursor   *string `json:"endCursor"`
+						} `json:"paging"`
+					} `json:"customerSearch"`
+				}
+				graphql(t, query, &data)
+
+				response, err := customers.SearchCustomers(grpcCtx, request)
+				require.NoError(t, err)
+				page := data.CustomerSearch
+				assertSameCustomers(t, apiCustomers(page.Data), response.GetCustomers())
+				assert.Equal(t, page.TotalCount, response.GetTotalCount())
+				assert.Equal(t, page.Paging.HasNextPage, response.GetHasNextPage())
+
+				if !page.Paging.HasNextPage {
+					break
+				}
+				require.NotNil(t, page.Paging.EndCursor)
+				arguments[len(arguments)-1] = fmt.Sprintf("first: 2, after: %q", *page.Paging.EndCursor)
+				request.After = response.GetEndCursor()
+			}
+		})
+	}
+}
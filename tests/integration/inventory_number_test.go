@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestInventoryByNumberGet verifies the number lookup ignores case, skips deleted inventories,
+// returns null for unknown numbers and fails with CONFLICT on duplicates, once its index exists
+func TestInventoryByNumberGet(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, "inventory_number_test_db")
+	require.NoError(t, client.EnsureIndexes(ctx, resolvers.InventoryIndexes()))
+
+	_, err = client.Collection("inventories").InsertMany(ctx, []interface{}{
+		bson.M{"identifier": "a1b2c3d4-0000-4000-8000-000000000001", "key": "INV-001", "actionIndicator": "NONE"},
+		bson.M{"identifier": "a1b2c3d4-0000-4000-8000-000000000002", "key": "INV-002", "actionIndicator": "DELETE"},
+		bson.M{"identifier": "a1b2c3d4-0000-4000-8000-000000000003", "key": "INV-003", "actionIndicator": "NONE"},
+		bson.M{"identifier": "a1b2c3d4-0000-4000-8000-000000000004", "key": "inv-003", "actionIndicator": "NONE"},
+		bson.M{"identifier": "a1b2c3d4-0000-4000-8000-000000000005", "actionIndicator": "NONE"},
+	})
+	require.NoError(t, err)
+
+	query := resolvers.NewResolver(client).Query()
+	adminCtx := testutil.WithAdminContext(ctx)
+
+	t.Run("found ignoring case", func(t *testing.T) {
+		inventory, err := query.InventoryByNumberGet(adminCtx, "inv-001")
+		require.NoError(t, err)
+		require.NotNil(t, inventory)
+		assert.Equal(t, "a1b2c3d4-0000-4000-8000-000000000001", inventory.Identifier)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		inventory, err := query.InventoryByNumberGet(adminCtx, "INV-404")
+		require.NoError(t, err)
+		assert.Nil(t, inventory)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		inventory, err := query.InventoryByNumberGet(adminCtx, "INV-002")
+		require.NoError(t, err)
+		assert.Nil(t, inventory)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		inventory, err := query.InventoryByNumberGet(adminCtx, "INV-003")
+		assert.Nil(t, inventory)
+		var queryErr *resolvers.QueryError
+		require.ErrorAs(t, err, &queryErr)
+		assert.Equal(t, resolvers.ErrCodeConflict, queryErr.Code)
+		assert.Contains(t, queryErr.Message, "a1b2c3d4-0000-4000-8000-000000000003, a1b2c3d4-0000-4000-8000-000000000004")
+	})
+}
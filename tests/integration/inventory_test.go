@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
 	"github.com/yourusername/air-go/tests/testutil"
 )
 
@@ -130,7 +132,7 @@ func TestInventoryOrderingByCustomerIdASC(t *testing.T) {
 		mongoURI = "mongodb://localhost:27017"
 	}
 
-	db := testutil.SetupTestDB(t, mongoURI)
+	db, commands := testutil.SetupRecordedTestDB(t, mongoURI)
 	defer testutil.TeardownTestDB(t, db)
 
 	testutil.CreateIndexes(t, db)
@@ -200,6 +202,10 @@ func TestInventoryOrderingByCustomerIdASC(t *testing.T) {
 	assert.Equal(t, id2, results[0]["identifier"], "First should be inventory with cust1")
 	assert.Equal(t, id3, results[1]["identifier"], "Second should be inventory with cust2")
 	assert.Equal(t, id1, results[2]["identifier"], "Third should be inventory with cust3")
+
+	// The ordering came from the server in one round trip, not from sorting on the client
+	assert.Equal(t, 1, commands.CommandCount("aggregate"))
+	commands.AssertAggregateContainsStage(t, "inventories", bson.M{"$sort": bson.M{"_sortKey": 1}})
 }
 
 // T034: Test ordering by customerId DESC with MongoDB
@@ -213,7 +219,7 @@ func TestInventoryOrderingByCustomerIdDESC(t *testing.T) {
 		mongoURI = "mongodb://localhost:27017"
 	}
 
-	db := testutil.SetupTestDB(t, mongoURI)
+	db, commands := testutil.SetupRecordedTestDB(t, mongoURI)
 	defer testutil.TeardownTestDB(t, db)
 
 	testutil.CreateIndexes(t, db)
@@ -283,6 +289,10 @@ func TestInventoryOrderingByCustomerIdDESC(t *testing.T) {
 	assert.Equal(t, id2, results[0]["identifier"], "First should be inventory with cust3")
 	assert.Equal(t, id3, results[1]["identifier"], "Second should be inventory with cust2")
 	assert.Equal(t, id1, results[2]["identifier"], "Third should be inventory with cust1")
+
+	// The ordering came from the server in one round trip, not from sorting on the client
+	assert.Equal(t, 1, commands.CommandCount("aggregate"))
+	commands.AssertAggregateContainsStage(t, "inventories", bson.M{"$sort": bson.M{"_sortKey": -1}})
 }
 
 // T035: Test null customerId handling with ASC ordering (nulls last)
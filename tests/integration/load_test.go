@@ -3,6 +3,7 @@ package integration
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -14,6 +15,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 
 	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
 )
 
 // TestConnectionPoolLoad tests connection pool under concurrent load (T099)
@@ -281,3 +284,120 @@ func TestConnectionPoolExhaustion(t *testing.T) {
 	assert.Greater(t, successCount.Load(), int64(0),
 		"Some operations should succeed even with pool pressure")
 }
+
+// TestMixedGetSearchLoad verifies heavy searches are throttled by the search concurrency limit
+// while point reads on the same 10-connection pool keep a low latency
+func TestMixedGetSearchLoad(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := connectMonitoredTestClient(t, uri, "mixed_load_test_db", nil)
+
+	// Enough customers that a full page sorted on an unindexed field is expensive
+	const customerCount = 5000
+	customers := make([]interface{}, customerCount)
+	for i := range customers {
+		customers[i] = bson.M{
+			"identifier": fmt.Sprintf("9a000000-0000-4000-8000-%012d", i),
+			"firstName":  fmt.Sprintf("Customer %d", i),
+			"userEmail":  fmt.Sprintf("%08d@example.com", (i*7919)%customerCount),
+			"status":     bson.M{"deletion": "INIT"},
+		}
+	}
+	_, err = client.Collection("customers").InsertMany(ctx, customers)
+	require.NoError(t, err)
+
+	// Two full pages at a time
+	resolvers.SetSearchConcurrency(2*resolvers.MaxBatchSize, 30*time.Second)
+	t.Cleanup(func() {
+		resolvers.SetSearchConcurrency(resolvers.DefaultSearchMaxConcurrentRows, resolvers.DefaultSearchQueueTimeout)
+	})
+
+	queryResolver := resolvers.NewResolver(client).Query()
+	adminCtx := resolvers.WithUserClaims(ctx, &resolvers.UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+
+	// Sample the limiter while the load runs
+	var peakInUse, peakWaiting atomic.Int64
+	sampling := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		for {
+			select {
+			case <-sampling:
+				return
+			case <-time.After(time.Millisecond):
+			}
+			stats := resolvers.SearchConcurrencyStats()
+			if stats.InUse > peakInUse.Load() {
+				peakInUse.Store(stats.InUse)
+			}
+			if stats.Waiting > peakWaiting.Load() {
+				peakWaiting.Store(stats.Waiting)
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var searchErrors atomic.Int64
+
+	const searchWorkers, searchesPerWorker = 10, 5
+	first := int64(resolvers.MaxBatchSize)
+	desc := generated.SortEnumTypeDesc
+	order := []*generated.CustomerQuerySorterInput{{UserEmail: &desc}}
+	for w := 0; w < searchWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < searchesPerWorker; i++ {
+				if _, err := queryResolver.CustomerSearch(adminCtx, nil, order, &first, nil, nil, nil, nil, nil, nil); err != nil {
+					searchErrors.Add(1)
+					t.Logf("Search failed: %v", err)
+				}
+			}
+		}()
+	}
+
+	const getWorkers, getsPerWorker = 5, 40
+	var latenciesMu sync.Mutex
+	var latencies []time.Duration
+	var getErrors atomic.Int64
+	for w := 0; w < getWorkers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < getsPerWorker; i++ {
+				identifier := fmt.Sprintf("9a000000-0000-4000-8000-%012d", (worker*getsPerWorker+i)%customerCount)
+				start := time.Now()
+				customer, err := queryResolver.CustomerGet(adminCtx, identifier)
+				elapsed := time.Since(start)
+				if err != nil || customer == nil {
+					getErrors.Add(1)
+					continue
+				}
+				latenciesMu.Lock()
+				latencies = append(latencies, elapsed)
+				latenciesMu.Unlock()
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	close(sampling)
+	<-sampled
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	require.NotEmpty(t, latencies)
+	p99 := latencies[(len(latencies)*99)/100]
+	t.Logf("Mixed load: get p99 %v over %d gets, peak search rows %d, peak queued searches %d",
+		p99, len(latencies), peakInUse.Load(), peakWaiting.Load())
+
+	assert.Equal(t, int64(0), searchErrors.Load(), "queued searches complete within the queue timeout")
+	assert.Equal(t, int64(0), getErrors.Load())
+	assert.LessOrEqual(t, peakInUse.Load(), int64(2*resolvers.MaxBatchSize), "searches stay within the limit")
+	assert.Greater(t, peakWaiting.Load(), int64(0), "searches beyond the limit are queued")
+	assert.Less(t, p99, 250*time.Millisecond, "point reads are not starved by heavy searches")
+}
@@ -0,0 +1,194 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/db/migrations"
+)
+
+const migrationsDB = "migrations_test_db"
+
+// testMigrationOptions returns run options with a short lease for tests
+func testMigrationOptions(owner string) migrations.Options {
+	return migrations.Options{LeaseTTL: time.Minute, Timeout: time.Minute, Owner: owner}
+}
+
+// TestMigrations_NormalizeCreateDate runs the createDate migration in dry-run mode and twice for real
+func TestMigrations_NormalizeCreateDate(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, migrationsDB)
+	database := client.Database()
+
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	_, err = client.Collection("customers").InsertMany(ctx, []interface{}{
+		bson.M{"identifier": "c1", "createDate": "2024-05-01T10:30:00Z"},
+		bson.M{"identifier": "c2", "createDate": "2024-05-01T12:30:00+02:00"},
+		bson.M{"identifier": "c3", "createDate": "yesterday"},
+		bson.M{"identifier": "c4", "createDate": created},
+	})
+	require.NoError(t, err)
+	_, err = client.Collection("teams").InsertOne(ctx, bson.M{"identifier": "t1", "createDate": "2024-05-01T10:30:00.123Z"})
+	require.NoError(t, err)
+
+	t.Run("dry run counts without writing", func(t *testing.T) {
+		opts := testMigrationOptions("dry-run")
+		opts.DryRun = true
+		results, err := migrations.Run(ctx, database, migrations.All(), opts, zerolog.Nop())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, int64(4), results[0].Scanned)
+		assert.Equal(t, int64(3), results[0].Updated)
+
+		assert.Equal(t, "2024-05-01T10:30:00Z", createDateOf(t, client, "customers", "c1"))
+		assertNotRecorded(t, client)
+	})
+
+	t.Run("first run converts RFC3339 strings", func(t *testing.T) {
+		results, err := migrations.Run(ctx, database, migrations.All(), testMigrationOptions("first"), zerolog.Nop())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, int64(3), results[0].Updated)
+
+		assert.Equal(t, primitive.NewDateTimeFromTime(created), createDateOf(t, client, "customers", "c1"))
+		assert.Equal(t, primitive.NewDateTimeFromTime(created), createDateOf(t, client, "customers", "c2"))
+		assert.Equal(t, "yesterday", createDateOf(t, client, "customers", "c3"))
+		assert.Equal(t, primitive.NewDateTimeFromTime(created.Add(123*time.Millisecond)), createDateOf(t, client, "teams", "t1"))
+
+		count, err := client.Collection(migrations.CollectionName).CountDocuments(ctx, bson.M{"_id": 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("second run finds nothing pending", func(t *testing.T) {
+		results, err := migrations.Run(ctx, database, migrations.All(), testMigrationOptions("second"), zerolog.Nop())
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("re-running the migration itself changes nothing", func(t *testing.T) {
+		_, err := client.Collection(migrations.CollectionName).DeleteOne(ctx, bson.M{"_id": 1})
+		require.NoError(t, err)
+
+		results, err := migrations.Run(ctx, database, migrations.All(), testMigrationOptions("rerun"), zerolog.Nop())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, int64(1), results[0].Scanned) // Only the unparseable value is still a string
+		assert.Equal(t, int64(0), results[0].Updated)
+	})
+}
+
+// TestMigrations_LeasePreventsConcurrentRuns verifies only one instance runs migrations at a time
+func TestMigrations_LeasePreventsConcurrentRuns(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, migrationsDB)
+	database := client.Database()
+
+	var mu sync.Mutex
+	runs := 0
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := []migrations.Migration{{
+		Version: 1,
+		Name:    "slow",
+		Run: func(ctx context.Context, database db.Database, progress *migrations.Progress) error {
+			mu.Lock()
+			runs++
+			mu.Unlock()
+			close(started)
+			<-release
+			return nil
+		},
+	}}
+
+	t.Run("a second instance is locked out while the first runs", func(t *testing.T) {
+		done := make(chan error, 1)
+		go func() {
+			_, err := migrations.Run(ctx, database, slow, testMigrationOptions("replica-a"), zerolog.Nop())
+			done <- err
+		}()
+		<-started
+
+		_, err := migrations.Run(ctx, database, slow, testMigrationOptions("replica-b"), zerolog.Nop())
+		assert.ErrorIs(t, err, migrations.ErrLocked)
+
+		close(release)
+		require.NoError(t, <-done)
+		assert.Equal(t, 1, runs)
+
+		// The lease is released once the run ends
+		count, err := client.Collection(migrations.CollectionName).CountDocuments(ctx, bson.M{"_id": "lease"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("an expired lease is taken over", func(t *testing.T) {
+		_, err := client.Collection(migrations.CollectionName).InsertOne(ctx, bson.M{
+			"_id":       "lease",
+			"owner":     "crashed-replica",
+			"expiresAt": time.Now().UTC().Add(-time.Minute),
+		})
+		require.NoError(t, err)
+
+		pending := []migrations.Migration{{
+			Version: 2,
+			Name:    "after-crash",
+			Run:     func(context.Context, db.Database, *migrations.Progress) error { return nil },
+		}}
+		results, err := migrations.Run(ctx, database, pending, testMigrationOptions("replica-b"), zerolog.Nop())
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("an unexpired lease of another owner blocks", func(t *testing.T) {
+		_, err := client.Collection(migrations.CollectionName).InsertOne(ctx, bson.M{
+			"_id":       "lease",
+			"owner":     "other-replica",
+			"expiresAt": time.Now().UTC().Add(time.Minute),
+		})
+		require.NoError(t, err)
+
+		pending := []migrations.Migration{{
+			Version: 3,
+			Name:    "blocked",
+			Run:     func(context.Context, db.Database, *migrations.Progress) error { return nil },
+		}}
+		_, err = migrations.Run(ctx, database, pending, testMigrationOptions("replica-b"), zerolog.Nop())
+		assert.ErrorIs(t, err, migrations.ErrLocked)
+	})
+}
+
+// createDateOf returns the stored createDate of a document
+func createDateOf(t *testing.T, client *db.Client, collection, identifier string) interface{} {
+	t.Helper()
+	var doc bson.M
+	require.NoError(t, client.Collection(collection).FindOne(context.Background(), bson.M{"identifier": identifier}).Decode(&doc))
+	return doc["createDate"]
+}
+
+// assertNotRecorded asserts no migration has been recorded as applied
+func assertNotRecorded(t *testing.T, client *db.Client) {
+	t.Helper()
+	count, err := client.Collection(migrations.CollectionName).CountDocuments(context.Background(), bson.M{"_id": bson.M{"$type": "number"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/db/purge"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+const purgeDB = "purge_test_db"
+
+// TestPurge_ExpiredDeletedCustomers seeds deleted customers with backdated deleteDates and
+// verifies a dry run removes nothing, while a real run removes and audits only the expired ones
+func TestPurge_ExpiredDeletedCustomers(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, purgeDB)
+	database := client.Database()
+
+	now := time.Now().UTC()
+	expired := now.Add(-500 * 24 * time.Hour)
+	recent := now.Add(-30 * 24 * time.Hour)
+	_, err = client.Collection("customers").InsertMany(ctx, []interface{}{
+		bson.M{"identifier": "expired-1", "status": bson.M{"deletion": "DELETED"}, purge.DeleteDateField: expired},
+		bson.M{"identifier": "expired-2", "status": bson.M{"deletion": "DELETED"}, purge.DeleteDateField: expired},
+		bson.M{"identifier": "expired-3", "status": bson.M{"deletion": "DELETED"}, purge.DeleteDateField: expired},
+		bson.M{"identifier": "recent", "status": bson.M{"deletion": "DELETED"}, purge.DeleteDateField: recent},
+		bson.M{"identifier": "restored", "status": bson.M{"deletion": "NONE"}, purge.DeleteDateField: expired},
+		bson.M{"identifier": "active", "status": bson.M{"deletion": "NONE"}},
+	})
+	require.NoError(t, err)
+
+	opts := purge.Options{BatchSize: 2, LeaseTTL: time.Minute, Owner: "purge-test"}
+
+	t.Run("dry run counts without deleting", func(t *testing.T) {
+		dryRun := opts
+		dryRun.DryRun = true
+		results, err := purge.Run(ctx, database, resolvers.PurgeTargets(), dryRun, zerolog.Nop())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, int64(3), results[0].Purged)
+
+		assert.Equal(t, int64(6), countDocuments(t, client, "customers", bson.M{}))
+		assert.Equal(t, int64(0), countDocuments(t, client, purge.AuditCollection, bson.M{}))
+	})
+
+	t.Run("run removes and audits expired customers", func(t *testing.T) {
+		results, err := purge.Run(ctx, database, resolvers.PurgeTargets(), opts, zerolog.Nop())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "customer", results[0].Entity)
+		assert.Equal(t, int64(3), results[0].Purged)
+
+		assert.Equal(t, int64(0), countDocuments(t, client, "customers", bson.M{"identifier": bson.M{"$regex": "^expired-"}}))
+		assert.Equal(t, int64(3), countDocuments(t, client, "customers", bson.M{}))
+
+		var record bson.M
+		require.NoError(t, client.Collection(purge.AuditCollection).FindOne(ctx, bson.M{"identifier": "expired-1"}).Decode(&record))
+		assert.Equal(t, "customer", record["entity"])
+		assert.Equal(t, "customers", record["collection"])
+		assert.Equal(t, "purge-test", record["purgedBy"])
+		assert.NotContains(t, record, "status", "audit records keep no entity data")
+		assert.Equal(t, int64(3), countDocuments(t, client, purge.AuditCollection, bson.M{}))
+	})
+
+	t.Run("second run finds nothing expired", func(t *testing.T) {
+		results, err := purge.Run(ctx, database, resolvers.PurgeTargets(), opts, zerolog.Nop())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, int64(0), results[0].Purged)
+	})
+}
+
+// countDocuments counts the documents of a collection matching filter
+func countDocuments(t *testing.T, client *db.Client, collection string, filter bson.M) int64 {
+	t.Helper()
+	count, err := client.Collection(collection).CountDocuments(context.Background(), filter)
+	require.NoError(t, err)
+	return count
+}
@@ -0,0 +1,130 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+const queryCommentJWTSecret = "test-secret-key-at-least-32-characters-long"
+
+// recordedComments returns the comment of every recorded aggregate and find command as
+// "<command>: <comment>", the comment empty for commands without one
+func recordedComments(recorder *testutil.CommandRecorder) []string {
+	var comments []string
+	for _, command := range recorder.Commands("") {
+		if command.Name != "aggregate" && command.Name != "find" {
+			continue
+		}
+		comment, _ := command.Body.Lookup("comment").StringValueOK()
+		comments = append(comments, command.Name+": "+comment)
+	}
+	return comments
+}
+
+// TestQueryComment_CustomerQueries verifies the customerSearch aggregation and the customerGet
+// find carry the operation name, entity and request ID, and carry nothing when query comments
+// are disabled
+func TestQueryComment_CustomerQueries(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	client, comments := connectRecordedTestClient(t, uri, "query_comment_test_db")
+
+	_, err = client.Collection("customers").InsertOne(ctx, bson.M{
+		"identifier": "8c3e1f2a-5b4d-4e6f-9a1b-2c3d4e5f6a70",
+		"firstName":  "Anna",
+		"status":     bson.M{"deletion": "INIT"},
+	})
+	require.NoError(t, err)
+
+	run := func(t *testing.T, enabled bool, requestID, query string) {
+		t.Helper()
+
+		ts := httptest.NewServer(server.New(&config.Config{
+			Port:                 8080,
+			LogFormat:            "json",
+			SchemaPath:           "../../schema.graphqls",
+			JWTSecret:            queryCommentJWTSecret,
+			CORSOrigins:          []string{"*"},
+			QueryCommentsEnabled: enabled,
+		}, server.WithDatabaseClient(client)))
+		defer ts.Close()
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":   "comment-admin",
+			"roles": []string{"ADMIN"},
+		}).SignedString([]byte(queryCommentJWTSecret))
+		require.NoError(t, err)
+
+		body, err := json.Marshal(map[string]string{"query": query})
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/graphql", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Request-ID", requestID)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data   map[string]interface{} `json:"data"`
+			Errors []interface{}          `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Empty(t, result.Errors)
+		require.NotNil(t, result.Data)
+	}
+
+	searchQuery := `query CustomerList { customerSearch { totalCount } }`
+	getQuery := `query CustomerDetail { customerGet(identifier: "8c3e1f2a-5b4d-4e6f-9a1b-2c3d4e5f6a70") { identifier } }`
+
+	t.Run("search enabled", func(t *testing.T) {
+		comments.Reset()
+		run(t, true, "req-42", searchQuery)
+
+		recorded := recordedComments(comments)
+		require.NotEmpty(t, recorded)
+		for _, comment := range recorded {
+			assert.Equal(t, "aggregate: graphql op=CustomerList entity=customers request=req-42", comment)
+		}
+	})
+
+	t.Run("get enabled", func(t *testing.T) {
+		comments.Reset()
+		run(t, true, "req-44", getQuery)
+
+		assert.Equal(t, []string{"find: graphql op=CustomerDetail entity=customers request=req-44"}, recordedComments(comments))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		comments.Reset()
+		run(t, false, "req-43", searchQuery)
+		run(t, false, "req-45", getQuery)
+
+		recorded := recordedComments(comments)
+		require.NotEmpty(t, recorded)
+		for _, comment := range recorded {
+			assert.Contains(t, []string{"aggregate: ", "find: "}, comment)
+		}
+	})
+}
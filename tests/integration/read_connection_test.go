@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+const readConnectionDB = "read_connection_test_db"
+
+const readConnectionCustomerID = "6f1c2b8e-4d3a-4f6b-9c2e-1a7d5e9b3c40"
+
+// TestReadConnection_RoutesReadsToReader verifies search, get and byKeys use the read connection
+// while writes through Collection stay on the primary, and that reads fall back to the primary
+// when no reader is configured
+func TestReadConnection_RoutesReadsToReader(t *testing.T) {
+	ctx := context.Background()
+
+	// Two independent servers stand in for the primary and the read replica
+	_, primaryURI, primaryCleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(primaryCleanup)
+
+	_, readerURI, readerCleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(readerCleanup)
+
+	primaryHost := uriHost(t, primaryURI)
+	readerHost := uriHost(t, readerURI)
+	require.NotEqual(t, primaryHost, readerHost)
+
+	commands := testutil.NewCommandRecorder()
+	primary := connectMonitoredTestClient(t, primaryURI, readConnectionDB, commands.Monitor())
+	reader := connectMonitoredTestClient(t, readerURI, readConnectionDB, commands.Monitor())
+
+	customer := bson.M{"identifier": readConnectionCustomerID, "firstName": "Anna", "status": bson.M{"deletion": "INIT"}}
+	_, err = primary.Collection("customers").InsertOne(ctx, customer)
+	require.NoError(t, err)
+	_, err = reader.Collection("customers").InsertOne(ctx, customer)
+	require.NoError(t, err)
+
+	adminCtx := resolvers.WithUserClaims(ctx, &resolvers.UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+	first := int64(10)
+
+	readAll := func(t *testing.T, queryResolver generated.QueryResolver) {
+		t.Helper()
+		result, err := queryResolver.CustomerSearch(adminCtx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.TotalCount)
+
+		found, err := queryResolver.CustomerGet(adminCtx, readConnectionCustomerID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+
+		byKeys, err := queryResolver.CustomerByKeysGet(adminCtx, []string{readConnectionCustomerID}, nil)
+		require.NoError(t, err)
+		assert.Len(t, byKeys, 1)
+	}
+
+	t.Run("reads use the reader and writes the primary", func(t *testing.T) {
+		primary.SetReader(reader)
+		t.Cleanup(func() { primary.SetReader(nil) })
+		commands.Reset()
+
+		readAll(t, resolvers.NewResolver(primary).Query())
+
+		// search and byKeys aggregate, get uses find
+		assert.Equal(t, []string{readerHost, readerHost}, commandHosts(commands, "aggregate"))
+		assert.Equal(t, []string{readerHost}, commandHosts(commands, "find"))
+
+		_, err := primary.Collection("teams").InsertOne(ctx, bson.M{"identifier": "written"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{primaryHost}, commandHosts(commands, "insert"))
+
+		status, err := primary.HealthStatus(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, status.Reader, "health reports the read connection")
+		assert.Equal(t, "connected", status.Reader.Status)
+	})
+
+	t.Run("without a reader all reads use the primary", func(t *testing.T) {
+		commands.Reset()
+
+		readAll(t, resolvers.NewResolver(primary).Query())
+
+		assert.Equal(t, []string{primaryHost, primaryHost}, commandHosts(commands, "aggregate"))
+		assert.Equal(t, []string{primaryHost}, commandHosts(commands, "find"))
+	})
+}
+
+// commandHosts returns the hosts the recorded commands of the given name were sent to
+func commandHosts(recorder *testutil.CommandRecorder, name string) []string {
+	hosts := []string{}
+	for _, command := range recorder.Commands(name) {
+		hosts = append(hosts, command.Host)
+	}
+	return hosts
+}
+
+// uriHost returns the host:port of a single-host MongoDB URI
+func uriHost(t *testing.T, uri string) string {
+	t.Helper()
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	return parsed.Host
+}
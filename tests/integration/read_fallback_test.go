@@ -0,0 +1,124 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+const readFallbackDB = "read_fallback_test_db"
+
+// readFallbackHostPort is the fixed port of the replica set member, announced so the driver
+// discovers the replica set and routes by read preference
+const readFallbackHostPort = "27217"
+
+// TestReadFallback_SteppedDownPrimary verifies a search still succeeds on a secondary after the
+// primary stepped down when the fallback is enabled, while writes and reads without it fail
+func TestReadFallback_SteppedDownPrimary(t *testing.T) {
+	ctx := context.Background()
+
+	config := DefaultTestContainerConfig()
+	config.ReplicaSet = "rs0"
+	config.HostPort = readFallbackHostPort
+	admin, uri, cleanup, err := StartTestContainerWithConfigAndURI(ctx, config)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	_, err = admin.Database(readFallbackDB).Collection("customers").InsertOne(ctx, bson.M{
+		"identifier": readConnectionCustomerID,
+		"firstName":  "Anna",
+		"status":     bson.M{"deletion": "INIT"},
+	})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var fallbacks []string
+	withFallback := connectReadFallbackClient(t, uri, true)
+	withFallback.SetReadFallbackHook(func(_ context.Context, collection string) {
+		mu.Lock()
+		defer mu.Unlock()
+		fallbacks = append(fallbacks, collection)
+	})
+	withoutFallback := connectReadFallbackClient(t, uri, false)
+
+	// The only member cannot be re-elected for 60s, so the replica set stays without a primary
+	stepDown := bson.D{{Key: "replSetStepDown", Value: 60}, {Key: "force", Value: true}}
+	_ = admin.Database("admin").RunCommand(ctx, stepDown).Err()
+	require.Eventually(t, func() bool {
+		var hello struct {
+			IsWritablePrimary bool `bson:"isWritablePrimary"`
+		}
+		err := admin.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+		return err == nil && !hello.IsWritablePrimary
+	}, 30*time.Second, 250*time.Millisecond, "primary did not step down")
+
+	adminCtx := resolvers.WithUserClaims(ctx, &resolvers.UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+	first := int64(10)
+
+	t.Run("search falls back to the secondary", func(t *testing.T) {
+		result, err := resolvers.NewResolver(withFallback).Query().CustomerSearch(adminCtx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.TotalCount)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Contains(t, fallbacks, "customers", "fallback hook is told about the retried read")
+	})
+
+	t.Run("writes never fall back", func(t *testing.T) {
+		writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		_, err := withFallback.Collection("customers").InsertOne(writeCtx, bson.M{"identifier": "written"})
+		assert.Error(t, err)
+	})
+
+	t.Run("search fails without the fallback", func(t *testing.T) {
+		searchCtx, cancel := context.WithTimeout(adminCtx, 5*time.Second)
+		defer cancel()
+		_, err := resolvers.NewResolver(withoutFallback).Query().CustomerSearch(searchCtx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
+		assert.Error(t, err)
+	})
+}
+
+// connectReadFallbackClient connects a db.Client to the replica set with the read fallback on or off
+func connectReadFallbackClient(t *testing.T, uri string, fallback bool) *db.Client {
+	t.Helper()
+
+	config := &db.DBConfig{
+		URI:                   uri,
+		Database:              readFallbackDB,
+		ConnectTimeout:        10 * time.Second,
+		OperationTimeout:      10 * time.Second,
+		MinPoolSize:           1,
+		MaxPoolSize:           10,
+		MaxConnIdleTime:       5 * time.Minute,
+		MaxRetryAttempts:      1,
+		RetryBaseDelay:        1 * time.Second,
+		RetryMaxDelay:         1 * time.Second,
+		ReadFallbackSecondary: fallback,
+	}
+
+	client, err := db.NewClient(config, zerolog.Nop())
+	require.NoError(t, err)
+
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer connectCancel()
+	require.NoError(t, client.Connect(connectCtx))
+
+	t.Cleanup(func() {
+		disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer disconnectCancel()
+		_ = client.Disconnect(disconnectCtx)
+		client.Close()
+	})
+	return client
+}
@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestReadOnly_ClientRefusesWrites verifies a read-only client refuses every kind of write with a
+// ReadOnlyError, that a customerCreate reaching the database fails with READ_ONLY, and that
+// reads keep working
+func TestReadOnly_ClientRefusesWrites(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, "read_only_test_db")
+	_, err = client.Collection("customers").InsertOne(ctx, bson.M{
+		"identifier": testutil.TestCustomerID1,
+		"firstName":  "Jane",
+		"status":     bson.M{"deletion": "INIT"},
+	})
+	require.NoError(t, err)
+
+	client.SetReadOnly(true)
+	customers := client.Collection("customers")
+
+	writes := map[string]func() error{
+		"insert_one": func() error {
+			_, err := customers.InsertOne(ctx, bson.M{"identifier": testutil.TestCustomerID2})
+			return err
+		},
+		"insert_many": func() error {
+			_, err := customers.InsertMany(ctx, []interface{}{bson.M{"identifier": testutil.TestCustomerID2}})
+			return err
+		},
+		"update_one": func() error {
+			_, err := customers.UpdateOne(ctx, bson.M{"identifier": testutil.TestCustomerID1}, bson.M{"$set": bson.M{"firstName": "Janet"}})
+			return err
+		},
+		"update_many": func() error {
+			_, err := customers.UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"firstName": "Janet"}})
+			return err
+		},
+		"bulk_write": func() error {
+			_, err := customers.BulkWrite(ctx, nil)
+			return err
+		},
+		"delete_one": func() error {
+			_, err := customers.DeleteOne(ctx, bson.M{"identifier": testutil.TestCustomerID1})
+			return err
+		},
+		"delete_many": func() error {
+			_, err := customers.DeleteMany(ctx, bson.M{})
+			return err
+		},
+	}
+	for operation, write := range writes {
+		err := write()
+		var readOnlyErr *db.ReadOnlyError
+		require.True(t, errors.As(err, &readOnlyErr), operation)
+		assert.Equal(t, operation, readOnlyErr.Operation)
+		assert.Equal(t, "customers", readOnlyErr.Collection)
+		assert.ErrorIs(t, err, db.ErrReadOnly, operation)
+	}
+
+	// The resolver maps the refused insert to READ_ONLY
+	resolver := resolvers.NewResolver(client)
+	_, err = resolver.Mutation().CustomerCreate(ctx, generated.CustomerMutationInput{Identifier: testutil.TestCustomerID2})
+	var queryErr *resolvers.QueryError
+	require.True(t, errors.As(err, &queryErr), "%v", err)
+	assert.Equal(t, resolvers.ErrCodeReadOnly, queryErr.Code)
+
+	count, err := customers.CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	customer, err := resolver.Query().CustomerGet(ctx, testutil.TestCustomerID1)
+	require.NoError(t, err)
+	require.NotNil(t, customer)
+	assert.Equal(t, "Jane", *customer.FirstName)
+
+	// Writes go through again once the mode is off
+	client.SetReadOnly(false)
+	_, err = client.Collection("customers").UpdateOne(ctx, bson.M{"identifier": testutil.TestCustomerID1}, bson.M{"$set": bson.M{"firstName": "Janet"}})
+	assert.NoError(t, err)
+}
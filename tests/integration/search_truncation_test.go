@@ -0,0 +1,56 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestSearch_TruncatesOversizedPages searches padded customers whose page does not fit into one
+// 16MB $facet result and verifies the search is retried with smaller pages
+func TestSearch_TruncatesOversizedPages(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, "search_truncation_test_db")
+
+	filler := strings.Repeat("x", 1<<20) // 1MB per document
+	for i := 0; i < 40; i++ {
+		_, err := client.Collection("customers").InsertOne(ctx, bson.M{
+			"identifier": fmt.Sprintf("00000000-0000-4000-8000-%012d", i),
+			"firstName":  fmt.Sprintf("Customer%02d", i),
+			"status":     bson.M{"deletion": "INIT"},
+			"filler":     filler,
+		})
+		require.NoError(t, err)
+	}
+
+	resolver := resolvers.NewResolver(client)
+	adminCtx := testutil.WithAdminContext(ctx)
+
+	// Called outside a GraphQL operation, the search keeps whole documents:
+	// 41 and 21 documents exceed 16MB, 11 fit
+	first := int64(40)
+	result, err := resolver.Query().CustomerSearch(adminCtx, nil, nil, &first, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), result.Count)
+	assert.Equal(t, int64(40), result.TotalCount)
+	assert.True(t, result.Paging.HasNextPage)
+
+	// The next page continues after the truncated one
+	next, err := resolver.Query().CustomerSearch(adminCtx, nil, nil, &first, result.Paging.EndCursor, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, next.Data)
+	assert.NotEqual(t, result.Data[len(result.Data)-1].Identifier, next.Data[0].Identifier)
+}
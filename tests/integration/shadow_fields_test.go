@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+const shadowFieldsDB = "shadow_fields_test_db"
+
+// TestShadowFields_StartsWithUsesIndex verifies the backfill writes the lowercase shadow fields and
+// that startsWith on the shadow field is answered from its index while the fallback scans the collection
+func TestShadowFields_StartsWithUsesIndex(t *testing.T) {
+	ctx := context.Background()
+
+	// Start test container
+	raw, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, shadowFieldsDB)
+	t.Cleanup(func() { resolvers.SetShadowFieldsEnabled(false) })
+
+	// Seed customers, two of them matching the prefix in different cases
+	customers := []interface{}{
+		bson.M{"identifier": "c-anna", "firstName": "Anna", "status": bson.M{"deletion": "INIT"}},
+		bson.M{"identifier": "c-annabel", "firstName": "ANNABEL", "status": bson.M{"deletion": "INIT"}},
+	}
+	for i := 0; i < 200; i++ {
+		customers = append(customers, bson.M{
+			"identifier": fmt.Sprintf("c-%03d", i),
+			"firstName":  fmt.Sprintf("Bob %03d", i),
+			"status":     bson.M{"deletion": "INIT"},
+		})
+	}
+	_, err = client.Collection("customers").InsertMany(ctx, customers)
+	require.NoError(t, err)
+
+	t.Run("backfill writes lowercase copies and is idempotent", func(t *testing.T) {
+		results, err := resolvers.BackfillShadowFields(ctx, client.Database(), "customer")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, int64(len(customers)), results[0].Updated)
+
+		var doc bson.M
+		require.NoError(t, client.Collection("customers").FindOne(ctx, bson.M{"identifier": "c-annabel"}).Decode(&doc))
+		assert.Equal(t, "annabel", doc["firstNameLower"])
+
+		results, err = resolvers.BackfillShadowFields(ctx, client.Database(), "customer")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), results[0].Updated)
+	})
+
+	_, err = raw.Database(shadowFieldsDB).Collection("customers").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "firstNameLower", Value: 1}},
+	})
+	require.NoError(t, err)
+
+	prefix := "ann"
+	where := &generated.CustomerQueryFilterInput{FirstName: &generated.StringFilterInput{StartsWith: &prefix}}
+	first := int64(10)
+	queryResolver := resolvers.NewResolver(client).Query()
+	adminCtx := resolvers.WithUserClaims(ctx, &resolvers.UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+
+	explain := func(t *testing.T) resolvers.ExplainSummary {
+		t.Helper()
+		explainCtx := resolvers.WithExplain(adminCtx)
+		_, err := queryResolver.CustomerSearch(explainCtx, where, nil, &first, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		summaries := resolvers.ExplainSummaries(explainCtx)
+		require.Len(t, summaries, 1)
+		for _, summary := range summaries {
+			return summary
+		}
+		return resolvers.ExplainSummary{}
+	}
+
+	t.Run("shadow field path uses the index", func(t *testing.T) {
+		resolvers.SetShadowFieldsEnabled(true)
+
+		summary := explain(t)
+		assert.Equal(t, "firstNameLower_1", summary.IndexUsed)
+		assert.False(t, summary.CollectionScan)
+
+		result, err := queryResolver.CustomerSearch(adminCtx, where, nil, &first, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+	})
+
+	t.Run("fallback path scans the collection", func(t *testing.T) {
+		resolvers.SetShadowFieldsEnabled(false)
+
+		summary := explain(t)
+		assert.Empty(t, summary.IndexUsed)
+		assert.True(t, summary.CollectionScan)
+
+		result, err := queryResolver.CustomerSearch(adminCtx, where, nil, &first, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+	})
+}
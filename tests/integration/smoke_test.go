@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/health"
+	"github.com/yourusername/air-go/internal/smoke"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+const smokeDB = "smoke_test_db"
+
+// checkOutcomes maps the checks of a report to passed, skipped or failed
+func checkOutcomes(report smoke.Report) map[string]string {
+	outcomes := map[string]string{}
+	for _, check := range report.Checks {
+		switch {
+		case check.Skipped:
+			outcomes[check.Name] = "skipped"
+		case check.Passed:
+			outcomes[check.Name] = "passed"
+		default:
+			outcomes[check.Name] = "failed"
+		}
+	}
+	return outcomes
+}
+
+// TestSmoke_SeededDeployment runs the self-test against seeded data, and with a missing customer,
+// a wrong schema checksum and a spent time budget
+func TestSmoke_SeededDeployment(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup) // Runs after the client disconnects
+
+	client := connectTestClient(t, uri, smokeDB)
+	_, err = client.Collection("customers").InsertOne(ctx, bson.M{
+		"identifier": testutil.TestCustomerID1,
+		"firstName":  "Jane",
+		"status":     bson.M{"deletion": "INIT"},
+		"createDate": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	resolver := resolvers.NewResolver(client)
+	resolver.ServiceInfo = &health.ServiceInfo{Schema: &health.SchemaInfo{Checksum: "abc123"}}
+
+	t.Run("passes against a healthy deployment", func(t *testing.T) {
+		report := smoke.Run(ctx, resolver, smoke.Options{CustomerID: testutil.TestCustomerID1, SchemaChecksum: "abc123"})
+		assert.True(t, report.Passed, "%+v", report)
+		assert.Equal(t, map[string]string{
+			"health":         "passed",
+			"schemaChecksum": "passed",
+			"customerGet":    "passed",
+			"customerSearch": "passed",
+		}, checkOutcomes(report))
+	})
+
+	t.Run("skips checks without configuration", func(t *testing.T) {
+		report := smoke.Run(ctx, resolver, smoke.Options{})
+		assert.True(t, report.Passed)
+		assert.Equal(t, "skipped", checkOutcomes(report)["customerGet"])
+		assert.Equal(t, "skipped", checkOutcomes(report)["schemaChecksum"])
+	})
+
+	t.Run("fails for a missing customer and another schema", func(t *testing.T) {
+		report := smoke.Run(ctx, resolver, smoke.Options{CustomerID: testutil.TestCustomerID2, SchemaChecksum: "other"})
+		assert.False(t, report.Passed)
+		assert.Equal(t, "failed", checkOutcomes(report)["customerGet"])
+		assert.Equal(t, "failed", checkOutcomes(report)["schemaChecksum"])
+		assert.Equal(t, "passed", checkOutcomes(report)["customerSearch"])
+	})
+
+	t.Run("fails once the time budget is spent", func(t *testing.T) {
+		report := smoke.Run(ctx, resolver, smoke.Options{CustomerID: testutil.TestCustomerID1, Timeout: time.Nanosecond})
+		assert.False(t, report.Passed)
+	})
+}
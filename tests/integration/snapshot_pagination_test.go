@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// snapshotCustomer builds a customer document for the snapshot pagination tests
+func snapshotCustomer(n int, firstName string) bson.M {
+	return bson.M{
+		"identifier":      fmt.Sprintf("550e8400-e29b-41d4-a716-4466554400%02d", n),
+		"firstName":       firstName,
+		"actionIndicator": "NONE",
+		"status":          bson.M{"deletion": "INIT"},
+	}
+}
+
+// customerFirstNames returns the first names of a search page
+func customerFirstNames(page *generated.QueryOutputOfCustomer) []string {
+	names := make([]string, 0, len(page.Data))
+	for _, customer := range page.Data {
+		names = append(names, *customer.FirstName)
+	}
+	return names
+}
+
+// TestSearchSnapshot_IgnoresWritesBetweenPages verifies a snapshot pagination keeps reading the
+// data as of its first page while a regular pagination sees documents inserted between pages
+func TestSearchSnapshot_IgnoresWritesBetweenPages(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartReplicaSetTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := connectTestClient(t, uri, "snapshot_test_db")
+
+	customers := make([]interface{}, 0, 6)
+	for i := 1; i <= 6; i++ {
+		customers = append(customers, snapshotCustomer(i, fmt.Sprintf("Customer %d", i)))
+	}
+	_, err = client.Collection("customers").InsertMany(ctx, customers)
+	require.NoError(t, err)
+
+	queryResolver := resolvers.NewResolver(client).Query()
+	adminCtx := testutil.WithAdminContext(ctx)
+	asc := generated.SortEnumTypeAsc
+	order := []*generated.CustomerQuerySorterInput{{FirstName: &asc}}
+	first := int64(3)
+	snapshot := true
+
+	snapshotPage, err := queryResolver.CustomerSearch(adminCtx, nil, order, &first, nil, nil, nil, &snapshot, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Customer 1", "Customer 2", "Customer 3"}, customerFirstNames(snapshotPage))
+
+	regularPage, err := queryResolver.CustomerSearch(adminCtx, nil, order, &first, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Customer 1", "Customer 2", "Customer 3"}, customerFirstNames(regularPage))
+
+	// A customer inserted mid-pagination sorts onto the second page
+	_, err = client.Collection("customers").InsertOne(ctx, snapshotCustomer(7, "Customer 3b"))
+	require.NoError(t, err)
+
+	t.Run("snapshot pagination does not see the insert", func(t *testing.T) {
+		page, err := queryResolver.CustomerSearch(adminCtx, nil, order, &first, snapshotPage.Paging.EndCursor, nil, nil, &snapshot, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Customer 4", "Customer 5", "Customer 6"}, customerFirstNames(page))
+		assert.Equal(t, int64(6), page.TotalCount)
+		assert.False(t, page.Paging.HasNextPage)
+
+		// Paging back stays in the same snapshot
+		page, err = queryResolver.CustomerSearch(adminCtx, nil, order, nil, nil, &first, page.Paging.StartCursor, &snapshot, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Customer 1", "Customer 2", "Customer 3"}, customerFirstNames(page))
+	})
+
+	t.Run("regular pagination sees the insert", func(t *testing.T) {
+		page, err := queryResolver.CustomerSearch(adminCtx, nil, order, &first, regularPage.Paging.EndCursor, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Customer 3b", "Customer 4", "Customer 5"}, customerFirstNames(page))
+		assert.Equal(t, int64(7), page.TotalCount)
+	})
+
+	t.Run("cursors do not cross snapshot modes", func(t *testing.T) {
+		_, err := queryResolver.CustomerSearch(adminCtx, nil, order, &first, snapshotPage.Paging.EndCursor, nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "different snapshot mode")
+
+		_, err = queryResolver.CustomerSearch(adminCtx, nil, order, &first, regularPage.Paging.EndCursor, nil, nil, &snapshot, nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "different snapshot mode")
+	})
+}
+
+// TestSearchSnapshot_StandaloneRejected verifies standalone servers report that snapshot
+// pagination is unsupported
+func TestSearchSnapshot_StandaloneRejected(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := connectTestClient(t, uri, "snapshot_standalone_db")
+	queryResolver := resolvers.NewResolver(client).Query()
+	snapshot := true
+
+	_, err = queryResolver.CustomerSearch(testutil.WithAdminContext(ctx), nil, nil, nil, nil, nil, nil, &snapshot, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "snapshot pagination requires a replica set or sharded cluster")
+}
+
+// TestAggregateSnapshot_DrainsBatches verifies a snapshot aggregate larger than one batch is
+// drained through getMores sent in the session that opened its cursor
+func TestAggregateSnapshot_DrainsBatches(t *testing.T) {
+	ctx := context.Background()
+
+	_, uri, cleanup, err := StartReplicaSetTestContainerWithURI(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	client, commands := connectRecordedTestClient(t, uri, "snapshot_batches_db")
+
+	// The first batch holds 101 documents, so 250 need further batches
+	customers := make([]interface{}, 0, 250)
+	for i := 0; i < 250; i++ {
+		customers = append(customers, bson.M{"n": i})
+	}
+	_, err = client.Collection("customers").InsertMany(ctx, customers)
+	require.NoError(t, err)
+	commands.Reset()
+
+	docs, _, err := client.Collection("customers").AggregateSnapshot(ctx, bson.A{bson.M{"$sort": bson.M{"n": 1}}}, nil)
+	require.NoError(t, err)
+	require.Len(t, docs, 250)
+	for i, doc := range docs {
+		assert.Equal(t, int32(i), doc.Lookup("n").Int32())
+	}
+
+	aggregates := commands.Commands("aggregate")
+	getMores := commands.Commands("getMore")
+	require.Len(t, aggregates, 1)
+	require.NotEmpty(t, getMores)
+	session := aggregates[0].Body.Lookup("lsid")
+	for _, getMore := range getMores {
+		assert.Equal(t, session, getMore.Body.Lookup("lsid"))
+	}
+}
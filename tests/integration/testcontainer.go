@@ -7,6 +7,7 @@ import (
 
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -18,6 +19,8 @@ type TestContainerConfig struct {
 	Database     string        // Test database name (default: test_db)
 	CleanupMode  string        // cleanup mode: "drop" (default) or "terminate"
 	StartTimeout time.Duration // Container startup timeout (default: 60s)
+	ReplicaSet   string        // Replica set name; empty starts a standalone server (default: "")
+	HostPort     string        // Fixed port the server listens on and is published at; lets clients discover the replica set instead of connecting directly (default: "")
 }
 
 // DefaultTestContainerConfig returns default configuration for test containers
@@ -46,6 +49,15 @@ func StartTestContainerWithURI(ctx context.Context) (*mongo.Client, string, func
 	return client, uri, cleanup, err
 }
 
+// StartReplicaSetTestContainerWithURI starts a single-member replica set test container
+// Replica sets support features standalone servers lack, such as snapshot reads
+// Returns: (*mongo.Client, URI string, cleanup function, error)
+func StartReplicaSetTestContainerWithURI(ctx context.Context) (*mongo.Client, string, func(), error) {
+	config := DefaultTestContainerConfig()
+	config.ReplicaSet = "rs0"
+	return StartTestContainerWithConfigAndURI(ctx, config)
+}
+
 // StartTestContainerWithConfigAndURI starts a MongoDB test container with custom configuration
 // Returns: (*mongo.Client, URI string, cleanup function, error)
 func StartTestContainerWithConfigAndURI(ctx context.Context, config *TestContainerConfig) (*mongo.Client, string, func(), error) {
@@ -63,6 +75,16 @@ func StartTestContainerWithConfigAndURI(ctx context.Context, config *TestContain
 			"MONGO_INITDB_DATABASE": config.Database,
 		},
 	}
+	if config.ReplicaSet != "" {
+		req.Cmd = []string{"--replSet", config.ReplicaSet, "--bind_ip_all"}
+	}
+	containerPort := "27017"
+	if config.HostPort != "" {
+		// The member announces localhost:HostPort, which must reach it from the host as well
+		containerPort = config.HostPort
+		req.Cmd = append(req.Cmd, "--port", config.HostPort)
+		req.ExposedPorts = []string{config.HostPort + ":" + config.HostPort + "/tcp"}
+	}
 
 	// Start container
 	mongoContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
@@ -80,18 +102,34 @@ func StartTestContainerWithConfigAndURI(ctx context.Context, config *TestContain
 		return nil, "", nil, fmt.Errorf("failed to get container host: %w", err)
 	}
 
-	mappedPort, err := mongoContainer.MappedPort(ctx, "27017")
-	if err != nil {
-		mongoContainer.Terminate(ctx)
-		return nil, "", nil, fmt.Errorf("failed to get mapped port: %w", err)
+	// A fixed host port is published as is
+	port := config.HostPort
+	if port == "" {
+		mappedPort, err := mongoContainer.MappedPort(ctx, "27017")
+		if err != nil {
+			mongoContainer.Terminate(ctx)
+			return nil, "", nil, fmt.Errorf("failed to get mapped port: %w", err)
+		}
+		port = mappedPort.Port()
 	}
 
 	// Build connection string
-	uri := fmt.Sprintf("mongodb://%s:%s", host, mappedPort.Port())
+	// The replica set member is only reachable through the mapped port, so connect directly,
+	// unless it listens on a fixed host port it can announce
+	uri := fmt.Sprintf("mongodb://%s:%s", host, port)
+	directURI := uri
+	if config.ReplicaSet != "" {
+		directURI += "/?directConnection=true"
+		uri = directURI
+		if config.HostPort != "" {
+			uri = fmt.Sprintf("mongodb://localhost:%s/?replicaSet=%s", config.HostPort, config.ReplicaSet)
+		}
+	}
 
 	// Connect to MongoDB
+	// The replica set is not initiated yet, so the setup client always connects directly
 	clientOptions := options.Client().
-		ApplyURI(uri).
+		ApplyURI(directURI).
 		SetServerSelectionTimeout(10 * time.Second).
 		SetConnectTimeout(10 * time.Second)
 
@@ -101,6 +139,14 @@ func StartTestContainerWithConfigAndURI(ctx context.Context, config *TestContain
 		return nil, "", nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
+	if config.ReplicaSet != "" {
+		if err := initiateReplicaSet(ctx, client, config.ReplicaSet, "localhost:"+containerPort, config.StartTimeout); err != nil {
+			client.Disconnect(ctx)
+			mongoContainer.Terminate(ctx)
+			return nil, "", nil, err
+		}
+	}
+
 	// Verify connection
 	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	err = client.Ping(pingCtx, nil)
@@ -135,6 +181,32 @@ func StartTestContainerWithConfigAndURI(ctx context.Context, config *TestContain
 	return client, uri, cleanup, nil
 }
 
+// initiateReplicaSet initiates a single-member replica set announcing the member as host and
+// waits until the member is primary
+func initiateReplicaSet(ctx context.Context, client *mongo.Client, name, host string, timeout time.Duration) error {
+	admin := client.Database("admin")
+
+	initiate := bson.D{{Key: "replSetInitiate", Value: bson.M{
+		"_id":     name,
+		"members": []bson.M{{"_id": 0, "host": host}},
+	}}}
+	if err := admin.RunCommand(ctx, initiate).Err(); err != nil {
+		return fmt.Errorf("failed to initiate replica set: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		var hello struct {
+			IsWritablePrimary bool `bson:"isWritablePrimary"`
+		}
+		if err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err == nil && hello.IsWritablePrimary {
+			return nil
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	return fmt.Errorf("replica set %s did not elect a primary within %s", name, timeout)
+}
+
 // StartTestContainerWithConfig starts a MongoDB test container with custom configuration
 func StartTestContainerWithConfig(ctx context.Context, config *TestContainerConfig) (*mongo.Client, func(), error) {
 	if config == nil {
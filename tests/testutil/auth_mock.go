@@ -12,6 +12,8 @@ type MockUserClaims struct {
 	Email       string
 	Roles       []string
 	Permissions []string
+	Teams       []string
+	Groups      []string
 }
 
 // contextKey is a type for context keys to avoid collisions
@@ -30,6 +32,8 @@ func WithAuthContext(ctx context.Context, claims *MockUserClaims) context.Contex
 		Email:       claims.Email,
 		Roles:       claims.Roles,
 		Permissions: claims.Permissions,
+		Teams:       claims.Teams,
+		Groups:      claims.Groups,
 	}
 	return resolvers.WithUserClaims(ctx, resolverClaims)
 }
@@ -67,6 +71,18 @@ func WithAdvisorContext(ctx context.Context) context.Context {
 	return WithAuthContext(ctx, claims)
 }
 
+// WithSupportAgentContext returns a context with support agent claims scoped to the given teams
+func WithSupportAgentContext(ctx context.Context, userID string, teams ...string) context.Context {
+	claims := &MockUserClaims{
+		UserID:      userID,
+		Email:       userID + "@test.com",
+		Roles:       []string{"SUPPORT_AGENT"},
+		Permissions: []string{"READ_CUSTOMERS"},
+		Teams:       teams,
+	}
+	return WithAuthContext(ctx, claims)
+}
+
 // UnauthenticatedContext returns a context without any user claims
 func UnauthenticatedContext(ctx context.Context) context.Context {
 	return ctx
@@ -0,0 +1,100 @@
+package testutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// Ensure *FakeClock implements db.Clock
+var _ db.Clock = (*FakeClock)(nil)
+
+// FakeClock is a db.Clock whose time only moves when Advance is called, or by its tick on
+// every Now call when created with NewTickingClock
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tick    time.Duration
+	waiters []fakeTimer
+}
+
+// fakeTimer is a pending After channel
+type fakeTimer struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFakeClock creates a fake clock starting at the given time
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// NewTickingClock creates a fake clock starting at the given time that moves forward by tick
+// after every Now call, so successive writes get deterministic, strictly increasing timestamps.
+// Ticks do not fire After channels
+func NewTickingClock(start time.Time, tick time.Duration) *FakeClock {
+	return &FakeClock{now: start, tick: tick}
+}
+
+// Now returns the fake current time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.tick)
+	return now
+}
+
+// After returns a channel that fires once the clock has been advanced by d.
+// A non-positive d fires immediately
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	deadline := c.now.Add(d)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeTimer{deadline: deadline, ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward and fires every timer whose deadline has been reached
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.deadline.After(c.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+		waiter.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// Waiters returns the number of After channels that have not fired yet
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntilWaiters waits until at least n After channels are pending, so a test can
+// advance the clock only once the code under test has started waiting. Gives up after timeout
+func (c *FakeClock) BlockUntilWaiters(n int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if c.Waiters() >= n {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// RecordedCommand is a command a client sent to MongoDB
+type RecordedCommand struct {
+	Name       string   // Command name, e.g. find or aggregate
+	Database   string   // Database the command ran against
+	Collection string   // Collection named by the command, "" for commands without one
+	Host       string   // host:port of the server the command was sent to
+	Body       bson.Raw // Full command document as sent
+}
+
+// CommandRecorder records the commands a client starts, so tests can assert what was sent to
+// MongoDB: pipeline stages, projections, comments, hints or the number of round trips. Install
+// its Monitor on the client before it connects
+type CommandRecorder struct {
+	mu       sync.Mutex
+	commands []RecordedCommand
+}
+
+// NewCommandRecorder creates an empty command recorder
+func NewCommandRecorder() *CommandRecorder {
+	return &CommandRecorder{}
+}
+
+// Monitor returns the driver command monitor recording into r
+func (r *CommandRecorder) Monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			command := RecordedCommand{
+				Name:     e.CommandName,
+				Database: e.DatabaseName,
+				// The driver reuses the command's buffer once the event returns
+				Body: append(bson.Raw(nil), e.Command...),
+			}
+			command.Collection, _ = command.Body.Lookup(e.CommandName).StringValueOK()
+			// ConnectionID is host:port[-n]
+			command.Host, _, _ = strings.Cut(e.ConnectionID, "[")
+
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.commands = append(r.commands, command)
+		},
+	}
+}
+
+// Reset forgets the commands recorded so far, e.g. those of a test's setup
+func (r *CommandRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = nil
+}
+
+// Commands returns the recorded commands with the given name, in the order they started; all
+// recorded commands for ""
+func (r *CommandRecorder) Commands(name string) []RecordedCommand {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var commands []RecordedCommand
+	for _, command := range r.commands {
+		if name == "" || command.Name == name {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// CommandCount returns how many commands with the given name were recorded, all for ""
+func (r *CommandRecorder) CommandCount(name string) int {
+	return len(r.Commands(name))
+}
+
+// AssertAggregateContainsStage asserts an aggregate on the collection sent a pipeline with the
+// stage, e.g. bson.M{"$sort": bson.M{"_sortKey": 1}}. Documents are compared regardless of
+// their key order; assert on Commands to check the order of a multi-key $sort
+func (r *CommandRecorder) AssertAggregateContainsStage(t testing.TB, collection string, stage interface{}) bool {
+	t.Helper()
+	expected, err := normalizedDocument(stage)
+	if err != nil {
+		return assert.Fail(t, "invalid expected stage", err.Error())
+	}
+
+	var sent []interface{}
+	for _, command := range r.Commands("aggregate") {
+		if command.Collection != collection {
+			continue
+		}
+		stages, ok := command.Body.Lookup("pipeline").ArrayOK()
+		if !ok {
+			continue
+		}
+		values, err := stages.Values()
+		if err != nil {
+			return assert.Fail(t, "invalid recorded pipeline", err.Error())
+		}
+		for _, value := range values {
+			document, ok := value.DocumentOK()
+			if !ok {
+				continue
+			}
+			actual, err := normalizedDocument(document)
+			if err != nil {
+				return assert.Fail(t, "invalid recorded stage", err.Error())
+			}
+			if assert.ObjectsAreEqual(expected, actual) {
+				return true
+			}
+			sent = append(sent, actual)
+		}
+	}
+	return assert.Fail(t, fmt.Sprintf("no aggregate on %s contains the stage %v", collection, expected), "stages sent: %v", sent)
+}
+
+// AssertFindProjection asserts a find on the collection sent the projection, compared
+// regardless of key order
+func (r *CommandRecorder) AssertFindProjection(t testing.TB, collection string, projection interface{}) bool {
+	t.Helper()
+	expected, err := normalizedDocument(projection)
+	if err != nil {
+		return assert.Fail(t, "invalid expected projection", err.Error())
+	}
+
+	var sent []interface{}
+	for _, command := range r.Commands("find") {
+		if command.Collection != collection {
+			continue
+		}
+		document, ok := command.Body.Lookup("projection").DocumentOK()
+		if !ok {
+			sent = append(sent, nil)
+			continue
+		}
+		actual, err := normalizedDocument(document)
+		if err != nil {
+			return assert.Fail(t, "invalid recorded projection", err.Error())
+		}
+		if assert.ObjectsAreEqual(expected, actual) {
+			return true
+		}
+		sent = append(sent, actual)
+	}
+	return assert.Fail(t, fmt.Sprintf("no find on %s sent the projection %v", collection, expected), "projections sent: %v", sent)
+}
+
+// normalizedDocument decodes a document, or a value marshalling to one, into nested bson.M
+// values. Both sides of a comparison pass the encoder, so Go ints and int32 compare equal
+func normalizedDocument(document interface{}) (bson.M, error) {
+	raw, ok := document.(bson.Raw)
+	if !ok {
+		marshalled, err := bson.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+		raw = marshalled
+	}
+
+	decoder, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	decoder.DefaultDocumentM()
+	var normalized bson.M
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
@@ -12,10 +12,24 @@ import (
 // SetupTestDB creates a test MongoDB connection
 // For integration tests, this connects to a real MongoDB instance (testcontainers or local)
 func SetupTestDB(t *testing.T, uri string) *mongo.Database {
+	t.Helper()
+	return setupTestDB(t, options.Client().ApplyURI(uri))
+}
+
+// SetupRecordedTestDB is SetupTestDB recording the commands sent through the connection, for
+// tests asserting the shape of the queries
+func SetupRecordedTestDB(t *testing.T, uri string) (*mongo.Database, *CommandRecorder) {
+	t.Helper()
+	recorder := NewCommandRecorder()
+	return setupTestDB(t, options.Client().ApplyURI(uri).SetMonitor(recorder.Monitor())), recorder
+}
+
+// setupTestDB connects with the client options and returns the test database
+func setupTestDB(t *testing.T, clientOptions *options.ClientOptions) *mongo.Database {
 	t.Helper()
 	ctx := context.Background()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, err := mongo.Connect(ctx, clientOptions)
 	require.NoError(t, err, "Failed to connect to test database")
 
 	// Verify connection
@@ -0,0 +1,203 @@
+package testutil
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// Ensure the mocks implement the interfaces the resolvers depend on
+var (
+	_ db.DBClient   = (*MockDBClient)(nil)
+	_ db.Collection = (*MockCollection)(nil)
+)
+
+// MockDBClient is a mock of db.DBClient
+type MockDBClient struct {
+	mock.Mock
+}
+
+// HealthStatus mocks the HealthStatus method
+func (m *MockDBClient) HealthStatus(ctx context.Context) (*db.HealthStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.HealthStatus), args.Error(1)
+}
+
+// Collection mocks the Collection method
+func (m *MockDBClient) Collection(name string) db.Collection {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(db.Collection)
+}
+
+// ReadCollection shares the Collection expectations; the mock has no separate read connection
+func (m *MockDBClient) ReadCollection(name string) db.Collection {
+	return m.Collection(name)
+}
+
+// IsConnected mocks the IsConnected method
+func (m *MockDBClient) IsConnected() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+// MockCollection is a mock of db.Collection
+type MockCollection struct {
+	mock.Mock
+}
+
+// InsertOne mocks the InsertOne method
+func (m *MockCollection) InsertOne(ctx context.Context, document interface{}) (*mongo.InsertOneResult, error) {
+	args := m.Called(ctx, document)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.InsertOneResult), args.Error(1)
+}
+
+// InsertMany mocks the InsertMany method
+func (m *MockCollection) InsertMany(ctx context.Context, documents []interface{}) (*mongo.InsertManyResult, error) {
+	args := m.Called(ctx, documents)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.InsertManyResult), args.Error(1)
+}
+
+// FindOne mocks the FindOne method; return a SingleResult to decode
+func (m *MockCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*mongo.SingleResult)
+}
+
+// Find mocks the Find method; return a Cursor to iterate
+func (m *MockCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	args := m.Called(ctx, filter, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.Cursor), args.Error(1)
+}
+
+// UpdateOne mocks the UpdateOne method
+func (m *MockCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	args := m.Called(ctx, filter, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.UpdateResult), args.Error(1)
+}
+
+// UpdateMany mocks the UpdateMany method
+func (m *MockCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	args := m.Called(ctx, filter, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.UpdateResult), args.Error(1)
+}
+
+// BulkWrite mocks the BulkWrite method
+func (m *MockCollection) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	args := m.Called(ctx, models, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.BulkWriteResult), args.Error(1)
+}
+
+// DeleteOne mocks the DeleteOne method
+func (m *MockCollection) DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.DeleteResult), args.Error(1)
+}
+
+// DeleteMany mocks the DeleteMany method
+func (m *MockCollection) DeleteMany(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.DeleteResult), args.Error(1)
+}
+
+// CountDocuments mocks the CountDocuments method
+func (m *MockCollection) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Aggregate mocks the Aggregate method; return a Cursor to iterate
+func (m *MockCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	args := m.Called(ctx, pipeline, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.Cursor), args.Error(1)
+}
+
+// ExplainAggregate mocks the ExplainAggregate method
+func (m *MockCollection) ExplainAggregate(ctx context.Context, pipeline interface{}, verbosity string) (bson.M, error) {
+	args := m.Called(ctx, pipeline, verbosity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(bson.M), args.Error(1)
+}
+
+// AggregateSnapshot mocks the AggregateSnapshot method
+func (m *MockCollection) AggregateSnapshot(ctx context.Context, pipeline interface{}, atClusterTime *primitive.Timestamp) ([]bson.Raw, primitive.Timestamp, error) {
+	args := m.Called(ctx, pipeline, atClusterTime)
+	if args.Get(0) == nil {
+		return nil, primitive.Timestamp{}, args.Error(2)
+	}
+	return args.Get(0).([]bson.Raw), args.Get(1).(primitive.Timestamp), args.Error(2)
+}
+
+// IndexKeys mocks the IndexKeys method
+func (m *MockCollection) IndexKeys(ctx context.Context) ([]bson.D, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]bson.D), args.Error(1)
+}
+
+// Name mocks the Name method
+func (m *MockCollection) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+// FoundResult is the result of a FindOne that found document, decoded with the client's registry
+func FoundResult(document interface{}) *mongo.SingleResult {
+	return mongo.NewSingleResultFromDocument(document, nil, db.Registry)
+}
+
+// NotFoundResult is the result of a FindOne that matched nothing
+func NotFoundResult() *mongo.SingleResult {
+	return mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+}
+
+// CursorOf returns a cursor over documents, as Find and Aggregate return them
+func CursorOf(documents ...interface{}) *mongo.Cursor {
+	cursor, err := mongo.NewCursorFromDocuments(documents, nil, db.Registry)
+	if err != nil {
+		panic(err)
+	}
+	return cursor
+}
@@ -0,0 +1,41 @@
+// Package leakcheck fails test binaries that leave cursors open. It lives apart from testutil,
+// which imports the resolvers, so the resolvers' own tests can use it too
+package leakcheck
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// Detector compares the open cursor count against the count when it was started
+type Detector struct {
+	baseline int64
+}
+
+// Start records the cursors open now; only cursors opened afterwards count as leaks
+func Start() *Detector {
+	return &Detector{baseline: db.OpenCursors()}
+}
+
+// Err reports the cursors opened since Start that are still open
+func (d *Detector) Err() error {
+	if leaked := db.OpenCursors() - d.baseline; leaked > 0 {
+		return fmt.Errorf("%d cursor(s) left open; close every cursor, e.g. through db.WithCursor", leaked)
+	}
+	return nil
+}
+
+// VerifyTestMain runs the tests and exits, failing a passing run that left cursors open.
+// Call it from TestMain: func TestMain(m *testing.M) { leakcheck.VerifyTestMain(m) }
+func VerifyTestMain(m *testing.M) {
+	detector := Start()
+	code := m.Run()
+	if err := detector.Err(); err != nil && code == 0 {
+		fmt.Fprintln(os.Stderr, "leakcheck:", err)
+		code = 1
+	}
+	os.Exit(code)
+}
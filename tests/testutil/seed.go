@@ -10,6 +10,19 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// SeedEpoch is the createDate of the first entity seeded by the helpers
+var SeedEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// seedClock hands out the createDate of seeded entities, a millisecond apart so the default
+// createDate order of searches is deterministic
+var seedClock = NewTickingClock(SeedEpoch, time.Millisecond)
+
+// NextCreateDate returns the createDate of the next seeded entity: a BSON date after every one
+// returned before, as the server stores createDate
+func NextCreateDate() time.Time {
+	return seedClock.Now()
+}
+
 // InventoryTestData represents test inventory data
 type InventoryTestData struct {
 	Identifier     string
@@ -64,7 +77,7 @@ func CreateTestInventory(identifier, key string, deleted bool) InventoryTestData
 		ActionIndicator: actionIndicator,
 		IsConsistent:    true,
 		IsComplete:      true,
-		CreateDate:      time.Now(),
+		CreateDate:      NextCreateDate(),
 		CreatedByUser:   "test-user",
 	}
 }
@@ -0,0 +1,137 @@
+package airctl_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/airctl"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// filterFiles serves filter files from memory
+func filterFiles(files map[string]string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		content, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+}
+
+// TestParseArgs_Get verifies get takes an entity and an identifier
+func TestParseArgs_Get(t *testing.T) {
+	cmd, err := airctl.ParseArgs([]string{"get", "customer", "7c9e6679-7425-40de-944b-e07fc1f90ae7", "--output", "table"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &airctl.Command{
+		Name:       airctl.CommandGet,
+		Entity:     "customer",
+		Identifier: "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		Output:     airctl.OutputTable,
+	}, cmd)
+
+	_, err = airctl.ParseArgs([]string{"get", "customer"}, nil)
+	assert.ErrorContains(t, err, "expected <entity> <identifier>")
+}
+
+// TestParseArgs_Search verifies search flags before and after the entity, the filter file and the sort
+func TestParseArgs_Search(t *testing.T) {
+	files := filterFiles(map[string]string{"f.json": `{"firstName":{"startsWith":"Jan"}}`})
+
+	cmd, err := airctl.ParseArgs([]string{"search", "--limit", "20", "customer", "--filter-file", "f.json", "--sort", "createDate:desc, lastName"}, files)
+	require.NoError(t, err)
+	assert.Equal(t, "customer", cmd.Entity)
+	assert.Equal(t, 20, cmd.Limit)
+	assert.Equal(t, airctl.OutputJSON, cmd.Output)
+	assert.JSONEq(t, `{"firstName":{"startsWith":"Jan"}}`, string(cmd.Filter))
+	assert.JSONEq(t, `[{"createDate":"DESC"},{"lastName":"ASC"}]`, string(cmd.Order))
+
+	cmd, err = airctl.ParseArgs([]string{"search", "team", "--sort", "status.creation:asc"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, airctl.DefaultSearchLimit, cmd.Limit)
+	assert.Nil(t, cmd.Filter)
+	assert.JSONEq(t, `[{"status":{"creation":"ASC"}}]`, string(cmd.Order))
+}
+
+// TestParseArgs_DumpVerify verifies dump takes an entity and --out, and verify a file
+func TestParseArgs_DumpVerify(t *testing.T) {
+	cmd, err := airctl.ParseArgs([]string{"dump", "--out", "customers.ndjson.gz", "customer"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &airctl.Command{
+		Name:   airctl.CommandDump,
+		Entity: "customer",
+		File:   "customers.ndjson.gz",
+		Output: airctl.OutputJSON,
+	}, cmd)
+
+	cmd, err = airctl.ParseArgs([]string{"verify", "customers.ndjson.gz"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &airctl.Command{Name: airctl.CommandVerify, File: "customers.ndjson.gz", Output: airctl.OutputJSON}, cmd)
+}
+
+// TestParseArgs_Invalid verifies malformed command lines are rejected before any database work
+func TestParseArgs_Invalid(t *testing.T) {
+	files := filterFiles(map[string]string{"list.json": `[1, 2]`})
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"no subcommand", nil, "usage:"},
+		{"write subcommand", []string{"delete", "customer", "7c9e6679-7425-40de-944b-e07fc1f90ae7"}, `unknown subcommand "delete": airctl is read-only`},
+		{"unknown entity", []string{"search", "portfolio"}, `unknown entity "portfolio"`},
+		{"unknown flag", []string{"search", "customer", "--drop"}, "flag provided but not defined: -drop"},
+		{"search flag on get", []string{"get", "customer", "id", "--limit", "5"}, "flag provided but not defined: -limit"},
+		{"zero limit", []string{"search", "customer", "--limit", "0"}, "--limit must be positive"},
+		{"missing filter file", []string{"search", "customer", "--filter-file", "missing.json"}, "failed to read filter file"},
+		{"filter file not an object", []string{"search", "customer", "--filter-file", "list.json"}, "must hold a JSON object"},
+		{"sort direction", []string{"search", "customer", "--sort", "createDate:newest"}, "must be asc or desc"},
+		{"sort without field", []string{"search", "customer", "--sort", "createDate:desc,"}, "does not name a field"},
+		{"output format", []string{"health", "--output", "yaml"}, "--output must be json or table"},
+		{"health arguments", []string{"health", "now"}, "expected no arguments"},
+		{"dump without out", []string{"dump", "customer"}, "--out is required"},
+		{"dump unknown entity", []string{"dump", "portfolio", "--out", "p.ndjson.gz"}, `unknown entity "portfolio"`},
+		{"verify without file", []string{"verify"}, "expected <file>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := airctl.ParseArgs(tt.args, files)
+			assert.ErrorContains(t, err, tt.contains)
+		})
+	}
+
+	_, err := airctl.ParseArgs([]string{"update"}, nil)
+	assert.True(t, errors.Is(err, airctl.ErrUsage))
+}
+
+// stubClient hands out nil collections, so any call reaching one panics
+type stubClient struct {
+	resolvers.DBClient
+}
+
+func (stubClient) ReadCollection(string) db.Collection { return nil }
+
+// TestReadOnly_RefusesWrites verifies writes through a read-only client fail without reaching the collection
+func TestReadOnly_RefusesWrites(t *testing.T) {
+	ctx := context.Background()
+	collection := airctl.ReadOnly(stubClient{}).Collection("customers")
+
+	_, err := collection.InsertOne(ctx, bson.M{})
+	assert.ErrorIs(t, err, airctl.ErrReadOnly)
+	_, err = collection.UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"firstName": "x"}})
+	assert.ErrorIs(t, err, airctl.ErrReadOnly)
+	_, err = collection.DeleteMany(ctx, bson.M{})
+	assert.ErrorIs(t, err, airctl.ErrReadOnly)
+	_, err = collection.BulkWrite(ctx, nil)
+	assert.ErrorIs(t, err, airctl.ErrReadOnly)
+	_, err = collection.Aggregate(ctx, []bson.M{{"$match": bson.M{}}, {"$out": "copy"}})
+	assert.ErrorIs(t, err, airctl.ErrReadOnly)
+}
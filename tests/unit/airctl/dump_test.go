@@ -0,0 +1,128 @@
+package airctl_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/airctl"
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// documentsCollection serves Find from fixed documents
+type documentsCollection struct {
+	db.Collection
+	documents []interface{}
+}
+
+func (c documentsCollection) Find(context.Context, interface{}, ...*options.FindOptions) (*mongo.Cursor, error) {
+	return mongo.NewCursorFromDocuments(c.documents, nil, nil)
+}
+
+// documentsClient hands out a documentsCollection for one collection name
+type documentsClient struct {
+	stubClient
+	name       string
+	collection documentsCollection
+}
+
+func (c documentsClient) ReadCollection(name string) db.Collection {
+	if name != c.name {
+		return nil
+	}
+	return c.collection
+}
+
+// TestDumpVerify verifies a dump lists its documents, deleted ones included, under a header that
+// verify accepts, and that verify rejects a file whose documents were changed
+func TestDumpVerify(t *testing.T) {
+	client := documentsClient{name: "customers", collection: documentsCollection{documents: []interface{}{
+		bson.D{{Key: "identifier", Value: "c-1"}, {Key: "status", Value: bson.D{{Key: "deletion", Value: "INIT"}}}},
+		bson.D{{Key: "identifier", Value: "c-2"}, {Key: "status", Value: bson.D{{Key: "deletion", Value: "DELETED"}}}},
+	}}}
+	path := filepath.Join(t.TempDir(), "customers.ndjson.gz")
+
+	header, err := airctl.Dump(context.Background(), client, "customer", path)
+	require.NoError(t, err)
+	assert.Equal(t, "customers", header.Collection)
+	assert.Equal(t, int64(2), header.Count)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, header.Checksum)
+
+	verified, err := airctl.Verify(path)
+	require.NoError(t, err)
+	assert.Equal(t, header.Checksum, verified.Checksum)
+	assert.True(t, header.DumpedAt.Equal(verified.DumpedAt))
+
+	lines := readDumpLines(t, path)
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[2], `"deletion":"DELETED"`)
+
+	// Recompress the file with one document changed: gzip stays valid, the checksum does not
+	lines[2] = `{"identifier":"c-2","status":{"deletion":"INIT"}}`
+	writeDumpLines(t, path, lines)
+	_, err = airctl.Verify(path)
+	assert.ErrorIs(t, err, airctl.ErrDumpMismatch)
+	assert.ErrorContains(t, err, "checksum")
+
+	// A dropped document changes the count
+	writeDumpLines(t, path, lines[:2])
+	_, err = airctl.Verify(path)
+	assert.ErrorIs(t, err, airctl.ErrDumpMismatch)
+	assert.ErrorContains(t, err, "1 documents, header lists 2")
+}
+
+// TestVerify_NotADump verifies files that are not gzip or lack a header fail
+func TestVerify_NotADump(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.ndjson")
+	require.NoError(t, os.WriteFile(plain, []byte("{}\n"), 0o600))
+	_, err := airctl.Verify(plain)
+	assert.ErrorContains(t, err, "is not a gzip file")
+
+	empty := filepath.Join(dir, "empty.ndjson.gz")
+	writeDumpLines(t, empty, nil)
+	_, err = airctl.Verify(empty)
+	assert.ErrorContains(t, err, "has no header record")
+}
+
+// readDumpLines returns the decompressed lines of a dump file, header first
+func readDumpLines(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	var lines []string
+	decoder := json.NewDecoder(reader)
+	for decoder.More() {
+		var line json.RawMessage
+		require.NoError(t, decoder.Decode(&line))
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// writeDumpLines writes lines as a gzip-compressed NDJSON file
+func writeDumpLines(t *testing.T, path string, lines []string) {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	for _, line := range lines {
+		_, err := writer.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+}
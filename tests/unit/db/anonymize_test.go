@@ -0,0 +1,130 @@
+package db_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/db/anonymize"
+)
+
+// anonymizeDatabase serves fixed documents per collection and records bulk writes
+type anonymizeDatabase struct {
+	db.Database
+	name        string
+	documents   map[string][]interface{}
+	collections map[string]*anonymizeCollection
+}
+
+func (d *anonymizeDatabase) Name() string { return d.name }
+
+func (d *anonymizeDatabase) Collection(name string) db.Collection {
+	if d.collections == nil {
+		d.collections = make(map[string]*anonymizeCollection)
+	}
+	if _, ok := d.collections[name]; !ok {
+		d.collections[name] = &anonymizeCollection{name: name, documents: d.documents[name]}
+	}
+	return d.collections[name]
+}
+
+type anonymizeCollection struct {
+	db.Collection
+	name      string
+	documents []interface{}
+	batches   [][]mongo.WriteModel
+}
+
+func (c *anonymizeCollection) Name() string { return c.name }
+
+func (c *anonymizeCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return mongo.NewCursorFromDocuments(c.documents, nil, nil)
+}
+
+func (c *anonymizeCollection) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	c.batches = append(c.batches, append([]mongo.WriteModel(nil), models...))
+	return &mongo.BulkWriteResult{MatchedCount: int64(len(models)), ModifiedCount: int64(len(models))}, nil
+}
+
+var prodPattern = regexp.MustCompile("(?i)prod")
+
+// TestAnonymizer_Fake tests fakes are deterministic, keyed and recognizable
+func TestAnonymizer_Fake(t *testing.T) {
+	a := anonymize.NewAnonymizer([]byte("key"))
+
+	email := a.Fake(anonymize.KindEmail, "Ada@Example.com")
+	assert.Regexp(t, `^[0-9a-f]{16}@anonymized\.invalid$`, email)
+	assert.Equal(t, email, a.Fake(anonymize.KindEmail, " ada@example.com"), "emails are compared case-insensitively")
+	assert.NotEqual(t, email, anonymize.NewAnonymizer([]byte("other")).Fake(anonymize.KindEmail, "ada@example.com"))
+
+	name := a.Fake(anonymize.KindName, "Ada")
+	assert.Regexp(t, `^Anon-[0-9a-f]{12}$`, name)
+	assert.Equal(t, name, a.Fake(anonymize.KindName, "Ada"))
+	assert.NotEqual(t, name, a.Fake(anonymize.KindName, "Byron"))
+
+	assert.True(t, anonymize.IsFake(email))
+	assert.True(t, anonymize.IsFake(name))
+	assert.False(t, anonymize.IsFake("ada@example.com"))
+}
+
+// TestAnonymizeRun_Guards tests the run refuses production databases and a missing key
+func TestAnonymizeRun_Guards(t *testing.T) {
+	database := &anonymizeDatabase{name: "air_prod"}
+	_, err := anonymize.Run(context.Background(), database, anonymize.DefaultFields,
+		anonymize.Options{Key: []byte("key"), ProductionPattern: prodPattern}, zerolog.Nop())
+	assert.ErrorIs(t, err, anonymize.ErrProductionDatabase)
+	assert.Empty(t, database.collections, "nothing is read")
+
+	database = &anonymizeDatabase{name: "air_staging"}
+	_, err = anonymize.Run(context.Background(), database, anonymize.DefaultFields,
+		anonymize.Options{ProductionPattern: prodPattern}, zerolog.Nop())
+	assert.EqualError(t, err, "anonymization key is required")
+}
+
+// TestAnonymizeRun_Batches tests changed documents are written in batches and skipped in dry-run mode
+func TestAnonymizeRun_Batches(t *testing.T) {
+	documents := map[string][]interface{}{
+		"customers": {
+			bson.M{"_id": 1, "firstName": "Ada", "firstNameLower": "ada"},
+			bson.M{"_id": 2, "firstName": "Grace"},
+			bson.M{"_id": 3, "firstName": "Anon-0123456789ab"}, // Already anonymized
+			bson.M{"_id": 4, "firstName": "Alan"},
+		},
+	}
+	fields := []anonymize.Field{{Collection: "customers", Path: "firstName", Shadow: "firstNameLower", Kind: anonymize.KindName}}
+	a := anonymize.NewAnonymizer([]byte("key"))
+
+	database := &anonymizeDatabase{name: "air_staging", documents: documents}
+	results, err := anonymize.Run(context.Background(), database, fields,
+		anonymize.Options{Key: []byte("key"), DryRun: true, ProductionPattern: prodPattern}, zerolog.Nop())
+	require.NoError(t, err)
+	assert.Equal(t, []anonymize.Result{{Collection: "customers", Scanned: 4, Changed: 3, DryRun: true}}, results)
+	assert.Empty(t, database.collections["customers"].batches)
+
+	database = &anonymizeDatabase{name: "air_staging", documents: documents}
+	results, err = anonymize.Run(context.Background(), database, fields,
+		anonymize.Options{Key: []byte("key"), BatchSize: 2, ProductionPattern: prodPattern}, zerolog.Nop())
+	require.NoError(t, err)
+	assert.Equal(t, []anonymize.Result{{Collection: "customers", Scanned: 4, Changed: 3}}, results)
+
+	batches := database.collections["customers"].batches
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	assert.Len(t, batches[1], 1)
+
+	first := batches[0][0].(*mongo.UpdateOneModel)
+	fake := a.Fake(anonymize.KindName, "Ada")
+	assert.Equal(t, bson.M{"_id": int32(1), "firstName": "Ada"}, first.Filter)
+	assert.Equal(t, bson.M{"$set": bson.M{"firstName": fake, "firstNameLower": "anon-" + fake[len("Anon-"):]}}, first.Update)
+
+	second := batches[0][1].(*mongo.UpdateOneModel)
+	assert.Equal(t, bson.M{"$set": bson.M{"firstName": a.Fake(anonymize.KindName, "Grace")}}, second.Update, "absent shadow fields are not added")
+}
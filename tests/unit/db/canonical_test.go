@@ -0,0 +1,59 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// buildFilter builds the same filter inserting the keys of its maps in the given order
+func buildFilter(keys []string) bson.M {
+	values := map[string]interface{}{
+		"status.deletion": bson.M{"$ne": "DELETED"},
+		"lastName":        bson.M{"$in": bson.A{"Doe", "Roe"}},
+		"createDate":      bson.M{"$gte": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"$or":             []bson.M{{"b": 2, "a": 1}, {"team": "t1"}},
+		"sort":            bson.D{{Key: "z", Value: 1}, {Key: "a", Value: -1}},
+	}
+	filter := bson.M{}
+	for _, key := range keys {
+		filter[key] = values[key]
+	}
+	return filter
+}
+
+// TestMarshalCanonical_OrderIndependent verifies equal filters built in different orders give
+// identical bytes, with map keys sorted and ordered documents and arrays kept in order
+func TestMarshalCanonical_OrderIndependent(t *testing.T) {
+	first, err := db.MarshalCanonical(buildFilter([]string{"status.deletion", "lastName", "createDate", "$or", "sort"}))
+	require.NoError(t, err)
+	assert.Equal(t, `{"$or":[{"a":1,"b":2},{"team":"t1"}],"createDate":{"$gte":{"$date":"2024-01-01T00:00:00Z"}},`+
+		`"lastName":{"$in":["Doe","Roe"]},"sort":{"z":1,"a":-1},"status.deletion":{"$ne":"DELETED"}}`, string(first))
+
+	for i := 0; i < 20; i++ {
+		again, err := db.MarshalCanonical(buildFilter([]string{"sort", "$or", "createDate", "lastName", "status.deletion"}))
+		require.NoError(t, err)
+		assert.Equal(t, string(first), string(again))
+	}
+}
+
+func TestMarshalCanonical_Empty(t *testing.T) {
+	for _, doc := range []bson.M{nil, {}} {
+		rendered, err := db.MarshalCanonical(doc)
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(rendered))
+	}
+}
+
+// TestCanonical_KeepsScalars verifies values other than documents and arrays are returned unchanged
+func TestCanonical_KeepsScalars(t *testing.T) {
+	assert.Equal(t, "abc", db.Canonical("abc"))
+	assert.Equal(t, []byte("raw"), db.Canonical([]byte("raw")))
+	assert.Equal(t, bson.D{{Key: "a", Value: bson.A{"x"}}, {Key: "b", Value: 1}},
+		db.Canonical(map[string]interface{}{"b": 1, "a": []string{"x"}}))
+}
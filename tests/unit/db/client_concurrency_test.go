@@ -0,0 +1,158 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// retryingClient returns a client whose connect attempts fail at once, so Connect waits on the
+// fake clock between its three attempts
+func retryingClient(t *testing.T) (*db.Client, *testutil.FakeClock) {
+	t.Helper()
+	config := &db.DBConfig{
+		// The driver rejects the option before dialing
+		URI:              "mongodb://localhost:27017/?connectTimeoutMS=invalid",
+		Database:         "testdb",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}
+
+	client, err := db.NewClient(config, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error = %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	client.SetClock(clock)
+	return client, clock
+}
+
+// startConnect runs Connect in the background until it waits for its first retry. Waits of
+// canceled Connects stay pending on the clock, so only a new one counts
+func startConnect(t *testing.T, client *db.Client, clock *testutil.FakeClock) <-chan error {
+	t.Helper()
+	waiters := clock.Waiters()
+	result := make(chan error, 1)
+	go func() { result <- client.Connect(context.Background()) }()
+	if !clock.BlockUntilWaiters(waiters+1, 5*time.Second) {
+		t.Fatal("Connect did not wait for a retry")
+	}
+	return result
+}
+
+// connectResult returns the error of a background Connect
+func connectResult(t *testing.T, result <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Connect did not return")
+		return nil
+	}
+}
+
+// TestClient_Connect_ConcurrentConnectReturnsInProgress tests a second Connect returns at once
+// while the first one retries, and the state follows the first one
+func TestClient_Connect_ConcurrentConnectReturnsInProgress(t *testing.T) {
+	client, clock := retryingClient(t)
+	result := startConnect(t, client, clock)
+
+	if state := client.State(); state != db.StateConnecting {
+		t.Errorf("State() = %v during Connect, expected connecting", state)
+	}
+	if err := client.Connect(context.Background()); !errors.Is(err, db.ErrConnectInProgress) {
+		t.Errorf("concurrent Connect() error = %v, expected ErrConnectInProgress", err)
+	}
+	status, err := client.HealthStatus(context.Background())
+	if err != nil {
+		t.Fatalf("HealthStatus() unexpected error = %v", err)
+	}
+	if status.State != "connecting" || status.Status != "disconnected" {
+		t.Errorf("HealthStatus() state = %q, status = %q, expected connecting, disconnected", status.State, status.Status)
+	}
+
+	// Exhaust the remaining attempts; delays vary by up to 20%
+	clock.Advance(2 * time.Second)
+	if !clock.BlockUntilWaiters(1, 5*time.Second) {
+		t.Fatal("Connect did not wait for its last retry")
+	}
+	clock.Advance(3 * time.Second)
+	if err := connectResult(t, result); !errors.Is(err, db.ErrConnectionTimeout) {
+		t.Errorf("Connect() error = %v, expected ErrConnectionTimeout", err)
+	}
+	if state := client.State(); state != db.StateDisconnected {
+		t.Errorf("State() = %v after failed Connect, expected disconnected", state)
+	}
+}
+
+// TestClient_Disconnect_CancelsConnect tests Disconnect cancels a retrying Connect and returns
+// once it has, leaving the client free to connect again
+func TestClient_Disconnect_CancelsConnect(t *testing.T) {
+	client, clock := retryingClient(t)
+	result := startConnect(t, client, clock)
+
+	if err := client.Disconnect(context.Background()); err != nil {
+		t.Fatalf("Disconnect() unexpected error = %v", err)
+	}
+	if state := client.State(); state != db.StateDisconnected {
+		t.Errorf("State() = %v after Disconnect, expected disconnected", state)
+	}
+	if err := connectResult(t, result); !errors.Is(err, db.ErrConnectCanceled) {
+		t.Errorf("Connect() error = %v, expected ErrConnectCanceled", err)
+	}
+
+	// A later Connect starts over instead of seeing the canceled one
+	result = startConnect(t, client, clock)
+	if err := client.Disconnect(context.Background()); err != nil {
+		t.Fatalf("second Disconnect() unexpected error = %v", err)
+	}
+	if err := connectResult(t, result); !errors.Is(err, db.ErrConnectCanceled) {
+		t.Errorf("second Connect() error = %v, expected ErrConnectCanceled", err)
+	}
+}
+
+// TestClient_Disconnect_ContextEndsWait tests Disconnect stops waiting for a canceled Connect
+// when its context is done
+func TestClient_Disconnect_ContextEndsWait(t *testing.T) {
+	client, clock := retryingClient(t)
+	result := startConnect(t, client, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Disconnect(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("Disconnect() error = %v, expected nil or context.Canceled", err)
+	}
+	if err := connectResult(t, result); !errors.Is(err, db.ErrConnectCanceled) {
+		t.Errorf("Connect() error = %v, expected ErrConnectCanceled", err)
+	}
+}
+
+// TestConnectionState_String tests the state names reported by HealthStatus
+func TestConnectionState_String(t *testing.T) {
+	expected := map[db.ConnectionState]string{
+		db.StateDisconnected:  "disconnected",
+		db.StateConnecting:    "connecting",
+		db.StateConnected:     "connected",
+		db.StateDisconnecting: "disconnecting",
+	}
+	for state, name := range expected {
+		if state.String() != name {
+			t.Errorf("%d.String() = %q, expected %q", state, state.String(), name)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/tests/testutil"
 )
 
 // TestClient_IsConnected_InitialState tests initial connection state
@@ -285,6 +286,9 @@ func TestClient_HealthStatus_Caching(t *testing.T) {
 	}
 	defer client.Close()
 
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	client.SetClock(clock)
+
 	ctx := context.Background()
 
 	// First call
@@ -294,27 +298,71 @@ func TestClient_HealthStatus_Caching(t *testing.T) {
 	}
 
 	// Second call within cache TTL (5 seconds)
+	clock.Advance(4 * time.Second)
 	status2, err := client.HealthStatus(ctx)
 	if err != nil {
 		t.Fatalf("HealthStatus() second call returned error = %v", err)
 	}
 
 	// Both calls should return the same cached status
-	if status1.Timestamp != status2.Timestamp {
-		t.Error("HealthStatus() cache not working - timestamps differ")
+	if status1 != status2 {
+		t.Error("HealthStatus() cache not working - expected the cached status")
 	}
 
-	// Wait for cache to expire (5 seconds + buffer)
-	time.Sleep(6 * time.Second)
-
-	// Third call after cache expiry
+	// Third call once the cache TTL has passed
+	clock.Advance(2 * time.Second)
 	status3, err := client.HealthStatus(ctx)
 	if err != nil {
 		t.Fatalf("HealthStatus() third call returned error = %v", err)
 	}
 
 	// Third call should have a new timestamp
-	if status1.Timestamp == status3.Timestamp {
-		t.Error("HealthStatus() cache not expiring - timestamps should differ after 6 seconds")
+	if !status3.Timestamp.Equal(status1.Timestamp.Add(6 * time.Second)) {
+		t.Errorf("HealthStatus() cache not expiring - timestamp = %v, expected %v",
+			status3.Timestamp, status1.Timestamp.Add(6*time.Second))
+	}
+}
+
+// TestClient_Reader_FallsBackToClient tests read routing with and without a separate reader
+func TestClient_Reader_FallsBackToClient(t *testing.T) {
+	logger := zerolog.Nop()
+
+	config := &db.DBConfig{
+		URI:              "mongodb://localhost:27017",
+		Database:         "testdb",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   1 * time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}
+
+	client, err := db.NewClient(config, logger)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error = %v", err)
+	}
+	defer client.Close()
+
+	if client.Reader() != client {
+		t.Error("Reader() without a separate reader should return the client itself")
+	}
+
+	reader, err := db.NewClient(config, logger)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error = %v", err)
+	}
+	defer reader.Close()
+
+	client.SetReader(reader)
+	if client.Reader() != reader {
+		t.Error("Reader() should return the configured reader")
+	}
+
+	client.SetReader(nil)
+	if client.Reader() != client {
+		t.Error("Reader() after SetReader(nil) should return the client itself")
 	}
 }
@@ -282,3 +282,40 @@ func TestDBConfig_Validate_NilConfig(t *testing.T) {
 		t.Error("Validate() expected error for nil config, got nil")
 	}
 }
+
+// TestDBConfig_Validate_ReadPreference tests the optional read preference mode
+func TestDBConfig_Validate_ReadPreference(t *testing.T) {
+	tests := []struct {
+		name           string
+		readPreference string
+		wantErr        bool
+	}{
+		{name: "empty keeps the default", readPreference: ""},
+		{name: "secondaryPreferred", readPreference: "secondaryPreferred"},
+		{name: "case insensitive", readPreference: "NEAREST"},
+		{name: "unknown mode", readPreference: "secondaryOnly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &db.DBConfig{
+				URI:              "mongodb://localhost:27017",
+				Database:         "testdb",
+				ReadPreference:   tt.readPreference,
+				ConnectTimeout:   30 * time.Second,
+				OperationTimeout: 10 * time.Second,
+				MinPoolSize:      5,
+				MaxPoolSize:      10,
+				MaxConnIdleTime:  5 * time.Minute,
+				MaxRetryAttempts: 3,
+				RetryBaseDelay:   1 * time.Second,
+				RetryMaxDelay:    10 * time.Second,
+			}
+
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
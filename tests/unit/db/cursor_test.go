@@ -0,0 +1,46 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/tests/testutil/leakcheck"
+)
+
+// TestWithCursor_ClosesOnEveryPath verifies the cursor is closed after success, an error and a panic
+func TestWithCursor_ClosesOnEveryPath(t *testing.T) {
+	ctx := context.Background()
+	detector := leakcheck.Start()
+
+	require.NoError(t, db.WithCursor(ctx, newCursor(t), func(cursor *mongo.Cursor) error {
+		assert.True(t, cursor.Next(ctx))
+		return nil
+	}))
+
+	failure := errors.New("decode failed")
+	err := db.WithCursor(ctx, newCursor(t), func(cursor *mongo.Cursor) error { return failure })
+	assert.Equal(t, failure, err, "an error without a close error is returned unchanged")
+
+	assert.Panics(t, func() {
+		_ = db.WithCursor(ctx, newCursor(t), func(cursor *mongo.Cursor) error { panic("boom") })
+	})
+
+	assert.NoError(t, detector.Err())
+}
+
+// TestLeakcheck_DetectsLeakedCursor verifies the detector reports a cursor that is never closed
+func TestLeakcheck_DetectsLeakedCursor(t *testing.T) {
+	detector := leakcheck.Start()
+
+	leaked := db.TrackCursor(newCursor(t))
+	assert.ErrorContains(t, detector.Err(), "1 cursor(s) left open")
+
+	require.NoError(t, leaked.Close(context.Background()))
+	assert.NoError(t, detector.Err())
+}
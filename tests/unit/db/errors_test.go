@@ -185,3 +185,12 @@ func TestErrorContext(t *testing.T) {
 		})
 	}
 }
+
+// TestReadOnlyError verifies refused writes name the operation and collection and match ErrReadOnly
+func TestReadOnlyError(t *testing.T) {
+	var err error = &db.ReadOnlyError{Operation: "insert_one", Collection: "customers"}
+
+	assert.EqualError(t, err, "db: insert_one on customers refused: client is read-only")
+	assert.ErrorIs(t, err, db.ErrReadOnly)
+	assert.NotErrorIs(t, err, db.ErrNotConnected)
+}
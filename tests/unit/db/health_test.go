@@ -7,6 +7,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/tests/testutil"
 )
 
 // TestHealthStatus_HealthyConnection tests health status with healthy connection (T077)
@@ -40,6 +41,10 @@ func TestHealthStatus_WithCache(t *testing.T) {
 	}
 	defer client.Close()
 
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := testutil.NewFakeClock(start)
+	client.SetClock(clock)
+
 	ctx := context.Background()
 
 	// First call - creates cache
@@ -52,29 +57,24 @@ func TestHealthStatus_WithCache(t *testing.T) {
 		t.Fatal("HealthStatus() returned nil status")
 	}
 
-	// Capture timestamp
-	timestamp1 := status1.Timestamp
+	if !status1.Timestamp.Equal(start) {
+		t.Errorf("Timestamp = %v, expected the clock time %v", status1.Timestamp, start)
+	}
 
-	// Immediate second call - should return cached result
+	// One nanosecond before expiry - still cached
+	clock.Advance(5*time.Second - time.Nanosecond)
 	status2, err := client.HealthStatus(ctx)
 	if err != nil {
 		t.Fatalf("HealthStatus() second call returned error = %v", err)
 	}
 
-	if status2 == nil {
-		t.Fatal("HealthStatus() returned nil status on second call")
-	}
-
-	// Timestamps should be identical (cached)
-	if !timestamp1.Equal(status2.Timestamp) {
+	if !status2.Timestamp.Equal(start) {
 		t.Errorf("Cache not working: timestamps differ (first=%v, second=%v)",
-			timestamp1, status2.Timestamp)
+			start, status2.Timestamp)
 	}
 
-	// Wait for cache to expire (5 seconds + buffer)
-	time.Sleep(6 * time.Second)
-
-	// Third call after expiry - should have new timestamp
+	// Exactly at the 5-second TTL - expired
+	clock.Advance(time.Nanosecond)
 	status3, err := client.HealthStatus(ctx)
 	if err != nil {
 		t.Fatalf("HealthStatus() third call returned error = %v", err)
@@ -84,15 +84,22 @@ func TestHealthStatus_WithCache(t *testing.T) {
 		t.Fatal("HealthStatus() returned nil status on third call")
 	}
 
-	// Timestamp should be different (cache expired)
-	if timestamp1.Equal(status3.Timestamp) {
-		t.Error("Cache not expiring: timestamps should differ after 6 seconds")
+	expected := start.Add(5 * time.Second)
+	if !status3.Timestamp.Equal(expected) {
+		t.Errorf("Cache not expiring at the TTL: timestamp = %v, expected %v",
+			status3.Timestamp, expected)
+	}
+
+	// The refreshed entry is cached for another 5 seconds
+	clock.Advance(5*time.Second - time.Nanosecond)
+	status4, err := client.HealthStatus(ctx)
+	if err != nil {
+		t.Fatalf("HealthStatus() fourth call returned error = %v", err)
 	}
 
-	// Verify new timestamp is later
-	if !status3.Timestamp.After(timestamp1) {
-		t.Errorf("New timestamp (%v) should be after old timestamp (%v)",
-			status3.Timestamp, timestamp1)
+	if !status4.Timestamp.Equal(expected) {
+		t.Errorf("Refreshed entry not cached: timestamp = %v, expected %v",
+			status4.Timestamp, expected)
 	}
 }
 
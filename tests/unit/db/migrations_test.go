@@ -0,0 +1,39 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/air-go/internal/db/migrations"
+)
+
+// TestParseCreateDate tests which createDate strings the normalization migration converts
+func TestParseCreateDate(t *testing.T) {
+	expected := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+		ok       bool
+	}{
+		{name: "UTC timestamp", value: "2024-05-01T10:30:00Z", expected: expected, ok: true},
+		{name: "offset is converted to UTC", value: "2024-05-01T12:30:00+02:00", expected: expected, ok: true},
+		{name: "fractional seconds", value: "2024-05-01T10:30:00.5Z", expected: expected.Add(500 * time.Millisecond), ok: true},
+		{name: "date only", value: "2024-05-01", ok: false},
+		{name: "empty", value: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, ok := migrations.ParseCreateDate(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, parsed)
+				assert.Equal(t, time.UTC, parsed.Location())
+			}
+		})
+	}
+}
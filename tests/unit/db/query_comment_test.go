@@ -0,0 +1,71 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// TestQueryComment_Format verifies the comment names operation, entity and request
+func TestQueryComment_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment db.QueryComment
+		want    string
+	}{
+		{
+			name:    "named operation",
+			comment: db.QueryComment{Operation: "CustomerList", RequestID: "3f2b-1"},
+			want:    "graphql op=CustomerList entity=customers request=3f2b-1",
+		},
+		{
+			name:    "anonymous operation",
+			comment: db.QueryComment{RequestID: "abc"},
+			want:    "graphql op=anonymous entity=customers request=abc",
+		},
+		{
+			name:    "without request ID",
+			comment: db.QueryComment{Operation: "CustomerList"},
+			want:    "graphql op=CustomerList entity=customers",
+		},
+		{
+			name:    "unsafe characters replaced",
+			comment: db.QueryComment{Operation: "a*/ b\n{$ne}", RequestID: "id\"x"},
+			want:    "graphql op=a___b___ne_ entity=customers request=id_x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.comment.Format("customers"))
+		})
+	}
+}
+
+// TestQueryComment_LengthCapped verifies long values are truncated
+func TestQueryComment_LengthCapped(t *testing.T) {
+	comment := db.QueryComment{
+		Operation: strings.Repeat("o", 500),
+		RequestID: strings.Repeat("r", 500),
+	}
+
+	formatted := comment.Format(strings.Repeat("c", 500))
+	assert.LessOrEqual(t, len(formatted), 256)
+	assert.Contains(t, formatted, "op="+strings.Repeat("o", 64)+" ")
+}
+
+// TestQueryComment_Context verifies the comment round-trips through a context
+func TestQueryComment_Context(t *testing.T) {
+	_, ok := db.QueryCommentFrom(context.Background())
+	assert.False(t, ok)
+
+	ctx := db.WithQueryComment(context.Background(), db.QueryComment{Operation: "Op", RequestID: "r"})
+	comment, ok := db.QueryCommentFrom(ctx)
+	require.True(t, ok)
+	assert.Equal(t, db.QueryComment{Operation: "Op", RequestID: "r"}, comment)
+}
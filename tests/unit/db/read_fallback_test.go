@@ -0,0 +1,39 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// TestIsNoPrimaryError verifies which errors make a read fall back to a secondary
+func TestIsNoPrimaryError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "not writable primary", err: mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}, want: true},
+		{name: "not primary no secondary ok", err: mongo.CommandError{Code: 13435}, want: true},
+		{name: "primary stepped down", err: mongo.CommandError{Code: 189}, want: true},
+		{name: "wrapped repl state change", err: fmt.Errorf("find: %w", mongo.CommandError{Code: 11602}), want: true},
+		{name: "server selection", err: topology.ServerSelectionError{Wrapped: context.DeadlineExceeded}, want: true},
+		{name: "other command error", err: mongo.CommandError{Code: 2, Name: "BadValue"}, want: false},
+		{name: "no documents", err: mongo.ErrNoDocuments, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, db.IsNoPrimaryError(tt.err))
+		})
+	}
+}
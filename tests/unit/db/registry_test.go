@@ -0,0 +1,50 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// TestRegistry_DecodesDatesIntoStrings verifies BSON dates decode into string fields as RFC3339 UTC
+// while strings, object IDs and nulls decode as with the default registry
+func TestRegistry_DecodesDatesIntoStrings(t *testing.T) {
+	oid := primitive.NewObjectID()
+	raw, err := bson.Marshal(bson.M{
+		"createDate": time.Date(2024, 3, 1, 10, 0, 0, 250_000_000, time.FixedZone("CET", 3600)),
+		"birthDate":  "1985-06-15",
+		"oid":        oid,
+		"deleted":    nil,
+		"nested":     bson.M{"date": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	require.NoError(t, err)
+
+	var doc struct {
+		CreateDate *string `bson:"createDate"`
+		BirthDate  string  `bson:"birthDate"`
+		OID        string  `bson:"oid"`
+		Deleted    *string `bson:"deleted"`
+		Nested     struct {
+			Date string `bson:"date"`
+		} `bson:"nested"`
+	}
+	require.NoError(t, bson.UnmarshalWithRegistry(db.Registry, raw, &doc))
+
+	require.NotNil(t, doc.CreateDate)
+	assert.Equal(t, "2024-03-01T09:00:00.25Z", *doc.CreateDate)
+	assert.Equal(t, "1985-06-15", doc.BirthDate)
+	assert.Equal(t, oid.Hex(), doc.OID)
+	assert.Nil(t, doc.Deleted)
+	assert.Equal(t, "2024-03-01T00:00:00Z", doc.Nested.Date)
+
+	// Untyped documents keep BSON dates
+	var untyped bson.M
+	require.NoError(t, bson.UnmarshalWithRegistry(db.Registry, raw, &untyped))
+	assert.IsType(t, primitive.DateTime(0), untyped["createDate"])
+}
@@ -0,0 +1,84 @@
+package db_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// newCursor returns a cursor over in-memory documents
+func newCursor(t *testing.T) *mongo.Cursor {
+	t.Helper()
+	cursor, err := mongo.NewCursorFromDocuments([]interface{}{bson.M{"identifier": "a"}}, nil, nil)
+	require.NoError(t, err)
+	return cursor
+}
+
+// TestTrackCursor_CountsLeakedCursor verifies a cursor that is never closed stays counted as open
+func TestTrackCursor_CountsLeakedCursor(t *testing.T) {
+	ctx := context.Background()
+	before := db.OpenCursors()
+
+	// Closed and drained cursors stop counting, a second Close does not count twice
+	closed := db.TrackCursor(newCursor(t))
+	require.NoError(t, closed.Close(ctx))
+	require.NoError(t, closed.Close(ctx))
+	var docs []bson.M
+	require.NoError(t, db.TrackCursor(newCursor(t)).All(ctx, &docs))
+	assert.Equal(t, before, db.OpenCursors())
+
+	// Leaked deliberately: no Close
+	leaked := db.TrackCursor(newCursor(t))
+	assert.Equal(t, before+1, db.OpenCursors())
+
+	monitor := db.NewResourceMonitor(zerolog.Nop(), db.DefaultResourceThresholds)
+	assert.Equal(t, before+1, monitor.Sample(time.Now()).OpenCursors)
+
+	require.NoError(t, leaked.Close(ctx))
+	assert.Equal(t, before, db.OpenCursors())
+}
+
+// TestResourceMonitor_WarnsAboutGrowth verifies connections checked out and not returned are
+// sampled, and growth past the threshold over the window is logged
+func TestResourceMonitor_WarnsAboutGrowth(t *testing.T) {
+	var buf bytes.Buffer
+	monitor := db.NewResourceMonitor(zerolog.New(&buf), db.ResourceThresholds{Goroutines: 1 << 20, ConnectionsInUse: 2, OpenCursors: 1 << 20})
+	pool := monitor.PoolMonitor()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, int64(0), monitor.Sample(start).ConnectionsInUse)
+
+	pool.Event(&event.PoolEvent{Type: event.GetSucceeded})
+	pool.Event(&event.PoolEvent{Type: event.GetSucceeded})
+	assert.Equal(t, int64(2), monitor.Sample(start.Add(time.Minute)).ConnectionsInUse)
+	assert.Empty(t, buf.String(), "growth at the threshold is tolerated")
+
+	pool.Event(&event.PoolEvent{Type: event.GetSucceeded})
+	pool.Event(&event.PoolEvent{Type: event.GetSucceeded})
+	pool.Event(&event.PoolEvent{Type: event.ConnectionReturned})
+	monitor.Sample(start.Add(2 * time.Minute))
+
+	entries := logEntries(t, &buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "warn", entries[0]["level"])
+	assert.Equal(t, "resource_growth", entries[0]["event_type"])
+	assert.Equal(t, "connections_in_use", entries[0]["resource"])
+	assert.Equal(t, float64(0), entries[0]["from"])
+	assert.Equal(t, float64(3), entries[0]["to"])
+	assert.Equal(t, float64(2*time.Minute/time.Millisecond), entries[0]["window_ms"])
+
+	samples := monitor.Summary().Samples
+	require.Len(t, samples, 3)
+	assert.Equal(t, start, samples[0].At)
+	assert.Equal(t, []int64{0, 2, 3}, []int64{samples[0].ConnectionsInUse, samples[1].ConnectionsInUse, samples[2].ConnectionsInUse})
+}
@@ -1,13 +1,16 @@
 package db_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/tests/testutil"
 )
 
 // TestCalculateDelay_RetrySchedule verifies retry schedule (1s, 2s, 10s) with jitter (T092)
@@ -158,3 +161,50 @@ func TestLogRetryAttempt_StructuredLogging(t *testing.T) {
 		db.LogRetryAttempt(logger, state, delay)
 	}, "LogRetryAttempt should not panic")
 }
+
+// TestWaitForRetry_WaitsForDelay verifies the retry wait ends exactly when the delay has elapsed
+func TestWaitForRetry_WaitsForDelay(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	delay := db.CalculateDelay(2, 1*time.Second, 10*time.Second)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- db.WaitForRetry(context.Background(), clock, delay)
+	}()
+	require.True(t, clock.BlockUntilWaiters(1, time.Second), "WaitForRetry should wait on the clock")
+
+	clock.Advance(delay - time.Nanosecond)
+	select {
+	case <-done:
+		t.Fatal("WaitForRetry returned before the delay elapsed")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Nanosecond)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("WaitForRetry did not return once the delay elapsed")
+	}
+}
+
+// TestWaitForRetry_ContextCancelled verifies a cancelled context ends the wait without advancing the clock
+func TestWaitForRetry_ContextCancelled(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- db.WaitForRetry(ctx, clock, 10*time.Second)
+	}()
+	require.True(t, clock.BlockUntilWaiters(1, time.Second))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("WaitForRetry did not return after cancellation")
+	}
+}
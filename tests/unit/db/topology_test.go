@@ -0,0 +1,163 @@
+package db_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/address"
+	"go.mongodb.org/mongo-driver/mongo/description"
+
+	"github.com/yourusername/air-go/internal/db"
+)
+
+// replicaSet builds a replica set topology description with the given primary (empty for none)
+func replicaSet(primary string, secondaries ...string) description.Topology {
+	topology := description.Topology{Kind: description.ReplicaSetNoPrimary}
+	if primary != "" {
+		topology.Kind = description.ReplicaSetWithPrimary
+		topology.Servers = append(topology.Servers, description.Server{Addr: address.Address(primary), Kind: description.RSPrimary})
+	}
+	for _, secondary := range secondaries {
+		topology.Servers = append(topology.Servers, description.Server{Addr: address.Address(secondary), Kind: description.RSSecondary})
+	}
+	return topology
+}
+
+// logEntries parses one JSON log entry per line
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestTopologyMonitor_TopologyAndPrimaryChanges verifies topology changes are counted and a failover is logged as a warning
+func TestTopologyMonitor_TopologyAndPrimaryChanges(t *testing.T) {
+	var buf bytes.Buffer
+	monitor := db.NewTopologyMonitor(zerolog.New(&buf))
+	callbacks := monitor.ServerMonitor()
+
+	// Initial discovery of the primary
+	callbacks.TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		PreviousDescription: description.Topology{Kind: description.ReplicaSetNoPrimary},
+		NewDescription:      replicaSet("mongo-0:27017", "mongo-1:27017", "mongo-2:27017"),
+	})
+
+	entries := logEntries(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "mongodb_topology_changed", entries[0]["event_type"])
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, float64(3), entries[0]["servers"])
+	assert.Equal(t, "mongodb_primary_changed", entries[1]["event_type"])
+	assert.Equal(t, "info", entries[1]["level"], "Discovering the first primary is not a warning")
+
+	// A secondary is elected primary
+	callbacks.TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		PreviousDescription: replicaSet("mongo-0:27017", "mongo-1:27017", "mongo-2:27017"),
+		NewDescription:      replicaSet("mongo-1:27017", "mongo-0:27017", "mongo-2:27017"),
+	})
+
+	entries = logEntries(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "mongodb_primary_changed", entries[1]["event_type"])
+	assert.Equal(t, "warn", entries[1]["level"])
+	assert.Equal(t, "mongo-0:27017", entries[1]["previous_primary"])
+	assert.Equal(t, "mongo-1:27017", entries[1]["primary"])
+
+	// A secondary leaves without a primary change
+	callbacks.TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		PreviousDescription: replicaSet("mongo-1:27017", "mongo-0:27017", "mongo-2:27017"),
+		NewDescription:      replicaSet("mongo-1:27017", "mongo-0:27017"),
+	})
+
+	entries = logEntries(t, &buf)
+	require.Len(t, entries, 1, "Only the topology change is logged")
+
+	summary := monitor.Summary()
+	assert.Equal(t, "ReplicaSetWithPrimary", summary.Kind)
+	assert.Equal(t, 2, summary.KnownServers)
+	assert.Equal(t, "mongo-1:27017", summary.Primary)
+	assert.Equal(t, int64(3), summary.TopologyChanges)
+	assert.Equal(t, int64(2), summary.PrimaryChanges)
+	assert.Equal(t, int64(0), summary.HeartbeatFailures)
+}
+
+// TestTopologyMonitor_PrimaryLost verifies losing the primary clears it and is logged as a warning
+func TestTopologyMonitor_PrimaryLost(t *testing.T) {
+	var buf bytes.Buffer
+	monitor := db.NewTopologyMonitor(zerolog.New(&buf))
+	callbacks := monitor.ServerMonitor()
+
+	callbacks.TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		NewDescription: replicaSet("mongo-0:27017", "mongo-1:27017"),
+	})
+	buf.Reset()
+
+	callbacks.TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		PreviousDescription: replicaSet("mongo-0:27017", "mongo-1:27017"),
+		NewDescription:      replicaSet("", "mongo-1:27017"),
+	})
+
+	entries := logEntries(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "warn", entries[1]["level"])
+	assert.Equal(t, "", entries[1]["primary"])
+
+	summary := monitor.Summary()
+	assert.Equal(t, "ReplicaSetNoPrimary", summary.Kind)
+	assert.Empty(t, summary.Primary)
+}
+
+// TestTopologyMonitor_HeartbeatFailed verifies failed heartbeats are counted and logged with structured fields
+func TestTopologyMonitor_HeartbeatFailed(t *testing.T) {
+	var buf bytes.Buffer
+	monitor := db.NewTopologyMonitor(zerolog.New(&buf))
+	callbacks := monitor.ServerMonitor()
+
+	for i := 0; i < 2; i++ {
+		callbacks.ServerHeartbeatFailed(&event.ServerHeartbeatFailedEvent{
+			Duration:     150 * time.Millisecond,
+			Failure:      errors.New("connection refused"),
+			ConnectionID: "mongo-2:27017[-4]",
+		})
+	}
+
+	entries := logEntries(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "mongodb_heartbeat_failed", entries[0]["event_type"])
+	assert.Equal(t, "warn", entries[0]["level"])
+	assert.Equal(t, "mongo-2:27017[-4]", entries[0]["connection_id"])
+	assert.Equal(t, float64(150), entries[0]["duration_ms"])
+	assert.Equal(t, "connection refused", entries[0]["error"])
+
+	assert.Equal(t, int64(2), monitor.Summary().HeartbeatFailures)
+}
+
+// TestTopologyMonitor_StripsCredentials verifies the reported primary never contains credentials
+func TestTopologyMonitor_StripsCredentials(t *testing.T) {
+	monitor := db.NewTopologyMonitor(zerolog.Nop())
+
+	monitor.ServerMonitor().TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		NewDescription: description.Topology{
+			Kind:    description.Single,
+			Servers: []description.Server{{Addr: address.Address("admin:s3cret@mongo.example.net:27017"), Kind: description.Standalone}},
+		},
+	})
+
+	summary := monitor.Summary()
+	assert.Equal(t, "mongo.example.net:27017", summary.Primary)
+	assert.Equal(t, 1, summary.KnownServers)
+}
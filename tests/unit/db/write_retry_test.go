@@ -0,0 +1,147 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// blipClient reports connected or disconnected and stores the writes made while connected
+type blipClient struct {
+	db.DBClient
+	connected atomic.Bool
+
+	mu      sync.Mutex
+	written []string
+}
+
+func (c *blipClient) HealthStatus(ctx context.Context) (*db.HealthStatus, error) {
+	if c.connected.Load() {
+		return &db.HealthStatus{Status: "connected"}, nil
+	}
+	return &db.HealthStatus{Status: "disconnected"}, nil
+}
+
+// write returns a write storing value, failing while the client is disconnected
+func (c *blipClient) write(value string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if !c.connected.Load() {
+			return errors.New("server selection timeout")
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.written = append(c.written, value)
+		return nil
+	}
+}
+
+func (c *blipClient) stored() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string{}, c.written...)
+}
+
+var writeRetryOptions = db.WriteRetryOptions{Capacity: 10, BaseDelay: time.Second, MaxDelay: 8 * time.Second}
+
+// runWriteRetry runs the queue until the test ends
+func runWriteRetry(t *testing.T, queue *db.WriteRetryQueue, client db.DBClient) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		queue.Run(ctx, client)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// awaitReplayed advances the clock until the queue replayed n writes
+func awaitReplayed(t *testing.T, queue *db.WriteRetryQueue, clock *testutil.FakeClock, n int64) {
+	require.Eventually(t, func() bool {
+		clock.Advance(time.Second)
+		return queue.Stats().Replayed == n
+	}, 5*time.Second, time.Millisecond)
+}
+
+// TestWriteRetryQueue_ReplaysInOrderOnceConnected verifies writes failed during an outage are
+// held while the database is disconnected and replayed oldest first once it is connected
+func TestWriteRetryQueue_ReplaysInOrderOnceConnected(t *testing.T) {
+	client := &blipClient{}
+	clock := testutil.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	queue := db.NewWriteRetryQueue(writeRetryOptions, clock, zerolog.Nop())
+	runWriteRetry(t, queue, client)
+
+	for _, value := range []string{"first", "second", "third"} {
+		require.True(t, queue.Add("test", client.write(value)))
+	}
+	for i := 0; i < 5; i++ {
+		clock.Advance(time.Second)
+	}
+	assert.Empty(t, client.stored(), "nothing is replayed while disconnected")
+	assert.Equal(t, 3, queue.Stats().Pending)
+
+	client.connected.Store(true)
+	awaitReplayed(t, queue, clock, 3)
+	assert.Equal(t, []string{"first", "second", "third"}, client.stored())
+	assert.Equal(t, db.WriteRetryStats{Queued: 3, Replayed: 3}, queue.Stats())
+}
+
+// TestWriteRetryQueue_FailedReplayKeepsOrder verifies a write failing on replay is retried before
+// the writes queued after it
+func TestWriteRetryQueue_FailedReplayKeepsOrder(t *testing.T) {
+	client := &blipClient{}
+	client.connected.Store(true)
+	clock := testutil.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	queue := db.NewWriteRetryQueue(writeRetryOptions, clock, zerolog.Nop())
+
+	var attempts atomic.Int32
+	flaky := func(ctx context.Context) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("not primary")
+		}
+		return client.write("flaky")(ctx)
+	}
+	queue.Add("test", flaky)
+	queue.Add("test", client.write("after"))
+	runWriteRetry(t, queue, client)
+
+	awaitReplayed(t, queue, clock, 2)
+	assert.Equal(t, []string{"flaky", "after"}, client.stored())
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+// TestWriteRetryQueue_DropsOldestBeyondCapacity verifies a full queue drops its oldest write
+func TestWriteRetryQueue_DropsOldestBeyondCapacity(t *testing.T) {
+	client := &blipClient{}
+	clock := testutil.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	opts := writeRetryOptions
+	opts.Capacity = 2
+	queue := db.NewWriteRetryQueue(opts, clock, zerolog.Nop())
+
+	for _, value := range []string{"oldest", "middle", "newest"} {
+		queue.Add("test", client.write(value))
+	}
+	assert.Equal(t, db.WriteRetryStats{Pending: 2, Queued: 3, Dropped: 1}, queue.Stats())
+
+	client.connected.Store(true)
+	runWriteRetry(t, queue, client)
+	awaitReplayed(t, queue, clock, 2)
+	assert.Equal(t, []string{"middle", "newest"}, client.stored())
+}
+
+func TestWriteRetryQueue_NilKeepsNothing(t *testing.T) {
+	var queue *db.WriteRetryQueue
+	assert.False(t, queue.Add("test", func(ctx context.Context) error { return nil }))
+}
@@ -0,0 +1,106 @@
+package export_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/air-go/internal/export"
+)
+
+// filterValueTemplates place a fuzzed JSON value where customer filters expect scalars or lists
+var filterValueTemplates = []string{
+	`{"firstName": {"eq": %s}}`,
+	`{"firstName": {"in": [%s]}}`,
+	`{"isShared": {"eq": %s}}`,
+	`{"createDate": {"gte": %s}}`,
+	`{"or": [{"lastName": {"contains": %s}}]}`,
+	`{"status": {"deletion": {"neq": %s}}}`,
+}
+
+// injectionSeeds are adversarial filter values, including MongoDB operators and JavaScript
+var injectionSeeds = []string{
+	`"Jane"`,
+	`null`,
+	`true`,
+	`1`,
+	`{"$gt": ""}`,
+	`{"$ne": null}`,
+	`{"$where": "sleep(1000)"}`,
+	`{"$regex": ".*"}`,
+	`["a", {"$ne": null}]`,
+	`[["a"]]`,
+	`{"eq": "Jane"}`,
+	`"{\"$gt\": \"\"}"`,
+}
+
+// hasObject reports whether a decoded JSON value is or holds an object
+func hasObject(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		for _, element := range v {
+			if hasObject(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasOperatorKey reports whether a decoded JSON value holds an object key starting with $
+func hasOperatorKey(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			if strings.HasPrefix(key, "$") || hasOperatorKey(element) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, element := range v {
+			if hasOperatorKey(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FuzzExportFilterValues verifies a filter value that is not a scalar is rejected before a
+// pipeline reaches the database, wherever it is placed in the request body
+func FuzzExportFilterValues(f *testing.F) {
+	for _, seed := range injectionSeeds {
+		f.Add(seed)
+	}
+	opts := export.Options{MaxRows: 10, BatchSize: 10, Timeout: 5 * time.Second}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return
+		}
+		for _, template := range filterValueTemplates {
+			where := fmt.Sprintf(template, value)
+			collection := &fakeCollection{}
+			rec := serveExport(t, &fakeDBClient{collection: collection}, opts, "customer", `{"where": `+where+`}`)
+
+			if hasObject(decoded) {
+				if rec.Code != http.StatusBadRequest || collection.pipeline != nil {
+					t.Fatalf("where %s: got status %d and pipeline %v, want 400 and no query", where, rec.Code, collection.pipeline)
+				}
+			}
+		}
+
+		// The value as the whole filter: operator keys match no filter field
+		collection := &fakeCollection{}
+		rec := serveExport(t, &fakeDBClient{collection: collection}, opts, "customer", `{"where": `+value+`}`)
+		if hasOperatorKey(decoded) && (rec.Code != http.StatusBadRequest || collection.pipeline != nil) {
+			t.Fatalf("where %s: got status %d and pipeline %v, want 400 and no query", value, rec.Code, collection.pipeline)
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package export_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/export"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// fakeCollection serves aggregation results from in-memory documents
+type fakeCollection struct {
+	db.Collection
+	docs     []interface{}
+	pipeline interface{}
+	opts     []*options.AggregateOptions
+	inserted []interface{}
+}
+
+func (c *fakeCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	c.pipeline = pipeline
+	c.opts = opts
+	return mongo.NewCursorFromDocuments(c.docs, nil, nil)
+}
+
+func (c *fakeCollection) InsertMany(ctx context.Context, documents []interface{}) (*mongo.InsertManyResult, error) {
+	c.inserted = append(c.inserted, documents...)
+	return &mongo.InsertManyResult{}, nil
+}
+
+// fakeDBClient returns the same fake collection for every name
+type fakeDBClient struct {
+	collection *fakeCollection
+}
+
+func (c *fakeDBClient) HealthStatus(ctx context.Context) (*db.HealthStatus, error) {
+	return &db.HealthStatus{Status: "connected"}, nil
+}
+
+func (c *fakeDBClient) Collection(name string) db.Collection {
+	return c.collection
+}
+
+func (c *fakeDBClient) ReadCollection(name string) db.Collection {
+	return c.collection
+}
+
+func (c *fakeDBClient) IsConnected() bool {
+	return true
+}
+
+// flushRecorder tracks how many bytes are written between flushes
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	pending      int
+	maxUnflushed int
+	flushCount   int
+}
+
+func (r *flushRecorder) Write(p []byte) (int, error) {
+	r.pending += len(p)
+	if r.pending > r.maxUnflushed {
+		r.maxUnflushed = r.pending
+	}
+	return r.ResponseRecorder.Write(p)
+}
+
+func (r *flushRecorder) Flush() {
+	r.pending = 0
+	r.flushCount++
+	r.ResponseRecorder.Flush()
+}
+
+func seedDocuments(count int) []interface{} {
+	docs := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		docs = append(docs, bson.M{
+			"identifier": fmt.Sprintf("00000000-0000-4000-8000-%012d", i),
+			"firstName":  fmt.Sprintf("Customer %d", i),
+			"status":     bson.M{"deletion": "INIT"},
+		})
+	}
+	return docs
+}
+
+func serveExport(t *testing.T, client *fakeDBClient, opts export.Options, entity, body string) *flushRecorder {
+	router := chi.NewRouter()
+	router.Post("/export/{entity}", export.Handler(client, opts))
+
+	req := httptest.NewRequest(http.MethodPost, "/export/"+entity, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func countLines(t *testing.T, body string) int {
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	lines := 0
+	for scanner.Scan() {
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &doc))
+		require.NotEmpty(t, doc["identifier"])
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestExportHandler_StreamsLargeExport(t *testing.T) {
+	client := &fakeDBClient{collection: &fakeCollection{docs: seedDocuments(5000)}}
+	opts := export.Options{MaxRows: 10000, BatchSize: 250, Timeout: time.Minute}
+
+	rec := serveExport(t, client, opts, "customer", `{"order":[{"firstName":"ASC"}]}`)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Equal(t, 5000, countLines(t, rec.Body.String()))
+
+	trailer := rec.Result().Trailer
+	assert.Equal(t, "5000", trailer.Get(export.TrailerRowCount))
+	assert.Equal(t, "false", trailer.Get(export.TrailerTruncated))
+	assert.Empty(t, trailer.Get(export.TrailerError))
+
+	// The response is flushed every batch instead of being buffered in full
+	assert.GreaterOrEqual(t, rec.flushCount, 5000/opts.BatchSize)
+	assert.Less(t, rec.maxUnflushed, rec.Body.Len()/10)
+
+	// The cursor is batched server-side
+	require.Len(t, client.collection.opts, 1)
+	assert.Equal(t, int32(250), *client.collection.opts[0].BatchSize)
+}
+
+func TestExportHandler_RowCap(t *testing.T) {
+	client := &fakeDBClient{collection: &fakeCollection{docs: seedDocuments(1500)}}
+	opts := export.Options{MaxRows: 1000, BatchSize: 100, Timeout: time.Minute}
+
+	rec := serveExport(t, client, opts, "customer", `{}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1000, countLines(t, rec.Body.String()))
+
+	trailer := rec.Result().Trailer
+	assert.Equal(t, "1000", trailer.Get(export.TrailerRowCount))
+	assert.Equal(t, "true", trailer.Get(export.TrailerTruncated))
+
+	// One extra row is requested to detect truncation
+	pipeline := client.collection.pipeline.([]bson.M)
+	assert.Equal(t, bson.M{"$limit": 1001}, pipeline[len(pipeline)-1])
+}
+
+func TestExportHandler_ExcludesDeleted(t *testing.T) {
+	client := &fakeDBClient{collection: &fakeCollection{}}
+	opts := export.Options{MaxRows: 10, BatchSize: 10, Timeout: time.Minute}
+
+	rec := serveExport(t, client, opts, "customer", `{"where":{"firstName":{"eq":"Alice"}}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	pipeline := client.collection.pipeline.([]bson.M)
+	match, ok := pipeline[0]["$match"].(bson.M)
+	require.True(t, ok)
+	and, ok := match["$and"].([]bson.M)
+	require.True(t, ok)
+	assert.Equal(t, bson.M{"status.deletion": bson.M{"$ne": "DELETED"}}, and[0])
+	assert.Equal(t, "0", rec.Result().Trailer.Get(export.TrailerRowCount))
+}
+
+// TestExportHandler_Inventory verifies inventories export with the filter and order of the search query
+func TestExportHandler_Inventory(t *testing.T) {
+	client := &fakeDBClient{collection: &fakeCollection{docs: seedDocuments(3)}}
+	opts := export.Options{MaxRows: 10, BatchSize: 10, Timeout: time.Minute}
+
+	rec := serveExport(t, client, opts, "inventory", `{"where":{"key":{"eq":"INV-1"}},"order":[{"customerId":"DESC"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, 3, countLines(t, rec.Body.String()))
+
+	pipeline := client.collection.pipeline.([]bson.M)
+	and := pipeline[0]["$match"].(bson.M)["$and"].([]bson.M)
+	assert.Contains(t, and, bson.M{"key": "INV-1"})
+}
+
+func TestExportHandler_InvalidInput(t *testing.T) {
+	client := &fakeDBClient{collection: &fakeCollection{}}
+	opts := export.Options{MaxRows: 10, BatchSize: 10, Timeout: time.Minute}
+
+	tests := []struct {
+		name     string
+		entity   string
+		body     string
+		expected int
+	}{
+		{"unknown entity", "unknown", `{}`, http.StatusNotFound},
+		{"unknown inventory filter field", "inventory", `{"where":{"firstName":{"eq":"Ada"}}}`, http.StatusBadRequest},
+		{"unknown filter field", "customer", `{"where":{"shoeSize":{"eq":"42"}}}`, http.StatusBadRequest},
+		{"invalid sort direction", "customer", `{"order":[{"firstName":"SIDEWAYS"}]}`, http.StatusBadRequest},
+		{"malformed body", "customer", `{"where":`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveExport(t, client, opts, tt.entity, tt.body)
+			assert.Equal(t, tt.expected, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestExportHandler_AuthorizationScope(t *testing.T) {
+	client := &fakeDBClient{collection: &fakeCollection{}}
+	opts := export.Options{MaxRows: 10, BatchSize: 10, Timeout: time.Minute}
+
+	router := chi.NewRouter()
+	router.With(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := testutil.WithSupportAgentContext(r.Context(), "agent-a", "TEAM_A")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}).Post("/export/{entity}", export.Handler(client, opts))
+
+	req := httptest.NewRequest(http.MethodPost, "/export/customer", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	pipeline := client.collection.pipeline.([]bson.M)
+	match := pipeline[0]["$match"].(bson.M)
+	and := match["$and"].([]bson.M)
+	assert.Equal(t, bson.M{"customerGroups": bson.M{"$in": []string{"TEAM_A"}}}, and[len(and)-1])
+}
+
+func TestExportHandler_RecordsAccess(t *testing.T) {
+	require.NoError(t, resolvers.SetAccessAudit(resolvers.AccessAuditOptions{
+		Entities: []string{"customer"}, QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour,
+	}))
+	t.Cleanup(func() { require.NoError(t, resolvers.SetAccessAudit(resolvers.AccessAuditOptions{})) })
+
+	client := &fakeDBClient{collection: &fakeCollection{docs: seedDocuments(250)}}
+	opts := export.Options{MaxRows: 1000, BatchSize: 100, Timeout: time.Minute}
+	rec := serveExport(t, client, opts, "customer", `{"where":{"firstName":{"startsWith":"Customer"}}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resolvers.RunAccessAuditWriter(ctx, client, zerolog.Nop())
+
+	// One event per flushed batch, together naming every exported customer
+	require.Len(t, client.collection.inserted, 3)
+	var identifiers []string
+	for _, inserted := range client.collection.inserted {
+		event := inserted.(bson.M)
+		assert.Equal(t, "customer", event["entity"])
+		assert.Equal(t, "SEARCH", event["queryType"])
+		assert.Len(t, event["filterDigest"], 64)
+		identifiers = append(identifiers, event["identifiers"].([]string)...)
+	}
+	require.Len(t, identifiers, 250)
+	assert.Equal(t, "00000000-0000-4000-8000-000000000249", identifiers[249])
+}
@@ -0,0 +1,33 @@
+package genchk_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGeneratedCodeMatchesSchema runs cmd/genchk, which fails with a summary of the changed types
+// and fields when internal/graphql/generated was not regenerated after a schema change
+func TestGeneratedCodeMatchesSchema(t *testing.T) {
+	if testing.Short() {
+		t.Skip("regenerating the GraphQL code takes a while")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is not available")
+	}
+
+	cmd := exec.Command(goTool, "run", "./cmd/genchk")
+	cmd.Dir = "../../.."
+	cmd.Env = append(os.Environ(), "GOPROXY=off", "GOTOOLCHAIN=local")
+	output, err := cmd.CombinedOutput()
+	if err != nil && bytes.Contains(output, []byte("without types was imported")) {
+		// gqlgen's golang.org/x/tools cannot read the export data of a newer Go toolchain, so
+		// gqlgen generate itself fails; run the check with the toolchain of go.mod
+		t.Skipf("gqlgen cannot load packages with this Go toolchain:\n%s", output)
+	}
+	require.NoError(t, err, "%s", output)
+}
@@ -0,0 +1,44 @@
+package graphql_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// newComplexityTestHandler builds a GraphQL handler with the relation complexity functions and limit
+func newComplexityTestHandler() http.Handler {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  &resolvers.Resolver{},
+		Complexity: resolvers.Complexity(),
+	}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.FixedComplexityLimit(resolvers.CurrentLimits().MaxComplexity))
+	return srv
+}
+
+func TestComplexity_CircularRelationsRejected(t *testing.T) {
+	h := newComplexityTestHandler()
+
+	resp := postGraphQL(t, h, queryBody(t, `{ teamSearch { data { members { teams { members { identifier } } } } } }`, ""))
+	require.NotEmpty(t, resp.Errors)
+	assert.Contains(t, resp.Errors[0].Message, "exceeds the limit")
+	assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", resp.Errors[0].Extensions["code"])
+}
+
+func TestComplexity_BoundedRelationsAccepted(t *testing.T) {
+	h := newComplexityTestHandler()
+
+	// Small first arguments keep the same selection under the limit; the nil database then fails the query
+	resp := postGraphQL(t, h, queryBody(t, `{ teamSearch { data { members(first: 5) { teams(first: 5) { members(first: 5) { identifier } } } } } }`, ""))
+	require.NotEmpty(t, resp.Errors)
+	assert.NotContains(t, resp.Errors[0].Message, "exceeds the limit")
+}
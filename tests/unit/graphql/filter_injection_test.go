@@ -0,0 +1,156 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// recordingCollection records the aggregation pipelines it receives and returns empty search results
+type recordingCollection struct {
+	db.Collection
+	pipelines []interface{}
+}
+
+func (c *recordingCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	c.pipelines = append(c.pipelines, pipeline)
+	return mongo.NewCursorFromDocuments([]interface{}{bson.M{"metadata": bson.A{}, "data": bson.A{}}}, nil, nil)
+}
+
+// recordingDB returns the same recording collection for every name
+type recordingDB struct {
+	collection *recordingCollection
+}
+
+func (d *recordingDB) Collection(name string) db.Collection     { return d.collection }
+func (d *recordingDB) ReadCollection(name string) db.Collection { return d.collection }
+func (d *recordingDB) IsConnected() bool                        { return true }
+func (d *recordingDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) {
+	return &db.HealthStatus{Status: "connected"}, nil
+}
+
+const searchWithVariablesQuery = `query($where: CustomerQueryFilterInput) { customerSearch(where: $where) { totalCount } }`
+
+// filterValueTemplates place a fuzzed JSON value where customer filters expect scalars or lists
+var filterValueTemplates = []string{
+	`{"firstName": {"eq": %s}}`,
+	`{"firstName": {"in": [%s]}}`,
+	`{"isShared": {"eq": %s}}`,
+	`{"createDate": {"gte": %s}}`,
+	`{"or": [{"lastName": {"contains": %s}}]}`,
+	`{"status": {"deletion": {"neq": %s}}}`,
+}
+
+// searchWithWhere runs customerSearch with where as the $where variable and returns the response
+// and the pipelines that reached the database
+func searchWithWhere(t *testing.T, where string) (map[string]json.RawMessage, []interface{}) {
+	collection := &recordingCollection{}
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolvers.NewResolver(&recordingDB{collection: collection})}))
+	srv.SetErrorPresenter(resolvers.ErrorPresenter)
+	srv.AddTransport(transport.POST{})
+
+	query, err := json.Marshal(searchWithVariablesQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := `{"query": ` + string(query) + `, "variables": {"where": ` + where + `}}`
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("where %s: %v: %s", where, err, rec.Body.String())
+	}
+	return resp, collection.pipelines
+}
+
+// TestSearchFilterVariables_ScalarReachesDatabase verifies a scalar filter variable is searched
+// for as a plain value, so the fuzz test below observes the pipelines of accepted filters
+func TestSearchFilterVariables_ScalarReachesDatabase(t *testing.T) {
+	resp, pipelines := searchWithWhere(t, `{"firstName": {"eq": "$gt"}}`)
+	require.Empty(t, resp["errors"])
+	require.Len(t, pipelines, 1)
+	match := pipelines[0].([]bson.M)[0]["$match"]
+	assert.Contains(t, fmt.Sprint(match), "firstName:$gt")
+}
+
+// FuzzSearchFilterVariables verifies a filter variable holding an object where a scalar is
+// expected, or an operator key anywhere, fails the query before a pipeline reaches the database
+func FuzzSearchFilterVariables(f *testing.F) {
+	for _, seed := range []string{
+		`"Jane"`, `null`, `true`, `1`,
+		`{"$gt": ""}`, `{"$ne": null}`, `{"$where": "sleep(1000)"}`, `{"$regex": ".*"}`,
+		`["a", {"$ne": null}]`, `[["a"]]`, `{"eq": "Jane"}`, `"{\"$gt\": \"\"}"`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return
+		}
+		check := func(where string, mustFail bool) {
+			resp, pipelines := searchWithWhere(t, where)
+			if mustFail && (len(resp["errors"]) == 0 || len(pipelines) > 0) {
+				t.Fatalf("where %s: got errors %s and %d pipelines, want an error and no query", where, resp["errors"], len(pipelines))
+			}
+		}
+		for _, template := range filterValueTemplates {
+			check(fmt.Sprintf(template, value), hasObject(decoded))
+		}
+		check(value, hasOperatorKey(decoded))
+	})
+}
+
+// hasObject reports whether a decoded JSON value is or holds an object
+func hasObject(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		for _, element := range v {
+			if hasObject(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasOperatorKey reports whether a decoded JSON value holds an object key starting with $
+func hasOperatorKey(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			if strings.HasPrefix(key, "$") || hasOperatorKey(element) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, element := range v {
+			if hasOperatorKey(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/persisted"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+const aliveQuery = "query Alive { alive }"
+
+type graphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// newTestHandler builds a GraphQL handler, optionally guarded by a persisted operations manifest
+func newTestHandler(manifest *persisted.Manifest) http.Handler {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &resolvers.Resolver{}}))
+	srv.AddTransport(transport.POST{})
+	if manifest != nil {
+		srv.Use(persisted.Extension{Manifest: manifest})
+	}
+	return srv
+}
+
+func postGraphQL(t *testing.T, h http.Handler, body string) graphQLResponse {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp graphQLResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+	return resp
+}
+
+func queryBody(t *testing.T, query string, hash string) string {
+	body := map[string]interface{}{}
+	if query != "" {
+		body["query"] = query
+	}
+	if hash != "" {
+		body["extensions"] = map[string]interface{}{
+			"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": hash},
+		}
+	}
+	encoded, err := json.Marshal(body)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestPersistedOperations_ManifestHit(t *testing.T) {
+	manifest := persisted.NewManifest(map[string]string{persisted.Hash(aliveQuery): aliveQuery})
+	h := newTestHandler(manifest)
+
+	t.Run("full query text", func(t *testing.T) {
+		resp := postGraphQL(t, h, queryBody(t, aliveQuery, ""))
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, true, resp.Data["alive"])
+	})
+
+	t.Run("hash only", func(t *testing.T) {
+		resp := postGraphQL(t, h, queryBody(t, "", persisted.Hash(aliveQuery)))
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, true, resp.Data["alive"])
+	})
+}
+
+func TestPersistedOperations_ManifestMiss(t *testing.T) {
+	manifest := persisted.NewManifest(map[string]string{persisted.Hash(aliveQuery): aliveQuery})
+	h := newTestHandler(manifest)
+
+	t.Run("ad-hoc query", func(t *testing.T) {
+		resp := postGraphQL(t, h, queryBody(t, "{ alive }", ""))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, resolvers.ErrCodeOperationNotAllowed, resp.Errors[0].Extensions["code"])
+		assert.Nil(t, resp.Data)
+	})
+
+	t.Run("unknown hash", func(t *testing.T) {
+		resp := postGraphQL(t, h, queryBody(t, "", persisted.Hash("{ alive }")))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, resolvers.ErrCodeOperationNotAllowed, resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("hash does not match query", func(t *testing.T) {
+		resp := postGraphQL(t, h, queryBody(t, aliveQuery, persisted.Hash("{ alive }")))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, resolvers.ErrCodeInvalidInput, resp.Errors[0].Extensions["code"])
+	})
+}
+
+func TestPersistedOperations_ModeOff(t *testing.T) {
+	h := newTestHandler(nil)
+
+	resp := postGraphQL(t, h, queryBody(t, "{ alive }", ""))
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, true, resp.Data["alive"])
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid manifest", func(t *testing.T) {
+		path := filepath.Join(dir, "valid.json")
+		content, _ := json.Marshal(map[string]string{persisted.Hash(aliveQuery): aliveQuery})
+		require.NoError(t, os.WriteFile(path, content, 0o600))
+
+		manifest, err := persisted.LoadManifest(path)
+		require.NoError(t, err)
+		assert.Equal(t, 1, manifest.Len())
+	})
+
+	t.Run("entry with wrong hash", func(t *testing.T) {
+		path := filepath.Join(dir, "tampered.json")
+		content, _ := json.Marshal(map[string]string{persisted.Hash(aliveQuery): "{ health { status } }"})
+		require.NoError(t, os.WriteFile(path, content, 0o600))
+
+		_, err := persisted.LoadManifest(path)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match its query hash")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := persisted.LoadManifest(filepath.Join(dir, "missing.json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestExtractOperations_IncludesOnlyUsedFragments(t *testing.T) {
+	content := `
+query Customer($id: UUID!) { customerGet(identifier: $id) { ...CustomerFields } }
+query Health { health { status } }
+fragment CustomerFields on Customer { identifier firstName }
+`
+	queries, err := persisted.ExtractOperations("ops.graphql", content)
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+
+	assert.Contains(t, queries[0], "query Customer")
+	assert.Contains(t, queries[0], "fragment CustomerFields on Customer")
+	assert.Contains(t, queries[1], "query Health")
+	assert.NotContains(t, queries[1], "fragment CustomerFields")
+}
@@ -0,0 +1,101 @@
+package graphql_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/scalars"
+)
+
+// marshaled renders a scalar the way it is written into a response
+func marshaled(m graphql.Marshaler) string {
+	var buf bytes.Buffer
+	m.MarshalGQL(&buf)
+	return buf.String()
+}
+
+// Test DateTime inputs with offsets, fractional seconds and date-only values are read as RFC3339 UTC
+func TestUnmarshalDateTime_Normalizes(t *testing.T) {
+	tests := map[string]string{
+		"2024-01-31T12:00:00Z":          "2024-01-31T12:00:00Z",
+		"2024-01-31T13:30:00+01:30":     "2024-01-31T12:00:00Z",
+		"2024-01-31T22:00:00-05:00":     "2024-02-01T03:00:00Z",
+		"2024-01-31T12:00:00.250+00:00": "2024-01-31T12:00:00.25Z",
+		"2024-01-31":                    "2024-01-31T00:00:00Z",
+		"":                              "",
+	}
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			value, err := scalars.UnmarshalDateTime(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, value)
+
+			// Normalized values read back unchanged
+			again, err := scalars.UnmarshalDateTime(value)
+			require.NoError(t, err)
+			assert.Equal(t, value, again)
+		})
+	}
+}
+
+// Test invalid DateTime inputs are rejected with the accepted formats
+func TestUnmarshalDateTime_Invalid(t *testing.T) {
+	for _, input := range []interface{}{"yesterday", "2024-13-01", "2024-01-31 12:00:00", "31.01.2024", 1706702400} {
+		_, err := scalars.UnmarshalDateTime(input)
+		require.Error(t, err, "%v", input)
+	}
+
+	_, err := scalars.UnmarshalDateTime("yesterday")
+	assert.EqualError(t, err, `invalid DateTime "yesterday": expected an RFC3339 timestamp (2024-01-31T12:00:00Z, 2024-01-31T13:00:00+01:00) or a date (2024-01-31)`)
+}
+
+// Test stored timestamps are written as RFC3339 UTC, and values that are no timestamp unchanged
+func TestMarshalDateTime(t *testing.T) {
+	assert.Equal(t, `"2024-01-31T12:00:00Z"`, marshaled(scalars.MarshalDateTime("2024-01-31T14:00:00+02:00")))
+	assert.Equal(t, `"2024-01-31T00:00:00Z"`, marshaled(scalars.MarshalDateTime("2024-01-31")))
+	assert.Equal(t, `"unknown"`, marshaled(scalars.MarshalDateTime("unknown")))
+}
+
+// Test Date inputs keep dates and take the UTC date of timestamps
+func TestUnmarshalDate_Normalizes(t *testing.T) {
+	tests := map[string]string{
+		"1985-06-15":                "1985-06-15",
+		"1985-06-15T00:00:00Z":      "1985-06-15",
+		"1985-06-15T23:30:00-02:00": "1985-06-16",
+		"1985-06-15T01:00:00+02:00": "1985-06-14",
+		"":                          "",
+	}
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			value, err := scalars.UnmarshalDate(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, value)
+		})
+	}
+
+	_, err := scalars.UnmarshalDate("15.06.1985")
+	assert.EqualError(t, err, `invalid Date "15.06.1985": expected a date (2024-01-31) or an RFC3339 timestamp (2024-01-31T12:00:00Z)`)
+}
+
+// Test stored dates are written as YYYY-MM-DD
+func TestMarshalDate(t *testing.T) {
+	assert.Equal(t, `"1985-06-16"`, marshaled(scalars.MarshalDate("1985-06-15T23:30:00-02:00")))
+	assert.Equal(t, `"1985-06-15"`, marshaled(scalars.MarshalDate("1985-06-15")))
+}
+
+// Test FormatDateTime and ParseDateTime round-trip an instant in UTC
+func TestFormatDateTime_RoundTrip(t *testing.T) {
+	instant := time.Date(2024, 1, 31, 13, 0, 0, 500_000_000, time.FixedZone("CET", 3600))
+	formatted := scalars.FormatDateTime(instant)
+	assert.Equal(t, "2024-01-31T12:00:00.5Z", formatted)
+
+	parsed, err := scalars.ParseDateTime(formatted)
+	require.NoError(t, err)
+	assert.True(t, instant.Equal(parsed))
+	assert.Equal(t, time.UTC, parsed.Location())
+}
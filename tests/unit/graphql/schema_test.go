@@ -0,0 +1,142 @@
+package graphql_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+const schemaPath = "../../../schema.graphqls"
+
+// Snapshot test: documented limits must follow the constants enforced by the resolvers
+func TestLoadSchema_DescriptionsMatchConstants(t *testing.T) {
+	schema, err := graphql.LoadSchema(schemaPath)
+	require.NoError(t, err)
+
+	assert.NotContains(t, schema.RawContent, "{{", "schema must not contain unresolved placeholders")
+
+	maxBatchSize := strconv.Itoa(resolvers.MaxBatchSize)
+	defaultPageSize := strconv.Itoa(resolvers.DefaultPageSize)
+
+	byKeysFields := []string{
+		"customerByKeysGet", "employeeByKeysGet", "teamByKeysGet",
+		"byKeysGet", "executionPlanByKeysGet", "referencePortfolioByKeysGet",
+	}
+	for _, name := range byKeysFields {
+		field := schema.Schema.Query.Fields.ForName(name)
+		require.NotNil(t, field, name)
+		assert.Contains(t, field.Description, "at most "+maxBatchSize, name)
+		assert.Contains(t, field.Arguments.ForName("identifiers").Description, maxBatchSize, name)
+	}
+
+	searchFields := []string{
+		"customerSearch", "employeeSearch", "teamSearch",
+		"search", "executionPlanSearch", "referencePortfolioSearch",
+	}
+	for _, name := range searchFields {
+		field := schema.Schema.Query.Fields.ForName(name)
+		require.NotNil(t, field, name)
+		assert.Contains(t, field.Description, "a page holds "+defaultPageSize+" entries", name)
+		assert.Contains(t, field.Arguments.ForName("first").Description, "0-"+maxBatchSize, name)
+		assert.Contains(t, field.Arguments.ForName("last").Description, "0-"+maxBatchSize, name)
+	}
+}
+
+// Deletion semantics in descriptions come from the entity configuration
+func TestLoadSchema_DescriptionsDocumentDeletionRules(t *testing.T) {
+	schema, err := graphql.LoadSchema(schemaPath)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"customerGet":            "`status.deletion` is `DELETED`",
+		"teamSearch":             "`status.deletion` is `DELETED`",
+		"inventoryGet":           "`actionIndicator` is `DELETE`",
+		"executionPlanByKeysGet": "`actionIndicator` is `DELETE`",
+	}
+
+	for name, rule := range tests {
+		field := schema.Schema.Query.Fields.ForName(name)
+		require.NotNil(t, field, name)
+		assert.Contains(t, field.Description, rule, name)
+	}
+}
+
+// Deprecated fields expose their reason through the @deprecated directive used by introspection
+func TestLoadSchema_DeprecatedFields(t *testing.T) {
+	schema, err := graphql.LoadSchema(schemaPath)
+	require.NoError(t, err)
+
+	for _, name := range []string{"create", "update"} {
+		field := schema.Schema.Mutation.Fields.ForName(name)
+		require.NotNil(t, field, name)
+
+		deprecated := field.Directives.ForName("deprecated")
+		require.NotNil(t, deprecated, name)
+		reason := deprecated.Arguments.ForName("reason")
+		require.NotNil(t, reason, name)
+		assert.True(t, strings.HasPrefix(reason.Value.Raw, "Use referencePortfolio"), name)
+	}
+}
+
+// Checksum identifies the schema build: stable for identical text, different once the fixture changes
+func TestLoadSchema_ChecksumTracksSchemaText(t *testing.T) {
+	original, err := os.ReadFile(schemaPath)
+	require.NoError(t, err)
+
+	fixture := filepath.Join(t.TempDir(), "schema.graphqls")
+	require.NoError(t, os.WriteFile(fixture, original, 0o644))
+
+	first, err := graphql.LoadSchema(fixture)
+	require.NoError(t, err)
+	assert.Len(t, first.Checksum, 64, "checksum should be a hex SHA-256")
+
+	again, err := graphql.LoadSchema(fixture)
+	require.NoError(t, err)
+	assert.Equal(t, first.Checksum, again.Checksum)
+
+	// Add a field to the fixture
+	changed := strings.Replace(string(original), "type Query {\n  alive: Boolean!\n", "type Query {\n  alive: Boolean!\n  ping: String\n", 1)
+	require.NotEqual(t, string(original), changed)
+	require.NoError(t, os.WriteFile(fixture, []byte(changed), 0o644))
+
+	modified, err := graphql.LoadSchema(fixture)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Checksum, modified.Checksum)
+}
+
+// The schema file is implemented by this build; fields, enum values and changed types it does
+// not implement are reported, while leaving definitions out is allowed
+func TestCheckImplemented(t *testing.T) {
+	schema, err := graphql.LoadSchema(schemaPath)
+	require.NoError(t, err)
+	assert.Empty(t, graphql.CheckImplemented(schema.Schema))
+
+	original, err := os.ReadFile(schemaPath)
+	require.NoError(t, err)
+	changed := strings.Replace(string(original), "type Query {\n  alive: Boolean!\n", "type Query {\n  alive: String\n  ping: String\n", 1)
+	changed = strings.Replace(changed, "enum ActionIndicator {\n", "enum ActionIndicator {\n  ARCHIVE\n", 1)
+	fixture := filepath.Join(t.TempDir(), "schema.graphqls")
+	require.NoError(t, os.WriteFile(fixture, []byte(changed), 0o644))
+
+	modified, err := graphql.LoadSchema(fixture)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"enum value ActionIndicator.ARCHIVE is not implemented by this build",
+		"Query.alive has type String, this build implements Boolean!",
+		"Query.ping is not implemented by this build",
+	}, graphql.CheckImplemented(modified.Schema))
+
+	subset := filepath.Join(t.TempDir(), "subset.graphqls")
+	require.NoError(t, os.WriteFile(subset, []byte("type Query {\n  alive: Boolean!\n}\n"), 0o644))
+	reduced, err := graphql.LoadSchema(subset)
+	require.NoError(t, err)
+	assert.Empty(t, graphql.CheckImplemented(reduced.Schema))
+}
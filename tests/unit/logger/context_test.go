@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/logger"
+	"github.com/yourusername/air-go/internal/server/middleware"
+)
+
+// TestFromContext_RequestFields verifies loggers taken from a request's context carry the request
+// ID set by the logging middleware and the user ID of the principal
+func TestFromContext_RequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	global := log.Logger
+	log.Logger = zerolog.New(io.Discard)
+	t.Cleanup(func() { log.Logger = global })
+
+	handler := middleware.LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := resolvers.WithUserClaims(r.Context(), &resolvers.UserClaims{UserID: "alice"})
+		logger.Enrich(ctx, zerolog.New(&buf)).Info().Msg("inside")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+	assert.Contains(t, buf.String(), `"user_id":"alice"`)
+}
+
+// TestFromContext_Fallback verifies contexts without fields use the base logger unchanged
+func TestFromContext_Fallback(t *testing.T) {
+	var buf bytes.Buffer
+	global := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = global })
+
+	logger.FromContext(context.Background()).Info().Msg("plain")
+	assert.JSONEq(t, `{"level":"info","message":"plain"}`, buf.String())
+}
+
+// TestWithContextField_Replaces verifies a field set again keeps only its latest value
+func TestWithContextField_Replaces(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := logger.WithContextField(context.Background(), "request_id", "batch")
+	ctx = logger.WithContextField(ctx, "request_id", "batch-1")
+
+	logger.Enrich(ctx, zerolog.New(&buf)).Info().Msg("operation")
+	assert.JSONEq(t, `{"level":"info","request_id":"batch-1","message":"operation"}`, buf.String())
+}
+
+// BenchmarkEnrich measures the per-call cost of taking a logger from a request's context for a
+// disabled debug entry, as the collection wrapper does for every operation
+func BenchmarkEnrich(b *testing.B) {
+	base := zerolog.New(io.Discard).Level(zerolog.InfoLevel)
+	ctx := logger.WithContextField(context.Background(), "request_id", "req-1")
+	ctx = logger.WithContextField(ctx, "user_id", "alice")
+
+	b.Run("base logger", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			base.Debug().Str("operation", "find_one").Msg("Document found")
+		}
+	})
+	b.Run("context logger", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Enrich(ctx, base).Debug().Str("operation", "find_one").Msg("Document found")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// setupFileLogger sets up a logger writing to a temporary file, restoring the global logger
+// after the test, and returns the file's path
+func setupFileLogger(t *testing.T, opts logger.Options) string {
+	t.Helper()
+	global := log.Logger
+	t.Cleanup(func() { log.Logger = global })
+
+	opts.Output = filepath.Join(t.TempDir(), "air.log")
+	_, err := logger.Setup(opts)
+	require.NoError(t, err)
+	return opts.Output
+}
+
+// logLines returns the lines written to a log file
+func logLines(t *testing.T, path string) []string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(content)), "\n")
+}
+
+// TestSetup_Level verifies debug entries are suppressed at info level, and that the returned
+// logger is the installed global one
+func TestSetup_Level(t *testing.T) {
+	path := setupFileLogger(t, logger.Options{Format: "json", Level: "info"})
+
+	log.Debug().Msg("hidden")
+	log.Info().Msg("shown")
+	log.Warn().Msg("also shown")
+
+	lines := logLines(t, path)
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"message":"shown"`)
+	assert.Contains(t, lines[1], `"level":"warn"`)
+	assert.NotContains(t, lines[0], `"caller"`)
+}
+
+// TestSetup_Sampling verifies sampled levels write about every Nth entry while others write all
+func TestSetup_Sampling(t *testing.T) {
+	path := setupFileLogger(t, logger.Options{Format: "json", Level: "debug", Sampling: "debug=100", Caller: true})
+
+	for i := 0; i < 1000; i++ {
+		log.Debug().Int("i", i).Msg("repetitive")
+	}
+	for i := 0; i < 10; i++ {
+		log.Info().Int("i", i).Msg("important")
+	}
+
+	debug, info := 0, 0
+	for _, line := range logLines(t, path) {
+		switch {
+		case strings.Contains(line, `"level":"debug"`):
+			debug++
+		case strings.Contains(line, `"level":"info"`):
+			info++
+		}
+		assert.Contains(t, line, `"caller"`)
+	}
+	assert.InDelta(t, 10, debug, 2)
+	assert.Equal(t, 10, info)
+}
+
+func TestSetup_InvalidOptions(t *testing.T) {
+	global := log.Logger
+	t.Cleanup(func() { log.Logger = global })
+
+	for name, opts := range map[string]logger.Options{
+		"level":    {Level: "verbose"},
+		"sampling": {Sampling: "debug"},
+		"output":   {Output: filepath.Join(t.TempDir(), "missing", "air.log")},
+	} {
+		_, err := logger.Setup(opts)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseSampling(t *testing.T) {
+	rates, err := logger.ParseSampling(" debug=100, info=10 ")
+	require.NoError(t, err)
+	assert.Len(t, rates, 2)
+
+	for _, spec := range []string{"debug=0", "debug=x", "fatal=2", "debug"} {
+		_, err := logger.ParseSampling(spec)
+		assert.Error(t, err, spec)
+	}
+}
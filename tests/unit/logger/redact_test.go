@@ -0,0 +1,141 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/logger"
+)
+
+// sensitiveValues are the personal values the test documents hold
+var sensitiveValues = []string{"jane.doe@example.com", "Jane", "Doe", "jane.doe", "1985-06-15", "Janet"}
+
+// renderLog renders a debug log line holding a value the way the collection wrapper logs filters
+func renderLog(t *testing.T, value interface{}) string {
+	t.Helper()
+	var buf bytes.Buffer
+	log := zerolog.New(&buf).Level(zerolog.DebugLevel)
+	log.Debug().Interface("filter", logger.Redacted(value)).Msg("Find operation completed")
+	require.True(t, json.Valid(buf.Bytes()), buf.String())
+	return buf.String()
+}
+
+// assertNoSensitiveValues fails for every personal value in a rendered log line
+func assertNoSensitiveValues(t *testing.T, rendered string) {
+	t.Helper()
+	for _, value := range sensitiveValues {
+		assert.NotContains(t, rendered, value)
+	}
+}
+
+// TestRedact_NestedDocuments verifies personal values are redacted at any depth of filters
+func TestRedact_NestedDocuments(t *testing.T) {
+	filter := bson.M{
+		"status.deletion": bson.M{"$ne": "DELETED"},
+		"$or": []bson.M{
+			{"userEmail": "jane.doe@example.com"},
+			{"$and": bson.A{
+				bson.D{{Key: "firstName", Value: bson.M{"$in": []string{"Jane", "Janet"}}}},
+				bson.M{"contact.lastName": primitive.Regex{Pattern: "^Doe", Options: "i"}},
+			}},
+		},
+		"userEmailLower": bson.M{"$gte": "jane.doe", "$lt": "jane.doe￿"},
+		"birthDate":      bson.M{"$lte": "1985-06-15"},
+		"$expr":          bson.M{"$eq": bson.A{"$FIRSTNAME", "Jane"}},
+		"nested":         map[string]interface{}{"deeper": []interface{}{map[string]string{"lastName": "Doe"}}},
+	}
+
+	rendered := renderLog(t, filter)
+	assertNoSensitiveValues(t, rendered)
+
+	// Structure, operators and other values stay readable
+	assert.Contains(t, rendered, `"$ne":"DELETED"`)
+	assert.Contains(t, rendered, `"$in":["sha256:`)
+	assert.Contains(t, rendered, `"$FIRSTNAME"`)
+	assert.Contains(t, rendered, `"userEmail":"sha256:`)
+}
+
+// TestRedact_Structs verifies documents given as structs or pointers are redacted by their bson names
+func TestRedact_Structs(t *testing.T) {
+	firstName, lastName, email, birthDate := "Jane", "Doe", "jane.doe@example.com", "1985-06-15"
+	employee := &generated.Employee{
+		Identifier: "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		FirstName:  &firstName,
+		LastName:   &lastName,
+		UserEmail:  &email,
+		BirthDate:  &birthDate,
+	}
+
+	rendered := renderLog(t, bson.M{"document": employee, "documents": []interface{}{*employee}})
+	assertNoSensitiveValues(t, rendered)
+	assert.Contains(t, rendered, "7c9e6679-7425-40de-944b-e07fc1f90ae7")
+
+	raw, err := bson.Marshal(bson.M{"profile": bson.M{"firstName": "Jane"}})
+	require.NoError(t, err)
+	assertNoSensitiveValues(t, renderLog(t, bson.Raw(raw)))
+}
+
+// TestRedact_HashPrefix verifies equal values share a hash so they can be correlated
+func TestRedact_HashPrefix(t *testing.T) {
+	first := logger.Redact(bson.M{"userEmail": "jane.doe@example.com"}).(bson.M)
+	second := logger.Redact(bson.D{{Key: "a.b.userEmail", Value: "jane.doe@example.com"}}).(bson.D)
+	other := logger.Redact(bson.M{"userEmail": "john@example.com"}).(bson.M)
+
+	assert.Regexp(t, `^sha256:[0-9a-f]{8}$`, first["userEmail"])
+	assert.Equal(t, first["userEmail"], second[0].Value)
+	assert.NotEqual(t, first["userEmail"], other["userEmail"])
+
+	// Other values and scalars outside documents are kept
+	kept := logger.Redact(bson.M{"createDate": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "count": 3}).(bson.M)
+	assert.Equal(t, 3, kept["count"])
+	assert.Equal(t, "abc", logger.Redact("abc"))
+}
+
+// TestRedact_ConfiguredFields verifies the configured list replaces the defaults
+func TestRedact_ConfiguredFields(t *testing.T) {
+	logger.SetRedactedFields([]string{"iban", " Phone "})
+	t.Cleanup(func() { logger.SetRedactedFields(logger.DefaultRedactedFields) })
+
+	redacted := logger.Redact(bson.M{"iban": "DE89370400440532013000", "phone": "+49 30 1234", "firstName": "Jane"}).(bson.M)
+	assert.Regexp(t, `^sha256:`, redacted["iban"])
+	assert.Regexp(t, `^sha256:`, redacted["phone"])
+	assert.Equal(t, "Jane", redacted["firstName"])
+}
+
+// TestRedacted_DisabledLevel verifies nothing is redacted for log lines that are not written
+func TestRedacted_DisabledLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := zerolog.New(&buf).Level(zerolog.InfoLevel)
+	log.Debug().Interface("filter", logger.Redacted(bson.M{"userEmail": "jane.doe@example.com"})).Msg("skipped")
+	assert.Empty(t, buf.String())
+}
+
+// BenchmarkRedact measures redacting a typical search filter
+func BenchmarkRedact(b *testing.B) {
+	filter := bson.M{
+		"status.deletion": bson.M{"$ne": "DELETED"},
+		"$or": []bson.M{
+			{"userEmail": "jane.doe@example.com"},
+			{"firstName": bson.M{"$in": []string{"Jane", "Janet"}}},
+		},
+	}
+	for i := 0; i < b.N; i++ {
+		logger.Redact(filter)
+	}
+}
+
+// TestRedacted_StableOrder verifies logged documents render the same whatever order their maps
+// were built in, and ordered documents render as objects in their order
+func TestRedacted_StableOrder(t *testing.T) {
+	filter := bson.D{{Key: "z", Value: 1}, {Key: "a", Value: bson.M{"$lt": 5, "$gt": 1}}}
+	assert.Contains(t, renderLog(t, filter), `"filter":{"z":1,"a":{"$gt":1,"$lt":5}}`)
+}
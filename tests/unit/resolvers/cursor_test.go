@@ -1,11 +1,22 @@
 package resolvers_test
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
 )
 
 // T050: Unit test for cursor encoding/decoding
@@ -102,3 +113,89 @@ func TestCursor_Roundtrip(t *testing.T) {
 	// JSON unmarshals numbers as float64
 	assert.Equal(t, float64(25), decoded.SortFields[1])
 }
+
+// Test date sort values decode back to dates so they compare against stored BSON dates
+func TestDecodeCursor_DateSortField(t *testing.T) {
+	// {"s":[{"$date":"2024-05-01T10:30:00.123Z"},"Smith"],"i":"abc"}
+	cursorString := "eyJzIjpbeyIkZGF0ZSI6IjIwMjQtMDUtMDFUMTA6MzA6MDAuMTIzWiJ9LCJTbWl0aCJdLCJpIjoiYWJjIn0="
+
+	decoded, err := resolvers.DecodeCursor(cursorString)
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 30, 0, 123000000, time.UTC), decoded.SortFields[0])
+	assert.Equal(t, "Smith", decoded.SortFields[1])
+
+	// {"s":[{"$date":"not a date"}],"i":"abc"}
+	_, err = resolvers.DecodeCursor("eyJzIjpbeyIkZGF0ZSI6Im5vdCBhIGRhdGUifV0sImkiOiJhYmMifQ==")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid cursor format")
+}
+
+// assertCursorError asserts a search failed with the given error code
+func assertCursorError(t *testing.T, err error, code string) {
+	t.Helper()
+	var queryErr *resolvers.QueryError
+	require.True(t, errors.As(err, &queryErr), "got %v", err)
+	assert.Equal(t, code, queryErr.Code)
+}
+
+// TestCustomerSearch_CursorExpiry verifies a cursor is accepted up to its maximum age and
+// rejected with CURSOR_EXPIRED after it, and that its issue time cannot be forged
+func TestCustomerSearch_CursorExpiry(t *testing.T) {
+	ctx := context.Background()
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	resolvers.SetClock(clock)
+	resolvers.SetCursorMaxAge(time.Hour)
+	t.Cleanup(func() {
+		resolvers.SetClock(db.RealClock{})
+		resolvers.SetCursorMaxAge(resolvers.DefaultCursorMaxAge)
+	})
+
+	clusterTime := primitive.Timestamp{T: 1760000000, I: 7}
+	mockDB := new(testutil.MockDBClient)
+	mockColl := new(testutil.MockCollection)
+	mockDB.On("Collection", "customers").Return(mockColl)
+	mockColl.On("AggregateSnapshot", ctx, mock.Anything, (*primitive.Timestamp)(nil)).
+		Return(snapshotFacet(t, "id-1", "id-2", "id-3"), clusterTime, nil).Once()
+	mockColl.On("AggregateSnapshot", ctx, mock.Anything, &clusterTime).
+		Return(snapshotFacet(t, "id-3"), clusterTime, nil).Once()
+
+	queryResolver := (&resolvers.Resolver{DBClient: mockDB}).Query()
+	snapshot := true
+	first := int64(2)
+
+	page, err := queryResolver.CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, &snapshot, nil, nil)
+	require.NoError(t, err)
+	cursor := page.Paging.EndCursor
+	require.NotNil(t, cursor)
+
+	// Just valid: exactly the maximum age
+	clock.Advance(time.Hour)
+	_, err = queryResolver.CustomerSearch(ctx, nil, nil, &first, cursor, nil, nil, &snapshot, nil, nil)
+	require.NoError(t, err)
+	mockColl.AssertExpectations(t)
+
+	// Just expired
+	clock.Advance(time.Second)
+	_, err = queryResolver.CustomerSearch(ctx, nil, nil, &first, cursor, nil, nil, &snapshot, nil, nil)
+	assertCursorError(t, err, resolvers.ErrCodeCursorExpired)
+
+	// A fresher issue time breaks the signature
+	payload, signature, found := strings.Cut(*cursor, ".")
+	require.True(t, found)
+	decoded, err := base64.StdEncoding.DecodeString(payload)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(decoded, &fields))
+	fields["a"] = clock.Now().Unix()
+	forged, err := json.Marshal(fields)
+	require.NoError(t, err)
+	forgedCursor := base64.StdEncoding.EncodeToString(forged) + "." + signature
+	_, err = queryResolver.CustomerSearch(ctx, nil, nil, &first, &forgedCursor, nil, nil, &snapshot, nil, nil)
+	assertCursorError(t, err, resolvers.ErrCodeInvalidInput)
+	assert.Contains(t, err.Error(), "signature mismatch")
+
+	// Unsigned cursors predate expiry and are expired
+	_, err = queryResolver.CustomerSearch(ctx, nil, nil, &first, &payload, nil, nil, &snapshot, nil, nil)
+	assertCursorError(t, err, resolvers.ErrCodeCursorExpired)
+}
@@ -0,0 +1,148 @@
+package resolvers_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// countingDB answers CountDocuments with a settable count per collection and records the calls
+type countingDB struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	calls  int
+}
+
+func (f *countingDB) set(collection string, count int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counts[collection] = count
+}
+
+func (f *countingDB) reads() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *countingDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) { return nil, nil }
+func (f *countingDB) Collection(name string) db.Collection                       { return &countingCollection{db: f, name: name} }
+func (f *countingDB) ReadCollection(name string) db.Collection                   { return f.Collection(name) }
+func (f *countingDB) IsConnected() bool                                          { return true }
+
+type countingCollection struct {
+	db.Collection
+	db   *countingDB
+	name string
+}
+
+func (c *countingCollection) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.calls++
+	return c.db.counts[c.name], nil
+}
+
+// useEntityCountsClock starts with empty entity counts timed by a fake clock for the rest of a test
+func useEntityCountsClock(t *testing.T) *testutil.FakeClock {
+	t.Helper()
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	resolvers.SetClock(clock)
+	resolvers.ResetEntityCounts()
+	t.Cleanup(func() {
+		resolvers.SetClock(db.RealClock{})
+		resolvers.ResetEntityCounts()
+	})
+	return clock
+}
+
+// entityCounts queries the counts with the given roles and returns them by entity
+func entityCounts(t *testing.T, database *countingDB, refresh *bool, roles ...string) map[string]*generated.EntityCount {
+	t.Helper()
+	ctx := resolvers.WithUserClaims(context.Background(), &resolvers.UserClaims{UserID: "user", Roles: roles})
+	counts, err := (&resolvers.Resolver{DBClient: database}).Query().EntityCounts(ctx, refresh)
+	require.NoError(t, err)
+
+	byEntity := make(map[string]*generated.EntityCount, len(counts))
+	for _, count := range counts {
+		byEntity[count.Entity] = count
+	}
+	return byEntity
+}
+
+// TestEntityCounts_ServedFromCacheBetweenRefreshes verifies queries between two background
+// refreshes read no counts from the database, and that the next refresh updates the counts
+func TestEntityCounts_ServedFromCacheBetweenRefreshes(t *testing.T) {
+	clock := useEntityCountsClock(t)
+	database := &countingDB{counts: map[string]int64{"customers": 3, "employees": 2, "teams": 1}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		resolvers.ScheduleEntityCounts(ctx, database, 30*time.Second, zerolog.Nop())
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	require.True(t, clock.BlockUntilWaiters(1, time.Second), "first refresh did not complete")
+	refreshReads := database.reads()
+
+	for i := 0; i < 5; i++ {
+		counts := entityCounts(t, database, nil, "USER")
+		require.Contains(t, counts, "customer")
+		assert.Equal(t, int64(3), counts["customer"].Count)
+		assert.Equal(t, int64(2), counts["employee"].Count)
+		assert.Equal(t, "2026-01-01T12:00:00Z", counts["customer"].RefreshedAt)
+	}
+	assert.Equal(t, refreshReads, database.reads(), "queries must not count between refreshes")
+
+	database.set("customers", 4)
+	clock.Advance(30 * time.Second)
+	require.True(t, clock.BlockUntilWaiters(1, time.Second), "second refresh did not complete")
+
+	counts := entityCounts(t, database, nil, "USER")
+	assert.Equal(t, int64(4), counts["customer"].Count)
+	assert.Equal(t, "2026-01-01T12:00:30Z", counts["customer"].RefreshedAt)
+	assert.Equal(t, 2*refreshReads, database.reads())
+}
+
+// TestEntityCounts_ColdAndManualRefresh verifies a query before any refresh counts once, and that
+// only admins can force a refresh
+func TestEntityCounts_ColdAndManualRefresh(t *testing.T) {
+	clock := useEntityCountsClock(t)
+	database := &countingDB{counts: map[string]int64{"customers": 3}}
+
+	counts := entityCounts(t, database, nil, "USER")
+	assert.Equal(t, int64(3), counts["customer"].Count)
+	coldReads := database.reads()
+	require.Positive(t, coldReads)
+
+	database.set("customers", 5)
+	clock.Advance(time.Minute)
+	counts = entityCounts(t, database, nil, "USER")
+	assert.Equal(t, int64(3), counts["customer"].Count, "without a refresh the cached count is served")
+
+	refresh := true
+	ctx := resolvers.WithUserClaims(context.Background(), &resolvers.UserClaims{UserID: "user", Roles: []string{"USER"}})
+	_, err := (&resolvers.Resolver{DBClient: database}).Query().EntityCounts(ctx, &refresh)
+	var queryErr *resolvers.QueryError
+	require.ErrorAs(t, err, &queryErr)
+	assert.Equal(t, resolvers.ErrCodeForbidden, queryErr.Code)
+
+	counts = entityCounts(t, database, &refresh, "ADMIN")
+	assert.Equal(t, int64(5), counts["customer"].Count)
+	assert.Equal(t, "2026-01-01T12:01:00Z", counts["customer"].RefreshedAt)
+	assert.Equal(t, 2*coldReads, database.reads())
+}
@@ -0,0 +1,87 @@
+package resolvers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// searchPageCursor returns the cursor of a search aggregate finding the identifiers
+func searchPageCursor(identifiers ...string) interface{} {
+	data := make([]bson.M, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		data = append(data, bson.M{"identifier": identifier})
+	}
+	return testutil.CursorOf(bson.M{
+		"metadata": []bson.M{{"totalCount": len(identifiers)}},
+		"data":     data,
+	})
+}
+
+// capturedDataSort returns the $sort keys of the data branch of a captured search pipeline
+func capturedDataSort(t *testing.T, pipeline []bson.M) bson.D {
+	t.Helper()
+	facet := pipeline[len(pipeline)-1]["$facet"].(bson.M)
+	for _, stage := range facet["data"].([]bson.M) {
+		if keys, ok := stage["$sort"].(bson.D); ok {
+			return keys
+		}
+	}
+	t.Fatal("the data branch has no $sort stage")
+	return nil
+}
+
+// mockSearchCollection expects one search aggregate on a collection and records its pipeline
+func mockSearchCollection(collection string, pipeline *[]bson.M, identifiers ...string) (*testutil.MockDBClient, *testutil.MockCollection) {
+	mockDB := new(testutil.MockDBClient)
+	mockColl := new(testutil.MockCollection)
+	mockDB.On("Collection", collection).Return(mockColl)
+	mockColl.On("Aggregate", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { *pipeline = args.Get(1).([]bson.M) }).
+		Return(searchPageCursor(identifiers...), nil).Once()
+	return mockDB, mockColl
+}
+
+// TestCustomerSearch_SortsByOrder tests the order argument becomes the data branch's $sort,
+// with identifier and _id as the tiebreakers (T037)
+func TestCustomerSearch_SortsByOrder(t *testing.T) {
+	ctx := context.Background()
+	var pipeline []bson.M
+	mockDB, mockColl := mockSearchCollection("customers", &pipeline, "550e8400-e29b-41d4-a716-446655440000")
+
+	asc := generated.SortEnumTypeAsc
+	first := int64(10)
+	order := []*generated.CustomerQuerySorterInput{{LastName: &asc}}
+	result, err := resolvers.NewResolver(mockDB).Query().CustomerSearch(ctx, nil, order, &first, nil, nil, nil, nil, nil, nil)
+
+	require.NoError(t, err)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440000", result.Data[0].Identifier)
+	assert.Equal(t, bson.D{{Key: "lastName", Value: 1}, {Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}, capturedDataSort(t, pipeline))
+	mockDB.AssertExpectations(t)
+	mockColl.AssertExpectations(t)
+}
+
+// TestEmployeeSearch_SortsByMultiFieldOrder tests the order entries keep their priority
+func TestEmployeeSearch_SortsByMultiFieldOrder(t *testing.T) {
+	ctx := context.Background()
+	var pipeline []bson.M
+	mockDB, mockColl := mockSearchCollection("employees", &pipeline)
+
+	asc, desc := generated.SortEnumTypeAsc, generated.SortEnumTypeDesc
+	order := []*generated.EmployeeQuerySorterInput{{LastName: &asc}, {FirstName: &desc}}
+	result, err := resolvers.NewResolver(mockDB).Query().EmployeeSearch(ctx, nil, order, nil, nil, nil, nil, nil, nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Data)
+	assert.Equal(t, bson.D{{Key: "lastName", Value: 1}, {Key: "firstName", Value: -1}, {Key: "identifier", Value: 1}, {Key: "_id", Value: 1}}, capturedDataSort(t, pipeline))
+	mockColl.AssertExpectations(t)
+}
@@ -9,34 +9,10 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/yourusername/air-go/internal/db"
 	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/health"
+	"github.com/yourusername/air-go/tests/testutil"
 )
 
-// MockDBClient is a mock implementation of resolvers.DBClient
-type MockDBClient struct {
-	mock.Mock
-}
-
-func (m *MockDBClient) HealthStatus(ctx context.Context) (*db.HealthStatus, error) {
-	args := m.Called(ctx)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*db.HealthStatus), args.Error(1)
-}
-
-func (m *MockDBClient) Collection(name string) db.Collection {
-	args := m.Called(name)
-	if args.Get(0) == nil {
-		return nil
-	}
-	return args.Get(0).(db.Collection)
-}
-
-func (m *MockDBClient) IsConnected() bool {
-	args := m.Called()
-	return args.Bool(0)
-}
-
 // TestAlive tests the alive query (T014)
 func TestAlive(t *testing.T) {
 	t.Run("should return true when system is operational", func(t *testing.T) {
@@ -71,7 +47,7 @@ func TestHealth(t *testing.T) {
 	t.Run("should return ok status when database is connected", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
-		mockDB := new(MockDBClient)
+		mockDB := new(testutil.MockDBClient)
 		mockDB.On("HealthStatus", mock.Anything).Return(&db.HealthStatus{
 			Status:    "connected",
 			Message:   "MongoDB connected",
@@ -101,7 +77,7 @@ func TestHealth(t *testing.T) {
 	t.Run("should return degraded status when database is not connected", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
-		mockDB := new(MockDBClient)
+		mockDB := new(testutil.MockDBClient)
 		mockDB.On("HealthStatus", mock.Anything).Return(&db.HealthStatus{
 			Status:    "disconnected",
 			Message:   "MongoDB connection failed",
@@ -129,7 +105,7 @@ func TestHealth(t *testing.T) {
 	t.Run("should not require authentication", func(t *testing.T) {
 		// Arrange - unauthenticated context
 		ctx := context.Background()
-		mockDB := new(MockDBClient)
+		mockDB := new(testutil.MockDBClient)
 		mockDB.On("HealthStatus", mock.Anything).Return(&db.HealthStatus{
 			Status:    "connected",
 			Message:   "MongoDB connected",
@@ -154,7 +130,7 @@ func TestHealth(t *testing.T) {
 	t.Run("should complete within 100ms threshold", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
-		mockDB := new(MockDBClient)
+		mockDB := new(testutil.MockDBClient)
 		mockDB.On("HealthStatus", mock.Anything).Return(&db.HealthStatus{
 			Status:    "connected",
 			Message:   "MongoDB connected",
@@ -177,3 +153,40 @@ func TestHealth(t *testing.T) {
 		mockDB.AssertExpectations(t)
 	})
 }
+
+// TestServiceInfo tests the serviceInfo query
+func TestServiceInfo(t *testing.T) {
+	t.Run("should return configured schema and build metadata", func(t *testing.T) {
+		resolver := &resolvers.Resolver{
+			ServiceInfo: &health.ServiceInfo{
+				Schema: &health.SchemaInfo{
+					Path:      "./schema.graphqls",
+					TypeCount: 42,
+					Checksum:  "abc123",
+					LoadedAt:  "2026-01-01T00:00:00Z",
+				},
+				Build: health.BuildInfo{GitSHA: "deadbeef", BuildTime: "2026-01-01T00:00:00Z"},
+			},
+		}
+
+		result, err := resolver.Query().ServiceInfo(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "./schema.graphqls", result.Schema.Path)
+		assert.Equal(t, 42, result.Schema.TypeCount)
+		assert.Equal(t, "abc123", result.Schema.Checksum)
+		assert.Equal(t, "2026-01-01T00:00:00Z", result.Schema.LoadedAt)
+		assert.Equal(t, "deadbeef", result.Build.GitSha)
+	})
+
+	t.Run("should fall back to linked build metadata without a schema", func(t *testing.T) {
+		resolver := &resolvers.Resolver{}
+
+		result, err := resolver.Query().ServiceInfo(context.Background())
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Schema)
+		assert.Equal(t, "unknown", result.Build.GitSha)
+		assert.Equal(t, "unknown", result.Build.BuildTime)
+	})
+}
@@ -0,0 +1,136 @@
+package resolvers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// useResolverStatsClock starts empty resolver statistics timed by a fake clock for the rest of a test
+func useResolverStatsClock(t *testing.T) *testutil.FakeClock {
+	t.Helper()
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	resolvers.SetClock(clock)
+	require.NoError(t, resolvers.SetResolverStatsWindow(resolvers.DefaultResolverStatsWindow))
+	t.Cleanup(func() {
+		resolvers.SetClock(db.RealClock{})
+		_ = resolvers.SetResolverStatsWindow(resolvers.DefaultResolverStatsWindow)
+	})
+	return clock
+}
+
+// callThroughStats runs a resolver of the given object field through the stats middleware,
+// taking latency on the fake clock and failing when fail is set
+func callThroughStats(t *testing.T, clock *testutil.FakeClock, object, field string, latency time.Duration, fail bool) {
+	t.Helper()
+	ctx := graphql.WithFieldContext(context.Background(), &graphql.FieldContext{
+		Object: object,
+		Field:  graphql.CollectedField{Field: &ast.Field{Name: field}},
+	})
+	_, _ = resolvers.RecordResolverStats(ctx, func(context.Context) (interface{}, error) {
+		clock.Advance(latency)
+		if fail {
+			return nil, errors.New("failed")
+		}
+		return "ok", nil
+	})
+}
+
+// resolverStats queries the statistics as an admin
+func resolverStats(t *testing.T, minutes *int) map[string]*generated.ResolverStats {
+	t.Helper()
+	ctx := resolvers.WithUserClaims(context.Background(), &resolvers.UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+	stats, err := (&resolvers.Resolver{}).Query().ResolverStats(ctx, minutes)
+	require.NoError(t, err)
+
+	byResolver := make(map[string]*generated.ResolverStats, len(stats))
+	for _, entry := range stats {
+		byResolver[entry.Resolver] = entry
+	}
+	return byResolver
+}
+
+// TestResolverStats_Percentiles verifies the percentiles of synthetic latencies within the
+// histogram's accuracy, and the call and error counts per root resolver
+func TestResolverStats_Percentiles(t *testing.T) {
+	clock := useResolverStatsClock(t)
+
+	// 0.1ms to 100ms in 0.1ms steps, within one minute; every 100th call fails
+	for i := 1; i <= 1000; i++ {
+		callThroughStats(t, clock, "Query", "customerSearch", time.Duration(i)*100*time.Microsecond, i%100 == 0)
+	}
+	callThroughStats(t, clock, "Mutation", "customerUpdate", 3*time.Millisecond, false)
+	callThroughStats(t, clock, "Customer", "firstName", time.Second, false) // Not a root resolver
+
+	stats := resolverStats(t, nil)
+	require.Len(t, stats, 2)
+
+	search := stats["customerSearch"]
+	require.NotNil(t, search)
+	assert.Equal(t, 1000, search.Calls)
+	assert.Equal(t, 10, search.Errors)
+	assert.InEpsilon(t, 50, search.P50Ms, 0.03)
+	assert.InEpsilon(t, 95, search.P95Ms, 0.03)
+	assert.InEpsilon(t, 99, search.P99Ms, 0.03)
+
+	update := stats["customerUpdate"]
+	require.NotNil(t, update)
+	assert.Equal(t, 1, update.Calls)
+	assert.InEpsilon(t, 3, update.P99Ms, 0.03)
+}
+
+// TestResolverStats_Window verifies calls leave the statistics with their minute and minutes
+// narrows the window
+func TestResolverStats_Window(t *testing.T) {
+	clock := useResolverStatsClock(t)
+
+	callThroughStats(t, clock, "Query", "teamGet", 10*time.Millisecond, false)
+	clock.Advance(2 * time.Minute)
+	callThroughStats(t, clock, "Query", "teamGet", 20*time.Millisecond, false)
+
+	assert.Equal(t, 2, resolverStats(t, nil)["teamGet"].Calls)
+	one := 1
+	assert.Equal(t, 1, resolverStats(t, &one)["teamGet"].Calls)
+
+	// Five minutes on, only the second call is left; after that none
+	clock.Advance(3 * time.Minute)
+	assert.Equal(t, 1, resolverStats(t, nil)["teamGet"].Calls)
+	clock.Advance(2 * time.Minute)
+	assert.Empty(t, resolverStats(t, nil))
+
+	// Setting the window discards the recorded calls
+	callThroughStats(t, clock, "Query", "teamGet", 10*time.Millisecond, false)
+	require.NoError(t, resolvers.SetResolverStatsWindow(10*time.Minute))
+	assert.Empty(t, resolverStats(t, nil))
+	assert.Error(t, resolvers.SetResolverStatsWindow(30*time.Second))
+	assert.Error(t, resolvers.SetResolverStatsWindow(2*time.Hour))
+}
+
+// TestResolverStats_Rejected verifies the query is admin only and minutes stays within the window
+func TestResolverStats_Rejected(t *testing.T) {
+	useResolverStatsClock(t)
+	queryResolver := (&resolvers.Resolver{}).Query()
+
+	userCtx := resolvers.WithUserClaims(context.Background(), &resolvers.UserClaims{UserID: "user", Roles: []string{"USER"}})
+	_, err := queryResolver.ResolverStats(userCtx, nil)
+	assert.Error(t, err)
+
+	adminCtx := resolvers.WithUserClaims(context.Background(), &resolvers.UserClaims{UserID: "admin", Roles: []string{"ADMIN"}})
+	for _, minutes := range []int{0, 6} {
+		_, err = queryResolver.ResolverStats(adminCtx, &minutes)
+		var queryErr *resolvers.QueryError
+		require.True(t, errors.As(err, &queryErr), "minutes %d", minutes)
+		assert.Equal(t, resolvers.ErrCodeInvalidInput, queryErr.Code)
+	}
+}
@@ -0,0 +1,98 @@
+package resolvers_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+)
+
+// TestServerLimits_ReportsEnforcedLimits verifies the serverLimits query reports the limits
+// the resolvers enforce, by probing each reported limit and one past it
+func TestServerLimits_ReportsEnforcedLimits(t *testing.T) {
+	ctx := context.Background()
+	resolver := resolvers.NewResolver(nil)
+
+	limits, err := resolver.Query().ServerLimits(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, limits.Entities)
+
+	var customer *generated.EntityLimits
+	for _, entity := range limits.Entities {
+		if entity.Entity == "customer" {
+			customer = entity
+		}
+	}
+	require.NotNil(t, customer)
+	assert.Equal(t, resolvers.CurrentLimits().DefaultPageSize, customer.DefaultPageSize)
+
+	// Page size: the reported maximum passes validation, one more is rejected
+	atLimit := int64(customer.MaxPageSize)
+	_, err = resolver.Query().CustomerSearch(ctx, nil, nil, &atLimit, nil, nil, nil, nil, nil, nil)
+	require.Error(t, err) // No database; validation passed
+	assert.NotContains(t, err.Error(), "exceeds maximum")
+
+	overLimit := atLimit + 1
+	_, err = resolver.Query().CustomerSearch(ctx, nil, nil, &overLimit, nil, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("'first' exceeds maximum batch size: requested %d, maximum %d", overLimit, customer.MaxPageSize))
+
+	// Batch size
+	identifiers := make([]string, customer.MaxBatchSize+1)
+	for i := range identifiers {
+		identifiers[i] = fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+	}
+	_, err = resolver.Query().CustomerByKeysGet(ctx, identifiers, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("maximum %d", customer.MaxBatchSize))
+
+	// Order entries
+	asc := generated.SortEnumTypeAsc
+	order := make([]*generated.CustomerQuerySorterInput, limits.MaxSortEntries+1)
+	for i := range order {
+		order[i] = &generated.CustomerQuerySorterInput{FirstName: &asc}
+	}
+	_, err = resolver.Query().CustomerSearch(ctx, nil, order, nil, nil, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("order accepts at most %d entries", limits.MaxSortEntries))
+
+	// Filter depth
+	where := &generated.CustomerQueryFilterInput{}
+	for i := 0; i < limits.MaxFilterDepth+1; i++ {
+		where = &generated.CustomerQueryFilterInput{And: []*generated.CustomerQueryFilterInput{where}}
+	}
+	_, err = resolver.Query().CustomerSearch(ctx, where, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("at most %d levels deep", limits.MaxFilterDepth))
+}
+
+// TestServerLimits_FollowsConfiguration verifies configured limits are reported
+func TestServerLimits_FollowsConfiguration(t *testing.T) {
+	previous := resolvers.CurrentLimits()
+	t.Cleanup(func() {
+		resolvers.SetMaxSortEntries(previous.MaxSortEntries)
+		resolvers.SetFilterLimits(previous.Filter)
+		resolvers.SetExportMaxRows(previous.ExportMaxRows)
+		resolvers.SetMaxQueryComplexity(previous.MaxComplexity)
+	})
+
+	resolvers.SetMaxSortEntries(3)
+	resolvers.SetFilterLimits(resolvers.FilterLimits{MaxIn: 7, MaxDepth: 3})
+	resolvers.SetExportMaxRows(500)
+	resolvers.SetMaxQueryComplexity(900)
+
+	limits, err := resolvers.NewResolver(nil).Query().ServerLimits(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, limits.MaxSortEntries)
+	assert.Equal(t, 7, limits.Filter.MaxIn)
+	assert.Equal(t, previous.Filter.MaxNin, limits.Filter.MaxNin)
+	assert.Equal(t, 3, limits.MaxFilterDepth)
+	assert.Equal(t, 500, limits.ExportMaxRows)
+	assert.Equal(t, 900, limits.MaxQueryComplexity)
+	assert.Equal(t, previous.MaxRelationSize, limits.MaxRelationSize)
+}
@@ -0,0 +1,74 @@
+package resolvers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// TestCustomerGet_SlowQueryThreshold verifies a query is flagged as slow only once it exceeds the threshold,
+// by advancing a fake clock while the database call runs
+func TestCustomerGet_SlowQueryThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		wantSlow bool
+	}{
+		{name: "at the threshold", elapsed: resolvers.SlowQueryThresholdSimple, wantSlow: false},
+		{name: "just over the threshold", elapsed: resolvers.SlowQueryThresholdSimple + time.Millisecond, wantSlow: true},
+	}
+
+	identifier := "550e8400-e29b-41d4-a716-446655440000"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			previousLogger := log.Logger
+			log.Logger = zerolog.New(&buf)
+			t.Cleanup(func() { log.Logger = previousLogger })
+
+			clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+			resolvers.SetClock(clock)
+			t.Cleanup(func() { resolvers.SetClock(db.RealClock{}) })
+
+			ctx := context.Background()
+			mockDB := new(testutil.MockDBClient)
+			mockColl := new(testutil.MockCollection)
+			mockColl.On("FindOne", ctx, mock.Anything).
+				Run(func(mock.Arguments) { clock.Advance(tt.elapsed) }).
+				Return(mongo.NewSingleResultFromDocument(bson.M{"identifier": identifier}, nil, nil))
+			mockDB.On("Collection", "customers").Return(mockColl)
+
+			resolver := &resolvers.Resolver{DBClient: mockDB}
+			customer, err := resolver.Query().CustomerGet(ctx, identifier)
+			require.NoError(t, err)
+			require.NotNil(t, customer)
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, "customerGet", entry["query"])
+			assert.Equal(t, float64(tt.elapsed.Milliseconds()), entry["duration_ms"])
+			if tt.wantSlow {
+				assert.Equal(t, "warn", entry["level"])
+				assert.Equal(t, true, entry["slow_query"])
+			} else {
+				assert.Equal(t, "info", entry["level"])
+				assert.NotContains(t, entry, "slow_query")
+			}
+		})
+	}
+}
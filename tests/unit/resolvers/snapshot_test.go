@@ -0,0 +1,86 @@
+package resolvers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// snapshotFacet builds the $facet result document of a search page
+func snapshotFacet(t *testing.T, identifiers ...string) []bson.Raw {
+	data := make([]bson.M, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		data = append(data, bson.M{"identifier": identifier})
+	}
+	raw, err := bson.Marshal(bson.M{
+		"metadata": []bson.M{{"totalCount": 3}},
+		"data":     data,
+	})
+	require.NoError(t, err)
+	return []bson.Raw{raw}
+}
+
+// TestCustomerSearch_SnapshotCarriesClusterTime verifies the first snapshot page records the
+// cluster time in its cursors and later pages read at that time
+func TestCustomerSearch_SnapshotCarriesClusterTime(t *testing.T) {
+	ctx := context.Background()
+	clusterTime := primitive.Timestamp{T: 1760000000, I: 7}
+
+	mockDB := new(testutil.MockDBClient)
+	mockColl := new(testutil.MockCollection)
+	mockDB.On("Collection", "customers").Return(mockColl)
+	mockColl.On("AggregateSnapshot", ctx, mock.Anything, (*primitive.Timestamp)(nil)).
+		Return(snapshotFacet(t, "id-1", "id-2", "id-3"), clusterTime, nil).Once()
+	mockColl.On("AggregateSnapshot", ctx, mock.Anything, &clusterTime).
+		Return(snapshotFacet(t, "id-3"), clusterTime, nil).Once()
+
+	resolver := &resolvers.Resolver{DBClient: mockDB}
+	snapshot := true
+	first := int64(2)
+
+	page, err := resolver.Query().CustomerSearch(ctx, nil, nil, &first, nil, nil, nil, &snapshot, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 2)
+	require.NotNil(t, page.Paging.EndCursor)
+
+	cursor, err := resolvers.DecodeCursor(*page.Paging.EndCursor)
+	require.NoError(t, err)
+	require.NotNil(t, cursor.Snapshot)
+
+	page, err = resolver.Query().CustomerSearch(ctx, nil, nil, &first, page.Paging.EndCursor, nil, nil, &snapshot, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 1)
+	mockColl.AssertExpectations(t)
+
+	// The snapshot cursor cannot continue a non-snapshot pagination
+	_, err = resolver.Query().CustomerSearch(ctx, nil, nil, &first, page.Paging.EndCursor, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different snapshot mode")
+}
+
+// TestCustomerSearch_SnapshotStandalone verifies standalone servers produce a clear error
+func TestCustomerSearch_SnapshotStandalone(t *testing.T) {
+	ctx := context.Background()
+
+	mockDB := new(testutil.MockDBClient)
+	mockColl := new(testutil.MockCollection)
+	mockDB.On("Collection", "customers").Return(mockColl)
+	mockColl.On("AggregateSnapshot", ctx, mock.Anything, (*primitive.Timestamp)(nil)).
+		Return(nil, primitive.Timestamp{}, db.ErrSnapshotUnsupported)
+
+	resolver := &resolvers.Resolver{DBClient: mockDB}
+	snapshot := true
+
+	_, err := resolver.Query().CustomerSearch(ctx, nil, nil, nil, nil, nil, nil, &snapshot, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "snapshot pagination requires a replica set or sharded cluster")
+}
@@ -0,0 +1,113 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/internal/server/middleware"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// degradedCacheHandler serves a schema whose query returns how often it has executed
+func degradedCacheHandler(cache *server.DegradedCache) (http.Handler, *int) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query { count(id: Int): Int! }
+		type Mutation { count: Int! }
+	`})
+
+	executions := 0
+	srv := handler.New(&graphql.ExecutableSchemaMock{
+		SchemaFunc: func() *ast.Schema { return schema },
+		ComplexityFunc: func(ctx context.Context, typeName, fieldName string, childComplexity int, args map[string]interface{}) (int, bool) {
+			return childComplexity, true
+		},
+		ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+			executions++
+			return graphql.OneShot(&graphql.Response{Data: []byte(fmt.Sprintf(`{"count":%d}`, executions))})
+		},
+	})
+	srv.AddTransport(transport.POST{})
+	srv.AroundOperations(cache.AroundOperations)
+	return srv, &executions
+}
+
+// postQuery sends a GraphQL request as the given user and returns the decoded response
+func postQuery(t *testing.T, h http.Handler, user, query string) map[string]interface{} {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"query": query})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, jwt.MapClaims{"sub": user}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp
+}
+
+// TestDegradedCache_ServesStoredResultsWhileDegraded verifies queries are stored while READY,
+// answered from the cache while DEGRADED and rejected when nothing is cached
+func TestDegradedCache_ServesStoredResultsWhileDegraded(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeHealthClient{}
+	client.set("connected", nil)
+	readiness := server.NewReadiness(client, testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)), zerolog.Nop())
+	readiness.MarkSchemaLoaded()
+	require.Equal(t, server.ReadinessReady, readiness.Evaluate(ctx).State)
+
+	h, executions := degradedCacheHandler(server.NewDegradedCache(readiness, 10))
+
+	resp := postQuery(t, h, "alice", "{ count(id: 1) }")
+	assert.Equal(t, map[string]interface{}{"count": float64(1)}, resp["data"])
+	resp = postQuery(t, h, "alice", "{ count(id: 1) }")
+	assert.Equal(t, map[string]interface{}{"count": float64(2)}, resp["data"], "READY always executes")
+
+	client.set("disconnected", nil)
+	require.Equal(t, server.ReadinessDegraded, readiness.Evaluate(ctx).State)
+
+	// Stored result, without executing
+	resp = postQuery(t, h, "alice", "{ count(id: 1) }")
+	assert.Equal(t, map[string]interface{}{"count": float64(2)}, resp["data"])
+	assert.Equal(t, map[string]interface{}{
+		"degradedCache": map[string]interface{}{"storedAt": "2026-01-01T12:00:00Z"},
+	}, resp["extensions"])
+	assert.Equal(t, 2, *executions)
+
+	// Different variables or principal have no stored result
+	for _, request := range []struct{ user, query string }{
+		{"alice", "{ count(id: 2) }"},
+		{"bob", "{ count(id: 1) }"},
+	} {
+		resp = postQuery(t, h, request.user, request.query)
+		assert.Nil(t, resp["data"])
+		errs, _ := resp["errors"].([]interface{})
+		require.Len(t, errs, 1)
+		assert.Equal(t, "SERVICE_UNAVAILABLE", errs[0].(map[string]interface{})["extensions"].(map[string]interface{})["code"])
+	}
+	assert.Equal(t, 2, *executions)
+
+	// Mutations are never answered from the cache
+	resp = postQuery(t, h, "alice", "mutation { count }")
+	assert.Equal(t, map[string]interface{}{"count": float64(3)}, resp["data"])
+}
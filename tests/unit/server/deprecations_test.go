@@ -0,0 +1,90 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// deprecationHandler serves the generated schema backed by an empty database, counting and,
+// with enforceSunset, refusing its deprecated fields
+func deprecationHandler(t *testing.T, enforceSunset bool) http.Handler {
+	t.Helper()
+	es := generated.NewExecutableSchema(generated.Config{Resolvers: resolvers.NewResolver(newMemoryDB())})
+	require.NoError(t, resolvers.SetDeprecations(es.Schema(), enforceSunset))
+	t.Cleanup(func() { _ = resolvers.SetDeprecations(es.Schema(), false) })
+
+	srv := handler.New(es)
+	srv.SetErrorPresenter(resolvers.ErrorPresenter)
+	srv.AddTransport(transport.POST{})
+	srv.AroundOperations(server.DeprecatedFieldOperations(server.DefaultDeprecationClientHeader))
+	return srv
+}
+
+// legacyCreateMutation uses the deprecated create mutation, whose sunset is 2027-06-30
+const legacyCreateMutation = `{"query":"mutation { create(mutationInput: {identifier: \"6f1c2d3e-4a5b-4c6d-8e7f-001122334455\"}) { identifier } }"}`
+
+// postLegacyCreate sends the deprecated create mutation as client and returns the raw response body
+func postLegacyCreate(h http.Handler, client string) string {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(legacyCreateMutation))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(server.DefaultDeprecationClientHeader, client)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+// legacyCreateUses returns how often client used the deprecated create mutation
+func legacyCreateUses(client string) int64 {
+	for _, usage := range resolvers.CurrentDeprecatedFieldUsage() {
+		if usage.Coordinate == "Mutation.create" && usage.Client == client {
+			return usage.Count
+		}
+	}
+	return 0
+}
+
+// useSunsetClock sets the resolvers' clock to a day after the create mutation's sunset
+func useSunsetClock(t *testing.T) {
+	resolvers.SetClock(testutil.NewFakeClock(time.Date(2027, 7, 1, 0, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { resolvers.SetClock(db.RealClock{}) })
+}
+
+// TestDeprecatedFields_CountedByClient verifies operations using a deprecated field are counted
+// by the client header, and still run past the sunset date while it is not enforced
+func TestDeprecatedFields_CountedByClient(t *testing.T) {
+	useSunsetClock(t)
+	h := deprecationHandler(t, false)
+	before := legacyCreateUses("portfolio-importer")
+
+	body := postLegacyCreate(h, "portfolio-importer")
+	assert.NotContains(t, body, resolvers.ErrCodeFieldSunset)
+	postLegacyCreate(h, "portfolio-importer")
+	assert.Equal(t, before+2, legacyCreateUses("portfolio-importer"))
+}
+
+// TestDeprecatedFields_RefusedAfterSunset verifies the deprecated create mutation answers
+// FIELD_SUNSET past its sunset date once enforcement is on, and is still counted
+func TestDeprecatedFields_RefusedAfterSunset(t *testing.T) {
+	useSunsetClock(t)
+	h := deprecationHandler(t, true)
+	before := legacyCreateUses("legacy-ui")
+
+	body := postLegacyCreate(h, "legacy-ui")
+	assert.Equal(t, resolvers.ErrCodeFieldSunset, errorCode(t, body))
+	assert.Contains(t, body, "Mutation.create was removed on 2027-06-30: Use referencePortfolioCreate instead")
+	assert.Equal(t, before+1, legacyCreateUses("legacy-ui"))
+}
@@ -0,0 +1,123 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// entityTagHandler serves the generated schema backed by database over GET and POST, tagging GET responses
+func entityTagHandler(database *memoryDB) http.Handler {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolvers.NewResolver(database)}))
+	srv.SetErrorPresenter(resolvers.ErrorPresenter)
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AroundFields(resolvers.TrackEntityVersions)
+	return server.EntityTagHandler(srv)
+}
+
+// getQuery sends a GraphQL query as a GET request with an optional If-None-Match header
+func getQuery(h http.Handler, query, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/graphql?"+url.Values{"query": {query}}.Encode(), nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+const customerGetQuery = `{ customerGet(identifier: "` + testutil.TestCustomerID1 + `") { firstName } }`
+
+// TestEntityTag_NotModifiedUntilMutation verifies a second GET with the returned ETag receives
+// 304 without a body, and that an update in between changes the tag and the response
+func TestEntityTag_NotModifiedUntilMutation(t *testing.T) {
+	// A stopped clock gives the update the lastUpdateDate of the create; only the version tells them apart
+	resolvers.SetClock(testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { resolvers.SetClock(db.RealClock{}) })
+	database := newMemoryDB()
+	h := entityTagHandler(database)
+	postMutation(t, h, "alice", "key-1", map[string]interface{}{"identifier": testutil.TestCustomerID1, "firstName": "Jane"})
+
+	first := getQuery(h, customerGetQuery, "")
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.JSONEq(t, `{"data":{"customerGet":{"firstName":"Jane"}}}`, first.Body.String())
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+
+	second := getQuery(h, customerGetQuery, etag)
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Empty(t, second.Body.String())
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+
+	postUpdate(t, h, map[string]interface{}{"identifier": testutil.TestCustomerID1, "firstName": "Janet"})
+	third := getQuery(h, customerGetQuery, etag)
+	require.Equal(t, http.StatusOK, third.Code)
+	assert.JSONEq(t, `{"data":{"customerGet":{"firstName":"Janet"}}}`, third.Body.String())
+	assert.NotEqual(t, etag, third.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, getQuery(h, customerGetQuery, third.Header().Get("ETag")).Code)
+}
+
+// TestEntityTag_NotFoundUntilCreated verifies a missing customer is tagged too, and its creation changes the tag
+func TestEntityTag_NotFoundUntilCreated(t *testing.T) {
+	database := newMemoryDB()
+	h := entityTagHandler(database)
+
+	missing := getQuery(h, customerGetQuery, "")
+	assert.JSONEq(t, `{"data":{"customerGet":null}}`, missing.Body.String())
+	etag := missing.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, http.StatusNotModified, getQuery(h, customerGetQuery, "W/\"other\", "+etag).Code)
+
+	postMutation(t, h, "alice", "key-1", map[string]interface{}{"identifier": testutil.TestCustomerID1, "firstName": "Jane"})
+	assert.Equal(t, http.StatusOK, getQuery(h, customerGetQuery, etag).Code)
+}
+
+// TestEntityTag_UntaggedResponses verifies responses the versions do not cover carry no ETag
+func TestEntityTag_UntaggedResponses(t *testing.T) {
+	database := newMemoryDB()
+	h := entityTagHandler(database)
+
+	for name, query := range map[string]string{
+		"other root field":   `{ customerGet(identifier: "` + testutil.TestCustomerID1 + `") { firstName } serverLimits { maxSortEntries } }`,
+		"unversioned entity": `{ teamGet(identifier: "` + testutil.TestCustomerID1 + `") { name } }`,
+		"error":              `{ customerGet(identifier: "not-a-uuid") { firstName } }`,
+	} {
+		rec := getQuery(h, query, "*")
+		assert.Equal(t, http.StatusOK, rec.Code, name)
+		assert.Empty(t, rec.Header().Get("ETag"), name)
+	}
+}
+
+// postUpdate sends customerUpdate and fails the test on errors
+func postUpdate(t *testing.T, h http.Handler, input map[string]interface{}) {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     `mutation Update($input: CustomerUpdateMutationInput!) { customerUpdate(customerInput: $input) { identifier } }`,
+		"variables": map[string]interface{}{"input": input},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.NotContains(t, rec.Body.String(), "errors")
+	assert.Empty(t, rec.Header().Get("ETag"), "POST responses are not tagged")
+}
@@ -0,0 +1,305 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/internal/server/middleware"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// memoryDB keeps collections in memory, matching filters by equality of their top-level fields
+type memoryDB struct {
+	mu          sync.Mutex
+	collections map[string]*memoryCollection
+}
+
+func newMemoryDB() *memoryDB {
+	return &memoryDB{collections: make(map[string]*memoryCollection)}
+}
+
+func (m *memoryDB) Collection(name string) db.Collection {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.collections[name] == nil {
+		m.collections[name] = &memoryCollection{}
+	}
+	return m.collections[name]
+}
+
+func (m *memoryDB) ReadCollection(name string) db.Collection {
+	return m.Collection(name)
+}
+
+func (m *memoryDB) HealthStatus(ctx context.Context) (*db.HealthStatus, error) {
+	return &db.HealthStatus{Status: "connected"}, nil
+}
+
+func (m *memoryDB) IsConnected() bool {
+	return true
+}
+
+// memoryCollection implements the operations the idempotency store and the customer resolvers use
+type memoryCollection struct {
+	db.Collection
+	mu        sync.Mutex
+	documents []bson.M
+}
+
+func (c *memoryCollection) InsertOne(ctx context.Context, document interface{}) (*mongo.InsertOneResult, error) {
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id, ok := doc["_id"]; ok && c.find(bson.M{"_id": id}) >= 0 {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+	}
+	c.documents = append(c.documents, doc)
+	return &mongo.InsertOneResult{InsertedID: doc["_id"]}, nil
+}
+
+func (c *memoryCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	index := c.find(filter.(bson.M))
+	if index < 0 {
+		return mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(c.documents[index], nil, db.Registry)
+}
+
+func (c *memoryCollection) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.find(filter.(bson.M)) < 0 {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func (c *memoryCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	index := c.find(filter.(bson.M))
+	if index < 0 {
+		return &mongo.UpdateResult{}, nil
+	}
+	for field, value := range update.(bson.M)["$set"].(bson.M) {
+		c.documents[index][field] = value
+	}
+	if inc, ok := update.(bson.M)["$inc"].(bson.M); ok {
+		for field, value := range inc {
+			current, _ := c.documents[index][field].(int64)
+			c.documents[index][field] = current + int64(value.(int))
+		}
+	}
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (c *memoryCollection) DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	index := c.find(bson.M{"_id": filter.(bson.M)["_id"]})
+	if index < 0 {
+		return &mongo.DeleteResult{}, nil
+	}
+	c.documents = append(c.documents[:index], c.documents[index+1:]...)
+	return &mongo.DeleteResult{DeletedCount: 1}, nil
+}
+
+// find returns the index of the first document matching filter, or -1. Fields may be dotted
+// paths, and conditions may be $ne
+func (c *memoryCollection) find(filter bson.M) int {
+	for i, doc := range c.documents {
+		matches := true
+		for field, value := range filter {
+			actual := lookupPath(doc, field)
+			if condition, ok := value.(bson.M); ok {
+				matches = matches && actual != condition["$ne"]
+			} else if actual != value {
+				matches = false
+			}
+		}
+		if matches {
+			return i
+		}
+	}
+	return -1
+}
+
+// lookupPath returns the value of a dotted field path of a document, or nil
+func lookupPath(doc bson.M, path string) interface{} {
+	var value interface{} = doc
+	for _, field := range strings.Split(path, ".") {
+		nested, ok := value.(bson.M)
+		if !ok {
+			return nil
+		}
+		value = nested[field]
+	}
+	return value
+}
+
+// count returns the number of documents
+func (c *memoryCollection) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.documents)
+}
+
+// idempotencyHandler serves the generated schema backed by database, applying idempotency keys
+func idempotencyHandler(database *memoryDB, clock db.Clock) http.Handler {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolvers.NewResolver(database)}))
+	srv.SetErrorPresenter(resolvers.ErrorPresenter)
+	srv.AddTransport(transport.POST{})
+	srv.AroundOperations(server.NewIdempotencyStore(database.Collection(server.IdempotencyCollection), time.Hour, clock).AroundOperations)
+	return srv
+}
+
+const customerCreateMutation = `mutation Create($input: CustomerMutationInput!) {
+	customerCreate(customerInput: $input) { identifier firstName createDate }
+}`
+
+// postMutation sends customerCreate as the given user with an Idempotency-Key and returns the raw response body
+func postMutation(t *testing.T, h http.Handler, user, key string, input map[string]interface{}) string {
+	t.Helper()
+	return postMutationWithClaims(t, h, jwt.MapClaims{"sub": user}, key, input)
+}
+
+// postMutationWithClaims sends customerCreate with the given token claims and an Idempotency-Key
+// and returns the raw response body
+func postMutationWithClaims(t *testing.T, h http.Handler, claims jwt.MapClaims, key string, input map[string]interface{}) string {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     customerCreateMutation,
+		"variables": map[string]interface{}{"input": input},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(server.IdempotencyKeyHeader, key)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, claims))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+// errorCode returns the code of the single error of a response body
+func errorCode(t *testing.T, body string) string {
+	t.Helper()
+	var resp struct {
+		Errors []struct {
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	require.Len(t, resp.Errors, 1, body)
+	code, _ := resp.Errors[0].Extensions["code"].(string)
+	return code
+}
+
+// TestIdempotency_ReplayedCustomerCreate verifies a replayed customerCreate creates one customer
+// and receives the first response, even after the clock moved on
+func TestIdempotency_ReplayedCustomerCreate(t *testing.T) {
+	database := newMemoryDB()
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	resolvers.SetClock(clock)
+	t.Cleanup(func() { resolvers.SetClock(db.RealClock{}) })
+	h := idempotencyHandler(database, clock)
+	input := map[string]interface{}{"identifier": testutil.TestCustomerID1, "firstName": "Jane"}
+
+	first := postMutation(t, h, "alice", "key-1", input)
+	assert.JSONEq(t, `{"data":{"customerCreate":{"identifier":"`+testutil.TestCustomerID1+`","firstName":"Jane","createDate":"2026-01-01T12:00:00Z"}}}`, first)
+
+	clock.Advance(time.Minute)
+	second := postMutation(t, h, "alice", "key-1", input)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, database.collections["customers"].count())
+}
+
+// TestIdempotency_KeyReusedForDifferentOperation verifies reusing a key with other variables
+// fails with CONFLICT without running the mutation
+func TestIdempotency_KeyReusedForDifferentOperation(t *testing.T) {
+	database := newMemoryDB()
+	h := idempotencyHandler(database, testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)))
+
+	postMutation(t, h, "alice", "key-1", map[string]interface{}{"identifier": testutil.TestCustomerID1})
+	body := postMutation(t, h, "alice", "key-1", map[string]interface{}{"identifier": testutil.TestCustomerID2})
+	assert.Equal(t, resolvers.ErrCodeConflict, errorCode(t, body))
+	assert.Equal(t, 1, database.collections["customers"].count())
+}
+
+// TestIdempotency_KeysScopedPerPrincipal verifies the same key sent by another user runs the mutation
+func TestIdempotency_KeysScopedPerPrincipal(t *testing.T) {
+	database := newMemoryDB()
+	h := idempotencyHandler(database, testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)))
+
+	postMutation(t, h, "alice", "key-1", map[string]interface{}{"identifier": testutil.TestCustomerID1})
+	body := postMutation(t, h, "bob", "key-1", map[string]interface{}{"identifier": testutil.TestCustomerID2})
+	assert.Contains(t, body, testutil.TestCustomerID2)
+	assert.Equal(t, 2, database.collections["customers"].count())
+	assert.Equal(t, 2, database.collections[server.IdempotencyCollection].count())
+}
+
+// TestIdempotency_KeysSurviveTokenRefresh verifies a retry under a refreshed token of the same
+// subject with other roles and teams receives the first response instead of creating a duplicate
+func TestIdempotency_KeysSurviveTokenRefresh(t *testing.T) {
+	database := newMemoryDB()
+	h := idempotencyHandler(database, testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)))
+	input := map[string]interface{}{"identifier": testutil.TestCustomerID1, "firstName": "Jane"}
+
+	first := postMutationWithClaims(t, h, jwt.MapClaims{"sub": "alice", "roles": []interface{}{"USER"}}, "key-1", input)
+	refreshed := postMutationWithClaims(t, h, jwt.MapClaims{
+		"sub":   "alice",
+		"roles": []interface{}{"USER", "ADMIN"},
+		"teams": []interface{}{"team-a"},
+	}, "key-1", input)
+	assert.Equal(t, first, refreshed)
+	assert.Equal(t, 1, database.collections["customers"].count())
+}
+
+// TestIdempotency_FailedMutationReleasesKey verifies a mutation that returned an error is not
+// stored, so its retry runs again, and that expired keys run the mutation again
+func TestIdempotency_FailedMutationReleasesKey(t *testing.T) {
+	database := newMemoryDB()
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	h := idempotencyHandler(database, clock)
+
+	body := postMutation(t, h, "alice", "key-1", map[string]interface{}{"identifier": "not-a-uuid"})
+	assert.Equal(t, resolvers.ErrCodeInvalidInput, errorCode(t, body))
+	assert.Equal(t, 0, database.collections[server.IdempotencyCollection].count())
+
+	// Not yet removed by the TTL monitor, but expired
+	input := map[string]interface{}{"identifier": testutil.TestCustomerID1}
+	postMutation(t, h, "bob", "key-2", input)
+	clock.Advance(time.Hour)
+	body = postMutation(t, h, "bob", "key-2", input)
+	assert.Equal(t, resolvers.ErrCodeInvalidInput, errorCode(t, body), "the expired key runs customerCreate again")
+}
@@ -0,0 +1,148 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/server"
+)
+
+// listenerConfig returns a configuration listening on ephemeral ports
+func listenerConfig(adminAddress string) *config.Config {
+	return &config.Config{
+		ListenAddress:      "127.0.0.1:0",
+		AdminListenAddress: adminAddress,
+		LogFormat:          "json",
+		JWTSecret:          "test-secret-key-at-least-32-characters-long",
+		CORSOrigins:        []string{"*"},
+	}
+}
+
+// startServer binds and serves srv, returning the channel receiving the result of Serve
+func startServer(t *testing.T, srv *server.Server) <-chan error {
+	t.Helper()
+	require.NoError(t, srv.Listen())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve() }()
+	t.Cleanup(func() { _ = srv.Stop(context.Background()) })
+	return served
+}
+
+// statusOf returns the status code of a request to a listener
+func statusOf(t *testing.T, method, address, path string) int {
+	t.Helper()
+	req, err := http.NewRequest(method, "http://"+address+path, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+// TestListeners_AdminRoutesOnAdminListener verifies /health, /ready and /metrics move to the admin
+// listener while the API routes stay on the main listener
+func TestListeners_AdminRoutesOnAdminListener(t *testing.T) {
+	srv := server.New(listenerConfig("127.0.0.1:0"))
+	startServer(t, srv)
+	require.NotEmpty(t, srv.AdminAddr())
+	require.NotEqual(t, srv.Addr(), srv.AdminAddr())
+
+	for _, path := range []string{"/health", "/ready", "/metrics"} {
+		assert.NotEqual(t, http.StatusNotFound, statusOf(t, http.MethodGet, srv.AdminAddr(), path), path)
+		assert.Equal(t, http.StatusNotFound, statusOf(t, http.MethodGet, srv.Addr(), path), path)
+	}
+	assert.Equal(t, http.StatusUnauthorized, statusOf(t, http.MethodPost, srv.Addr(), "/graphql"))
+	assert.Equal(t, http.StatusUnauthorized, statusOf(t, http.MethodPost, srv.Addr(), "/export/customer"))
+	assert.Equal(t, http.StatusNotFound, statusOf(t, http.MethodPost, srv.AdminAddr(), "/graphql"))
+}
+
+// TestListeners_WithoutAdminListener verifies all routes are served on the main listener, at
+// their configured paths
+func TestListeners_WithoutAdminListener(t *testing.T) {
+	cfg := listenerConfig("")
+	cfg.GraphQLPath = "/api/graphql"
+	cfg.HealthPath = "/healthz"
+	srv := server.New(cfg)
+	startServer(t, srv)
+	assert.Empty(t, srv.AdminAddr())
+	assert.Nil(t, srv.AdminHandler())
+
+	assert.Equal(t, http.StatusOK, statusOf(t, http.MethodGet, srv.Addr(), "/healthz"))
+	assert.Equal(t, http.StatusNotFound, statusOf(t, http.MethodGet, srv.Addr(), "/health"))
+	assert.NotEqual(t, http.StatusNotFound, statusOf(t, http.MethodGet, srv.Addr(), "/metrics"))
+	assert.Equal(t, http.StatusUnauthorized, statusOf(t, http.MethodPost, srv.Addr(), "/api/graphql"))
+	assert.Equal(t, http.StatusNotFound, statusOf(t, http.MethodPost, srv.Addr(), "/graphql"))
+}
+
+// blockingHealthClient is a health.DBHealthChecker whose health checks wait until released
+type blockingHealthClient struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingHealthClient) HealthStatus(ctx context.Context) (*db.HealthStatus, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return &db.HealthStatus{Status: "connected"}, nil
+}
+
+func (b *blockingHealthClient) IsConnected() bool {
+	return true
+}
+
+// TestListeners_StopDrainsBothListeners verifies Stop closes both listeners at once and returns,
+// like Serve, only after the request in flight on the admin listener completed
+func TestListeners_StopDrainsBothListeners(t *testing.T) {
+	client := &blockingHealthClient{started: make(chan struct{}, 1), release: make(chan struct{})}
+	srv := server.New(listenerConfig("127.0.0.1:0"), server.WithDatabaseClient(client))
+	served := startServer(t, srv)
+
+	health := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + srv.AdminAddr() + "/health")
+		if err != nil {
+			health <- 0
+			return
+		}
+		resp.Body.Close()
+		health <- resp.StatusCode
+	}()
+	<-client.started
+
+	// A deadline passing while the request is in flight fails the shutdown
+	expired, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, srv.Stop(expired), context.DeadlineExceeded)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- srv.Stop(context.Background()) }()
+
+	// Neither listener accepts connections while the request drains
+	for _, address := range []string{srv.Addr(), srv.AdminAddr()} {
+		_, err := net.DialTimeout("tcp", address, time.Second)
+		assert.Error(t, err, "%s still accepts connections", address)
+	}
+	select {
+	case err := <-stopped:
+		t.Fatalf("Stop returned before the request completed: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(client.release)
+	assert.Equal(t, http.StatusOK, <-health)
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the request completed")
+	}
+	assert.NoError(t, <-served)
+}
@@ -0,0 +1,46 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// readOnlyHandler serves the generated schema backed by database over GET and POST, refusing
+// mutations in read-only mode
+func readOnlyHandler(database *memoryDB) http.Handler {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolvers.NewResolver(database)}))
+	srv.SetErrorPresenter(resolvers.ErrorPresenter)
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AroundFields(resolvers.RejectReadOnlyMutations)
+	return srv
+}
+
+// TestReadOnly_RejectsMutationsKeepsReads verifies customerCreate answers READ_ONLY without
+// writing once the mode is on, while customerGet keeps reading
+func TestReadOnly_RejectsMutationsKeepsReads(t *testing.T) {
+	database := newMemoryDB()
+	h := readOnlyHandler(database)
+	postMutation(t, h, "alice", "key-1", map[string]interface{}{"identifier": testutil.TestCustomerID1, "firstName": "Jane"})
+	require.Equal(t, 1, database.collections["customers"].count())
+
+	resolvers.SetReadOnly(true)
+	t.Cleanup(func() { resolvers.SetReadOnly(false) })
+
+	body := postMutation(t, h, "alice", "key-2", map[string]interface{}{"identifier": testutil.TestCustomerID2, "firstName": "John"})
+	assert.Equal(t, resolvers.ErrCodeReadOnly, errorCode(t, body))
+	assert.Contains(t, body, "customerCreate is refused")
+	assert.Equal(t, 1, database.collections["customers"].count())
+
+	read := getQuery(h, customerGetQuery, "")
+	assert.JSONEq(t, `{"data":{"customerGet":{"firstName":"Jane"}}}`, read.Body.String())
+}
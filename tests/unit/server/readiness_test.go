@@ -0,0 +1,330 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/resolvers"
+	"github.com/yourusername/air-go/internal/health"
+	"github.com/yourusername/air-go/internal/server"
+	"github.com/yourusername/air-go/tests/testutil"
+)
+
+// fakeHealthClient is a health.DBHealthChecker whose status is set by the test
+type fakeHealthClient struct {
+	mu     sync.Mutex
+	status *db.HealthStatus
+	err    error
+}
+
+func (f *fakeHealthClient) set(status string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = &db.HealthStatus{Status: status, Message: "status " + status}
+	f.err = err
+}
+
+func (f *fakeHealthClient) HealthStatus(ctx context.Context) (*db.HealthStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status, f.err
+}
+
+func (f *fakeHealthClient) IsConnected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status != nil && f.status.Status == "connected"
+}
+
+// logLines decodes the JSON log entries written so far
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for decoder.More() {
+		var entry map[string]interface{}
+		require.NoError(t, decoder.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestReadiness_Transitions drives the state machine through every transition
+func TestReadiness_Transitions(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := testutil.NewFakeClock(start)
+	client := &fakeHealthClient{}
+	client.set("disconnected", nil)
+
+	var buf bytes.Buffer
+	readiness := server.NewReadiness(client, clock, zerolog.New(&buf))
+
+	// Schema not loaded and database not connected
+	report := readiness.Evaluate(ctx)
+	assert.Equal(t, server.ReadinessNotReady, report.State)
+	assert.Equal(t, 0, report.StateValue)
+	assert.Equal(t, []string{"schema not loaded", "database not connected: status disconnected"}, report.Reasons)
+	assert.Empty(t, logLines(t, &buf), "staying NOT_READY is not a transition")
+
+	// Schema loaded, still waiting for the database
+	readiness.MarkSchemaLoaded()
+	report = readiness.Evaluate(ctx)
+	assert.Equal(t, server.ReadinessNotReady, report.State)
+	assert.Equal(t, []string{"database not connected: status disconnected"}, report.Reasons)
+
+	// Database connected: READY
+	clock.Advance(5 * time.Second)
+	client.set("connected", nil)
+	report = readiness.Evaluate(ctx)
+	assert.Equal(t, server.ReadinessReady, report.State)
+	assert.Equal(t, 1, report.StateValue)
+	assert.Empty(t, report.Reasons)
+	assert.Equal(t, start.Add(5*time.Second).Format(time.RFC3339), report.Since)
+
+	// Database lost after having been ready: DEGRADED
+	clock.Advance(time.Minute)
+	client.set("error", nil)
+	report = readiness.Evaluate(ctx)
+	assert.Equal(t, server.ReadinessDegraded, report.State)
+	assert.Equal(t, 2, report.StateValue)
+	assert.Equal(t, []string{"database not connected: status error"}, report.Reasons)
+
+	// A failing health check keeps it DEGRADED
+	client.set("connected", errors.New("ping timed out"))
+	report = readiness.Evaluate(ctx)
+	assert.Equal(t, server.ReadinessDegraded, report.State)
+	assert.Equal(t, []string{"database health check failed: ping timed out"}, report.Reasons)
+
+	// Reconnected: READY again
+	clock.Advance(10 * time.Second)
+	client.set("connected", nil)
+	assert.Equal(t, server.ReadinessReady, readiness.Evaluate(ctx).State)
+
+	// Draining: SHUTTING_DOWN is final
+	clock.Advance(time.Second)
+	readiness.BeginShutdown()
+	report = readiness.Evaluate(ctx)
+	assert.Equal(t, server.ReadinessShuttingDown, report.State)
+	assert.Equal(t, 3, report.StateValue)
+	assert.Equal(t, []string{"server is shutting down"}, report.Reasons)
+
+	entries := logLines(t, &buf)
+	wantTransitions := [][2]string{
+		{"NOT_READY", "READY"},
+		{"READY", "DEGRADED"},
+		{"DEGRADED", "READY"},
+		{"READY", "SHUTTING_DOWN"},
+	}
+	require.Len(t, entries, len(wantTransitions), "changed reasons alone are not logged")
+	for i, entry := range entries {
+		assert.Equal(t, "Readiness state changed", entry["message"])
+		assert.Equal(t, wantTransitions[i][0], entry["from"])
+		assert.Equal(t, wantTransitions[i][1], entry["to"])
+	}
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, "warn", entries[1]["level"])
+	assert.Equal(t, float64(time.Minute.Milliseconds()), entries[1]["previous_state_duration"])
+	assert.Equal(t, "info", entries[3]["level"])
+}
+
+// TestReadiness_Run verifies the background evaluation moves the state to READY and DEGRADED
+// and back without any /ready or /metrics request
+func TestReadiness_Run(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &fakeHealthClient{}
+	client.set("connected", nil)
+	readiness := server.NewReadiness(client, clock, zerolog.Nop())
+	readiness.MarkSchemaLoaded()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		readiness.Run(ctx, 5*time.Second)
+	}()
+
+	// tick advances past the next evaluation once Run is waiting for it
+	tick := func() {
+		t.Helper()
+		require.True(t, clock.BlockUntilWaiters(1, time.Second))
+		clock.Advance(5 * time.Second)
+	}
+
+	require.True(t, clock.BlockUntilWaiters(1, time.Second))
+	assert.Equal(t, server.ReadinessReady, readiness.State(), "Run evaluates once on start")
+
+	client.set("disconnected", nil)
+	tick()
+	require.Eventually(t, func() bool { return readiness.State() == server.ReadinessDegraded }, time.Second, time.Millisecond)
+
+	client.set("connected", nil)
+	tick()
+	require.Eventually(t, func() bool { return readiness.State() == server.ReadinessReady }, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after its context was canceled")
+	}
+}
+
+// TestReadiness_NeverReadyIsNotDegraded verifies losing the database before the first READY stays NOT_READY
+func TestReadiness_NeverReadyIsNotDegraded(t *testing.T) {
+	client := &fakeHealthClient{}
+	client.set("error", nil)
+	readiness := server.NewReadiness(client, testutil.NewFakeClock(time.Now()), zerolog.Nop())
+	readiness.MarkSchemaLoaded()
+
+	assert.Equal(t, server.ReadinessNotReady, readiness.Evaluate(context.Background()).State)
+}
+
+// TestReadiness_ReaderDown verifies a disconnected read connection degrades a ready server
+func TestReadiness_ReaderDown(t *testing.T) {
+	client := &fakeHealthClient{}
+	client.set("connected", nil)
+	readiness := server.NewReadiness(client, testutil.NewFakeClock(time.Now()), zerolog.Nop())
+	readiness.MarkSchemaLoaded()
+	require.Equal(t, server.ReadinessReady, readiness.Evaluate(context.Background()).State)
+
+	client.mu.Lock()
+	client.status.Reader = &db.HealthStatus{Status: "disconnected"}
+	client.mu.Unlock()
+
+	report := readiness.Evaluate(context.Background())
+	assert.Equal(t, server.ReadinessDegraded, report.State)
+	assert.Equal(t, []string{"read connection not connected"}, report.Reasons)
+}
+
+// TestReadiness_Handler verifies the /ready status code for each state
+func TestReadiness_Handler(t *testing.T) {
+	client := &fakeHealthClient{}
+	client.set("disconnected", nil)
+	readiness := server.NewReadiness(client, testutil.NewFakeClock(time.Now()), zerolog.Nop())
+	readiness.MarkSchemaLoaded()
+
+	get := func() (int, server.ReadinessReport) {
+		rec := httptest.NewRecorder()
+		readiness.Handler()(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var report server.ReadinessReport
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+		return rec.Code, report
+	}
+
+	code, report := get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, server.ReadinessNotReady, report.State)
+
+	client.set("connected", nil)
+	code, report = get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, server.ReadinessReady, report.State)
+
+	client.set("disconnected", nil)
+	code, report = get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, server.ReadinessDegraded, report.State)
+
+	readiness.BeginShutdown()
+	code, report = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, server.ReadinessShuttingDown, report.State)
+}
+
+// TestReadiness_IndexesRequired verifies a server requiring indexes is not ready until they are ensured
+func TestReadiness_IndexesRequired(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeHealthClient{}
+	client.set("connected", nil)
+	readiness := server.NewReadiness(client, testutil.NewFakeClock(time.Now()), zerolog.Nop())
+	readiness.MarkSchemaLoaded()
+	readiness.RequireIndexes()
+
+	report := readiness.Evaluate(ctx)
+	assert.Equal(t, server.ReadinessNotReady, report.State)
+	assert.Equal(t, []string{"indexes not ensured"}, report.Reasons)
+
+	readiness.MarkIndexesEnsured(errors.New("not primary"))
+	report = readiness.Evaluate(ctx)
+	assert.Equal(t, server.ReadinessNotReady, report.State)
+	assert.Equal(t, []string{"indexes not ensured: not primary"}, report.Reasons)
+
+	readiness.MarkIndexesEnsured(nil)
+	assert.Equal(t, server.ReadinessReady, readiness.Evaluate(ctx).State)
+}
+
+// countingHealthClient is a fakeHealthClient reporting connections checked out of its pool
+type countingHealthClient struct {
+	fakeHealthClient
+	inUse int64
+}
+
+func (c *countingHealthClient) ConnectionsInUse() int64 { return c.inUse }
+
+// TestReadiness_MetricsResources verifies the goroutines, pooled connections in use and open
+// cursors are exposed as gauges, the connections only for a client reporting them
+func TestReadiness_MetricsResources(t *testing.T) {
+	scrape := func(client health.DBHealthChecker) string {
+		readiness := server.NewReadiness(client, testutil.NewFakeClock(time.Now()), zerolog.Nop())
+		rec := httptest.NewRecorder()
+		readiness.MetricsHandler()(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return rec.Body.String()
+	}
+
+	client := &countingHealthClient{inUse: 3}
+	client.set("connected", nil)
+	body := scrape(client)
+	assert.Contains(t, body, "# TYPE air_goroutines gauge\n")
+	assert.Contains(t, body, "\nair_mongodb_connections_in_use 3\n")
+	assert.Contains(t, body, fmt.Sprintf("\nair_mongodb_open_cursors %d\n", db.OpenCursors()))
+
+	plain := &fakeHealthClient{}
+	plain.set("connected", nil)
+	assert.NotContains(t, scrape(plain), "air_mongodb_connections_in_use")
+}
+
+// TestReadiness_MetricsHandler verifies the state gauge follows the evaluated state and the
+// capped page size counter is exposed
+func TestReadiness_MetricsHandler(t *testing.T) {
+	client := &fakeHealthClient{}
+	client.set("connected", nil)
+	readiness := server.NewReadiness(client, testutil.NewFakeClock(time.Now()), zerolog.Nop())
+	readiness.MarkSchemaLoaded()
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		readiness.MetricsHandler()(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "# TYPE air_readiness_state gauge\n")
+		return rec.Body.String()
+	}
+
+	assert.Contains(t, scrape(), "\nair_readiness_state 1\n")
+
+	client.set("disconnected", nil)
+	assert.Contains(t, scrape(), "\nair_readiness_state 2\n")
+	assert.Equal(t, server.ReadinessDegraded, readiness.State())
+	assert.Contains(t, scrape(), "# TYPE air_page_size_capped_total counter\n")
+
+	body := scrape()
+	search := resolvers.SearchConcurrencyStats()
+	assert.Contains(t, body, "# TYPE air_search_rows_in_use gauge\n")
+	assert.Contains(t, body, fmt.Sprintf("\nair_search_rows_max %d\n", search.Max))
+	assert.Contains(t, body, "# TYPE air_search_waiting gauge\n")
+	assert.Contains(t, body, "# TYPE air_search_rejected_total counter\n")
+}
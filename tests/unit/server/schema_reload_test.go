@@ -0,0 +1,237 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/air-go/internal/graphql"
+	"github.com/yourusername/air-go/internal/health"
+	"github.com/yourusername/air-go/internal/server"
+)
+
+// Schema fixtures the generated schema implements; the second adds the serviceInfo query
+const (
+	aliveSchema       = "type Query {\n  alive: Boolean!\n}\n"
+	serviceInfoSchema = `type Query {
+  alive: Boolean!
+  serviceInfo: ServiceInfo!
+}
+type ServiceInfo {
+  schema: SchemaInfo
+  build: BuildInfo!
+}
+type SchemaInfo {
+  path: String!
+  typeCount: Int!
+  checksum: String!
+  loadedAt: String!
+}
+type BuildInfo {
+  gitSha: String!
+  buildTime: String!
+}
+`
+	// unimplementedSchema declares a field this build has no resolver for
+	unimplementedSchema = "type Query {\n  alive: Boolean!\n  brandNew: String\n}\n"
+)
+
+// reloadServer returns a server with schema reloads enabled, reading its schema from a fixture
+// file, which starts as aliveSchema and is served after a first reload
+func reloadServer(t *testing.T) (*server.Server, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.graphqls")
+	writeSchema(t, path, aliveSchema)
+
+	cfg := listenerConfig("")
+	cfg.SchemaPath = path
+	cfg.SchemaReloadEnabled = true
+	cfg.SchemaReloadPath = "/admin/schema/reload"
+	srv := server.New(cfg)
+	require.Equal(t, server.SchemaReloaded, srv.ReloadSchema().Status)
+	return srv, path
+}
+
+func writeSchema(t *testing.T, path, schema string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(schema), 0o644))
+}
+
+// loadedFixture loads a schema fixture the way the server does
+func loadedFixture(t *testing.T, schema string) *graphql.Schema {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.graphqls")
+	writeSchema(t, path, schema)
+	loaded, err := graphql.LoadSchema(path)
+	require.NoError(t, err)
+	return loaded
+}
+
+// postReload triggers a reload as a user with roles and returns the status and result
+func postReload(t *testing.T, srv *server.Server, roles ...string) (int, health.SchemaReload) {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "operator", "roles": roles}).
+		SignedString([]byte(listenerConfig("").JWTSecret))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/schema/reload", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var result health.SchemaReload
+	if rec.Code == http.StatusOK || rec.Code == http.StatusUnprocessableEntity {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	}
+	return rec.Code, result
+}
+
+// healthService returns the service info of /health
+func healthService(t *testing.T, srv *server.Server) *health.ServiceInfo {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var response health.Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.NotNil(t, response.Service)
+	return response.Service
+}
+
+// TestSchemaReload_SwapsSchema verifies a reload serves the changed schema file and reports the
+// previous and new checksums in its response and in /health
+func TestSchemaReload_SwapsSchema(t *testing.T) {
+	srv, path := reloadServer(t)
+	alive := loadedFixture(t, aliveSchema)
+	withServiceInfo := loadedFixture(t, serviceInfoSchema)
+
+	status, result := postReload(t, srv, "ADMIN")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, server.SchemaUnchanged, result.Status)
+
+	writeSchema(t, path, serviceInfoSchema)
+	status, result = postReload(t, srv, "ADMIN")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, server.SchemaReloaded, result.Status)
+	assert.Equal(t, alive.Checksum, result.PreviousChecksum)
+	assert.Equal(t, withServiceInfo.Checksum, result.Checksum)
+	assert.Empty(t, result.Errors)
+
+	info := healthService(t, srv)
+	assert.Equal(t, withServiceInfo.Checksum, info.Schema.Checksum)
+	assert.Equal(t, len(withServiceInfo.Schema.Types), info.Schema.TypeCount)
+	assert.Equal(t, &result, info.SchemaReload)
+}
+
+// TestSchemaReload_RejectsUnservableSchema verifies schemas that do not load, or declare what
+// this build does not implement, are rejected with their errors while the previous schema stays
+func TestSchemaReload_RejectsUnservableSchema(t *testing.T) {
+	srv, path := reloadServer(t)
+	alive := loadedFixture(t, aliveSchema)
+
+	writeSchema(t, path, unimplementedSchema)
+	status, result := postReload(t, srv, "ADMIN")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, server.SchemaRejected, result.Status)
+	assert.Equal(t, alive.Checksum, result.PreviousChecksum)
+	assert.Equal(t, loadedFixture(t, unimplementedSchema).Checksum, result.Checksum)
+	assert.Equal(t, []string{"Query.brandNew is not implemented by this build"}, result.Errors)
+
+	info := healthService(t, srv)
+	assert.Equal(t, alive.Checksum, info.Schema.Checksum)
+	assert.Equal(t, server.SchemaRejected, info.SchemaReload.Status)
+
+	writeSchema(t, path, "type Query {\n  alive: Boolean!\n")
+	status, result = postReload(t, srv, "ADMIN")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "schema validation failed")
+	assert.Empty(t, result.Checksum)
+	assert.Equal(t, alive.Checksum, healthService(t, srv).Schema.Checksum)
+}
+
+// TestSchemaReload_RevalidatesConfiguredFeatures verifies a schema lacking a field the
+// configuration disables or marks heavy is rejected, as it would be at startup
+func TestSchemaReload_RevalidatesConfiguredFeatures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.graphqls")
+	writeSchema(t, path, serviceInfoSchema)
+	cfg := listenerConfig("")
+	cfg.SchemaPath = path
+	cfg.DisabledOperations = []string{"serviceInfo"}
+	srv := server.New(cfg)
+	require.Equal(t, server.SchemaReloaded, srv.ReloadSchema().Status)
+
+	writeSchema(t, path, aliveSchema)
+	result := srv.ReloadSchema()
+	assert.Equal(t, server.SchemaRejected, result.Status)
+	assert.Equal(t, []string{`DISABLED_ENTITIES or DISABLED_OPERATIONS: "serviceInfo" is not a Query or Mutation field`}, result.Errors)
+	assert.Equal(t, loadedFixture(t, serviceInfoSchema).Checksum, healthService(t, srv).Schema.Checksum)
+
+	cfg.DisabledOperations = nil
+	cfg.SearchHeavyFields = []string{"customer.payment"}
+	result = srv.ReloadSchema()
+	assert.Equal(t, server.SchemaRejected, result.Status)
+	assert.Equal(t, []string{`SEARCH_HEAVY_FIELDS: customerSearch results have no field "payment"`}, result.Errors)
+}
+
+// TestSchemaReload_RequiresAdmin verifies only authenticated administrators may reload
+func TestSchemaReload_RequiresAdmin(t *testing.T) {
+	srv, _ := reloadServer(t)
+
+	status, _ := postReload(t, srv, "VIEWER")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/schema/reload", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// TestSchemaReload_Atomic verifies readers never see a schema paired with another schema's
+// metadata while reloads alternate between two fixtures
+func TestSchemaReload_Atomic(t *testing.T) {
+	srv, path := reloadServer(t)
+	typeCounts := map[string]int{}
+	for _, fixture := range []string{aliveSchema, serviceInfoSchema} {
+		loaded := loadedFixture(t, fixture)
+		typeCounts[loaded.Checksum] = len(loaded.Schema.Types)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if err := os.WriteFile(path, []byte([]string{serviceInfoSchema, aliveSchema}[i%2]), 0o644); err != nil {
+				t.Error(err)
+				return
+			}
+			if result := srv.ReloadSchema(); result.Status != server.SchemaReloaded {
+				t.Errorf("reload %d: %s %v", i, result.Status, result.Errors)
+				return
+			}
+		}
+	}()
+
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+		info := healthService(t, srv)
+		count, ok := typeCounts[info.Schema.Checksum]
+		require.True(t, ok, "unexpected checksum %s", info.Schema.Checksum)
+		require.Equal(t, count, info.Schema.TypeCount)
+		require.Equal(t, info.Schema.Checksum, info.SchemaReload.Checksum)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,247 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/yourusername/air-go/internal/config"
+	"github.com/yourusername/air-go/internal/db"
+	"github.com/yourusername/air-go/internal/graphql/generated"
+	"github.com/yourusername/air-go/internal/server"
+)
+
+// subscriptionSchema returns the generated schema with an empty Subscription type, so the
+// server registers the websocket transport
+func subscriptionSchema() *ast.Schema {
+	schema := *generated.NewExecutableSchema(generated.Config{}).Schema()
+	schema.Subscription = &ast.Definition{Kind: ast.Object, Name: "Subscription"}
+	return &schema
+}
+
+// websocketServer starts a server serving a schema with a Subscription type. Its database
+// client is never connected; the operations the tests run need no database
+func websocketServer(t *testing.T, configure func(cfg *config.Config)) (*server.Server, <-chan error) {
+	t.Helper()
+	cfg := listenerConfig("")
+	cfg.WebsocketKeepAliveInterval = 10 * time.Second
+	configure(cfg)
+	srv := server.New(cfg, server.WithDatabaseClient(unconnectedClient(t)), server.WithSchema(subscriptionSchema()))
+	return srv, startServer(t, srv)
+}
+
+// unconnectedClient creates a database client that is never connected
+func unconnectedClient(t *testing.T) *db.Client {
+	t.Helper()
+	client, err := db.NewClient(&db.DBConfig{
+		URI:              "mongodb://localhost:27017",
+		Database:         "test",
+		ConnectTimeout:   30 * time.Second,
+		OperationTimeout: 10 * time.Second,
+		MinPoolSize:      5,
+		MaxPoolSize:      10,
+		MaxConnIdleTime:  5 * time.Minute,
+		MaxRetryAttempts: 3,
+		RetryBaseDelay:   time.Second,
+		RetryMaxDelay:    10 * time.Second,
+	}, zerolog.Nop())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+// bearerToken signs a token of a user with the test secret
+func bearerToken(t *testing.T, user string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": user}).
+		SignedString([]byte(listenerConfig("").JWTSecret))
+	require.NoError(t, err)
+	return "Bearer " + token
+}
+
+// dialGraphQL opens a graphql-transport-ws connection and sends connection_init with authorization
+func dialGraphQL(t *testing.T, srv *server.Server, authorization string) *websocket.Conn {
+	t.Helper()
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	conn, resp, err := dialer.Dial("ws://"+srv.Addr()+"/graphql", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+
+	payload := map[string]interface{}{}
+	if authorization != "" {
+		payload["Authorization"] = authorization
+	}
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "connection_init", "payload": payload}))
+	return conn
+}
+
+// nextMessage reads the next message of a connection
+func nextMessage(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var msg map[string]interface{}
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+// closeCode waits for the server to close a connection and returns the code of its close frame
+func closeCode(t *testing.T, conn *websocket.Conn) int {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err == nil {
+			continue
+		}
+		var closeErr *websocket.CloseError
+		require.True(t, errors.As(err, &closeErr), "expected a close frame, got %v", err)
+		return closeErr.Code
+	}
+}
+
+// metrics returns the /metrics response of a server
+func metrics(t *testing.T, srv *server.Server) string {
+	t.Helper()
+	resp, err := http.Get("http://" + srv.Addr() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+// TestWebsocket_InitPayloadAuthentication verifies connections authenticate with the token of
+// their connection_init payload and are closed with 4401 without a valid one
+func TestWebsocket_InitPayloadAuthentication(t *testing.T) {
+	srv, _ := websocketServer(t, func(cfg *config.Config) {})
+
+	conn := dialGraphQL(t, srv, bearerToken(t, "alice"))
+	assert.Equal(t, "connection_ack", nextMessage(t, conn)["type"])
+
+	for _, authorization := range []string{"", "Bearer not-a-token", "Basic YWxpY2U6c2VjcmV0"} {
+		conn := dialGraphQL(t, srv, authorization)
+		assert.Equal(t, server.WebsocketCloseUnauthorized, closeCode(t, conn), authorization)
+	}
+	assert.Contains(t, metrics(t, srv), `air_websocket_closed_total{reason="unauthorized"} 3`)
+}
+
+// TestWebsocket_GETWithoutUpgrade verifies GraphQL requests cannot bypass authentication over GET
+func TestWebsocket_GETWithoutUpgrade(t *testing.T) {
+	srv, _ := websocketServer(t, func(cfg *config.Config) {})
+	assert.Equal(t, http.StatusUnauthorized, statusOf(t, http.MethodGet, srv.Addr(), "/graphql?query={serverLimits{maxSortEntries}}"))
+}
+
+// TestWebsocket_NotServedWithoutSubscriptions verifies a schema without a Subscription type
+// opens no websocket endpoint: upgrades are plain GET requests needing the Authorization header
+func TestWebsocket_NotServedWithoutSubscriptions(t *testing.T) {
+	srv := server.New(listenerConfig(""), server.WithDatabaseClient(unconnectedClient(t)))
+	startServer(t, srv)
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	_, resp, err := dialer.Dial("ws://"+srv.Addr()+"/graphql", nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	header := http.Header{"Authorization": []string{bearerToken(t, "alice")}}
+	_, resp, err = dialer.Dial("ws://"+srv.Addr()+"/graphql", header)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	resp.Body.Close()
+	assert.NotEqual(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	assert.NotContains(t, metrics(t, srv), "air_websocket_connections")
+}
+
+// TestWebsocket_IdleTimeout verifies a connection running operations stays open and is closed
+// with 4408 once it ran none for the idle timeout
+func TestWebsocket_IdleTimeout(t *testing.T) {
+	srv, _ := websocketServer(t, func(cfg *config.Config) {
+		cfg.WebsocketIdleTimeout = 300 * time.Millisecond
+	})
+
+	conn := dialGraphQL(t, srv, bearerToken(t, "alice"))
+	require.Equal(t, "connection_ack", nextMessage(t, conn)["type"])
+
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		time.Sleep(200 * time.Millisecond)
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{
+			"id":      "limits",
+			"type":    "subscribe",
+			"payload": map[string]interface{}{"query": "{ serverLimits { maxSortEntries } }"},
+		}))
+		assert.Equal(t, "next", nextMessage(t, conn)["type"])
+		assert.Equal(t, "complete", nextMessage(t, conn)["type"])
+	}
+	assert.Greater(t, time.Since(started), 500*time.Millisecond, "operations keep the connection open")
+
+	assert.Equal(t, server.WebsocketCloseIdle, closeCode(t, conn))
+	assert.Contains(t, metrics(t, srv), `air_websocket_closed_total{reason="idle"} 1`)
+}
+
+// TestWebsocket_PerPrincipalLimit verifies connections over a user's limit are closed with 4429,
+// while other users still connect and a released connection makes room again
+func TestWebsocket_PerPrincipalLimit(t *testing.T) {
+	srv, _ := websocketServer(t, func(cfg *config.Config) {
+		cfg.WebsocketMaxPerPrincipal = 1
+	})
+
+	first := dialGraphQL(t, srv, bearerToken(t, "alice"))
+	require.Equal(t, "connection_ack", nextMessage(t, first)["type"])
+
+	second := dialGraphQL(t, srv, bearerToken(t, "alice"))
+	assert.Equal(t, server.WebsocketCloseTooMany, closeCode(t, second))
+
+	other := dialGraphQL(t, srv, bearerToken(t, "bob"))
+	assert.Equal(t, "connection_ack", nextMessage(t, other)["type"])
+	assert.Contains(t, metrics(t, srv), "air_websocket_connections 2\n")
+
+	require.NoError(t, first.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+	require.Eventually(t, func() bool {
+		return strings.Contains(metrics(t, srv), "air_websocket_connections 1\n")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	third := dialGraphQL(t, srv, bearerToken(t, "alice"))
+	assert.Equal(t, "connection_ack", nextMessage(t, third)["type"])
+	assert.Contains(t, metrics(t, srv), `air_websocket_closed_total{reason="limit"} 1`)
+}
+
+// TestWebsocket_ServerLimit verifies the server-wide connection limit applies across users
+func TestWebsocket_ServerLimit(t *testing.T) {
+	srv, _ := websocketServer(t, func(cfg *config.Config) {
+		cfg.WebsocketMaxConnections = 1
+	})
+
+	first := dialGraphQL(t, srv, bearerToken(t, "alice"))
+	require.Equal(t, "connection_ack", nextMessage(t, first)["type"])
+	assert.Equal(t, server.WebsocketCloseTooMany, closeCode(t, dialGraphQL(t, srv, bearerToken(t, "bob"))))
+}
+
+// TestWebsocket_StopClosesConnections verifies draining closes open connections with 1001, which
+// the HTTP server's shutdown would otherwise wait for forever
+func TestWebsocket_StopClosesConnections(t *testing.T) {
+	srv, served := websocketServer(t, func(cfg *config.Config) {})
+
+	conn := dialGraphQL(t, srv, bearerToken(t, "alice"))
+	require.Equal(t, "connection_ack", nextMessage(t, conn)["type"])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, srv.Stop(ctx))
+	assert.Equal(t, server.WebsocketCloseShutdown, closeCode(t, conn))
+	assert.NoError(t, <-served)
+}